@@ -3,11 +3,16 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
 	"github.com/baking-bad/bcdhub/internal/contractparser/docstring"
 	"github.com/baking-bad/bcdhub/internal/contractparser/meta"
 	"github.com/baking-bad/bcdhub/internal/contractparser/newmiguel"
+	"github.com/baking-bad/bcdhub/internal/contractparser/storage/hash"
 	"github.com/baking-bad/bcdhub/internal/contractparser/stringer"
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
 	"github.com/baking-bad/bcdhub/internal/models/bigmapaction"
 	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
 	"github.com/gin-gonic/gin"
@@ -179,7 +184,7 @@ func (ctx *Context) GetBigMapKeys(c *gin.Context) {
 // @ID get-bigmap-keyhash
 // @Param network path string true "Network"
 // @Param ptr path integer true "Big map pointer"
-// @Param key_hash path string true "Key hash in big map" minlength(54) maxlength(54)
+// @Param key_hash path string true "Key hash in big map or key itself: readable literal or Micheline JSON"
 // @Param offset query integer false "Offset"
 // @Param size query integer false "Requested count" mininum(1)
 // @Accept json
@@ -199,6 +204,14 @@ func (ctx *Context) GetBigMapByKeyHash(c *gin.Context) {
 		return
 	}
 
+	if !isScriptExpression(req.KeyHash) {
+		keyHash, err := ctx.getBigMapKeyHash(req.Network, req.Ptr, req.KeyHash)
+		if ctx.handleError(c, err, http.StatusBadRequest) {
+			return
+		}
+		req.KeyHash = keyHash
+	}
+
 	bm, total, err := ctx.BigMapDiffs.GetByPtrAndKeyHash(req.Ptr, req.Network, req.KeyHash, pageReq.Size, pageReq.Offset)
 	if ctx.handleError(c, err, 0) {
 		return
@@ -244,6 +257,75 @@ func (ctx *Context) GetBigMapDiffCount(c *gin.Context) {
 	c.JSON(http.StatusOK, CountResponse{count})
 }
 
+func isScriptExpression(value string) bool {
+	return len(value) == 54 && strings.HasPrefix(value, "expr")
+}
+
+// getBigMapKeyHash - returns script expression hash of `key` packed with key type of big map `ptr`
+func (ctx *Context) getBigMapKeyHash(network string, ptr int64, key string) (string, error) {
+	bm, err := ctx.BigMapDiffs.Get(bigmapdiff.GetContext{
+		Ptr:     &ptr,
+		Network: network,
+		Size:    1,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(bm) == 0 {
+		return "", errors.Errorf("Unknown big map: %d", ptr)
+	}
+
+	code, err := ctx.getContractCodeJSON(network, bm[0].Address, bm[0].Protocol, 0)
+	if err != nil {
+		return "", err
+	}
+	bigMapType := code.Get(`#(prim=="storage").args.0`)
+	if bm[0].BinPath != "0" {
+		bigMapType = bigMapType.Get(newmiguel.GetGJSONPath(strings.TrimPrefix(bm[0].BinPath, "0/")))
+	}
+	if bigMapType.Get("prim").String() != consts.BIGMAP {
+		return "", errors.Errorf("Can't find type of big map %d", ptr)
+	}
+	keyType, err := typechecker.ParseType(bigMapType.Get("args.0"), "")
+	if err != nil {
+		return "", err
+	}
+	return hash.TypedKey(bigMapKey(key, keyType), keyType)
+}
+
+// bigMapKey - converts `key` from request to Micheline. Keys of complex types have to be passed as Micheline JSON.
+func bigMapKey(key string, typ *typechecker.Type) gjson.Result {
+	if (strings.HasPrefix(key, "{") || strings.HasPrefix(key, "[")) && gjson.Valid(key) {
+		return gjson.Parse(key)
+	}
+
+	var value interface{}
+	switch typ.Prim {
+	case consts.INT, consts.NAT, consts.MUTEZ:
+		value = map[string]interface{}{consts.KeyInt: key}
+	case consts.TIMESTAMP:
+		if _, err := strconv.ParseInt(key, 10, 64); err == nil {
+			value = map[string]interface{}{consts.KeyInt: key}
+		} else {
+			value = map[string]interface{}{consts.KeyString: key}
+		}
+	case consts.BYTES:
+		value = map[string]interface{}{consts.KeyBytes: key}
+	case consts.BOOL:
+		prim := "False"
+		if strings.EqualFold(key, "true") {
+			prim = "True"
+		}
+		value = map[string]interface{}{consts.KeyPrim: prim}
+	case consts.UNIT:
+		value = map[string]interface{}{consts.KeyPrim: "Unit"}
+	default:
+		value = map[string]interface{}{consts.KeyString: key}
+	}
+	data, _ := json.Marshal(value)
+	return gjson.ParseBytes(data)
+}
+
 func (ctx *Context) prepareBigMapKeys(data []bigmapdiff.Bucket) ([]BigMapResponseItem, error) {
 	if len(data) == 0 {
 		return []BigMapResponseItem{}, nil
@@ -307,6 +389,28 @@ func (ctx *Context) prepareBigMapItem(data []bigmapdiff.BigMapDiff, keyHash stri
 	return
 }
 
+// PrepareBigMapDiff - decodes key and value of big map diff
+func (ctx *Context) PrepareBigMapDiff(diff bigmapdiff.BigMapDiff) (BigMapItem, error) {
+	contractMetadata, err := meta.GetContractSchema(ctx.Schema, diff.Address)
+	if err != nil {
+		return BigMapItem{}, err
+	}
+
+	key, value, keyString, err := prepareItem(diff, contractMetadata)
+	if err != nil {
+		return BigMapItem{}, err
+	}
+
+	return BigMapItem{
+		Key:       key,
+		KeyHash:   diff.KeyHash,
+		KeyString: keyString,
+		Level:     diff.Level,
+		Value:     value,
+		Timestamp: diff.Timestamp,
+	}, nil
+}
+
 func prepareItem(item bigmapdiff.BigMapDiff, contractMetadata *meta.ContractSchema) (interface{}, interface{}, string, error) {
 	var protoSymLink string
 	protoSymLink, err := meta.GetProtoSymLink(item.Protocol)
@@ -6,6 +6,8 @@ import (
 	"github.com/baking-bad/bcdhub/internal/contractparser"
 	"github.com/baking-bad/bcdhub/internal/contractparser/formatter"
 	"github.com/baking-bad/bcdhub/internal/contractparser/macros"
+	"github.com/baking-bad/bcdhub/internal/contractparser/semdiff"
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
 	"github.com/baking-bad/bcdhub/internal/logger"
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
@@ -66,7 +68,7 @@ func (ctx *Context) GetContractCode(c *gin.Context) {
 
 // GetDiff godoc
 // @Summary Get diff between two contracts
-// @Description Get diff between two contracts
+// @Description Get text diff and structural diff (entrypoints, storage type and changed instruction blocks) between two contracts
 // @Tags contract
 // @ID get-diff
 // @Param body body CodeDiffRequest true "Request body"
@@ -83,6 +85,10 @@ func (ctx *Context) GetDiff(c *gin.Context) {
 	}
 
 	resp, err := ctx.getContractCodeDiff(req.Left, req.Right)
+	if errors.As(err, &typechecker.Errors{}) {
+		ctx.handleError(c, err, http.StatusBadRequest)
+		return
+	}
 	if ctx.handleError(c, err, 0) {
 		return
 	}
@@ -111,8 +117,23 @@ func (ctx *Context) getContractCodeJSON(network, address, protocol string, fallb
 func (ctx *Context) getContractCodeDiff(left, right CodeDiffLeg) (res CodeDiffResponse, err error) {
 	currentProtocols := make(map[string]string, 2)
 	sides := make([]gjson.Result, 2)
+	scripts := make([]gjson.Result, 2)
 
 	for i, leg := range []*CodeDiffLeg{&left, &right} {
+		if leg.Script != "" {
+			code := gjson.Parse(leg.Script)
+			if _, err := typechecker.Check(code); err != nil {
+				return res, err
+			}
+			scripts[i] = code
+			collapsed, err := macros.Collapse(code, macros.GetAllFamilies())
+			if err != nil {
+				return res, err
+			}
+			sides[i] = collapsed
+			continue
+		}
+
 		if leg.Protocol == "" {
 			protocol, ok := currentProtocols[leg.Network]
 			if !ok {
@@ -130,6 +151,7 @@ func (ctx *Context) getContractCodeDiff(left, right CodeDiffLeg) (res CodeDiffRe
 		if err != nil {
 			return res, err
 		}
+		scripts[i] = code
 		collapsed, err := macros.Collapse(code, macros.GetAllFamilies())
 		if err != nil {
 			return res, err
@@ -145,5 +167,12 @@ func (ctx *Context) getContractCodeDiff(left, right CodeDiffLeg) (res CodeDiffRe
 	res.Left = left
 	res.Right = right
 	res.Diff = diff
+
+	structure, err := semdiff.Diff(scripts[0], scripts[1])
+	if err != nil {
+		logger.Warning("structural diff of %s and %s: %s", left.Address, right.Address, err)
+		return res, nil
+	}
+	res.Structure = &structure
 	return res, nil
 }
@@ -3,6 +3,7 @@ package handlers
 import (
 	"github.com/baking-bad/bcdhub/cmd/api/oauth"
 	"github.com/baking-bad/bcdhub/internal/config"
+	"github.com/baking-bad/bcdhub/internal/safehttp"
 	"github.com/gin-gonic/gin"
 	"github.com/karlseguin/ccache"
 )
@@ -10,8 +11,9 @@ import (
 // Context -
 type Context struct {
 	*config.Context
-	OAUTH oauth.Config
-	Cache *ccache.Cache
+	OAUTH        oauth.Config
+	Cache        *ccache.Cache
+	WebhookGuard *safehttp.Guard
 }
 
 // NewContext -
@@ -34,15 +36,18 @@ func NewContext(cfg config.Config) (*Context, error) {
 		config.WithLoadErrorDescriptions("data/errors.json"),
 		config.WithConfigCopy(cfg),
 		config.WithContractsInterfaces(),
+		config.WithContractsInterfacesReload(cfg.Metrics.ContractInterfaces.Period(config.DefaultInterfacesPollPeriod)),
 		config.WithRabbit(cfg.RabbitMQ, cfg.API.ProjectName, cfg.API.MQ),
 		config.WithPinata(cfg.API.Pinata),
+		config.WithRestViews(cfg.API.Views),
 		config.WithTzipSchema("data/tzip-16-schema.json"),
 	)
 
 	return &Context{
-		Context: ctx,
-		OAUTH:   oauthCfg,
-		Cache:   ccache.New(ccache.Configure().MaxSize(10)),
+		Context:      ctx,
+		OAUTH:        oauthCfg,
+		Cache:        ccache.New(ccache.Configure().MaxSize(1000)),
+		WebhookGuard: safehttp.NewGuard(cfg.Metrics.Webhooks.AllowedHosts...),
 	}, nil
 }
 
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
 	"github.com/baking-bad/bcdhub/internal/helpers"
@@ -100,10 +101,11 @@ func (ctx *Context) appendDAppInfo(dapp *tzip.DApp, withDetails bool) (DApp, err
 			result.DexTokens = make([]TokenMetadata, 0)
 
 			for _, token := range dapp.DexTokens {
+				tokenID := strconv.FormatInt(token.TokenID, 10)
 				tokenMetadata, err := ctx.TokenMetadata.Get(tokenmetadata.GetContext{
 					Contract: token.Contract,
 					Network:  consts.Mainnet,
-					TokenID:  token.TokenID,
+					TokenID:  tokenID,
 				})
 				if err != nil {
 					if ctx.Storage.IsRecordNotFound(err) {
@@ -118,7 +120,7 @@ func (ctx *Context) appendDAppInfo(dapp *tzip.DApp, withDetails bool) (DApp, err
 					entrypoints = append(entrypoints, c.DexVolumeEntrypoints...)
 				}
 
-				vol, err := ctx.Transfers.GetToken24HoursVolume(consts.Mainnet, token.Contract, initiators, entrypoints, token.TokenID)
+				vol, err := ctx.Transfers.GetToken24HoursVolume(consts.Mainnet, token.Contract, initiators, entrypoints, tokenID)
 				if err != nil {
 					if ctx.Storage.IsRecordNotFound(err) {
 						continue
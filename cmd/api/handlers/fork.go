@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/baking-bad/bcdhub/internal/contractparser/meta"
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
 	"github.com/gin-gonic/gin"
 	"github.com/tidwall/gjson"
 )
@@ -18,7 +19,7 @@ func (ctx *Context) ForkContract(c *gin.Context) {
 	response, err := ctx.buildStorageDataFromForkRequest(req)
 	if err != nil {
 		var code int
-		if errors.As(err, &meta.ValidationError{}) || errors.As(err, &meta.RequiredError{}) {
+		if errors.As(err, &meta.ValidationError{}) || errors.As(err, &meta.RequiredError{}) || errors.As(err, &typechecker.Errors{}) {
 			code = http.StatusBadRequest
 		}
 		ctx.handleError(c, err, code)
@@ -34,6 +35,9 @@ func (ctx *Context) buildStorageDataFromForkRequest(req forkRequest) (gin.H, err
 
 	if req.Script != "" {
 		script = gjson.Parse(req.Script)
+		if _, err := typechecker.Check(script); err != nil {
+			return nil, err
+		}
 		metadata, err = meta.ParseMetadata(script.Get("#(prim==\"storage\").args"))
 		if err != nil {
 			return nil, err
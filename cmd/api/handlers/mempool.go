@@ -46,10 +46,11 @@ func (ctx *Context) GetMempool(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, ctx.mempoolPostprocessing(res, req.Network))
+	c.JSON(http.StatusOK, ctx.PrepareMempoolOperations(res, req.Network))
 }
 
-func (ctx *Context) mempoolPostprocessing(res []tzkt.MempoolOperation, network string) []Operation {
+// PrepareMempoolOperations - converts TzKT mempool operations to API response. Unsupported kinds are returned as empty operations.
+func (ctx *Context) PrepareMempoolOperations(res []tzkt.MempoolOperation, network string) []Operation {
 	ret := make([]Operation, len(res))
 	if len(res) == 0 {
 		return ret
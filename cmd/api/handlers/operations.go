@@ -14,6 +14,7 @@ import (
 	"github.com/baking-bad/bcdhub/internal/contractparser/newmiguel"
 	"github.com/baking-bad/bcdhub/internal/helpers"
 	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
+	"github.com/baking-bad/bcdhub/internal/models/contract"
 	"github.com/baking-bad/bcdhub/internal/models/operation"
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
@@ -32,6 +33,7 @@ import (
 // @Param to query integer false "Timestamp"
 // @Param size query integer false "Expected OPG count" mininum(1)
 // @Param status query string false "Comma-separated operations statuses"
+// @Param kinds query string false "Comma-separated operations kinds"
 // @Param entrypoints query string false "Comma-separated called entrypoints list"
 // @Param with_storage_diff query bool false "Include storage diff to operations or not"
 // @Accept  json
@@ -52,6 +54,14 @@ func (ctx *Context) GetContractOperations(c *gin.Context) {
 	}
 
 	filters := prepareFilters(filtersReq)
+	manager, err := ctx.getContractManager(req.Network, req.Address)
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+	if manager != "" {
+		filters["manager"] = manager
+	}
+
 	ops, err := ctx.Operations.GetByContract(req.Network, req.Address, filtersReq.Size, filters)
 	if ctx.handleError(c, err, 0) {
 		return
@@ -203,6 +213,21 @@ func (ctx *Context) getOperation(network, hash string, ops chan<- *Operation, wg
 	ops <- ctx.prepareMempoolOperation(res[0], network, string(res[0].Raw))
 }
 
+// getContractManager - returns manager of contract if it's an implicit account
+func (ctx *Context) getContractManager(network, address string) (string, error) {
+	contract := contract.NewEmptyContract(network, address)
+	if err := ctx.Storage.GetByID(&contract); err != nil {
+		if ctx.Storage.IsRecordNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if !helpers.IsAddress(contract.Manager) {
+		return "", nil
+	}
+	return contract.Manager, nil
+}
+
 func prepareFilters(req operationsRequest) map[string]interface{} {
 	filters := map[string]interface{}{}
 
@@ -223,6 +248,11 @@ func prepareFilters(req operationsRequest) map[string]interface{} {
 		filters["status"] = status
 	}
 
+	if req.Kinds != "" {
+		kinds := "'" + strings.Join(strings.Split(req.Kinds, ","), "','") + "'"
+		filters["kind"] = kinds
+	}
+
 	if req.Entrypoints != "" {
 		entrypoints := "'" + strings.Join(strings.Split(req.Entrypoints, ","), "','") + "'"
 		filters["entrypoints"] = entrypoints
@@ -240,6 +270,20 @@ func formatErrors(errs []*cerrors.Error, op *Operation) error {
 	return nil
 }
 
+// translateErrors - expands errors by `errors` section of contract metadata
+func (ctx *Context) translateErrors(network, address string, errs []*cerrors.Error) error {
+	if !helpers.IsContract(address) || !cerrors.HasScriptRejectedError(errs) {
+		return nil
+	}
+	translations, err := ctx.getErrorTranslations(network, address)
+	if err != nil || translations == nil {
+		return err
+	}
+	return translations.table.Translate(errs, func(view string, value gjson.Result) ([]byte, error) {
+		return ctx.getErrorExpansion(network, address, translations.views, view, value), nil
+	})
+}
+
 func (ctx *Context) prepareOperation(operation operation.Operation, bmd []bigmapdiff.BigMapDiff, withStorageDiff bool) (Operation, error) {
 	var op Operation
 	op.FromModel(operation)
@@ -248,6 +292,9 @@ func (ctx *Context) prepareOperation(operation operation.Operation, bmd []bigmap
 	result.FromModel(operation.Result)
 	op.Result = &result
 
+	if err := ctx.translateErrors(operation.Network, operation.Destination, operation.Errors); err != nil {
+		return op, err
+	}
 	if err := formatErrors(operation.Errors, &op); err != nil {
 		return op, err
 	}
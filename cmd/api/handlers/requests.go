@@ -1,6 +1,10 @@
 package handlers
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/baking-bad/bcdhub/internal/database"
+)
 
 type getContractRequest struct {
 	Address string `uri:"address" binding:"required,address"`
@@ -19,10 +23,11 @@ type networkQueryRequest struct {
 
 // CodeDiffLeg -
 type CodeDiffLeg struct {
-	Address  string `json:"address" binding:"required,address"`
-	Network  string `json:"network" binding:"required,network"`
+	Address  string `json:"address" binding:"required_without=Script,omitempty,address"`
+	Network  string `json:"network" binding:"required_without=Script,omitempty,network"`
 	Protocol string `json:"protocol,omitempty"`
 	Level    int64  `json:"level,omitempty"`
+	Script   string `json:"script,omitempty"`
 }
 
 // CodeDiffRequest -
@@ -36,6 +41,11 @@ type getBigMapRequest struct {
 	Ptr     int64  `uri:"ptr" binding:"min=0"`
 }
 
+type getSaplingStateRequest struct {
+	Network string `uri:"network" binding:"required,network"`
+	Ptr     int64  `uri:"ptr" binding:"min=0"`
+}
+
 type getBigMapByKeyHashRequest struct {
 	Network string `uri:"network" binding:"required,network"`
 	Ptr     int64  `uri:"ptr" binding:"min=0"`
@@ -59,6 +69,7 @@ type operationsRequest struct {
 	To              uint   `form:"to" binding:"omitempty,gtfield=From"`
 	Size            uint64 `form:"size" binding:"min=0"`
 	Status          string `form:"status" binding:"omitempty,status"`
+	Kinds           string `form:"kinds" binding:"omitempty,operation_kind"`
 	Entrypoints     string `form:"entrypoints" binding:"omitempty,excludesall=\"'"`
 	WithStorageDiff bool   `form:"with_storage_diff"`
 }
@@ -87,14 +98,14 @@ type searchRequest struct {
 
 // Subscription flags
 const (
-	WatchSame uint = 1 << iota
-	WatchSimilar
-	WatchMempool
-	WatchMigrations
-	WatchDeployments
-	WatchCalls
-	WatchErrors
-	SentryEnabled
+	WatchSame        = database.WatchSame
+	WatchSimilar     = database.WatchSimilar
+	WatchMempool     = database.WatchMempool
+	WatchMigrations  = database.WatchMigrations
+	WatchDeployments = database.WatchDeployments
+	WatchCalls       = database.WatchCalls
+	WatchErrors      = database.WatchErrors
+	SentryEnabled    = database.SentryEnabled
 )
 
 type subRequest struct {
@@ -243,6 +254,16 @@ type runCodeRequest struct {
 	Sender   string                 `json:"sender,omitempty" binding:"omitempty,address"`
 }
 
+type simulateCodeRequest struct {
+	Data    map[string]interface{} `json:"data" binding:"required"`
+	BinPath string                 `json:"bin_path" binding:"required"`
+	Amount  int64                  `json:"amount,omitempty"`
+	Source  string                 `json:"source,omitempty" binding:"omitempty,address"`
+	Sender  string                 `json:"sender,omitempty" binding:"omitempty,address"`
+	Level   int64                  `json:"level,omitempty" binding:"omitempty,min=1"`
+	Trace   bool                   `json:"trace,omitempty"`
+}
+
 type markReadRequest struct {
 	Timestamp int64 `json:"timestamp"`
 }
@@ -285,7 +306,7 @@ func (req GetTokenStatsRequest) Addresses() []string {
 type getTokenSeriesRequest struct {
 	Contract string `form:"contract" binding:"required,address"`
 	Period   string `form:"period" binding:"oneof=year month week day" example:"year"`
-	TokenID  uint   `form:"token_id"`
+	TokenID  string `form:"token_id,default=0" binding:"token_id"`
 	Slug     string `form:"slug" binding:"required"`
 }
 
@@ -327,7 +348,7 @@ type getDappRequest struct {
 
 type getContractTransfers struct {
 	pageableRequest
-	TokenID *uint `form:"token_id"  binding:"omitempty,min=0"`
+	TokenID string `form:"token_id" binding:"omitempty,token_id"`
 }
 
 type getTransfersRequest struct {
@@ -336,11 +357,11 @@ type getTransfersRequest struct {
 	End       uint   `form:"end"  binding:"omitempty,min=1,gtfield=Start"`
 	Contracts string `form:"contracts"  binding:"omitempty"`
 	Sort      string `form:"sort" binding:"omitempty,oneof=asc desc"`
-	TokenID   *int64 `form:"token_id" binding:"omitempty,min=0"`
+	TokenID   string `form:"token_id" binding:"omitempty,token_id"`
 }
 
 type getTokenHolders struct {
-	TokenID *int64 `form:"token_id" binding:"min=0"`
+	TokenID string `form:"token_id" binding:"required,token_id"`
 }
 
 type resolveDomainRequest struct {
@@ -361,3 +382,18 @@ type executeViewRequest struct {
 	Source         string                 `json:"source,omitempty" binding:"omitempty,address"`
 	Sender         string                 `json:"sender,omitempty" binding:"omitempty,address"`
 }
+
+type webhookRequest struct {
+	Address string `json:"address" binding:"required,address"`
+	Network string `json:"network" binding:"required,network"`
+	URL     string `json:"url" binding:"required,url"`
+}
+
+type getWebhookRequest struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type webhookDeliveriesRequest struct {
+	Limit  uint `form:"limit" binding:"omitempty,min=0,max=100"`
+	Offset uint `form:"offset" binding:"omitempty,min=0"`
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/baking-bad/bcdhub/internal/contractparser/cerrors"
 	"github.com/baking-bad/bcdhub/internal/contractparser/docstring"
 	"github.com/baking-bad/bcdhub/internal/contractparser/formatter"
+	"github.com/baking-bad/bcdhub/internal/contractparser/interpreter"
+	"github.com/baking-bad/bcdhub/internal/contractparser/semdiff"
+	"github.com/baking-bad/bcdhub/internal/database"
 	"github.com/baking-bad/bcdhub/internal/jsonschema"
 	"github.com/baking-bad/bcdhub/internal/models/block"
 	"github.com/baking-bad/bcdhub/internal/models/contract"
@@ -258,6 +261,12 @@ type Subscription struct {
 	SentryDSN        string    `json:"sentry_dsn,omitempty" extensions:"x-nullable"`
 }
 
+// Webhook - created webhook with its secret
+type Webhook struct {
+	database.Webhook
+	Secret string `json:"secret"`
+}
+
 // Event -
 type Event struct {
 	Event string    `json:"event"`
@@ -389,9 +398,10 @@ type BigMapDiffByKeyResponse struct {
 
 // CodeDiffResponse -
 type CodeDiffResponse struct {
-	Left  CodeDiffLeg          `json:"left"`
-	Right CodeDiffLeg          `json:"right"`
-	Diff  formatter.DiffResult `json:"diff"`
+	Left      CodeDiffLeg          `json:"left"`
+	Right     CodeDiffLeg          `json:"right"`
+	Diff      formatter.DiffResult `json:"diff"`
+	Structure *semdiff.Result      `json:"structure,omitempty" extensions:"x-nullable"`
 }
 
 // NetworkStats -
@@ -566,6 +576,38 @@ type BigMapHistoryItem struct {
 	Timestamp      time.Time `json:"timestamp"`
 }
 
+// GetSaplingStateResponse -
+type GetSaplingStateResponse struct {
+	Address       string `json:"address"`
+	Network       string `json:"network"`
+	Ptr           int64  `json:"ptr"`
+	MemoSize      int64  `json:"memo_size"`
+	SourcePtr     *int64 `json:"source_ptr,omitempty" extensions:"x-nullable"`
+	Size          int64  `json:"size"`
+	Nullifiers    int64  `json:"nullifiers"`
+	Updates       int64  `json:"updates"`
+	ContractAlias string `json:"contract_alias,omitempty" extensions:"x-nullable"`
+}
+
+// SaplingHistoryResponse -
+type SaplingHistoryResponse struct {
+	Address string               `json:"address"`
+	Network string               `json:"network"`
+	Ptr     int64                `json:"ptr"`
+	Items   []SaplingHistoryItem `json:"items,omitempty" extensions:"x-nullable"`
+}
+
+// SaplingHistoryItem -
+type SaplingHistoryItem struct {
+	Action      string    `json:"action"`
+	SourcePtr   *int64    `json:"source_ptr,omitempty" extensions:"x-nullable"`
+	Level       int64     `json:"level"`
+	Timestamp   time.Time `json:"timestamp"`
+	Hash        string    `json:"hash"`
+	Commitments []string  `json:"commitments,omitempty" extensions:"x-nullable"`
+	Nullifiers  []string  `json:"nullifiers,omitempty" extensions:"x-nullable"`
+}
+
 // Transfer -
 type Transfer struct {
 	IndexedTime    int64          `json:"indexed_time"`
@@ -578,7 +620,7 @@ type Transfer struct {
 	Level          int64          `json:"level"`
 	From           string         `json:"from"`
 	To             string         `json:"to"`
-	TokenID        int64          `json:"token_id"`
+	TokenID        string         `json:"token_id"`
 	Amount         string         `json:"amount"`
 	Counter        int64          `json:"counter"`
 	Nonce          *int64         `json:"nonce,omitempty" extensions:"x-nullable"`
@@ -695,7 +737,7 @@ type TokenMetadata struct {
 	Contract      string                 `json:"contract"`
 	Network       string                 `json:"network"`
 	Level         int64                  `json:"level,omitempty" extensions:"x-nullable"`
-	TokenID       int64                  `json:"token_id"`
+	TokenID       string                 `json:"token_id"`
 	Symbol        string                 `json:"symbol,omitempty" extensions:"x-nullable"`
 	Name          string                 `json:"name,omitempty" extensions:"x-nullable"`
 	Decimals      *int64                 `json:"decimals,omitempty" extensions:"x-nullable"`
@@ -739,7 +781,25 @@ type ViewSchema struct {
 	Type           []docstring.Typedef     `json:"typedef"`
 	Name           string                  `json:"name"`
 	Implementation int                     `json:"implementation"`
+	Kind           string                  `json:"kind"`
 	Description    string                  `json:"description"`
-	Schema         jsonschema.Schema       `json:"schema"`
+	Schema         jsonschema.Schema       `json:"schema,omitempty" extensions:"x-nullable"`
 	DefaultModel   jsonschema.DefaultModel `json:"default_model,omitempty" extensions:"x-nullable"`
+	RestAPIQuery   *tzip.RestAPIQuery      `json:"rest_api_query,omitempty" extensions:"x-nullable"`
+}
+
+// TicketBalance -
+type TicketBalance struct {
+	Ticketer    string      `json:"ticketer"`
+	ContentType interface{} `json:"content_type"`
+	Content     interface{} `json:"content"`
+	Amount      string      `json:"amount"`
+}
+
+// SimulationResult - result of native execution of entrypoint
+type SimulationResult struct {
+	Operations []Operation             `json:"operations"`
+	Trace      []interpreter.TraceItem `json:"trace,omitempty" extensions:"x-nullable"`
+	Truncated  bool                    `json:"truncated,omitempty"`
+	Steps      int64                   `json:"steps" example:"125"`
 }
@@ -90,7 +90,7 @@ func (ctx *Context) RunOperation(c *gin.Context) {
 		ctx.Storage, ctx.BigMapDiffs, ctx.Blocks, ctx.TZIP, ctx.Schema, ctx.TokenBalances,
 		operations.WithConstants(protocol.Constants),
 		operations.WithHead(header),
-		operations.WithInterfaces(ctx.Interfaces),
+		operations.WithInterfaces(ctx.Interfaces.All()),
 		operations.WithShareDirectory(ctx.SharePath),
 		operations.WithNetwork(req.Network),
 	))
@@ -234,6 +234,9 @@ func (ctx *Context) parseFailedRunCode(response gjson.Result, main *Operation) (
 	if err != nil {
 		return nil, err
 	}
+	if err := ctx.translateErrors(main.Network, main.Destination, errs); err != nil {
+		return nil, err
+	}
 	main.Errors = errs
 	if err := formatErrors(main.Errors, main); err != nil {
 		return nil, err
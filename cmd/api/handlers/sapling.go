@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/baking-bad/bcdhub/internal/models/saplingdiff"
+	"github.com/gin-gonic/gin"
+)
+
+const maxSaplingCopyDepth = 10
+
+// GetSaplingState godoc
+// @Summary Get sapling pool info by sapling state id
+// @Description Get sapling pool info by sapling state id
+// @Tags sapling
+// @ID get-sapling-state
+// @Param network path string true "Network"
+// @Param ptr path integer true "Sapling state id"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} GetSaplingStateResponse
+// @Failure 400 {object} Error
+// @Failure 404 {object} Error
+// @Failure 500 {object} Error
+// @Router /v1/sapling/{network}/{ptr} [get]
+func (ctx *Context) GetSaplingState(c *gin.Context) {
+	var req getSaplingStateRequest
+	if err := c.BindUri(&req); ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	first, err := ctx.SaplingDiffs.GetFirst(req.Network, req.Ptr)
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+
+	stats, err := ctx.SaplingDiffs.GetStats(req.Network, req.Ptr, 0)
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+
+	res := GetSaplingStateResponse{
+		Address:    first.Contract,
+		Network:    req.Network,
+		Ptr:        req.Ptr,
+		MemoSize:   first.MemoSize,
+		SourcePtr:  first.SourcePtr,
+		Size:       stats.Commitments,
+		Nullifiers: stats.Nullifiers,
+		Updates:    stats.Updates,
+	}
+
+	// copied state contains all commitments and nullifiers of its source at the moment of copying
+	for depth := 0; first.Action == saplingdiff.ActionCopy && first.SourcePtr != nil && depth < maxSaplingCopyDepth; depth++ {
+		source, err := ctx.SaplingDiffs.GetStats(req.Network, *first.SourcePtr, first.Level)
+		if ctx.handleError(c, err, 0) {
+			return
+		}
+		res.Size += source.Commitments
+		res.Nullifiers += source.Nullifiers
+
+		level := first.Level
+		first, err = ctx.SaplingDiffs.GetFirst(req.Network, *first.SourcePtr)
+		if err != nil {
+			if ctx.Storage.IsRecordNotFound(err) {
+				break
+			}
+			ctx.handleError(c, err, 0)
+			return
+		}
+		if first.Level > level {
+			break
+		}
+		if res.MemoSize == 0 {
+			res.MemoSize = first.MemoSize
+		}
+	}
+
+	alias, err := ctx.TZIP.GetAlias(req.Network, res.Address)
+	if err != nil {
+		if !ctx.Storage.IsRecordNotFound(err) {
+			ctx.handleError(c, err, 0)
+			return
+		}
+	} else {
+		res.ContractAlias = alias.Name
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// GetSaplingStateHistory godoc
+// @Summary Get sapling state diffs
+// @Description Get sapling state diffs: new commitments and nullifiers of each operation
+// @Tags sapling
+// @ID get-sapling-state-history
+// @Param network path string true "Network"
+// @Param ptr path integer true "Sapling state id"
+// @Param offset query integer false "Offset"
+// @Param size query integer false "Requested count" mininum(1) maximum(10000)
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} SaplingHistoryResponse
+// @Success 204 {object} gin.H
+// @Failure 400 {object} Error
+// @Failure 500 {object} Error
+// @Router /v1/sapling/{network}/{ptr}/history [get]
+func (ctx *Context) GetSaplingStateHistory(c *gin.Context) {
+	var req getSaplingStateRequest
+	if err := c.BindUri(&req); ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+	var page pageableRequest
+	if err := c.BindQuery(&page); ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	diffs, err := ctx.SaplingDiffs.Get(req.Network, req.Ptr, page.Size, page.Offset)
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+	if len(diffs) == 0 {
+		c.JSON(http.StatusNoContent, gin.H{})
+		return
+	}
+
+	c.JSON(http.StatusOK, prepareSaplingHistory(diffs, req.Network, req.Ptr))
+}
+
+func prepareSaplingHistory(diffs []saplingdiff.SaplingDiff, network string, ptr int64) SaplingHistoryResponse {
+	response := SaplingHistoryResponse{
+		Address: diffs[0].Contract,
+		Network: network,
+		Ptr:     ptr,
+		Items:   make([]SaplingHistoryItem, len(diffs)),
+	}
+
+	for i := range diffs {
+		response.Items[i] = SaplingHistoryItem{
+			Action:      diffs[i].Action,
+			SourcePtr:   diffs[i].SourcePtr,
+			Level:       diffs[i].Level,
+			Timestamp:   diffs[i].Timestamp,
+			Hash:        diffs[i].Hash,
+			Commitments: diffs[i].Commitments,
+			Nullifiers:  diffs[i].Nullifiers,
+		}
+	}
+	return response
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser"
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/interpreter"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// Limits of simulation. Trace is returned only on request.
+const (
+	simulateStepLimit  = 50000
+	simulateTraceLimit = 5000
+	simulateTimeout    = 5 * time.Second
+)
+
+// SimulateCode godoc
+// @Summary Execute entrypoint by native interpreter
+// @Description Execute entrypoint with passed arguments on indexed contract state at the level (the last one by default). Returns operations and, if requested, per-instruction stack trace.
+// @Tags contract
+// @ID simulate-code
+// @Param network path string true "Network"
+// @Param address path string true "KT address" minlength(36) maxlength(36)
+// @Param body body simulateCodeRequest true "Request body"
+// @Accept json
+// @Produce json
+// @Success 200 {object} SimulationResult
+// @Failure 400 {object} Error
+// @Failure 500 {object} Error
+// @Router /v1/contract/{network}/{address}/entrypoints/simulate [post]
+func (ctx *Context) SimulateCode(c *gin.Context) {
+	var req getContractRequest
+	if err := c.BindUri(&req); ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+	var reqSimulate simulateCodeRequest
+	if err := c.BindJSON(&reqSimulate); ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	state, err := ctx.Blocks.Last(req.Network)
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+	if reqSimulate.Level > 0 && reqSimulate.Level < state.Level {
+		state, err = ctx.Blocks.Get(req.Network, reqSimulate.Level)
+		if ctx.handleError(c, err, 0) {
+			return
+		}
+	}
+
+	script, err := contractparser.GetIndexedContract(req.Address, req.Network, state.Protocol, ctx.SharePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			ctx.handleError(c, errors.Errorf("Unknown contract: %s", req.Address), http.StatusNotFound)
+			return
+		}
+		ctx.handleError(c, err, 0)
+		return
+	}
+
+	input, err := ctx.buildEntrypointMicheline(req.Network, req.Address, reqSimulate.BinPath, reqSimulate.Data, true)
+	if ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+	if !input.Get("entrypoint").Exists() || !input.Get("value").Exists() {
+		ctx.handleError(c, errors.Errorf("Error during build parameters: %s", input.String()), 0)
+		return
+	}
+	entrypoint := input.Get("entrypoint").String()
+
+	last, err := ctx.Operations.LastByLevel(req.Network, req.Address, state.Level)
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+	balance, err := ctx.Operations.GetBalance(req.Network, req.Address, state.Level)
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+
+	opts := []interpreter.InterpreterOption{
+		interpreter.WithSelf(req.Address),
+		interpreter.WithSource(reqSimulate.Source),
+		interpreter.WithSender(reqSimulate.Sender),
+		interpreter.WithAmount(reqSimulate.Amount),
+		interpreter.WithBalance(balance + reqSimulate.Amount),
+		interpreter.WithChainID(state.ChainID),
+		interpreter.WithNow(state.Timestamp),
+		interpreter.WithLevel(state.Level),
+		interpreter.WithBigMaps(req.Network, ctx.BigMapDiffs, ctx.Storage),
+		interpreter.WithStepLimit(simulateStepLimit),
+		interpreter.WithTimeout(simulateTimeout),
+	}
+	if reqSimulate.Trace {
+		opts = append(opts, interpreter.WithTrace(simulateTraceLimit))
+	}
+	result, runErr := interpreter.New(opts...).Run(script.Get("code"), entrypoint, input.Get("value"), gjson.Parse(last.DeffatedStorage))
+	response, err := simulationResponse(result, runErr, state.Protocol)
+	if ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	main := Operation{
+		IndexedTime: time.Now().UTC().UnixNano(),
+		Protocol:    state.Protocol,
+		Network:     req.Network,
+		Timestamp:   state.Timestamp,
+		Source:      reqSimulate.Source,
+		Destination: req.Address,
+		Amount:      reqSimulate.Amount,
+		Kind:        consts.Transaction,
+		Level:       state.Level,
+		Status:      "applied",
+		Entrypoint:  entrypoint,
+	}
+	if err := ctx.setParameters(input.Raw, &main); ctx.handleError(c, err, 0) {
+		return
+	}
+	if err := ctx.setSimulateStorageDiff(response, script, &main); ctx.handleError(c, err, 0) {
+		return
+	}
+	operations, err := ctx.parseRunCodeResponse(response, script, &main)
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, SimulationResult{
+		Operations: operations,
+		Trace:      result.Trace,
+		Truncated:  result.Truncated,
+		Steps:      result.Steps,
+	})
+}
+
+// simulationResponse - converts result of interpreter to the form of node's `run_code` response
+func simulationResponse(result *interpreter.Result, runErr error, protocol string) (gjson.Result, error) {
+	var response interface{}
+	var failed *interpreter.FailedError
+	var runtime *interpreter.RuntimeError
+	switch {
+	case errors.As(runErr, &failed):
+		response = []map[string]interface{}{
+			{"kind": "temporary", "id": "proto." + protocol + ".michelson_v1.runtime_error"},
+			{"kind": "temporary", "id": "proto." + protocol + ".michelson_v1.script_rejected", "with": failed.With},
+		}
+	case errors.As(runErr, &runtime):
+		response = []map[string]interface{}{
+			{"kind": "temporary", "id": "proto." + protocol + ".michelson_v1.runtime_error", "with": map[string]string{consts.KeyString: runtime.Error()}},
+		}
+	case runErr != nil:
+		return gjson.Result{}, runErr
+	default:
+		diffs := make([]map[string]interface{}, 0, len(result.BigMapDiffs))
+		for _, diff := range result.BigMapDiffs {
+			// big maps allocated during execution do not exist in the chain
+			if diff.Ptr < 0 {
+				continue
+			}
+			item := map[string]interface{}{
+				"action":   "update",
+				"big_map":  diff.Ptr,
+				"key_hash": diff.KeyHash,
+				"key":      diff.Key,
+			}
+			if diff.Value != nil {
+				item["value"] = diff.Value
+			}
+			diffs = append(diffs, item)
+		}
+		response = map[string]interface{}{
+			"storage":      result.Storage,
+			"operations":   result.Operations,
+			"big_map_diff": diffs,
+		}
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	return gjson.ParseBytes(data), nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/baking-bad/bcdhub/internal/database"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
 )
 
 // ListSubscriptions -
@@ -71,10 +72,18 @@ func (ctx *Context) DeleteSubscription(c *gin.Context) {
 		return
 	}
 
-	subscription := database.Subscription{
-		UserID:  userID,
-		Address: sub.Address,
-		Network: sub.Network,
+	subscription, err := ctx.DB.GetSubscription(userID, sub.Address, sub.Network)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			c.JSON(http.StatusOK, gin.H{})
+			return
+		}
+		ctx.handleError(c, err, 0)
+		return
+	}
+
+	if err := ctx.DB.DeleteSubscriptionWebhooks(subscription.ID); ctx.handleError(c, err, 0) {
+		return
 	}
 
 	if err := ctx.DB.DeleteSubscription(&subscription); ctx.handleError(c, err, 0) {
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/meta"
+	"github.com/baking-bad/bcdhub/internal/contractparser/newmiguel"
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
+)
+
+// GetContractTickets godoc
+// @Summary Get contract ticket balances
+// @Description Get tickets held by contract grouped by ticketer and content
+// @Tags contract
+// @ID get-contract-tickets
+// @Param network path string true "Network"
+// @Param address path string true "KT address" minlength(36) maxlength(36)
+// @Accept json
+// @Produce json
+// @Success 200 {array} TicketBalance
+// @Failure 400 {object} Error
+// @Failure 500 {object} Error
+// @Router /v1/contract/{network}/{address}/tickets [get]
+func (ctx *Context) GetContractTickets(c *gin.Context) {
+	var req getContractRequest
+	if err := c.BindUri(&req); ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	balances, err := ctx.TicketBalances.GetAccountBalances(req.Network, req.Address)
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+
+	result, err := prepareTicketBalances(balances)
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func prepareTicketBalances(balances []ticketbalance.TicketBalance) ([]TicketBalance, error) {
+	result := make([]TicketBalance, len(balances))
+	for i := range balances {
+		contentType := gjson.Parse(balances[i].ContentType)
+		metadata, err := meta.ParseMetadata(contentType)
+		if err != nil {
+			return nil, err
+		}
+		content, err := newmiguel.MichelineToMiguel(gjson.Parse(balances[i].Content), metadata)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = TicketBalance{
+			Ticketer:    balances[i].Ticketer,
+			ContentType: contentType.Value(),
+			Content:     content,
+			Amount:      balances[i].Value.String(),
+		}
+	}
+	return result, nil
+}
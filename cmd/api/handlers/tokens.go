@@ -56,11 +56,11 @@ func (ctx *Context) GetFA(c *gin.Context) {
 
 // GetFAByVersion godoc
 // @Summary Get all contracts that implement FA1/FA1.2 standard by version
-// @Description Get all contracts that implement FA1/FA1.2 standard by version
+// @Description Get all contracts that implement FA1/FA1.2 standard or any other registered contract interface by version
 // @Tags tokens
 // @ID get-fa-version
 // @Param network path string true "Network"
-// @Param faversion path string true "FA token version" Enums(fa1, fa12, fa2)
+// @Param faversion path string true "FA token version or name of registered contract interface"
 // @Param offset query integer false "Offset"
 // @Param size query integer false "Requested count" minimum(0) maximum(100)
 // @Accept json
@@ -108,7 +108,7 @@ func (ctx *Context) GetFAByVersion(c *gin.Context) {
 // @Param start query integer false "Timestamp in seconds" mininum(1)
 // @Param end query integer false "Timestamp in seconds" mininum(1)
 // @Param contracts query string false "Comma-separated list of contracts which tokens will be requested"
-// @Param token_id query integer false "Token ID" mininum(0)
+// @Param token_id query string false "Token ID (decimal string)"
 // @Accept json
 // @Produce json
 // @Success 200 {object} TransferResponse
@@ -131,11 +131,6 @@ func (ctx *Context) GetFA12OperationsForAddress(c *gin.Context) {
 		contracts = strings.Split(ctxReq.Contracts, ",")
 	}
 
-	tokenID := int64(-1)
-	if ctxReq.TokenID != nil {
-		tokenID = *ctxReq.TokenID
-	}
-
 	transfers, err := ctx.Transfers.Get(transfer.GetContext{
 		Network:   req.Network,
 		Address:   req.Address,
@@ -145,7 +140,7 @@ func (ctx *Context) GetFA12OperationsForAddress(c *gin.Context) {
 		LastID:    ctxReq.LastID,
 		SortOrder: ctxReq.Sort,
 		Size:      ctxReq.Size,
-		TokenID:   tokenID,
+		TokenID:   ctxReq.TokenID,
 	})
 	if ctx.handleError(c, err, 0) {
 		return
@@ -167,7 +162,7 @@ func (ctx *Context) GetFA12OperationsForAddress(c *gin.Context) {
 // @Param network path string true "Network"
 // @Param period query string true "One of periods"  Enums(year, month, week, day)
 // @Param contract path string true "KT address" minlength(36) maxlength(36)
-// @Param token_id query int true "Token ID" minimum(0)
+// @Param token_id query string true "Token ID (decimal string)"
 // @Accept json
 // @Produce  json
 // @Success 200 {object} SeriesFloat
@@ -227,11 +222,14 @@ func (ctx *Context) contractToTokens(contracts []contract.Contract, network, ver
 				tokens[i].Type = consts.FA1Tag
 			}
 		}
+		if tokens[i].Type == "" {
+			tokens[i].Type = version
+		}
 		addresses[i] = tokens[i].Address
 	}
 
 	if version != "" {
-		interfaceVersion, ok := ctx.Interfaces[version]
+		interfaceVersion, ok := ctx.Interfaces.Get(version)
 		if !ok {
 			return PageableTokenContracts{}, errors.Errorf("Unknown interface version: %s", version)
 		}
@@ -303,7 +301,6 @@ func (ctx *Context) getTokens(network, address string) ([]Token, error) {
 	metadata, err := ctx.TokenMetadata.Get(tokenmetadata.GetContext{
 		Contract: address,
 		Network:  network,
-		TokenID:  -1,
 	})
 	if err != nil {
 		if ctx.Storage.IsRecordNotFound(err) {
@@ -335,7 +332,7 @@ func (ctx *Context) getTokens(network, address string) ([]Token, error) {
 // @ID get-token-holders
 // @Param network path string true "Network"
 // @Param address path string true "KT address" minlength(36) maxlength(36)
-// @Param token_id query int true "Token ID" minimum(0)
+// @Param token_id query string true "Token ID (decimal string)"
 // @Accept  json
 // @Produce  json
 // @Success 200 {array} gin.H
@@ -352,7 +349,7 @@ func (ctx *Context) GetTokenHolders(c *gin.Context) {
 		return
 	}
 
-	balances, err := ctx.TokenBalances.GetHolders(req.Network, req.Address, *reqArgs.TokenID)
+	balances, err := ctx.TokenBalances.GetHolders(req.Network, req.Address, reqArgs.TokenID)
 	if ctx.handleError(c, err, 0) {
 		return
 	}
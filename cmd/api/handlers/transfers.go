@@ -17,7 +17,7 @@ import (
 // @Param address path string true "KT address" minlength(36) maxlength(36)
 // @Param size query integer false "Transfers count" mininum(1)
 // @Param offset query integer false "Offset" mininum(1)
-// @Param token_id query integer false "Token ID" mininum(1)
+// @Param token_id query string false "Token ID (decimal string)"
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} TransferResponse
@@ -34,17 +34,12 @@ func (ctx *Context) GetContractTransfers(c *gin.Context) {
 		return
 	}
 
-	tokenID := int64(-1)
-	if req.TokenID != nil {
-		tokenID = int64(*req.TokenID)
-	}
-
 	transfers, err := ctx.Transfers.Get(transfer.GetContext{
 		Network:   contractRequest.Network,
 		Contracts: []string{contractRequest.Address},
 		Size:      req.Size,
 		Offset:    req.Offset,
-		TokenID:   tokenID,
+		TokenID:   req.TokenID,
 	})
 	if ctx.handleError(c, err, 0) {
 		return
@@ -59,7 +54,38 @@ func (ctx *Context) GetContractTransfers(c *gin.Context) {
 type tokenKey struct {
 	Network  string
 	Contract string
-	TokenID  int64
+	TokenID  string
+}
+
+// PrepareTransfer - returns transfer response with token metadata and aliases
+func (ctx *Context) PrepareTransfer(model transfer.Transfer) (Transfer, error) {
+	t := TransferFromElasticModel(model)
+
+	tokens, err := ctx.TokenMetadata.Get(tokenmetadata.GetContext{
+		Network:  model.Network,
+		Contract: model.Contract,
+		TokenID:  model.TokenID,
+	})
+	if err != nil {
+		if !ctx.Storage.IsRecordNotFound(err) {
+			return t, err
+		}
+	} else if len(tokens) > 0 {
+		t.Token = &TokenMetadata{
+			Contract: tokens[0].Contract,
+			TokenID:  tokens[0].TokenID,
+			Symbol:   tokens[0].Symbol,
+			Name:     tokens[0].Name,
+			Decimals: tokens[0].Decimals,
+			Network:  tokens[0].Network,
+		}
+	}
+
+	t.Alias = ctx.getAlias(model.Network, model.Contract)
+	t.InitiatorAlias = ctx.getAlias(model.Network, model.Initiator)
+	t.FromAlias = ctx.getAlias(model.Network, model.From)
+	t.ToAlias = ctx.getAlias(model.Network, model.To)
+	return t, nil
 }
 
 func (ctx *Context) transfersPostprocessing(transfers transfer.Pageable, withLastID bool) (response TransferResponse, err error) {
@@ -70,9 +96,7 @@ func (ctx *Context) transfersPostprocessing(transfers transfer.Pageable, withLas
 	}
 
 	mapTokens := make(map[tokenKey]*TokenMetadata)
-	tokens, err := ctx.TokenMetadata.Get(tokenmetadata.GetContext{
-		TokenID: -1,
-	})
+	tokens, err := ctx.TokenMetadata.Get(tokenmetadata.GetContext{})
 	if err != nil {
 		if !ctx.Storage.IsRecordNotFound(err) {
 			return
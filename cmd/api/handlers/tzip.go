@@ -36,3 +36,40 @@ func (ctx *Context) GetMetadata(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, tzip)
 }
+
+// GetMetadataHistory godoc
+// @Summary Get metadata history for account
+// @Description Returns versions of account metadata set by updates of `%metadata` big map from the newest one
+// @Tags account
+// @ID get-account-tzip-history
+// @Param network path string true "Network"
+// @Param address path string true "KT address" minlength(36) maxlength(36)
+// @Param offset query integer false "Offset"
+// @Param size query integer false "Requested count" mininum(1) maximum(10000)
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} tzipversion.Version
+// @Success 204 {object} gin.H
+// @Failure 400 {object} Error
+// @Failure 500 {object} Error
+// @Router /v1/account/{network}/{address}/metadata/history [get]
+func (ctx *Context) GetMetadataHistory(c *gin.Context) {
+	var req getContractRequest
+	if err := c.BindUri(&req); ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+	var page pageableRequest
+	if err := c.BindQuery(&page); ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	versions, err := ctx.TZIPVersions.Get(req.Network, req.Address, page.Size, page.Offset)
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+	if len(versions) == 0 {
+		c.JSON(http.StatusNoContent, gin.H{})
+		return
+	}
+	c.JSON(http.StatusOK, versions)
+}
@@ -2,13 +2,17 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/baking-bad/bcdhub/internal/contractparser/cerrors"
 	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
 	"github.com/baking-bad/bcdhub/internal/contractparser/docstring"
 	"github.com/baking-bad/bcdhub/internal/contractparser/meta"
 	"github.com/baking-bad/bcdhub/internal/contractparser/newmiguel"
 	"github.com/baking-bad/bcdhub/internal/jsonschema"
+	"github.com/baking-bad/bcdhub/internal/logger"
 	"github.com/baking-bad/bcdhub/internal/models/tzip"
 	"github.com/baking-bad/bcdhub/internal/views"
 	"github.com/gin-gonic/gin"
@@ -40,28 +44,36 @@ func (ctx *Context) GetViewsSchema(c *gin.Context) {
 		return
 	}
 
-	tzip, err := ctx.TZIP.Get(req.Network, req.Address)
+	tzipValue, err := ctx.TZIP.Get(req.Network, req.Address)
 	if ctx.handleError(c, err, 0) {
 		return
 	}
 
 	schemas := make([]ViewSchema, 0)
 
-	if len(tzip.Views) == 0 {
+	if len(tzipValue.Views) == 0 {
 		c.JSON(http.StatusOK, schemas)
 		return
 	}
 
-	for _, view := range tzip.Views {
+	for _, view := range tzipValue.Views {
 		for i, impl := range view.Implementations {
-			if impl.MichelsonStorageView.Empty() {
-				continue
-			}
-
 			schema := ViewSchema{
 				Name:           view.Name,
 				Description:    view.Description,
 				Implementation: i,
+				Kind:           impl.Kind(),
+			}
+
+			switch schema.Kind {
+			case "":
+				continue
+			case tzip.ViewKindRestAPIQuery:
+				// arguments of REST API views are described by OpenAPI specification, so michelson schema can not be built
+				restAPIQuery := impl.RestAPIQuery
+				schema.RestAPIQuery = &restAPIQuery
+				schemas = append(schemas, schema)
+				continue
 			}
 
 			metadata, err := getViewMetadata(impl)
@@ -112,16 +124,6 @@ func (ctx *Context) ExecuteView(c *gin.Context) {
 		return
 	}
 
-	rpc, err := ctx.GetRPC(req.Network)
-	if ctx.handleError(c, err, http.StatusBadRequest) {
-		return
-	}
-
-	state, err := ctx.Blocks.Last(req.Network)
-	if ctx.handleError(c, err, 0) {
-		return
-	}
-
 	tzipValue, err := ctx.TZIP.Get(req.Network, req.Address)
 	if ctx.handleError(c, err, 0) {
 		return
@@ -145,8 +147,33 @@ func (ctx *Context) ExecuteView(c *gin.Context) {
 		impl = view.Implementations[idx]
 		break
 	}
-	if impl.MichelsonStorageView.Empty() {
+	switch impl.Kind() {
+	case tzip.ViewKindMichelsonStorage:
+		ctx.executeMichelsonStorageView(c, req, execView, impl)
+	case tzip.ViewKindRestAPIQuery:
+		ctx.executeRestAPIQueryView(c, execView, impl)
+	default:
 		ctx.handleError(c, errEmptyImplementation, 0)
+	}
+}
+
+func (ctx *Context) executeRestAPIQueryView(c *gin.Context, execView executeViewRequest, impl tzip.ViewImplementation) {
+	view := views.NewRestAPIQueryView(impl, execView.Name)
+	response, err := ctx.RestViews.Execute(view, execView.Data)
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (ctx *Context) executeMichelsonStorageView(c *gin.Context, req getContractRequest, execView executeViewRequest, impl tzip.ViewImplementation) {
+	rpc, err := ctx.GetRPC(req.Network)
+	if ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	state, err := ctx.Blocks.Last(req.Network)
+	if ctx.handleError(c, err, 0) {
 		return
 	}
 
@@ -192,6 +219,96 @@ func (ctx *Context) ExecuteView(c *gin.Context) {
 	c.JSON(http.StatusOK, data)
 }
 
+// errorTranslations - cached translation table of contract errors
+type errorTranslations struct {
+	table *cerrors.Translations
+	views []tzip.View
+}
+
+// getErrorTranslations - returns translation table of contract errors. Returns nil if contract has no translations.
+func (ctx *Context) getErrorTranslations(network, address string) (*errorTranslations, error) {
+	key := fmt.Sprintf("errors:%s:%s", network, address)
+	item, err := ctx.Cache.Fetch(key, time.Minute*10, func() (interface{}, error) {
+		metadata, err := ctx.TZIP.Get(network, address)
+		if err != nil {
+			if ctx.Storage.IsRecordNotFound(err) {
+				return (*errorTranslations)(nil), nil
+			}
+			return nil, err
+		}
+		table := cerrors.NewTranslations(metadata.Errors)
+		if table.Empty() {
+			return (*errorTranslations)(nil), nil
+		}
+		return &errorTranslations{table, metadata.Views}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item.Value().(*errorTranslations), nil
+}
+
+// getErrorExpansion - returns cached expansion of error `value` by view `name`. Failed executions are cached too, so node is called once per contract and error value.
+func (ctx *Context) getErrorExpansion(network, address string, metadataViews []tzip.View, name string, value gjson.Result) []byte {
+	key := fmt.Sprintf("error_view:%s:%s:%s:%s", network, address, name, value.Raw)
+	item, err := ctx.Cache.Fetch(key, time.Minute*10, func() (interface{}, error) {
+		expansion, err := ctx.executeErrorView(network, address, metadataViews, name, value)
+		if err != nil {
+			logger.Warning("[%s] error view %s of %s: %s", network, name, address, err)
+			return []byte(nil), nil
+		}
+		return expansion, nil
+	})
+	if err != nil {
+		return nil
+	}
+	return item.Value().([]byte)
+}
+
+// executeErrorView - computes expansion of error `value` by michelson storage view `name`. Returns nil if view returns `None`.
+func (ctx *Context) executeErrorView(network, address string, metadataViews []tzip.View, name string, value gjson.Result) ([]byte, error) {
+	var impl *tzip.ViewImplementation
+	for i := range metadataViews {
+		if metadataViews[i].Name != name {
+			continue
+		}
+		for j := range metadataViews[i].Implementations {
+			if metadataViews[i].Implementations[j].Kind() == tzip.ViewKindMichelsonStorage {
+				impl = &metadataViews[i].Implementations[j]
+				break
+			}
+		}
+		break
+	}
+	if impl == nil {
+		return nil, nil
+	}
+
+	rpc, err := ctx.GetRPC(network)
+	if err != nil {
+		return nil, err
+	}
+	state, err := ctx.Blocks.Last(network)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := views.ExecuteWithoutParsing(rpc, views.NewMichelsonStorageView(*impl, name), views.Context{
+		Network:    network,
+		Contract:   address,
+		ChainID:    state.ChainID,
+		Protocol:   state.Protocol,
+		Parameters: value.Raw,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if response.Get("prim").String() == consts.None {
+		return nil, nil
+	}
+	return []byte(response.Get("args.0").Raw), nil
+}
+
 func getViewMetadata(impl tzip.ViewImplementation) (meta.Metadata, error) {
 	var params gjson.Result
 	if !impl.MichelsonStorageView.IsParameterEmpty() {
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/baking-bad/bcdhub/internal/database"
+	"github.com/baking-bad/bcdhub/internal/webhook"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+const defaultDeliveriesLimit = 20
+
+// ListWebhooks -
+func (ctx *Context) ListWebhooks(c *gin.Context) {
+	userID := CurrentUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user"})
+		return
+	}
+
+	webhooks, err := ctx.DB.ListWebhooks(userID)
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// CreateWebhook - registers webhook of subscription. Secret of webhook is returned only in this response.
+func (ctx *Context) CreateWebhook(c *gin.Context) {
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+	if err := ctx.WebhookGuard.CheckURL(c.Request.Context(), req.URL); err != nil {
+		ctx.handleError(c, errors.Wrapf(err, "Invalid webhook URL: %s", req.URL), http.StatusBadRequest)
+		return
+	}
+
+	userID := CurrentUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user"})
+		return
+	}
+
+	subscription, err := ctx.DB.GetSubscription(userID, req.Address, req.Network)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			ctx.handleError(c, errors.Errorf("You have to subscribe to %s before webhook creation", req.Address), http.StatusBadRequest)
+			return
+		}
+		ctx.handleError(c, err, 0)
+		return
+	}
+
+	secret, err := webhook.NewSecret()
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+
+	hook := database.Webhook{
+		UserID:         userID,
+		SubscriptionID: subscription.ID,
+		URL:            req.URL,
+		Secret:         secret,
+		Active:         true,
+	}
+	if err := ctx.DB.CreateWebhook(&hook); ctx.handleError(c, err, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, Webhook{
+		Webhook: hook,
+		Secret:  hook.Secret,
+	})
+}
+
+// DeleteWebhook -
+func (ctx *Context) DeleteWebhook(c *gin.Context) {
+	var req getWebhookRequest
+	if err := c.BindUri(&req); ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	userID := CurrentUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user"})
+		return
+	}
+
+	if err := ctx.DB.DeleteWebhook(userID, req.ID); err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			ctx.handleError(c, errors.Errorf("Unknown webhook: %d", req.ID), http.StatusNotFound)
+			return
+		}
+		ctx.handleError(c, err, 0)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// ListWebhookDeliveries -
+func (ctx *Context) ListWebhookDeliveries(c *gin.Context) {
+	var req getWebhookRequest
+	if err := c.BindUri(&req); ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+	var pageReq webhookDeliveriesRequest
+	if err := c.BindQuery(&pageReq); ctx.handleError(c, err, http.StatusBadRequest) {
+		return
+	}
+
+	userID := CurrentUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user"})
+		return
+	}
+
+	hook, err := ctx.DB.GetWebhook(req.ID)
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			ctx.handleError(c, errors.Errorf("Unknown webhook: %d", req.ID), http.StatusNotFound)
+			return
+		}
+		ctx.handleError(c, err, 0)
+		return
+	}
+	if hook.UserID != userID {
+		ctx.handleError(c, errors.Errorf("Unknown webhook: %d", req.ID), http.StatusNotFound)
+		return
+	}
+
+	if pageReq.Limit == 0 {
+		pageReq.Limit = defaultDeliveriesLimit
+	}
+	deliveries, err := ctx.DB.ListWebhookDeliveries(hook.ID, pageReq.Limit, pageReq.Offset)
+	if ctx.handleError(c, err, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
@@ -76,7 +76,7 @@ func (api *app) makeRouter() {
 	r.MaxMultipartMemory = 4 << 20 // max upload size 4 MiB
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		if err := validations.Register(v, api.Context.Config.API.Networks); err != nil {
+		if err := validations.Register(v, api.Context.Config.API.Networks, api.Context.Interfaces); err != nil {
 			logger.Fatal(err)
 		}
 	}
@@ -139,6 +139,12 @@ func (api *app) makeRouter() {
 			}
 		}
 
+		sapling := v1.Group("sapling/:network/:ptr")
+		{
+			sapling.GET("", api.Context.GetSaplingState)
+			sapling.GET("history", api.Context.GetSaplingStateHistory)
+		}
+
 		contract := v1.Group("contract/:network/:address")
 		contract.Use(api.Context.IsAuthenticated())
 		{
@@ -147,6 +153,7 @@ func (api *app) makeRouter() {
 			contract.GET("operations", api.Context.GetContractOperations)
 			contract.GET("migrations", api.Context.GetContractMigrations)
 			contract.GET("transfers", api.Context.GetContractTransfers)
+			contract.GET("tickets", api.Context.GetContractTickets)
 
 			tokens := contract.Group("tokens")
 			{
@@ -171,6 +178,7 @@ func (api *app) makeRouter() {
 				entrypoints.GET("schema", api.Context.GetEntrypointSchema)
 				entrypoints.POST("data", api.Context.GetEntrypointData)
 				entrypoints.POST("trace", api.Context.RunCode)
+				entrypoints.POST("simulate", api.Context.SimulateCode)
 				entrypoints.POST("run_operation", api.Context.RunOperation)
 			}
 			views := contract.Group("views")
@@ -190,6 +198,7 @@ func (api *app) makeRouter() {
 		{
 			account.GET("", api.Context.GetInfo)
 			account.GET("metadata", api.Context.GetMetadata)
+			account.GET("metadata/history", api.Context.GetMetadataHistory)
 		}
 
 		fa12 := v1.Group("tokens/:network")
@@ -230,6 +239,14 @@ func (api *app) makeRouter() {
 					subscriptions.DELETE("", api.Context.DeleteSubscription)
 					subscriptions.GET("events", api.Context.GetEvents)
 					subscriptions.GET("mempool", api.Context.GetMempoolEvents)
+
+					webhooks := subscriptions.Group("webhooks")
+					{
+						webhooks.GET("", api.Context.ListWebhooks)
+						webhooks.POST("", api.Context.CreateWebhook)
+						webhooks.DELETE(":id", api.Context.DeleteWebhook)
+						webhooks.GET(":id/deliveries", api.Context.ListWebhookDeliveries)
+					}
 				}
 				vote := profile.Group("vote")
 				{
@@ -6,13 +6,14 @@ import (
 
 	"github.com/baking-bad/bcdhub/internal/compiler/compilation"
 	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/kinds"
 	"github.com/baking-bad/bcdhub/internal/helpers"
 	"github.com/btcsuite/btcutil/base58"
 	"gopkg.in/go-playground/validator.v9"
 )
 
-// Register -
-func Register(v *validator.Validate, networks []string) error {
+// Register - `interfaces` are contract interfaces which can be requested by version
+func Register(v *validator.Validate, networks []string, interfaces *kinds.Registry) error {
 	if err := v.RegisterValidation("address", addressValidator()); err != nil {
 		return err
 	}
@@ -29,7 +30,11 @@ func Register(v *validator.Validate, networks []string) error {
 		return err
 	}
 
-	if err := v.RegisterValidation("faversion", faVersionValidator()); err != nil {
+	if err := v.RegisterValidation("operation_kind", operationKindValidator()); err != nil {
+		return err
+	}
+
+	if err := v.RegisterValidation("faversion", faVersionValidator(interfaces)); err != nil {
 		return err
 	}
 
@@ -49,6 +54,10 @@ func Register(v *validator.Validate, networks []string) error {
 		return err
 	}
 
+	if err := v.RegisterValidation("token_id", tokenIDValidator()); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -81,6 +90,12 @@ func opgValidator() validator.Func {
 	}
 }
 
+func tokenIDValidator() validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		return helpers.IsDecimal(fl.Field().String())
+	}
+}
+
 func statusValidator() validator.Func {
 	return func(fl validator.FieldLevel) bool {
 		status := fl.Field().String()
@@ -99,14 +114,29 @@ func statusValidator() validator.Func {
 	}
 }
 
-func faVersionValidator() validator.Func {
+func operationKindValidator() validator.Func {
 	return func(fl validator.FieldLevel) bool {
-		version := fl.Field().String()
-		return helpers.StringInArray(version, []string{
-			consts.FA1Tag,
-			consts.FA12Tag,
-			consts.FA2Tag,
-		})
+		kind := fl.Field().String()
+		data := strings.Split(kind, ",")
+		for i := range data {
+			if !helpers.StringInArray(data[i], []string{
+				consts.Transaction,
+				consts.Origination,
+				consts.OriginationNew,
+				consts.Delegation,
+				consts.Reveal,
+			}) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func faVersionValidator(interfaces *kinds.Registry) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		_, ok := interfaces.Get(fl.Field().String())
+		return ok
 	}
 }
 
@@ -0,0 +1,154 @@
+package channels
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/baking-bad/bcdhub/cmd/api/handlers"
+	"github.com/baking-bad/bcdhub/cmd/api/ws/datasources"
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
+	"github.com/baking-bad/bcdhub/internal/mq"
+	"github.com/pkg/errors"
+)
+
+// BigMapBody -
+type BigMapBody struct {
+	Network string              `json:"network"`
+	Address string              `json:"address"`
+	Ptr     int64               `json:"ptr"`
+	Item    handlers.BigMapItem `json:"item"`
+}
+
+// BigMapChannel - pushes decoded updates of big map. If `KeyHash` is set only updates of the key are pushed.
+type BigMapChannel struct {
+	*DefaultChannel
+	Network string
+	Ptr     int64
+	KeyHash string
+
+	messages chan Message
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewBigMapChannel -
+func NewBigMapChannel(network string, ptr int64, keyHash string, opts ...ChannelOption) *BigMapChannel {
+	return &BigMapChannel{
+		DefaultChannel: NewDefaultChannel(opts...),
+		Network:        network,
+		Ptr:            ptr,
+		KeyHash:        keyHash,
+
+		messages: make(chan Message, 10),
+		stop:     make(chan struct{}),
+	}
+}
+
+// BigMapChannelName -
+func BigMapChannelName(network string, ptr int64, keyHash string) string {
+	if keyHash == "" {
+		return fmt.Sprintf("bigmap_%s_%d", network, ptr)
+	}
+	return fmt.Sprintf("bigmap_%s_%d_%s", network, ptr, keyHash)
+}
+
+// GetName -
+func (c *BigMapChannel) GetName() string {
+	return BigMapChannelName(c.Network, c.Ptr, c.KeyHash)
+}
+
+// Run -
+func (c *BigMapChannel) Run() {
+	if len(c.sources) == 0 {
+		logger.Errorf("[%s] Empty source list", c.GetName())
+		return
+	}
+
+	for i := range c.sources {
+		c.wg.Add(1)
+		go c.listen(c.sources[i])
+	}
+}
+
+// Listen -
+func (c *BigMapChannel) Listen() <-chan Message {
+	return c.messages
+}
+
+// Stop -
+func (c *BigMapChannel) Stop() {
+	close(c.stop)
+	c.wg.Wait()
+	close(c.messages)
+}
+
+// Init -
+func (c *BigMapChannel) Init() error {
+	c.messages <- Message{
+		ChannelName: c.GetName(),
+		Body:        "ok",
+	}
+	return nil
+}
+
+func (c *BigMapChannel) listen(source datasources.DataSource) {
+	defer c.wg.Done()
+
+	ch := source.Subscribe()
+	for {
+		select {
+		case <-c.stop:
+			source.Unsubscribe(ch)
+			return
+		case data := <-ch:
+			if data.Type != datasources.RabbitType || data.Kind != mq.QueueBigMapDiffs {
+				continue
+			}
+			if err := c.createMessage(data); err != nil {
+				logger.Error(err)
+			}
+		}
+	}
+}
+
+func (c *BigMapChannel) createMessage(data datasources.Data) error {
+	msg, err := bigmapdiff.ParseQueueMessage(data.Body.([]byte))
+	if err != nil {
+		return errors.Errorf("[BigMapChannel.createMessage] Invalid message: %s", err)
+	}
+	if msg.Network != "" && !c.matches(msg.Network, msg.Ptr, msg.KeyHash) {
+		return nil
+	}
+
+	diff := bigmapdiff.BigMapDiff{ID: msg.ID}
+	if err := c.ctx.Storage.GetByID(&diff); err != nil {
+		return errors.Errorf("[BigMapChannel.createMessage] Find big map diff error: %s", err)
+	}
+	if !c.matches(diff.Network, diff.Ptr, diff.KeyHash) {
+		return nil
+	}
+
+	item, err := c.ctx.PrepareBigMapDiff(diff)
+	if err != nil {
+		return err
+	}
+
+	c.messages <- Message{
+		ChannelName: c.GetName(),
+		Body: BigMapBody{
+			Network: diff.Network,
+			Address: diff.Address,
+			Ptr:     diff.Ptr,
+			Item:    item,
+		},
+	}
+	return nil
+}
+
+func (c *BigMapChannel) matches(network string, ptr int64, keyHash string) bool {
+	if network != c.Network || ptr != c.Ptr {
+		return false
+	}
+	return c.KeyHash == "" || keyHash == c.KeyHash
+}
@@ -0,0 +1,139 @@
+package channels
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/baking-bad/bcdhub/cmd/api/handlers"
+	"github.com/baking-bad/bcdhub/cmd/api/ws/datasources"
+	"github.com/baking-bad/bcdhub/internal/config"
+	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
+	mock_general "github.com/baking-bad/bcdhub/internal/models/mock"
+	"github.com/baking-bad/bcdhub/internal/mq"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+)
+
+type testSource struct {
+	*datasources.DefaultSource
+
+	mx    sync.Mutex
+	count int
+}
+
+func newTestSource() *testSource {
+	return &testSource{DefaultSource: datasources.NewDefaultSource()}
+}
+
+func (s *testSource) Run()            {}
+func (s *testSource) Stop()           {}
+func (s *testSource) GetType() string { return datasources.RabbitType }
+
+func (s *testSource) Subscribe() chan datasources.Data {
+	s.mx.Lock()
+	s.count++
+	s.mx.Unlock()
+	return s.DefaultSource.Subscribe()
+}
+
+func (s *testSource) Unsubscribe(ch chan datasources.Data) {
+	s.mx.Lock()
+	s.count--
+	s.mx.Unlock()
+	s.DefaultSource.Unsubscribe(ch)
+}
+
+func (s *testSource) subscribers() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.count
+}
+
+func marshalToQueue(t *testing.T, msg mq.IMessage) []byte {
+	body, err := msg.MarshalToQueue()
+	if err != nil {
+		t.Fatalf("MarshalToQueue() error = %v", err)
+	}
+	return body
+}
+
+func TestBigMapChannel_createMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyHash string
+		diff    bigmapdiff.BigMapDiff
+		body    []byte
+		wantGet bool
+	}{
+		{
+			name: "other network",
+			diff: bigmapdiff.BigMapDiff{ID: "diff", Network: "edo2net", Ptr: 10},
+		}, {
+			name: "other pointer",
+			diff: bigmapdiff.BigMapDiff{ID: "diff", Network: "mainnet", Ptr: 11},
+		}, {
+			name:    "other key",
+			keyHash: "expru1",
+			diff:    bigmapdiff.BigMapDiff{ID: "diff", Network: "mainnet", Ptr: 10, KeyHash: "expru2"},
+		}, {
+			name:    "subscribed pointer",
+			diff:    bigmapdiff.BigMapDiff{ID: "diff", Network: "mainnet", Ptr: 10, KeyHash: "expru2"},
+			wantGet: true,
+		}, {
+			name:    "subscribed key",
+			keyHash: "expru1",
+			diff:    bigmapdiff.BigMapDiff{ID: "diff", Network: "mainnet", Ptr: 10, KeyHash: "expru1"},
+			wantGet: true,
+		}, {
+			name:    "previous format",
+			body:    []byte("diff"),
+			wantGet: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mock_general.NewMockGeneralRepository(ctrl)
+			if tt.wantGet {
+				storage.EXPECT().GetByID(&bigmapdiff.BigMapDiff{ID: "diff"}).Return(errors.New("not found"))
+			}
+
+			body := tt.body
+			if body == nil {
+				body = marshalToQueue(t, &tt.diff)
+			}
+
+			c := NewBigMapChannel("mainnet", 10, tt.keyHash, WithContext(&handlers.Context{
+				Context: &config.Context{Storage: storage},
+			}))
+			err := c.createMessage(datasources.Data{Type: datasources.RabbitType, Kind: mq.QueueBigMapDiffs, Body: body})
+			if (err != nil) != tt.wantGet {
+				t.Errorf("createMessage() error = %v, wantGet %v", err, tt.wantGet)
+			}
+		})
+	}
+}
+
+func TestBigMapChannel_Stop(t *testing.T) {
+	source := newTestSource()
+	c := NewBigMapChannel("mainnet", 10, "", WithSource([]datasources.DataSource{source}, datasources.RabbitType))
+	c.Run()
+
+	if err := c.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if msg := <-c.Listen(); msg.Body != "ok" {
+		t.Errorf("Init() message = %v", msg)
+	}
+
+	c.Stop()
+
+	if count := source.subscribers(); count != 0 {
+		t.Errorf("channel is not unsubscribed: %d subscribers", count)
+	}
+	if _, ok := <-c.Listen(); ok {
+		t.Errorf("messages channel is not closed")
+	}
+}
@@ -17,6 +17,7 @@ type Channel interface {
 // DefaultChannel -
 type DefaultChannel struct {
 	sources []datasources.DataSource
+	mempool *MempoolPoller
 
 	ctx *handlers.Context
 }
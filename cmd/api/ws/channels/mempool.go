@@ -0,0 +1,389 @@
+package channels
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/baking-bad/bcdhub/cmd/api/handlers"
+	"github.com/baking-bad/bcdhub/cmd/api/ws/datasources"
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/models/block"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/mq"
+	"github.com/baking-bad/bcdhub/internal/tzkt"
+	"github.com/pkg/errors"
+)
+
+// Mempool operation statuses
+const (
+	MempoolPending = "pending"
+	MempoolApplied = "applied"
+	MempoolFailed  = "failed"
+	MempoolDropped = "dropped"
+)
+
+const (
+	mempoolPollPeriod = 5 * time.Second
+	// operation which is absent in mempool and is not indexed during `mempoolDropBlocks` blocks is considered as dropped
+	mempoolDropBlocks = 3
+)
+
+// MempoolBody - message of mempool channel. Operations are taken from mempool for pending status and from index for others.
+type MempoolBody struct {
+	Hash       string               `json:"hash"`
+	Status     string               `json:"status"`
+	Level      int64                `json:"level,omitempty"`
+	Operations []handlers.Operation `json:"operations,omitempty"`
+}
+
+// MempoolChannel - pushes pending operations of address and their final status
+type MempoolChannel struct {
+	*DefaultChannel
+	Address string
+	Network string
+
+	messages chan Message
+	stop     chan struct{}
+	wg       sync.WaitGroup
+
+	mux      sync.Mutex
+	pending  map[string]*pendingOperation
+	resolved map[string]struct{}
+}
+
+type pendingOperation struct {
+	inMempool bool
+	missing   int
+}
+
+// NewMempoolChannel -
+func NewMempoolChannel(address, network string, opts ...ChannelOption) *MempoolChannel {
+	return &MempoolChannel{
+		DefaultChannel: NewDefaultChannel(opts...),
+		Address:        address,
+		Network:        network,
+
+		messages: make(chan Message, 10),
+		stop:     make(chan struct{}),
+		pending:  make(map[string]*pendingOperation),
+		resolved: make(map[string]struct{}),
+	}
+}
+
+// GetName -
+func (c *MempoolChannel) GetName() string {
+	return fmt.Sprintf("mempool_%s_%s", c.Network, c.Address)
+}
+
+// Run -
+func (c *MempoolChannel) Run() {
+	if len(c.sources) == 0 {
+		logger.Errorf("[%s] Empty source list", c.GetName())
+		return
+	}
+	if c.mempool == nil {
+		logger.Errorf("[%s] Empty mempool poller", c.GetName())
+		return
+	}
+
+	updates, err := c.mempool.Subscribe(c.Network, c.Address)
+	if err != nil {
+		logger.Errorf("[%s] %s", c.GetName(), err)
+		return
+	}
+
+	c.wg.Add(1)
+	go c.poll(updates)
+
+	for i := range c.sources {
+		c.wg.Add(1)
+		go c.listen(c.sources[i])
+	}
+}
+
+// Listen -
+func (c *MempoolChannel) Listen() <-chan Message {
+	return c.messages
+}
+
+// Stop -
+func (c *MempoolChannel) Stop() {
+	close(c.stop)
+	c.wg.Wait()
+	close(c.messages)
+}
+
+// Init -
+func (c *MempoolChannel) Init() error {
+	if _, err := c.ctx.GetTzKTService(c.Network); err != nil {
+		return errors.Errorf("Mempool is not available for %s", c.Network)
+	}
+	c.messages <- Message{
+		ChannelName: c.GetName(),
+		Body:        "ok",
+	}
+	return nil
+}
+
+func (c *MempoolChannel) poll(updates chan []tzkt.MempoolOperation) {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.stop:
+			c.mempool.Unsubscribe(c.Network, c.Address, updates)
+			return
+		case res := <-updates:
+			if err := c.checkMempool(res); err != nil {
+				logger.Error(err)
+			}
+		}
+	}
+}
+
+func (c *MempoolChannel) listen(source datasources.DataSource) {
+	defer c.wg.Done()
+
+	ch := source.Subscribe()
+	for {
+		select {
+		case <-c.stop:
+			source.Unsubscribe(ch)
+			return
+		case data := <-ch:
+			if data.Type != datasources.RabbitType {
+				continue
+			}
+
+			var err error
+			switch data.Kind {
+			case mq.QueueOperations:
+				err = c.checkOperation(string(data.Body.([]byte)))
+			case mq.QueueBlocks:
+				err = c.checkBlock(data.Body.([]byte))
+			}
+			if err != nil {
+				logger.Error(err)
+			}
+		}
+	}
+}
+
+// checkMempool - sends new pending operations and drops refused ones. Operations which are already indexed are sent with final status.
+func (c *MempoolChannel) checkMempool(res []tzkt.MempoolOperation) error {
+	groups := make(map[string][]tzkt.MempoolOperation)
+	hashes := make([]string, 0)
+	for i := range res {
+		hash := res[i].Body.Hash
+		if _, ok := groups[hash]; !ok {
+			hashes = append(hashes, hash)
+		}
+		groups[hash] = append(groups[hash], res[i])
+	}
+
+	messages := make([]MempoolBody, 0)
+	candidates := make([]string, 0)
+
+	c.mux.Lock()
+	for _, item := range c.pending {
+		item.inMempool = false
+	}
+	// mempool of TzKT can still contain operations which are already resolved
+	for hash := range c.resolved {
+		if _, ok := groups[hash]; !ok {
+			delete(c.resolved, hash)
+		}
+	}
+
+	for _, hash := range hashes {
+		if _, ok := c.resolved[hash]; ok {
+			continue
+		}
+
+		status := groups[hash][0].Body.Status
+		if status == "refused" || status == "branch_refused" {
+			if _, ok := c.pending[hash]; ok {
+				c.resolve(hash)
+				messages = append(messages, MempoolBody{Hash: hash, Status: MempoolDropped})
+			}
+			continue
+		}
+
+		if item, ok := c.pending[hash]; ok {
+			item.inMempool = true
+			item.missing = 0
+			continue
+		}
+		candidates = append(candidates, hash)
+	}
+	c.mux.Unlock()
+
+	var err error
+	for _, hash := range candidates {
+		body, indexedErr := c.indexedStatus(hash)
+		if indexedErr != nil {
+			err = indexedErr
+			continue
+		}
+		if body == nil {
+			operations := make([]handlers.Operation, 0)
+			for _, op := range c.ctx.PrepareMempoolOperations(groups[hash], c.Network) {
+				if op.Hash != "" {
+					operations = append(operations, op)
+				}
+			}
+			if len(operations) == 0 {
+				continue
+			}
+			body = &MempoolBody{
+				Hash:       hash,
+				Status:     MempoolPending,
+				Operations: operations,
+			}
+		}
+
+		c.mux.Lock()
+		_, isPending := c.pending[hash]
+		_, isResolved := c.resolved[hash]
+		if !isPending && !isResolved {
+			if body.Status == MempoolPending {
+				c.pending[hash] = &pendingOperation{inMempool: true}
+			} else {
+				c.resolve(hash)
+			}
+			messages = append(messages, *body)
+		}
+		c.mux.Unlock()
+	}
+
+	c.send(messages...)
+	return err
+}
+
+// checkOperation - sends final status of pending operation which is indexed
+func (c *MempoolChannel) checkOperation(id string) error {
+	op := operation.Operation{ID: id}
+	if err := c.ctx.Storage.GetByID(&op); err != nil {
+		return errors.Errorf("[MempoolChannel.checkOperation] Find operation error: %s", err)
+	}
+	if op.Network != c.Network {
+		return nil
+	}
+
+	c.mux.Lock()
+	_, ok := c.pending[op.Hash]
+	c.mux.Unlock()
+	if !ok {
+		return nil
+	}
+
+	body, err := c.indexedStatus(op.Hash)
+	if err != nil || body == nil {
+		return err
+	}
+
+	c.mux.Lock()
+	_, ok = c.pending[op.Hash]
+	if ok {
+		c.resolve(op.Hash)
+	}
+	c.mux.Unlock()
+
+	if ok {
+		c.send(*body)
+	}
+	return nil
+}
+
+// indexedStatus - returns final status of operation group `hash` if it's indexed and nil otherwise
+func (c *MempoolChannel) indexedStatus(hash string) (*MempoolBody, error) {
+	operations, err := c.ctx.Operations.Get(
+		map[string]interface{}{
+			"hash":    hash,
+			"network": c.Network,
+		},
+		0,
+		true,
+	)
+	if err != nil {
+		if c.ctx.Storage.IsRecordNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Errorf("[MempoolChannel.indexedStatus] Find operations error: %s", err)
+	}
+	if len(operations) == 0 {
+		return nil, nil
+	}
+
+	response, err := c.ctx.PrepareOperations(operations, true)
+	if err != nil {
+		return nil, err
+	}
+
+	status := MempoolApplied
+	for i := range operations {
+		if operations[i].Status != consts.Applied {
+			status = MempoolFailed
+			break
+		}
+	}
+
+	return &MempoolBody{
+		Hash:       hash,
+		Status:     status,
+		Level:      operations[0].Level,
+		Operations: response,
+	}, nil
+}
+
+// checkBlock - drops operations which left mempool and were not included in blocks
+func (c *MempoolChannel) checkBlock(data []byte) error {
+	var b block.Block
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	if b.Network != c.Network {
+		return nil
+	}
+
+	messages := make([]MempoolBody, 0)
+
+	c.mux.Lock()
+	for hash, item := range c.pending {
+		if item.inMempool {
+			continue
+		}
+		item.missing++
+		if item.missing < mempoolDropBlocks {
+			continue
+		}
+		c.resolve(hash)
+		messages = append(messages, MempoolBody{
+			Hash:   hash,
+			Status: MempoolDropped,
+			Level:  b.Level,
+		})
+	}
+	c.mux.Unlock()
+
+	c.send(messages...)
+	return nil
+}
+
+// resolve - must be called under lock
+func (c *MempoolChannel) resolve(hash string) {
+	delete(c.pending, hash)
+	c.resolved[hash] = struct{}{}
+}
+
+// send - must be called without lock because channel can be full
+func (c *MempoolChannel) send(bodies ...MempoolBody) {
+	for i := range bodies {
+		c.messages <- Message{
+			ChannelName: c.GetName(),
+			Body:        bodies[i],
+		}
+	}
+}
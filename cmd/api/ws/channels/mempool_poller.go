@@ -0,0 +1,118 @@
+package channels
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/baking-bad/bcdhub/cmd/api/handlers"
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/tzkt"
+)
+
+// MempoolPoller - polls mempool of TzKT once per address for all channels subscribed to it
+type MempoolPoller struct {
+	ctx    *handlers.Context
+	period time.Duration
+
+	mux   sync.Mutex
+	polls map[string]*mempoolPoll
+}
+
+type mempoolPoll struct {
+	listeners map[chan []tzkt.MempoolOperation]struct{}
+	stop      chan struct{}
+}
+
+// NewMempoolPoller -
+func NewMempoolPoller(ctx *handlers.Context) *MempoolPoller {
+	return &MempoolPoller{
+		ctx:    ctx,
+		period: mempoolPollPeriod,
+		polls:  make(map[string]*mempoolPoll),
+	}
+}
+
+// Subscribe - returns channel which receives mempool operations of `address` after every poll. Polling is started by the first subscriber.
+func (p *MempoolPoller) Subscribe(network, address string) (chan []tzkt.MempoolOperation, error) {
+	api, err := p.ctx.GetTzKTService(network)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan []tzkt.MempoolOperation, 1)
+	key := mempoolPollKey(network, address)
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	poll, ok := p.polls[key]
+	if !ok {
+		poll = &mempoolPoll{
+			listeners: make(map[chan []tzkt.MempoolOperation]struct{}),
+			stop:      make(chan struct{}),
+		}
+		p.polls[key] = poll
+		go p.poll(api, address, poll)
+	}
+	poll.listeners[ch] = struct{}{}
+	return ch, nil
+}
+
+// Unsubscribe - removes subscriber of `address`. Polling is stopped when the last subscriber leaves.
+func (p *MempoolPoller) Unsubscribe(network, address string, ch chan []tzkt.MempoolOperation) {
+	key := mempoolPollKey(network, address)
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	poll, ok := p.polls[key]
+	if !ok {
+		return
+	}
+	delete(poll.listeners, ch)
+	if len(poll.listeners) == 0 {
+		close(poll.stop)
+		delete(p.polls, key)
+	}
+}
+
+func (p *MempoolPoller) poll(api tzkt.Service, address string, poll *mempoolPoll) {
+	ticker := time.NewTicker(p.period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-poll.stop:
+			return
+		case <-ticker.C:
+			res, err := api.GetMempool(address)
+			if err != nil {
+				logger.Errorf("[MempoolPoller] %s", err)
+				continue
+			}
+
+			p.mux.Lock()
+			for ch := range poll.listeners {
+				publish(ch, res)
+			}
+			p.mux.Unlock()
+		}
+	}
+}
+
+// publish - replaces unread mempool state by the latest one, so slow subscriber doesn't block others
+func publish(ch chan []tzkt.MempoolOperation, res []tzkt.MempoolOperation) {
+	select {
+	case <-ch:
+	default:
+	}
+	select {
+	case ch <- res:
+	default:
+	}
+}
+
+func mempoolPollKey(network, address string) string {
+	return fmt.Sprintf("%s_%s", network, address)
+}
@@ -0,0 +1,244 @@
+package channels
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baking-bad/bcdhub/cmd/api/handlers"
+	"github.com/baking-bad/bcdhub/internal/config"
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/models"
+	mock_general "github.com/baking-bad/bcdhub/internal/models/mock"
+	mock_bmd "github.com/baking-bad/bcdhub/internal/models/mock/bigmapdiff"
+	mock_operation "github.com/baking-bad/bcdhub/internal/models/mock/operation"
+	mock_tzip "github.com/baking-bad/bcdhub/internal/models/mock/tzip"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/tzkt"
+	"github.com/golang/mock/gomock"
+	"github.com/karlseguin/ccache"
+)
+
+type mempoolMocks struct {
+	storage    *mock_general.MockGeneralRepository
+	operations *mock_operation.MockRepository
+}
+
+func newTestMempoolChannel(ctrl *gomock.Controller) (*MempoolChannel, mempoolMocks) {
+	mocks := mempoolMocks{
+		storage:    mock_general.NewMockGeneralRepository(ctrl),
+		operations: mock_operation.NewMockRepository(ctrl),
+	}
+	tzipRepo := mock_tzip.NewMockRepository(ctrl)
+	tzipRepo.EXPECT().GetAliasesMap("mainnet").Return(map[string]string{}, nil).AnyTimes()
+	bigMapDiffs := mock_bmd.NewMockRepository(ctrl)
+	bigMapDiffs.EXPECT().GetUniqueByOperationID(gomock.Any()).Return(nil, nil).AnyTimes()
+
+	ctx := &handlers.Context{
+		Context: &config.Context{
+			Storage:     mocks.storage,
+			Operations:  mocks.operations,
+			BigMapDiffs: bigMapDiffs,
+			TZIP:        tzipRepo,
+		},
+		Cache: ccache.New(ccache.Configure().MaxSize(10)),
+	}
+	return NewMempoolChannel("tz1sender", "mainnet", WithContext(ctx)), mocks
+}
+
+func mempoolOperation(hash, status string) tzkt.MempoolOperation {
+	return tzkt.MempoolOperation{
+		Body: tzkt.MempoolOperationBody{
+			Hash:        hash,
+			Kind:        consts.Transaction,
+			Source:      "tz1sender",
+			Destination: "tz1receiver",
+			Status:      status,
+		},
+	}
+}
+
+func expectStatus(t *testing.T, c *MempoolChannel, hash, status string) {
+	select {
+	case msg := <-c.Listen():
+		body, ok := msg.Body.(MempoolBody)
+		if !ok {
+			t.Fatalf("unexpected message: %v", msg)
+		}
+		if body.Hash != hash || body.Status != status {
+			t.Errorf("message = %s %s, want %s %s", body.Hash, body.Status, hash, status)
+		}
+	default:
+		t.Fatalf("no message with status %s of %s", status, hash)
+	}
+}
+
+func expectNoMessages(t *testing.T, c *MempoolChannel) {
+	select {
+	case msg := <-c.Listen():
+		t.Errorf("unexpected message: %v", msg)
+	default:
+	}
+}
+
+func TestMempoolChannel_Applied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, mocks := newTestMempoolChannel(ctrl)
+
+	filter := map[string]interface{}{"hash": "oo1", "network": "mainnet"}
+	gomock.InOrder(
+		mocks.operations.EXPECT().Get(filter, int64(0), true).Return([]operation.Operation{}, nil),
+		mocks.operations.EXPECT().Get(filter, int64(0), true).Return([]operation.Operation{
+			{ID: "op_id", Network: "mainnet", Hash: "oo1", Level: 100, Kind: consts.Transaction, Status: consts.Applied, Destination: "tz1receiver"},
+		}, nil),
+	)
+	mocks.storage.EXPECT().GetByID(gomock.Any()).DoAndReturn(func(model models.Model) error {
+		op := model.(*operation.Operation)
+		op.Network = "mainnet"
+		op.Hash = "oo1"
+		op.Level = 100
+		return nil
+	})
+
+	if err := c.checkMempool([]tzkt.MempoolOperation{mempoolOperation("oo1", consts.Applied)}); err != nil {
+		t.Fatalf("checkMempool() error = %v", err)
+	}
+	expectStatus(t, c, "oo1", MempoolPending)
+
+	// operation which is pending already is not checked in the index again
+	if err := c.checkMempool([]tzkt.MempoolOperation{mempoolOperation("oo1", consts.Applied)}); err != nil {
+		t.Fatalf("checkMempool() error = %v", err)
+	}
+	expectNoMessages(t, c)
+
+	if err := c.checkOperation("op_id"); err != nil {
+		t.Fatalf("checkOperation() error = %v", err)
+	}
+	expectStatus(t, c, "oo1", MempoolApplied)
+
+	// resolved operation which is still in TzKT mempool is skipped
+	if err := c.checkMempool([]tzkt.MempoolOperation{mempoolOperation("oo1", consts.Applied)}); err != nil {
+		t.Fatalf("checkMempool() error = %v", err)
+	}
+	expectNoMessages(t, c)
+}
+
+func TestMempoolChannel_AlreadyIndexed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, mocks := newTestMempoolChannel(ctrl)
+
+	mocks.operations.EXPECT().Get(map[string]interface{}{"hash": "oo1", "network": "mainnet"}, int64(0), true).Return([]operation.Operation{
+		{ID: "op_id", Network: "mainnet", Hash: "oo1", Level: 100, Kind: consts.Transaction, Status: "failed", Destination: "tz1receiver"},
+	}, nil)
+
+	if err := c.checkMempool([]tzkt.MempoolOperation{mempoolOperation("oo1", consts.Applied)}); err != nil {
+		t.Fatalf("checkMempool() error = %v", err)
+	}
+	expectStatus(t, c, "oo1", MempoolFailed)
+
+	if err := c.checkMempool([]tzkt.MempoolOperation{mempoolOperation("oo1", consts.Applied)}); err != nil {
+		t.Fatalf("checkMempool() error = %v", err)
+	}
+	expectNoMessages(t, c)
+}
+
+func TestMempoolChannel_Dropped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, mocks := newTestMempoolChannel(ctrl)
+
+	mocks.operations.EXPECT().Get(gomock.Any(), int64(0), true).Return([]operation.Operation{}, nil).Times(2)
+
+	if err := c.checkMempool([]tzkt.MempoolOperation{
+		mempoolOperation("oo1", consts.Applied),
+		mempoolOperation("oo2", consts.Applied),
+	}); err != nil {
+		t.Fatalf("checkMempool() error = %v", err)
+	}
+	expectStatus(t, c, "oo1", MempoolPending)
+	expectStatus(t, c, "oo2", MempoolPending)
+
+	if err := c.checkMempool([]tzkt.MempoolOperation{mempoolOperation("oo2", "refused")}); err != nil {
+		t.Fatalf("checkMempool() error = %v", err)
+	}
+	expectStatus(t, c, "oo2", MempoolDropped)
+
+	for i := 1; i <= mempoolDropBlocks; i++ {
+		expectNoMessages(t, c)
+		if err := c.checkBlock([]byte(`{"network":"mainnet","level":100}`)); err != nil {
+			t.Fatalf("checkBlock() error = %v", err)
+		}
+	}
+	expectStatus(t, c, "oo1", MempoolDropped)
+}
+
+type countingMempool struct {
+	mx    sync.Mutex
+	calls int
+}
+
+func (s *countingMempool) GetMempool(address string) ([]tzkt.MempoolOperation, error) {
+	s.mx.Lock()
+	s.calls++
+	s.mx.Unlock()
+	return []tzkt.MempoolOperation{mempoolOperation("oo1", consts.Applied)}, nil
+}
+
+func (s *countingMempool) getCalls() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.calls
+}
+
+func TestMempoolPoller_Shared(t *testing.T) {
+	api := new(countingMempool)
+	poller := NewMempoolPoller(&handlers.Context{
+		Context: &config.Context{
+			TzKTServices: map[string]tzkt.Service{"mainnet": api},
+		},
+	})
+	poller.period = 10 * time.Millisecond
+
+	first, err := poller.Subscribe("mainnet", "tz1sender")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	second, err := poller.Subscribe("mainnet", "tz1sender")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if _, err := poller.Subscribe("unknown", "tz1sender"); err == nil {
+		t.Errorf("Subscribe() to unknown network has to fail")
+	}
+
+	for _, ch := range []chan []tzkt.MempoolOperation{first, second} {
+		select {
+		case res := <-ch:
+			if len(res) != 1 {
+				t.Errorf("received %d operations, want 1", len(res))
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("mempool is not received")
+		}
+	}
+	if len(poller.polls) != 1 {
+		t.Errorf("%d polls are running, want 1", len(poller.polls))
+	}
+
+	poller.Unsubscribe("mainnet", "tz1sender", first)
+	poller.Unsubscribe("mainnet", "tz1sender", second)
+	if len(poller.polls) != 0 {
+		t.Errorf("%d polls are running after unsubscribe, want 0", len(poller.polls))
+	}
+
+	calls := api.getCalls()
+	time.Sleep(50 * time.Millisecond)
+	if got := api.getCalls(); got > calls+1 {
+		t.Errorf("mempool is polled after unsubscribe: %d calls, was %d", got, calls)
+	}
+}
@@ -29,6 +29,13 @@ func WithContext(ctx *handlers.Context) ChannelOption {
 	}
 }
 
+// WithMempoolPoller - sets TzKT mempool poller which is shared by mempool channels
+func WithMempoolPoller(poller *MempoolPoller) ChannelOption {
+	return func(c *DefaultChannel) {
+		c.mempool = poller
+	}
+}
+
 func getSourceByType(sources []datasources.DataSource, typ string) (datasources.DataSource, error) {
 	for i := range sources {
 		if sources[i].GetType() == typ {
@@ -0,0 +1,130 @@
+package channels
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/baking-bad/bcdhub/cmd/api/ws/datasources"
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/models/transfer"
+	"github.com/baking-bad/bcdhub/internal/mq"
+	"github.com/pkg/errors"
+)
+
+// TransfersChannel - pushes token transfers of account or token contract
+type TransfersChannel struct {
+	*DefaultChannel
+	Address string
+	Network string
+
+	messages chan Message
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewTransfersChannel -
+func NewTransfersChannel(address, network string, opts ...ChannelOption) *TransfersChannel {
+	return &TransfersChannel{
+		DefaultChannel: NewDefaultChannel(opts...),
+		Address:        address,
+		Network:        network,
+
+		messages: make(chan Message, 10),
+		stop:     make(chan struct{}),
+	}
+}
+
+// GetName -
+func (c *TransfersChannel) GetName() string {
+	return fmt.Sprintf("transfers_%s_%s", c.Network, c.Address)
+}
+
+// Run -
+func (c *TransfersChannel) Run() {
+	if len(c.sources) == 0 {
+		logger.Errorf("[%s] Empty source list", c.GetName())
+		return
+	}
+
+	for i := range c.sources {
+		c.wg.Add(1)
+		go c.listen(c.sources[i])
+	}
+}
+
+// Listen -
+func (c *TransfersChannel) Listen() <-chan Message {
+	return c.messages
+}
+
+// Stop -
+func (c *TransfersChannel) Stop() {
+	close(c.stop)
+	c.wg.Wait()
+	close(c.messages)
+}
+
+// Init -
+func (c *TransfersChannel) Init() error {
+	c.messages <- Message{
+		ChannelName: c.GetName(),
+		Body:        "ok",
+	}
+	return nil
+}
+
+func (c *TransfersChannel) listen(source datasources.DataSource) {
+	defer c.wg.Done()
+
+	ch := source.Subscribe()
+	for {
+		select {
+		case <-c.stop:
+			source.Unsubscribe(ch)
+			return
+		case data := <-ch:
+			if data.Type != datasources.RabbitType || data.Kind != mq.QueueTransfers {
+				continue
+			}
+			if err := c.createMessage(data); err != nil {
+				logger.Error(err)
+			}
+		}
+	}
+}
+
+func (c *TransfersChannel) createMessage(data datasources.Data) error {
+	msg, err := transfer.ParseQueueMessage(data.Body.([]byte))
+	if err != nil {
+		return errors.Errorf("[TransfersChannel.createMessage] Invalid message: %s", err)
+	}
+	if msg.Network != "" && !c.matches(msg.Network, msg.Contract, msg.From, msg.To) {
+		return nil
+	}
+
+	t := transfer.Transfer{ID: msg.ID}
+	if err := c.ctx.Storage.GetByID(&t); err != nil {
+		return errors.Errorf("[TransfersChannel.createMessage] Find transfer error: %s", err)
+	}
+	if !c.matches(t.Network, t.Contract, t.From, t.To) {
+		return nil
+	}
+
+	response, err := c.ctx.PrepareTransfer(t)
+	if err != nil {
+		return err
+	}
+
+	c.messages <- Message{
+		ChannelName: c.GetName(),
+		Body:        response,
+	}
+	return nil
+}
+
+func (c *TransfersChannel) matches(network, contract, from, to string) bool {
+	if network != c.Network {
+		return false
+	}
+	return contract == c.Address || from == c.Address || to == c.Address
+}
@@ -0,0 +1,93 @@
+package channels
+
+import (
+	"testing"
+
+	"github.com/baking-bad/bcdhub/cmd/api/handlers"
+	"github.com/baking-bad/bcdhub/cmd/api/ws/datasources"
+	"github.com/baking-bad/bcdhub/internal/config"
+	mock_general "github.com/baking-bad/bcdhub/internal/models/mock"
+	"github.com/baking-bad/bcdhub/internal/models/transfer"
+	"github.com/baking-bad/bcdhub/internal/mq"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+)
+
+func TestTransfersChannel_createMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		transfer transfer.Transfer
+		body     []byte
+		wantGet  bool
+	}{
+		{
+			name:     "other network",
+			transfer: transfer.Transfer{ID: "transfer", Network: "edo2net", Contract: "KT1token"},
+		}, {
+			name:     "other accounts",
+			transfer: transfer.Transfer{ID: "transfer", Network: "mainnet", Contract: "KT1other", From: "tz1from", To: "tz1to"},
+		}, {
+			name:     "subscribed contract",
+			transfer: transfer.Transfer{ID: "transfer", Network: "mainnet", Contract: "KT1token", From: "tz1from", To: "tz1to"},
+			wantGet:  true,
+		}, {
+			name:     "subscribed sender",
+			transfer: transfer.Transfer{ID: "transfer", Network: "mainnet", Contract: "KT1other", From: "KT1token", To: "tz1to"},
+			wantGet:  true,
+		}, {
+			name:     "subscribed receiver",
+			transfer: transfer.Transfer{ID: "transfer", Network: "mainnet", Contract: "KT1other", From: "tz1from", To: "KT1token"},
+			wantGet:  true,
+		}, {
+			name:    "previous format",
+			body:    []byte("transfer"),
+			wantGet: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mock_general.NewMockGeneralRepository(ctrl)
+			if tt.wantGet {
+				storage.EXPECT().GetByID(&transfer.Transfer{ID: "transfer"}).Return(errors.New("not found"))
+			}
+
+			body := tt.body
+			if body == nil {
+				body = marshalToQueue(t, &tt.transfer)
+			}
+
+			c := NewTransfersChannel("KT1token", "mainnet", WithContext(&handlers.Context{
+				Context: &config.Context{Storage: storage},
+			}))
+			err := c.createMessage(datasources.Data{Type: datasources.RabbitType, Kind: mq.QueueTransfers, Body: body})
+			if (err != nil) != tt.wantGet {
+				t.Errorf("createMessage() error = %v, wantGet %v", err, tt.wantGet)
+			}
+		})
+	}
+}
+
+func TestTransfersChannel_Stop(t *testing.T) {
+	source := newTestSource()
+	c := NewTransfersChannel("KT1token", "mainnet", WithSource([]datasources.DataSource{source}, datasources.RabbitType))
+	c.Run()
+
+	if err := c.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if msg := <-c.Listen(); msg.Body != "ok" {
+		t.Errorf("Init() message = %v", msg)
+	}
+
+	c.Stop()
+
+	if count := source.subscribers(); count != 0 {
+		t.Errorf("channel is not unsubscribed: %d subscribers", count)
+	}
+	if _, ok := <-c.Listen(); ok {
+		t.Errorf("messages channel is not closed")
+	}
+}
@@ -84,7 +84,7 @@ func (c *RabbitMQ) listenChannel(queue string) {
 
 func (c *RabbitMQ) handler(data mq.Data) error {
 	switch data.GetKey() {
-	case mq.QueueOperations, mq.QueueBlocks:
+	case mq.QueueOperations, mq.QueueBlocks, mq.QueueBigMapDiffs, mq.QueueTransfers:
 		val := Data{
 			Type: c.GetType(),
 			Kind: data.GetKey(),
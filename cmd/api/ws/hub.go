@@ -17,7 +17,8 @@ type Hub struct {
 	clients sync.Map
 	public  sync.Map
 
-	ctx *handlers.Context
+	ctx     *handlers.Context
+	mempool *channels.MempoolPoller
 
 	stop chan struct{}
 	wg   sync.WaitGroup
@@ -154,6 +155,43 @@ func createDynamicChannels(c *Client, channelName string, data *fastjson.Value)
 			channels.WithSource(c.hub.sources, datasources.RabbitType),
 			channels.WithContext(c.hub.ctx),
 		), nil
+	case "mempool":
+		address := parseString(data, "address")
+		network := parseString(data, "network")
+
+		mempoolChannelName := fmt.Sprintf("%s_%s_%s", channelName, network, address)
+		if _, ok := c.subscriptions.Load(mempoolChannelName); ok {
+			return nil, nil
+		}
+		return channels.NewMempoolChannel(address, network,
+			channels.WithSource(c.hub.sources, datasources.RabbitType),
+			channels.WithContext(c.hub.ctx),
+			channels.WithMempoolPoller(c.hub.mempool),
+		), nil
+	case "bigmap":
+		network := parseString(data, "network")
+		ptr := data.GetInt64("ptr")
+		keyHash := parseString(data, "key_hash")
+
+		if _, ok := c.subscriptions.Load(channels.BigMapChannelName(network, ptr, keyHash)); ok {
+			return nil, nil
+		}
+		return channels.NewBigMapChannel(network, ptr, keyHash,
+			channels.WithSource(c.hub.sources, datasources.RabbitType),
+			channels.WithContext(c.hub.ctx),
+		), nil
+	case "transfers":
+		address := parseString(data, "address")
+		network := parseString(data, "network")
+
+		transfersChannelName := fmt.Sprintf("%s_%s_%s", channelName, network, address)
+		if _, ok := c.subscriptions.Load(transfersChannelName); ok {
+			return nil, nil
+		}
+		return channels.NewTransfersChannel(address, network,
+			channels.WithSource(c.hub.sources, datasources.RabbitType),
+			channels.WithContext(c.hub.ctx),
+		), nil
 	default:
 		return nil, errors.Errorf("Unknown channel: %s", channelName)
 	}
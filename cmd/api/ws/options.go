@@ -2,6 +2,7 @@ package ws
 
 import (
 	"github.com/baking-bad/bcdhub/cmd/api/handlers"
+	"github.com/baking-bad/bcdhub/cmd/api/ws/channels"
 	"github.com/baking-bad/bcdhub/cmd/api/ws/datasources"
 	"github.com/baking-bad/bcdhub/internal/logger"
 	"github.com/baking-bad/bcdhub/internal/mq"
@@ -33,5 +34,6 @@ func WithRabbitSource(messageQueue mq.Mediator) HubOption {
 func WithContext(ctx *handlers.Context) HubOption {
 	return func(h *Hub) {
 		h.ctx = ctx
+		h.mempool = channels.NewMempoolPoller(ctx)
 	}
 }
@@ -5,8 +5,10 @@ import (
 
 	"github.com/baking-bad/bcdhub/internal/compiler/compilation"
 	"github.com/baking-bad/bcdhub/internal/compiler/compilers"
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
 	"github.com/baking-bad/bcdhub/internal/database"
 	"github.com/jinzhu/gorm/dialects/postgres"
+	"github.com/tidwall/gjson"
 )
 
 func compile(task compilation.Task) []database.CompilationTaskResult {
@@ -31,6 +33,10 @@ func compile(task compilation.Task) []database.CompilationTaskResult {
 
 		data, err := compilers.BuildFromFile(filepath)
 
+		if err == nil && task.Kind == compilation.KindDeployment {
+			_, err = typechecker.Check(gjson.Parse(data.Script))
+		}
+
 		if err != nil {
 			taskResult.Error = err.Error()
 		} else {
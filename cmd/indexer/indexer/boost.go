@@ -9,20 +9,6 @@ import (
 	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
 	"github.com/baking-bad/bcdhub/internal/contractparser/kinds"
 	"github.com/baking-bad/bcdhub/internal/contractparser/meta"
-	elasticBalanceUpdate "github.com/baking-bad/bcdhub/internal/elastic/balanceupdate"
-	elasticBigMapAction "github.com/baking-bad/bcdhub/internal/elastic/bigmapaction"
-	elasticBigMapDiff "github.com/baking-bad/bcdhub/internal/elastic/bigmapdiff"
-	elasticBlock "github.com/baking-bad/bcdhub/internal/elastic/block"
-	elasticContract "github.com/baking-bad/bcdhub/internal/elastic/contract"
-	"github.com/baking-bad/bcdhub/internal/elastic/core"
-	elasticMigration "github.com/baking-bad/bcdhub/internal/elastic/migration"
-	elasticOperation "github.com/baking-bad/bcdhub/internal/elastic/operation"
-	elasticProtocol "github.com/baking-bad/bcdhub/internal/elastic/protocol"
-	elasticSchema "github.com/baking-bad/bcdhub/internal/elastic/schema"
-	elasticTezosDomain "github.com/baking-bad/bcdhub/internal/elastic/tezosdomain"
-	elasticTokenBalance "github.com/baking-bad/bcdhub/internal/elastic/tokenbalance"
-	elasticTransfer "github.com/baking-bad/bcdhub/internal/elastic/transfer"
-	elasticTZIP "github.com/baking-bad/bcdhub/internal/elastic/tzip"
 	"github.com/baking-bad/bcdhub/internal/helpers"
 	"github.com/baking-bad/bcdhub/internal/index"
 	"github.com/baking-bad/bcdhub/internal/logger"
@@ -34,18 +20,23 @@ import (
 	"github.com/baking-bad/bcdhub/internal/models/contract"
 	"github.com/baking-bad/bcdhub/internal/models/migration"
 	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/models/outbox"
 	"github.com/baking-bad/bcdhub/internal/models/protocol"
 	"github.com/baking-bad/bcdhub/internal/models/schema"
 	"github.com/baking-bad/bcdhub/internal/models/tezosdomain"
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
+	"github.com/baking-bad/bcdhub/internal/models/ticketupdate"
 	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
 	"github.com/baking-bad/bcdhub/internal/models/transfer"
 	"github.com/baking-bad/bcdhub/internal/models/tzip"
+	"github.com/baking-bad/bcdhub/internal/models/tzipversion"
 	"github.com/baking-bad/bcdhub/internal/mq"
 	"github.com/baking-bad/bcdhub/internal/noderpc"
 	"github.com/baking-bad/bcdhub/internal/parsers"
 	"github.com/baking-bad/bcdhub/internal/parsers/operations"
 	"github.com/baking-bad/bcdhub/internal/rollback"
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
 )
 
 var errBcdQuit = errors.New("bcd-quit")
@@ -65,13 +56,16 @@ type BoostIndexer struct {
 	Protocols      protocol.Repository
 	Schema         schema.Repository
 	TezosDomains   tezosdomain.Repository
+	TicketBalances ticketbalance.Repository
+	TicketUpdates  ticketupdate.Repository
 	TokenBalances  tokenbalance.Repository
 	Transfers      transfer.Repository
 	TZIP           tzip.Repository
+	TZIPVersions   tzipversion.Repository
 
 	rpc             noderpc.INode
 	externalIndexer index.Indexer
-	interfaces      map[string]kinds.ContractKind
+	interfaces      *kinds.Registry
 	messageQueue    mq.Mediator
 	state           block.Block
 	currentProtocol protocol.Protocol
@@ -80,8 +74,10 @@ type BoostIndexer struct {
 	updateTicker        *time.Ticker
 	stop                chan struct{}
 	Network             string
+	prefetchDepth       int
 	boost               bool
 	skipDelegatorBlocks bool
+	monitorHeads        bool
 	stopped             bool
 }
 
@@ -140,7 +136,12 @@ func (bi *BoostIndexer) fetchExternalProtocols() error {
 // NewBoostIndexer -
 func NewBoostIndexer(cfg config.Config, network string, opts ...BoostIndexerOption) (*BoostIndexer, error) {
 	logger.WithNetwork(network).Info("Creating indexer object...")
-	es := core.WaitNew(cfg.Storage.URI, cfg.Storage.Timeout)
+	ctx := config.NewContext(
+		config.WithStorage(cfg.Storage),
+		config.WithShare(cfg.SharePath),
+		config.WithContractsInterfaces(),
+		config.WithContractsInterfacesReload(cfg.Metrics.ContractInterfaces.Period(config.DefaultInterfacesPollPeriod)),
+	)
 
 	rpcProvider, ok := cfg.RPC[network]
 	if !ok {
@@ -153,31 +154,30 @@ func NewBoostIndexer(cfg config.Config, network string, opts ...BoostIndexerOpti
 
 	messageQueue := mq.New(cfg.RabbitMQ.URI, cfg.Indexer.ProjectName, cfg.Indexer.MQ.NeedPublisher, 10)
 
-	interfaces, err := kinds.Load()
-	if err != nil {
-		return nil, err
-	}
-
 	bi := &BoostIndexer{
-		Storage:        es,
-		BalanceUpdates: elasticBalanceUpdate.NewStorage(es),
-		BigMapActions:  elasticBigMapAction.NewStorage(es),
-		BigMapDiffs:    elasticBigMapDiff.NewStorage(es),
-		Blocks:         elasticBlock.NewStorage(es),
-		Contracts:      elasticContract.NewStorage(es),
-		Migrations:     elasticMigration.NewStorage(es),
-		Operations:     elasticOperation.NewStorage(es),
-		Protocols:      elasticProtocol.NewStorage(es),
-		Schema:         elasticSchema.NewStorage(es),
-		TezosDomains:   elasticTezosDomain.NewStorage(es),
-		TokenBalances:  elasticTokenBalance.NewStorage(es),
-		Transfers:      elasticTransfer.NewStorage(es),
-		TZIP:           elasticTZIP.NewStorage(es),
+		Storage:        ctx.Storage,
+		BalanceUpdates: ctx.BalanceUpdates,
+		BigMapActions:  ctx.BigMapActions,
+		BigMapDiffs:    ctx.BigMapDiffs,
+		Blocks:         ctx.Blocks,
+		Contracts:      ctx.Contracts,
+		Migrations:     ctx.Migrations,
+		Operations:     ctx.Operations,
+		Protocols:      ctx.Protocols,
+		Schema:         ctx.Schema,
+		TezosDomains:   ctx.TezosDomains,
+		TicketBalances: ctx.TicketBalances,
+		TicketUpdates:  ctx.TicketUpdates,
+		TokenBalances:  ctx.TokenBalances,
+		Transfers:      ctx.Transfers,
+		TZIP:           ctx.TZIP,
+		TZIPVersions:   ctx.TZIPVersions,
 		Network:        network,
 		rpc:            rpc,
 		messageQueue:   messageQueue,
 		stop:           make(chan struct{}),
-		interfaces:     interfaces,
+		prefetchDepth:  defaultPrefetchDepth,
+		interfaces:     ctx.Interfaces,
 		cfg:            cfg,
 	}
 
@@ -185,7 +185,7 @@ func NewBoostIndexer(cfg config.Config, network string, opts ...BoostIndexerOpti
 		opt(bi)
 	}
 
-	err = bi.init()
+	err := bi.init()
 	return bi, err
 }
 
@@ -194,6 +194,14 @@ func (bi *BoostIndexer) init() error {
 		return err
 	}
 
+	if err := bi.publishPendingMessages(); err != nil {
+		return err
+	}
+
+	if _, err := bi.rollbackManager().Resume(bi.Network); err != nil {
+		return err
+	}
+
 	if bi.boost {
 		if err := bi.fetchExternalProtocols(); err != nil {
 			return err
@@ -249,11 +257,14 @@ func (bi *BoostIndexer) Sync(wg *sync.WaitGroup) {
 		return
 	}
 
-	everySecond := false
-	duration := time.Duration(bi.currentProtocol.Constants.TimeBetweenBlocks) * time.Second
-	if duration.Microseconds() <= 0 {
-		duration = 10 * time.Second
+	var heads <-chan noderpc.Header
+	headsStop := make(chan struct{})
+	defer close(headsStop)
+	if bi.monitorHeads {
+		heads = bi.subscribeHeads(headsStop)
 	}
+
+	everySecond := false
 	bi.setUpdateTicker(0)
 	for {
 		select {
@@ -261,8 +272,36 @@ func (bi *BoostIndexer) Sync(wg *sync.WaitGroup) {
 			bi.stopped = true
 			bi.messageQueue.Close()
 			return
+		case _, ok := <-heads:
+			if !ok {
+				logger.WithNetwork(bi.Network).Warning("Heads stream is closed. Falling back to polling")
+				heads = nil
+				continue
+			}
+			if err := bi.process(); err != nil && !errors.Is(err, errSameLevel) {
+				logger.Error(err)
+				helpers.CatchErrorSentry(err)
+			}
 		case <-bi.updateTicker.C:
-			if err := bi.process(); err != nil {
+			if bi.monitorHeads && heads == nil {
+				heads = bi.subscribeHeads(headsStop)
+			}
+
+			err := bi.process()
+			if heads != nil {
+				// ticker is only a safety net while heads are streamed
+				if err != nil && !errors.Is(err, errSameLevel) {
+					logger.Error(err)
+					helpers.CatchErrorSentry(err)
+				}
+				if everySecond {
+					everySecond = false
+					bi.setUpdateTicker(0)
+				}
+				continue
+			}
+
+			if err != nil {
 				if errors.Is(err, errSameLevel) {
 					if !everySecond {
 						everySecond = true
@@ -282,6 +321,17 @@ func (bi *BoostIndexer) Sync(wg *sync.WaitGroup) {
 	}
 }
 
+// subscribeHeads - returns heads stream of node or nil if node doesn't stream heads
+func (bi *BoostIndexer) subscribeHeads(stop <-chan struct{}) <-chan noderpc.Header {
+	heads, err := bi.rpc.MonitorHeads(stop)
+	if err != nil {
+		logger.WithNetwork(bi.Network).Warningf("Heads stream is unavailable: %s. Polling is used", err)
+		return nil
+	}
+	logger.WithNetwork(bi.Network).Info("Indexer is driven by heads stream")
+	return heads
+}
+
 func (bi *BoostIndexer) setUpdateTicker(seconds int) {
 	if bi.updateTicker != nil {
 		bi.updateTicker.Stop()
@@ -311,27 +361,34 @@ func (bi *BoostIndexer) Index(levels []int64) error {
 	}
 	helpers.SetTagSentry("network", bi.Network)
 
-	for _, level := range levels {
-		helpers.SetTagSentry("block", fmt.Sprintf("%d", level))
+	blocks := newPrefetcher(bi.rpc, bi.prefetchDepth)
+	blocks.Start(levels)
+	defer blocks.Stop()
 
-		select {
-		case <-bi.stop:
-			bi.stopped = true
-			bi.messageQueue.Close()
-			return errBcdQuit
-		default:
-		}
-
-		currentHead, err := bi.rpc.GetHeader(level)
+	for {
+		prefetched, err := blocks.Next(bi.stop)
 		if err != nil {
+			if errors.Is(err, errBcdQuit) {
+				bi.stopped = true
+				bi.messageQueue.Close()
+			}
 			return err
 		}
+		if prefetched == nil {
+			return nil
+		}
+		helpers.SetTagSentry("block", fmt.Sprintf("%d", prefetched.Level))
+
+		if prefetched.Err != nil {
+			return prefetched.Err
+		}
+		currentHead := prefetched.Header
 
 		if bi.state.Level > 0 && currentHead.Predecessor != bi.state.Hash && !bi.boost {
 			return errRollback
 		}
 
-		logger.WithNetwork(bi.Network).Infof("indexing %d block", level)
+		logger.WithNetwork(bi.Network).Infof("indexing %d block", prefetched.Level)
 
 		if currentHead.Protocol != bi.currentProtocol.Hash {
 			logger.WithNetwork(bi.Network).Infof("New protocol detected: %s -> %s", bi.currentProtocol.Hash, currentHead.Protocol)
@@ -339,22 +396,21 @@ func (bi *BoostIndexer) Index(levels []int64) error {
 			if err != nil {
 				return err
 			}
-			if err := bi.saveModels(migrationModels); err != nil {
+			if err := bi.saveModels(currentHead.Level, migrationModels); err != nil {
 				return err
 			}
 		}
 
-		parsedModels, err := bi.getDataFromBlock(bi.Network, currentHead)
+		parsedModels, err := bi.getDataFromBlock(bi.Network, currentHead, prefetched.Operations)
 		if err != nil {
 			return err
 		}
 		parsedModels = append(parsedModels, bi.createBlock(currentHead))
 
-		if err := bi.saveModels(parsedModels); err != nil {
+		if err := bi.saveModels(currentHead.Level, parsedModels); err != nil {
 			return err
 		}
 	}
-	return nil
 }
 
 // Rollback -
@@ -366,8 +422,7 @@ func (bi *BoostIndexer) Rollback() error {
 		return err
 	}
 
-	manager := rollback.NewManager(bi.Storage, bi.Contracts, bi.Operations, bi.Transfers, bi.TokenBalances, bi.Protocols, bi.messageQueue, bi.rpc, bi.cfg.SharePath)
-	if err := manager.Rollback(bi.state, lastLevel); err != nil {
+	if err := bi.rollbackManager().Rollback(bi.state, lastLevel); err != nil {
 		return err
 	}
 
@@ -383,6 +438,10 @@ func (bi *BoostIndexer) Rollback() error {
 	return nil
 }
 
+func (bi *BoostIndexer) rollbackManager() rollback.Manager {
+	return rollback.NewManager(bi.Storage, bi.Blocks, bi.Contracts, bi.Operations, bi.Transfers, bi.TicketUpdates, bi.Protocols, bi.TZIPVersions, bi.messageQueue, bi.rpc, bi.cfg.SharePath)
+}
+
 func (bi *BoostIndexer) getLastRollbackBlock() (int64, error) {
 	var lastLevel int64
 	level := bi.state.Level
@@ -502,28 +561,74 @@ func (bi *BoostIndexer) createBlock(head noderpc.Header) *block.Block {
 	return &newBlock
 }
 
-func (bi *BoostIndexer) saveModels(items []models.Model) error {
+// saveModels - saves `items` together with their queue messages. Messages are removed from outbox only after publishing,
+// so they are delivered at least once even if indexer stops between saving and publishing.
+func (bi *BoostIndexer) saveModels(level int64, items []models.Model) error {
 	logger.WithNetwork(bi.Network).Debugf("Found %d new models", len(items))
-	if err := bi.Storage.BulkInsert(items); err != nil {
+
+	messages := make([]*outbox.Message, 0)
+	for i := range items {
+		itemMessages, err := outbox.New(bi.Network, level, items[i])
+		if err != nil {
+			return err
+		}
+		messages = append(messages, itemMessages...)
+	}
+
+	data := make([]models.Model, 0, len(items)+len(messages))
+	data = append(data, items...)
+	for i := range messages {
+		data = append(data, messages[i])
+	}
+	if err := bi.Storage.BulkInsert(data); err != nil {
 		return err
 	}
 
-	for i := range items {
-		if err := bi.messageQueue.Send(items[i]); err != nil {
+	return bi.publishMessages(messages)
+}
+
+func (bi *BoostIndexer) publishPendingMessages() error {
+	var pending []outbox.Message
+	if err := bi.Storage.GetByNetwork(bi.Network, &pending); err != nil {
+		if bi.Storage.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	logger.WithNetwork(bi.Network).Infof("Publishing %d pending messages", len(pending))
+	messages := make([]*outbox.Message, len(pending))
+	for i := range pending {
+		messages[i] = &pending[i]
+	}
+	return bi.publishMessages(messages)
+}
+
+func (bi *BoostIndexer) publishMessages(messages []*outbox.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	published := make([]models.Model, 0, len(messages))
+	for i := range messages {
+		if err := bi.messageQueue.SendRaw(messages[i].Queue, []byte(messages[i].Body)); err != nil {
+			if deleteErr := bi.Storage.BulkDelete(published); deleteErr != nil {
+				logger.Error(deleteErr)
+			}
 			return err
 		}
+		published = append(published, messages[i])
 	}
-	return nil
+	return bi.Storage.BulkDelete(published)
 }
 
-func (bi *BoostIndexer) getDataFromBlock(network string, head noderpc.Header) ([]models.Model, error) {
+func (bi *BoostIndexer) getDataFromBlock(network string, head noderpc.Header, data gjson.Result) ([]models.Model, error) {
 	if head.Level <= 1 {
 		return nil, nil
 	}
-	data, err := bi.rpc.GetOperations(head.Level)
-	if err != nil {
-		return nil, err
-	}
 
 	parsedModels := make([]models.Model, 0)
 	for _, opg := range data.Array() {
@@ -533,9 +638,11 @@ func (bi *BoostIndexer) getDataFromBlock(network string, head noderpc.Header) ([
 			operations.WithConstants(bi.currentProtocol.Constants),
 			operations.WithHead(head),
 			operations.WithIPFSGateways(bi.cfg.IPFSGateways),
-			operations.WithInterfaces(bi.interfaces),
+			operations.WithInterfaces(bi.interfaces.All()),
 			operations.WithShareDirectory(bi.cfg.SharePath),
 			operations.WithNetwork(network),
+			operations.WithTicketBalances(bi.TicketBalances),
+			operations.WithContracts(bi.Contracts),
 		))
 		parsed, err := parser.Parse(opg)
 		if err != nil {
@@ -644,7 +751,7 @@ func (bi *BoostIndexer) vestingMigration(head noderpc.Header) ([]models.Model, e
 		return nil, err
 	}
 
-	p := parsers.NewVestingParser(bi.Storage, bi.cfg.SharePath, bi.interfaces)
+	p := parsers.NewVestingParser(bi.Storage, bi.cfg.SharePath, bi.interfaces.All())
 
 	parsedModels := make([]models.Model, 0)
 	for _, address := range addresses {
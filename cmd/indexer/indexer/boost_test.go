@@ -0,0 +1,107 @@
+package indexer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	mock_general "github.com/baking-bad/bcdhub/internal/models/mock"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/models/outbox"
+	"github.com/baking-bad/bcdhub/internal/mq"
+	"github.com/golang/mock/gomock"
+)
+
+func TestBoostIndexer_saveModels(t *testing.T) {
+	errPublish := errors.New("publish")
+	tests := []struct {
+		name        string
+		items       []models.Model
+		failOn      string
+		wantInsert  int
+		wantDeleted []string
+		wantErr     bool
+	}{
+		{
+			name: "all messages are published",
+			items: []models.Model{
+				&operation.Operation{ID: "op1"},
+				&operation.Operation{ID: "op2"},
+			},
+			wantInsert:  4,
+			wantDeleted: []string{"op1", "op2"},
+		}, {
+			name: "publishing failed",
+			items: []models.Model{
+				&operation.Operation{ID: "op1"},
+				&operation.Operation{ID: "op2"},
+				&operation.Operation{ID: "op3"},
+			},
+			failOn:      "op2",
+			wantInsert:  6,
+			wantDeleted: []string{"op1"},
+			wantErr:     true,
+		}, {
+			name: "models without queues",
+			items: []models.Model{
+				&outbox.Message{ID: "message"},
+			},
+			wantInsert: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			storage := mock_general.NewMockGeneralRepository(ctrl)
+			messageQueue := mq.NewMockMediator(ctrl)
+
+			storage.EXPECT().BulkInsert(gomock.Any()).DoAndReturn(
+				func(items []models.Model) error {
+					if len(items) != tt.wantInsert {
+						t.Errorf("BulkInsert() count = %d, want %d", len(items), tt.wantInsert)
+					}
+					return nil
+				},
+			).Times(1)
+
+			messageQueue.EXPECT().SendRaw(mq.QueueOperations, gomock.Any()).DoAndReturn(
+				func(queue string, body []byte) error {
+					if string(body) == tt.failOn {
+						return errPublish
+					}
+					return nil
+				},
+			).AnyTimes()
+
+			storage.EXPECT().BulkDelete(gomock.Any()).DoAndReturn(
+				func(items []models.Model) error {
+					if len(items) != len(tt.wantDeleted) {
+						t.Errorf("BulkDelete() count = %d, want %d", len(items), len(tt.wantDeleted))
+						return nil
+					}
+					for i := range items {
+						msg, ok := items[i].(*outbox.Message)
+						if !ok {
+							t.Errorf("BulkDelete() unexpected model %T", items[i])
+							continue
+						}
+						if msg.Body != tt.wantDeleted[i] {
+							t.Errorf("BulkDelete() body = %s, want %s", msg.Body, tt.wantDeleted[i])
+						}
+					}
+					return nil
+				},
+			).MaxTimes(1)
+
+			bi := &BoostIndexer{
+				Storage:      storage,
+				Network:      "mainnet",
+				messageQueue: messageQueue,
+			}
+			if err := bi.saveModels(100, tt.items); (err != nil) != tt.wantErr {
+				t.Errorf("saveModels() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -20,6 +20,12 @@ func CreateIndexers(cfg config.Config) ([]Indexer, error) {
 		if cfg.Indexer.SkipDelegatorBlocks {
 			boostOptions = append(boostOptions, WithSkipDelegatorBlocks())
 		}
+		if cfg.Indexer.PrefetchDepth > 0 {
+			boostOptions = append(boostOptions, WithPrefetchDepth(cfg.Indexer.PrefetchDepth))
+		}
+		if cfg.Indexer.MonitorHeads {
+			boostOptions = append(boostOptions, WithMonitorHeads())
+		}
 		bi, err := NewBoostIndexer(cfg, network, boostOptions...)
 		if err != nil {
 			return nil, err
@@ -35,3 +35,19 @@ func WithSkipDelegatorBlocks() BoostIndexerOption {
 		bi.skipDelegatorBlocks = true
 	}
 }
+
+// WithPrefetchDepth -
+func WithPrefetchDepth(depth int) BoostIndexerOption {
+	return func(bi *BoostIndexer) {
+		if depth > 0 {
+			bi.prefetchDepth = depth
+		}
+	}
+}
+
+// WithMonitorHeads - indexer is woken up by heads stream of node instead of polling. Polling is used while stream is unavailable.
+func WithMonitorHeads() BoostIndexerOption {
+	return func(bi *BoostIndexer) {
+		bi.monitorHeads = true
+	}
+}
@@ -0,0 +1,106 @@
+package indexer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/baking-bad/bcdhub/internal/noderpc"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	defaultPrefetchDepth = 10
+	minPrefetchDepth     = 1
+)
+
+type prefetchedBlock struct {
+	Level      int64
+	Header     noderpc.Header
+	Operations gjson.Result
+	Err        error
+}
+
+// prefetcher - downloads headers and operations of the upcoming levels concurrently. Results are returned strictly in the order of levels.
+type prefetcher struct {
+	rpc   noderpc.INode
+	depth int
+
+	results chan chan prefetchedBlock
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func newPrefetcher(rpc noderpc.INode, depth int) *prefetcher {
+	if depth < minPrefetchDepth {
+		depth = defaultPrefetchDepth
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &prefetcher{
+		rpc:     rpc,
+		depth:   depth,
+		results: make(chan chan prefetchedBlock, depth),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start - starts downloading `levels`. Blocks are received by `Next`
+func (p *prefetcher) Start(levels []int64) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(p.results)
+
+		for _, level := range levels {
+			result := make(chan prefetchedBlock, 1)
+			select {
+			case <-p.ctx.Done():
+				return
+			case p.results <- result:
+			}
+
+			p.wg.Add(1)
+			go p.fetch(level, result)
+		}
+	}()
+}
+
+// Next - returns next block in the order of levels. Returns nil if there are no more blocks and `errBcdQuit` if `stop` was received while waiting.
+func (p *prefetcher) Next(stop <-chan struct{}) (*prefetchedBlock, error) {
+	select {
+	case <-stop:
+		return nil, errBcdQuit
+	case <-p.ctx.Done():
+		return nil, nil
+	case result, ok := <-p.results:
+		if !ok {
+			return nil, nil
+		}
+		select {
+		case <-stop:
+			return nil, errBcdQuit
+		case <-p.ctx.Done():
+			return nil, nil
+		case block := <-result:
+			return &block, nil
+		}
+	}
+}
+
+// Stop - cancels all pending and in-flight downloads and waits until workers are finished
+func (p *prefetcher) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *prefetcher) fetch(level int64, result chan<- prefetchedBlock) {
+	defer p.wg.Done()
+
+	block := prefetchedBlock{Level: level}
+	block.Header, block.Err = p.rpc.GetHeaderWithContext(p.ctx, level)
+	if block.Err == nil && level > 1 {
+		block.Operations, block.Err = p.rpc.GetOperationsWithContext(p.ctx, level)
+	}
+	result <- block
+}
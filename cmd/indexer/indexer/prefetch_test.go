@@ -0,0 +1,136 @@
+package indexer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/noderpc"
+	"github.com/golang/mock/gomock"
+	"github.com/tidwall/gjson"
+)
+
+func TestPrefetcher_Order(t *testing.T) {
+	tests := []struct {
+		name   string
+		depth  int
+		levels []int64
+	}{
+		{
+			name:   "depth 1",
+			depth:  1,
+			levels: []int64{1, 2, 3, 4, 5},
+		}, {
+			name:   "depth greater than levels count",
+			depth:  10,
+			levels: []int64{100, 101, 105},
+		}, {
+			name:   "depth 3",
+			depth:  3,
+			levels: []int64{10, 20, 30, 40, 50, 60, 70},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrlRPC := gomock.NewController(t)
+			defer ctrlRPC.Finish()
+			rpc := noderpc.NewMockINode(ctrlRPC)
+
+			rpc.EXPECT().GetHeaderWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, level int64) (noderpc.Header, error) {
+					// the earlier level is requested the later it is returned
+					time.Sleep(time.Duration(200-level) * time.Microsecond)
+					return noderpc.Header{Level: level}, nil
+				},
+			).Times(len(tt.levels))
+			rpc.EXPECT().GetOperationsWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, level int64) (gjson.Result, error) {
+					return gjson.Parse(`[]`), nil
+				},
+			).AnyTimes()
+
+			p := newPrefetcher(rpc, tt.depth)
+			p.Start(tt.levels)
+			defer p.Stop()
+
+			for _, level := range tt.levels {
+				block, err := p.Next(nil)
+				if err != nil {
+					t.Errorf("Next() error = %v", err)
+					return
+				}
+				if block == nil {
+					t.Errorf("Next() unexpected end of blocks on level %d", level)
+					return
+				}
+				if block.Level != level || block.Header.Level != level {
+					t.Errorf("Next() level = %d, want %d", block.Level, level)
+					return
+				}
+			}
+
+			block, err := p.Next(nil)
+			if err != nil {
+				t.Errorf("Next() error = %v", err)
+				return
+			}
+			if block != nil {
+				t.Errorf("Next() unexpected block %d", block.Level)
+			}
+		})
+	}
+}
+
+func TestPrefetcher_Stop(t *testing.T) {
+	ctrlRPC := gomock.NewController(t)
+	defer ctrlRPC.Finish()
+	rpc := noderpc.NewMockINode(ctrlRPC)
+
+	// requests of levels after the first one hang until they are cancelled
+	rpc.EXPECT().GetHeaderWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, level int64) (noderpc.Header, error) {
+			if level > 2 {
+				<-ctx.Done()
+				return noderpc.Header{}, ctx.Err()
+			}
+			return noderpc.Header{Level: level}, nil
+		},
+	).AnyTimes()
+	rpc.EXPECT().GetOperationsWithContext(gomock.Any(), gomock.Any()).Return(gjson.Parse(`[]`), nil).AnyTimes()
+
+	levels := make([]int64, 1000)
+	for i := range levels {
+		levels[i] = int64(i + 2)
+	}
+
+	p := newPrefetcher(rpc, 5)
+	p.Start(levels)
+
+	stop := make(chan struct{}, 1)
+	block, err := p.Next(stop)
+	if err != nil {
+		t.Errorf("Next() error = %v", err)
+		return
+	}
+	if block == nil || block.Level != 2 {
+		t.Errorf("Next() unexpected block %v", block)
+		return
+	}
+
+	stop <- struct{}{}
+	if _, err := p.Next(stop); err != errBcdQuit {
+		t.Errorf("Next() error = %v, want %v", err, errBcdQuit)
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("Stop() doesn't cancel in-flight requests")
+	}
+}
@@ -1,9 +1,17 @@
 package main
 
-import "strings"
+import (
+	"strings"
 
+	"github.com/tidwall/gjson"
+)
+
+// parseID - returns document ID of queue message. Body is either ID or JSON object with `id` field.
 func parseID(data []byte) string {
 	id := string(data)
+	if strings.HasPrefix(id, "{") {
+		return gjson.Get(id, "id").String()
+	}
 	if strings.HasPrefix(id, `"`) && strings.HasSuffix(id, `"`) {
 		id = strings.TrimPrefix(id, `"`)
 		id = strings.TrimSuffix(id, `"`)
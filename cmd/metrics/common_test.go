@@ -24,6 +24,10 @@ func Test_parseID(t *testing.T) {
 			name: "Empty quoted string",
 			data: []byte(`""`),
 			want: "",
+		}, {
+			name: "Object",
+			data: []byte(`{"id":"test","network":"mainnet","ptr":1}`),
+			want: "test",
 		},
 	}
 	for _, tt := range tests {
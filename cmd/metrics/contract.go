@@ -42,7 +42,7 @@ func parseContract(contract *contract.Contract) error {
 	if err != nil {
 		return err
 	}
-	if err = h.CreateTokenMetadata(rpc, ctx.SharePath, contract, ctx.Config.IPFSGateways...); err != nil {
+	if err = h.CreateTokenMetadata(rpc, ctx.SharePath, contract, ctx.IPFS); err != nil {
 		if !errors.Is(err, tokens.ErrNoMetadataKeyInStorage) {
 			logger.Error(err)
 		}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/baking-bad/bcdhub/internal/config"
+	contractHandlers "github.com/baking-bad/bcdhub/internal/handlers"
+)
+
+func newContractInterfacesHandler(ctx *config.Context) *contractHandlers.ContractInterfaces {
+	networks := make([]string, 0, len(ctx.RPC))
+	for network := range ctx.RPC {
+		networks = append(networks, network)
+	}
+	sort.Strings(networks)
+
+	return contractHandlers.NewContractInterfaces(ctx.Storage, ctx.Contracts, ctx.Schema, ctx.InterfacesDirectory(), networks)
+}
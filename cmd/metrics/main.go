@@ -9,9 +9,11 @@ import (
 	"time"
 
 	"github.com/baking-bad/bcdhub/internal/config"
+	contractHandlers "github.com/baking-bad/bcdhub/internal/handlers"
 	"github.com/baking-bad/bcdhub/internal/helpers"
 	"github.com/baking-bad/bcdhub/internal/logger"
 	"github.com/baking-bad/bcdhub/internal/mq"
+	"github.com/baking-bad/bcdhub/internal/webhook"
 	"github.com/karlseguin/ccache"
 	"github.com/pkg/errors"
 )
@@ -20,6 +22,9 @@ import (
 type Context struct {
 	Cache               *ccache.Cache
 	AliasesCacheSeconds time.Duration
+	Webhooks            *webhook.Sender
+	Metadata            *contractHandlers.TZIP
+	Interfaces          *contractHandlers.ContractInterfaces
 	*config.Context
 }
 
@@ -95,6 +100,7 @@ func main() {
 		config.WithRabbit(cfg.RabbitMQ, cfg.Metrics.ProjectName, cfg.Metrics.MQ),
 		config.WithShare(cfg.SharePath),
 		config.WithDomains(cfg.Domains),
+		config.WithIPFS(cfg.IPFSGateways, cfg.IPFSResolver),
 		config.WithConfigCopy(cfg),
 	)
 	defer configCtx.Close()
@@ -102,12 +108,16 @@ func main() {
 	ctx = Context{
 		Cache:               ccache.New(ccache.Configure().MaxSize(10)),
 		AliasesCacheSeconds: time.Second * time.Duration(configCtx.Config.Metrics.CacheAliasesSeconds),
+		Webhooks:            newWebhookSender(configCtx),
+		Metadata:            newMetadataHandler(configCtx),
+		Interfaces:          newContractInterfacesHandler(configCtx),
 		Context:             configCtx,
 	}
 
 	var wg sync.WaitGroup
 
 	closeChan := make(chan struct{})
+	stopWorkers := make(chan struct{})
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 
@@ -116,11 +126,30 @@ func main() {
 		defer wg.Done()
 
 		<-signals
+		close(stopWorkers)
 		for range ctx.MQ.GetQueues() {
 			closeChan <- struct{}{}
 		}
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ctx.Webhooks.Run(webhooksPollPeriod(configCtx.Config.Metrics.Webhooks), stopWorkers)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ctx.Metadata.RunRetries(metadataRetryPeriod(configCtx.Config.Metrics.MetadataRetry), stopWorkers)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ctx.Interfaces.Run(configCtx.Config.Metrics.ContractInterfaces.Period(config.DefaultInterfacesPollPeriod), stopWorkers)
+	}()
+
 	for _, queue := range ctx.MQ.GetQueues() {
 		if handler, ok := handlers[queue]; ok {
 			managers[queue] = NewBulkManager(30, 10, handler)
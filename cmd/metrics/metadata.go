@@ -0,0 +1,26 @@
+package main
+
+import (
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/config"
+	contractHandlers "github.com/baking-bad/bcdhub/internal/handlers"
+)
+
+const defaultMetadataRetryPeriod = 30 * time.Second
+
+func newMetadataHandler(ctx *config.Context) *contractHandlers.TZIP {
+	cfg := ctx.Config.Metrics.MetadataRetry
+	return contractHandlers.NewTZIP(
+		ctx.BigMapDiffs, ctx.Blocks, ctx.Schema, ctx.TZIPVersions, ctx.Storage, ctx.RPC, ctx.IPFS,
+		contractHandlers.WithTZIPMaxAttempts(cfg.MaxAttempts),
+		contractHandlers.WithTZIPBackoff(time.Second*time.Duration(cfg.Backoff)),
+	)
+}
+
+func metadataRetryPeriod(cfg config.RetryConfig) time.Duration {
+	if cfg.PollPeriod <= 0 {
+		return defaultMetadataRetryPeriod
+	}
+	return time.Second * time.Duration(cfg.PollPeriod)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/metrics"
 	"github.com/baking-bad/bcdhub/internal/models/migration"
 	"github.com/pkg/errors"
 )
@@ -23,5 +24,10 @@ func getMigrations(ids []string) error {
 }
 
 func parseMigration(migration migration.Migration) error {
-	return ctx.Contracts.UpdateMigrationsCount(migration.Address, migration.Network)
+	if err := ctx.Contracts.UpdateMigrationsCount(migration.Address, migration.Network); err != nil {
+		return err
+	}
+
+	h := metrics.New(ctx.Contracts, ctx.BigMapDiffs, ctx.Blocks, ctx.Protocols, ctx.Operations, ctx.Schema, ctx.TokenBalances, ctx.TokenMetadata, ctx.TZIP, ctx.Migrations, ctx.Storage, ctx.DB)
+	return h.SendMigrationWebhooks(ctx.Webhooks, migration)
 }
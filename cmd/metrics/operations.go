@@ -55,7 +55,7 @@ func parseOperation(operation operation.Operation) error {
 			return err
 		}
 	}
-	return nil
+	return h.SendOperationWebhooks(ctx.Webhooks, operation)
 }
 
 type stats struct {
@@ -27,10 +27,15 @@ func getProject(ids []string) error {
 func parseProject(contract contract.Contract) error {
 	h := metrics.New(ctx.Contracts, ctx.BigMapDiffs, ctx.Blocks, ctx.Protocols, ctx.Operations, ctx.Schema, ctx.TokenBalances, ctx.TokenMetadata, ctx.TZIP, ctx.Migrations, ctx.Storage, ctx.DB)
 
-	if contract.ProjectID == "" {
-		if err := h.SetContractProjectID(&contract); err != nil {
-			return errors.Errorf("[parseContract] Error during set contract projectID: %s", err)
-		}
+	if contract.ProjectID != "" {
+		return nil
+	}
+
+	if err := h.SetContractProjectID(&contract); err != nil {
+		return errors.Errorf("[parseContract] Error during set contract projectID: %s", err)
+	}
+	if err := ctx.Storage.UpdateFields(models.DocContracts, contract.GetID(), contract, "ProjectID"); err != nil {
+		return err
 	}
-	return ctx.Storage.UpdateFields(models.DocContracts, contract.GetID(), contract, "ProjectID")
+	return h.SendContractWebhooks(ctx.Webhooks, contract)
 }
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/config"
+	"github.com/baking-bad/bcdhub/internal/webhook"
+)
+
+const defaultWebhooksPollPeriod = 5 * time.Second
+
+func newWebhookSender(ctx *config.Context) *webhook.Sender {
+	cfg := ctx.Config.Metrics.Webhooks
+	return webhook.NewSender(
+		ctx.DB,
+		webhook.WithTimeout(time.Second*time.Duration(cfg.Timeout)),
+		webhook.WithMaxAttempts(cfg.MaxAttempts),
+		webhook.WithBackoff(time.Second*time.Duration(cfg.Backoff)),
+		webhook.WithAllowedHosts(cfg.AllowedHosts),
+	)
+}
+
+func webhooksPollPeriod(cfg config.WebhooksConfig) time.Duration {
+	if cfg.PollPeriod <= 0 {
+		return defaultWebhooksPollPeriod
+	}
+	return time.Second * time.Duration(cfg.PollPeriod)
+}
@@ -0,0 +1,285 @@
+package audit
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/meta"
+	"github.com/baking-bad/bcdhub/internal/contractparser/storage"
+	"github.com/baking-bad/bcdhub/internal/helpers"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
+	"github.com/baking-bad/bcdhub/internal/models/contract"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/models/schema"
+	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
+	"github.com/baking-bad/bcdhub/internal/models/transfer"
+	"github.com/baking-bad/bcdhub/internal/noderpc"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	bigMapPageSize    = 1000
+	transfersPageSize = 1000
+)
+
+// Auditor - compares indexed state of contracts with state of the node
+type Auditor struct {
+	storage       models.GeneralRepository
+	rpc           noderpc.INode
+	operations    operation.Repository
+	bigMapDiffs   bigmapdiff.Repository
+	transfers     transfer.Repository
+	tokenBalances tokenbalance.Repository
+	schemas       schema.Repository
+}
+
+// NewAuditor -
+func NewAuditor(storage models.GeneralRepository, rpc noderpc.INode, operations operation.Repository, bigMapDiffs bigmapdiff.Repository, transfers transfer.Repository, tokenBalances tokenbalance.Repository, schemas schema.Repository) *Auditor {
+	return &Auditor{
+		storage:       storage,
+		rpc:           rpc,
+		operations:    operations,
+		bigMapDiffs:   bigMapDiffs,
+		transfers:     transfers,
+		tokenBalances: tokenBalances,
+		schemas:       schemas,
+	}
+}
+
+// Audit - checks `contracts` against node state at `level`. Contracts which can't be checked are listed in `Failed` of report.
+func (a *Auditor) Audit(network, protocol string, level int64, contracts []contract.Contract) Report {
+	report := NewReport(network, level)
+	for i := range contracts {
+		drifts, err := a.AuditContract(protocol, level, contracts[i])
+		if err != nil {
+			report.Failed[contracts[i].Address] = err.Error()
+			continue
+		}
+		report.Checked++
+		report.Drifts = append(report.Drifts, drifts...)
+	}
+	return report
+}
+
+// AuditContract - returns all drifts of `c` at `level`
+func (a *Auditor) AuditContract(protocol string, level int64, c contract.Contract) ([]Drift, error) {
+	drifts := make([]Drift, 0)
+
+	balanceDrifts, err := a.checkBalance(level, c)
+	if err != nil {
+		return nil, err
+	}
+	drifts = append(drifts, balanceDrifts...)
+
+	nodeStorage, err := a.rpc.GetScriptStorageJSON(c.Address, level)
+	if err != nil {
+		return nil, err
+	}
+
+	storageDrifts, err := a.checkStorage(c, nodeStorage)
+	if err != nil {
+		return nil, err
+	}
+	drifts = append(drifts, storageDrifts...)
+
+	bigMapDrifts, err := a.checkBigMaps(protocol, level, c, nodeStorage)
+	if err != nil {
+		return nil, err
+	}
+	drifts = append(drifts, bigMapDrifts...)
+
+	if helpers.StringInArray(consts.FA12Tag, c.Tags) || helpers.StringInArray(consts.FA2Tag, c.Tags) {
+		tokenDrifts, err := a.checkTokenBalances(c)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, tokenDrifts...)
+	}
+	return drifts, nil
+}
+
+func (a *Auditor) checkBalance(level int64, c contract.Contract) ([]Drift, error) {
+	stats, err := a.operations.RecalcStats(c.Network, c.Address)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := a.rpc.GetContractBalance(c.Address, level)
+	if err != nil {
+		return nil, err
+	}
+	if stats.Balance == balance {
+		return nil, nil
+	}
+	return []Drift{
+		newDrift(c, KindBalance, fmt.Sprintf("%d", stats.Balance), fmt.Sprintf("%d", balance)),
+	}, nil
+}
+
+func (a *Auditor) checkStorage(c contract.Contract, nodeStorage gjson.Result) ([]Drift, error) {
+	last, err := a.operations.Last(c.Network, c.Address, math.MaxInt64)
+	if err != nil {
+		if !a.storage.IsRecordNotFound(err) {
+			return nil, err
+		}
+		return []Drift{
+			newDrift(c, KindStorage, "", nodeStorage.Raw),
+		}, nil
+	}
+
+	indexed := gjson.Parse(last.DeffatedStorage)
+	if reflect.DeepEqual(indexed.Value(), nodeStorage.Value()) {
+		return nil, nil
+	}
+	return []Drift{
+		newDrift(c, KindStorage, last.DeffatedStorage, nodeStorage.Raw),
+	}, nil
+}
+
+func (a *Auditor) checkBigMaps(protocol string, level int64, c contract.Contract, nodeStorage gjson.Result) ([]Drift, error) {
+	metadata, err := meta.GetSchema(a.schemas, c.Address, consts.STORAGE, protocol)
+	if err != nil {
+		return nil, err
+	}
+	ptrs, err := storage.FindBigMapPointers(metadata, nodeStorage)
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := make([]Drift, 0)
+	for ptr := range ptrs {
+		expected, err := a.countNodeBigMapKeys(ptr, level)
+		if err != nil {
+			return nil, err
+		}
+
+		diffs, err := a.bigMapDiffs.GetByPtr(c.Address, c.Network, ptr)
+		if err != nil {
+			return nil, err
+		}
+		var indexed int64
+		for i := range diffs {
+			if diffs[i].Value != "" {
+				indexed++
+			}
+		}
+
+		if indexed != expected {
+			drift := newDrift(c, KindBigMap, fmt.Sprintf("%d", indexed), fmt.Sprintf("%d", expected))
+			drift.Ptr = new(int64)
+			*drift.Ptr = ptr
+			drifts = append(drifts, drift)
+		}
+	}
+	return drifts, nil
+}
+
+func (a *Auditor) countNodeBigMapKeys(ptr, level int64) (int64, error) {
+	var count int64
+	for {
+		values, err := a.rpc.GetBigMapValues(ptr, level, count, bigMapPageSize)
+		if err != nil {
+			return 0, err
+		}
+		pageLength := int64(len(values.Array()))
+		count += pageLength
+		if pageLength < bigMapPageSize {
+			return count, nil
+		}
+	}
+}
+
+func (a *Auditor) checkTokenBalances(c contract.Contract) ([]Drift, error) {
+	expected, err := a.sumTransfers(c)
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := make([]Drift, 0)
+	for tokenID, balances := range expected {
+		holders, err := a.tokenBalances.GetHolders(c.Network, c.Address, tokenID)
+		if err != nil {
+			return nil, err
+		}
+
+		indexed := make(map[string]*big.Int)
+		for i := range holders {
+			indexed[holders[i].Address] = holders[i].Value
+		}
+
+		for address, value := range balances {
+			indexedValue, ok := indexed[address]
+			if !ok {
+				indexedValue = big.NewInt(0)
+			}
+			if indexedValue.Cmp(value) != 0 {
+				drifts = append(drifts, newTokenDrift(c, tokenID, address, indexedValue, value))
+			}
+		}
+		for address, indexedValue := range indexed {
+			if _, ok := balances[address]; !ok && indexedValue.Sign() != 0 {
+				drifts = append(drifts, newTokenDrift(c, tokenID, address, indexedValue, big.NewInt(0)))
+			}
+		}
+	}
+	return drifts, nil
+}
+
+// sumTransfers - returns balances computed from transfers of contract grouped by token ID and holder
+func (a *Auditor) sumTransfers(c contract.Contract) (map[string]map[string]*big.Int, error) {
+	balances := make(map[string]map[string]*big.Int)
+	add := func(tokenID string, address string, value *big.Int) {
+		if address == "" {
+			return
+		}
+		if _, ok := balances[tokenID]; !ok {
+			balances[tokenID] = make(map[string]*big.Int)
+		}
+		if _, ok := balances[tokenID][address]; !ok {
+			balances[tokenID][address] = big.NewInt(0)
+		}
+		balances[tokenID][address].Add(balances[tokenID][address], value)
+	}
+
+	ctx := transfer.GetContext{
+		Network:   c.Network,
+		Contracts: []string{c.Address},
+		Size:      transfersPageSize,
+		SortOrder: "desc",
+	}
+	for {
+		page, err := a.transfers.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		transfers := page.Transfers
+		full := len(transfers) == transfersPageSize
+		if full {
+			// transfers of one operation have the same indexed time, so the last of them are requested again with the next page
+			lastIndexedTime := transfers[len(transfers)-1].IndexedTime
+			for len(transfers) > 0 && transfers[len(transfers)-1].IndexedTime == lastIndexedTime {
+				transfers = transfers[:len(transfers)-1]
+			}
+			if len(transfers) == 0 {
+				return nil, fmt.Errorf("Too many transfers with indexed time %d", lastIndexedTime)
+			}
+			ctx.LastID = fmt.Sprintf("%d", lastIndexedTime+1)
+		}
+
+		for i := range transfers {
+			if transfers[i].Status != consts.Applied || transfers[i].AmountBigInt == nil {
+				continue
+			}
+			add(transfers[i].TokenID, transfers[i].From, new(big.Int).Neg(transfers[i].AmountBigInt))
+			add(transfers[i].TokenID, transfers[i].To, transfers[i].AmountBigInt)
+		}
+
+		if !full {
+			return balances, nil
+		}
+	}
+}
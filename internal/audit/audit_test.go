@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/models/contract"
+	mock_tokenbalance "github.com/baking-bad/bcdhub/internal/models/mock/tokenbalance"
+	mock_transfer "github.com/baking-bad/bcdhub/internal/models/mock/transfer"
+	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
+	"github.com/baking-bad/bcdhub/internal/models/transfer"
+	"github.com/golang/mock/gomock"
+)
+
+func TestAuditor_checkTokenBalances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transfers := mock_transfer.NewMockRepository(ctrl)
+	tokenBalances := mock_tokenbalance.NewMockRepository(ctrl)
+
+	newTransfer := func(indexedTime int64, from, to string, amount int64) transfer.Transfer {
+		return transfer.Transfer{
+			IndexedTime:  indexedTime,
+			Status:       consts.Applied,
+			From:         from,
+			To:           to,
+			TokenID:      "0",
+			AmountBigInt: big.NewInt(amount),
+		}
+	}
+
+	// the first page is full and ends in the middle of operation with indexed time 1
+	firstPage := make([]transfer.Transfer, 0, transfersPageSize)
+	for i := 0; i < transfersPageSize-2; i++ {
+		firstPage = append(firstPage, newTransfer(int64(transfersPageSize-i), "", "tz1alice", 1))
+	}
+	firstPage = append(firstPage, newTransfer(1, "", "tz1bob", 10), newTransfer(1, "", "tz1bob", 10))
+	secondPage := []transfer.Transfer{
+		newTransfer(1, "", "tz1bob", 10), newTransfer(1, "", "tz1bob", 10), newTransfer(1, "tz1bob", "tz1carol", 5),
+	}
+
+	gomock.InOrder(
+		transfers.EXPECT().Get(gomock.Any()).DoAndReturn(func(ctx transfer.GetContext) (transfer.Pageable, error) {
+			if ctx.LastID != "" {
+				t.Errorf("unexpected LastID of the first page: %s", ctx.LastID)
+			}
+			return transfer.Pageable{Transfers: firstPage}, nil
+		}),
+		transfers.EXPECT().Get(gomock.Any()).DoAndReturn(func(ctx transfer.GetContext) (transfer.Pageable, error) {
+			if ctx.LastID != "2" {
+				t.Errorf("LastID of the second page = %s, want 2", ctx.LastID)
+			}
+			return transfer.Pageable{Transfers: secondPage}, nil
+		}),
+	)
+
+	tokenBalances.EXPECT().GetHolders("mainnet", "KT1token", "0").Return([]tokenbalance.TokenBalance{
+		{Address: "tz1alice", Value: big.NewInt(transfersPageSize - 2)},
+		{Address: "tz1bob", Value: big.NewInt(20)},
+		{Address: "tz1dave", Value: big.NewInt(1)},
+	}, nil)
+
+	auditor := NewAuditor(nil, nil, nil, nil, transfers, tokenBalances, nil)
+	drifts, err := auditor.checkTokenBalances(contract.NewEmptyContract("mainnet", "KT1token"))
+	if err != nil {
+		t.Errorf("checkTokenBalances() error = %v", err)
+		return
+	}
+
+	want := map[string][2]string{
+		"tz1bob":   {"20", "15"},
+		"tz1carol": {"0", "5"},
+		"tz1dave":  {"1", "0"},
+	}
+	if len(drifts) != len(want) {
+		t.Errorf("checkTokenBalances() drifts = %v, want %d drifts", drifts, len(want))
+		return
+	}
+	for _, drift := range drifts {
+		values, ok := want[drift.Holder]
+		if !ok {
+			t.Errorf("unexpected drift of %s", drift.Holder)
+			continue
+		}
+		if drift.Kind != KindTokenBalance || drift.Indexed != values[0] || drift.Expected != values[1] {
+			t.Errorf("drift of %s = %+v, want indexed %s and expected %s", drift.Holder, drift, values[0], values[1])
+		}
+	}
+}
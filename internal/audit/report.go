@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/models/contract"
+)
+
+// Drift kinds
+const (
+	KindBalance      = "balance"
+	KindStorage      = "storage"
+	KindBigMap       = "big_map"
+	KindTokenBalance = "token_balance"
+)
+
+// Drift - difference between indexed and node state of contract
+type Drift struct {
+	Network  string `json:"network"`
+	Address  string `json:"address"`
+	Kind     string `json:"kind"`
+	Ptr      *int64 `json:"ptr,omitempty"`
+	TokenID  string `json:"token_id,omitempty"`
+	Holder   string `json:"holder,omitempty"`
+	Indexed  string `json:"indexed"`
+	Expected string `json:"expected"`
+}
+
+func newDrift(c contract.Contract, kind, indexed, expected string) Drift {
+	return Drift{
+		Network:  c.Network,
+		Address:  c.Address,
+		Kind:     kind,
+		Indexed:  indexed,
+		Expected: expected,
+	}
+}
+
+func newTokenDrift(c contract.Contract, tokenID string, holder string, indexed, expected *big.Int) Drift {
+	drift := newDrift(c, KindTokenBalance, indexed.String(), expected.String())
+	drift.TokenID = tokenID
+	drift.Holder = holder
+	return drift
+}
+
+// Report - result of audit
+type Report struct {
+	Network   string            `json:"network"`
+	Level     int64             `json:"level"`
+	Timestamp time.Time         `json:"timestamp"`
+	Checked   int               `json:"checked"`
+	Failed    map[string]string `json:"failed"`
+	Drifts    []Drift           `json:"drifts"`
+}
+
+// NewReport -
+func NewReport(network string, level int64) Report {
+	return Report{
+		Network:   network,
+		Level:     level,
+		Timestamp: time.Now().UTC(),
+		Failed:    make(map[string]string),
+		Drifts:    make([]Drift, 0),
+	}
+}
+
+// AffectedContracts - returns IDs of contracts which have drifts
+func (r Report) AffectedContracts() []string {
+	exists := make(map[string]struct{})
+	ids := make([]string, 0)
+	for i := range r.Drifts {
+		c := contract.NewEmptyContract(r.Drifts[i].Network, r.Drifts[i].Address)
+		id := c.GetID()
+		if _, ok := exists[id]; ok {
+			continue
+		}
+		exists[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
@@ -17,6 +18,9 @@ const (
 	EnvironmentBox  = "sandbox"
 )
 
+// DefaultInterfacesPollPeriod - default period of syncing contract interfaces by metrics service and reloading them by indexer and API
+const DefaultInterfacesPollPeriod = time.Minute
+
 // Config -
 type Config struct {
 	RPC          map[string]RPCConfig  `yaml:"rpc"`
@@ -29,6 +33,7 @@ type Config struct {
 	SharePath    string                `yaml:"share_path"`
 	BaseURL      string                `yaml:"base_url"`
 	IPFSGateways []string              `yaml:"ipfs"`
+	IPFSResolver IPFSResolverConfig    `yaml:"ipfs_resolver"`
 	Domains      TezosDomainsConfig    `yaml:"domains"`
 
 	API struct {
@@ -44,6 +49,7 @@ type Config struct {
 		Networks      []string       `yaml:"networks"`
 		MQ            MQConfig       `yaml:"mq"`
 		Pinata        PinataConfig   `yaml:"pinata"`
+		Views         ViewsConfig    `yaml:"views"`
 	} `yaml:"api"`
 
 	Compiler struct {
@@ -61,14 +67,19 @@ type Config struct {
 		SentryEnabled bool   `yaml:"sentry_enabled"`
 
 		SkipDelegatorBlocks bool     `yaml:"skip_delegator_blocks"`
+		PrefetchDepth       int      `yaml:"prefetch_depth"`
+		MonitorHeads        bool     `yaml:"monitor_heads"`
 		MQ                  MQConfig `yaml:"mq"`
 	} `yaml:"indexer"`
 
 	Metrics struct {
-		ProjectName         string   `yaml:"project_name"`
-		SentryEnabled       bool     `yaml:"sentry_enabled"`
-		CacheAliasesSeconds int      `yaml:"cache_aliases_seconds"`
-		MQ                  MQConfig `yaml:"mq"`
+		ProjectName         string         `yaml:"project_name"`
+		SentryEnabled       bool           `yaml:"sentry_enabled"`
+		CacheAliasesSeconds int            `yaml:"cache_aliases_seconds"`
+		Webhooks            WebhooksConfig `yaml:"webhooks"`
+		MetadataRetry       RetryConfig    `yaml:"metadata_retry"`
+		ContractInterfaces  PollConfig     `yaml:"contract_interfaces"`
+		MQ                  MQConfig       `yaml:"mq"`
 	} `yaml:"metrics"`
 
 	Scripts struct {
@@ -78,10 +89,58 @@ type Config struct {
 	} `yaml:"scripts"`
 }
 
+// WebhooksConfig - delivery settings of subscription webhooks. Durations are in seconds.
+type WebhooksConfig struct {
+	Timeout      int      `yaml:"timeout"`
+	MaxAttempts  uint     `yaml:"max_attempts"`
+	Backoff      int      `yaml:"backoff"`
+	PollPeriod   int      `yaml:"poll_period"`
+	AllowedHosts []string `yaml:"allowed_hosts"`
+}
+
+// RetryConfig - settings of retrying failed fetches. Durations are in seconds.
+type RetryConfig struct {
+	MaxAttempts int64 `yaml:"max_attempts"`
+	Backoff     int   `yaml:"backoff"`
+	PollPeriod  int   `yaml:"poll_period"`
+}
+
+// PollConfig - settings of periodic background job. Durations are in seconds.
+type PollConfig struct {
+	PollPeriod int `yaml:"poll_period"`
+}
+
+// Period - returns poll period or `defaultPeriod` if it's not set
+func (cfg PollConfig) Period(defaultPeriod time.Duration) time.Duration {
+	if cfg.PollPeriod <= 0 {
+		return defaultPeriod
+	}
+	return time.Second * time.Duration(cfg.PollPeriod)
+}
+
 // RPCConfig -
 type RPCConfig struct {
-	URI     string `yaml:"uri"`
-	Timeout int    `yaml:"timeout"`
+	URI        string   `yaml:"uri"`
+	URIs       []string `yaml:"uris"`
+	Timeout    int      `yaml:"timeout"`
+	MaxLag     int64    `yaml:"max_lag"`
+	HedgeDelay int      `yaml:"hedge_delay"`
+	CacheDir   string   `yaml:"cache_dir"`
+	CacheSize  int64    `yaml:"cache_size"`
+}
+
+// GetURIs - returns all node URIs of network
+func (cfg RPCConfig) GetURIs() []string {
+	uris := make([]string, 0, len(cfg.URIs)+1)
+	if cfg.URI != "" {
+		uris = append(uris, cfg.URI)
+	}
+	for i := range cfg.URIs {
+		if cfg.URIs[i] != "" && cfg.URIs[i] != cfg.URI {
+			uris = append(uris, cfg.URIs[i])
+		}
+	}
+	return uris
 }
 
 // TzKTConfig -
@@ -92,8 +151,16 @@ type TzKTConfig struct {
 	Timeout     int    `yaml:"timeout"`
 }
 
-// StorageConfig -
+// Storage kinds
+const (
+	StorageKindElastic   = "elastic"
+	StorageKindReindexer = "reindexer"
+	StorageKindPostgres  = "postgres"
+)
+
+// StorageConfig - storage of indexed data. Postgres storage uses `db.conn_string` if `uri` is empty and shares connection with application database.
 type StorageConfig struct {
+	Kind    string   `yaml:"kind"`
 	URI     []string `yaml:"uri"`
 	Timeout int      `yaml:"timeout"`
 }
@@ -201,6 +268,23 @@ type PinataConfig struct {
 	TimeoutSeconds int    `yaml:"timeout_seconds"`
 }
 
+// ViewsConfig - settings of HTTP client which calls off-chain REST API views
+type ViewsConfig struct {
+	TimeoutSeconds  int      `yaml:"timeout_seconds"`
+	MaxResponseSize int64    `yaml:"max_response_size"`
+	AllowedHosts    []string `yaml:"allowed_hosts"`
+}
+
+// IPFSResolverConfig - settings of IPFS content resolving. Gateways from `ipfs` section are used if local node is unavailable.
+type IPFSResolverConfig struct {
+	Node           string       `yaml:"node"`
+	TimeoutSeconds int          `yaml:"timeout_seconds"`
+	MaxSize        int64        `yaml:"max_size"`
+	CacheDir       string       `yaml:"cache_dir"`
+	CacheSize      int64        `yaml:"cache_size"`
+	Pinata         PinataConfig `yaml:"pinata"`
+}
+
 // LoadDefaultConfig -
 func LoadDefaultConfig() (Config, error) {
 	configurations := map[string]string{
@@ -238,5 +322,13 @@ func LoadConfig(filename string) (Config, error) {
 		return config, fmt.Errorf("unmarshaling configuration file %s error: %w", filename, err)
 	}
 
+	// postgres storage uses application database if its own connection is not set
+	if config.Storage.Kind == StorageKindPostgres && len(config.Storage.URI) == 0 {
+		config.Storage.URI = []string{config.DB.ConnString}
+		if config.Storage.Timeout == 0 {
+			config.Storage.Timeout = config.DB.Timeout
+		}
+	}
+
 	return config, nil
 }
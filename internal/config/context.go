@@ -1,28 +1,39 @@
 package config
 
 import (
+	"path/filepath"
+	"time"
+
 	"github.com/baking-bad/bcdhub/internal/aws"
 	"github.com/baking-bad/bcdhub/internal/contractparser/kinds"
 	"github.com/baking-bad/bcdhub/internal/database"
+	"github.com/baking-bad/bcdhub/internal/ipfs"
+	"github.com/baking-bad/bcdhub/internal/logger"
 	"github.com/baking-bad/bcdhub/internal/models"
 	"github.com/baking-bad/bcdhub/internal/models/balanceupdate"
 	"github.com/baking-bad/bcdhub/internal/models/bigmapaction"
 	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
 	"github.com/baking-bad/bcdhub/internal/models/block"
 	"github.com/baking-bad/bcdhub/internal/models/contract"
+	"github.com/baking-bad/bcdhub/internal/models/contractinterface"
 	"github.com/baking-bad/bcdhub/internal/models/migration"
 	"github.com/baking-bad/bcdhub/internal/models/operation"
 	"github.com/baking-bad/bcdhub/internal/models/protocol"
+	"github.com/baking-bad/bcdhub/internal/models/saplingdiff"
 	"github.com/baking-bad/bcdhub/internal/models/schema"
 	"github.com/baking-bad/bcdhub/internal/models/tezosdomain"
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
+	"github.com/baking-bad/bcdhub/internal/models/ticketupdate"
 	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
 	"github.com/baking-bad/bcdhub/internal/models/tokenmetadata"
 	"github.com/baking-bad/bcdhub/internal/models/transfer"
 	"github.com/baking-bad/bcdhub/internal/models/tzip"
+	"github.com/baking-bad/bcdhub/internal/models/tzipversion"
 	"github.com/baking-bad/bcdhub/internal/mq"
 	"github.com/baking-bad/bcdhub/internal/noderpc"
 	"github.com/baking-bad/bcdhub/internal/pinata"
 	"github.com/baking-bad/bcdhub/internal/tzkt"
+	"github.com/baking-bad/bcdhub/internal/views"
 	"github.com/pkg/errors"
 )
 
@@ -34,12 +45,14 @@ type Context struct {
 	RPC          map[string]noderpc.INode
 	TzKTServices map[string]tzkt.Service
 	Pinata       pinata.Service
+	IPFS         ipfs.Resolver
+	RestViews    *views.RestClient
 
 	Config     Config
 	SharePath  string
 	TzipSchema string
 
-	Interfaces map[string]kinds.ContractKind
+	Interfaces *kinds.Registry
 	Domains    map[string]string
 
 	Storage        models.GeneralRepository
@@ -51,17 +64,25 @@ type Context struct {
 	Migrations     migration.Repository
 	Operations     operation.Repository
 	Protocols      protocol.Repository
+	SaplingDiffs   saplingdiff.Repository
 	Schema         schema.Repository
 	TezosDomains   tezosdomain.Repository
+	TicketBalances ticketbalance.Repository
+	TicketUpdates  ticketupdate.Repository
 	TokenBalances  tokenbalance.Repository
 	TokenMetadata  tokenmetadata.Repository
 	Transfers      transfer.Repository
 	TZIP           tzip.Repository
+	TZIPVersions   tzipversion.Repository
+
+	stop chan struct{}
 }
 
 // NewContext -
 func NewContext(opts ...ContextOption) *Context {
-	ctx := &Context{}
+	ctx := &Context{
+		stop: make(chan struct{}),
+	}
 
 	for _, opt := range opts {
 		opt(ctx)
@@ -85,12 +106,76 @@ func (ctx *Context) GetTzKTService(network string) (tzkt.Service, error) {
 	return nil, errors.Errorf("Unknown tzkt service network %s", network)
 }
 
+// InterfacesDirectory - returns directory of user-defined contract interfaces
+func (ctx *Context) InterfacesDirectory() string {
+	return filepath.Join(ctx.SharePath, "interfaces")
+}
+
+// LoadInterfaces - returns built-in interfaces, interfaces stored in the index and interfaces of `interfaces` directory of share path.
+// Broken stored interfaces are skipped.
+func (ctx *Context) LoadInterfaces() (map[string]kinds.ContractKind, error) {
+	result, err := kinds.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx.Storage != nil {
+		stored := make([]contractinterface.Interface, 0)
+		if err := ctx.Storage.GetAll(&stored); err != nil {
+			logger.Errorf("Stored contract interfaces are not loaded: %s", err)
+		}
+		for i := range stored {
+			if err := kinds.Register(result, stored[i].Kind()); err != nil {
+				logger.Errorf("Stored contract interface %s is skipped: %s", stored[i].Name, err)
+			}
+		}
+	}
+
+	if ctx.SharePath != "" {
+		custom, err := kinds.LoadDirectory(ctx.InterfacesDirectory())
+		if err != nil {
+			return nil, err
+		}
+		if err := kinds.Register(result, custom...); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (ctx *Context) reloadInterfaces(period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.stop:
+			return
+		case <-ticker.C:
+			interfaces, err := ctx.LoadInterfaces()
+			if err != nil {
+				logger.Errorf("[contract interfaces] reload error: %s", err)
+				continue
+			}
+			ctx.Interfaces.Set(interfaces)
+		}
+	}
+}
+
 // Close -
 func (ctx *Context) Close() {
+	if ctx.stop != nil {
+		close(ctx.stop)
+	}
 	if ctx.MQ != nil {
 		ctx.MQ.Close()
 	}
 	if ctx.DB != nil {
 		ctx.DB.Close()
 	}
+	for _, rpc := range ctx.RPC {
+		if closer, ok := rpc.(interface{ Close() }); ok {
+			closer.Close()
+		}
+	}
 }
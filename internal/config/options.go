@@ -3,7 +3,7 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
-	"strings"
+	"path/filepath"
 	"time"
 
 	"github.com/baking-bad/bcdhub/internal/aws"
@@ -19,12 +19,37 @@ import (
 	"github.com/baking-bad/bcdhub/internal/elastic/migration"
 	"github.com/baking-bad/bcdhub/internal/elastic/operation"
 	"github.com/baking-bad/bcdhub/internal/elastic/protocol"
+	"github.com/baking-bad/bcdhub/internal/elastic/saplingdiff"
 	"github.com/baking-bad/bcdhub/internal/elastic/schema"
 	"github.com/baking-bad/bcdhub/internal/elastic/tezosdomain"
+	"github.com/baking-bad/bcdhub/internal/elastic/ticketbalance"
+	"github.com/baking-bad/bcdhub/internal/elastic/ticketupdate"
 	"github.com/baking-bad/bcdhub/internal/elastic/tokenbalance"
 	"github.com/baking-bad/bcdhub/internal/elastic/tokenmetadata"
 	"github.com/baking-bad/bcdhub/internal/elastic/transfer"
 	"github.com/baking-bad/bcdhub/internal/elastic/tzip"
+	"github.com/baking-bad/bcdhub/internal/elastic/tzipversion"
+	"github.com/baking-bad/bcdhub/internal/ipfs"
+
+	postgresBU "github.com/baking-bad/bcdhub/internal/postgres/balanceupdate"
+	postgresBMA "github.com/baking-bad/bcdhub/internal/postgres/bigmapaction"
+	postgresBMD "github.com/baking-bad/bcdhub/internal/postgres/bigmapdiff"
+	postgresBlock "github.com/baking-bad/bcdhub/internal/postgres/block"
+	postgresContract "github.com/baking-bad/bcdhub/internal/postgres/contract"
+	postgresCore "github.com/baking-bad/bcdhub/internal/postgres/core"
+	postgresMigration "github.com/baking-bad/bcdhub/internal/postgres/migration"
+	postgresOperation "github.com/baking-bad/bcdhub/internal/postgres/operation"
+	postgresProtocol "github.com/baking-bad/bcdhub/internal/postgres/protocol"
+	postgresSD "github.com/baking-bad/bcdhub/internal/postgres/saplingdiff"
+	postgresSchema "github.com/baking-bad/bcdhub/internal/postgres/schema"
+	postgresTD "github.com/baking-bad/bcdhub/internal/postgres/tezosdomain"
+	postgresTicketBalance "github.com/baking-bad/bcdhub/internal/postgres/ticketbalance"
+	postgresTicketUpdate "github.com/baking-bad/bcdhub/internal/postgres/ticketupdate"
+	postgresTB "github.com/baking-bad/bcdhub/internal/postgres/tokenbalance"
+	postgresTM "github.com/baking-bad/bcdhub/internal/postgres/tokenmetadata"
+	postgresTransfer "github.com/baking-bad/bcdhub/internal/postgres/transfer"
+	postgrestzip "github.com/baking-bad/bcdhub/internal/postgres/tzip"
+	postgresTZIPVersion "github.com/baking-bad/bcdhub/internal/postgres/tzipversion"
 
 	reindexerBU "github.com/baking-bad/bcdhub/internal/reindexer/balanceupdate"
 	reindexerBMA "github.com/baking-bad/bcdhub/internal/reindexer/bigmapaction"
@@ -35,19 +60,27 @@ import (
 	reindexerMigration "github.com/baking-bad/bcdhub/internal/reindexer/migration"
 	reindexerOperation "github.com/baking-bad/bcdhub/internal/reindexer/operation"
 	reindexerProtocol "github.com/baking-bad/bcdhub/internal/reindexer/protocol"
+	reindexerSD "github.com/baking-bad/bcdhub/internal/reindexer/saplingdiff"
 	reindexerSchema "github.com/baking-bad/bcdhub/internal/reindexer/schema"
 	reindexerTD "github.com/baking-bad/bcdhub/internal/reindexer/tezosdomain"
+	reindexerTicketBalance "github.com/baking-bad/bcdhub/internal/reindexer/ticketbalance"
+	reindexerTicketUpdate "github.com/baking-bad/bcdhub/internal/reindexer/ticketupdate"
 	reindexerTB "github.com/baking-bad/bcdhub/internal/reindexer/tokenbalance"
 	reindexerTM "github.com/baking-bad/bcdhub/internal/reindexer/tokenmetadata"
 	reindexerTransfer "github.com/baking-bad/bcdhub/internal/reindexer/transfer"
 	reindexertzip "github.com/baking-bad/bcdhub/internal/reindexer/tzip"
+	reindexerTZIPVersion "github.com/baking-bad/bcdhub/internal/reindexer/tzipversion"
 
+	"github.com/baking-bad/bcdhub/internal/logger"
 	"github.com/baking-bad/bcdhub/internal/mq"
 	"github.com/baking-bad/bcdhub/internal/noderpc"
 	"github.com/baking-bad/bcdhub/internal/pinata"
 	"github.com/baking-bad/bcdhub/internal/tzkt"
+	"github.com/baking-bad/bcdhub/internal/views"
 )
 
+const rpcCacheStatsPeriod = 10 * time.Minute
+
 // ContextOption -
 type ContextOption func(ctx *Context)
 
@@ -60,9 +93,28 @@ func WithRPC(rpcConfig map[string]RPCConfig) ContextOption {
 		rpc := make(map[string]noderpc.INode)
 		for network, rpcProvider := range rpcConfig {
 			rpc[network] = noderpc.NewPool(
-				[]string{rpcProvider.URI},
-				noderpc.WithTimeout(time.Second*time.Duration(rpcProvider.Timeout)),
+				rpcProvider.GetURIs(),
+				[]noderpc.NodeOption{
+					noderpc.WithTimeout(time.Second * time.Duration(rpcProvider.Timeout)),
+				},
+				noderpc.WithMaxLag(rpcProvider.MaxLag),
+				noderpc.WithHedging(time.Millisecond*time.Duration(rpcProvider.HedgeDelay)),
+			)
+
+			if rpcProvider.CacheDir == "" {
+				continue
+			}
+			cached, err := noderpc.NewCachedNode(
+				rpc[network],
+				filepath.Join(rpcProvider.CacheDir, network),
+				noderpc.WithCacheSize(rpcProvider.CacheSize<<20),
 			)
+			if err != nil {
+				logger.Errorf("RPC cache of %s is disabled: %s", network, err)
+				continue
+			}
+			go cached.LogStats(network, rpcCacheStatsPeriod, ctx.stop)
+			rpc[network] = cached
 		}
 		ctx.RPC = rpc
 	}
@@ -74,7 +126,8 @@ func WithStorage(cfg StorageConfig) ContextOption {
 		if len(cfg.URI) == 0 {
 			panic("Please set connection strings to storage in config")
 		}
-		if strings.HasPrefix(cfg.URI[0], "builtin://") {
+		switch cfg.Kind {
+		case StorageKindReindexer:
 			storage, err := reindexerCore.New(cfg.URI[0])
 			if err != nil {
 				panic(err)
@@ -89,17 +142,47 @@ func WithStorage(cfg StorageConfig) ContextOption {
 			ctx.Migrations = reindexerMigration.NewStorage(storage)
 			ctx.Operations = reindexerOperation.NewStorage(storage)
 			ctx.Protocols = reindexerProtocol.NewStorage(storage)
+			ctx.SaplingDiffs = reindexerSD.NewStorage(storage)
 			ctx.Schema = reindexerSchema.NewStorage(storage)
 			ctx.TezosDomains = reindexerTD.NewStorage(storage)
+			ctx.TicketBalances = reindexerTicketBalance.NewStorage(storage)
+			ctx.TicketUpdates = reindexerTicketUpdate.NewStorage(storage)
 			ctx.TokenBalances = reindexerTB.NewStorage(storage)
 			ctx.TokenMetadata = reindexerTM.NewStorage(storage)
 			ctx.Transfers = reindexerTransfer.NewStorage(storage)
 			ctx.TZIP = reindexertzip.NewStorage(storage)
+			ctx.TZIPVersions = reindexerTZIPVersion.NewStorage(storage)
+
+			if err := ctx.Storage.CreateIndexes(); err != nil {
+				panic(err)
+			}
+		case StorageKindPostgres:
+			storage := postgresCore.WaitNew(cfg.URI[0], cfg.Timeout)
+
+			ctx.Storage = storage
+			ctx.BalanceUpdates = postgresBU.NewStorage(storage)
+			ctx.BigMapActions = postgresBMA.NewStorage(storage)
+			ctx.BigMapDiffs = postgresBMD.NewStorage(storage)
+			ctx.Blocks = postgresBlock.NewStorage(storage)
+			ctx.Contracts = postgresContract.NewStorage(storage)
+			ctx.Migrations = postgresMigration.NewStorage(storage)
+			ctx.Operations = postgresOperation.NewStorage(storage)
+			ctx.Protocols = postgresProtocol.NewStorage(storage)
+			ctx.SaplingDiffs = postgresSD.NewStorage(storage)
+			ctx.Schema = postgresSchema.NewStorage(storage)
+			ctx.TezosDomains = postgresTD.NewStorage(storage)
+			ctx.TicketBalances = postgresTicketBalance.NewStorage(storage)
+			ctx.TicketUpdates = postgresTicketUpdate.NewStorage(storage)
+			ctx.TokenBalances = postgresTB.NewStorage(storage)
+			ctx.TokenMetadata = postgresTM.NewStorage(storage)
+			ctx.Transfers = postgresTransfer.NewStorage(storage)
+			ctx.TZIP = postgrestzip.NewStorage(storage)
+			ctx.TZIPVersions = postgresTZIPVersion.NewStorage(storage)
 
 			if err := ctx.Storage.CreateIndexes(); err != nil {
 				panic(err)
 			}
-		} else {
+		case StorageKindElastic, "":
 			es := core.WaitNew(cfg.URI, cfg.Timeout)
 
 			ctx.Storage = es
@@ -111,12 +194,18 @@ func WithStorage(cfg StorageConfig) ContextOption {
 			ctx.Migrations = migration.NewStorage(es)
 			ctx.Operations = operation.NewStorage(es)
 			ctx.Protocols = protocol.NewStorage(es)
+			ctx.SaplingDiffs = saplingdiff.NewStorage(es)
 			ctx.Schema = schema.NewStorage(es)
 			ctx.TezosDomains = tezosdomain.NewStorage(es)
+			ctx.TicketBalances = ticketbalance.NewStorage(es)
+			ctx.TicketUpdates = ticketupdate.NewStorage(es)
 			ctx.TokenBalances = tokenbalance.NewStorage(es)
 			ctx.TokenMetadata = tokenmetadata.NewStorage(es)
 			ctx.Transfers = transfer.NewStorage(es)
 			ctx.TZIP = tzip.NewStorage(es)
+			ctx.TZIPVersions = tzipversion.NewStorage(es)
+		default:
+			panic(fmt.Sprintf("Unknown storage kind: %s", cfg.Kind))
 		}
 	}
 }
@@ -190,14 +279,26 @@ func WithLoadErrorDescriptions(filePath string) ContextOption {
 	}
 }
 
-// WithContractsInterfaces -
+// WithContractsInterfaces - loads built-in interfaces, interfaces stored in the index and interfaces from `interfaces` directory of share path.
+// Interface file replaces stored interface with the same name. Storage and share path have to be set before.
 func WithContractsInterfaces() ContextOption {
 	return func(ctx *Context) {
-		result, err := kinds.Load()
+		interfaces, err := ctx.LoadInterfaces()
 		if err != nil {
 			panic(err)
 		}
-		ctx.Interfaces = result
+		ctx.Interfaces = kinds.NewRegistry(interfaces)
+	}
+}
+
+// WithContractsInterfacesReload - reloads contract interfaces every `period` until context is closed,
+// so interfaces synced by metrics service are applied without restart. Interfaces have to be loaded before.
+func WithContractsInterfacesReload(period time.Duration) ContextOption {
+	return func(ctx *Context) {
+		if ctx.Interfaces == nil {
+			return
+		}
+		go ctx.reloadInterfaces(period)
 	}
 }
 
@@ -226,6 +327,48 @@ func WithPinata(cfg PinataConfig) ContextOption {
 	}
 }
 
+// WithIPFS - resolves IPFS content with local node (if it's set) and then with `gateways`.
+// Trusted content is cached on disk and resolved content is pinned to Pinata if they are configured.
+func WithIPFS(gateways []string, cfg IPFSResolverConfig) ContextOption {
+	return func(ctx *Context) {
+		timeout := time.Second * time.Duration(cfg.TimeoutSeconds)
+
+		resolvers := make([]ipfs.Resolver, 0, 2)
+		if cfg.Node != "" {
+			resolvers = append(resolvers, ipfs.NewNode(cfg.Node, ipfs.WithNodeTimeout(timeout), ipfs.WithNodeMaxSize(cfg.MaxSize)))
+		}
+		if len(gateways) > 0 {
+			resolvers = append(resolvers, ipfs.NewGateways(gateways, ipfs.WithGatewaysTimeout(timeout), ipfs.WithGatewaysMaxSize(cfg.MaxSize)))
+		}
+		var resolver ipfs.Resolver = ipfs.NewFallback(resolvers...)
+
+		if cfg.CacheDir != "" {
+			cached, err := ipfs.NewCache(resolver, cfg.CacheDir, ipfs.WithCacheSize(cfg.CacheSize<<20))
+			if err != nil {
+				logger.Errorf("IPFS cache is disabled: %s", err)
+			} else {
+				resolver = cached
+			}
+		}
+
+		if cfg.Pinata.Key != "" {
+			resolver = ipfs.NewPinning(resolver, pinata.New(cfg.Pinata.Key, cfg.Pinata.SecretKey, time.Second*time.Duration(cfg.Pinata.TimeoutSeconds)))
+		}
+		ctx.IPFS = resolver
+	}
+}
+
+// WithRestViews -
+func WithRestViews(cfg ViewsConfig) ContextOption {
+	return func(ctx *Context) {
+		ctx.RestViews = views.NewRestClient(
+			views.WithRestTimeout(time.Second*time.Duration(cfg.TimeoutSeconds)),
+			views.WithRestResponseSize(cfg.MaxResponseSize),
+			views.WithRestAllowedHosts(cfg.AllowedHosts),
+		)
+	}
+}
+
 // WithTzipSchema -
 func WithTzipSchema(filePath string) ContextOption {
 	return func(ctx *Context) {
@@ -138,8 +138,9 @@ func (e *Error) MarshalJSON() ([]byte, error) {
 
 // DefaultError -
 type DefaultError struct {
-	Location int64              `json:"location,omitempty"`
-	With     stdJSON.RawMessage `json:"with,omitempty"`
+	Location  int64              `json:"location,omitempty"`
+	With      stdJSON.RawMessage `json:"with,omitempty"`
+	Expansion stdJSON.RawMessage `json:"expansion,omitempty"`
 }
 
 // Format -
@@ -147,6 +148,26 @@ func (e *DefaultError) Format() error {
 	if e.With == nil {
 		return nil
 	}
+	text := e.withMicheline()
+	if text.String() != "" {
+		errString, err := formatter.MichelineToMichelson(text, true, formatter.DefLineSize)
+		if err != nil {
+			return err
+		}
+		e.With = []byte(errString)
+	}
+	if e.Expansion != nil {
+		expansion, err := formatter.MichelineToMichelson(gjson.ParseBytes(e.Expansion), true, formatter.DefLineSize)
+		if err != nil {
+			return err
+		}
+		e.Expansion = []byte(expansion)
+	}
+	return nil
+}
+
+// withMicheline - returns `With` value. Packed bytes are unpacked.
+func (e *DefaultError) withMicheline() gjson.Result {
 	text := gjson.ParseBytes(e.With)
 	if text.Get("bytes").Exists() {
 		data := text.Get("bytes").String()
@@ -156,14 +177,7 @@ func (e *DefaultError) Format() error {
 			text = gjson.Parse(decodedString)
 		}
 	}
-	if text.String() != "" {
-		errString, err := formatter.MichelineToMichelson(text, true, formatter.DefLineSize)
-		if err != nil {
-			return err
-		}
-		e.With = []byte(errString)
-	}
-	return nil
+	return text
 }
 
 // String -
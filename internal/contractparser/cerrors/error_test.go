@@ -5,7 +5,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/baking-bad/bcdhub/internal/models/tzip"
 	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestError_parse(t *testing.T) {
@@ -295,3 +297,70 @@ func TestInvalidSyntacticConstantError_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslate(t *testing.T) {
+	translations := []tzip.Error{
+		{Error: []byte(`{"int":"1"}`), Expansion: []byte(`{"string":"Not enough balance"}`), Languages: []string{"en"}},
+		{Error: []byte(`{"string":"NotOwner"}`), Expansion: []byte(`{"string":"Sender is not owner"}`)},
+		{View: "translate"},
+	}
+	translator := func(view string, value gjson.Result) ([]byte, error) {
+		if view != "translate" || value.Get("int").String() != "3" {
+			return nil, nil
+		}
+		return []byte(`{"string":"Paused"}`), nil
+	}
+	tests := []struct {
+		name      string
+		errJSON   string
+		with      string
+		expansion string
+	}{
+		{
+			name:      "int error",
+			errJSON:   `{"kind":"temporary","id":"proto.008-PtEdo2Zk.michelson_v1.script_rejected","location":10,"with":{"int": "1"}}`,
+			with:      "1",
+			expansion: `"Not enough balance"`,
+		}, {
+			name:      "packed error",
+			errJSON:   `{"kind":"temporary","id":"proto.008-PtEdo2Zk.michelson_v1.script_rejected","location":10,"with":{"bytes":"0501000000084e6f744f776e6572"}}`,
+			with:      `"NotOwner"`,
+			expansion: `"Sender is not owner"`,
+		}, {
+			name:      "dynamic error",
+			errJSON:   `{"kind":"temporary","id":"proto.008-PtEdo2Zk.michelson_v1.script_rejected","location":10,"with":{"int":"3"}}`,
+			with:      "3",
+			expansion: `"Paused"`,
+		}, {
+			name:    "unknown error",
+			errJSON: `{"kind":"temporary","id":"proto.008-PtEdo2Zk.michelson_v1.script_rejected","location":10,"with":{"int":"2"}}`,
+			with:    "2",
+		},
+	}
+
+	if err := LoadErrorDescriptions("errors.json"); err != nil {
+		panic(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e Error
+			if err := json.Unmarshal([]byte(tt.errJSON), &e); err != nil {
+				t.Errorf("json.Unmarshal: %v", err)
+				return
+			}
+			errs := []*Error{&e}
+			if err := NewTranslations(translations).Translate(errs, translator); err != nil {
+				t.Errorf("Translate: %v", err)
+				return
+			}
+			if err := e.Format(); err != nil {
+				t.Errorf("Format: %v", err)
+				return
+			}
+
+			defaultError := e.IError.(*DefaultError)
+			assert.Equal(t, tt.with, string(defaultError.With))
+			assert.Equal(t, tt.expansion, string(defaultError.Expansion))
+		})
+	}
+}
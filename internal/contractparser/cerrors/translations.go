@@ -0,0 +1,91 @@
+package cerrors
+
+import (
+	stdJSON "encoding/json"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/models/tzip"
+	"github.com/tidwall/gjson"
+)
+
+// DynamicTranslator - computes expansion of error `value` by off-chain view `view`. Returns nil if the view does not expand the value.
+type DynamicTranslator func(view string, value gjson.Result) ([]byte, error)
+
+// Translations - table of translations from `errors` section of contract metadata. It is built once per contract.
+type Translations struct {
+	static  map[string][]byte
+	dynamic []string
+}
+
+// NewTranslations -
+func NewTranslations(items []tzip.Error) *Translations {
+	t := &Translations{
+		static:  make(map[string][]byte),
+		dynamic: make([]string, 0),
+	}
+	for i := range items {
+		switch {
+		case items[i].IsStatic():
+			key := michelineKey(gjson.ParseBytes(items[i].Error))
+			if _, ok := t.static[key]; !ok {
+				t.static[key] = items[i].Expansion
+			}
+		case items[i].View != "":
+			t.dynamic = append(t.dynamic, items[i].View)
+		}
+	}
+	return t
+}
+
+// Empty -
+func (t *Translations) Empty() bool {
+	return len(t.static) == 0 && len(t.dynamic) == 0
+}
+
+// Translate - expands script rejected errors. Static translations are looked up first, then dynamic ones are computed by `translator`
+// in order of metadata. Dynamic translations are skipped if `translator` is nil. Must be called before `Format`.
+func (t *Translations) Translate(errs []*Error, translator DynamicTranslator) error {
+	for i := range errs {
+		if !errs[i].Is(consts.ScriptRejectedError) {
+			continue
+		}
+		defaultError, ok := errs[i].IError.(*DefaultError)
+		if !ok || defaultError.With == nil || defaultError.Expansion != nil {
+			continue
+		}
+
+		value := defaultError.withMicheline()
+		if expansion, ok := t.static[michelineKey(gjson.ParseBytes(defaultError.With))]; ok {
+			defaultError.Expansion = expansion
+			continue
+		}
+		if expansion, ok := t.static[michelineKey(value)]; ok {
+			defaultError.Expansion = expansion
+			continue
+		}
+
+		if translator == nil {
+			continue
+		}
+		for _, view := range t.dynamic {
+			expansion, err := translator(view, value)
+			if err != nil {
+				return err
+			}
+			if expansion != nil {
+				defaultError.Expansion = expansion
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// michelineKey - returns canonical JSON of micheline `value`: object keys are sorted and whitespaces are removed
+func michelineKey(value gjson.Result) string {
+	data, err := stdJSON.Marshal(value.Value())
+	if err != nil {
+		return value.Raw
+	}
+	return string(data)
+}
@@ -18,6 +18,7 @@ const (
 	Origination    = "origination"
 	OriginationNew = "origination_new"
 	Delegation     = "delegation"
+	Reveal         = "reveal"
 	Migration      = "migration"
 )
 
@@ -20,31 +20,36 @@ const (
 // GetContract -
 func GetContract(rpc noderpc.INode, address, network, protocol, filesDirectory string, fallbackLevel int64) (gjson.Result, error) {
 	if filesDirectory != "" {
-		protoSymLink, err := meta.GetProtoSymLink(protocol)
-		if err != nil {
-			return gjson.Result{}, err
-		}
-
-		filePath := fmt.Sprintf(contractFormatPath, filesDirectory, network, address, protoSymLink)
-		_, err = os.Stat(filePath)
+		contract, err := GetIndexedContract(address, network, protocol, filesDirectory)
 		switch {
 		case err == nil:
-			data, err := ioutil.ReadFile(filePath)
-			if err != nil {
-				return gjson.Result{}, err
-			}
-			contract := gjson.ParseBytes(data)
-			if contract.Get("script").Exists() {
-				contract = contract.Get("script")
-			}
 			return contract, nil
-		case !os.IsNotExist(err):
+		case !os.IsNotExist(errors.Cause(err)):
 			return gjson.Result{}, err
 		}
 	}
 	return rpc.GetScriptJSON(address, fallbackLevel)
 }
 
+// GetIndexedContract - returns script of contract which is stored by indexer in `filesDirectory`. Returns `os.ErrNotExist` if contract is not stored.
+func GetIndexedContract(address, network, protocol, filesDirectory string) (gjson.Result, error) {
+	protoSymLink, err := meta.GetProtoSymLink(protocol)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+
+	filePath := fmt.Sprintf(contractFormatPath, filesDirectory, network, address, protoSymLink)
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	contract := gjson.ParseBytes(data)
+	if contract.Get("script").Exists() {
+		contract = contract.Get("script")
+	}
+	return contract, nil
+}
+
 // RemoveContractFromFileSystem -
 func RemoveContractFromFileSystem(address, network, protocol, filesDirectory string) error {
 	if filesDirectory == "" {
@@ -0,0 +1,147 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/storage/hash"
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// BigMap - big map value. Entries of stored big map (`Ptr` is set) are loaded from big map diffs on demand.
+// `Updates` contains entries changed during execution: removed keys have `None` value.
+type BigMap struct {
+	Ptr       *int64
+	KeyType   *typechecker.Type
+	ValueType *typechecker.Type
+	Updates   Map
+}
+
+// Micheline -
+func (v *BigMap) Micheline(optimized bool) interface{} {
+	if v.Ptr != nil {
+		return map[string]interface{}{consts.KeyInt: strconv.FormatInt(*v.Ptr, 10)}
+	}
+	result := make([]interface{}, 0, len(v.Updates))
+	for i := range v.Updates {
+		value := v.Updates[i].Value.(Option).Value
+		if value == nil {
+			continue
+		}
+		result = append(result, prim("Elt", v.Updates[i].Key.Micheline(optimized), value.Micheline(optimized)))
+	}
+	return result
+}
+
+func (v *BigMap) update(key Value, value Value) *BigMap {
+	return &BigMap{
+		Ptr:       v.Ptr,
+		KeyType:   v.KeyType,
+		ValueType: v.ValueType,
+		Updates:   v.Updates.update(key, Option{Value: value}),
+	}
+}
+
+// BigMapDiff - change of big map entry made by execution. `Value` is nil for removed keys.
+type BigMapDiff struct {
+	Ptr     int64       `json:"ptr"`
+	KeyHash string      `json:"key_hash"`
+	Key     interface{} `json:"key"`
+	Value   interface{} `json:"value,omitempty"`
+}
+
+func keyHash(key Value) (string, error) {
+	data, err := json.Marshal(key.Micheline(true))
+	if err != nil {
+		return "", err
+	}
+	return hash.Key(gjson.ParseBytes(data))
+}
+
+// bigMapGet - returns value of `key` or nil if it's absent
+func (m *machine) bigMapGet(bm *BigMap, key Value) (Value, error) {
+	if value, ok := bm.Updates.get(key); ok {
+		return value.(Option).Value, nil
+	}
+	if bm.Ptr == nil {
+		return nil, nil
+	}
+	if m.bigMaps == nil {
+		return nil, errors.Errorf("big map %d can not be read: big map storage is not set", *bm.Ptr)
+	}
+
+	kh, err := keyHash(key)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := m.bigMaps.CurrentByKeyAtLevel(m.network, kh, *bm.Ptr, m.level)
+	if err != nil {
+		if m.storage != nil && m.storage.IsRecordNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if diff.Value == "" {
+		return nil, nil
+	}
+	return ParseData(gjson.Parse(diff.Value), bm.ValueType)
+}
+
+// commit - collects changes of big maps in `value` and replaces them by pointers.
+// Big maps created during execution get temporary negative pointers.
+func (m *machine) commit(value Value, diffs []BigMapDiff) (Value, []BigMapDiff, error) {
+	switch v := value.(type) {
+	case *BigMap:
+		ptr := m.temporaryPtr
+		if v.Ptr != nil {
+			ptr = *v.Ptr
+		} else {
+			m.temporaryPtr--
+		}
+		for i := range v.Updates {
+			kh, err := keyHash(v.Updates[i].Key)
+			if err != nil {
+				return nil, nil, err
+			}
+			diff := BigMapDiff{
+				Ptr:     ptr,
+				KeyHash: kh,
+				Key:     v.Updates[i].Key.Micheline(false),
+			}
+			if item := v.Updates[i].Value.(Option).Value; item != nil {
+				diff.Value = item.Micheline(false)
+			}
+			diffs = append(diffs, diff)
+		}
+		return &BigMap{Ptr: &ptr, KeyType: v.KeyType, ValueType: v.ValueType}, diffs, nil
+	case Pair:
+		left, diffs, err := m.commit(v.Left, diffs)
+		if err != nil {
+			return nil, nil, err
+		}
+		right, diffs, err := m.commit(v.Right, diffs)
+		if err != nil {
+			return nil, nil, err
+		}
+		return Pair{Left: left, Right: right}, diffs, nil
+	case Option:
+		if v.Value == nil {
+			return v, diffs, nil
+		}
+		item, diffs, err := m.commit(v.Value, diffs)
+		if err != nil {
+			return nil, nil, err
+		}
+		return Option{Value: item}, diffs, nil
+	case Or:
+		item, diffs, err := m.commit(v.Value, diffs)
+		if err != nil {
+			return nil, nil, err
+		}
+		return Or{Left: v.Left, Value: item}, diffs, nil
+	}
+	return value, diffs, nil
+}
@@ -0,0 +1,105 @@
+package interpreter
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/pack"
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
+	"github.com/baking-bad/bcdhub/internal/contractparser/unpack/rawbytes"
+	"github.com/baking-bad/bcdhub/internal/tzbase58"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+// key hash prefixes by curve tag of public key
+var keyHashPrefixes = map[byte][]byte{
+	0x00: {6, 161, 159},
+	0x01: {6, 161, 161},
+	0x02: {6, 161, 164},
+}
+
+func packValue(value Value) ([]byte, error) {
+	data, err := json.Marshal(value.Micheline(true))
+	if err != nil {
+		return nil, err
+	}
+	return pack.Micheline(gjson.ParseBytes(data))
+}
+
+// unpackValue - returns `None` if `data` is not packed value of type `typ`
+func unpackValue(data Bytes, typ *typechecker.Type) Value {
+	if len(data) == 0 || data[0] != 0x05 {
+		return Option{}
+	}
+	micheline, err := rawbytes.ToMicheline(hex.EncodeToString(data[1:]))
+	if err != nil {
+		return Option{}
+	}
+	node := gjson.Parse(micheline)
+	if err := typechecker.CheckData(node, typ); err != nil {
+		return Option{}
+	}
+	value, err := ParseData(node, typ)
+	if err != nil {
+		return Option{}
+	}
+	return Option{Value: value}
+}
+
+func hashBytes(prim string, data []byte) []byte {
+	switch prim {
+	case "BLAKE2B":
+		sum := blake2b.Sum256(data)
+		return sum[:]
+	case "SHA256":
+		sum := sha256.Sum256(data)
+		return sum[:]
+	case "SHA512":
+		sum := sha512.Sum512(data)
+		return sum[:]
+	case "KECCAK":
+		h := sha3.NewLegacyKeccak256()
+		h.Write(data)
+		return h.Sum(nil)
+	default:
+		sum := sha3.Sum256(data)
+		return sum[:]
+	}
+}
+
+func hashKey(key string) (string, error) {
+	data, err := encodeLiteral(consts.KEY, key)
+	if err != nil {
+		return "", err
+	}
+	h, err := blake2b.New(20, nil)
+	if err != nil {
+		return "", err
+	}
+	h.Write(data[1:])
+	return tzbase58.EncodeFromBytes(h.Sum(nil), keyHashPrefixes[data[0]]), nil
+}
+
+// checkSignature - verifies signature of blake2b hash of `message`. Only ed25519 keys are supported.
+func checkSignature(key, signature string, message []byte) (bool, error) {
+	keyData, err := encodeLiteral(consts.KEY, key)
+	if err != nil {
+		return false, err
+	}
+	if keyData[0] != 0x00 {
+		return false, errors.Errorf("signatures of %s keys can not be checked", key[:4])
+	}
+	sigData, err := encodeLiteral(consts.SIGNATURE, signature)
+	if err != nil {
+		return false, err
+	}
+	digest := blake2b.Sum256(message)
+	return ed25519.Verify(ed25519.PublicKey(keyData[1:]), digest[:], sigData), nil
+}
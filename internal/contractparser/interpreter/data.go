@@ -0,0 +1,188 @@
+package interpreter
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// ParseData - converts Micheline value of type `typ` to runtime value. Big maps are referenced by pointers.
+// The value has to be type checked by `typechecker.CheckData` before.
+func ParseData(node gjson.Result, typ *typechecker.Type) (Value, error) {
+	switch typ.Prim {
+	case consts.INT, consts.NAT, consts.MUTEZ:
+		value, err := parseInt(node)
+		if err != nil {
+			return nil, err
+		}
+		return Int{Prim: typ.Prim, Value: value}, nil
+	case consts.TIMESTAMP:
+		if node.Get(consts.KeyString).Exists() {
+			ts, err := time.Parse(time.RFC3339, node.Get(consts.KeyString).String())
+			if err != nil {
+				return nil, err
+			}
+			return Int{Prim: typ.Prim, Value: big.NewInt(ts.Unix())}, nil
+		}
+		value, err := parseInt(node)
+		if err != nil {
+			return nil, err
+		}
+		return Int{Prim: typ.Prim, Value: value}, nil
+	case consts.STRING:
+		return String{Prim: typ.Prim, Value: node.Get(consts.KeyString).String()}, nil
+	case consts.ADDRESS, consts.KEYHASH, consts.KEY, consts.SIGNATURE, consts.CHAINID:
+		value, err := parseLiteral(node, typ.Prim)
+		if err != nil {
+			return nil, err
+		}
+		return String{Prim: typ.Prim, Value: value}, nil
+	case consts.CONTRACT:
+		value, err := parseLiteral(node, consts.ADDRESS)
+		if err != nil {
+			return nil, err
+		}
+		address, entrypoint := value, consts.DefaultEntrypoint
+		if idx := strings.IndexByte(value, '%'); idx > -1 {
+			address, entrypoint = value[:idx], value[idx+1:]
+		}
+		return Contract{Address: address, Entrypoint: entrypoint, Type: typ.Args[0]}, nil
+	case consts.BYTES:
+		data, err := hex.DecodeString(node.Get(consts.KeyBytes).String())
+		if err != nil {
+			return nil, err
+		}
+		return Bytes(data), nil
+	case consts.BOOL:
+		return Bool(node.Get(consts.KeyPrim).String() == "True"), nil
+	case consts.UNIT:
+		return Unit{}, nil
+	case consts.OPTION:
+		if node.Get(consts.KeyPrim).String() == consts.None {
+			return Option{}, nil
+		}
+		value, err := ParseData(node.Get("args.0"), typ.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		return Option{Value: value}, nil
+	case consts.OR:
+		left := node.Get(consts.KeyPrim).String() == consts.Left
+		argType := typ.Args[1]
+		if left {
+			argType = typ.Args[0]
+		}
+		value, err := ParseData(node.Get("args.0"), argType)
+		if err != nil {
+			return nil, err
+		}
+		return Or{Left: left, Value: value}, nil
+	case consts.PAIR:
+		var items []gjson.Result
+		if node.IsArray() {
+			items = node.Array()
+		} else {
+			items = node.Get(consts.KeyArgs).Array()
+		}
+		return parseComb(items, typ)
+	case consts.LIST, consts.SET:
+		items := node.Array()
+		values := make([]Value, len(items))
+		for i := range items {
+			value, err := ParseData(items[i], typ.Args[0])
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		if typ.Prim == consts.SET {
+			return sortSet(values), nil
+		}
+		return List(values), nil
+	case consts.MAP, consts.BIGMAP:
+		if typ.Prim == consts.BIGMAP && node.Get(consts.KeyInt).Exists() {
+			ptr := node.Get(consts.KeyInt).Int()
+			return &BigMap{Ptr: &ptr, KeyType: typ.Args[0], ValueType: typ.Args[1]}, nil
+		}
+		items := node.Array()
+		elts := make([]Elt, len(items))
+		for i := range items {
+			key, err := ParseData(items[i].Get("args.0"), typ.Args[0])
+			if err != nil {
+				return nil, err
+			}
+			value, err := ParseData(items[i].Get("args.1"), typ.Args[1])
+			if err != nil {
+				return nil, err
+			}
+			if typ.Prim == consts.BIGMAP {
+				value = Option{Value: value}
+			}
+			elts[i] = Elt{Key: key, Value: value}
+		}
+		if typ.Prim == consts.BIGMAP {
+			return &BigMap{KeyType: typ.Args[0], ValueType: typ.Args[1], Updates: sortMap(elts)}, nil
+		}
+		return sortMap(elts), nil
+	case consts.LAMBDA:
+		return Lambda{Type: typ, Code: node}, nil
+	case consts.TICKET:
+		value, err := ParseData(node, typechecker.NewType(consts.PAIR,
+			typechecker.NewType(consts.ADDRESS),
+			typechecker.NewType(consts.PAIR, typ.Args[0], typechecker.NewType(consts.NAT)),
+		))
+		if err != nil {
+			return nil, err
+		}
+		pair := value.(Pair)
+		inner := pair.Right.(Pair)
+		return Ticket{Ticketer: pair.Left.(String).Value, Value: inner.Left, Amount: inner.Right.(Int).Value}, nil
+	}
+	return nil, errors.Errorf("values of type %s are not supported", typ)
+}
+
+func parseComb(items []gjson.Result, typ *typechecker.Type) (Value, error) {
+	if len(items) < 2 || typ.Prim != consts.PAIR {
+		return nil, errors.Errorf("invalid pair of type %s", typ)
+	}
+	left, err := ParseData(items[0], typ.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	var right Value
+	if len(items) == 2 {
+		right, err = ParseData(items[1], typ.Args[1])
+	} else {
+		right, err = parseComb(items[1:], typ.Args[1])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Pair{Left: left, Right: right}, nil
+}
+
+func parseInt(node gjson.Result) (*big.Int, error) {
+	value, ok := new(big.Int).SetString(node.Get(consts.KeyInt).String(), 10)
+	if !ok {
+		return nil, errors.Errorf("invalid integer: %s", node.Raw)
+	}
+	return value, nil
+}
+
+// parseLiteral - returns readable form of literal which may be in readable or optimized form
+func parseLiteral(node gjson.Result, typ string) (string, error) {
+	if !node.Get(consts.KeyBytes).Exists() {
+		return node.Get(consts.KeyString).String(), nil
+	}
+	data, err := hex.DecodeString(node.Get(consts.KeyBytes).String())
+	if err != nil {
+		return "", err
+	}
+	return decodeLiteral(typ, data)
+}
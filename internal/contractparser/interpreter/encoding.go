@@ -0,0 +1,114 @@
+package interpreter
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/pack"
+	"github.com/baking-bad/bcdhub/internal/contractparser/unpack"
+	"github.com/baking-bad/bcdhub/internal/tzbase58"
+	"github.com/pkg/errors"
+)
+
+type base58Prefix struct {
+	prefix string
+	tag    []byte
+	bytes  []byte
+}
+
+var (
+	keyPrefixes = []base58Prefix{
+		{"edpk", []byte{0x00}, []byte{13, 15, 37, 217}},
+		{"sppk", []byte{0x01}, []byte{3, 254, 226, 86}},
+		{"p2pk", []byte{0x02}, []byte{3, 178, 139, 127}},
+	}
+	signaturePrefixes = []base58Prefix{
+		{"edsig", nil, []byte{9, 245, 205, 134, 18}},
+		{"spsig1", nil, []byte{13, 115, 101, 19, 63}},
+		{"p2sig", nil, []byte{54, 240, 44, 52}},
+		{"sig", nil, []byte{4, 130, 43}},
+	}
+	chainIDPrefixes = []base58Prefix{
+		{"Net", nil, []byte{87, 82, 0}},
+	}
+)
+
+// encodeLiteral - returns binary (optimized) form of readable literal
+func encodeLiteral(typ, value string) ([]byte, error) {
+	switch typ {
+	case consts.ADDRESS, consts.CONTRACT:
+		address, entrypoint := value, ""
+		if idx := strings.IndexByte(value, '%'); idx > -1 {
+			address, entrypoint = value[:idx], value[idx+1:]
+		}
+		data, err := encodeAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		return append(data, []byte(entrypoint)...), nil
+	case consts.KEYHASH:
+		if strings.HasPrefix(value, "KT") {
+			return nil, errors.Errorf("invalid key hash: %s", value)
+		}
+		data, err := encodeAddress(value)
+		if err != nil {
+			return nil, err
+		}
+		return data[1:], nil
+	case consts.KEY:
+		return encodeBase58(value, keyPrefixes)
+	case consts.SIGNATURE:
+		return encodeBase58(value, signaturePrefixes)
+	case consts.CHAINID:
+		return encodeBase58(value, chainIDPrefixes)
+	}
+	return nil, errors.Errorf("%s has no binary form", typ)
+}
+
+// decodeLiteral - returns readable form of binary literal
+func decodeLiteral(typ string, data []byte) (string, error) {
+	value := hex.EncodeToString(data)
+	switch typ {
+	case consts.ADDRESS, consts.CONTRACT:
+		return unpack.Contract(value)
+	case consts.KEYHASH:
+		return unpack.KeyHash(value)
+	case consts.KEY:
+		return unpack.PublicKey(value)
+	case consts.SIGNATURE:
+		return unpack.Signature(value)
+	case consts.CHAINID:
+		return unpack.ChainID(value)
+	}
+	return "", errors.Errorf("%s has no binary form", typ)
+}
+
+func encodeAddress(address string) ([]byte, error) {
+	if len(address) < 3 {
+		return nil, errors.Errorf("invalid address: %s", address)
+	}
+	value, err := pack.Address(address)
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(value)
+}
+
+func encodeBase58(value string, prefixes []base58Prefix) ([]byte, error) {
+	for _, p := range prefixes {
+		if !strings.HasPrefix(value, p.prefix) {
+			continue
+		}
+		payload, err := tzbase58.DecodeToHex(value, p.bytes)
+		if err != nil {
+			return nil, err
+		}
+		data, err := hex.DecodeString(payload)
+		if err != nil {
+			return nil, err
+		}
+		return append(append([]byte{}, p.tag...), data...), nil
+	}
+	return nil, errors.Errorf("unknown prefix of %s", value)
+}
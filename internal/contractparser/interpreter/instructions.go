@@ -0,0 +1,772 @@
+package interpreter
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
+	"github.com/tidwall/gjson"
+)
+
+// maxShift - max shift of LSL and LSR instructions
+const maxShift = 256
+
+var maxMutez = new(big.Int).SetUint64(1<<63 - 1)
+
+// apply - executes single instruction. It returns `errUnknownInstruction` for macros.
+func (m *machine) apply(prim string, node gjson.Result, path string, stack []Value) ([]Value, error) {
+	args := node.Get(consts.KeyArgs).Array()
+
+	switch prim {
+	case "FAILWITH":
+		return nil, &FailedError{Location: m.location(path), With: stack[0].Micheline(false)}
+	case "NEVER":
+		return nil, m.errorf(path, "NEVER is unreachable")
+	case "DROP":
+		n := optionalIndex(args, 1)
+		return stack[n:], nil
+	case "DUP":
+		n := optionalIndex(args, 1)
+		return push(stack, stack[n-1]), nil
+	case "SWAP":
+		return push(stack[2:], stack[1], stack[0]), nil
+	case "DIG":
+		n := optionalIndex(args, 0)
+		result := make([]Value, 0, len(stack))
+		result = append(result, stack[n])
+		result = append(result, stack[:n]...)
+		return append(result, stack[n+1:]...), nil
+	case "DUG":
+		n := optionalIndex(args, 0)
+		result := make([]Value, 0, len(stack))
+		result = append(result, stack[1:n+1]...)
+		result = append(result, stack[0])
+		return append(result, stack[n+1:]...), nil
+	case "DIP":
+		n, code, codePath := 1, args[0], argPath(path, 0)
+		if len(args) == 2 {
+			n, code, codePath = int(args[0].Get(consts.KeyInt).Int()), args[1], argPath(path, 1)
+		}
+		result, err := m.execute(code, codePath, stack[n:])
+		if err != nil {
+			return nil, err
+		}
+		return append(append(make([]Value, 0, n+len(result)), stack[:n]...), result...), nil
+	case "PUSH":
+		typ, err := typechecker.ParseType(args[0], argPath(path, 0))
+		if err != nil {
+			return nil, err
+		}
+		value, err := ParseData(args[1], typ)
+		if err != nil {
+			return nil, err
+		}
+		if lambda, ok := value.(Lambda); ok {
+			lambda.Path = argPath(path, 1)
+			value = lambda
+		}
+		return push(stack, value), nil
+	case "LAMBDA":
+		input, err := typechecker.ParseType(args[0], argPath(path, 0))
+		if err != nil {
+			return nil, err
+		}
+		output, err := typechecker.ParseType(args[1], argPath(path, 1))
+		if err != nil {
+			return nil, err
+		}
+		return push(stack, Lambda{
+			Type: typechecker.NewType(consts.LAMBDA, input, output),
+			Code: args[2],
+			Path: argPath(path, 2),
+		}), nil
+	case "EXEC":
+		result, err := m.lambda(path, stack[1].(Lambda), stack[0])
+		if err != nil {
+			return nil, err
+		}
+		return push(stack[2:], result), nil
+	case "APPLY":
+		lambda := stack[1].(Lambda)
+		captured := make([]Value, 0, len(lambda.Captured)+1)
+		captured = append(captured, lambda.Captured...)
+		lambda.Captured = append(captured, stack[0])
+		return push(stack[2:], lambda), nil
+	case "CAST", "RENAME":
+		return stack, nil
+
+	case "UNIT":
+		return push(stack, Unit{}), nil
+	case "NOW":
+		return push(stack, Int{Prim: consts.TIMESTAMP, Value: big.NewInt(m.now.Unix())}), nil
+	case "LEVEL":
+		return push(stack, Int{Prim: consts.NAT, Value: big.NewInt(m.level)}), nil
+	case "AMOUNT":
+		return push(stack, Int{Prim: consts.MUTEZ, Value: big.NewInt(m.amount)}), nil
+	case "BALANCE":
+		return push(stack, Int{Prim: consts.MUTEZ, Value: big.NewInt(m.balance)}), nil
+	case "SOURCE":
+		return push(stack, String{Prim: consts.ADDRESS, Value: m.source}), nil
+	case "SENDER":
+		return push(stack, String{Prim: consts.ADDRESS, Value: m.sender}), nil
+	case "SELF_ADDRESS":
+		return push(stack, String{Prim: consts.ADDRESS, Value: m.self}), nil
+	case "CHAIN_ID":
+		return push(stack, String{Prim: consts.CHAINID, Value: m.chainID}), nil
+	case "TOTAL_VOTING_POWER":
+		var total int64
+		for _, power := range m.votingPowers {
+			total += power
+		}
+		return push(stack, Int{Prim: consts.NAT, Value: big.NewInt(total)}), nil
+	case "VOTING_POWER":
+		power := m.votingPowers[stack[0].(String).Value]
+		return push(stack[1:], Int{Prim: consts.NAT, Value: big.NewInt(power)}), nil
+
+	case "SOME":
+		return push(stack[1:], Option{Value: stack[0]}), nil
+	case "NONE":
+		return push(stack, Option{}), nil
+	case "LEFT":
+		return push(stack[1:], Or{Left: true, Value: stack[0]}), nil
+	case "RIGHT":
+		return push(stack[1:], Or{Value: stack[0]}), nil
+	case "NIL":
+		return push(stack, List{}), nil
+	case "EMPTY_SET":
+		return push(stack, Set{}), nil
+	case "EMPTY_MAP":
+		return push(stack, Map{}), nil
+	case "EMPTY_BIG_MAP":
+		keyType, err := typechecker.ParseType(args[0], argPath(path, 0))
+		if err != nil {
+			return nil, err
+		}
+		valueType, err := typechecker.ParseType(args[1], argPath(path, 1))
+		if err != nil {
+			return nil, err
+		}
+		return push(stack, &BigMap{KeyType: keyType, ValueType: valueType, Updates: Map{}}), nil
+	case "CONS":
+		list := stack[1].(List)
+		result := make(List, 0, len(list)+1)
+		result = append(result, stack[0])
+		return push(stack[2:], append(result, list...)), nil
+
+	case "PAIR":
+		n := optionalIndex(args, 2)
+		value := stack[n-1]
+		for i := n - 2; i >= 0; i-- {
+			value = Pair{Left: stack[i], Right: value}
+		}
+		return push(stack[n:], value), nil
+	case "UNPAIR":
+		n := optionalIndex(args, 2)
+		items := make([]Value, 0, n)
+		value := stack[0]
+		for i := 0; i < n-1; i++ {
+			pair := value.(Pair)
+			items = append(items, pair.Left)
+			value = pair.Right
+		}
+		return push(stack[1:], append(items, value)...), nil
+	case "CAR":
+		return push(stack[1:], stack[0].(Pair).Left), nil
+	case "CDR":
+		return push(stack[1:], stack[0].(Pair).Right), nil
+
+	case "IF":
+		if stack[0].(Bool) {
+			return m.execute(args[0], argPath(path, 0), stack[1:])
+		}
+		return m.execute(args[1], argPath(path, 1), stack[1:])
+	case "IF_NONE":
+		if value := stack[0].(Option).Value; value != nil {
+			return m.execute(args[1], argPath(path, 1), push(stack[1:], value))
+		}
+		return m.execute(args[0], argPath(path, 0), stack[1:])
+	case "IF_LEFT":
+		or := stack[0].(Or)
+		if or.Left {
+			return m.execute(args[0], argPath(path, 0), push(stack[1:], or.Value))
+		}
+		return m.execute(args[1], argPath(path, 1), push(stack[1:], or.Value))
+	case "IF_CONS":
+		list := stack[0].(List)
+		if len(list) > 0 {
+			return m.execute(args[0], argPath(path, 0), push(stack[1:], list[0], list[1:]))
+		}
+		return m.execute(args[1], argPath(path, 1), stack[1:])
+	case "LOOP":
+		for stack[0].(Bool) {
+			var err error
+			if stack, err = m.execute(args[0], argPath(path, 0), stack[1:]); err != nil {
+				return nil, err
+			}
+		}
+		return stack[1:], nil
+	case "LOOP_LEFT":
+		for {
+			or := stack[0].(Or)
+			if !or.Left {
+				return push(stack[1:], or.Value), nil
+			}
+			var err error
+			if stack, err = m.execute(args[0], argPath(path, 0), push(stack[1:], or.Value)); err != nil {
+				return nil, err
+			}
+		}
+	case "ITER":
+		items, rest := elements(stack[0]), stack[1:]
+		for i := range items {
+			var err error
+			if rest, err = m.execute(args[0], argPath(path, 0), push(rest, items[i])); err != nil {
+				return nil, err
+			}
+		}
+		return rest, nil
+	case "MAP":
+		return m.mapInstruction(args[0], argPath(path, 0), stack)
+
+	case "COMPARE":
+		return push(stack[2:], Int{Prim: consts.INT, Value: big.NewInt(int64(Compare(stack[0], stack[1])))}), nil
+	case "EQ", "NEQ", "LT", "GT", "LE", "GE":
+		return push(stack[1:], Bool(compareResult(prim, stack[0].(Int).Value.Sign()))), nil
+	case "SIZE":
+		return push(stack[1:], nat(int64(size(stack[0])))), nil
+	case "CONCAT":
+		return m.concat(path, stack)
+	case "SLICE":
+		return push(stack[3:], slice(stack[0].(Int).Value, stack[1].(Int).Value, stack[2])), nil
+	case "MEM", "GET", "UPDATE", "GET_AND_UPDATE":
+		if len(args) == 1 && (prim == "GET" || prim == "UPDATE") {
+			n := int(args[0].Get(consts.KeyInt).Int())
+			if prim == "GET" {
+				return push(stack[1:], getComb(stack[0], n)), nil
+			}
+			return push(stack[2:], updateComb(stack[1], n, stack[0])), nil
+		}
+		return m.collectionAccess(path, prim, stack)
+
+	case "ADD", "SUB", "MUL", "EDIV", "LSL", "LSR", "OR", "AND", "XOR":
+		result, err := m.arithmetic(path, prim, stack[0], stack[1])
+		if err != nil {
+			return nil, err
+		}
+		return push(stack[2:], result), nil
+	case "ABS", "ISNAT", "INT", "NEG", "NOT":
+		return push(stack[1:], unaryOperation(prim, stack[0])), nil
+
+	case "PACK":
+		data, err := packValue(stack[0])
+		if err != nil {
+			return nil, m.errorf(path, "%s", err.Error())
+		}
+		return push(stack[1:], Bytes(data)), nil
+	case "UNPACK":
+		typ, err := typechecker.ParseType(args[0], argPath(path, 0))
+		if err != nil {
+			return nil, err
+		}
+		return push(stack[1:], unpackValue(stack[0].(Bytes), typ)), nil
+	case "BLAKE2B", "SHA256", "SHA512", "KECCAK", "SHA3":
+		return push(stack[1:], Bytes(hashBytes(prim, stack[0].(Bytes)))), nil
+	case "HASH_KEY":
+		value, err := hashKey(stack[0].(String).Value)
+		if err != nil {
+			return nil, m.errorf(path, "%s", err.Error())
+		}
+		return push(stack[1:], String{Prim: consts.KEYHASH, Value: value}), nil
+	case "CHECK_SIGNATURE":
+		ok, err := checkSignature(stack[0].(String).Value, stack[1].(String).Value, stack[2].(Bytes))
+		if err != nil {
+			return nil, m.errorf(path, "%s", err.Error())
+		}
+		return push(stack[3:], Bool(ok)), nil
+
+	case "SELF":
+		entrypoint := entrypointAnnot(node)
+		typ, _, ok := entrypointPath(m.selfType, entrypoint)
+		if !ok {
+			return nil, m.errorf(path, "unknown entrypoint %s", entrypoint)
+		}
+		return push(stack, Contract{Address: m.self, Entrypoint: entrypoint, Type: typ}), nil
+	case "CONTRACT":
+		typ, err := typechecker.ParseType(args[0], argPath(path, 0))
+		if err != nil {
+			return nil, err
+		}
+		contract, err := m.contract(stack[0].(String).Value, entrypointAnnot(node), typ)
+		if err != nil {
+			return nil, m.errorf(path, "%s", err.Error())
+		}
+		return push(stack[1:], contract), nil
+	case "ADDRESS":
+		contract := stack[0].(Contract)
+		address := contract.Address
+		if contract.Entrypoint != consts.DefaultEntrypoint {
+			address += "%" + contract.Entrypoint
+		}
+		return push(stack[1:], String{Prim: consts.ADDRESS, Value: address}), nil
+	case "IMPLICIT_ACCOUNT":
+		return push(stack[1:], Contract{
+			Address:    stack[0].(String).Value,
+			Entrypoint: consts.DefaultEntrypoint,
+			Type:       typechecker.NewType(consts.UNIT),
+		}), nil
+	case "TRANSFER_TOKENS":
+		contract := stack[2].(Contract)
+		return push(stack[3:], m.operation(Operation{
+			Kind:        consts.Transaction,
+			Amount:      stack[1].(Int).Value.String(),
+			Destination: contract.Address,
+			Parameters: &OperationParameters{
+				Entrypoint: contract.Entrypoint,
+				Value:      stack[0].Micheline(false),
+			},
+		})), nil
+	case "SET_DELEGATE":
+		op := Operation{
+			Kind: consts.Delegation,
+		}
+		if delegate := stack[0].(Option).Value; delegate != nil {
+			op.Delegate = delegate.(String).Value
+		}
+		return push(stack[1:], m.operation(op)), nil
+	case "CREATE_CONTRACT":
+		op := Operation{
+			Kind:    consts.Origination,
+			Balance: stack[1].(Int).Value.String(),
+			Script: &OperationScript{
+				Code:    args[0].Value(),
+				Storage: stack[2].Micheline(false),
+			},
+		}
+		if delegate := stack[0].(Option).Value; delegate != nil {
+			op.Delegate = delegate.(String).Value
+		}
+		op = m.operation(op).(Operation)
+		op.Originated = originatedAddress(m.self, op.Nonce)
+		return push(stack[3:], op, String{Prim: consts.ADDRESS, Value: op.Originated}), nil
+
+	case "TICKET":
+		return push(stack[2:], Ticket{Ticketer: m.self, Value: stack[0], Amount: stack[1].(Int).Value}), nil
+	case "READ_TICKET":
+		ticket := stack[0].(Ticket)
+		return push(stack[1:], Pair{
+			Left:  String{Prim: consts.ADDRESS, Value: ticket.Ticketer},
+			Right: Pair{Left: ticket.Value, Right: Int{Prim: consts.NAT, Value: ticket.Amount}},
+		}, ticket), nil
+	case "SPLIT_TICKET":
+		return push(stack[2:], splitTicket(stack[0].(Ticket), stack[1].(Pair))), nil
+	case "JOIN_TICKETS":
+		return push(stack[1:], joinTickets(stack[0].(Pair))), nil
+
+	case "SAPLING_EMPTY_STATE", "SAPLING_VERIFY_UPDATE", "PAIRING_CHECK":
+		return nil, m.errorf(path, "%s is not supported", prim)
+	}
+	return nil, errUnknownInstruction
+}
+
+func (m *machine) mapInstruction(code gjson.Result, path string, stack []Value) ([]Value, error) {
+	rest := stack[1:]
+	switch collection := stack[0].(type) {
+	case List:
+		result := make(List, len(collection))
+		for i := range collection {
+			output, err := m.execute(code, path, push(rest, collection[i]))
+			if err != nil {
+				return nil, err
+			}
+			result[i], rest = output[0], output[1:]
+		}
+		return push(rest, result), nil
+	case Map:
+		result := make(Map, len(collection))
+		for i := range collection {
+			output, err := m.execute(code, path, push(rest, Pair{Left: collection[i].Key, Right: collection[i].Value}))
+			if err != nil {
+				return nil, err
+			}
+			result[i], rest = Elt{Key: collection[i].Key, Value: output[0]}, output[1:]
+		}
+		return push(rest, result), nil
+	}
+	return nil, m.errorf(path, "MAP is not defined for %T", stack[0])
+}
+
+func (m *machine) concat(path string, stack []Value) ([]Value, error) {
+	switch first := stack[0].(type) {
+	case String:
+		return push(stack[2:], String{Prim: consts.STRING, Value: first.Value + stack[1].(String).Value}), nil
+	case Bytes:
+		result := append(append(Bytes{}, first...), stack[1].(Bytes)...)
+		return push(stack[2:], result), nil
+	case List:
+		bytesList := len(first) > 0 && isBytes(first[0])
+		if len(first) == 0 {
+			if types, ok := m.stacks[path]; ok {
+				bytesList = types[0].Args[0].Prim == consts.BYTES
+			}
+		}
+		if bytesList {
+			result := Bytes{}
+			for i := range first {
+				result = append(result, first[i].(Bytes)...)
+			}
+			return push(stack[1:], result), nil
+		}
+		var result string
+		for i := range first {
+			result += first[i].(String).Value
+		}
+		return push(stack[1:], String{Prim: consts.STRING, Value: result}), nil
+	}
+	return nil, m.errorf(path, "CONCAT is not defined for %T", stack[0])
+}
+
+func (m *machine) collectionAccess(path, prim string, stack []Value) ([]Value, error) {
+	key, collection, rest := stack[0], stack[1], stack[2:]
+	var update Value
+	if prim == "UPDATE" || prim == "GET_AND_UPDATE" {
+		update, collection, rest = stack[1], stack[2], stack[3:]
+	}
+
+	switch c := collection.(type) {
+	case Set:
+		if prim == "MEM" {
+			return push(rest, Bool(c.mem(key))), nil
+		}
+		return push(rest, c.update(key, bool(update.(Bool)))), nil
+	case Map:
+		value, ok := c.get(key)
+		switch prim {
+		case "MEM":
+			return push(rest, Bool(ok)), nil
+		case "GET":
+			return push(rest, Option{Value: value}), nil
+		case "UPDATE":
+			return push(rest, c.update(key, update.(Option).Value)), nil
+		default:
+			return push(rest, Option{Value: value}, c.update(key, update.(Option).Value)), nil
+		}
+	case *BigMap:
+		if prim == "UPDATE" {
+			return push(rest, c.update(key, update.(Option).Value)), nil
+		}
+		value, err := m.bigMapGet(c, key)
+		if err != nil {
+			return nil, m.errorf(path, "%s", err.Error())
+		}
+		switch prim {
+		case "MEM":
+			return push(rest, Bool(value != nil)), nil
+		case "GET":
+			return push(rest, Option{Value: value}), nil
+		default:
+			return push(rest, Option{Value: value}, c.update(key, update.(Option).Value)), nil
+		}
+	}
+	return nil, m.errorf(path, "%s is not defined for %T", prim, collection)
+}
+
+func (m *machine) operation(op Operation) Value {
+	op.Source = m.self
+	op.Nonce = m.nonce
+	m.nonce++
+	return op
+}
+
+// contract - returns `Some` contract of type `typ` at `address` or `None` if there is no such contract
+func (m *machine) contract(address, entrypoint string, typ *typechecker.Type) (Option, error) {
+	if idx := strings.IndexByte(address, '%'); idx > -1 {
+		if entrypoint != consts.DefaultEntrypoint {
+			return Option{}, nil
+		}
+		address, entrypoint = address[:idx], address[idx+1:]
+	}
+
+	var paramType *typechecker.Type
+	switch {
+	case address == m.self:
+		paramType = m.selfType
+	case strings.HasPrefix(address, "tz"):
+		if entrypoint != consts.DefaultEntrypoint || typ.Prim != consts.UNIT {
+			return Option{}, nil
+		}
+		return Option{Value: Contract{Address: address, Entrypoint: entrypoint, Type: typ}}, nil
+	case m.contracts != nil:
+		var err error
+		if paramType, err = m.contracts.Parameter(address); err != nil {
+			return Option{}, err
+		}
+		if paramType == nil {
+			return Option{}, nil
+		}
+	default:
+		return Option{Value: Contract{Address: address, Entrypoint: entrypoint, Type: typ}}, nil
+	}
+
+	entrypointType, _, ok := entrypointPath(paramType, entrypoint)
+	if !ok || !entrypointType.Equal(typ) {
+		return Option{}, nil
+	}
+	return Option{Value: Contract{Address: address, Entrypoint: entrypoint, Type: typ}}, nil
+}
+
+func (m *machine) arithmetic(path, prim string, a, b Value) (Value, error) {
+	if x, ok := a.(Bool); ok {
+		y := b.(Bool)
+		switch prim {
+		case "OR":
+			return x || y, nil
+		case "AND":
+			return x && y, nil
+		default:
+			return Bool(x != y), nil
+		}
+	}
+
+	x, y := a.(Int), b.(Int)
+	result := new(big.Int)
+	switch prim {
+	case "ADD":
+		result.Add(x.Value, y.Value)
+	case "SUB":
+		result.Sub(x.Value, y.Value)
+	case "MUL":
+		result.Mul(x.Value, y.Value)
+	case "EDIV":
+		return ediv(x, y), nil
+	case "LSL", "LSR":
+		if y.Value.Cmp(big.NewInt(maxShift)) > 0 {
+			return nil, m.errorf(path, "shift overflow: %s", y.Value)
+		}
+		if prim == "LSL" {
+			result.Lsh(x.Value, uint(y.Value.Uint64()))
+		} else {
+			result.Rsh(x.Value, uint(y.Value.Uint64()))
+		}
+	case "OR":
+		result.Or(x.Value, y.Value)
+	case "AND":
+		result.And(x.Value, y.Value)
+	case "XOR":
+		result.Xor(x.Value, y.Value)
+	}
+
+	value := Int{Prim: arithmeticPrim(prim, x.Prim, y.Prim), Value: result}
+	if value.Prim == consts.MUTEZ && (result.Sign() < 0 || result.Cmp(maxMutez) > 0) {
+		return nil, m.errorf(path, "mutez overflow: %s", result)
+	}
+	return value, nil
+}
+
+// arithmeticPrim - returns type of result of arithmetic instruction
+func arithmeticPrim(prim, a, b string) string {
+	switch {
+	case prim == "SUB" && a == consts.TIMESTAMP && b == consts.TIMESTAMP:
+		return consts.INT
+	case a == consts.TIMESTAMP || b == consts.TIMESTAMP:
+		return consts.TIMESTAMP
+	case a == consts.MUTEZ || b == consts.MUTEZ:
+		return consts.MUTEZ
+	case prim == "AND" && b == consts.NAT:
+		return consts.NAT
+	case prim != "SUB" && a == consts.NAT && b == consts.NAT:
+		return consts.NAT
+	default:
+		return consts.INT
+	}
+}
+
+func ediv(x, y Int) Value {
+	if y.Value.Sign() == 0 {
+		return Option{}
+	}
+	q, r := new(big.Int).DivMod(x.Value, y.Value, new(big.Int))
+	quotient, remainder := consts.INT, consts.NAT
+	switch {
+	case x.Prim == consts.NAT && y.Prim == consts.NAT:
+		quotient = consts.NAT
+	case x.Prim == consts.MUTEZ && y.Prim == consts.NAT:
+		quotient, remainder = consts.MUTEZ, consts.MUTEZ
+	case x.Prim == consts.MUTEZ && y.Prim == consts.MUTEZ:
+		quotient, remainder = consts.NAT, consts.MUTEZ
+	}
+	return Option{Value: Pair{
+		Left:  Int{Prim: quotient, Value: q},
+		Right: Int{Prim: remainder, Value: r},
+	}}
+}
+
+func unaryOperation(prim string, value Value) Value {
+	if b, ok := value.(Bool); ok {
+		return !b
+	}
+	x := value.(Int).Value
+	switch prim {
+	case "ABS":
+		return Int{Prim: consts.NAT, Value: new(big.Int).Abs(x)}
+	case "ISNAT":
+		if x.Sign() < 0 {
+			return Option{}
+		}
+		return Option{Value: Int{Prim: consts.NAT, Value: x}}
+	case "INT":
+		return Int{Prim: consts.INT, Value: x}
+	case "NEG":
+		return Int{Prim: consts.INT, Value: new(big.Int).Neg(x)}
+	default:
+		return Int{Prim: consts.INT, Value: new(big.Int).Not(x)}
+	}
+}
+
+func compareResult(prim string, sign int) bool {
+	switch prim {
+	case "EQ":
+		return sign == 0
+	case "NEQ":
+		return sign != 0
+	case "LT":
+		return sign < 0
+	case "GT":
+		return sign > 0
+	case "LE":
+		return sign <= 0
+	default:
+		return sign >= 0
+	}
+}
+
+func size(value Value) int {
+	switch v := value.(type) {
+	case String:
+		return len(v.Value)
+	case Bytes:
+		return len(v)
+	case List:
+		return len(v)
+	case Set:
+		return len(v)
+	case Map:
+		return len(v)
+	}
+	return 0
+}
+
+func slice(offset, length *big.Int, value Value) Value {
+	total := big.NewInt(int64(size(value)))
+	if new(big.Int).Add(offset, length).Cmp(total) > 0 {
+		return Option{}
+	}
+	from, to := offset.Int64(), offset.Int64()+length.Int64()
+	switch v := value.(type) {
+	case String:
+		return Option{Value: String{Prim: consts.STRING, Value: v.Value[from:to]}}
+	case Bytes:
+		return Option{Value: append(Bytes{}, v[from:to]...)}
+	}
+	return Option{}
+}
+
+// elements - returns items iterated by ITER. Map entries are iterated as pairs of key and value.
+func elements(value Value) []Value {
+	switch v := value.(type) {
+	case List:
+		return v
+	case Set:
+		return v
+	case Map:
+		result := make([]Value, len(v))
+		for i := range v {
+			result[i] = Pair{Left: v[i].Key, Right: v[i].Value}
+		}
+		return result
+	}
+	return nil
+}
+
+// getComb - returns `n`-th node of right comb: odd indices are left items, even are tails
+func getComb(value Value, n int) Value {
+	for ; n > 1; n -= 2 {
+		value = value.(Pair).Right
+	}
+	if n == 1 {
+		return value.(Pair).Left
+	}
+	return value
+}
+
+// updateComb - replaces `n`-th node of right comb by `item`
+func updateComb(value Value, n int, item Value) Value {
+	switch {
+	case n == 0:
+		return item
+	case n == 1:
+		return Pair{Left: item, Right: value.(Pair).Right}
+	default:
+		pair := value.(Pair)
+		return Pair{Left: pair.Left, Right: updateComb(pair.Right, n-2, item)}
+	}
+}
+
+func splitTicket(ticket Ticket, amounts Pair) Value {
+	a, b := amounts.Left.(Int).Value, amounts.Right.(Int).Value
+	if a.Sign() == 0 || b.Sign() == 0 || new(big.Int).Add(a, b).Cmp(ticket.Amount) != 0 {
+		return Option{}
+	}
+	return Option{Value: Pair{
+		Left:  Ticket{Ticketer: ticket.Ticketer, Value: ticket.Value, Amount: a},
+		Right: Ticket{Ticketer: ticket.Ticketer, Value: ticket.Value, Amount: b},
+	}}
+}
+
+func joinTickets(tickets Pair) Value {
+	a, b := tickets.Left.(Ticket), tickets.Right.(Ticket)
+	if a.Ticketer != b.Ticketer || Compare(a.Value, b.Value) != 0 {
+		return Option{}
+	}
+	return Option{Value: Ticket{Ticketer: a.Ticketer, Value: a.Value, Amount: new(big.Int).Add(a.Amount, b.Amount)}}
+}
+
+func isBytes(value Value) bool {
+	_, ok := value.(Bytes)
+	return ok
+}
+
+func nat(value int64) Int {
+	return Int{Prim: consts.NAT, Value: big.NewInt(value)}
+}
+
+func push(stack []Value, values ...Value) []Value {
+	result := make([]Value, 0, len(stack)+len(values))
+	result = append(result, values...)
+	return append(result, stack...)
+}
+
+// optionalIndex - returns numeric argument of instruction or `def` if it's omitted
+func optionalIndex(args []gjson.Result, def int) int {
+	if len(args) == 0 {
+		return def
+	}
+	return int(args[0].Get(consts.KeyInt).Int())
+}
+
+func entrypointAnnot(node gjson.Result) string {
+	for _, annot := range node.Get(consts.KeyAnnots).Array() {
+		if s := annot.String(); len(s) > 1 && s[0] == '%' {
+			return s[1:]
+		}
+	}
+	return consts.DefaultEntrypoint
+}
+
+func argPath(path string, idx int) string {
+	if path == "" {
+		return fmt.Sprintf("args.%d", idx)
+	}
+	return fmt.Sprintf("%s.args.%d", path, idx)
+}
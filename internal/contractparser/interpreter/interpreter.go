@@ -0,0 +1,314 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// Default limits of execution
+const (
+	DefaultStepLimit  = 100000
+	DefaultTraceLimit = 10000
+)
+
+// deadlineCheckPeriod - count of steps between checks of execution deadline
+const deadlineCheckPeriod = 1024
+
+// ContractTypes - source of parameter types of originated contracts. It's used by CONTRACT instruction.
+type ContractTypes interface {
+	Parameter(address string) (*typechecker.Type, error)
+}
+
+// Interpreter - executes Michelson scripts in the chain context set by options
+type Interpreter struct {
+	network string
+	level   int64
+
+	self    string
+	source  string
+	sender  string
+	amount  int64
+	balance int64
+	chainID string
+	now     time.Time
+
+	votingPowers map[string]int64
+
+	bigMaps   bigmapdiff.Repository
+	storage   models.GeneralRepository
+	contracts ContractTypes
+
+	stepLimit  int64
+	traceLimit int64
+	timeout    time.Duration
+	withTrace  bool
+}
+
+// InterpreterOption -
+type InterpreterOption func(*Interpreter)
+
+// WithSelf - address of executed contract
+func WithSelf(address string) InterpreterOption {
+	return func(in *Interpreter) {
+		in.self = address
+	}
+}
+
+// WithSource - source of the operation
+func WithSource(address string) InterpreterOption {
+	return func(in *Interpreter) {
+		in.source = address
+	}
+}
+
+// WithSender - sender of the operation. It's equal to source if it's not set.
+func WithSender(address string) InterpreterOption {
+	return func(in *Interpreter) {
+		in.sender = address
+	}
+}
+
+// WithAmount - amount of the transaction in mutez
+func WithAmount(amount int64) InterpreterOption {
+	return func(in *Interpreter) {
+		in.amount = amount
+	}
+}
+
+// WithBalance - balance of executed contract in mutez including amount of the transaction
+func WithBalance(balance int64) InterpreterOption {
+	return func(in *Interpreter) {
+		in.balance = balance
+	}
+}
+
+// WithChainID -
+func WithChainID(chainID string) InterpreterOption {
+	return func(in *Interpreter) {
+		in.chainID = chainID
+	}
+}
+
+// WithNow - timestamp returned by NOW instruction
+func WithNow(now time.Time) InterpreterOption {
+	return func(in *Interpreter) {
+		in.now = now
+	}
+}
+
+// WithLevel - level returned by LEVEL instruction. Big maps are read at this level.
+func WithLevel(level int64) InterpreterOption {
+	return func(in *Interpreter) {
+		in.level = level
+	}
+}
+
+// WithVotingPowers - voting powers of bakers by key hashes
+func WithVotingPowers(powers map[string]int64) InterpreterOption {
+	return func(in *Interpreter) {
+		in.votingPowers = powers
+	}
+}
+
+// WithBigMaps - reads stored big maps of `network` from big map diffs repository
+func WithBigMaps(network string, repo bigmapdiff.Repository, storage models.GeneralRepository) InterpreterOption {
+	return func(in *Interpreter) {
+		in.network = network
+		in.bigMaps = repo
+		in.storage = storage
+	}
+}
+
+// WithContractTypes - checks types of contracts requested by CONTRACT instruction.
+// Without it every originated contract is considered to have requested type.
+func WithContractTypes(contracts ContractTypes) InterpreterOption {
+	return func(in *Interpreter) {
+		in.contracts = contracts
+	}
+}
+
+// WithStepLimit - max count of executed instructions
+func WithStepLimit(limit int64) InterpreterOption {
+	return func(in *Interpreter) {
+		if limit > 0 {
+			in.stepLimit = limit
+		}
+	}
+}
+
+// WithTrace - enables per-instruction stack trace. Trace is truncated after `limit` items (`DefaultTraceLimit` if limit is not positive).
+func WithTrace(limit int64) InterpreterOption {
+	return func(in *Interpreter) {
+		in.withTrace = true
+		if limit > 0 {
+			in.traceLimit = limit
+		}
+	}
+}
+
+// WithTimeout - max wall-clock time of execution
+func WithTimeout(timeout time.Duration) InterpreterOption {
+	return func(in *Interpreter) {
+		if timeout > 0 {
+			in.timeout = timeout
+		}
+	}
+}
+
+// New -
+func New(opts ...InterpreterOption) *Interpreter {
+	in := &Interpreter{
+		chainID:    "NetXdQprcVkpaWU",
+		self:       "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi",
+		now:        time.Now().UTC(),
+		stepLimit:  DefaultStepLimit,
+		traceLimit: DefaultTraceLimit,
+	}
+	for _, opt := range opts {
+		opt(in)
+	}
+	if in.sender == "" {
+		in.sender = in.source
+	}
+	return in
+}
+
+// Result - result of execution
+type Result struct {
+	Storage     interface{}  `json:"storage"`
+	Operations  []Operation  `json:"operations"`
+	BigMapDiffs []BigMapDiff `json:"big_map_diff"`
+	Trace       []TraceItem  `json:"trace,omitempty"`
+	Truncated   bool         `json:"truncated,omitempty"`
+	Steps       int64        `json:"steps"`
+}
+
+// TraceItem - stack after execution of instruction. Instructions of expanded macros have location of the macro.
+type TraceItem struct {
+	Location    string        `json:"location"`
+	Instruction string        `json:"instruction"`
+	Stack       []interface{} `json:"stack"`
+}
+
+// Run - executes `script` (array of `parameter`, `storage` and `code` sections) with `parameter` passed to `entrypoint` and `storage`.
+// If execution fails, result with trace and steps is returned along with `*FailedError` or `*RuntimeError`.
+func (in *Interpreter) Run(script gjson.Result, entrypoint string, parameter, storage gjson.Result) (*Result, error) {
+	checked, err := typechecker.Check(script)
+	if err != nil {
+		return nil, err
+	}
+	if entrypoint == "" {
+		entrypoint = consts.DefaultEntrypoint
+	}
+	paramType, branches, ok := entrypointPath(checked.Parameter, entrypoint)
+	if !ok {
+		return nil, errors.Errorf("unknown entrypoint: %s", entrypoint)
+	}
+	if err := typechecker.CheckData(parameter, paramType); err != nil {
+		return nil, errors.Wrap(err, "parameter")
+	}
+	if err := typechecker.CheckData(storage, checked.Storage); err != nil {
+		return nil, errors.Wrap(err, "storage")
+	}
+
+	paramValue, err := ParseData(parameter, paramType)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(branches) - 1; i >= 0; i-- {
+		paramValue = Or{Left: branches[i], Value: paramValue}
+	}
+	storageValue, err := ParseData(storage, checked.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &machine{
+		Interpreter:  in,
+		selfType:     checked.Parameter,
+		stacks:       checked.Stacks,
+		temporaryPtr: -1,
+	}
+	if in.timeout > 0 {
+		m.deadline = time.Now().Add(in.timeout)
+	}
+	stack, err := m.execute(checked.Code, checked.CodePath, []Value{Pair{Left: paramValue, Right: storageValue}})
+	result := &Result{
+		Trace:     m.trace,
+		Truncated: m.truncated,
+		Steps:     m.steps,
+	}
+	if err != nil {
+		return result, err
+	}
+
+	output := stack[0].(Pair)
+	newStorage, diffs, err := m.commit(output.Right, make([]BigMapDiff, 0))
+	if err != nil {
+		return result, err
+	}
+	result.Storage = newStorage.Micheline(false)
+	result.BigMapDiffs = diffs
+	result.Operations = make([]Operation, 0)
+	for _, op := range output.Left.(List) {
+		result.Operations = append(result.Operations, op.(Operation))
+	}
+	return result, nil
+}
+
+// entrypointPath - returns type of entrypoint and path to it in `or` tree of parameter (true is left branch)
+func entrypointPath(typ *typechecker.Type, name string) (*typechecker.Type, []bool, bool) {
+	if result, path, ok := searchEntrypoint(typ, name); ok {
+		return result, path, true
+	}
+	if name == consts.DefaultEntrypoint {
+		return typ, nil, true
+	}
+	return nil, nil, false
+}
+
+func searchEntrypoint(typ *typechecker.Type, name string) (*typechecker.Type, []bool, bool) {
+	if typ.FieldAnnot() == name {
+		return typ, nil, true
+	}
+	if typ.Prim != consts.OR {
+		return nil, nil, false
+	}
+	for i, left := range []bool{true, false} {
+		if result, path, ok := searchEntrypoint(typ.Args[i], name); ok {
+			return result, append([]bool{left}, path...), true
+		}
+	}
+	return nil, nil, false
+}
+
+// FailedError - script reached FAILWITH instruction
+type FailedError struct {
+	Location string
+	With     interface{}
+}
+
+// Error -
+func (e *FailedError) Error() string {
+	data, _ := json.Marshal(e.With)
+	return fmt.Sprintf("%s: script failed with %s", e.Location, data)
+}
+
+// RuntimeError - execution error: overflow, exceeded step limit or unsupported feature
+type RuntimeError struct {
+	Location string
+	Message  string
+}
+
+// Error -
+func (e *RuntimeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Location, e.Message)
+}
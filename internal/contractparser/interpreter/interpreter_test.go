@@ -0,0 +1,203 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
+	mock_bigmapdiff "github.com/baking-bad/bcdhub/internal/models/mock/bigmapdiff"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func script(parameter, storage, code string) gjson.Result {
+	return gjson.Parse(fmt.Sprintf(`[{"prim":"parameter","args":[%s]},{"prim":"storage","args":[%s]},{"prim":"code","args":[%s]}]`, parameter, storage, code))
+}
+
+func TestInterpreter_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		script      gjson.Result
+		entrypoint  string
+		parameter   string
+		storage     string
+		opts        []InterpreterOption
+		wantStorage string
+		wantErr     string
+	}{
+		{
+			name:        "add",
+			script:      script(`{"prim":"int"}`, `{"prim":"int"}`, `[{"prim":"UNPAIR"},{"prim":"ADD"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]`),
+			parameter:   `{"int":"2"}`,
+			storage:     `{"int":"1"}`,
+			wantStorage: `{"int":"3"}`,
+		}, {
+			name:        "entrypoint",
+			script:      script(`{"prim":"or","args":[{"prim":"int","annots":["%add"]},{"prim":"int","annots":["%sub"]}]}`, `{"prim":"int"}`, `[{"prim":"UNPAIR"},{"prim":"IF_LEFT","args":[[{"prim":"ADD"}],[{"prim":"SWAP"},{"prim":"SUB"}]]},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]`),
+			entrypoint:  "sub",
+			parameter:   `{"int":"3"}`,
+			storage:     `{"int":"10"}`,
+			wantStorage: `{"int":"7"}`,
+		}, {
+			name:      "failwith",
+			script:    script(`{"prim":"int"}`, `{"prim":"int"}`, `[{"prim":"UNPAIR"},{"prim":"DUP"},{"prim":"GT"},{"prim":"IF","args":[[{"prim":"ADD"}],[{"prim":"PUSH","args":[{"prim":"string"},{"string":"negative"}]},{"prim":"FAILWITH"}]]},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]`),
+			parameter: `{"int":"-1"}`,
+			storage:   `{"int":"1"}`,
+			wantErr:   `2.args.0.3.args.1.1: script failed with {"string":"negative"}`,
+		}, {
+			name:        "partially applied lambda",
+			script:      script(`{"prim":"unit"}`, `{"prim":"int"}`, `[{"prim":"CDR"},{"prim":"LAMBDA","args":[{"prim":"pair","args":[{"prim":"int"},{"prim":"int"}]},{"prim":"int"},[{"prim":"UNPAIR"},{"prim":"ADD"}]]},{"prim":"SWAP"},{"prim":"APPLY"},{"prim":"PUSH","args":[{"prim":"int"},{"int":"5"}]},{"prim":"EXEC"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]`),
+			parameter:   `{"prim":"Unit"}`,
+			storage:     `{"int":"3"}`,
+			wantStorage: `{"int":"8"}`,
+		}, {
+			name:        "pack",
+			script:      script(`{"prim":"unit"}`, `{"prim":"bytes"}`, `[{"prim":"DROP"},{"prim":"PUSH","args":[{"prim":"pair","args":[{"prim":"nat"},{"prim":"string"}]},{"prim":"Pair","args":[{"int":"1"},{"string":"a"}]}]},{"prim":"PACK"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]`),
+			parameter:   `{"prim":"Unit"}`,
+			storage:     `{"bytes":""}`,
+			wantStorage: `{"bytes":"0507070001010000000161"}`,
+		}, {
+			name:        "map and concat",
+			script:      script(`{"prim":"list","args":[{"prim":"string"}]}`, `{"prim":"string"}`, `[{"prim":"CAR"},{"prim":"MAP","args":[[{"prim":"PUSH","args":[{"prim":"string"},{"string":"-"}]},{"prim":"CONCAT"}]]},{"prim":"CONCAT"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]`),
+			parameter:   `[{"string":"a"},{"string":"b"}]`,
+			storage:     `{"string":""}`,
+			wantStorage: `{"string":"-a-b"}`,
+		}, {
+			name:        "concat of empty list of bytes",
+			script:      script(`{"prim":"list","args":[{"prim":"bytes"}]}`, `{"prim":"bytes"}`, `[{"prim":"CAR"},{"prim":"CONCAT"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]`),
+			parameter:   `[]`,
+			storage:     `{"bytes":"00"}`,
+			wantStorage: `{"bytes":""}`,
+		}, {
+			name:      "mutez overflow",
+			script:    script(`{"prim":"mutez"}`, `{"prim":"mutez"}`, `[{"prim":"UNPAIR"},{"prim":"SWAP"},{"prim":"SUB"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]`),
+			parameter: `{"int":"2"}`,
+			storage:   `{"int":"1"}`,
+			wantErr:   "2.args.0.2: mutez overflow: -1",
+		}, {
+			name:      "step limit",
+			script:    script(`{"prim":"unit"}`, `{"prim":"unit"}`, `[{"prim":"CDR"},{"prim":"PUSH","args":[{"prim":"bool"},{"prim":"True"}]},{"prim":"LOOP","args":[[{"prim":"PUSH","args":[{"prim":"bool"},{"prim":"True"}]}]]},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]`),
+			parameter: `{"prim":"Unit"}`,
+			storage:   `{"prim":"Unit"}`,
+			opts:      []InterpreterOption{WithStepLimit(100)},
+			wantErr:   "2.args.0.2.args.0.0: step limit 100 is exceeded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := New(tt.opts...).Run(tt.script, tt.entrypoint, gjson.Parse(tt.parameter), gjson.Parse(tt.storage))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			storage, err := json.Marshal(result.Storage)
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, tt.wantStorage, string(storage))
+			}
+		})
+	}
+}
+
+func TestInterpreter_RunOperations(t *testing.T) {
+	code := script(`{"prim":"unit"}`, `{"prim":"unit"}`, `[{"prim":"CDR"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"SOURCE"},{"prim":"CONTRACT","args":[{"prim":"unit"}]},{"prim":"IF_NONE","args":[[{"prim":"FAIL"}],[]]},{"prim":"PUSH","args":[{"prim":"mutez"},{"int":"10"}]},{"prim":"UNIT"},{"prim":"TRANSFER_TOKENS"},{"prim":"CONS"},{"prim":"PAIR"}]`)
+	source := "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx"
+
+	result, err := New(WithSource(source)).Run(code, "", gjson.Parse(`{"prim":"Unit"}`), gjson.Parse(`{"prim":"Unit"}`))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []Operation{
+		{
+			Kind:        "transaction",
+			Source:      "KT1BEqzn5Wx8uJrZNvuS9DVHmLvG9td3fDLi",
+			Amount:      "10",
+			Destination: source,
+			Parameters: &OperationParameters{
+				Entrypoint: "default",
+				Value:      map[string]interface{}{"prim": "Unit"},
+			},
+		},
+	}, result.Operations)
+}
+
+func TestInterpreter_RunBigMap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := mock_bigmapdiff.NewMockRepository(ctrl)
+	repo.
+		EXPECT().
+		CurrentByKeyAtLevel("mainnet", gomock.Any(), int64(5), int64(100)).
+		Return(bigmapdiff.BigMapDiff{Value: `{"string":"a"}`}, nil).
+		Times(1)
+
+	// copies value of key from parameter to key 2
+	code := script(`{"prim":"nat"}`, `{"prim":"big_map","args":[{"prim":"nat"},{"prim":"string"}]}`, `[{"prim":"UNPAIR"},{"prim":"DUP","args":[{"int":"2"}]},{"prim":"SWAP"},{"prim":"GET"},{"prim":"PUSH","args":[{"prim":"nat"},{"int":"2"}]},{"prim":"UPDATE"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]`)
+	result, err := New(WithBigMaps("mainnet", repo, nil), WithLevel(100)).Run(code, "", gjson.Parse(`{"int":"1"}`), gjson.Parse(`{"int":"5"}`))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]interface{}{"int": "5"}, result.Storage)
+	if assert.Len(t, result.BigMapDiffs, 1) {
+		diff := result.BigMapDiffs[0]
+		assert.Equal(t, int64(5), diff.Ptr)
+		assert.Equal(t, map[string]interface{}{"int": "2"}, diff.Key)
+		assert.Equal(t, map[string]interface{}{"string": "a"}, diff.Value)
+		assert.NotEmpty(t, diff.KeyHash)
+	}
+}
+
+func TestInterpreter_RunTrace(t *testing.T) {
+	code := script(`{"prim":"unit"}`, `{"prim":"int"}`, `[{"prim":"CDR"},{"prim":"PUSH","args":[{"prim":"int"},{"int":"0"}]},{"prim":"DUUP"},{"prim":"ASSERT_CMPGE"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]`)
+
+	result, err := New(WithTrace(0)).Run(code, "", gjson.Parse(`{"prim":"Unit"}`), gjson.Parse(`{"int":"1"}`))
+	if !assert.NoError(t, err) {
+		return
+	}
+	got := make([]string, len(result.Trace))
+	for i := range result.Trace {
+		got[i] = result.Trace[i].Location + " " + result.Trace[i].Instruction
+	}
+	assert.Equal(t, []string{
+		"2.args.0.0 CDR",
+		"2.args.0.1 PUSH",
+		"2.args.0.2 DUP",
+		"2.args.0.3 COMPARE",
+		"2.args.0.3 GE",
+		"2.args.0.3 IF",
+		"2.args.0.4 NIL",
+		"2.args.0.5 PAIR",
+	}, got)
+	assert.Equal(t, int64(len(got)), result.Steps)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"int": "1"},
+		map[string]interface{}{"int": "0"},
+		map[string]interface{}{"int": "1"},
+	}, result.Trace[2].Stack)
+}
+
+func TestInterpreter_RunLimits(t *testing.T) {
+	loop := script(`{"prim":"unit"}`, `{"prim":"unit"}`, `[{"prim":"DROP"},{"prim":"PUSH","args":[{"prim":"bool"},{"prim":"True"}]},{"prim":"LOOP","args":[[{"prim":"PUSH","args":[{"prim":"bool"},{"prim":"True"}]}]]},{"prim":"UNIT"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]`)
+
+	t.Run("trace limit", func(t *testing.T) {
+		result, err := New(WithStepLimit(100), WithTrace(10)).Run(loop, "", gjson.Parse(`{"prim":"Unit"}`), gjson.Parse(`{"prim":"Unit"}`))
+		assert.Error(t, err)
+		if assert.NotNil(t, result) {
+			assert.Len(t, result.Trace, 10)
+			assert.True(t, result.Truncated)
+			assert.Equal(t, int64(100), result.Steps)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		_, err := New(WithStepLimit(1<<40), WithTimeout(10*time.Millisecond)).Run(loop, "", gjson.Parse(`{"prim":"Unit"}`), gjson.Parse(`{"prim":"Unit"}`))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "execution timeout")
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package interpreter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/macros"
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+var errUnknownInstruction = errors.New("unknown instruction")
+
+// machine - state of single execution
+type machine struct {
+	*Interpreter
+
+	selfType     *typechecker.Type
+	stacks       map[string][]*typechecker.Type
+	steps        int64
+	nonce        int64
+	temporaryPtr int64
+	trace        []TraceItem
+	truncated    bool
+	deadline     time.Time
+
+	// macroPath - path of macro which expansion is executed
+	macroPath string
+}
+
+// execute - executes sequence `code` located at `path`
+func (m *machine) execute(code gjson.Result, path string, stack []Value) ([]Value, error) {
+	if !code.IsArray() {
+		return m.instruction(code, path, stack)
+	}
+	var err error
+	for i, item := range code.Array() {
+		itemPath := fmt.Sprintf("%s.%d", path, i)
+		if path == "" {
+			itemPath = fmt.Sprintf("%d", i)
+		}
+		if stack, err = m.execute(item, itemPath, stack); err != nil {
+			return nil, err
+		}
+	}
+	return stack, nil
+}
+
+func (m *machine) instruction(node gjson.Result, path string, stack []Value) ([]Value, error) {
+	prim := node.Get(consts.KeyPrim).String()
+	if m.steps >= m.stepLimit {
+		return nil, m.errorf(path, "step limit %d is exceeded", m.stepLimit)
+	}
+	if !m.deadline.IsZero() && m.steps%deadlineCheckPeriod == 0 && time.Now().After(m.deadline) {
+		return nil, m.errorf(path, "execution timeout %s is exceeded", m.timeout)
+	}
+	m.steps++
+
+	result, err := m.apply(prim, node, path, stack)
+	if err == errUnknownInstruction {
+		m.steps--
+		return m.macro(node, path, stack)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if m.withTrace && int64(len(m.trace)) >= m.traceLimit {
+		m.truncated = true
+	} else if m.withTrace {
+		item := TraceItem{
+			Location:    m.location(path),
+			Instruction: prim,
+			Stack:       make([]interface{}, len(result)),
+		}
+		for i := range result {
+			item.Stack[i] = result[i].Micheline(false)
+		}
+		m.trace = append(m.trace, item)
+	}
+	return result, nil
+}
+
+func (m *machine) macro(node gjson.Result, path string, stack []Value) ([]Value, error) {
+	expanded, err := macros.Expand(node)
+	if err != nil {
+		return nil, m.errorf(path, "%s", err.Error())
+	}
+	if m.macroPath != "" {
+		return m.execute(expanded, path, stack)
+	}
+	m.macroPath = path
+	defer func() {
+		m.macroPath = ""
+	}()
+	return m.execute(expanded, path, stack)
+}
+
+// location - returns path of instruction in the script. Instructions of expanded macros have path of the macro.
+func (m *machine) location(path string) string {
+	if m.macroPath != "" {
+		return m.macroPath
+	}
+	return path
+}
+
+func (m *machine) errorf(path, format string, args ...interface{}) error {
+	return &RuntimeError{
+		Location: m.location(path),
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// lambda - executes lambda with argument `arg`
+func (m *machine) lambda(path string, l Lambda, arg Value) (Value, error) {
+	for i := len(l.Captured) - 1; i >= 0; i-- {
+		arg = Pair{Left: l.Captured[i], Right: arg}
+	}
+
+	// instructions of lambdas without location in the script (e.g. passed in parameter) are reported at location of EXEC
+	macroPath := m.macroPath
+	codePath := l.Path
+	if codePath == "" {
+		m.macroPath = m.location(path)
+	} else {
+		m.macroPath = ""
+	}
+	defer func() {
+		m.macroPath = macroPath
+	}()
+
+	result, err := m.execute(l.Code, codePath, []Value{arg})
+	if err != nil {
+		return nil, err
+	}
+	if len(result) != 1 {
+		return nil, m.errorf(path, "lambda returned %d values", len(result))
+	}
+	return result[0], nil
+}
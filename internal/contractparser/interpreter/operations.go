@@ -0,0 +1,51 @@
+package interpreter
+
+import (
+	"encoding/binary"
+
+	"github.com/baking-bad/bcdhub/internal/tzbase58"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Operation - internal operation emitted by script. It's serialized in the same way as internal operations of node.
+type Operation struct {
+	Kind        string               `json:"kind"`
+	Source      string               `json:"source"`
+	Nonce       int64                `json:"nonce"`
+	Amount      string               `json:"amount,omitempty"`
+	Destination string               `json:"destination,omitempty"`
+	Parameters  *OperationParameters `json:"parameters,omitempty"`
+	Balance     string               `json:"balance,omitempty"`
+	Delegate    string               `json:"delegate,omitempty"`
+	Script      *OperationScript     `json:"script,omitempty"`
+
+	// Originated - address of contract originated by the operation. It differs from real address which depends on operation hash.
+	Originated string `json:"originated_contract,omitempty"`
+}
+
+// OperationParameters -
+type OperationParameters struct {
+	Entrypoint string      `json:"entrypoint"`
+	Value      interface{} `json:"value"`
+}
+
+// OperationScript -
+type OperationScript struct {
+	Code    interface{} `json:"code"`
+	Storage interface{} `json:"storage"`
+}
+
+// Micheline -
+func (op Operation) Micheline(optimized bool) interface{} {
+	return op
+}
+
+// originatedAddress - builds deterministic address of contract originated by `source` with `nonce`
+func originatedAddress(source string, nonce int64) string {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, uint64(nonce))
+	h, _ := blake2b.New(20, nil)
+	h.Write([]byte(source))
+	h.Write(data)
+	return tzbase58.EncodeFromBytes(h.Sum(nil), []byte{2, 90, 121})
+}
@@ -0,0 +1,384 @@
+package interpreter
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
+	"github.com/tidwall/gjson"
+)
+
+// Value - runtime Michelson value. Values are immutable: instructions always build new values.
+type Value interface {
+	// Micheline - returns Micheline JSON representation of the value. Literals are binary encoded if `optimized` is true.
+	Micheline(optimized bool) interface{}
+}
+
+// Int - value of `int`, `nat`, `mutez` or `timestamp` type
+type Int struct {
+	Prim  string
+	Value *big.Int
+}
+
+// Micheline -
+func (v Int) Micheline(optimized bool) interface{} {
+	if v.Prim == consts.TIMESTAMP && !optimized && v.Value.IsInt64() {
+		ts := time.Unix(v.Value.Int64(), 0).UTC()
+		if ts.Year() >= 0 && ts.Year() < 10000 {
+			return map[string]interface{}{consts.KeyString: ts.Format(time.RFC3339)}
+		}
+	}
+	return map[string]interface{}{consts.KeyInt: v.Value.String()}
+}
+
+// String - value of `string`, `address`, `key_hash`, `key`, `signature` or `chain_id` type. Literals are kept in readable form.
+type String struct {
+	Prim  string
+	Value string
+}
+
+// Micheline -
+func (v String) Micheline(optimized bool) interface{} {
+	if optimized && v.Prim != consts.STRING {
+		if data, err := encodeLiteral(v.Prim, v.Value); err == nil {
+			return map[string]interface{}{consts.KeyBytes: hex.EncodeToString(data)}
+		}
+	}
+	return map[string]interface{}{consts.KeyString: v.Value}
+}
+
+// Bytes -
+type Bytes []byte
+
+// Micheline -
+func (v Bytes) Micheline(optimized bool) interface{} {
+	return map[string]interface{}{consts.KeyBytes: hex.EncodeToString(v)}
+}
+
+// Bool -
+type Bool bool
+
+// Micheline -
+func (v Bool) Micheline(optimized bool) interface{} {
+	if v {
+		return prim("True")
+	}
+	return prim("False")
+}
+
+// Unit -
+type Unit struct{}
+
+// Micheline -
+func (v Unit) Micheline(optimized bool) interface{} {
+	return prim("Unit")
+}
+
+// Pair -
+type Pair struct {
+	Left  Value
+	Right Value
+}
+
+// Micheline -
+func (v Pair) Micheline(optimized bool) interface{} {
+	return prim(consts.Pair, v.Left.Micheline(optimized), v.Right.Micheline(optimized))
+}
+
+// Option - `Value` is nil for `None`
+type Option struct {
+	Value Value
+}
+
+// Micheline -
+func (v Option) Micheline(optimized bool) interface{} {
+	if v.Value == nil {
+		return prim(consts.None)
+	}
+	return prim(consts.Some, v.Value.Micheline(optimized))
+}
+
+// Or -
+type Or struct {
+	Left  bool
+	Value Value
+}
+
+// Micheline -
+func (v Or) Micheline(optimized bool) interface{} {
+	if v.Left {
+		return prim(consts.Left, v.Value.Micheline(optimized))
+	}
+	return prim(consts.Right, v.Value.Micheline(optimized))
+}
+
+// List -
+type List []Value
+
+// Micheline -
+func (v List) Micheline(optimized bool) interface{} {
+	result := make([]interface{}, len(v))
+	for i := range v {
+		result[i] = v[i].Micheline(optimized)
+	}
+	return result
+}
+
+// Set - elements are sorted in ascending order
+type Set []Value
+
+// Micheline -
+func (v Set) Micheline(optimized bool) interface{} {
+	return List(v).Micheline(optimized)
+}
+
+// Elt - map entry
+type Elt struct {
+	Key   Value
+	Value Value
+}
+
+// Map - entries are sorted by key in ascending order
+type Map []Elt
+
+// Micheline -
+func (v Map) Micheline(optimized bool) interface{} {
+	result := make([]interface{}, len(v))
+	for i := range v {
+		result[i] = prim("Elt", v[i].Key.Micheline(optimized), v[i].Value.Micheline(optimized))
+	}
+	return result
+}
+
+// Lambda - `Captured` contains values partially applied by APPLY instruction
+type Lambda struct {
+	Type     *typechecker.Type
+	Code     gjson.Result
+	Path     string
+	Captured []Value
+}
+
+// Micheline - code of partially applied lambda is prefixed with `PUSH` of captured value and `PAIR` for each APPLY
+func (v Lambda) Micheline(optimized bool) interface{} {
+	code := v.Code.Value()
+	types := make([]*typechecker.Type, len(v.Captured))
+	arg := v.Type.Args[0]
+	for i := range v.Captured {
+		types[i] = arg.Args[0]
+		arg = arg.Args[1]
+	}
+	for i := len(v.Captured) - 1; i >= 0; i-- {
+		code = []interface{}{
+			prim("PUSH", types[i].Micheline(), v.Captured[i].Micheline(optimized)),
+			prim("PAIR"),
+			code,
+		}
+	}
+	return code
+}
+
+// Contract - typed address of contract entrypoint
+type Contract struct {
+	Address    string
+	Entrypoint string
+	Type       *typechecker.Type
+}
+
+// Micheline -
+func (v Contract) Micheline(optimized bool) interface{} {
+	address := v.Address
+	if v.Entrypoint != "" && v.Entrypoint != consts.DefaultEntrypoint {
+		address += "%" + v.Entrypoint
+	}
+	return String{Prim: consts.ADDRESS, Value: address}.Micheline(optimized)
+}
+
+// Ticket -
+type Ticket struct {
+	Ticketer string
+	Value    Value
+	Amount   *big.Int
+}
+
+// Micheline -
+func (v Ticket) Micheline(optimized bool) interface{} {
+	return prim(consts.Pair,
+		String{Prim: consts.ADDRESS, Value: v.Ticketer}.Micheline(optimized),
+		prim(consts.Pair, v.Value.Micheline(optimized), Int{Prim: consts.NAT, Value: v.Amount}.Micheline(optimized)),
+	)
+}
+
+func prim(name string, args ...interface{}) map[string]interface{} {
+	result := map[string]interface{}{consts.KeyPrim: name}
+	if len(args) > 0 {
+		result[consts.KeyArgs] = args
+	}
+	return result
+}
+
+// Compare - compares values of the same comparable type
+func Compare(a, b Value) int {
+	switch x := a.(type) {
+	case Int:
+		return x.Value.Cmp(b.(Int).Value)
+	case String:
+		y := b.(String)
+		if x.Prim == consts.STRING {
+			return compareStrings(x.Value, y.Value)
+		}
+		dx, errX := encodeLiteral(x.Prim, x.Value)
+		dy, errY := encodeLiteral(y.Prim, y.Value)
+		if errX != nil || errY != nil {
+			return compareStrings(x.Value, y.Value)
+		}
+		return bytes.Compare(dx, dy)
+	case Bytes:
+		return bytes.Compare(x, b.(Bytes))
+	case Bool:
+		y := b.(Bool)
+		switch {
+		case x == y:
+			return 0
+		case !bool(x):
+			return -1
+		default:
+			return 1
+		}
+	case Unit:
+		return 0
+	case Pair:
+		y := b.(Pair)
+		if c := Compare(x.Left, y.Left); c != 0 {
+			return c
+		}
+		return Compare(x.Right, y.Right)
+	case Option:
+		y := b.(Option)
+		switch {
+		case x.Value == nil && y.Value == nil:
+			return 0
+		case x.Value == nil:
+			return -1
+		case y.Value == nil:
+			return 1
+		default:
+			return Compare(x.Value, y.Value)
+		}
+	case Or:
+		y := b.(Or)
+		switch {
+		case x.Left == y.Left:
+			return Compare(x.Value, y.Value)
+		case x.Left:
+			return -1
+		default:
+			return 1
+		}
+	}
+	return 0
+}
+
+func compareStrings(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func sortSet(items []Value) Set {
+	sort.SliceStable(items, func(i, j int) bool {
+		return Compare(items[i], items[j]) < 0
+	})
+	result := make(Set, 0, len(items))
+	for i := range items {
+		if len(result) > 0 && Compare(result[len(result)-1], items[i]) == 0 {
+			continue
+		}
+		result = append(result, items[i])
+	}
+	return result
+}
+
+func sortMap(items []Elt) Map {
+	sort.SliceStable(items, func(i, j int) bool {
+		return Compare(items[i].Key, items[j].Key) < 0
+	})
+	result := make(Map, 0, len(items))
+	for i := range items {
+		if len(result) > 0 && Compare(result[len(result)-1].Key, items[i].Key) == 0 {
+			result[len(result)-1] = items[i]
+			continue
+		}
+		result = append(result, items[i])
+	}
+	return result
+}
+
+// search - returns index of `key` in sorted collection of `size` elements and flag if it's found
+func search(size int, key Value, get func(int) Value) (int, bool) {
+	idx := sort.Search(size, func(i int) bool {
+		return Compare(get(i), key) >= 0
+	})
+	return idx, idx < size && Compare(get(idx), key) == 0
+}
+
+func (v Set) mem(key Value) bool {
+	_, ok := search(len(v), key, func(i int) Value { return v[i] })
+	return ok
+}
+
+func (v Set) update(key Value, add bool) Set {
+	idx, ok := search(len(v), key, func(i int) Value { return v[i] })
+	switch {
+	case ok == add:
+		return v
+	case add:
+		result := make(Set, 0, len(v)+1)
+		result = append(result, v[:idx]...)
+		result = append(result, key)
+		return append(result, v[idx:]...)
+	default:
+		result := make(Set, 0, len(v)-1)
+		result = append(result, v[:idx]...)
+		return append(result, v[idx+1:]...)
+	}
+}
+
+func (v Map) get(key Value) (Value, bool) {
+	idx, ok := search(len(v), key, func(i int) Value { return v[i].Key })
+	if !ok {
+		return nil, false
+	}
+	return v[idx].Value, true
+}
+
+// update - sets value of `key` or removes it if `value` is nil
+func (v Map) update(key, value Value) Map {
+	idx, ok := search(len(v), key, func(i int) Value { return v[i].Key })
+	switch {
+	case ok && value != nil:
+		result := make(Map, len(v))
+		copy(result, v)
+		result[idx] = Elt{Key: key, Value: value}
+		return result
+	case ok:
+		result := make(Map, 0, len(v)-1)
+		result = append(result, v[:idx]...)
+		return append(result, v[idx+1:]...)
+	case value != nil:
+		result := make(Map, 0, len(v)+1)
+		result = append(result, v[:idx]...)
+		result = append(result, Elt{Key: key, Value: value})
+		return append(result, v[idx:]...)
+	default:
+		return v
+	}
+}
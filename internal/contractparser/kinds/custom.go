@@ -0,0 +1,145 @@
+package kinds
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Interface - user-defined contract interface. It's described in JSON or YAML file with the same fields.
+type Interface struct {
+	Name        string       `json:"name"`
+	IsRoot      bool         `json:"is_root"`
+	Entrypoints []Entrypoint `json:"entrypoints"`
+}
+
+// Kind - returns contract kind which is used to find interface in contracts
+func (i Interface) Kind() ContractKind {
+	return ContractKind{
+		Entrypoints: i.Entrypoints,
+		IsRoot:      i.IsRoot,
+	}
+}
+
+// Validate - checks that interface can be registered
+func (i Interface) Validate() error {
+	if i.Name == "" {
+		return errors.New("Empty interface name")
+	}
+	if IsBuiltin(i.Name) {
+		return errors.Errorf("Built-in interface can't be redefined: %s", i.Name)
+	}
+	if len(i.Entrypoints) == 0 {
+		return errors.Errorf("Interface %s has no entrypoints", i.Name)
+	}
+	if i.IsRoot && len(i.Entrypoints) != 1 {
+		return errors.Errorf("Root interface %s has to have exactly one entrypoint", i.Name)
+	}
+	return nil
+}
+
+// ParseInterface - parses interface description. `ext` is the file extension which selects format: `.json`, `.yml` or `.yaml`.
+func ParseInterface(data []byte, ext string) (Interface, error) {
+	var i Interface
+	switch strings.ToLower(ext) {
+	case ".json":
+	case ".yml", ".yaml":
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return i, err
+		}
+		converted, err := json.Marshal(yamlToJSON(raw))
+		if err != nil {
+			return i, err
+		}
+		data = converted
+	default:
+		return i, errors.Errorf("Unknown interface file format: %s", ext)
+	}
+	if err := json.Unmarshal(data, &i); err != nil {
+		return i, err
+	}
+	return i, i.Validate()
+}
+
+// LoadDirectory - loads interfaces from `*.json`, `*.yml` and `*.yaml` files of `dir`. Missing directory contains no interfaces.
+func LoadDirectory(dir string) ([]Interface, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+
+	result := make([]Interface, 0)
+	names := make(map[string]string)
+	for _, file := range files {
+		ext := filepath.Ext(file.Name())
+		if file.IsDir() || !isInterfaceFile(ext) {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		i, err := ParseInterface(data, ext)
+		if err != nil {
+			return nil, errors.Wrap(err, path)
+		}
+		if other, ok := names[i.Name]; ok {
+			return nil, errors.Errorf("Interface %s is defined in %s and %s", i.Name, other, path)
+		}
+		names[i.Name] = path
+		result = append(result, i)
+	}
+	return result, nil
+}
+
+// Register - adds `custom` interfaces to `interfaces`. Interface with the same name is replaced.
+func Register(interfaces map[string]ContractKind, custom ...Interface) error {
+	for _, i := range custom {
+		if err := i.Validate(); err != nil {
+			return err
+		}
+		interfaces[i.Name] = i.Kind()
+	}
+	return nil
+}
+
+func isInterfaceFile(ext string) bool {
+	switch strings.ToLower(ext) {
+	case ".json", ".yml", ".yaml":
+		return true
+	}
+	return false
+}
+
+// yamlToJSON - converts maps decoded by YAML parser to maps with string keys which can be marshaled to JSON
+func yamlToJSON(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			result[fmt.Sprintf("%v", k)] = yamlToJSON(v)
+		}
+		return result
+	case []interface{}:
+		for i := range typed {
+			typed[i] = yamlToJSON(typed[i])
+		}
+		return typed
+	}
+	return value
+}
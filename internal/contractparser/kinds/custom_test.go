@@ -0,0 +1,122 @@
+package kinds
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/meta"
+	"github.com/baking-bad/bcdhub/internal/helpers"
+	"github.com/tidwall/gjson"
+)
+
+func TestLoadDirectory(t *testing.T) {
+	custom, err := LoadDirectory("testdata/interfaces")
+	if err != nil {
+		t.Fatalf("LoadDirectory error %v", err)
+	}
+	if len(custom) != 2 {
+		t.Fatalf("invalid interfaces count: %d", len(custom))
+	}
+
+	interfaces := make(map[string]ContractKind)
+	if err := Register(interfaces, custom...); err != nil {
+		t.Fatalf("Register error %v", err)
+	}
+
+	testCases := []struct {
+		name string
+		path string
+		tag  string
+		res  bool
+	}{
+		{
+			name: "fa2_transfer: carthagenet/KT19nsmdVr54y2MLG1zKtbRUc6TqLfdYXNRG",
+			path: "testdata/fa2-carthagenet-KT19nsmdVr54y2MLG1zKtbRUc6TqLfdYXNRG.json",
+			tag:  "fa2_transfer",
+			res:  true,
+		},
+		{
+			name: "fa2_transfer: babylonnet/KT1Q3XGrpbqhF6ny4qLhuiKekmk86hiAnmhh [wrong]",
+			path: "testdata/fa12-babylonnet-KT1Q3XGrpbqhF6ny4qLhuiKekmk86hiAnmhh.json",
+			tag:  "fa2_transfer",
+			res:  false,
+		},
+		{
+			name: "view_counter: mainnet/KT1NhtHwHD5cqabfSdwg1Fowud5f175eShwx",
+			path: "testdata/viewnat-mainnet-KT1NhtHwHD5cqabfSdwg1Fowud5f175eShwx.json",
+			tag:  "view_counter",
+			res:  true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := ioutil.ReadFile(tt.path)
+			if err != nil {
+				t.Fatalf("ioutil.ReadFile %v error %v", tt.path, err)
+			}
+			m, err := meta.ParseMetadata(gjson.ParseBytes(file))
+			if err != nil {
+				t.Fatalf("meta.ParseMetadata %v error %v", tt.path, err)
+			}
+			tags, err := Find(m, interfaces)
+			if err != nil {
+				t.Fatalf("Find error %v", err)
+			}
+			if ok := helpers.StringInArray(tt.tag, tags); ok != tt.res {
+				t.Errorf("ok != res: %v != %v", ok, tt.res)
+			}
+		})
+	}
+}
+
+func TestParseInterface(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    string
+		ext     string
+		wantErr bool
+	}{
+		{
+			name: "yaml",
+			data: "name: view_unit\nis_root: true\nentrypoints:\n  - prim: unit\n",
+			ext:  ".yaml",
+		},
+		{
+			name:    "built-in name",
+			data:    `{"name": "fa2", "entrypoints": [{"name": "transfer", "prim": "unit"}]}`,
+			ext:     ".json",
+			wantErr: true,
+		},
+		{
+			name:    "root with several entrypoints",
+			data:    `{"name": "view", "is_root": true, "entrypoints": [{"prim": "nat"}, {"prim": "int"}]}`,
+			ext:     ".json",
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			data:    `name = "view"`,
+			ext:     ".toml",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseInterface([]byte(tt.data), tt.ext); (err != nil) != tt.wantErr {
+				t.Errorf("ParseInterface error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadDirectoryMissing(t *testing.T) {
+	custom, err := LoadDirectory("testdata/unknown")
+	if err != nil {
+		t.Fatalf("LoadDirectory error %v", err)
+	}
+	if len(custom) != 0 {
+		t.Errorf("invalid interfaces count: %d", len(custom))
+	}
+}
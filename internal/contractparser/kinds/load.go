@@ -12,27 +12,33 @@ type ContractKind struct {
 	IsRoot      bool
 }
 
-// Load - load interfaces by name. If `names` is empty loads all interfaces.
+var builtin = map[string]IContractKind{
+	FA1Name:           Fa1{},
+	FA1_2Name:         Fa1_2{},
+	FA2Name:           Fa2{},
+	ViewNatName:       ViewNat{},
+	ViewAddressName:   ViewAddress{},
+	ViewBalanceOfName: ViewBalanceOf{},
+}
+
+// IsBuiltin - returns true if interface `name` is built in and can't be redefined
+func IsBuiltin(name string) bool {
+	_, ok := builtin[name]
+	return ok
+}
+
+// Load - load built-in interfaces by name. If `names` is empty loads all interfaces.
 func Load(names ...string) (map[string]ContractKind, error) {
 	interfaces := make(map[string]ContractKind)
 
-	items := map[string]IContractKind{
-		FA1Name:           Fa1{},
-		FA1_2Name:         Fa1_2{},
-		FA2Name:           Fa2{},
-		ViewNatName:       ViewNat{},
-		ViewAddressName:   ViewAddress{},
-		ViewBalanceOfName: ViewBalanceOf{},
-	}
-
 	if len(names) == 0 {
-		for k := range items {
+		for k := range builtin {
 			names = append(names, k)
 		}
 	}
 
 	for _, name := range names {
-		i, ok := items[name]
+		i, ok := builtin[name]
 		if !ok {
 			return nil, errors.Errorf("Invalid interface name: %s", name)
 		}
@@ -0,0 +1,44 @@
+package kinds
+
+import "sync"
+
+// Registry - thread-safe set of contract interfaces which can be replaced at runtime
+type Registry struct {
+	interfaces map[string]ContractKind
+	mx         sync.RWMutex
+}
+
+// NewRegistry -
+func NewRegistry(interfaces map[string]ContractKind) *Registry {
+	if interfaces == nil {
+		interfaces = make(map[string]ContractKind)
+	}
+	return &Registry{
+		interfaces: interfaces,
+	}
+}
+
+// Get - returns interface by name
+func (r *Registry) Get(name string) (ContractKind, bool) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	kind, ok := r.interfaces[name]
+	return kind, ok
+}
+
+// All - returns current set of interfaces. Returned map must not be modified.
+func (r *Registry) All() map[string]ContractKind {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	return r.interfaces
+}
+
+// Set - replaces set of interfaces
+func (r *Registry) Set(interfaces map[string]ContractKind) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.interfaces = interfaces
+}
@@ -0,0 +1,267 @@
+package macros
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// ErrNotMacro -
+var ErrNotMacro = errors.New("not a macro")
+
+var (
+	comparisons = map[string]struct{}{
+		"EQ": {}, "NEQ": {}, "LT": {}, "GT": {}, "LE": {}, "GE": {},
+	}
+
+	cadrRegexp   = regexp.MustCompile(`^C[AD]{2,}R$`)
+	dupRegexp    = regexp.MustCompile(`^DU+P$`)
+	dipRegexp    = regexp.MustCompile(`^DI+P$`)
+	pairRegexp   = regexp.MustCompile(`^P[PAI]{4,}R$`)
+	unpairRegexp = regexp.MustCompile(`^UNP[PAI]{4,}R$`)
+	setRegexp    = regexp.MustCompile(`^SET_C[AD]+R$`)
+	mapRegexp    = regexp.MustCompile(`^MAP_C[AD]+R$`)
+)
+
+type instr map[string]interface{}
+
+func newInstr(prim string, args ...interface{}) instr {
+	result := instr{"prim": prim}
+	if len(args) > 0 {
+		result["args"] = args
+	}
+	return result
+}
+
+type seq []interface{}
+
+// Expand - expands macro instruction `node` to sequence of instructions. Nested macros in the result are not expanded.
+// Returns `ErrNotMacro` if `node` is not a known macro.
+func Expand(node gjson.Result) (gjson.Result, error) {
+	prim := node.Get("prim").String()
+	args := make([]interface{}, 0)
+	for _, arg := range node.Get("args").Array() {
+		args = append(args, json.RawMessage(arg.Raw))
+	}
+
+	expanded, err := expand(prim, args)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	data, err := json.Marshal(expanded)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	return gjson.ParseBytes(data), nil
+}
+
+func expand(prim string, args []interface{}) (seq, error) {
+	switch prim {
+	case "FAIL":
+		return fail(), checkArgs(prim, args, 0)
+	case "ASSERT":
+		return seq{assertion("IF", false)}, checkArgs(prim, args, 0)
+	case "ASSERT_NONE":
+		return seq{assertion("IF_NONE", false)}, checkArgs(prim, args, 0)
+	case "ASSERT_SOME":
+		return seq{assertion("IF_NONE", true)}, checkArgs(prim, args, 0)
+	case "ASSERT_LEFT":
+		return seq{assertion("IF_LEFT", false)}, checkArgs(prim, args, 0)
+	case "ASSERT_RIGHT":
+		return seq{assertion("IF_LEFT", true)}, checkArgs(prim, args, 0)
+	case "IF_SOME":
+		if err := checkArgs(prim, args, 2); err != nil {
+			return nil, err
+		}
+		return seq{newInstr("IF_NONE", args[1], args[0])}, nil
+	case "IF_RIGHT":
+		if err := checkArgs(prim, args, 2); err != nil {
+			return nil, err
+		}
+		return seq{newInstr("IF_LEFT", args[1], args[0])}, nil
+	case "SET_CAR":
+		return seq{newInstr("CDR"), newInstr("SWAP"), newInstr("PAIR")}, checkArgs(prim, args, 0)
+	case "SET_CDR":
+		return seq{newInstr("CAR"), newInstr("PAIR")}, checkArgs(prim, args, 0)
+	case "MAP_CAR":
+		if err := checkArgs(prim, args, 1); err != nil {
+			return nil, err
+		}
+		return seq{newInstr("DUP"), newInstr("CDR"), newInstr("DIP", seq{newInstr("CAR"), args[0]}), newInstr("SWAP"), newInstr("PAIR")}, nil
+	case "MAP_CDR":
+		if err := checkArgs(prim, args, 1); err != nil {
+			return nil, err
+		}
+		return seq{newInstr("DUP"), newInstr("CDR"), args[0], newInstr("SWAP"), newInstr("CAR"), newInstr("PAIR")}, nil
+	}
+
+	switch {
+	case strings.HasPrefix(prim, "ASSERT_CMP") && isComparison(strings.TrimPrefix(prim, "ASSERT_CMP")):
+		return seq{newInstr("COMPARE"), newInstr(strings.TrimPrefix(prim, "ASSERT_CMP")), assertion("IF", false)}, checkArgs(prim, args, 0)
+	case strings.HasPrefix(prim, "ASSERT_") && isComparison(strings.TrimPrefix(prim, "ASSERT_")):
+		return seq{newInstr(strings.TrimPrefix(prim, "ASSERT_")), assertion("IF", false)}, checkArgs(prim, args, 0)
+	case strings.HasPrefix(prim, "CMP") && isComparison(strings.TrimPrefix(prim, "CMP")):
+		return seq{newInstr("COMPARE"), newInstr(strings.TrimPrefix(prim, "CMP"))}, checkArgs(prim, args, 0)
+	case strings.HasPrefix(prim, "IFCMP") && isComparison(strings.TrimPrefix(prim, "IFCMP")):
+		if err := checkArgs(prim, args, 2); err != nil {
+			return nil, err
+		}
+		return seq{newInstr("COMPARE"), newInstr(strings.TrimPrefix(prim, "IFCMP")), newInstr("IF", args...)}, nil
+	case strings.HasPrefix(prim, "IF") && isComparison(strings.TrimPrefix(prim, "IF")):
+		if err := checkArgs(prim, args, 2); err != nil {
+			return nil, err
+		}
+		return seq{newInstr(strings.TrimPrefix(prim, "IF")), newInstr("IF", args...)}, nil
+	case cadrRegexp.MatchString(prim):
+		result := make(seq, 0, len(prim)-2)
+		for _, c := range prim[1 : len(prim)-1] {
+			if c == 'A' {
+				result = append(result, newInstr("CAR"))
+			} else {
+				result = append(result, newInstr("CDR"))
+			}
+		}
+		return result, checkArgs(prim, args, 0)
+	case dupRegexp.MatchString(prim):
+		return seq{newInstr("DUP", intArg(len(prim)-2))}, checkArgs(prim, args, 0)
+	case dipRegexp.MatchString(prim):
+		if err := checkArgs(prim, args, 1); err != nil {
+			return nil, err
+		}
+		return seq{newInstr("DIP", intArg(len(prim)-2), args[0])}, nil
+	case pairRegexp.MatchString(prim):
+		tree, err := parsePairTree(prim[:len(prim)-1])
+		if err != nil {
+			return nil, err
+		}
+		return tree.pair(), checkArgs(prim, args, 0)
+	case unpairRegexp.MatchString(prim):
+		tree, err := parsePairTree(prim[2 : len(prim)-1])
+		if err != nil {
+			return nil, err
+		}
+		return tree.unpair(), checkArgs(prim, args, 0)
+	case setRegexp.MatchString(prim):
+		if err := checkArgs(prim, args, 0); err != nil {
+			return nil, err
+		}
+		rest := "SET_C" + prim[6:]
+		if prim[5] == 'A' {
+			return seq{newInstr("DUP"), newInstr("DIP", seq{newInstr("CAR"), newInstr(rest)}), newInstr("CDR"), newInstr("SWAP"), newInstr("PAIR")}, nil
+		}
+		return seq{newInstr("DUP"), newInstr("DIP", seq{newInstr("CDR"), newInstr(rest)}), newInstr("CAR"), newInstr("PAIR")}, nil
+	case mapRegexp.MatchString(prim):
+		if err := checkArgs(prim, args, 1); err != nil {
+			return nil, err
+		}
+		rest := newInstr("MAP_C"+prim[6:], args[0])
+		if prim[5] == 'A' {
+			return seq{newInstr("DUP"), newInstr("DIP", seq{newInstr("CAR"), rest}), newInstr("CDR"), newInstr("SWAP"), newInstr("PAIR")}, nil
+		}
+		return seq{newInstr("DUP"), newInstr("DIP", seq{newInstr("CDR"), rest}), newInstr("CAR"), newInstr("PAIR")}, nil
+	}
+	return nil, ErrNotMacro
+}
+
+func fail() seq {
+	return seq{newInstr("UNIT"), newInstr("FAILWITH")}
+}
+
+// assertion - `prim {} {FAIL}` or `prim {FAIL} {}` if `swap` is true
+func assertion(prim string, swap bool) instr {
+	if swap {
+		return newInstr(prim, seq{fail()}, seq{})
+	}
+	return newInstr(prim, seq{}, seq{fail()})
+}
+
+func isComparison(prim string) bool {
+	_, ok := comparisons[prim]
+	return ok
+}
+
+func intArg(value int) instr {
+	return instr{"int": strconv.Itoa(value)}
+}
+
+func checkArgs(prim string, args []interface{}, count int) error {
+	if len(args) != count {
+		return errors.Errorf("%s expects %d arguments, got %d", prim, count, len(args))
+	}
+	return nil
+}
+
+// pairTree - tree of P[AIP]+R macro. Leaves are nil.
+type pairTree struct {
+	left  *pairTree
+	right *pairTree
+}
+
+func parsePairTree(name string) (*pairTree, error) {
+	tree, rest, err := parsePair(name)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, errors.Errorf("invalid pair macro: unexpected %s", rest)
+	}
+	return tree, nil
+}
+
+func parsePair(name string) (*pairTree, string, error) {
+	if !strings.HasPrefix(name, "P") {
+		return nil, "", errors.Errorf("invalid pair macro: P expected at %s", name)
+	}
+	name = name[1:]
+
+	var tree pairTree
+	var err error
+	switch {
+	case strings.HasPrefix(name, "A"):
+		name = name[1:]
+	case strings.HasPrefix(name, "P"):
+		if tree.left, name, err = parsePair(name); err != nil {
+			return nil, "", err
+		}
+	default:
+		return nil, "", errors.Errorf("invalid pair macro: A or P expected at %s", name)
+	}
+
+	switch {
+	case strings.HasPrefix(name, "I"):
+		name = name[1:]
+	case strings.HasPrefix(name, "P"):
+		if tree.right, name, err = parsePair(name); err != nil {
+			return nil, "", err
+		}
+	default:
+		return nil, "", errors.Errorf("invalid pair macro: I or P expected at %s", name)
+	}
+	return &tree, name, nil
+}
+
+func (t *pairTree) pair() seq {
+	result := make(seq, 0)
+	if t.left != nil {
+		result = append(result, t.left.pair()...)
+	}
+	if t.right != nil {
+		result = append(result, newInstr("DIP", t.right.pair()))
+	}
+	return append(result, newInstr("PAIR"))
+}
+
+func (t *pairTree) unpair() seq {
+	result := seq{newInstr("UNPAIR")}
+	if t.right != nil {
+		result = append(result, newInstr("DIP", t.right.unpair()))
+	}
+	if t.left != nil {
+		result = append(result, t.left.unpair()...)
+	}
+	return result
+}
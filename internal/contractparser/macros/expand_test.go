@@ -0,0 +1,82 @@
+package macros
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name    string
+		node    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "FAIL",
+			node: `{"prim":"FAIL"}`,
+			want: `[{"prim":"UNIT"},{"prim":"FAILWITH"}]`,
+		}, {
+			name: "ASSERT_SOME",
+			node: `{"prim":"ASSERT_SOME"}`,
+			want: `[{"args":[[[{"prim":"UNIT"},{"prim":"FAILWITH"}]],[]],"prim":"IF_NONE"}]`,
+		}, {
+			name: "IFCMPEQ",
+			node: `{"prim":"IFCMPEQ","args":[[],[{"prim":"DROP"}]]}`,
+			want: `[{"prim":"COMPARE"},{"prim":"EQ"},{"args":[[],[{"prim":"DROP"}]],"prim":"IF"}]`,
+		}, {
+			name: "CDAR",
+			node: `{"prim":"CDAR"}`,
+			want: `[{"prim":"CDR"},{"prim":"CAR"}]`,
+		}, {
+			name: "DUUUP",
+			node: `{"prim":"DUUUP"}`,
+			want: `[{"args":[{"int":"3"}],"prim":"DUP"}]`,
+		}, {
+			name: "DIIP",
+			node: `{"prim":"DIIP","args":[[{"prim":"DROP"}]]}`,
+			want: `[{"args":[{"int":"2"},[{"prim":"DROP"}]],"prim":"DIP"}]`,
+		}, {
+			name: "PAPPAIIR",
+			node: `{"prim":"PAPPAIIR"}`,
+			want: `[{"args":[[{"prim":"PAIR"},{"prim":"PAIR"}]],"prim":"DIP"},{"prim":"PAIR"}]`,
+		}, {
+			name: "UNPAPAIR",
+			node: `{"prim":"UNPAPAIR"}`,
+			want: `[{"prim":"UNPAIR"},{"args":[[{"prim":"UNPAIR"}]],"prim":"DIP"}]`,
+		}, {
+			name: "SET_CDAR",
+			node: `{"prim":"SET_CDAR"}`,
+			want: `[{"prim":"DUP"},{"args":[[{"prim":"CDR"},{"prim":"SET_CAR"}]],"prim":"DIP"},{"prim":"CAR"},{"prim":"PAIR"}]`,
+		}, {
+			name: "MAP_CDR",
+			node: `{"prim":"MAP_CDR","args":[[{"prim":"ABS"}]]}`,
+			want: `[{"prim":"DUP"},{"prim":"CDR"},[{"prim":"ABS"}],{"prim":"SWAP"},{"prim":"CAR"},{"prim":"PAIR"}]`,
+		}, {
+			name:    "not a macro",
+			node:    `{"prim":"ADD"}`,
+			wantErr: ErrNotMacro,
+		}, {
+			name:    "invalid pair macro",
+			node:    `{"prim":"PAPIIR"}`,
+			wantErr: errors.New("invalid pair macro"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Expand(gjson.Parse(tt.node))
+			if tt.wantErr != nil {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tt.wantErr.Error())
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, tt.want, got.Raw)
+			}
+		})
+	}
+}
@@ -62,49 +62,9 @@ func (l *literalDecoder) Decode(jsonData gjson.Result, path string, nm *meta.Nod
 		} else if jsonData.Get(consts.STRING).Exists() {
 			node.Value = jsonData.Get(consts.STRING).String()
 		}
-	case consts.CONTRACT, consts.ADDRESS:
+	case consts.CONTRACT, consts.ADDRESS, consts.KEYHASH, consts.KEY, consts.SIGNATURE, consts.CHAINID:
 		if jsonData.Get(consts.BYTES).Exists() {
-			data, err := unpack.Contract(jsonData.Get(consts.BYTES).String())
-			if err != nil {
-				return nil, err
-			}
-			node.Value = data
-		} else {
-			node.Value = jsonData.Get(consts.STRING).String()
-		}
-	case consts.KEYHASH:
-		if jsonData.Get(consts.BYTES).Exists() {
-			data, err := unpack.KeyHash(jsonData.Get(consts.BYTES).String())
-			if err != nil {
-				return nil, err
-			}
-			node.Value = data
-		} else {
-			node.Value = jsonData.Get(consts.STRING).String()
-		}
-	case consts.KEY:
-		if jsonData.Get(consts.BYTES).Exists() {
-			data, err := unpack.PublicKey(jsonData.Get(consts.BYTES).String())
-			if err != nil {
-				return nil, err
-			}
-			node.Value = data
-		} else {
-			node.Value = jsonData.Get(consts.STRING).String()
-		}
-	case consts.SIGNATURE:
-		if jsonData.Get(consts.BYTES).Exists() {
-			data, err := unpack.Signature(jsonData.Get(consts.BYTES).String())
-			if err != nil {
-				return nil, err
-			}
-			node.Value = data
-		} else {
-			node.Value = jsonData.Get(consts.STRING).String()
-		}
-	case consts.CHAINID:
-		if jsonData.Get(consts.BYTES).Exists() {
-			data, err := unpack.ChainID(jsonData.Get(consts.BYTES).String())
+			data, err := unpack.Literal(nm.Type, jsonData.Get(consts.BYTES).String())
 			if err != nil {
 				return nil, err
 			}
@@ -30,6 +30,7 @@ func newMiguel() *miguel {
 		consts.OR:             &orDecoder{parent: m},
 		consts.LAMBDA:         &lambdaDecoder{},
 		consts.OPTION:         &optionDecoder{parent: m},
+		consts.TICKET:         &ticketDecoder{parent: m},
 		"default":             newLiteralDecoder(),
 	}
 	m.decoders = decoders
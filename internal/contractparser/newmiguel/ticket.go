@@ -0,0 +1,100 @@
+package newmiguel
+
+import (
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/meta"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// Ticket field names
+const (
+	TicketTicketer = "ticketer"
+	TicketContent  = "content"
+	TicketAmount   = "amount"
+)
+
+type ticketDecoder struct {
+	parent *miguel
+}
+
+// Decode -
+func (d *ticketDecoder) Decode(data gjson.Result, path string, nm *meta.NodeMetadata, metadata meta.Metadata, isRoot bool) (*Node, error) {
+	node := Node{
+		Prim:     nm.Prim,
+		Type:     nm.Type,
+		Name:     &(nm.Name),
+		Children: make([]*Node, 0),
+	}
+	if data.Value() == nil || len(nm.Args) != 1 {
+		return &node, nil
+	}
+
+	ticketer, content, amount, err := GetTicketArgs(data)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketerNode, err := d.parent.decoders["default"].Decode(ticketer, path, &meta.NodeMetadata{
+		Prim: consts.ADDRESS,
+		Type: consts.ADDRESS,
+	}, metadata, false)
+	if err != nil {
+		return nil, err
+	}
+	ticketerName := TicketTicketer
+	ticketerNode.Name = &ticketerName
+
+	contentNode, err := d.parent.Convert(content, nm.Args[0], metadata, false)
+	if err != nil {
+		return nil, err
+	}
+	contentName := TicketContent
+	contentNode.Name = &contentName
+
+	amountNode, err := d.parent.decoders["default"].Decode(amount, path, &meta.NodeMetadata{
+		Prim: consts.NAT,
+		Type: consts.NAT,
+	}, metadata, false)
+	if err != nil {
+		return nil, err
+	}
+	amountName := TicketAmount
+	amountNode.Name = &amountName
+
+	node.Children = append(node.Children, ticketerNode, contentNode, amountNode)
+	return &node, nil
+}
+
+// GetTicketArgs - returns ticketer, content and amount of ticket value. Value can be represented as `Pair ticketer content amount`, `Pair ticketer (Pair content amount)` or `{ticketer; content; amount}`
+func GetTicketArgs(data gjson.Result) (ticketer gjson.Result, content gjson.Result, amount gjson.Result, err error) {
+	var args []gjson.Result
+	if data.IsArray() {
+		args = data.Array()
+	} else {
+		if prim := data.Get("prim").String(); prim != consts.Pair {
+			err = errors.Errorf("ticketDecoder.Decode: invalid ticket prim %s", prim)
+			return
+		}
+		args = data.Get("args").Array()
+	}
+
+	switch len(args) {
+	case 2:
+		ticketer = args[0]
+		content = args[1].Get("args.0")
+		amount = args[1].Get("args.1")
+	case 3:
+		ticketer = args[0]
+		content = args[1]
+		amount = args[2]
+	default:
+		err = errors.Errorf("ticketDecoder.Decode: invalid ticket args count %d", len(args))
+		return
+	}
+
+	if !content.Exists() || !amount.Exists() {
+		err = errors.Errorf("ticketDecoder.Decode: invalid ticket value %s", data.Raw)
+	}
+	return
+}
@@ -0,0 +1,84 @@
+package newmiguel
+
+import (
+	"testing"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/meta"
+	"github.com/tidwall/gjson"
+)
+
+func TestTicketDecoder(t *testing.T) {
+	tests := []struct {
+		name         string
+		typ          string
+		data         string
+		wantTicketer string
+		wantContent  interface{}
+		wantAmount   interface{}
+		wantErr      bool
+	}{
+		{
+			name:         "nested pair",
+			typ:          `{"prim":"ticket","args":[{"prim":"string"}]}`,
+			data:         `{"prim":"Pair","args":[{"string":"KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm"},{"prim":"Pair","args":[{"string":"hello"},{"int":"10"}]}]}`,
+			wantTicketer: "KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm",
+			wantContent:  "hello",
+			wantAmount:   "10",
+		}, {
+			name:         "comb pair with bytes ticketer",
+			typ:          `{"prim":"ticket","args":[{"prim":"nat"}]}`,
+			data:         `{"prim":"Pair","args":[{"bytes":"016f516588d2ee560385e386708a13bd63da907cf300"},{"int":"1"},{"int":"5"}]}`,
+			wantTicketer: "KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm",
+			wantContent:  "1",
+			wantAmount:   "5",
+		}, {
+			name:         "sequence",
+			typ:          `{"prim":"ticket","args":[{"prim":"unit"}]}`,
+			data:         `[{"string":"KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm"},{"prim":"Unit"},{"int":"100"}]`,
+			wantTicketer: "KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm",
+			wantAmount:   "100",
+		}, {
+			name:    "invalid prim",
+			typ:     `{"prim":"ticket","args":[{"prim":"unit"}]}`,
+			data:    `{"prim":"Elt","args":[{"string":"KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm"},{"prim":"Unit"}]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, err := meta.ParseMetadata(gjson.Parse(tt.typ))
+			if err != nil {
+				t.Errorf("ParseMetadata() error = %v", err)
+				return
+			}
+			node, err := MichelineToMiguel(gjson.Parse(tt.data), metadata)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MichelineToMiguel() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(node.Children) != 3 {
+				t.Errorf("MichelineToMiguel() children count = %d, want 3", len(node.Children))
+				return
+			}
+			for i, want := range []struct {
+				name  string
+				value interface{}
+			}{
+				{TicketTicketer, tt.wantTicketer},
+				{TicketContent, tt.wantContent},
+				{TicketAmount, tt.wantAmount},
+			} {
+				child := node.Children[i]
+				if child.Name == nil || *child.Name != want.name {
+					t.Errorf("child %d name = %v, want %s", i, child.Name, want.name)
+				}
+				if child.Value != want.value {
+					t.Errorf("child %s value = %v (%T), want %v (%T)", want.name, child.Value, child.Value, want.value, want.value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package pack
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/tzbase58"
+	"github.com/pkg/errors"
+)
+
+type base58Prefix struct {
+	prefix string
+	tag    []byte
+	bytes  []byte
+}
+
+var (
+	keyPrefixes = []base58Prefix{
+		{"edpk", []byte{0x00}, []byte{13, 15, 37, 217}},
+		{"sppk", []byte{0x01}, []byte{3, 254, 226, 86}},
+		{"p2pk", []byte{0x02}, []byte{3, 178, 139, 127}},
+	}
+	signaturePrefixes = []base58Prefix{
+		{"edsig", nil, []byte{9, 245, 205, 134, 18}},
+		{"spsig1", nil, []byte{13, 115, 101, 19, 63}},
+		{"p2sig", nil, []byte{54, 240, 44, 52}},
+		{"sig", nil, []byte{4, 130, 43}},
+	}
+	chainIDPrefixes = []base58Prefix{
+		{"Net", nil, []byte{87, 82, 0}},
+	}
+)
+
+// Literal - returns binary (optimized) form of readable literal `value` of type `typ`
+func Literal(typ, value string) ([]byte, error) {
+	switch typ {
+	case consts.ADDRESS, consts.CONTRACT:
+		address, entrypoint := value, ""
+		if idx := strings.IndexByte(value, '%'); idx > -1 {
+			address, entrypoint = value[:idx], value[idx+1:]
+		}
+		data, err := addressBytes(address)
+		if err != nil {
+			return nil, err
+		}
+		return append(data, []byte(entrypoint)...), nil
+	case consts.KEYHASH:
+		if strings.HasPrefix(value, "KT") {
+			return nil, errors.Errorf("invalid key hash: %s", value)
+		}
+		data, err := addressBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return data[1:], nil
+	case consts.KEY:
+		return base58Bytes(value, keyPrefixes)
+	case consts.SIGNATURE:
+		return base58Bytes(value, signaturePrefixes)
+	case consts.CHAINID:
+		return base58Bytes(value, chainIDPrefixes)
+	}
+	return nil, errors.Errorf("%s has no binary form", typ)
+}
+
+func addressBytes(address string) ([]byte, error) {
+	if len(address) < 3 {
+		return nil, errors.Errorf("invalid address: %s", address)
+	}
+	value, err := Address(address)
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(value)
+}
+
+func base58Bytes(value string, prefixes []base58Prefix) ([]byte, error) {
+	for _, p := range prefixes {
+		if !strings.HasPrefix(value, p.prefix) {
+			continue
+		}
+		payload, err := tzbase58.DecodeToHex(value, p.bytes)
+		if err != nil {
+			return nil, err
+		}
+		data, err := hex.DecodeString(payload)
+		if err != nil {
+			return nil, err
+		}
+		return append(append([]byte{}, p.tag...), data...), nil
+	}
+	return nil, errors.Errorf("unknown prefix of %s", value)
+}
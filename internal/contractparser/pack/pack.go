@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/hex"
-	"math"
+	"math/big"
 
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
@@ -12,6 +12,14 @@ import (
 
 // Micheline pack micheline-json to bytes
 func Micheline(node gjson.Result) ([]byte, error) {
+	data, err := encode(node)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{0x05}, data...), nil
+}
+
+func encode(node gjson.Result) ([]byte, error) {
 	if node.IsArray() {
 		return packArray(node)
 	}
@@ -30,7 +38,7 @@ func packArray(node gjson.Result) ([]byte, error) {
 	var temp bytes.Buffer
 
 	for _, item := range node.Array() {
-		res, err := Micheline(item)
+		res, err := encode(item)
 		if err != nil {
 			return nil, err
 		}
@@ -75,13 +83,17 @@ func packObjectPrim(node gjson.Result) ([]byte, error) {
 	argsLen := int(node.Get("args.#").Int())
 	annotsLen := int(node.Get("annots.#").Int())
 
-	result.WriteByte(lenTags[argsLen][annotsLen > 0])
+	tagIdx := argsLen
+	if tagIdx > 3 {
+		tagIdx = 3
+	}
+	result.WriteByte(lenTags[tagIdx][annotsLen > 0])
 	result.WriteByte(primTags[node.Get("prim").String()])
 
 	if argsLen > 0 {
 		var args bytes.Buffer
 		for _, item := range node.Get("args").Array() {
-			arg, err := Micheline(item)
+			arg, err := encode(item)
 			if err != nil {
 				return nil, err
 			}
@@ -106,7 +118,7 @@ func packObjectPrim(node gjson.Result) ([]byte, error) {
 		}
 
 		result.Write(packArrayWithLength(temp.Bytes()))
-	} else {
+	} else if argsLen > 2 {
 		result.Write([]byte{0x00, 0x00, 0x00, 0x00})
 	}
 
@@ -116,7 +128,7 @@ func packObjectPrim(node gjson.Result) ([]byte, error) {
 func packObjectBytes(node gjson.Result) ([]byte, error) {
 	var result bytes.Buffer
 
-	if _, err := result.Write([]byte{0x05, 0x0A}); err != nil {
+	if err := result.WriteByte(0x0A); err != nil {
 		return nil, err
 	}
 
@@ -133,22 +145,26 @@ func packObjectBytes(node gjson.Result) ([]byte, error) {
 }
 
 func packObjectInt(node gjson.Result) []byte {
-	result := []byte{0x05, 0x00}
+	result := []byte{0x00}
 
-	val := node.Get("int").Float()
-	i := int(math.Abs(val))
-	b := (i & 0x3F)
+	val, ok := new(big.Int).SetString(node.Get("int").String(), 10)
+	if !ok {
+		val = big.NewInt(node.Get("int").Int())
+	}
+	abs := new(big.Int).Abs(val)
+	b := byte(new(big.Int).And(abs, big.NewInt(0x3F)).Uint64())
 
-	if val < 0 {
+	if val.Sign() < 0 {
 		b |= 0xC0
 	} else {
 		b |= 0x80
 	}
 
-	result = append(result, byte(b))
+	result = append(result, b)
 
-	for i >>= 6; i != 0; i >>= 7 {
-		result = append(result, byte((i&0x7F)|0x80))
+	mask := big.NewInt(0x7F)
+	for abs.Rsh(abs, 6); abs.Sign() != 0; abs.Rsh(abs, 7) {
+		result = append(result, byte(new(big.Int).And(abs, mask).Uint64())|0x80)
 	}
 
 	result[len(result)-1] &= 0x7F
@@ -159,7 +175,7 @@ func packObjectInt(node gjson.Result) []byte {
 func packObjectString(node gjson.Result) ([]byte, error) {
 	var result bytes.Buffer
 
-	if _, err := result.Write([]byte{0x05, 0x01}); err != nil {
+	if err := result.WriteByte(0x01); err != nil {
 		return nil, err
 	}
 
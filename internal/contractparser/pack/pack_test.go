@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
 	"github.com/tidwall/gjson"
 )
 
@@ -62,6 +63,21 @@ func TestMicheline(t *testing.T) {
 			}`,
 			expected: "050a000000c010c6d5cdca84fc3c7f33061add256f48e0ab03a697832b338901898b650419eb6f334b28153fb73ad2ecd1cd2ac67053161e9f46cfbdaf7b1132a4654a55162850249650f9b873ac3113fa8c02ef1cd1df481480a4457f351d28f4da89d19fa405c3d77f686dc9a24d2681c9184bf2b091f62e6b24df651a3da8bd7067e14e7908fb02f8955b84af5081614cb5bc49b416d9edf914fc608c441b3f2eb8b6043736ddb9d4e4d62334a23b5625c14ef3e1a7e99258386310221b22d83a5eac035c",
 		},
+		{
+			name:     "pair",
+			input:    `{"prim": "Pair", "args": [{"int": "1"}, {"int": "2"}]}`,
+			expected: "05070700010002",
+		},
+		{
+			name:     "list",
+			input:    `[{"int": "1"}, {"string": "a"}]`,
+			expected: "0502000000080001010000000161",
+		},
+		{
+			name:     "big int",
+			input:    `{"int": "-9223372036854775809"}`,
+			expected: "0500c1808080808080808002",
+		},
 	}
 
 	for _, tt := range tests {
@@ -77,3 +93,94 @@ func TestMicheline(t *testing.T) {
 		})
 	}
 }
+
+func TestMicheline_encoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "nested values are not prefixed",
+			input:    `{"prim": "Pair", "args": [{"prim": "Pair", "args": [{"int": "1"}, {"string": "a"}]}, [{"bytes": "00"}]]}`,
+			expected: "0507070707000101000000016102000000060a0000000100",
+		},
+		{
+			name:     "int of one byte",
+			input:    `{"int": "63"}`,
+			expected: "05003f",
+		},
+		{
+			name:     "int of two bytes",
+			input:    `{"int": "64"}`,
+			expected: "05008001",
+		},
+		{
+			name:     "negative int of two bytes",
+			input:    `{"int": "-64"}`,
+			expected: "0500c001",
+		},
+		{
+			name:     "int larger than int64",
+			input:    `{"int": "1180591620717411303424"}`,
+			expected: "05008080808080808080808002",
+		},
+		{
+			name:     "prim with three args",
+			input:    `{"prim": "LAMBDA", "args": [{"prim": "unit"}, {"prim": "unit"}, []]}`,
+			expected: "05093100000009036c036c020000000000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Micheline(gjson.Parse(tt.input))
+			if err != nil {
+				t.Errorf("error in Micheline, error: %v", err)
+				return
+			}
+			if fmt.Sprintf("%x", result) != tt.expected {
+				t.Errorf("error in Micheline, got: %x, expected: %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTyped(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		typ      string
+		expected string
+	}{
+		{
+			name:     "key",
+			input:    `{"string": "edpkuEhzJqdFBCWMw6TU3deADRK2fq3GuwWFUphwyH7ero1Na4oGFP"}`,
+			typ:      `{"prim": "key"}`,
+			expected: "050a00000021004e4ca2abb4baeed702a0ac5b0de9b5607dd1fedb399c0ce25e15b3868f67269e",
+		},
+		{
+			name:     "comb pair",
+			input:    `[{"int": "1"}, {"string": "KT1FgscaMyhxoVLbVirJVVKpRXgiSGtDG9Z4%foo"}, {"string": "2021-01-01T00:00:00Z"}]`,
+			typ:      `{"prim": "pair", "args": [{"prim": "nat"}, {"prim": "address"}, {"prim": "timestamp"}]}`,
+			expected: "050707000107070a00000019014df06cdd999b02e2e601bacf7f1812ecfece52f700666f6f008098f3fe0b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, err := typechecker.ParseType(gjson.Parse(tt.typ), "")
+			if err != nil {
+				t.Errorf("error in ParseType, error: %v", err)
+				return
+			}
+			result, err := Typed(gjson.Parse(tt.input), typ)
+			if err != nil {
+				t.Errorf("error in Typed, error: %v", err)
+			}
+			if fmt.Sprintf("%x", result) != tt.expected {
+				t.Errorf("error in Typed, got: %x, expected: %v", result, tt.expected)
+			}
+		})
+	}
+}
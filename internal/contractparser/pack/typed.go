@@ -0,0 +1,54 @@
+package pack
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// Typed - packs `value` of type `typ`. Readable literals are packed in optimized form as the node does.
+func Typed(value gjson.Result, typ *typechecker.Type) ([]byte, error) {
+	if !typ.IsPackable() {
+		return nil, errors.Errorf("type %s is not packable", typ)
+	}
+	if err := typechecker.CheckData(value, typ); err != nil {
+		return nil, err
+	}
+	optimized, err := typechecker.MapLiterals(value, typ, optimizeLiteral)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(optimized)
+	if err != nil {
+		return nil, err
+	}
+	return Micheline(gjson.ParseBytes(data))
+}
+
+func optimizeLiteral(node gjson.Result, typ *typechecker.Type) (interface{}, error) {
+	str := node.Get(consts.KeyString)
+	if !str.Exists() {
+		return node.Value(), nil
+	}
+	switch typ.Prim {
+	case consts.TIMESTAMP:
+		ts, err := time.Parse(time.RFC3339, str.String())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{consts.KeyInt: strconv.FormatInt(ts.Unix(), 10)}, nil
+	case consts.ADDRESS, consts.CONTRACT, consts.KEYHASH, consts.KEY, consts.SIGNATURE, consts.CHAINID:
+		data, err := Literal(typ.Prim, str.String())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{consts.KeyBytes: hex.EncodeToString(data)}, nil
+	}
+	return node.Value(), nil
+}
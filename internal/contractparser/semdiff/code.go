@@ -0,0 +1,174 @@
+package semdiff
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/tidwall/gjson"
+)
+
+// firstInstructionRune - every distinct instruction is encoded by its own rune to diff instruction sequences as texts
+const firstInstructionRune = 0x10000
+
+// diffCode - returns changed instruction blocks of sequences `a` and `b`. Paths of blocks start with `prefix`.
+func diffCode(a, b []gjson.Result, prefix string) ([]Block, error) {
+	dictionary := make(map[string]rune)
+	left := encodeInstructions(a, dictionary)
+	right := encodeInstructions(b, dictionary)
+
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffMainRunes(left, right, false)
+
+	blocks := make([]Block, 0)
+	var i, j, start int
+	removed := make([]gjson.Result, 0)
+	added := make([]gjson.Result, 0)
+
+	flush := func() error {
+		if len(removed) == 0 && len(added) == 0 {
+			return nil
+		}
+		changed, err := diffInstructions(removed, added, prefix+strconv.Itoa(start))
+		if err != nil {
+			return err
+		}
+		blocks = append(blocks, changed...)
+		removed = removed[:0]
+		added = added[:0]
+		return nil
+	}
+
+	for _, d := range diffs {
+		count := len([]rune(d.Text))
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			i += count
+			j += count
+		case diffmatchpatch.DiffDelete:
+			if len(removed) == 0 && len(added) == 0 {
+				start = i
+			}
+			removed = append(removed, a[i:i+count]...)
+			i += count
+		case diffmatchpatch.DiffInsert:
+			if len(removed) == 0 && len(added) == 0 {
+				start = i
+			}
+			added = append(added, b[j:j+count]...)
+			j += count
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// diffInstructions - returns blocks of replacement `removed` by `added`. If single instruction is replaced by the same instruction with other arguments, only arguments are compared.
+func diffInstructions(removed, added []gjson.Result, path string) ([]Block, error) {
+	if len(removed) == 1 && len(added) == 1 {
+		argsA := removed[0].Get(consts.KeyArgs).Array()
+		argsB := added[0].Get(consts.KeyArgs).Array()
+		if removed[0].Get(consts.KeyPrim).String() == added[0].Get(consts.KeyPrim).String() && len(argsA) == len(argsB) && len(argsA) > 0 {
+			blocks := make([]Block, 0)
+			for k := range argsA {
+				if canonical(argsA[k]) == canonical(argsB[k]) {
+					continue
+				}
+				argPath := path + "/" + strconv.Itoa(k)
+				if argsA[k].IsArray() && argsB[k].IsArray() {
+					changed, err := diffCode(argsA[k].Array(), argsB[k].Array(), argPath+"/")
+					if err != nil {
+						return nil, err
+					}
+					blocks = append(blocks, changed...)
+					continue
+				}
+				changed, err := newBlock([]gjson.Result{argsA[k]}, []gjson.Result{argsB[k]}, argPath)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, changed)
+			}
+			return blocks, nil
+		}
+	}
+
+	block, err := newBlock(removed, added, path)
+	if err != nil {
+		return nil, err
+	}
+	return []Block{block}, nil
+}
+
+func newBlock(removed, added []gjson.Result, path string) (block Block, err error) {
+	block.Path = path
+	switch {
+	case len(removed) == 0:
+		block.Status = StatusAdded
+	case len(added) == 0:
+		block.Status = StatusRemoved
+	default:
+		block.Status = StatusChanged
+	}
+	if len(removed) > 0 {
+		if block.Left, err = michelson(removed); err != nil {
+			return
+		}
+	}
+	if len(added) > 0 {
+		block.Right, err = michelson(added)
+	}
+	return
+}
+
+func encodeInstructions(code []gjson.Result, dictionary map[string]rune) []rune {
+	result := make([]rune, len(code))
+	for i := range code {
+		key := canonical(code[i])
+		r, ok := dictionary[key]
+		if !ok {
+			r = rune(firstInstructionRune + len(dictionary))
+			dictionary[key] = r
+		}
+		result[i] = r
+	}
+	return result
+}
+
+// canonical - returns JSON of `node` without annotations. Annotations don't change semantics of code and types.
+func canonical(node gjson.Result) string {
+	data, err := json.Marshal(stripAnnotations(node))
+	if err != nil {
+		return node.Raw
+	}
+	return string(data)
+}
+
+func stripAnnotations(node gjson.Result) interface{} {
+	switch {
+	case node.IsArray():
+		arr := node.Array()
+		result := make([]interface{}, len(arr))
+		for i := range arr {
+			result[i] = stripAnnotations(arr[i])
+		}
+		return result
+	case node.IsObject():
+		result := make(map[string]interface{})
+		node.ForEach(func(key, value gjson.Result) bool {
+			if key.String() != consts.KeyAnnots {
+				result[key.String()] = stripAnnotations(value)
+			}
+			return true
+		})
+		return result
+	default:
+		return node.Value()
+	}
+}
@@ -0,0 +1,109 @@
+package semdiff
+
+// diffEntrypoints - matches entrypoints by name. Removed and added entrypoints with the same path and parameter type are treated as renamed.
+func diffEntrypoints(left, right *script) ([]Entrypoint, error) {
+	names := make(map[string]int, len(right.entrypoints))
+	for i := range right.entrypoints {
+		names[right.entrypoints[i].name] = i
+	}
+
+	result := make([]Entrypoint, 0)
+	matched := make(map[int]struct{})
+	removed := make([]int, 0)
+	for i := range left.entrypoints {
+		j, ok := names[left.entrypoints[i].name]
+		if !ok {
+			removed = append(removed, i)
+			continue
+		}
+		matched[j] = struct{}{}
+
+		changed, err := diffEntrypoint(left.entrypoints[i], right.entrypoints[j])
+		if err != nil {
+			return nil, err
+		}
+		if changed != nil {
+			result = append(result, *changed)
+		}
+	}
+
+	for _, i := range removed {
+		a := left.entrypoints[i]
+		renamed := false
+		for j, b := range right.entrypoints {
+			if _, ok := matched[j]; ok || a.path != b.path || canonical(a.typ) != canonical(b.typ) {
+				continue
+			}
+			matched[j] = struct{}{}
+			changed, err := diffEntrypoint(a, b)
+			if err != nil {
+				return nil, err
+			}
+			if changed == nil {
+				changed = &Entrypoint{Name: b.name}
+			}
+			changed.Status = StatusRenamed
+			changed.OldName = a.name
+			result = append(result, *changed)
+			renamed = true
+			break
+		}
+		if renamed {
+			continue
+		}
+
+		typ, err := michelson(a.typ)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, Entrypoint{
+			Name:   a.name,
+			Status: StatusRemoved,
+			Left:   typ,
+		})
+	}
+
+	for j, b := range right.entrypoints {
+		if _, ok := matched[j]; ok {
+			continue
+		}
+		typ, err := michelson(b.typ)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, Entrypoint{
+			Name:   b.name,
+			Status: StatusAdded,
+			Right:  typ,
+		})
+	}
+	return result, nil
+}
+
+// diffEntrypoint - returns nil if parameter type and code of entrypoint are not changed
+func diffEntrypoint(a, b entrypoint) (*Entrypoint, error) {
+	blocks, err := diffCode(a.code, b.code, "")
+	if err != nil {
+		return nil, err
+	}
+	typeChanged := canonical(a.typ) != canonical(b.typ)
+	if !typeChanged && len(blocks) == 0 {
+		return nil, nil
+	}
+
+	result := Entrypoint{
+		Name:        b.name,
+		Status:      StatusChanged,
+		TypeChanged: typeChanged,
+		Blocks:      blocks,
+	}
+	if typeChanged {
+		if result.Left, err = michelson(a.typ); err != nil {
+			return nil, err
+		}
+		if result.Right, err = michelson(b.typ); err != nil {
+			return nil, err
+		}
+	}
+	return &result, nil
+}
@@ -0,0 +1,91 @@
+package semdiff
+
+import (
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/meta"
+	"github.com/tidwall/gjson"
+)
+
+const ifLeft = "IF_LEFT"
+
+type entrypoint struct {
+	name string
+	path string
+	typ  gjson.Result
+	code []gjson.Result
+}
+
+// script - sections of script. Code is split by entrypoints, instructions which are not attributed to any entrypoint are `common`.
+type script struct {
+	parameter   gjson.Result
+	storage     gjson.Result
+	entrypoints []entrypoint
+	common      []gjson.Result
+}
+
+func newScript(data gjson.Result) (*script, error) {
+	parameter, err := section(data, consts.PARAMETER)
+	if err != nil {
+		return nil, err
+	}
+	storage, err := section(data, consts.STORAGE)
+	if err != nil {
+		return nil, err
+	}
+	code, err := section(data, consts.CODE)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := meta.ParseMetadata(parameter)
+	if err != nil {
+		return nil, err
+	}
+	entrypoints, err := metadata.GetEntrypoints()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &script{
+		parameter:   parameter,
+		storage:     storage,
+		entrypoints: make([]entrypoint, len(entrypoints)),
+		common:      make([]gjson.Result, 0),
+	}
+	paths := make(map[string]int, len(entrypoints))
+	for i := range entrypoints {
+		s.entrypoints[i] = entrypoint{
+			name: entrypoints[i].Name,
+			path: entrypoints[i].Path,
+			typ:  typeAt(parameter, entrypoints[i].Path),
+		}
+		paths[entrypoints[i].Path] = i
+	}
+	s.dispatch(code.Array(), "0", paths)
+	return s, nil
+}
+
+// dispatch - attributes `code` to entrypoints following `IF_LEFT` branching of parameter `or` tree.
+// Instructions before and after the dispatching `IF_LEFT` are common for all entrypoints of the branch.
+func (s *script) dispatch(code []gjson.Result, path string, paths map[string]int) {
+	if idx, ok := paths[path]; ok {
+		s.entrypoints[idx].code = append(s.entrypoints[idx].code, code...)
+		return
+	}
+
+	for i := range code {
+		if !isInstruction(code[i], ifLeft) {
+			continue
+		}
+		branches := code[i].Get(consts.KeyArgs).Array()
+		if len(branches) != 2 || !branches[0].IsArray() || !branches[1].IsArray() {
+			break
+		}
+		s.common = append(s.common, code[:i]...)
+		s.dispatch(branches[0].Array(), path+"/0", paths)
+		s.dispatch(branches[1].Array(), path+"/1", paths)
+		s.common = append(s.common, code[i+1:]...)
+		return
+	}
+	s.common = append(s.common, code...)
+}
@@ -0,0 +1,143 @@
+package semdiff
+
+import (
+	"strings"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/formatter"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// Statuses of changes
+const (
+	StatusAdded   = "added"
+	StatusRemoved = "removed"
+	StatusChanged = "changed"
+	StatusRenamed = "renamed"
+)
+
+// Result - structural difference of two scripts. Annotations are ignored except entrypoint and field names.
+type Result struct {
+	Entrypoints []Entrypoint `json:"entrypoints"`
+	Storage     *Storage     `json:"storage,omitempty" extensions:"x-nullable"`
+	Common      []Block      `json:"common,omitempty" extensions:"x-nullable"`
+	Added       int64        `json:"added"`
+	Removed     int64        `json:"removed"`
+	Changed     int64        `json:"changed"`
+}
+
+// Entrypoint - entrypoint which was added, removed, renamed or changed. `Left` and `Right` are parameter types.
+type Entrypoint struct {
+	Name        string  `json:"name"`
+	OldName     string  `json:"old_name,omitempty"`
+	Status      string  `json:"status"`
+	TypeChanged bool    `json:"type_changed,omitempty"`
+	Left        string  `json:"left,omitempty"`
+	Right       string  `json:"right,omitempty"`
+	Blocks      []Block `json:"blocks,omitempty" extensions:"x-nullable"`
+}
+
+// Block - changed instruction block. `Path` is the position of the block in the left code: indexes of instructions and their arguments separated by `/`.
+type Block struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Left   string `json:"left,omitempty"`
+	Right  string `json:"right,omitempty"`
+}
+
+// Storage - changed storage type and its fields
+type Storage struct {
+	Left   string  `json:"left"`
+	Right  string  `json:"right"`
+	Fields []Field `json:"fields,omitempty" extensions:"x-nullable"`
+}
+
+// Field - added, removed or changed field of storage
+type Field struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Left   string `json:"left,omitempty"`
+	Right  string `json:"right,omitempty"`
+}
+
+// Diff - returns structural difference of scripts `a` and `b`. Scripts are arrays of `parameter`, `storage` and `code` sections.
+func Diff(a, b gjson.Result) (res Result, err error) {
+	left, err := newScript(a)
+	if err != nil {
+		return
+	}
+	right, err := newScript(b)
+	if err != nil {
+		return
+	}
+
+	if res.Entrypoints, err = diffEntrypoints(left, right); err != nil {
+		return
+	}
+	for i := range res.Entrypoints {
+		switch res.Entrypoints[i].Status {
+		case StatusAdded:
+			res.Added++
+		case StatusRemoved:
+			res.Removed++
+		default:
+			res.Changed++
+		}
+	}
+
+	if res.Storage, err = diffStorage(left.storage, right.storage); err != nil {
+		return
+	}
+	res.Common, err = diffCode(left.common, right.common, "")
+	return
+}
+
+func section(script gjson.Result, prim string) (gjson.Result, error) {
+	value := script.Get(`#(prim=="` + prim + `").args.0`)
+	if !value.Exists() {
+		return value, errors.Errorf("Script has no %s section", prim)
+	}
+	return value, nil
+}
+
+// typeAt - returns subtree of type `typ` by path of metadata (e.g. `0/1/0`, `0/0/o`, `0/l/1`).
+// Trailing `o` segments are not followed, so type of optional field keeps its `option` wrapper.
+func typeAt(typ gjson.Result, path string) gjson.Result {
+	parts := strings.Split(path, "/")
+	for len(parts) > 1 && parts[len(parts)-1] == "o" {
+		parts = parts[:len(parts)-1]
+	}
+	for i := 1; i < len(parts); i++ {
+		switch parts[i] {
+		case "o", "l", "s", "k":
+			typ = typ.Get("args.0")
+		case "v":
+			typ = typ.Get("args.1")
+		default:
+			typ = typ.Get("args." + parts[i])
+		}
+	}
+	return typ
+}
+
+func michelson(node interface{}) (string, error) {
+	switch typed := node.(type) {
+	case gjson.Result:
+		return formatter.MichelineToMichelson(typed, true, formatter.DefLineSize)
+	case []gjson.Result:
+		raw := "["
+		for i := range typed {
+			if i > 0 {
+				raw += ","
+			}
+			raw += typed[i].Raw
+		}
+		return formatter.MichelineToMichelson(gjson.Parse(raw+"]"), true, formatter.DefLineSize)
+	}
+	return "", errors.Errorf("Unknown node type: %T", node)
+}
+
+func isInstruction(node gjson.Result, prim string) bool {
+	return node.IsObject() && node.Get(consts.KeyPrim).String() == prim
+}
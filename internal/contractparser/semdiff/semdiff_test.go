@@ -0,0 +1,162 @@
+package semdiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+const leftScript = `[
+	{"prim":"parameter","args":[{"prim":"or","args":[{"prim":"nat","annots":["%increment"]},{"prim":"or","args":[{"prim":"nat","annots":["%decrement"]},{"prim":"unit","annots":["%reset"]}]}]}]},
+	{"prim":"storage","args":[{"prim":"pair","args":[{"prim":"int","annots":["%counter"]},{"prim":"address","annots":["%owner"]}]}]},
+	{"prim":"code","args":[[
+		{"prim":"UNPAIR"},
+		{"prim":"IF_LEFT","args":[
+			[{"prim":"INT"},{"prim":"ADD"}],
+			[{"prim":"IF_LEFT","args":[
+				[{"prim":"INT"},{"prim":"SWAP"},{"prim":"SUB"}],
+				[{"prim":"DROP"},{"prim":"DROP"},{"prim":"PUSH","args":[{"prim":"int"},{"int":"0"}]}]
+			]}]
+		]},
+		{"prim":"NIL","args":[{"prim":"operation"}]},
+		{"prim":"PAIR"}
+	]]}
+]`
+
+const rightScript = `[
+	{"prim":"parameter","args":[{"prim":"or","args":[{"prim":"nat","annots":["%increment"]},{"prim":"or","args":[{"prim":"int","annots":["%decrement"]},{"prim":"unit","annots":["%clear"]}]}]}]},
+	{"prim":"storage","args":[{"prim":"pair","args":[{"prim":"int","annots":["%counter"]},{"prim":"pair","args":[{"prim":"key_hash","annots":["%owner"]},{"prim":"bool","annots":["%paused"]}]}]}]},
+	{"prim":"code","args":[[
+		{"prim":"UNPAIR","annots":["@parameter"]},
+		{"prim":"IF_LEFT","args":[
+			[{"prim":"INT"},{"prim":"ADD"}],
+			[{"prim":"IF_LEFT","args":[
+				[{"prim":"SWAP"},{"prim":"SUB"}],
+				[{"prim":"DROP"},{"prim":"DROP"},{"prim":"PUSH","args":[{"prim":"int"},{"int":"0"}]}]
+			]}]
+		]},
+		{"prim":"NIL","args":[{"prim":"operation"}]},
+		{"prim":"PAIR"}
+	]]}
+]`
+
+func TestDiff(t *testing.T) {
+	res, err := Diff(gjson.Parse(leftScript), gjson.Parse(rightScript))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Entrypoint{
+		{
+			Name:        "decrement",
+			Status:      StatusChanged,
+			TypeChanged: true,
+			Left:        "nat %decrement",
+			Right:       "int %decrement",
+			Blocks: []Block{
+				{Path: "0", Status: StatusRemoved, Left: "{ INT }"},
+			},
+		},
+		{
+			Name:    "clear",
+			OldName: "reset",
+			Status:  StatusRenamed,
+		},
+	}, res.Entrypoints)
+	assert.EqualValues(t, 0, res.Added)
+	assert.EqualValues(t, 0, res.Removed)
+	assert.EqualValues(t, 2, res.Changed)
+	assert.Empty(t, res.Common)
+
+	require.NotNil(t, res.Storage)
+	assert.Equal(t, []Field{
+		{Name: "owner", Status: StatusChanged, Left: "address %owner", Right: "key_hash %owner"},
+		{Name: "paused", Status: StatusAdded, Right: "bool %paused"},
+	}, res.Storage.Fields)
+}
+
+func TestDiffSame(t *testing.T) {
+	res, err := Diff(gjson.Parse(leftScript), gjson.Parse(leftScript))
+	require.NoError(t, err)
+	assert.Empty(t, res.Entrypoints)
+	assert.Nil(t, res.Storage)
+	assert.Empty(t, res.Common)
+}
+
+func Test_diffCode(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want []Block
+	}{
+		{
+			name: "annotations are ignored",
+			a:    `[{"prim":"DUP","annots":["@a"]},{"prim":"CAR"}]`,
+			b:    `[{"prim":"DUP","annots":["@b"]},{"prim":"CAR"}]`,
+			want: []Block{},
+		}, {
+			name: "added instructions",
+			a:    `[{"prim":"DUP"},{"prim":"CAR"}]`,
+			b:    `[{"prim":"DUP"},{"prim":"SWAP"},{"prim":"DROP"},{"prim":"CAR"}]`,
+			want: []Block{
+				{Path: "1", Status: StatusAdded, Right: "{ SWAP ; DROP }"},
+			},
+		}, {
+			name: "changed branch",
+			a:    `[{"prim":"DUP"},{"prim":"IF","args":[[{"prim":"DROP"}],[{"prim":"UNIT"},{"prim":"FAILWITH"}]]}]`,
+			b:    `[{"prim":"DUP"},{"prim":"IF","args":[[{"prim":"DROP"}],[{"prim":"PUSH","args":[{"prim":"string"},{"string":"error"}]},{"prim":"FAILWITH"}]]}]`,
+			want: []Block{
+				{Path: "1/1/0", Status: StatusChanged, Left: "{ UNIT }", Right: `{ PUSH string "error" }`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := diffCode(gjson.Parse(tt.a).Array(), gjson.Parse(tt.b).Array(), "")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDiff_WrappedFields(t *testing.T) {
+	left := `[
+		{"prim":"parameter","args":[{"prim":"unit"}]},
+		{"prim":"storage","args":[{"prim":"pair","args":[{"prim":"option","args":[{"prim":"map","args":[{"prim":"address"},{"prim":"nat"}]}],"annots":["%sta"]},{"prim":"pair","args":[{"prim":"list","args":[{"prim":"nat"}],"annots":["%items"]},{"prim":"option","args":[{"prim":"nat"}],"annots":["%limit"]}]}]}]},
+		{"prim":"code","args":[[{"prim":"CDR"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]]}
+	]`
+	right := `[
+		{"prim":"parameter","args":[{"prim":"unit"}]},
+		{"prim":"storage","args":[{"prim":"pair","args":[{"prim":"option","args":[{"prim":"map","args":[{"prim":"address"},{"prim":"int"}]}],"annots":["%sta"]},{"prim":"pair","args":[{"prim":"list","args":[{"prim":"nat"}],"annots":["%items"]},{"prim":"nat","annots":["%limit"]}]}]}]},
+		{"prim":"code","args":[[{"prim":"CDR"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]]}
+	]`
+
+	res, err := Diff(gjson.Parse(left), gjson.Parse(right))
+	require.NoError(t, err)
+	require.NotNil(t, res.Storage)
+	assert.Equal(t, []Field{
+		{Name: "sta", Status: StatusChanged, Left: "option %sta (map address nat)", Right: "option %sta (map address int)"},
+		{Name: "limit", Status: StatusChanged, Left: "option %limit nat", Right: "nat %limit"},
+	}, res.Storage.Fields)
+}
+
+func Test_typeAt(t *testing.T) {
+	typ := gjson.Parse(`{"prim":"pair","args":[{"prim":"option","args":[{"prim":"pair","args":[{"prim":"nat"},{"prim":"string"}]}]},{"prim":"pair","args":[{"prim":"list","args":[{"prim":"pair","args":[{"prim":"int"},{"prim":"bytes"}]}]},{"prim":"map","args":[{"prim":"address"},{"prim":"set","args":[{"prim":"mutez"}]}]}]}]}`)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "0", want: `pair`},
+		{path: "0/0/o", want: `option`},
+		{path: "0/0/o/1", want: `string`},
+		{path: "0/1/0/l/1", want: `bytes`},
+		{path: "0/1/1/k", want: `address`},
+		{path: "0/1/1/v/s", want: `mutez`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, typeAt(typ, tt.path).Get("prim").String())
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package semdiff
+
+import (
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/meta"
+	"github.com/tidwall/gjson"
+)
+
+type field struct {
+	name string
+	typ  gjson.Result
+}
+
+// diffStorage - returns nil if storage type is not changed. Fields are top-level fields of storage matched by name.
+func diffStorage(a, b gjson.Result) (*Storage, error) {
+	if canonical(a) == canonical(b) {
+		return nil, nil
+	}
+
+	var result Storage
+	var err error
+	if result.Left, err = michelson(a); err != nil {
+		return nil, err
+	}
+	if result.Right, err = michelson(b); err != nil {
+		return nil, err
+	}
+
+	left, err := storageFields(a)
+	if err != nil {
+		return nil, err
+	}
+	right, err := storageFields(b)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]int, len(right))
+	for i := range right {
+		names[right[i].name] = i
+	}
+	matched := make(map[string]struct{}, len(left))
+	for i := range left {
+		j, ok := names[left[i].name]
+		if !ok {
+			typ, err := michelson(left[i].typ)
+			if err != nil {
+				return nil, err
+			}
+			result.Fields = append(result.Fields, Field{
+				Name:   left[i].name,
+				Status: StatusRemoved,
+				Left:   typ,
+			})
+			continue
+		}
+		matched[left[i].name] = struct{}{}
+		if canonical(left[i].typ) == canonical(right[j].typ) {
+			continue
+		}
+		changed := Field{
+			Name:   left[i].name,
+			Status: StatusChanged,
+		}
+		if changed.Left, err = michelson(left[i].typ); err != nil {
+			return nil, err
+		}
+		if changed.Right, err = michelson(right[j].typ); err != nil {
+			return nil, err
+		}
+		result.Fields = append(result.Fields, changed)
+	}
+
+	for i := range right {
+		if _, ok := matched[right[i].name]; ok {
+			continue
+		}
+		typ, err := michelson(right[i].typ)
+		if err != nil {
+			return nil, err
+		}
+		result.Fields = append(result.Fields, Field{
+			Name:   right[i].name,
+			Status: StatusAdded,
+			Right:  typ,
+		})
+	}
+	return &result, nil
+}
+
+func storageFields(typ gjson.Result) ([]field, error) {
+	metadata, err := meta.ParseMetadata(typ)
+	if err != nil {
+		return nil, err
+	}
+	root := metadata["0"]
+	if root.Prim != consts.PAIR || len(root.Args) == 0 {
+		return []field{
+			{name: metadata.GetFieldName("0", -1), typ: typ},
+		}, nil
+	}
+
+	fields := make([]field, len(root.Args))
+	for i, path := range root.Args {
+		fields[i] = field{
+			name: metadata.GetFieldName(path, i),
+			typ:  typeAt(typ, path),
+		}
+	}
+	return fields, nil
+}
@@ -2,6 +2,7 @@ package hash
 
 import (
 	"github.com/baking-bad/bcdhub/internal/contractparser/pack"
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
 	"github.com/baking-bad/bcdhub/internal/tzbase58"
 
 	"github.com/tidwall/gjson"
@@ -14,9 +15,21 @@ func Key(input gjson.Result) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return scriptExpression(packed), nil
+}
+
+// TypedKey - returns script expression hash of big map key `input` of type `typ`. Readable literals are allowed.
+func TypedKey(input gjson.Result, typ *typechecker.Type) (string, error) {
+	packed, err := pack.Typed(input, typ)
+	if err != nil {
+		return "", err
+	}
+	return scriptExpression(packed), nil
+}
 
+func scriptExpression(packed []byte) string {
 	blakeHash := blake2b.Sum256(packed)
 	prefix := []byte{0x0D, 0x2C, 0x40, 0x1B}
 
-	return tzbase58.EncodeFromBytes(blakeHash[:], prefix), nil
+	return tzbase58.EncodeFromBytes(blakeHash[:], prefix)
 }
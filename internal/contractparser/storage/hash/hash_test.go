@@ -3,6 +3,7 @@ package hash
 import (
 	"testing"
 
+	"github.com/baking-bad/bcdhub/internal/contractparser/typechecker"
 	"github.com/tidwall/gjson"
 )
 
@@ -55,3 +56,48 @@ func TestKey(t *testing.T) {
 		})
 	}
 }
+
+func TestTypedKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		typ      string
+		expected string
+	}{
+		{
+			name:     "readable address",
+			input:    `{"string": "tz1MsmYzmqxHs9trE1qQugZxxcLPqAXdQaX9"}`,
+			typ:      `{"prim": "address"}`,
+			expected: "expru2YV8AanTTUSV4K21P7X4DzbuWQFVk7NewDuP1A5uamffiiFA3",
+		},
+		{
+			name:     "optimized address",
+			input:    `{"bytes": "000018896fcfc6690baefa9aedc6d759f9bf05727e8c"}`,
+			typ:      `{"prim": "address"}`,
+			expected: "expru2YV8AanTTUSV4K21P7X4DzbuWQFVk7NewDuP1A5uamffiiFA3",
+		},
+		{
+			name:     "string",
+			input:    `{"string": "metadata"}`,
+			typ:      `{"prim": "string"}`,
+			expected: "exprtuf4ctHCKfnRvAxgU8rMeqPzfb8D8e51GWR3iHkoWsFBxD8u9h",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, err := typechecker.ParseType(gjson.Parse(tt.typ), "")
+			if err != nil {
+				t.Errorf("error in ParseType, error: %v", err)
+				return
+			}
+			result, err := TypedKey(gjson.Parse(tt.input), typ)
+			if err != nil {
+				t.Errorf("error in TypedKey, error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("error in TypedKey, got: %v, expected: %v", result, tt.expected)
+			}
+		})
+	}
+}
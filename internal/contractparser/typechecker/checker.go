@@ -0,0 +1,265 @@
+package typechecker
+
+import (
+	"fmt"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/macros"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// Script - type checked script
+type Script struct {
+	Parameter *Type
+	Storage   *Type
+	Code      gjson.Result
+	CodePath  string
+	// Stacks - input stacks of instructions by their paths. Instructions of expanded macros are not included.
+	Stacks map[string][]*Type
+}
+
+// Entrypoint - returns type of entrypoint `name`. Root entrypoint is `default` if parameter has no such field annotation.
+func (s *Script) Entrypoint(name string) (*Type, bool) {
+	return findEntrypoint(s.Parameter, name)
+}
+
+type checker struct {
+	// self - parameter type of checked contract. It's nil inside lambdas where SELF is forbidden.
+	self *Type
+	// stacks - input stacks of checked instructions. It's nil if stacks are not collected.
+	stacks map[string][]*Type
+}
+
+// Check - type checks script: array of `parameter`, `storage` and `code` sections, as it's returned by node in `script.code`.
+// Macros are expanded on the fly. All found section errors are returned as `Errors`.
+func Check(script gjson.Result) (*Script, error) {
+	return checkScript(script, "")
+}
+
+func checkScript(script gjson.Result, path string) (*Script, error) {
+	if !script.IsArray() {
+		return nil, Errors{newError(path, "sequence of script sections expected")}
+	}
+
+	var result Script
+	var errs Errors
+	var codeFound bool
+	for i, section := range script.Array() {
+		sectionPath := joinPath(path, fmt.Sprintf("%d", i))
+		prim := section.Get(consts.KeyPrim).String()
+		args := section.Get(consts.KeyArgs).Array()
+		if len(args) != 1 {
+			errs = append(errs, newError(sectionPath, "section %s expects 1 argument", prim))
+			continue
+		}
+		switch prim {
+		case consts.PARAMETER:
+			if result.Parameter != nil {
+				errs = append(errs, newError(sectionPath, "duplicated parameter section"))
+				continue
+			}
+			typ, err := ParseType(args[0], argPath(sectionPath, 0))
+			if err != nil {
+				errs = append(errs, toError(err, sectionPath))
+				continue
+			}
+			if !typ.IsPassable() {
+				errs = append(errs, newError(argPath(sectionPath, 0), "parameter type %s is not passable", typ))
+				continue
+			}
+			result.Parameter = typ
+		case consts.STORAGE:
+			if result.Storage != nil {
+				errs = append(errs, newError(sectionPath, "duplicated storage section"))
+				continue
+			}
+			typ, err := ParseType(args[0], argPath(sectionPath, 0))
+			if err != nil {
+				errs = append(errs, toError(err, sectionPath))
+				continue
+			}
+			if !typ.IsStorable() {
+				errs = append(errs, newError(argPath(sectionPath, 0), "storage type %s is not storable", typ))
+				continue
+			}
+			result.Storage = typ
+		case consts.CODE:
+			if codeFound {
+				errs = append(errs, newError(sectionPath, "duplicated code section"))
+				continue
+			}
+			codeFound = true
+			result.Code = args[0]
+			result.CodePath = argPath(sectionPath, 0)
+		default:
+			errs = append(errs, newError(sectionPath, "unknown script section %s", prim))
+		}
+	}
+
+	switch {
+	case result.Parameter == nil && len(errs) == 0:
+		errs = append(errs, newError(path, "parameter section is missing"))
+	case result.Storage == nil && len(errs) == 0:
+		errs = append(errs, newError(path, "storage section is missing"))
+	case !codeFound:
+		errs = append(errs, newError(path, "code section is missing"))
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	result.Stacks = make(map[string][]*Type)
+	c := checker{self: result.Parameter, stacks: result.Stacks}
+	input := NewType(consts.PAIR, result.Parameter, result.Storage)
+	output := NewType(consts.PAIR, NewType(consts.LIST, NewType(consts.OPERATION)), result.Storage)
+	if err := c.body(result.Code, result.CodePath, []*Type{input}, []*Type{output}); err != nil {
+		return nil, Errors{toError(err, result.CodePath)}
+	}
+	return &result, nil
+}
+
+// CheckLambda - type checks code of lambda which takes `input` and returns `output`
+func CheckLambda(code gjson.Result, input, output *Type) error {
+	c := checker{}
+	if err := c.lambda(code, "", input, output); err != nil {
+		return Errors{toError(err, "")}
+	}
+	return nil
+}
+
+func (c *checker) lambda(code gjson.Result, path string, input, output *Type) error {
+	inner := checker{stacks: c.stacks}
+	return inner.body(code, path, []*Type{input}, []*Type{output})
+}
+
+// body - checks that sequence `code` transforms `input` stack to `output` stack
+func (c *checker) body(code gjson.Result, path string, input, output []*Type) error {
+	stack, failed, err := c.sequence(code, path, input)
+	if err != nil {
+		return err
+	}
+	if failed {
+		return nil
+	}
+	if !equalStacks(stack, output) {
+		return newError(path, "stack %s expected at the end of code, got %s", stackString(output), stackString(stack))
+	}
+	return nil
+}
+
+// sequence - checks sequence of instructions and returns resulting stack. `failed` is true if sequence always fails.
+func (c *checker) sequence(code gjson.Result, path string, stack []*Type) ([]*Type, bool, error) {
+	if !code.IsArray() {
+		return nil, false, newError(path, "sequence of instructions expected")
+	}
+
+	var failed bool
+	for i, item := range code.Array() {
+		itemPath := joinPath(path, fmt.Sprintf("%d", i))
+		if failed {
+			return nil, false, newError(itemPath, "FAILWITH must be the last instruction of sequence")
+		}
+
+		var err error
+		if item.IsArray() {
+			stack, failed, err = c.sequence(item, itemPath, stack)
+		} else {
+			if c.stacks != nil {
+				c.stacks[itemPath] = copyStack(stack)
+			}
+			stack, failed, err = c.instruction(item, itemPath, stack)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return stack, failed, nil
+}
+
+// macro - expands macro and checks its expansion. Errors inside of expansion are reported at path of macro.
+func (c *checker) macro(node gjson.Result, path string, stack []*Type) ([]*Type, bool, error) {
+	prim := node.Get(consts.KeyPrim).String()
+	expanded, err := macros.Expand(node)
+	if err != nil {
+		if errors.Is(err, macros.ErrNotMacro) {
+			return nil, false, newError(path, "unknown instruction %s", prim)
+		}
+		return nil, false, newError(path, "invalid macro %s: %s", prim, err)
+	}
+
+	inner := checker{self: c.self}
+	result, failed, err := inner.sequence(expanded, "", stack)
+	if err != nil {
+		return nil, false, newError(path, "%s: %s", prim, messageOf(err))
+	}
+	return result, failed, nil
+}
+
+func equalStacks(a, b []*Type) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func stackString(stack []*Type) string {
+	if len(stack) == 0 {
+		return "[]"
+	}
+	s := "["
+	for i := range stack {
+		if i > 0 {
+			s += " : "
+		}
+		s += stack[i].String()
+	}
+	return s + "]"
+}
+
+func toError(err error, path string) *Error {
+	var typeErr *Error
+	if errors.As(err, &typeErr) {
+		return typeErr
+	}
+	return newError(path, "%s", err.Error())
+}
+
+func messageOf(err error) string {
+	var typeErr *Error
+	if errors.As(err, &typeErr) {
+		return typeErr.Message
+	}
+	return err.Error()
+}
+
+// findEntrypoint - searches `or` branch with field annotation `name`
+func findEntrypoint(typ *Type, name string) (*Type, bool) {
+	if result, ok := searchEntrypoint(typ, name); ok {
+		return result, true
+	}
+	if name == consts.DefaultEntrypoint {
+		return typ, true
+	}
+	return nil, false
+}
+
+func searchEntrypoint(typ *Type, name string) (*Type, bool) {
+	if typ.FieldAnnot() == name {
+		return typ, true
+	}
+	if typ.Prim != consts.OR {
+		return nil, false
+	}
+	for i := range typ.Args {
+		if result, ok := searchEntrypoint(typ.Args[i], name); ok {
+			return result, true
+		}
+	}
+	return nil, false
+}
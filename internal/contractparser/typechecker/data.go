@@ -0,0 +1,263 @@
+package typechecker
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/tidwall/gjson"
+)
+
+var maxMutez = new(big.Int).SetUint64(1<<63 - 1)
+
+// CheckData - checks that Micheline value `data` has type `typ`. Lambdas are type checked too.
+// Order of set elements and map keys is not checked.
+func CheckData(data gjson.Result, typ *Type) error {
+	c := checker{}
+	if err := c.data(data, "", typ); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *checker) data(node gjson.Result, path string, typ *Type) error {
+	switch typ.Prim {
+	case consts.INT:
+		_, err := intValue(node, path)
+		return err
+	case consts.NAT:
+		value, err := intValue(node, path)
+		if err != nil {
+			return err
+		}
+		if value.Sign() < 0 {
+			return newError(path, "natural number expected, got %s", value)
+		}
+		return nil
+	case consts.MUTEZ:
+		value, err := intValue(node, path)
+		if err != nil {
+			return err
+		}
+		if value.Sign() < 0 || value.Cmp(maxMutez) > 0 {
+			return newError(path, "mutez amount is out of bounds: %s", value)
+		}
+		return nil
+	case consts.STRING:
+		return stringValue(node, path, nil)
+	case consts.BYTES, consts.BLS12381G1, consts.BLS12381G2:
+		_, err := bytesValue(node, path)
+		return err
+	case consts.BLS12381FR:
+		if node.Get(consts.KeyInt).Exists() {
+			_, err := intValue(node, path)
+			return err
+		}
+		_, err := bytesValue(node, path)
+		return err
+	case consts.BOOL:
+		return c.prim(node, path, typ, "True", "False")
+	case consts.UNIT:
+		return c.prim(node, path, typ, "Unit")
+	case consts.TIMESTAMP:
+		if node.Get(consts.KeyInt).Exists() {
+			_, err := intValue(node, path)
+			return err
+		}
+		return stringValue(node, path, isTimestamp)
+	case consts.ADDRESS, consts.CONTRACT:
+		return literalValue(node, path, typ, isAddress, isBinaryAddress)
+	case consts.KEYHASH:
+		return literalValue(node, path, typ, func(s string) bool { return isBase58(s, keyHashPrefixes) }, isBinaryKeyHash)
+	case consts.KEY:
+		return literalValue(node, path, typ, func(s string) bool { return isBase58(s, keyPrefixes) }, isBinaryKey)
+	case consts.SIGNATURE:
+		return literalValue(node, path, typ, func(s string) bool { return isBase58(s, signaturePrefixes) }, func(b []byte) bool { return len(b) == 64 })
+	case consts.CHAINID:
+		return literalValue(node, path, typ, func(s string) bool { return isBase58(s, chainIDPrefixes) }, func(b []byte) bool { return len(b) == 4 })
+	case consts.OPTION:
+		if err := c.prim(node, path, typ, consts.Some, consts.None); err != nil {
+			return err
+		}
+		if node.Get(consts.KeyPrim).String() == consts.None {
+			return checkArgsCount(node, path, 0)
+		}
+		if err := checkArgsCount(node, path, 1); err != nil {
+			return err
+		}
+		return c.data(node.Get("args.0"), argPath(path, 0), typ.Args[0])
+	case consts.OR:
+		if err := c.prim(node, path, typ, consts.Left, consts.Right); err != nil {
+			return err
+		}
+		if err := checkArgsCount(node, path, 1); err != nil {
+			return err
+		}
+		if node.Get(consts.KeyPrim).String() == consts.Left {
+			return c.data(node.Get("args.0"), argPath(path, 0), typ.Args[0])
+		}
+		return c.data(node.Get("args.0"), argPath(path, 0), typ.Args[1])
+	case consts.PAIR:
+		return c.pair(node, path, typ)
+	case consts.LIST, consts.SET:
+		if !node.IsArray() {
+			return newError(path, "sequence of %s expected", typ)
+		}
+		for i, item := range node.Array() {
+			if err := c.data(item, joinPath(path, fmt.Sprintf("%d", i)), typ.Args[0]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case consts.MAP, consts.BIGMAP:
+		if typ.Prim == consts.BIGMAP && node.Get(consts.KeyInt).Exists() {
+			_, err := intValue(node, path)
+			return err
+		}
+		if !node.IsArray() {
+			return newError(path, "sequence of Elt expected for %s", typ)
+		}
+		for i, item := range node.Array() {
+			itemPath := joinPath(path, fmt.Sprintf("%d", i))
+			if item.Get(consts.KeyPrim).String() != "Elt" {
+				return newError(itemPath, "Elt expected")
+			}
+			if err := checkArgsCount(item, itemPath, 2); err != nil {
+				return err
+			}
+			if err := c.data(item.Get("args.0"), argPath(itemPath, 0), typ.Args[0]); err != nil {
+				return err
+			}
+			if err := c.data(item.Get("args.1"), argPath(itemPath, 1), typ.Args[1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case consts.LAMBDA:
+		if !node.IsArray() {
+			return newError(path, "sequence of instructions expected for %s", typ)
+		}
+		return c.lambda(node, path, typ.Args[0], typ.Args[1])
+	case consts.SAPLINGSTATE:
+		if node.Get(consts.KeyInt).Exists() {
+			_, err := intValue(node, path)
+			return err
+		}
+		if !node.IsArray() || len(node.Array()) != 0 {
+			return newError(path, "empty sapling state expected")
+		}
+		return nil
+	default:
+		return newError(path, "values of type %s can not be written", typ)
+	}
+}
+
+// pair - checks `Pair a b ...` and sequence `{a; b; ...}` notations of right comb
+func (c *checker) pair(node gjson.Result, path string, typ *Type) error {
+	var items []gjson.Result
+	var paths []string
+	switch {
+	case node.IsArray():
+		items = node.Array()
+		for i := range items {
+			paths = append(paths, joinPath(path, fmt.Sprintf("%d", i)))
+		}
+	case node.Get(consts.KeyPrim).String() == consts.Pair:
+		items = node.Get(consts.KeyArgs).Array()
+		for i := range items {
+			paths = append(paths, argPath(path, i))
+		}
+	default:
+		return newError(path, "Pair expected for %s", typ)
+	}
+	if len(items) < 2 {
+		return newError(path, "pair expects at least 2 values, got %d", len(items))
+	}
+	return c.comb(items, paths, typ)
+}
+
+func (c *checker) comb(items []gjson.Result, paths []string, typ *Type) error {
+	if typ.Prim != consts.PAIR {
+		return newError(paths[0], "too many values in pair")
+	}
+	if err := c.data(items[0], paths[0], typ.Args[0]); err != nil {
+		return err
+	}
+	if len(items) == 2 {
+		return c.data(items[1], paths[1], typ.Args[1])
+	}
+	return c.comb(items[1:], paths[1:], typ.Args[1])
+}
+
+func (c *checker) prim(node gjson.Result, path string, typ *Type, prims ...string) error {
+	prim := node.Get(consts.KeyPrim).String()
+	for i := range prims {
+		if prim == prims[i] {
+			return nil
+		}
+	}
+	return newError(path, "invalid value for %s: %s", typ, node.Raw)
+}
+
+func checkArgsCount(node gjson.Result, path string, count int) error {
+	if got := len(node.Get(consts.KeyArgs).Array()); got != count {
+		return newError(path, "%s expects %d arguments, got %d", node.Get(consts.KeyPrim).String(), count, got)
+	}
+	return nil
+}
+
+func intValue(node gjson.Result, path string) (*big.Int, error) {
+	raw := node.Get(consts.KeyInt)
+	if !raw.Exists() {
+		return nil, newError(path, "integer expected, got %s", node.Raw)
+	}
+	value, ok := new(big.Int).SetString(raw.String(), 10)
+	if !ok {
+		return nil, newError(path, "invalid integer %s", raw.String())
+	}
+	return value, nil
+}
+
+func bytesValue(node gjson.Result, path string) ([]byte, error) {
+	raw := node.Get(consts.KeyBytes)
+	if !raw.Exists() {
+		return nil, newError(path, "bytes expected, got %s", node.Raw)
+	}
+	data, ok := decodeBytes(raw.String())
+	if !ok {
+		return nil, newError(path, "invalid bytes %s", raw.String())
+	}
+	return data, nil
+}
+
+func stringValue(node gjson.Result, path string, validate func(string) bool) error {
+	raw := node.Get(consts.KeyString)
+	if !raw.Exists() {
+		return newError(path, "string expected, got %s", node.Raw)
+	}
+	if validate != nil && !validate(raw.String()) {
+		return newError(path, "invalid value %s", raw.String())
+	}
+	return nil
+}
+
+// literalValue - checks readable (string) or optimized (bytes) form of literal
+func literalValue(node gjson.Result, path string, typ *Type, readable func(string) bool, optimized func([]byte) bool) error {
+	if node.Get(consts.KeyBytes).Exists() {
+		data, err := bytesValue(node, path)
+		if err != nil {
+			return err
+		}
+		if !optimized(data) {
+			return newError(path, "invalid %s: %s", typ, node.Get(consts.KeyBytes).String())
+		}
+		return nil
+	}
+	if err := stringValue(node, path, nil); err != nil {
+		return err
+	}
+	if !readable(node.Get(consts.KeyString).String()) {
+		return newError(path, "invalid %s: %s", typ, node.Get(consts.KeyString).String())
+	}
+	return nil
+}
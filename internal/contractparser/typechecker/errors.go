@@ -0,0 +1,39 @@
+package typechecker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error - type error. `Path` is gjson path of the node in checked Micheline.
+type Error struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func newError(path, format string, args ...interface{}) *Error {
+	return &Error{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// Error -
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Errors - list of type errors
+type Errors []*Error
+
+// Error -
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i := range e {
+		messages[i] = e[i].Error()
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,923 @@
+package typechecker
+
+import (
+	"strings"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/tidwall/gjson"
+)
+
+// maxStackIndex - max argument of DIG, DUG, DIP, DROP, DUP, PAIR and other instructions with numeric argument
+const maxStackIndex = 1023
+
+var (
+	typeInt       = NewType(consts.INT)
+	typeNat       = NewType(consts.NAT)
+	typeString    = NewType(consts.STRING)
+	typeBytes     = NewType(consts.BYTES)
+	typeMutez     = NewType(consts.MUTEZ)
+	typeBool      = NewType(consts.BOOL)
+	typeUnit      = NewType(consts.UNIT)
+	typeKey       = NewType(consts.KEY)
+	typeKeyHash   = NewType(consts.KEYHASH)
+	typeSignature = NewType(consts.SIGNATURE)
+	typeTimestamp = NewType(consts.TIMESTAMP)
+	typeAddress   = NewType(consts.ADDRESS)
+	typeChainID   = NewType(consts.CHAINID)
+	typeOperation = NewType(consts.OPERATION)
+	typeG1        = NewType(consts.BLS12381G1)
+	typeG2        = NewType(consts.BLS12381G2)
+	typeFr        = NewType(consts.BLS12381FR)
+)
+
+// binary arithmetic instructions: operand types -> result type
+var arithmetic = map[string]map[[2]string]*Type{
+	"ADD": {
+		{consts.NAT, consts.NAT}: typeNat, {consts.NAT, consts.INT}: typeInt, {consts.INT, consts.NAT}: typeInt, {consts.INT, consts.INT}: typeInt,
+		{consts.TIMESTAMP, consts.INT}: typeTimestamp, {consts.INT, consts.TIMESTAMP}: typeTimestamp, {consts.MUTEZ, consts.MUTEZ}: typeMutez,
+		{consts.BLS12381G1, consts.BLS12381G1}: typeG1, {consts.BLS12381G2, consts.BLS12381G2}: typeG2, {consts.BLS12381FR, consts.BLS12381FR}: typeFr,
+	},
+	"SUB": {
+		{consts.NAT, consts.NAT}: typeInt, {consts.NAT, consts.INT}: typeInt, {consts.INT, consts.NAT}: typeInt, {consts.INT, consts.INT}: typeInt,
+		{consts.TIMESTAMP, consts.INT}: typeTimestamp, {consts.TIMESTAMP, consts.TIMESTAMP}: typeInt, {consts.MUTEZ, consts.MUTEZ}: typeMutez,
+	},
+	"MUL": {
+		{consts.NAT, consts.NAT}: typeNat, {consts.NAT, consts.INT}: typeInt, {consts.INT, consts.NAT}: typeInt, {consts.INT, consts.INT}: typeInt,
+		{consts.MUTEZ, consts.NAT}: typeMutez, {consts.NAT, consts.MUTEZ}: typeMutez,
+		{consts.BLS12381G1, consts.BLS12381FR}: typeG1, {consts.BLS12381G2, consts.BLS12381FR}: typeG2, {consts.BLS12381FR, consts.BLS12381FR}: typeFr,
+		{consts.NAT, consts.BLS12381FR}: typeFr, {consts.INT, consts.BLS12381FR}: typeFr, {consts.BLS12381FR, consts.NAT}: typeFr, {consts.BLS12381FR, consts.INT}: typeFr,
+	},
+	"EDIV": {
+		{consts.NAT, consts.NAT}:     NewType(consts.OPTION, NewType(consts.PAIR, typeNat, typeNat)),
+		{consts.NAT, consts.INT}:     NewType(consts.OPTION, NewType(consts.PAIR, typeInt, typeNat)),
+		{consts.INT, consts.NAT}:     NewType(consts.OPTION, NewType(consts.PAIR, typeInt, typeNat)),
+		{consts.INT, consts.INT}:     NewType(consts.OPTION, NewType(consts.PAIR, typeInt, typeNat)),
+		{consts.MUTEZ, consts.NAT}:   NewType(consts.OPTION, NewType(consts.PAIR, typeMutez, typeMutez)),
+		{consts.MUTEZ, consts.MUTEZ}: NewType(consts.OPTION, NewType(consts.PAIR, typeNat, typeMutez)),
+	},
+	"LSL": {{consts.NAT, consts.NAT}: typeNat},
+	"LSR": {{consts.NAT, consts.NAT}: typeNat},
+	"OR":  {{consts.BOOL, consts.BOOL}: typeBool, {consts.NAT, consts.NAT}: typeNat},
+	"XOR": {{consts.BOOL, consts.BOOL}: typeBool, {consts.NAT, consts.NAT}: typeNat},
+	"AND": {{consts.BOOL, consts.BOOL}: typeBool, {consts.NAT, consts.NAT}: typeNat, {consts.INT, consts.NAT}: typeNat},
+}
+
+// unary instructions: operand type -> result type
+var unary = map[string]map[string]*Type{
+	"ABS":              {consts.INT: typeNat},
+	"ISNAT":            {consts.INT: NewType(consts.OPTION, typeNat)},
+	"INT":              {consts.NAT: typeInt, consts.BLS12381FR: typeInt},
+	"NEG":              {consts.NAT: typeInt, consts.INT: typeInt, consts.BLS12381G1: typeG1, consts.BLS12381G2: typeG2, consts.BLS12381FR: typeFr},
+	"NOT":              {consts.BOOL: typeBool, consts.NAT: typeInt, consts.INT: typeInt},
+	"EQ":               {consts.INT: typeBool},
+	"NEQ":              {consts.INT: typeBool},
+	"LT":               {consts.INT: typeBool},
+	"GT":               {consts.INT: typeBool},
+	"LE":               {consts.INT: typeBool},
+	"GE":               {consts.INT: typeBool},
+	"BLAKE2B":          {consts.BYTES: typeBytes},
+	"SHA256":           {consts.BYTES: typeBytes},
+	"SHA512":           {consts.BYTES: typeBytes},
+	"KECCAK":           {consts.BYTES: typeBytes},
+	"SHA3":             {consts.BYTES: typeBytes},
+	"HASH_KEY":         {consts.KEY: typeKeyHash},
+	"VOTING_POWER":     {consts.KEYHASH: typeNat},
+	"IMPLICIT_ACCOUNT": {consts.KEYHASH: NewType(consts.CONTRACT, typeUnit)},
+}
+
+// instructions without operands: result type
+var constants = map[string]*Type{
+	"UNIT":               typeUnit,
+	"NOW":                typeTimestamp,
+	"LEVEL":              typeNat,
+	"AMOUNT":             typeMutez,
+	"BALANCE":            typeMutez,
+	"SOURCE":             typeAddress,
+	"SENDER":             typeAddress,
+	"CHAIN_ID":           typeChainID,
+	"SELF_ADDRESS":       typeAddress,
+	"TOTAL_VOTING_POWER": typeNat,
+}
+
+func (c *checker) instruction(node gjson.Result, path string, stack []*Type) ([]*Type, bool, error) {
+	if !node.IsObject() {
+		return nil, false, newError(path, "instruction expected")
+	}
+	prim := node.Get(consts.KeyPrim).String()
+	args := node.Get(consts.KeyArgs).Array()
+
+	if result, ok := constants[prim]; ok {
+		if err := checkArgsCount(node, path, 0); err != nil {
+			return nil, false, err
+		}
+		return push(stack, result), false, nil
+	}
+	if table, ok := arithmetic[prim]; ok {
+		if err := checkArgsCount(node, path, 0); err != nil {
+			return nil, false, err
+		}
+		if err := need(path, prim, stack, 2); err != nil {
+			return nil, false, err
+		}
+		result, ok := table[[2]string{stack[0].Prim, stack[1].Prim}]
+		if !ok {
+			return nil, false, newError(path, "%s is not defined for %s and %s", prim, stack[0], stack[1])
+		}
+		return push(stack[2:], result), false, nil
+	}
+
+	switch prim {
+	case "FAILWITH":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if !stack[0].IsPackable() {
+			return nil, false, newError(path, "FAILWITH argument must be packable, got %s", stack[0])
+		}
+		return nil, true, nil
+	case "NEVER":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if stack[0].Prim != consts.NEVER {
+			return nil, false, newError(path, "never expected, got %s", stack[0])
+		}
+		return nil, true, nil
+	case "DROP":
+		n, err := optionalIndex(node, path, 1)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := need(path, prim, stack, n); err != nil {
+			return nil, false, err
+		}
+		return stack[n:], false, nil
+	case "DUP":
+		n, err := optionalIndex(node, path, 1)
+		if err != nil {
+			return nil, false, err
+		}
+		if n == 0 {
+			return nil, false, newError(path, "DUP 0 is forbidden")
+		}
+		if err := need(path, prim, stack, n); err != nil {
+			return nil, false, err
+		}
+		if !stack[n-1].IsDuplicable() {
+			return nil, false, newError(path, "%s can not be duplicated", stack[n-1])
+		}
+		return push(stack, stack[n-1]), false, nil
+	case "SWAP":
+		if err := need(path, prim, stack, 2); err != nil {
+			return nil, false, err
+		}
+		return push(push(stack[2:], stack[0]), stack[1]), false, nil
+	case "DIG":
+		n, err := index(node, path)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := need(path, prim, stack, n+1); err != nil {
+			return nil, false, err
+		}
+		result := make([]*Type, 0, len(stack))
+		result = append(result, stack[n])
+		result = append(result, stack[:n]...)
+		return append(result, stack[n+1:]...), false, nil
+	case "DUG":
+		n, err := index(node, path)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := need(path, prim, stack, n+1); err != nil {
+			return nil, false, err
+		}
+		result := make([]*Type, 0, len(stack))
+		result = append(result, stack[1:n+1]...)
+		result = append(result, stack[0])
+		return append(result, stack[n+1:]...), false, nil
+	case "DIP":
+		n, code, codePath := 1, gjson.Result{}, ""
+		switch len(args) {
+		case 1:
+			code, codePath = args[0], argPath(path, 0)
+		case 2:
+			var err error
+			if n, err = index(node, path); err != nil {
+				return nil, false, err
+			}
+			code, codePath = args[1], argPath(path, 1)
+		default:
+			return nil, false, newError(path, "DIP expects 1 or 2 arguments, got %d", len(args))
+		}
+		if err := need(path, prim, stack, n); err != nil {
+			return nil, false, err
+		}
+		result, failed, err := c.sequence(code, codePath, stack[n:])
+		if err != nil {
+			return nil, false, err
+		}
+		if failed {
+			return nil, false, newError(path, "code under DIP can not fail")
+		}
+		return append(copyStack(stack[:n]), result...), false, nil
+	case "PUSH":
+		if err := checkArgsCount(node, path, 2); err != nil {
+			return nil, false, err
+		}
+		typ, err := ParseType(args[0], argPath(path, 0))
+		if err != nil {
+			return nil, false, err
+		}
+		if !typ.IsPushable() {
+			return nil, false, newError(argPath(path, 0), "%s can not be pushed", typ)
+		}
+		if err := c.data(args[1], argPath(path, 1), typ); err != nil {
+			return nil, false, err
+		}
+		return push(stack, typ), false, nil
+	case "SOME":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		return push(stack[1:], NewType(consts.OPTION, stack[0])), false, nil
+	case "NONE", "NIL", "EMPTY_SET":
+		typ, err := typeArg(node, path)
+		if err != nil {
+			return nil, false, err
+		}
+		switch prim {
+		case "NONE":
+			return push(stack, NewType(consts.OPTION, typ)), false, nil
+		case "NIL":
+			return push(stack, NewType(consts.LIST, typ)), false, nil
+		default:
+			if !typ.IsComparable() {
+				return nil, false, newError(argPath(path, 0), "comparable type expected, got %s", typ)
+			}
+			return push(stack, NewType(consts.SET, typ)), false, nil
+		}
+	case "EMPTY_MAP", "EMPTY_BIG_MAP":
+		if err := checkArgsCount(node, path, 2); err != nil {
+			return nil, false, err
+		}
+		kind := consts.MAP
+		if prim == "EMPTY_BIG_MAP" {
+			kind = consts.BIGMAP
+		}
+		typ, err := ParseType(gjson.Parse(`{"prim":"`+kind+`","args":[`+args[0].Raw+`,`+args[1].Raw+`]}`), path)
+		if err != nil {
+			return nil, false, err
+		}
+		return push(stack, typ), false, nil
+	case "LEFT", "RIGHT":
+		typ, err := typeArg(node, path)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if prim == "LEFT" {
+			return push(stack[1:], NewType(consts.OR, stack[0], typ)), false, nil
+		}
+		return push(stack[1:], NewType(consts.OR, typ, stack[0])), false, nil
+	case "CONS":
+		if err := need(path, prim, stack, 2); err != nil {
+			return nil, false, err
+		}
+		if stack[1].Prim != consts.LIST || !stack[1].Args[0].Equal(stack[0]) {
+			return nil, false, newError(path, "list of %s expected, got %s", stack[0], stack[1])
+		}
+		return push(stack[2:], stack[1]), false, nil
+	case "PAIR":
+		n, err := optionalIndex(node, path, 2)
+		if err != nil {
+			return nil, false, err
+		}
+		if n < 2 {
+			return nil, false, newError(path, "PAIR expects at least 2 elements")
+		}
+		if err := need(path, prim, stack, n); err != nil {
+			return nil, false, err
+		}
+		return push(stack[n:], combType(stack[:n])), false, nil
+	case "UNPAIR":
+		n, err := optionalIndex(node, path, 2)
+		if err != nil {
+			return nil, false, err
+		}
+		if n < 2 {
+			return nil, false, newError(path, "UNPAIR expects at least 2 elements")
+		}
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		items := make([]*Type, 0, n)
+		typ := stack[0]
+		for i := 0; i < n-1; i++ {
+			if typ.Prim != consts.PAIR {
+				return nil, false, newError(path, "pair of %d elements expected, got %s", n, stack[0])
+			}
+			items = append(items, typ.Args[0])
+			typ = typ.Args[1]
+		}
+		items = append(items, typ)
+		return append(items, stack[1:]...), false, nil
+	case "CAR", "CDR":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if stack[0].Prim != consts.PAIR {
+			return nil, false, newError(path, "pair expected, got %s", stack[0])
+		}
+		if prim == "CAR" {
+			return push(stack[1:], stack[0].Args[0]), false, nil
+		}
+		return push(stack[1:], stack[0].Args[1]), false, nil
+	case "IF", "IF_NONE", "IF_LEFT", "IF_CONS":
+		return c.branches(node, path, prim, stack)
+	case "LOOP":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if !stack[0].Equal(typeBool) {
+			return nil, false, newError(path, "bool expected, got %s", stack[0])
+		}
+		if err := c.loop(node, path, stack[1:], push(stack[1:], typeBool)); err != nil {
+			return nil, false, err
+		}
+		return stack[1:], false, nil
+	case "LOOP_LEFT":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if stack[0].Prim != consts.OR {
+			return nil, false, newError(path, "or expected, got %s", stack[0])
+		}
+		if err := c.loop(node, path, push(stack[1:], stack[0].Args[0]), stack); err != nil {
+			return nil, false, err
+		}
+		return push(stack[1:], stack[0].Args[1]), false, nil
+	case "ITER":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		elem, err := elementType(path, stack[0])
+		if err != nil {
+			return nil, false, err
+		}
+		if err := c.loop(node, path, push(stack[1:], elem), stack[1:]); err != nil {
+			return nil, false, err
+		}
+		return stack[1:], false, nil
+	case "MAP":
+		return c.mapInstruction(node, path, stack)
+	case "LAMBDA":
+		if err := checkArgsCount(node, path, 3); err != nil {
+			return nil, false, err
+		}
+		input, err := ParseType(args[0], argPath(path, 0))
+		if err != nil {
+			return nil, false, err
+		}
+		output, err := ParseType(args[1], argPath(path, 1))
+		if err != nil {
+			return nil, false, err
+		}
+		if err := c.lambda(args[2], argPath(path, 2), input, output); err != nil {
+			return nil, false, err
+		}
+		return push(stack, NewType(consts.LAMBDA, input, output)), false, nil
+	case "EXEC":
+		if err := need(path, prim, stack, 2); err != nil {
+			return nil, false, err
+		}
+		if stack[1].Prim != consts.LAMBDA || !stack[1].Args[0].Equal(stack[0]) {
+			return nil, false, newError(path, "lambda with argument %s expected, got %s", stack[0], stack[1])
+		}
+		return push(stack[2:], stack[1].Args[1]), false, nil
+	case "APPLY":
+		if err := need(path, prim, stack, 2); err != nil {
+			return nil, false, err
+		}
+		lambda := stack[1]
+		if lambda.Prim != consts.LAMBDA || lambda.Args[0].Prim != consts.PAIR || !lambda.Args[0].Args[0].Equal(stack[0]) {
+			return nil, false, newError(path, "lambda with argument (pair %s _) expected, got %s", stack[0], lambda)
+		}
+		if !stack[0].IsPackable() {
+			return nil, false, newError(path, "%s can not be captured by lambda", stack[0])
+		}
+		return push(stack[2:], NewType(consts.LAMBDA, lambda.Args[0].Args[1], lambda.Args[1])), false, nil
+	case "CAST":
+		typ, err := typeArg(node, path)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if !typ.Equal(stack[0]) {
+			return nil, false, newError(path, "can not cast %s to %s", stack[0], typ)
+		}
+		return push(stack[1:], typ), false, nil
+	case "RENAME":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		return stack, false, nil
+	case "COMPARE":
+		if err := need(path, prim, stack, 2); err != nil {
+			return nil, false, err
+		}
+		if !stack[0].Equal(stack[1]) || !stack[0].IsComparable() {
+			return nil, false, newError(path, "can not compare %s and %s", stack[0], stack[1])
+		}
+		return push(stack[2:], typeInt), false, nil
+	case "SIZE":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		switch stack[0].Prim {
+		case consts.STRING, consts.BYTES, consts.LIST, consts.SET, consts.MAP:
+			return push(stack[1:], typeNat), false, nil
+		}
+		return nil, false, newError(path, "SIZE is not defined for %s", stack[0])
+	case "CONCAT":
+		return concat(path, stack)
+	case "SLICE":
+		if err := need(path, prim, stack, 3); err != nil {
+			return nil, false, err
+		}
+		if !stack[0].Equal(typeNat) || !stack[1].Equal(typeNat) || (stack[2].Prim != consts.STRING && stack[2].Prim != consts.BYTES) {
+			return nil, false, newError(path, "SLICE is not defined for %s", stackString(stack[:3]))
+		}
+		return push(stack[3:], NewType(consts.OPTION, stack[2])), false, nil
+	case "MEM", "GET", "UPDATE", "GET_AND_UPDATE":
+		if len(args) == 1 && prim != "MEM" && prim != "GET_AND_UPDATE" {
+			return combAccess(node, path, prim, stack)
+		}
+		return collectionAccess(node, path, prim, stack)
+	case "PACK":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if !stack[0].IsPackable() {
+			return nil, false, newError(path, "%s is not packable", stack[0])
+		}
+		return push(stack[1:], typeBytes), false, nil
+	case "UNPACK":
+		typ, err := typeArg(node, path)
+		if err != nil {
+			return nil, false, err
+		}
+		if !typ.IsPackable() {
+			return nil, false, newError(argPath(path, 0), "%s is not packable", typ)
+		}
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if !stack[0].Equal(typeBytes) {
+			return nil, false, newError(path, "bytes expected, got %s", stack[0])
+		}
+		return push(stack[1:], NewType(consts.OPTION, typ)), false, nil
+	case "CHECK_SIGNATURE":
+		if err := need(path, prim, stack, 3); err != nil {
+			return nil, false, err
+		}
+		if !stack[0].Equal(typeKey) || !stack[1].Equal(typeSignature) || !stack[2].Equal(typeBytes) {
+			return nil, false, newError(path, "key : signature : bytes expected, got %s", stackString(stack[:3]))
+		}
+		return push(stack[3:], typeBool), false, nil
+	case "SELF":
+		if c.self == nil {
+			return nil, false, newError(path, "SELF is forbidden in lambdas")
+		}
+		entrypoint := entrypointAnnot(node)
+		typ, ok := findEntrypoint(c.self, entrypoint)
+		if !ok {
+			return nil, false, newError(path, "unknown entrypoint %s", entrypoint)
+		}
+		return push(stack, NewType(consts.CONTRACT, typ)), false, nil
+	case "CONTRACT":
+		typ, err := typeArg(node, path)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if !stack[0].Equal(typeAddress) {
+			return nil, false, newError(path, "address expected, got %s", stack[0])
+		}
+		return push(stack[1:], NewType(consts.OPTION, NewType(consts.CONTRACT, typ))), false, nil
+	case "ADDRESS":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if stack[0].Prim != consts.CONTRACT {
+			return nil, false, newError(path, "contract expected, got %s", stack[0])
+		}
+		return push(stack[1:], typeAddress), false, nil
+	case "TRANSFER_TOKENS":
+		if err := need(path, prim, stack, 3); err != nil {
+			return nil, false, err
+		}
+		if !stack[1].Equal(typeMutez) || stack[2].Prim != consts.CONTRACT || !stack[2].Args[0].Equal(stack[0]) {
+			return nil, false, newError(path, "%s : mutez : contract %s expected, got %s", stack[0], stack[0], stackString(stack[:3]))
+		}
+		return push(stack[3:], typeOperation), false, nil
+	case "SET_DELEGATE":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if !stack[0].Equal(NewType(consts.OPTION, typeKeyHash)) {
+			return nil, false, newError(path, "option key_hash expected, got %s", stack[0])
+		}
+		return push(stack[1:], typeOperation), false, nil
+	case "CREATE_CONTRACT":
+		if err := checkArgsCount(node, path, 1); err != nil {
+			return nil, false, err
+		}
+		script, err := checkScript(args[0], argPath(path, 0))
+		if err != nil {
+			return nil, false, err
+		}
+		if err := need(path, prim, stack, 3); err != nil {
+			return nil, false, err
+		}
+		if !stack[0].Equal(NewType(consts.OPTION, typeKeyHash)) || !stack[1].Equal(typeMutez) || !stack[2].Equal(script.Storage) {
+			return nil, false, newError(path, "option key_hash : mutez : %s expected, got %s", script.Storage, stackString(stack[:3]))
+		}
+		return push(push(stack[3:], typeAddress), typeOperation), false, nil
+	case "TICKET":
+		if err := need(path, prim, stack, 2); err != nil {
+			return nil, false, err
+		}
+		if !stack[0].IsComparable() || !stack[1].Equal(typeNat) {
+			return nil, false, newError(path, "comparable value and nat expected, got %s", stackString(stack[:2]))
+		}
+		return push(stack[2:], NewType(consts.TICKET, stack[0])), false, nil
+	case "READ_TICKET":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if stack[0].Prim != consts.TICKET {
+			return nil, false, newError(path, "ticket expected, got %s", stack[0])
+		}
+		return push(stack, combType([]*Type{typeAddress, stack[0].Args[0], typeNat})), false, nil
+	case "SPLIT_TICKET":
+		if err := need(path, prim, stack, 2); err != nil {
+			return nil, false, err
+		}
+		if stack[0].Prim != consts.TICKET || !stack[1].Equal(NewType(consts.PAIR, typeNat, typeNat)) {
+			return nil, false, newError(path, "ticket and pair nat nat expected, got %s", stackString(stack[:2]))
+		}
+		return push(stack[2:], NewType(consts.OPTION, NewType(consts.PAIR, stack[0], stack[0]))), false, nil
+	case "JOIN_TICKETS":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if stack[0].Prim != consts.PAIR || stack[0].Args[0].Prim != consts.TICKET || !stack[0].Args[0].Equal(stack[0].Args[1]) {
+			return nil, false, newError(path, "pair of equal tickets expected, got %s", stack[0])
+		}
+		return push(stack[1:], NewType(consts.OPTION, stack[0].Args[0])), false, nil
+	case "SAPLING_EMPTY_STATE":
+		if len(args) != 1 || !args[0].Get(consts.KeyInt).Exists() {
+			return nil, false, newError(path, "SAPLING_EMPTY_STATE expects memo size")
+		}
+		return push(stack, &Type{Prim: consts.SAPLINGSTATE, Size: args[0].Get(consts.KeyInt).Int()}), false, nil
+	case "SAPLING_VERIFY_UPDATE":
+		if err := need(path, prim, stack, 2); err != nil {
+			return nil, false, err
+		}
+		if stack[0].Prim != consts.SAPLINGTRANSACTION || stack[1].Prim != consts.SAPLINGSTATE || stack[0].Size != stack[1].Size {
+			return nil, false, newError(path, "sapling_transaction and sapling_state of equal memo size expected, got %s", stackString(stack[:2]))
+		}
+		return push(stack[2:], NewType(consts.OPTION, NewType(consts.PAIR, typeInt, stack[1]))), false, nil
+	case "PAIRING_CHECK":
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		if !stack[0].Equal(NewType(consts.LIST, NewType(consts.PAIR, typeG1, typeG2))) {
+			return nil, false, newError(path, "list (pair bls12_381_g1 bls12_381_g2) expected, got %s", stack[0])
+		}
+		return push(stack[1:], typeBool), false, nil
+	}
+
+	if table, ok := unary[prim]; ok {
+		if err := checkArgsCount(node, path, 0); err != nil {
+			return nil, false, err
+		}
+		if err := need(path, prim, stack, 1); err != nil {
+			return nil, false, err
+		}
+		result, ok := table[stack[0].Prim]
+		if !ok {
+			return nil, false, newError(path, "%s is not defined for %s", prim, stack[0])
+		}
+		return push(stack[1:], result), false, nil
+	}
+
+	return c.macro(node, path, stack)
+}
+
+// branches - checks IF, IF_NONE, IF_LEFT and IF_CONS
+func (c *checker) branches(node gjson.Result, path, prim string, stack []*Type) ([]*Type, bool, error) {
+	if err := checkArgsCount(node, path, 2); err != nil {
+		return nil, false, err
+	}
+	if err := need(path, prim, stack, 1); err != nil {
+		return nil, false, err
+	}
+
+	var left, right []*Type
+	top, rest := stack[0], stack[1:]
+	switch prim {
+	case "IF":
+		if !top.Equal(typeBool) {
+			return nil, false, newError(path, "bool expected, got %s", top)
+		}
+		left, right = rest, rest
+	case "IF_NONE":
+		if top.Prim != consts.OPTION {
+			return nil, false, newError(path, "option expected, got %s", top)
+		}
+		left, right = rest, push(rest, top.Args[0])
+	case "IF_LEFT":
+		if top.Prim != consts.OR {
+			return nil, false, newError(path, "or expected, got %s", top)
+		}
+		left, right = push(rest, top.Args[0]), push(rest, top.Args[1])
+	case "IF_CONS":
+		if top.Prim != consts.LIST {
+			return nil, false, newError(path, "list expected, got %s", top)
+		}
+		left, right = push(push(rest, top), top.Args[0]), rest
+	}
+
+	args := node.Get(consts.KeyArgs).Array()
+	leftResult, leftFailed, err := c.sequence(args[0], argPath(path, 0), left)
+	if err != nil {
+		return nil, false, err
+	}
+	rightResult, rightFailed, err := c.sequence(args[1], argPath(path, 1), right)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch {
+	case leftFailed && rightFailed:
+		return nil, true, nil
+	case leftFailed:
+		return rightResult, false, nil
+	case rightFailed:
+		return leftResult, false, nil
+	case !equalStacks(leftResult, rightResult):
+		return nil, false, newError(path, "branches of %s have different stacks: %s and %s", prim, stackString(leftResult), stackString(rightResult))
+	default:
+		return leftResult, false, nil
+	}
+}
+
+// loop - checks that body of LOOP, LOOP_LEFT or ITER transforms `input` to `output`
+func (c *checker) loop(node gjson.Result, path string, input, output []*Type) error {
+	if err := checkArgsCount(node, path, 1); err != nil {
+		return err
+	}
+	bodyPath := argPath(path, 0)
+	result, failed, err := c.sequence(node.Get("args.0"), bodyPath, input)
+	if err != nil {
+		return err
+	}
+	if !failed && !equalStacks(result, output) {
+		return newError(bodyPath, "stack %s expected at the end of loop body, got %s", stackString(output), stackString(result))
+	}
+	return nil
+}
+
+func (c *checker) mapInstruction(node gjson.Result, path string, stack []*Type) ([]*Type, bool, error) {
+	if err := checkArgsCount(node, path, 1); err != nil {
+		return nil, false, err
+	}
+	if err := need(path, "MAP", stack, 1); err != nil {
+		return nil, false, err
+	}
+	collection := stack[0]
+	elem, err := elementType(path, collection)
+	if err != nil {
+		return nil, false, err
+	}
+	if collection.Prim == consts.SET {
+		return nil, false, newError(path, "MAP is not defined for %s", collection)
+	}
+
+	bodyPath := argPath(path, 0)
+	result, failed, err := c.sequence(node.Get("args.0"), bodyPath, push(stack[1:], elem))
+	if err != nil {
+		return nil, false, err
+	}
+	if failed {
+		return nil, false, newError(bodyPath, "body of MAP can not fail")
+	}
+	if len(result) != len(stack) || !equalStacks(result[1:], stack[1:]) {
+		return nil, false, newError(bodyPath, "body of MAP must keep the rest of stack %s, got %s", stackString(stack[1:]), stackString(result))
+	}
+
+	if collection.Prim == consts.LIST {
+		return push(stack[1:], NewType(consts.LIST, result[0])), false, nil
+	}
+	return push(stack[1:], NewType(consts.MAP, collection.Args[0], result[0])), false, nil
+}
+
+func concat(path string, stack []*Type) ([]*Type, bool, error) {
+	if err := need(path, "CONCAT", stack, 1); err != nil {
+		return nil, false, err
+	}
+	top := stack[0]
+	if top.Prim == consts.LIST && (top.Args[0].Equal(typeString) || top.Args[0].Equal(typeBytes)) {
+		return push(stack[1:], top.Args[0]), false, nil
+	}
+	if err := need(path, "CONCAT", stack, 2); err != nil {
+		return nil, false, err
+	}
+	if (top.Equal(typeString) || top.Equal(typeBytes)) && top.Equal(stack[1]) {
+		return push(stack[2:], top), false, nil
+	}
+	return nil, false, newError(path, "CONCAT is not defined for %s", stackString(stack[:2]))
+}
+
+// collectionAccess - checks MEM, GET, UPDATE and GET_AND_UPDATE of sets and maps
+func collectionAccess(node gjson.Result, path, prim string, stack []*Type) ([]*Type, bool, error) {
+	if err := checkArgsCount(node, path, 0); err != nil {
+		return nil, false, err
+	}
+	count := 2
+	if prim == "UPDATE" || prim == "GET_AND_UPDATE" {
+		count = 3
+	}
+	if err := need(path, prim, stack, count); err != nil {
+		return nil, false, err
+	}
+
+	key, collection := stack[0], stack[count-1]
+	var value *Type
+	switch collection.Prim {
+	case consts.SET:
+		if prim != "MEM" && prim != "UPDATE" {
+			return nil, false, newError(path, "%s is not defined for %s", prim, collection)
+		}
+		value = typeBool
+	case consts.MAP, consts.BIGMAP:
+		value = NewType(consts.OPTION, collection.Args[1])
+	default:
+		return nil, false, newError(path, "%s is not defined for %s", prim, collection)
+	}
+	if !collection.Args[0].Equal(key) {
+		return nil, false, newError(path, "key of type %s expected, got %s", collection.Args[0], key)
+	}
+
+	switch prim {
+	case "MEM":
+		return push(stack[2:], typeBool), false, nil
+	case "GET":
+		return push(stack[2:], value), false, nil
+	}
+	if !stack[1].Equal(value) {
+		return nil, false, newError(path, "%s expected, got %s", value, stack[1])
+	}
+	if prim == "UPDATE" {
+		return push(stack[3:], collection), false, nil
+	}
+	return push(push(stack[3:], collection), value), false, nil
+}
+
+// combAccess - checks `GET n` and `UPDATE n` of right combs
+func combAccess(node gjson.Result, path, prim string, stack []*Type) ([]*Type, bool, error) {
+	n, err := index(node, path)
+	if err != nil {
+		return nil, false, err
+	}
+	count := 1
+	if prim == "UPDATE" {
+		count = 2
+	}
+	if err := need(path, prim, stack, count); err != nil {
+		return nil, false, err
+	}
+
+	comb := stack[count-1]
+	if prim == "GET" {
+		typ, ok := getComb(comb, n)
+		if !ok {
+			return nil, false, newError(path, "GET %d is not defined for %s", n, comb)
+		}
+		return push(stack[1:], typ), false, nil
+	}
+	typ, ok := updateComb(comb, n, stack[0])
+	if !ok {
+		return nil, false, newError(path, "UPDATE %d is not defined for %s", n, comb)
+	}
+	return push(stack[2:], typ), false, nil
+}
+
+func getComb(typ *Type, n int) (*Type, bool) {
+	switch {
+	case n == 0:
+		return typ, true
+	case typ.Prim != consts.PAIR:
+		return nil, false
+	case n == 1:
+		return typ.Args[0], true
+	default:
+		return getComb(typ.Args[1], n-2)
+	}
+}
+
+func updateComb(typ *Type, n int, value *Type) (*Type, bool) {
+	switch {
+	case n == 0:
+		return value, true
+	case typ.Prim != consts.PAIR:
+		return nil, false
+	case n == 1:
+		return NewType(consts.PAIR, value, typ.Args[1]), true
+	default:
+		right, ok := updateComb(typ.Args[1], n-2, value)
+		if !ok {
+			return nil, false
+		}
+		return NewType(consts.PAIR, typ.Args[0], right), true
+	}
+}
+
+func elementType(path string, collection *Type) (*Type, error) {
+	switch collection.Prim {
+	case consts.LIST, consts.SET:
+		return collection.Args[0], nil
+	case consts.MAP:
+		return NewType(consts.PAIR, collection.Args[0], collection.Args[1]), nil
+	default:
+		return nil, newError(path, "list, set or map expected, got %s", collection)
+	}
+}
+
+func need(path, prim string, stack []*Type, count int) error {
+	if len(stack) < count {
+		return newError(path, "%s expects %d elements on stack, got %d", prim, count, len(stack))
+	}
+	return nil
+}
+
+func push(stack []*Type, typ *Type) []*Type {
+	result := make([]*Type, 0, len(stack)+1)
+	result = append(result, typ)
+	return append(result, stack...)
+}
+
+func copyStack(stack []*Type) []*Type {
+	result := make([]*Type, len(stack))
+	copy(result, stack)
+	return result
+}
+
+func typeArg(node gjson.Result, path string) (*Type, error) {
+	if err := checkArgsCount(node, path, 1); err != nil {
+		return nil, err
+	}
+	return ParseType(node.Get("args.0"), argPath(path, 0))
+}
+
+// index - returns numeric argument of instruction
+func index(node gjson.Result, path string) (int, error) {
+	arg := node.Get("args.0")
+	if !arg.Get(consts.KeyInt).Exists() {
+		return 0, newError(path, "%s expects numeric argument", node.Get(consts.KeyPrim).String())
+	}
+	n := arg.Get(consts.KeyInt).Int()
+	if n < 0 || n > maxStackIndex {
+		return 0, newError(argPath(path, 0), "invalid argument %d", n)
+	}
+	return int(n), nil
+}
+
+// optionalIndex - returns numeric argument of instruction or `def` if it's omitted
+func optionalIndex(node gjson.Result, path string, def int) (int, error) {
+	switch len(node.Get(consts.KeyArgs).Array()) {
+	case 0:
+		return def, nil
+	case 1:
+		return index(node, path)
+	default:
+		return 0, checkArgsCount(node, path, 1)
+	}
+}
+
+func entrypointAnnot(node gjson.Result) string {
+	for _, annot := range node.Get(consts.KeyAnnots).Array() {
+		if strings.HasPrefix(annot.String(), "%") {
+			return strings.TrimPrefix(annot.String(), "%")
+		}
+	}
+	return consts.DefaultEntrypoint
+}
@@ -0,0 +1,112 @@
+package typechecker
+
+import (
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// base58 prefixes of Tezos literals with lengths of decoded prefix and payload
+type base58Prefix struct {
+	prefix  string
+	length  int
+	payload int
+}
+
+var (
+	addressPrefixes = []base58Prefix{
+		{"tz1", 3, 20}, {"tz2", 3, 20}, {"tz3", 3, 20}, {"KT1", 3, 20},
+	}
+	keyHashPrefixes = []base58Prefix{
+		{"tz1", 3, 20}, {"tz2", 3, 20}, {"tz3", 3, 20},
+	}
+	keyPrefixes = []base58Prefix{
+		{"edpk", 4, 32}, {"sppk", 4, 33}, {"p2pk", 4, 33},
+	}
+	signaturePrefixes = []base58Prefix{
+		{"edsig", 5, 64}, {"spsig1", 5, 64}, {"p2sig", 4, 64}, {"sig", 3, 64},
+	}
+	chainIDPrefixes = []base58Prefix{
+		{"Net", 3, 4},
+	}
+)
+
+func isBase58(value string, prefixes []base58Prefix) bool {
+	for _, p := range prefixes {
+		if !strings.HasPrefix(value, p.prefix) {
+			continue
+		}
+		decoded := base58.Decode(value)
+		if len(decoded) != p.length+p.payload+4 {
+			return false
+		}
+		_, _, err := base58.CheckDecode(value)
+		return err == nil
+	}
+	return false
+}
+
+// isAddress - checks readable address with optional entrypoint (`KT1...%entrypoint`)
+func isAddress(value string) bool {
+	if idx := strings.IndexByte(value, '%'); idx > -1 {
+		if !isEntrypoint(value[idx+1:]) {
+			return false
+		}
+		value = value[:idx]
+	}
+	return isBase58(value, addressPrefixes)
+}
+
+func isEntrypoint(value string) bool {
+	return value != "" && len(value) <= 31
+}
+
+// isBinaryAddress - 22 bytes of address optionally followed by entrypoint
+func isBinaryAddress(data []byte) bool {
+	if len(data) < 22 {
+		return false
+	}
+	switch data[0] {
+	case 0x00:
+		if data[1] > 0x02 {
+			return false
+		}
+	case 0x01:
+		if data[21] != 0x00 {
+			return false
+		}
+	default:
+		return false
+	}
+	return len(data) == 22 || isEntrypoint(string(data[22:]))
+}
+
+func isBinaryKeyHash(data []byte) bool {
+	return len(data) == 21 && data[0] <= 0x02
+}
+
+func isBinaryKey(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	switch data[0] {
+	case 0x00:
+		return len(data) == 33
+	case 0x01, 0x02:
+		return len(data) == 34
+	default:
+		return false
+	}
+}
+
+func isTimestamp(value string) bool {
+	_, err := time.Parse(time.RFC3339, value)
+	return err == nil
+}
+
+func decodeBytes(value string) ([]byte, bool) {
+	data, err := hex.DecodeString(value)
+	return data, err == nil
+}
@@ -0,0 +1,150 @@
+package typechecker
+
+import (
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// LiteralFunc - converts literal `node` of type `typ`
+type LiteralFunc func(node gjson.Result, typ *Type) (interface{}, error)
+
+// MapLiterals - rebuilds value `node` of type `typ` replacing its literals (data pushed by lambdas too) by results of `fn`.
+// Pairs are rebuilt as nested binary pairs. The value has to be type checked by `CheckData` before.
+func MapLiterals(node gjson.Result, typ *Type, fn LiteralFunc) (interface{}, error) {
+	switch typ.Prim {
+	case consts.OPTION:
+		if node.Get(consts.KeyPrim).String() == consts.None {
+			return node.Value(), nil
+		}
+		value, err := MapLiterals(node.Get("args.0"), typ.Args[0], fn)
+		if err != nil {
+			return nil, err
+		}
+		return primitive(node, value), nil
+	case consts.OR:
+		argType := typ.Args[1]
+		if node.Get(consts.KeyPrim).String() == consts.Left {
+			argType = typ.Args[0]
+		}
+		value, err := MapLiterals(node.Get("args.0"), argType, fn)
+		if err != nil {
+			return nil, err
+		}
+		return primitive(node, value), nil
+	case consts.PAIR:
+		items := node.Get(consts.KeyArgs).Array()
+		if node.IsArray() {
+			items = node.Array()
+		}
+		return mapComb(items, typ, fn)
+	case consts.LIST, consts.SET:
+		items := node.Array()
+		result := make([]interface{}, len(items))
+		for i := range items {
+			value, err := MapLiterals(items[i], typ.Args[0], fn)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = value
+		}
+		return result, nil
+	case consts.MAP, consts.BIGMAP:
+		if !node.IsArray() {
+			return node.Value(), nil
+		}
+		items := node.Array()
+		result := make([]interface{}, len(items))
+		for i := range items {
+			key, err := MapLiterals(items[i].Get("args.0"), typ.Args[0], fn)
+			if err != nil {
+				return nil, err
+			}
+			value, err := MapLiterals(items[i].Get("args.1"), typ.Args[1], fn)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = primitive(items[i], key, value)
+		}
+		return result, nil
+	case consts.LAMBDA:
+		return mapCode(node, fn)
+	}
+	return fn(node, typ)
+}
+
+func mapComb(items []gjson.Result, typ *Type, fn LiteralFunc) (interface{}, error) {
+	if len(items) < 2 || typ.Prim != consts.PAIR {
+		return nil, errors.Errorf("invalid pair of type %s", typ)
+	}
+	left, err := MapLiterals(items[0], typ.Args[0], fn)
+	if err != nil {
+		return nil, err
+	}
+	var right interface{}
+	if len(items) == 2 {
+		right, err = MapLiterals(items[1], typ.Args[1], fn)
+	} else {
+		right, err = mapComb(items[1:], typ.Args[1], fn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		consts.KeyPrim: consts.Pair,
+		consts.KeyArgs: []interface{}{left, right},
+	}, nil
+}
+
+// mapCode - maps literals pushed by PUSH instructions of `code`
+func mapCode(code gjson.Result, fn LiteralFunc) (interface{}, error) {
+	if code.IsArray() {
+		items := code.Array()
+		result := make([]interface{}, len(items))
+		for i := range items {
+			value, err := mapCode(items[i], fn)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = value
+		}
+		return result, nil
+	}
+
+	args := code.Get(consts.KeyArgs).Array()
+	if len(args) == 0 {
+		return code.Value(), nil
+	}
+	result := make([]interface{}, len(args))
+	if code.Get(consts.KeyPrim).String() == "PUSH" && len(args) == 2 {
+		typ, err := ParseType(args[0], "")
+		if err != nil {
+			return nil, err
+		}
+		value, err := MapLiterals(args[1], typ, fn)
+		if err != nil {
+			return nil, err
+		}
+		return primitive(code, args[0].Value(), value), nil
+	}
+	for i := range args {
+		value, err := mapCode(args[i], fn)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = value
+	}
+	return primitive(code, result...), nil
+}
+
+// primitive - returns copy of primitive `node` with new arguments
+func primitive(node gjson.Result, args ...interface{}) map[string]interface{} {
+	result := map[string]interface{}{
+		consts.KeyPrim: node.Get(consts.KeyPrim).String(),
+		consts.KeyArgs: args,
+	}
+	if annots := node.Get(consts.KeyAnnots); annots.Exists() {
+		result[consts.KeyAnnots] = annots.Value()
+	}
+	return result
+}
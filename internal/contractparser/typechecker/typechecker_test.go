@@ -0,0 +1,132 @@
+package typechecker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		wantPath string
+		wantErr  string
+	}{
+		{
+			name:   "simple",
+			script: `[{"prim":"parameter","args":[{"prim":"int"}]},{"prim":"storage","args":[{"prim":"int"}]},{"prim":"code","args":[[{"prim":"UNPAIR"},{"prim":"ADD"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]]}]`,
+		}, {
+			name:     "sections in any order with macros",
+			script:   `[{"prim":"storage","args":[{"prim":"pair","args":[{"prim":"nat"},{"prim":"string"}]}]},{"prim":"code","args":[[{"prim":"DUP"},{"prim":"CAR"},{"prim":"DIP","args":[[{"prim":"CDR"}]]},{"prim":"IF_LEFT","args":[[{"prim":"SWAP"},{"prim":"SET_CAR"}],[{"prim":"DUUP"},{"prim":"CAR"},{"prim":"ASSERT_CMPGE"},{"prim":"SET_CDR"}]]},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]]},{"prim":"parameter","args":[{"prim":"or","args":[{"prim":"nat","annots":["%set_number"]},{"prim":"string","annots":["%set_name"]}]}]}]`,
+			wantPath: "1.args.0.3.args.1.2",
+			wantErr:  "ASSERT_CMPGE: can not compare nat and string",
+		}, {
+			name:     "wrong result stack",
+			script:   `[{"prim":"parameter","args":[{"prim":"unit"}]},{"prim":"storage","args":[{"prim":"int"}]},{"prim":"code","args":[[{"prim":"CDR"}]]}]`,
+			wantPath: "2.args.0",
+			wantErr:  "stack [(pair (list operation) int)] expected at the end of code, got [int]",
+		}, {
+			name:     "FAILWITH is not the last",
+			script:   `[{"prim":"parameter","args":[{"prim":"unit"}]},{"prim":"storage","args":[{"prim":"unit"}]},{"prim":"code","args":[[{"prim":"FAILWITH"},{"prim":"DROP"}]]}]`,
+			wantPath: "2.args.0.1",
+			wantErr:  "FAILWITH must be the last instruction of sequence",
+		}, {
+			name:   "failing branch",
+			script: `[{"prim":"parameter","args":[{"prim":"option","args":[{"prim":"int"}]}]},{"prim":"storage","args":[{"prim":"int"}]},{"prim":"code","args":[[{"prim":"UNPAIR"},{"prim":"IF_NONE","args":[[{"prim":"FAIL"}],[{"prim":"ADD"}]]},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]]}]`,
+		}, {
+			name:     "invalid PUSH literal",
+			script:   `[{"prim":"parameter","args":[{"prim":"unit"}]},{"prim":"storage","args":[{"prim":"nat"}]},{"prim":"code","args":[[{"prim":"DROP"},{"prim":"PUSH","args":[{"prim":"nat"},{"int":"-1"}]},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]]}]`,
+			wantPath: "2.args.0.1.args.1",
+			wantErr:  "natural number expected, got -1",
+		}, {
+			name:     "SELF in lambda",
+			script:   `[{"prim":"parameter","args":[{"prim":"unit"}]},{"prim":"storage","args":[{"prim":"unit"}]},{"prim":"code","args":[[{"prim":"LAMBDA","args":[{"prim":"unit"},{"prim":"address"},[{"prim":"DROP"},{"prim":"SELF"},{"prim":"ADDRESS"}]]},{"prim":"DROP"},{"prim":"CDR"},{"prim":"NIL","args":[{"prim":"operation"}]},{"prim":"PAIR"}]]}]`,
+			wantPath: "2.args.0.0.args.2.1",
+			wantErr:  "SELF is forbidden in lambdas",
+		}, {
+			name:     "unknown instruction",
+			script:   `[{"prim":"parameter","args":[{"prim":"unit"}]},{"prim":"storage","args":[{"prim":"unit"}]},{"prim":"code","args":[[{"prim":"FOO"}]]}]`,
+			wantPath: "2.args.0.0",
+			wantErr:  "unknown instruction FOO",
+		}, {
+			name:    "missing storage",
+			script:  `[{"prim":"parameter","args":[{"prim":"unit"}]},{"prim":"code","args":[[]]}]`,
+			wantErr: "storage section is missing",
+		}, {
+			name:     "big_map in big_map",
+			script:   `[{"prim":"parameter","args":[{"prim":"unit"}]},{"prim":"storage","args":[{"prim":"big_map","args":[{"prim":"nat"},{"prim":"big_map","args":[{"prim":"nat"},{"prim":"nat"}]}]}]},{"prim":"code","args":[[]]}]`,
+			wantPath: "1.args.0.args.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Check(gjson.Parse(tt.script))
+			if tt.wantErr == "" && tt.wantPath == "" {
+				assert.NoError(t, err)
+				return
+			}
+			errs, ok := err.(Errors)
+			if !assert.True(t, ok, "Errors expected, got %v", err) || !assert.Len(t, errs, 1) {
+				return
+			}
+			assert.Equal(t, tt.wantPath, errs[0].Path)
+			if tt.wantErr != "" {
+				assert.Equal(t, tt.wantErr, errs[0].Message)
+			}
+		})
+	}
+}
+
+func TestCheckData(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		typ     string
+		wantErr bool
+	}{
+		{
+			name: "comb",
+			data: `{"prim":"Pair","args":[{"int":"1"},{"string":"a"},{"prim":"True"}]}`,
+			typ:  `{"prim":"pair","args":[{"prim":"nat"},{"prim":"string"},{"prim":"bool"}]}`,
+		}, {
+			name: "address",
+			data: `{"string":"KT1PWx2mnDueood7fEmfbBDKx1D9BAnnXitn%transfer"}`,
+			typ:  `{"prim":"address"}`,
+		}, {
+			name:    "invalid address",
+			data:    `{"string":"KT1PWx2mnDueood7fEmfbBDKx1D9BAnnXitm"}`,
+			typ:     `{"prim":"address"}`,
+			wantErr: true,
+		}, {
+			name: "map",
+			data: `[{"prim":"Elt","args":[{"string":"a"},{"prim":"Some","args":[{"bytes":"00ff"}]}]}]`,
+			typ:  `{"prim":"map","args":[{"prim":"string"},{"prim":"option","args":[{"prim":"bytes"}]}]}`,
+		}, {
+			name:    "mutez overflow",
+			data:    `{"int":"9223372036854775808"}`,
+			typ:     `{"prim":"mutez"}`,
+			wantErr: true,
+		}, {
+			name: "lambda",
+			data: `[{"prim":"PUSH","args":[{"prim":"nat"},{"int":"1"}]},{"prim":"ADD"}]`,
+			typ:  `{"prim":"lambda","args":[{"prim":"nat"},{"prim":"nat"}]}`,
+		}, {
+			name:    "invalid lambda",
+			data:    `[{"prim":"PUSH","args":[{"prim":"int"},{"int":"1"}]},{"prim":"ADD"}]`,
+			typ:     `{"prim":"lambda","args":[{"prim":"nat"},{"prim":"nat"}]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, err := ParseType(gjson.Parse(tt.typ), "")
+			if !assert.NoError(t, err) {
+				return
+			}
+			err = CheckData(gjson.Parse(tt.data), typ)
+			assert.Equal(t, tt.wantErr, err != nil, "CheckData() error = %v", err)
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package typechecker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/tidwall/gjson"
+)
+
+// Type - Michelson type. Pairs with more than two arguments are stored as right combs.
+type Type struct {
+	Prim   string
+	Args   []*Type
+	Annots []string
+	// Size - memo size of sapling types
+	Size int64
+}
+
+// NewType -
+func NewType(prim string, args ...*Type) *Type {
+	return &Type{
+		Prim: prim,
+		Args: args,
+	}
+}
+
+// Equal - compares types ignoring annotations
+func (t *Type) Equal(other *Type) bool {
+	if t == nil || other == nil {
+		return t == other
+	}
+	if t.Prim != other.Prim || t.Size != other.Size || len(t.Args) != len(other.Args) {
+		return false
+	}
+	for i := range t.Args {
+		if !t.Args[i].Equal(other.Args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// String - returns type in Michelson notation
+func (t *Type) String() string {
+	if t == nil {
+		return "<nil>"
+	}
+	if len(t.Args) == 0 && t.Size == 0 {
+		return t.Prim
+	}
+	parts := []string{t.Prim}
+	if t.Size != 0 {
+		parts = append(parts, fmt.Sprintf("%d", t.Size))
+	}
+	for i := range t.Args {
+		parts = append(parts, t.Args[i].String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Micheline - returns Micheline JSON representation of the type
+func (t *Type) Micheline() interface{} {
+	result := map[string]interface{}{consts.KeyPrim: t.Prim}
+	args := make([]interface{}, 0, len(t.Args)+1)
+	if t.Size != 0 {
+		args = append(args, map[string]interface{}{consts.KeyInt: fmt.Sprintf("%d", t.Size)})
+	}
+	for i := range t.Args {
+		args = append(args, t.Args[i].Micheline())
+	}
+	if len(args) > 0 {
+		result[consts.KeyArgs] = args
+	}
+	if len(t.Annots) > 0 {
+		result[consts.KeyAnnots] = t.Annots
+	}
+	return result
+}
+
+// FieldAnnot - returns field annotation (`%name`) without prefix or empty string
+func (t *Type) FieldAnnot() string {
+	for i := range t.Annots {
+		if strings.HasPrefix(t.Annots[i], "%") {
+			return t.Annots[i][1:]
+		}
+	}
+	return ""
+}
+
+// IsComparable -
+func (t *Type) IsComparable() bool {
+	switch t.Prim {
+	case consts.INT, consts.NAT, consts.STRING, consts.BYTES, consts.MUTEZ, consts.BOOL, consts.KEYHASH, consts.TIMESTAMP,
+		consts.ADDRESS, consts.KEY, consts.UNIT, consts.SIGNATURE, consts.CHAINID, consts.NEVER:
+		return true
+	case consts.PAIR, consts.OPTION, consts.OR:
+		return t.all((*Type).IsComparable)
+	default:
+		return false
+	}
+}
+
+// IsPackable - values of type can be serialized with PACK
+func (t *Type) IsPackable() bool {
+	switch t.Prim {
+	case consts.OPERATION, consts.BIGMAP, consts.SAPLINGSTATE, consts.TICKET:
+		return false
+	case consts.LAMBDA:
+		return true
+	default:
+		return t.all((*Type).IsPackable)
+	}
+}
+
+// IsPushable - values of type can be written as literals
+func (t *Type) IsPushable() bool {
+	switch t.Prim {
+	case consts.OPERATION, consts.BIGMAP, consts.SAPLINGSTATE, consts.TICKET, consts.CONTRACT:
+		return false
+	case consts.LAMBDA:
+		return true
+	default:
+		return t.all((*Type).IsPushable)
+	}
+}
+
+// IsStorable - type can be used in storage
+func (t *Type) IsStorable() bool {
+	switch t.Prim {
+	case consts.OPERATION, consts.CONTRACT:
+		return false
+	case consts.LAMBDA:
+		return true
+	default:
+		return t.all((*Type).IsStorable)
+	}
+}
+
+// IsPassable - type can be used in parameter
+func (t *Type) IsPassable() bool {
+	switch t.Prim {
+	case consts.OPERATION:
+		return false
+	case consts.LAMBDA:
+		return true
+	default:
+		return t.all((*Type).IsPassable)
+	}
+}
+
+// IsBigMapValue - type can be used as value of big map
+func (t *Type) IsBigMapValue() bool {
+	switch t.Prim {
+	case consts.OPERATION, consts.BIGMAP, consts.SAPLINGSTATE:
+		return false
+	case consts.LAMBDA:
+		return true
+	default:
+		return t.all((*Type).IsBigMapValue)
+	}
+}
+
+// IsDuplicable - values of type can be copied with DUP
+func (t *Type) IsDuplicable() bool {
+	switch t.Prim {
+	case consts.TICKET:
+		return false
+	case consts.LAMBDA:
+		return true
+	default:
+		return t.all((*Type).IsDuplicable)
+	}
+}
+
+func (t *Type) all(predicate func(*Type) bool) bool {
+	for i := range t.Args {
+		if !predicate(t.Args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+var typeArgsCount = map[string]int{
+	consts.INT: 0, consts.NAT: 0, consts.STRING: 0, consts.BYTES: 0, consts.MUTEZ: 0, consts.BOOL: 0,
+	consts.KEYHASH: 0, consts.TIMESTAMP: 0, consts.ADDRESS: 0, consts.KEY: 0, consts.UNIT: 0,
+	consts.SIGNATURE: 0, consts.OPERATION: 0, consts.CHAINID: 0, consts.NEVER: 0,
+	consts.BLS12381G1: 0, consts.BLS12381G2: 0, consts.BLS12381FR: 0,
+	consts.OPTION: 1, consts.LIST: 1, consts.SET: 1, consts.CONTRACT: 1, consts.TICKET: 1,
+	consts.OR: 2, consts.LAMBDA: 2, consts.MAP: 2, consts.BIGMAP: 2,
+}
+
+// ParseType - parses Micheline type located at `path`
+func ParseType(node gjson.Result, path string) (*Type, error) {
+	if !node.IsObject() {
+		return nil, newError(path, "type expected")
+	}
+	prim := node.Get(consts.KeyPrim).String()
+	args := node.Get(consts.KeyArgs).Array()
+
+	typ := &Type{
+		Prim: prim,
+	}
+	for _, annot := range node.Get(consts.KeyAnnots).Array() {
+		typ.Annots = append(typ.Annots, annot.String())
+	}
+
+	switch prim {
+	case consts.PAIR:
+		if len(args) < 2 {
+			return nil, newError(path, "pair expects at least 2 arguments, got %d", len(args))
+		}
+	case consts.SAPLINGSTATE, consts.SAPLINGTRANSACTION:
+		if len(args) != 1 || !args[0].Get(consts.KeyInt).Exists() {
+			return nil, newError(path, "%s expects memo size", prim)
+		}
+		typ.Size = args[0].Get(consts.KeyInt).Int()
+		return typ, nil
+	default:
+		count, ok := typeArgsCount[prim]
+		if !ok {
+			return nil, newError(path, "unknown type %s", prim)
+		}
+		if len(args) != count {
+			return nil, newError(path, "%s expects %d arguments, got %d", prim, count, len(args))
+		}
+	}
+
+	for i := range args {
+		arg, err := ParseType(args[i], argPath(path, i))
+		if err != nil {
+			return nil, err
+		}
+		typ.Args = append(typ.Args, arg)
+	}
+
+	if prim == consts.PAIR && len(typ.Args) > 2 {
+		typ.Args = []*Type{typ.Args[0], combType(typ.Args[1:])}
+	}
+
+	switch prim {
+	case consts.SET, consts.TICKET:
+		if !typ.Args[0].IsComparable() {
+			return nil, newError(argPath(path, 0), "comparable type expected, got %s", typ.Args[0])
+		}
+	case consts.MAP:
+		if !typ.Args[0].IsComparable() {
+			return nil, newError(argPath(path, 0), "comparable type expected, got %s", typ.Args[0])
+		}
+	case consts.BIGMAP:
+		if !typ.Args[0].IsComparable() {
+			return nil, newError(argPath(path, 0), "comparable type expected, got %s", typ.Args[0])
+		}
+		if !typ.Args[1].IsBigMapValue() {
+			return nil, newError(argPath(path, 1), "%s can not be used as big map value", typ.Args[1])
+		}
+	}
+	return typ, nil
+}
+
+func combType(args []*Type) *Type {
+	if len(args) == 1 {
+		return args[0]
+	}
+	return NewType(consts.PAIR, args[0], combType(args[1:]))
+}
+
+func argPath(path string, idx int) string {
+	return joinPath(path, fmt.Sprintf("args.%d", idx))
+}
+
+func joinPath(path, sub string) string {
+	if path == "" {
+		return sub
+	}
+	return path + "." + sub
+}
@@ -0,0 +1,23 @@
+package unpack
+
+import (
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/pkg/errors"
+)
+
+// Literal - returns readable form of binary (optimized) literal `input` of type `typ`
+func Literal(typ, input string) (string, error) {
+	switch typ {
+	case consts.ADDRESS, consts.CONTRACT:
+		return Contract(input)
+	case consts.KEYHASH:
+		return KeyHash(input)
+	case consts.KEY:
+		return PublicKey(input)
+	case consts.SIGNATURE:
+		return Signature(input)
+	case consts.CHAINID:
+		return ChainID(input)
+	}
+	return "", errors.Errorf("%s has no binary form", typ)
+}
@@ -0,0 +1,139 @@
+package rawbytes
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Decode - decodes binary micheline `data` (without watermark) as is: bytes are never interpreted as addresses or nested data.
+func Decode(data []byte) (interface{}, error) {
+	node, n, err := decodeNode(data)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(data) {
+		return nil, errors.Errorf("input is not empty")
+	}
+	return node, nil
+}
+
+func decodeNode(data []byte) (interface{}, int, error) {
+	if len(data) == 0 {
+		return nil, 0, errors.Errorf("unexpected end of data")
+	}
+	tag, body := data[0], data[1:]
+	switch tag {
+	case 0x00:
+		for i := range body {
+			if body[i] < 0x80 {
+				value, err := intDecoder{}.DecodeSigned(body[:i+1])
+				if err != nil {
+					return nil, 0, err
+				}
+				return map[string]interface{}{"int": value}, i + 2, nil
+			}
+		}
+		return nil, 0, errors.Errorf("unexpected end of int")
+	case 0x01, 0x0a:
+		value, n, err := decodeBlock(body)
+		if err != nil {
+			return nil, 0, err
+		}
+		if tag == 0x01 {
+			return map[string]interface{}{"string": string(value)}, n + 1, nil
+		}
+		return map[string]interface{}{"bytes": hex.EncodeToString(value)}, n + 1, nil
+	case 0x02:
+		value, n, err := decodeBlock(body)
+		if err != nil {
+			return nil, 0, err
+		}
+		items, err := decodeSequence(value)
+		if err != nil {
+			return nil, 0, err
+		}
+		return items, n + 1, nil
+	case 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09:
+		node, n, err := decodePrimitive(tag, body)
+		return node, n + 1, err
+	}
+	return nil, 0, errors.Errorf("unknown tag %x", tag)
+}
+
+func decodePrimitive(tag byte, data []byte) (interface{}, int, error) {
+	if len(data) == 0 || int(data[0]) >= len(primKeywords) {
+		return nil, 0, errors.Errorf("invalid prim keyword")
+	}
+	node := map[string]interface{}{"prim": primKeywords[data[0]]}
+	offset := 1
+
+	var args []interface{}
+	if tag == 0x09 {
+		value, n, err := decodeBlock(data[offset:])
+		if err != nil {
+			return nil, 0, err
+		}
+		if args, err = decodeSequence(value); err != nil {
+			return nil, 0, err
+		}
+		offset += n
+	} else {
+		count := int(tag-0x03) / 2
+		for i := 0; i < count; i++ {
+			arg, n, err := decodeNode(data[offset:])
+			if err != nil {
+				return nil, 0, err
+			}
+			args = append(args, arg)
+			offset += n
+		}
+	}
+	if len(args) > 0 {
+		node["args"] = args
+	}
+
+	if tag == 0x09 || tag%2 == 0 {
+		value, n, err := decodeBlock(data[offset:])
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(value) > 0 {
+			annots := strings.Split(string(value), " ")
+			result := make([]interface{}, len(annots))
+			for i := range annots {
+				result[i] = annots[i]
+			}
+			node["annots"] = result
+		}
+		offset += n
+	}
+	return node, offset, nil
+}
+
+func decodeSequence(data []byte) ([]interface{}, error) {
+	items := make([]interface{}, 0)
+	for offset := 0; offset < len(data); {
+		item, n, err := decodeNode(data[offset:])
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		offset += n
+	}
+	return items, nil
+}
+
+// decodeBlock - returns data prefixed by 4-byte length and count of read bytes
+func decodeBlock(data []byte) ([]byte, int, error) {
+	if len(data) < 4 {
+		return nil, 0, errors.Errorf("unexpected end of data")
+	}
+	length := int(binary.BigEndian.Uint32(data))
+	if len(data) < 4+length {
+		return nil, 0, errors.Errorf("not enough data: %d < %d", len(data)-4, length)
+	}
+	return data[4 : 4+length], 4 + length, nil
+}
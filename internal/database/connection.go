@@ -0,0 +1,44 @@
+package database
+
+import (
+	"sync"
+
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	connections    = make(map[string]*gorm.DB)
+	connectionsMux sync.Mutex
+)
+
+// Connect - returns connection to postgres by `connectionString`. Connection is shared by all users of the same database,
+// so application tables and postgres storage of indexed data use one pool.
+func Connect(connectionString string) (*gorm.DB, error) {
+	connectionsMux.Lock()
+	defer connectionsMux.Unlock()
+
+	if conn, ok := connections[connectionString]; ok {
+		return conn, nil
+	}
+
+	conn, err := gorm.Open("postgres", connectionString)
+	if err != nil {
+		return nil, err
+	}
+	conn.LogMode(false)
+
+	connections[connectionString] = conn
+	return conn, nil
+}
+
+func disconnect(conn *gorm.DB) error {
+	connectionsMux.Lock()
+	defer connectionsMux.Unlock()
+
+	for connectionString, c := range connections {
+		if c.DB() == conn.DB() {
+			delete(connections, connectionString)
+		}
+	}
+	return conn.Close()
+}
@@ -19,6 +19,7 @@ type DB interface {
 	ISubscription
 	IUser
 	IVerification
+	IWebhook
 
 	Close()
 }
@@ -65,6 +66,7 @@ type ISubscription interface {
 	UpsertSubscription(s *Subscription) error
 	DeleteSubscription(s *Subscription) error
 	GetSubscriptionsCount(address, network string) (int, error)
+	GetSubscriptionsByMask(network string, mask uint) ([]Subscription, error)
 }
 
 // IUser -
@@ -82,19 +84,31 @@ type IVerification interface {
 	CountVerifications(userID uint) (int64, error)
 }
 
+// IWebhook -
+type IWebhook interface {
+	CreateWebhook(w *Webhook) error
+	GetWebhook(id uint) (Webhook, error)
+	ListWebhooks(userID uint) ([]Webhook, error)
+	GetActiveWebhooks(subscriptionIDs ...uint) ([]Webhook, error)
+	DeleteWebhook(userID, id uint) error
+	DeleteSubscriptionWebhooks(subscriptionID uint) error
+	CreateWebhookDelivery(delivery *WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *WebhookDelivery) error
+	ClaimWebhookDeliveries(ts time.Time, lease time.Duration, limit uint) ([]WebhookDelivery, error)
+	ListWebhookDeliveries(webhookID, limit, offset uint) ([]WebhookDelivery, error)
+}
+
 type db struct {
 	*gorm.DB
 }
 
 // New - creates db connection
 func New(connectionString string) (DB, error) {
-	gormDB, err := gorm.Open("postgres", connectionString)
+	gormDB, err := Connect(connectionString)
 	if err != nil {
 		return nil, err
 	}
 
-	gormDB.LogMode(false)
-
 	gormDB.AutoMigrate(
 		&User{},
 		&Subscription{},
@@ -104,6 +118,8 @@ func New(connectionString string) (DB, error) {
 		&CompilationTaskResult{},
 		&Verification{},
 		&Deployment{},
+		&Webhook{},
+		&WebhookDelivery{},
 	)
 
 	gormDB = gormDB.Set("gorm:auto_preload", false)
@@ -128,5 +144,7 @@ func WaitNew(connectionString string, timeout int) DB {
 }
 
 func (d *db) Close() {
-	d.DB.Close()
+	if err := disconnect(d.DB); err != nil {
+		logger.Error(err)
+	}
 }
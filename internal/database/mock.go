@@ -7,6 +7,7 @@ package database
 import (
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockDB is a mock of DB interface
@@ -75,18 +76,18 @@ func (mr *MockDBMockRecorder) CreateOrUpdateAssessment(a interface{}) *gomock.Ca
 }
 
 // GetAssessmentsWithValue mocks base method
-func (m *MockDB) GetAssessmentsWithValue(arg0, arg1, arg2 uint) ([]Assessments, error) {
+func (m *MockDB) GetAssessmentsWithValue(userID, assessment, size uint) ([]Assessments, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAssessmentsWithValue", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "GetAssessmentsWithValue", userID, assessment, size)
 	ret0, _ := ret[0].([]Assessments)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAssessmentsWithValue indicates an expected call of GetAssessmentsWithValue
-func (mr *MockDBMockRecorder) GetAssessmentsWithValue(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockDBMockRecorder) GetAssessmentsWithValue(userID, assessment, size interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssessmentsWithValue", reflect.TypeOf((*MockDB)(nil).GetAssessmentsWithValue), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssessmentsWithValue", reflect.TypeOf((*MockDB)(nil).GetAssessmentsWithValue), userID, assessment, size)
 }
 
 // GetUserCompletedAssesments mocks base method
@@ -250,6 +251,21 @@ func (mr *MockDBMockRecorder) GetDeploymentBy(opHash interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeploymentBy", reflect.TypeOf((*MockDB)(nil).GetDeploymentBy), opHash)
 }
 
+// GetDeploymentsByAddressNetwork mocks base method
+func (m *MockDB) GetDeploymentsByAddressNetwork(address, network string) ([]Deployment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeploymentsByAddressNetwork", address, network)
+	ret0, _ := ret[0].([]Deployment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeploymentsByAddressNetwork indicates an expected call of GetDeploymentsByAddressNetwork
+func (mr *MockDBMockRecorder) GetDeploymentsByAddressNetwork(address, network interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeploymentsByAddressNetwork", reflect.TypeOf((*MockDB)(nil).GetDeploymentsByAddressNetwork), address, network)
+}
+
 // UpdateDeployment mocks base method
 func (m *MockDB) UpdateDeployment(dt *Deployment) error {
 	m.ctrl.T.Helper()
@@ -325,31 +341,31 @@ func (mr *MockDBMockRecorder) ListSubscriptions(userID interface{}) *gomock.Call
 }
 
 // UpsertSubscription mocks base method
-func (m *MockDB) UpsertSubscription(arg0 *Subscription) error {
+func (m *MockDB) UpsertSubscription(s *Subscription) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpsertSubscription", arg0)
+	ret := m.ctrl.Call(m, "UpsertSubscription", s)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpsertSubscription indicates an expected call of UpsertSubscription
-func (mr *MockDBMockRecorder) UpsertSubscription(arg0 interface{}) *gomock.Call {
+func (mr *MockDBMockRecorder) UpsertSubscription(s interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertSubscription", reflect.TypeOf((*MockDB)(nil).UpsertSubscription), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertSubscription", reflect.TypeOf((*MockDB)(nil).UpsertSubscription), s)
 }
 
 // DeleteSubscription mocks base method
-func (m *MockDB) DeleteSubscription(arg0 *Subscription) error {
+func (m *MockDB) DeleteSubscription(s *Subscription) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSubscription", arg0)
+	ret := m.ctrl.Call(m, "DeleteSubscription", s)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteSubscription indicates an expected call of DeleteSubscription
-func (mr *MockDBMockRecorder) DeleteSubscription(arg0 interface{}) *gomock.Call {
+func (mr *MockDBMockRecorder) DeleteSubscription(s interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockDB)(nil).DeleteSubscription), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockDB)(nil).DeleteSubscription), s)
 }
 
 // GetSubscriptionsCount mocks base method
@@ -367,47 +383,62 @@ func (mr *MockDBMockRecorder) GetSubscriptionsCount(address, network interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionsCount", reflect.TypeOf((*MockDB)(nil).GetSubscriptionsCount), address, network)
 }
 
+// GetSubscriptionsByMask mocks base method
+func (m *MockDB) GetSubscriptionsByMask(network string, mask uint) ([]Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptionsByMask", network, mask)
+	ret0, _ := ret[0].([]Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptionsByMask indicates an expected call of GetSubscriptionsByMask
+func (mr *MockDBMockRecorder) GetSubscriptionsByMask(network, mask interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionsByMask", reflect.TypeOf((*MockDB)(nil).GetSubscriptionsByMask), network, mask)
+}
+
 // GetOrCreateUser mocks base method
-func (m *MockDB) GetOrCreateUser(arg0 *User, arg1 string) error {
+func (m *MockDB) GetOrCreateUser(u *User, token string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrCreateUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetOrCreateUser", u, token)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // GetOrCreateUser indicates an expected call of GetOrCreateUser
-func (mr *MockDBMockRecorder) GetOrCreateUser(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockDBMockRecorder) GetOrCreateUser(u, token interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateUser", reflect.TypeOf((*MockDB)(nil).GetOrCreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateUser", reflect.TypeOf((*MockDB)(nil).GetOrCreateUser), u, token)
 }
 
 // GetUser mocks base method
-func (m *MockDB) GetUser(arg0 uint) (*User, error) {
+func (m *MockDB) GetUser(userID uint) (*User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0)
+	ret := m.ctrl.Call(m, "GetUser", userID)
 	ret0, _ := ret[0].(*User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUser indicates an expected call of GetUser
-func (mr *MockDBMockRecorder) GetUser(arg0 interface{}) *gomock.Call {
+func (mr *MockDBMockRecorder) GetUser(userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockDB)(nil).GetUser), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockDB)(nil).GetUser), userID)
 }
 
 // UpdateUserMarkReadAt mocks base method
-func (m *MockDB) UpdateUserMarkReadAt(arg0 uint, arg1 int64) error {
+func (m *MockDB) UpdateUserMarkReadAt(userID uint, ts int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUserMarkReadAt", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateUserMarkReadAt", userID, ts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateUserMarkReadAt indicates an expected call of UpdateUserMarkReadAt
-func (mr *MockDBMockRecorder) UpdateUserMarkReadAt(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockDBMockRecorder) UpdateUserMarkReadAt(userID, ts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserMarkReadAt", reflect.TypeOf((*MockDB)(nil).UpdateUserMarkReadAt), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserMarkReadAt", reflect.TypeOf((*MockDB)(nil).UpdateUserMarkReadAt), userID, ts)
 }
 
 // ListVerifications mocks base method
@@ -469,6 +500,155 @@ func (mr *MockDBMockRecorder) CountVerifications(userID interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountVerifications", reflect.TypeOf((*MockDB)(nil).CountVerifications), userID)
 }
 
+// CreateWebhook mocks base method
+func (m *MockDB) CreateWebhook(w *Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook
+func (mr *MockDBMockRecorder) CreateWebhook(w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockDB)(nil).CreateWebhook), w)
+}
+
+// GetWebhook mocks base method
+func (m *MockDB) GetWebhook(id uint) (Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", id)
+	ret0, _ := ret[0].(Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook
+func (mr *MockDBMockRecorder) GetWebhook(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockDB)(nil).GetWebhook), id)
+}
+
+// ListWebhooks mocks base method
+func (m *MockDB) ListWebhooks(userID uint) ([]Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", userID)
+	ret0, _ := ret[0].([]Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks
+func (mr *MockDBMockRecorder) ListWebhooks(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockDB)(nil).ListWebhooks), userID)
+}
+
+// GetActiveWebhooks mocks base method
+func (m *MockDB) GetActiveWebhooks(subscriptionIDs ...uint) ([]Webhook, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range subscriptionIDs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetActiveWebhooks", varargs...)
+	ret0, _ := ret[0].([]Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveWebhooks indicates an expected call of GetActiveWebhooks
+func (mr *MockDBMockRecorder) GetActiveWebhooks(subscriptionIDs ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveWebhooks", reflect.TypeOf((*MockDB)(nil).GetActiveWebhooks), subscriptionIDs...)
+}
+
+// DeleteWebhook mocks base method
+func (m *MockDB) DeleteWebhook(userID, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook
+func (mr *MockDBMockRecorder) DeleteWebhook(userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockDB)(nil).DeleteWebhook), userID, id)
+}
+
+// DeleteSubscriptionWebhooks mocks base method
+func (m *MockDB) DeleteSubscriptionWebhooks(subscriptionID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscriptionWebhooks", subscriptionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscriptionWebhooks indicates an expected call of DeleteSubscriptionWebhooks
+func (mr *MockDBMockRecorder) DeleteSubscriptionWebhooks(subscriptionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscriptionWebhooks", reflect.TypeOf((*MockDB)(nil).DeleteSubscriptionWebhooks), subscriptionID)
+}
+
+// CreateWebhookDelivery mocks base method
+func (m *MockDB) CreateWebhookDelivery(delivery *WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery
+func (mr *MockDBMockRecorder) CreateWebhookDelivery(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockDB)(nil).CreateWebhookDelivery), delivery)
+}
+
+// UpdateWebhookDelivery mocks base method
+func (m *MockDB) UpdateWebhookDelivery(delivery *WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDelivery indicates an expected call of UpdateWebhookDelivery
+func (mr *MockDBMockRecorder) UpdateWebhookDelivery(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDelivery", reflect.TypeOf((*MockDB)(nil).UpdateWebhookDelivery), delivery)
+}
+
+// ClaimWebhookDeliveries mocks base method
+func (m *MockDB) ClaimWebhookDeliveries(ts time.Time, lease time.Duration, limit uint) ([]WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", ts, lease, limit)
+	ret0, _ := ret[0].([]WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries
+func (mr *MockDBMockRecorder) ClaimWebhookDeliveries(ts, lease, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockDB)(nil).ClaimWebhookDeliveries), ts, lease, limit)
+}
+
+// ListWebhookDeliveries mocks base method
+func (m *MockDB) ListWebhookDeliveries(webhookID, limit, offset uint) ([]WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", webhookID, limit, offset)
+	ret0, _ := ret[0].([]WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries
+func (mr *MockDBMockRecorder) ListWebhookDeliveries(webhookID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockDB)(nil).ListWebhookDeliveries), webhookID, limit, offset)
+}
+
 // Close mocks base method
 func (m *MockDB) Close() {
 	m.ctrl.T.Helper()
@@ -570,18 +750,18 @@ func (mr *MockIAssessmentMockRecorder) CreateOrUpdateAssessment(a interface{}) *
 }
 
 // GetAssessmentsWithValue mocks base method
-func (m *MockIAssessment) GetAssessmentsWithValue(arg0, arg1, arg2 uint) ([]Assessments, error) {
+func (m *MockIAssessment) GetAssessmentsWithValue(userID, assessment, size uint) ([]Assessments, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAssessmentsWithValue", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "GetAssessmentsWithValue", userID, assessment, size)
 	ret0, _ := ret[0].([]Assessments)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAssessmentsWithValue indicates an expected call of GetAssessmentsWithValue
-func (mr *MockIAssessmentMockRecorder) GetAssessmentsWithValue(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockIAssessmentMockRecorder) GetAssessmentsWithValue(userID, assessment, size interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssessmentsWithValue", reflect.TypeOf((*MockIAssessment)(nil).GetAssessmentsWithValue), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssessmentsWithValue", reflect.TypeOf((*MockIAssessment)(nil).GetAssessmentsWithValue), userID, assessment, size)
 }
 
 // GetUserCompletedAssesments mocks base method
@@ -791,6 +971,21 @@ func (mr *MockIDeploymentMockRecorder) GetDeploymentBy(opHash interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeploymentBy", reflect.TypeOf((*MockIDeployment)(nil).GetDeploymentBy), opHash)
 }
 
+// GetDeploymentsByAddressNetwork mocks base method
+func (m *MockIDeployment) GetDeploymentsByAddressNetwork(address, network string) ([]Deployment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeploymentsByAddressNetwork", address, network)
+	ret0, _ := ret[0].([]Deployment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeploymentsByAddressNetwork indicates an expected call of GetDeploymentsByAddressNetwork
+func (mr *MockIDeploymentMockRecorder) GetDeploymentsByAddressNetwork(address, network interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeploymentsByAddressNetwork", reflect.TypeOf((*MockIDeployment)(nil).GetDeploymentsByAddressNetwork), address, network)
+}
+
 // UpdateDeployment mocks base method
 func (m *MockIDeployment) UpdateDeployment(dt *Deployment) error {
 	m.ctrl.T.Helper()
@@ -889,31 +1084,31 @@ func (mr *MockISubscriptionMockRecorder) ListSubscriptions(userID interface{}) *
 }
 
 // UpsertSubscription mocks base method
-func (m *MockISubscription) UpsertSubscription(arg0 *Subscription) error {
+func (m *MockISubscription) UpsertSubscription(s *Subscription) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpsertSubscription", arg0)
+	ret := m.ctrl.Call(m, "UpsertSubscription", s)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpsertSubscription indicates an expected call of UpsertSubscription
-func (mr *MockISubscriptionMockRecorder) UpsertSubscription(arg0 interface{}) *gomock.Call {
+func (mr *MockISubscriptionMockRecorder) UpsertSubscription(s interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertSubscription", reflect.TypeOf((*MockISubscription)(nil).UpsertSubscription), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertSubscription", reflect.TypeOf((*MockISubscription)(nil).UpsertSubscription), s)
 }
 
 // DeleteSubscription mocks base method
-func (m *MockISubscription) DeleteSubscription(arg0 *Subscription) error {
+func (m *MockISubscription) DeleteSubscription(s *Subscription) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSubscription", arg0)
+	ret := m.ctrl.Call(m, "DeleteSubscription", s)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteSubscription indicates an expected call of DeleteSubscription
-func (mr *MockISubscriptionMockRecorder) DeleteSubscription(arg0 interface{}) *gomock.Call {
+func (mr *MockISubscriptionMockRecorder) DeleteSubscription(s interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockISubscription)(nil).DeleteSubscription), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockISubscription)(nil).DeleteSubscription), s)
 }
 
 // GetSubscriptionsCount mocks base method
@@ -931,6 +1126,21 @@ func (mr *MockISubscriptionMockRecorder) GetSubscriptionsCount(address, network
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionsCount", reflect.TypeOf((*MockISubscription)(nil).GetSubscriptionsCount), address, network)
 }
 
+// GetSubscriptionsByMask mocks base method
+func (m *MockISubscription) GetSubscriptionsByMask(network string, mask uint) ([]Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptionsByMask", network, mask)
+	ret0, _ := ret[0].([]Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptionsByMask indicates an expected call of GetSubscriptionsByMask
+func (mr *MockISubscriptionMockRecorder) GetSubscriptionsByMask(network, mask interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionsByMask", reflect.TypeOf((*MockISubscription)(nil).GetSubscriptionsByMask), network, mask)
+}
+
 // MockIUser is a mock of IUser interface
 type MockIUser struct {
 	ctrl     *gomock.Controller
@@ -955,46 +1165,46 @@ func (m *MockIUser) EXPECT() *MockIUserMockRecorder {
 }
 
 // GetOrCreateUser mocks base method
-func (m *MockIUser) GetOrCreateUser(arg0 *User, arg1 string) error {
+func (m *MockIUser) GetOrCreateUser(u *User, token string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrCreateUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetOrCreateUser", u, token)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // GetOrCreateUser indicates an expected call of GetOrCreateUser
-func (mr *MockIUserMockRecorder) GetOrCreateUser(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockIUserMockRecorder) GetOrCreateUser(u, token interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateUser", reflect.TypeOf((*MockIUser)(nil).GetOrCreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateUser", reflect.TypeOf((*MockIUser)(nil).GetOrCreateUser), u, token)
 }
 
 // GetUser mocks base method
-func (m *MockIUser) GetUser(arg0 uint) (*User, error) {
+func (m *MockIUser) GetUser(userID uint) (*User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0)
+	ret := m.ctrl.Call(m, "GetUser", userID)
 	ret0, _ := ret[0].(*User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUser indicates an expected call of GetUser
-func (mr *MockIUserMockRecorder) GetUser(arg0 interface{}) *gomock.Call {
+func (mr *MockIUserMockRecorder) GetUser(userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockIUser)(nil).GetUser), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockIUser)(nil).GetUser), userID)
 }
 
 // UpdateUserMarkReadAt mocks base method
-func (m *MockIUser) UpdateUserMarkReadAt(arg0 uint, arg1 int64) error {
+func (m *MockIUser) UpdateUserMarkReadAt(userID uint, ts int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUserMarkReadAt", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateUserMarkReadAt", userID, ts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateUserMarkReadAt indicates an expected call of UpdateUserMarkReadAt
-func (mr *MockIUserMockRecorder) UpdateUserMarkReadAt(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockIUserMockRecorder) UpdateUserMarkReadAt(userID, ts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserMarkReadAt", reflect.TypeOf((*MockIUser)(nil).UpdateUserMarkReadAt), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserMarkReadAt", reflect.TypeOf((*MockIUser)(nil).UpdateUserMarkReadAt), userID, ts)
 }
 
 // MockIVerification is a mock of IVerification interface
@@ -1078,3 +1288,175 @@ func (mr *MockIVerificationMockRecorder) CountVerifications(userID interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountVerifications", reflect.TypeOf((*MockIVerification)(nil).CountVerifications), userID)
 }
+
+// MockIWebhook is a mock of IWebhook interface
+type MockIWebhook struct {
+	ctrl     *gomock.Controller
+	recorder *MockIWebhookMockRecorder
+}
+
+// MockIWebhookMockRecorder is the mock recorder for MockIWebhook
+type MockIWebhookMockRecorder struct {
+	mock *MockIWebhook
+}
+
+// NewMockIWebhook creates a new mock instance
+func NewMockIWebhook(ctrl *gomock.Controller) *MockIWebhook {
+	mock := &MockIWebhook{ctrl: ctrl}
+	mock.recorder = &MockIWebhookMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIWebhook) EXPECT() *MockIWebhookMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method
+func (m *MockIWebhook) CreateWebhook(w *Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook
+func (mr *MockIWebhookMockRecorder) CreateWebhook(w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockIWebhook)(nil).CreateWebhook), w)
+}
+
+// GetWebhook mocks base method
+func (m *MockIWebhook) GetWebhook(id uint) (Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", id)
+	ret0, _ := ret[0].(Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook
+func (mr *MockIWebhookMockRecorder) GetWebhook(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockIWebhook)(nil).GetWebhook), id)
+}
+
+// ListWebhooks mocks base method
+func (m *MockIWebhook) ListWebhooks(userID uint) ([]Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", userID)
+	ret0, _ := ret[0].([]Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks
+func (mr *MockIWebhookMockRecorder) ListWebhooks(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockIWebhook)(nil).ListWebhooks), userID)
+}
+
+// GetActiveWebhooks mocks base method
+func (m *MockIWebhook) GetActiveWebhooks(subscriptionIDs ...uint) ([]Webhook, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range subscriptionIDs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetActiveWebhooks", varargs...)
+	ret0, _ := ret[0].([]Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveWebhooks indicates an expected call of GetActiveWebhooks
+func (mr *MockIWebhookMockRecorder) GetActiveWebhooks(subscriptionIDs ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveWebhooks", reflect.TypeOf((*MockIWebhook)(nil).GetActiveWebhooks), subscriptionIDs...)
+}
+
+// DeleteWebhook mocks base method
+func (m *MockIWebhook) DeleteWebhook(userID, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook
+func (mr *MockIWebhookMockRecorder) DeleteWebhook(userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockIWebhook)(nil).DeleteWebhook), userID, id)
+}
+
+// DeleteSubscriptionWebhooks mocks base method
+func (m *MockIWebhook) DeleteSubscriptionWebhooks(subscriptionID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscriptionWebhooks", subscriptionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscriptionWebhooks indicates an expected call of DeleteSubscriptionWebhooks
+func (mr *MockIWebhookMockRecorder) DeleteSubscriptionWebhooks(subscriptionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscriptionWebhooks", reflect.TypeOf((*MockIWebhook)(nil).DeleteSubscriptionWebhooks), subscriptionID)
+}
+
+// CreateWebhookDelivery mocks base method
+func (m *MockIWebhook) CreateWebhookDelivery(delivery *WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery
+func (mr *MockIWebhookMockRecorder) CreateWebhookDelivery(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockIWebhook)(nil).CreateWebhookDelivery), delivery)
+}
+
+// UpdateWebhookDelivery mocks base method
+func (m *MockIWebhook) UpdateWebhookDelivery(delivery *WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDelivery", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDelivery indicates an expected call of UpdateWebhookDelivery
+func (mr *MockIWebhookMockRecorder) UpdateWebhookDelivery(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDelivery", reflect.TypeOf((*MockIWebhook)(nil).UpdateWebhookDelivery), delivery)
+}
+
+// ClaimWebhookDeliveries mocks base method
+func (m *MockIWebhook) ClaimWebhookDeliveries(ts time.Time, lease time.Duration, limit uint) ([]WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", ts, lease, limit)
+	ret0, _ := ret[0].([]WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries
+func (mr *MockIWebhookMockRecorder) ClaimWebhookDeliveries(ts, lease, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockIWebhook)(nil).ClaimWebhookDeliveries), ts, lease, limit)
+}
+
+// ListWebhookDeliveries mocks base method
+func (m *MockIWebhook) ListWebhookDeliveries(webhookID, limit, offset uint) ([]WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", webhookID, limit, offset)
+	ret0, _ := ret[0].([]WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries
+func (mr *MockIWebhookMockRecorder) ListWebhookDeliveries(webhookID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockIWebhook)(nil).ListWebhookDeliveries), webhookID, limit, offset)
+}
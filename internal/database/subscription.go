@@ -2,6 +2,18 @@ package database
 
 import "github.com/jinzhu/gorm"
 
+// Subscription flags
+const (
+	WatchSame uint = 1 << iota
+	WatchSimilar
+	WatchMempool
+	WatchMigrations
+	WatchDeployments
+	WatchCalls
+	WatchErrors
+	SentryEnabled
+)
+
 // Subscription model
 type Subscription struct {
 	gorm.Model
@@ -61,3 +73,13 @@ func (d *db) GetSubscriptionsCount(address, network string) (count int, err erro
 		Count(&count).Error
 	return
 }
+
+func (d *db) GetSubscriptionsByMask(network string, mask uint) ([]Subscription, error) {
+	var subs []Subscription
+
+	err := d.
+		Where("network = ? AND watch_mask & ? != 0", network, mask).
+		Find(&subs).Error
+
+	return subs, err
+}
@@ -0,0 +1,168 @@
+package database
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook - HTTP endpoint which receives events of subscription
+type Webhook struct {
+	ID             uint       `gorm:"primary_key" json:"id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `sql:"index" json:"-"`
+	UserID         uint       `gorm:"index;not null" json:"user_id"`
+	SubscriptionID uint       `gorm:"index;not null" json:"subscription_id"`
+	URL            string     `gorm:"not null" json:"url"`
+	Secret         string     `gorm:"not null" json:"-"`
+	Active         bool       `json:"active"`
+}
+
+// WebhookDelivery - delivery log record of single event sent to webhook
+type WebhookDelivery struct {
+	ID            uint       `gorm:"primary_key" json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	WebhookID     uint       `gorm:"index;not null" json:"webhook_id"`
+	Event         string     `json:"event"`
+	Payload       string     `gorm:"type:text" json:"payload"`
+	Status        string     `gorm:"index" json:"status"`
+	Attempts      uint       `json:"attempts"`
+	ResponseCode  int        `json:"response_code,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	NextAttemptAt *time.Time `gorm:"index" json:"next_attempt_at,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
+
+// CreateWebhook -
+func (d *db) CreateWebhook(w *Webhook) error {
+	return d.Create(w).Error
+}
+
+// GetWebhook -
+func (d *db) GetWebhook(id uint) (w Webhook, err error) {
+	err = d.Scopes(idScope(id)).First(&w).Error
+	return
+}
+
+// ListWebhooks -
+func (d *db) ListWebhooks(userID uint) ([]Webhook, error) {
+	var webhooks []Webhook
+
+	err := d.
+		Scopes(userIDScope(userID), createdAtDesc).
+		Find(&webhooks).Error
+
+	return webhooks, err
+}
+
+// GetActiveWebhooks - returns active webhooks of subscriptions
+func (d *db) GetActiveWebhooks(subscriptionIDs ...uint) ([]Webhook, error) {
+	var webhooks []Webhook
+	if len(subscriptionIDs) == 0 {
+		return webhooks, nil
+	}
+
+	err := d.
+		Where("subscription_id IN (?) AND active = ?", subscriptionIDs, true).
+		Find(&webhooks).Error
+
+	return webhooks, err
+}
+
+// DeleteWebhook - deletes webhook of user with its delivery log. Returns `gorm.ErrRecordNotFound` if user has no such webhook.
+func (d *db) DeleteWebhook(userID, id uint) error {
+	tx := d.Begin()
+	result := tx.Unscoped().Scopes(userIDScope(userID), idScope(id)).Delete(Webhook{})
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return gorm.ErrRecordNotFound
+	}
+	if err := tx.Where("webhook_id = ?", id).Delete(WebhookDelivery{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// DeleteSubscriptionWebhooks - deletes webhooks of subscription with their delivery logs
+func (d *db) DeleteSubscriptionWebhooks(subscriptionID uint) error {
+	tx := d.Begin()
+	webhooks := tx.Unscoped().Model(&Webhook{}).Select("id").Where("subscription_id = ?", subscriptionID).SubQuery()
+	if err := tx.Where("webhook_id IN ?", webhooks).Delete(WebhookDelivery{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Unscoped().Where("subscription_id = ?", subscriptionID).Delete(Webhook{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// CreateWebhookDelivery -
+func (d *db) CreateWebhookDelivery(delivery *WebhookDelivery) error {
+	return d.Create(delivery).Error
+}
+
+// UpdateWebhookDelivery -
+func (d *db) UpdateWebhookDelivery(delivery *WebhookDelivery) error {
+	return d.Save(delivery).Error
+}
+
+// ClaimWebhookDeliveries - claims pending deliveries which next attempt time is before `ts` by moving their next attempt time to `ts + lease`.
+// Rows are selected with SKIP LOCKED, so concurrent dispatchers claim different deliveries. Delivery which is not updated until lease expires is claimed again.
+func (d *db) ClaimWebhookDeliveries(ts time.Time, lease time.Duration, limit uint) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+
+	tx := d.Begin()
+	if err := tx.
+		Set("gorm:query_option", "FOR UPDATE SKIP LOCKED").
+		Where("status = ? AND next_attempt_at <= ?", DeliveryPending, ts).
+		Order("next_attempt_at asc").
+		Scopes(pagination(limit, 0)).
+		Find(&deliveries).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return deliveries, tx.Commit().Error
+	}
+
+	claimedUntil := ts.Add(lease)
+	ids := make([]uint, len(deliveries))
+	for i := range deliveries {
+		ids[i] = deliveries[i].ID
+		next := claimedUntil
+		deliveries[i].NextAttemptAt = &next
+	}
+	if err := tx.Model(&WebhookDelivery{}).Where("id IN (?)", ids).Update("next_attempt_at", claimedUntil).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return deliveries, tx.Commit().Error
+}
+
+// ListWebhookDeliveries -
+func (d *db) ListWebhookDeliveries(webhookID, limit, offset uint) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+
+	err := d.
+		Where("webhook_id = ?", webhookID).
+		Scopes(pagination(limit, offset), createdAtDesc).
+		Find(&deliveries).Error
+
+	return deliveries, err
+}
@@ -47,6 +47,34 @@ func (storage *Storage) CurrentByKey(network, keyHash string, ptr int64) (data b
 	return
 }
 
+// CurrentByKeyAtLevel - returns the last diff of the key at or before `level`
+func (storage *Storage) CurrentByKeyAtLevel(network, keyHash string, ptr, level int64) (data bigmapdiff.BigMapDiff, err error) {
+	if ptr < 0 {
+		err = errors.Errorf("Invalid pointer value: %d", ptr)
+		return
+	}
+	mustQuery := core.Must(
+		core.MatchPhrase("network", network),
+		core.MatchPhrase("key_hash", keyHash),
+		core.Term("ptr", ptr),
+		core.Range("level", core.Item{"lte": level}),
+	)
+	b := core.Bool(mustQuery)
+
+	query := core.NewQuery().Query(b).Sort("level", "desc").One()
+
+	var response core.SearchResponse
+	if err = storage.es.Query([]string{models.DocBigMapDiff}, query, &response); err != nil {
+		return
+	}
+
+	if response.Hits.Total.Value == 0 {
+		return data, core.NewRecordNotFoundError(models.DocBigMapDiff, "")
+	}
+	err = json.Unmarshal(response.Hits.Hits[0].Source, &data)
+	return
+}
+
 // GetForAddress -
 func (storage *Storage) GetForAddress(address string) ([]bigmapdiff.BigMapDiff, error) {
 	query := core.NewQuery().Query(
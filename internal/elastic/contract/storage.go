@@ -57,6 +57,34 @@ func (storage *Storage) GetMany(by map[string]interface{}) ([]contract.Contract,
 	return storage.getContracts(query)
 }
 
+// GetPage - returns up to `size` contracts of `network` with address greater than `lastAddress` sorted by address
+func (storage *Storage) GetPage(network, lastAddress string, size int64) ([]contract.Contract, error) {
+	query := core.NewQuery().Query(
+		core.Bool(
+			core.Filter(
+				core.Match("network", network),
+			),
+		),
+	).Sort("address.keyword", "asc").Size(size)
+
+	if lastAddress != "" {
+		query = query.SearchAfter([]interface{}{lastAddress})
+	}
+
+	var response core.SearchResponse
+	if err := storage.es.Query([]string{models.DocContracts}, query, &response); err != nil {
+		return nil, err
+	}
+
+	contracts := make([]contract.Contract, len(response.Hits.Hits))
+	for i := range response.Hits.Hits {
+		if err := json.Unmarshal(response.Hits.Hits[i].Source, &contracts[i]); err != nil {
+			return nil, err
+		}
+	}
+	return contracts, nil
+}
+
 // GetRandom -
 func (storage *Storage) GetRandom(network string) (contract.Contract, error) {
 	random := core.Item{
@@ -479,7 +507,7 @@ func (storage *Storage) GetDiffTasks() ([]contract.DiffTask, error) {
 // GetTokens -
 func (storage *Storage) GetTokens(network, tokenInterface string, offset, size int64) ([]contract.Contract, int64, error) {
 	tags := []string{"fa12", "fa1", "fa2"}
-	if tokenInterface == "fa12" || tokenInterface == "fa1" || tokenInterface == "fa2" {
+	if tokenInterface != "" {
 		tags = []string{tokenInterface}
 	}
 
@@ -78,6 +78,18 @@ type BulkResponse struct {
 	Errors bool  `json:"errors"`
 }
 
+// ReindexResponse -
+type ReindexResponse struct {
+	Total    int64                `json:"total"`
+	Created  int64                `json:"created"`
+	Failures []stdJSON.RawMessage `json:"failures"`
+}
+
+// CountResponse -
+type CountResponse struct {
+	Count int64 `json:"count"`
+}
+
 // Header -
 type Header struct {
 	Took     int64 `json:"took"`
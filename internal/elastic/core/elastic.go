@@ -296,6 +296,25 @@ func (e *Elastic) DeleteIndices(indices []string) error {
 	return nil
 }
 
+// Refresh - refreshes `indices` and waits until refresh is finished
+func (e *Elastic) Refresh(indices []string) error {
+	resp, err := e.Indices.Refresh(
+		e.Indices.Refresh.WithIndex(indices...),
+		e.Indices.Refresh.WithAllowNoIndices(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return errors.Errorf(resp.Status())
+	}
+
+	return nil
+}
+
 // ReloadSecureSettings -
 func (e *Elastic) ReloadSecureSettings() error {
 	resp, err := e.Nodes.ReloadSecureSettings()
@@ -315,28 +334,42 @@ func (e *Elastic) ReloadSecureSettings() error {
 // DeleteByContract -
 // TODO - delete context
 func (e *Elastic) DeleteByContract(indices []string, network, address string) error {
-	filters := make([]Item, 0)
-	if network != "" {
-		filters = append(filters, Match("network", network))
-	}
-	if address != "" {
-		filters = append(filters, MatchPhrase("contract", address))
+	byFields := make(map[string][]string)
+	for i := range indices {
+		fields := strings.Join(models.ContractFields(indices[i]), ",")
+		byFields[fields] = append(byFields[fields], indices[i])
 	}
-	query := NewQuery().Query(
-		Bool(
-			Filter(filters...),
-		),
-	)
-	end := false
 
-	for !end {
-		response, err := e.deleteByQuery(indices, query)
-		if err != nil {
-			return err
+	for fields, fieldIndices := range byFields {
+		filters := make([]Item, 0)
+		if network != "" {
+			filters = append(filters, Match("network", network))
+		}
+		if address != "" {
+			matches := make([]Item, 0)
+			for _, field := range strings.Split(fields, ",") {
+				matches = append(matches, MatchPhrase(field, address))
+			}
+			filters = append(filters, Bool(
+				Should(matches...),
+				MinimumShouldMatch(1),
+			))
 		}
+		query := NewQuery().Query(
+			Bool(
+				Filter(filters...),
+			),
+		)
 
-		end = response.VersionConflicts == 0
-		logger.Info("Removed %d/%d records from %s", response.Deleted, response.Total, strings.Join(indices, ","))
+		for end := false; !end; {
+			response, err := e.deleteByQuery(fieldIndices, query)
+			if err != nil {
+				return err
+			}
+
+			end = response.VersionConflicts == 0
+			logger.Info("Removed %d/%d records from %s", response.Deleted, response.Total, strings.Join(fieldIndices, ","))
+		}
 	}
 
 	return nil
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/pkg/errors"
+)
+
+// MigrateIndex - copies documents of `index` to new index with current mapping applying painless `script` to every document
+// and switches alias `index` to the copy. The copy is checked before switching: number of documents must be equal
+// and no document may match `invalid` query. Source index is deleted only after successful check.
+func (e *Elastic) MigrateIndex(index, script string, invalid Item) error {
+	source, err := e.concreteIndex(index)
+	if err != nil {
+		return err
+	}
+	dest := fmt.Sprintf("%s_%d", index, time.Now().Unix())
+
+	logger.Info("Copying %s to %s...", source, dest)
+	if err := e.createIndexWithMapping(dest, index); err != nil {
+		return err
+	}
+	if err := e.reindex(source, dest, script); err != nil {
+		return e.dropCopy(dest, err)
+	}
+	if err := e.Refresh([]string{dest}); err != nil {
+		return e.dropCopy(dest, err)
+	}
+	if err := e.checkCopy(source, dest, invalid); err != nil {
+		return e.dropCopy(dest, err)
+	}
+
+	logger.Info("Switching alias %s to %s...", index, dest)
+	return e.switchAlias(index, source, dest)
+}
+
+// concreteIndex - returns name of index which is behind `index`. `index` may be index name or alias.
+func (e *Elastic) concreteIndex(index string) (string, error) {
+	resp, err := e.Indices.Get([]string{index})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var response map[string]interface{}
+	if err := e.GetResponse(resp, &response); err != nil {
+		return "", err
+	}
+	if len(response) != 1 {
+		return "", errors.Errorf("%s points to %d indices", index, len(response))
+	}
+	for name := range response {
+		return name, nil
+	}
+	return "", nil
+}
+
+func (e *Elastic) createIndexWithMapping(index, mapping string) error {
+	jsonFile, err := os.Open(fmt.Sprintf("mappings/%s.json", mapping))
+	if err != nil {
+		return err
+	}
+	defer jsonFile.Close()
+
+	resp, err := e.Indices.Create(index, e.Indices.Create.WithBody(jsonFile))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return e.GetResponse(resp, nil)
+}
+
+func (e *Elastic) reindex(source, dest, script string) error {
+	body := Item{
+		"source": Item{"index": source},
+		"dest":   Item{"index": dest},
+	}
+	if script != "" {
+		body["script"] = Item{
+			"lang":   "painless",
+			"source": script,
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return err
+	}
+
+	resp, err := e.Reindex(
+		&buf,
+		e.Reindex.WithContext(context.Background()),
+		e.Reindex.WithWaitForCompletion(true),
+	)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var response ReindexResponse
+	if err := e.GetResponse(resp, &response); err != nil {
+		return err
+	}
+	if len(response.Failures) > 0 {
+		return errors.Errorf("%d documents of %s are not copied: %s", len(response.Failures), source, response.Failures[0])
+	}
+	return nil
+}
+
+func (e *Elastic) checkCopy(source, dest string, invalid Item) error {
+	sourceCount, err := e.count(source, nil)
+	if err != nil {
+		return err
+	}
+	destCount, err := e.count(dest, nil)
+	if err != nil {
+		return err
+	}
+	if sourceCount != destCount {
+		return errors.Errorf("%s has %d documents, but its copy %s has %d", source, sourceCount, dest, destCount)
+	}
+
+	if invalid == nil {
+		return nil
+	}
+	invalidCount, err := e.count(dest, Item{"query": invalid})
+	if err != nil {
+		return err
+	}
+	if invalidCount > 0 {
+		return errors.Errorf("%d invalid documents in %s", invalidCount, dest)
+	}
+	return nil
+}
+
+func (e *Elastic) count(index string, query Item) (int64, error) {
+	options := []func(*esapi.CountRequest){
+		e.Count.WithContext(context.Background()),
+		e.Count.WithIndex(index),
+	}
+	if query != nil {
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(query); err != nil {
+			return 0, err
+		}
+		options = append(options, e.Count.WithBody(&buf))
+	}
+
+	resp, err := e.Count(options...)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var response CountResponse
+	if err := e.GetResponse(resp, &response); err != nil {
+		return 0, err
+	}
+	return response.Count, nil
+}
+
+// switchAlias - atomically deletes `source` and points `alias` to `dest`
+func (e *Elastic) switchAlias(alias, source, dest string) error {
+	body := Item{
+		"actions": []Item{
+			{"remove_index": Item{"index": source}},
+			{"add": Item{"index": dest, "alias": alias}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return err
+	}
+
+	resp, err := e.Indices.UpdateAliases(&buf, e.Indices.UpdateAliases.WithContext(context.Background()))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return e.GetResponse(resp, nil)
+}
+
+func (e *Elastic) dropCopy(dest string, err error) error {
+	if deleteErr := e.DeleteIndices([]string{dest}); deleteErr != nil {
+		logger.Error(deleteErr)
+	}
+	return err
+}
@@ -47,6 +47,18 @@ type getTokensStatsResponse struct {
 	} `json:"aggregations"`
 }
 
+type getContractLevelsResponse struct {
+	Aggs struct {
+		Levels struct {
+			Buckets []struct {
+				Key struct {
+					Level int64 `json:"level"`
+				} `json:"key"`
+			} `json:"buckets"`
+		} `json:"levels"`
+	} `json:"aggregations"`
+}
+
 type operationAddresses struct {
 	Source      string `json:"source"`
 	Destination string `json:"destination"`
@@ -34,12 +34,17 @@ func (storage *Storage) getContractOPG(address, network string, size uint64, fil
 		return nil, err
 	}
 
+	participants := fmt.Sprintf("source = '%s' OR destination = '%s'", address, address)
+	if manager, ok := filters["manager"]; ok && manager != "" {
+		participants += fmt.Sprintf(" OR (source = '%s' AND kind = '%s')", manager, constants.Reveal)
+	}
+
 	sqlString := fmt.Sprintf(`SELECT hash, counter
 		FROM operation 
-		WHERE (source = '%s' OR destination = '%s') AND network = '%s' %s 
+		WHERE (%s) AND network = '%s' %s 
 		GROUP BY hash, counter, level
 		ORDER BY level DESC
-		LIMIT %d`, address, address, network, filtersString, size)
+		LIMIT %d`, participants, network, filtersString, size)
 
 	var response core.SQLResponse
 	if err := storage.es.ExecuteSQL(sqlString, &response); err != nil {
@@ -59,7 +64,7 @@ func (storage *Storage) getContractOPG(address, network string, size uint64, fil
 
 func prepareOperationFilters(filters map[string]interface{}) (s string, err error) {
 	for k, v := range filters {
-		if v != "" {
+		if v != "" && k != "manager" {
 			s += " AND "
 			switch k {
 			case "from":
@@ -72,6 +77,8 @@ func prepareOperationFilters(filters map[string]interface{}) (s string, err erro
 				s += fmt.Sprintf("indexed_time < %s", v)
 			case "status":
 				s += fmt.Sprintf("status IN (%s)", v)
+			case "kind":
+				s += fmt.Sprintf("kind IN (%s)", v)
 			default:
 				return "", errors.Errorf("Unknown operation filter: %s %v", k, v)
 			}
@@ -167,6 +174,76 @@ func (storage *Storage) Last(network, address string, indexedTime int64) (op ope
 	return
 }
 
+// LastByLevel -
+func (storage *Storage) LastByLevel(network, address string, level int64) (op operation.Operation, err error) {
+	query := core.NewQuery().
+		Query(
+			core.Bool(
+				core.Filter(
+					core.MatchPhrase("destination", address),
+					core.Range("level", core.Item{"lte": level}),
+					core.Term("network", network),
+					core.Term("status", "applied"),
+				),
+				core.MustNot(
+					core.Term("deffated_storage", ""),
+				),
+			),
+		).Sort("indexed_time", "desc").One()
+
+	var response core.SearchResponse
+	if err = storage.es.Query([]string{models.DocOperations}, query, &response); err != nil {
+		return
+	}
+
+	if response.Hits.Total.Value == 0 {
+		return op, core.NewRecordNotFoundError(models.DocOperations, "")
+	}
+	err = json.Unmarshal(response.Hits.Hits[0].Source, &op)
+	op.ID = response.Hits.Hits[0].ID
+	return
+}
+
+// GetBalance -
+func (storage *Storage) GetBalance(network, address string, level int64) (int64, error) {
+	query := core.NewQuery().Query(
+		core.Bool(
+			core.Filter(
+				core.Match("network", network),
+				core.Term("status", "applied"),
+				core.Range("level", core.Item{"lte": level}),
+			),
+			core.Should(
+				core.MatchPhrase("source", address),
+				core.MatchPhrase("destination", address),
+			),
+			core.MinimumShouldMatch(1),
+		),
+	).Add(
+		core.Item{
+			"aggs": core.Item{
+				"balance": core.Item{
+					"scripted_metric": core.Item{
+						"init_script":    "state.operations = []",
+						"map_script":     "if (doc['amount'].size() != 0) {boolean in = doc['destination.keyword'].value == params.address; if (in != (doc['source.keyword'].value == params.address)) {state.operations.add(in ? doc['amount'].value : -1L * doc['amount'].value)}}",
+						"combine_script": "double balance = 0; for (amount in state.operations) { balance += amount } return balance",
+						"reduce_script":  "double balance = 0; for (a in states) { balance += a } return balance",
+						"params": core.Item{
+							"address": address,
+						},
+					},
+				},
+			},
+		},
+	).Zero()
+
+	var response recalcContractStatsResponse
+	if err := storage.es.Query([]string{models.DocOperations}, query, &response); err != nil {
+		return 0, err
+	}
+	return response.Aggs.Balance.Value, nil
+}
+
 // Get -
 func (storage *Storage) Get(filters map[string]interface{}, size int64, sort bool) ([]operation.Operation, error) {
 	operations := make([]operation.Operation, 0)
@@ -381,6 +458,56 @@ func (storage *Storage) GetParticipatingContracts(network string, fromLevel, toL
 	return addresses, nil
 }
 
+// GetContractLevels -
+func (storage *Storage) GetContractLevels(network, address string) ([]int64, error) {
+	levels := make([]int64, 0)
+	var after core.Item
+	for {
+		levelsAgg := core.Composite(
+			core.MaxQuerySize,
+			core.AggItem{
+				Name: "level", Body: core.TermsAgg("level", 0),
+			},
+		)
+		if after != nil {
+			levelsAgg["composite"].(core.Item)["after"] = after
+		}
+
+		query := core.NewQuery().Query(
+			core.Bool(
+				core.Filter(
+					core.Match("network", network),
+					core.Bool(
+						core.Should(
+							core.MatchPhrase("source", address),
+							core.MatchPhrase("destination", address),
+						),
+						core.MinimumShouldMatch(1),
+					),
+				),
+			),
+		).Add(
+			core.Aggs(
+				core.AggItem{Name: "levels", Body: levelsAgg},
+			),
+		).Zero()
+
+		var response getContractLevelsResponse
+		if err := storage.es.Query([]string{models.DocOperations}, query, &response); err != nil {
+			return nil, err
+		}
+
+		buckets := response.Aggs.Levels.Buckets
+		for i := range buckets {
+			levels = append(levels, buckets[i].Key.Level)
+		}
+		if len(buckets) < core.MaxQuerySize {
+			return levels, nil
+		}
+		after = core.Item{"level": buckets[len(buckets)-1].Key.Level}
+	}
+}
+
 // RecalcStats -
 func (storage *Storage) RecalcStats(network, address string) (stats operation.ContractStats, err error) {
 	query := core.NewQuery().Query(
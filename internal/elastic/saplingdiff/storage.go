@@ -0,0 +1,114 @@
+package saplingdiff
+
+import (
+	"encoding/json"
+
+	"github.com/baking-bad/bcdhub/internal/elastic/consts"
+	"github.com/baking-bad/bcdhub/internal/elastic/core"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/saplingdiff"
+)
+
+// Storage -
+type Storage struct {
+	es *core.Elastic
+}
+
+// NewStorage -
+func NewStorage(es *core.Elastic) *Storage {
+	return &Storage{es}
+}
+
+// Get - returns sapling state diffs of `ptr` from the newest one
+func (storage *Storage) Get(network string, ptr, size, offset int64) ([]saplingdiff.SaplingDiff, error) {
+	if size == 0 {
+		size = consts.DefaultSize
+	}
+
+	query := core.NewQuery().Query(
+		core.Bool(
+			core.Filter(
+				core.Match("network", network),
+				core.Term("ptr", ptr),
+			),
+		),
+	).Sort("indexed_time", "desc").Size(size).From(offset)
+
+	var response core.SearchResponse
+	if err := storage.es.Query([]string{models.DocSaplingDiffs}, query, &response); err != nil {
+		return nil, err
+	}
+
+	diffs := make([]saplingdiff.SaplingDiff, len(response.Hits.Hits))
+	for i := range response.Hits.Hits {
+		if err := json.Unmarshal(response.Hits.Hits[i].Source, &diffs[i]); err != nil {
+			return nil, err
+		}
+		diffs[i].ID = response.Hits.Hits[i].ID
+	}
+	return diffs, nil
+}
+
+// GetFirst - returns the diff which created sapling state `ptr`
+func (storage *Storage) GetFirst(network string, ptr int64) (diff saplingdiff.SaplingDiff, err error) {
+	query := core.NewQuery().Query(
+		core.Bool(
+			core.Filter(
+				core.Match("network", network),
+				core.Term("ptr", ptr),
+			),
+		),
+	).Sort("indexed_time", "asc").One()
+
+	var response core.SearchResponse
+	if err = storage.es.Query([]string{models.DocSaplingDiffs}, query, &response); err != nil {
+		return
+	}
+	if response.Hits.Total.Value == 0 {
+		return diff, core.NewRecordNotFoundError(models.DocSaplingDiffs, "")
+	}
+	err = json.Unmarshal(response.Hits.Hits[0].Source, &diff)
+	diff.ID = response.Hits.Hits[0].ID
+	return
+}
+
+type getStatsResponse struct {
+	Agg struct {
+		Commitments core.FloatValue `json:"commitments"`
+		Nullifiers  core.FloatValue `json:"nullifiers"`
+		Updates     core.IntValue   `json:"updates"`
+	} `json:"aggregations"`
+}
+
+// GetStats - returns counters of sapling state `ptr`. If `maxLevel` is positive only diffs up to `maxLevel` are counted.
+func (storage *Storage) GetStats(network string, ptr, maxLevel int64) (stats saplingdiff.Stats, err error) {
+	filters := []core.Item{
+		core.Match("network", network),
+		core.Term("ptr", ptr),
+	}
+	if maxLevel > 0 {
+		filters = append(filters, core.Range("level", core.Item{"lte": maxLevel}))
+	}
+
+	query := core.NewQuery().Query(
+		core.Bool(
+			core.Filter(filters...),
+		),
+	).Add(
+		core.Aggs(
+			core.AggItem{Name: "commitments", Body: core.Sum("commitments_count")},
+			core.AggItem{Name: "nullifiers", Body: core.Sum("nullifiers_count")},
+			core.AggItem{Name: "updates", Body: core.Count("indexed_time")},
+		),
+	).Zero()
+
+	var response getStatsResponse
+	if err = storage.es.Query([]string{models.DocSaplingDiffs}, query, &response); err != nil {
+		return
+	}
+
+	stats.Commitments = int64(response.Agg.Commitments.Value)
+	stats.Nullifiers = int64(response.Agg.Nullifiers.Value)
+	stats.Updates = response.Agg.Updates.Value
+	return
+}
@@ -0,0 +1,67 @@
+package ticketbalance
+
+import (
+	"github.com/baking-bad/bcdhub/internal/elastic/core"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
+)
+
+// Storage -
+type Storage struct {
+	es *core.Elastic
+}
+
+// NewStorage -
+func NewStorage(es *core.Elastic) *Storage {
+	return &Storage{es}
+}
+
+// Update -
+func (storage *Storage) Update(updates []*ticketbalance.TicketBalance) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	buf := make([]ticketbalance.TicketBalance, 0)
+	ids := make([]string, len(updates))
+	for i := range updates {
+		ids[i] = updates[i].GetID()
+	}
+	if err := storage.es.GetByIDs(&buf, ids...); err != nil {
+		if !storage.es.IsRecordNotFound(err) {
+			return err
+		}
+	}
+
+	items := make([]models.Model, len(updates))
+
+	for i := range updates {
+		for j := range buf {
+			if buf[j].GetID() == updates[i].GetID() {
+				updates[i].Sum(&buf[j])
+				break
+			}
+		}
+		items[i] = updates[i]
+	}
+
+	return storage.es.BulkInsert(items)
+}
+
+// GetAccountBalances - returns non-zero ticket balances of contract `address`
+func (storage *Storage) GetAccountBalances(network, address string) ([]ticketbalance.TicketBalance, error) {
+	query := core.NewQuery().Query(
+		core.Bool(
+			core.Filter(
+				core.MatchPhrase("address", address),
+				core.Match("network", network),
+			),
+			core.MustNot(
+				core.Term("balance.keyword", "0"),
+			),
+		),
+	).All()
+
+	balances := make([]ticketbalance.TicketBalance, 0)
+	err := storage.es.GetAllByQuery(query, &balances)
+	return balances, err
+}
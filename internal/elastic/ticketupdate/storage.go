@@ -0,0 +1,32 @@
+package ticketupdate
+
+import (
+	"github.com/baking-bad/bcdhub/internal/elastic/core"
+	"github.com/baking-bad/bcdhub/internal/models/ticketupdate"
+)
+
+// Storage -
+type Storage struct {
+	es *core.Elastic
+}
+
+// NewStorage -
+func NewStorage(es *core.Elastic) *Storage {
+	return &Storage{es}
+}
+
+// GetAll - returns all ticket updates in `network` after `level`
+func (storage *Storage) GetAll(network string, level int64) ([]ticketupdate.TicketUpdate, error) {
+	query := core.NewQuery().Query(
+		core.Bool(
+			core.Filter(
+				core.Match("network", network),
+				core.Range("level", core.Item{"gt": level}),
+			),
+		),
+	)
+
+	updates := make([]ticketupdate.TicketUpdate, 0)
+	err := storage.es.GetAllByQuery(query, &updates)
+	return updates, err
+}
@@ -64,7 +64,7 @@ func (storage *Storage) Update(updates []*tokenbalance.TokenBalance) error {
 }
 
 // GetHolders -
-func (storage *Storage) GetHolders(network, contract string, tokenID int64) ([]tokenbalance.TokenBalance, error) {
+func (storage *Storage) GetHolders(network, contract string, tokenID string) ([]tokenbalance.TokenBalance, error) {
 	query := core.NewQuery().Query(
 		core.Bool(
 			core.Filter(
@@ -19,7 +19,7 @@ func buildGetTokenMetadataContext(ctx ...tokenmetadata.GetContext) core.Base {
 		if c.Level.IsFilled() {
 			filter = append(filter, core.BuildComparator(c.Level))
 		}
-		if c.TokenID != -1 {
+		if c.TokenID != "" {
 			filter = append(filter, core.Term("token_id", c.TokenID))
 		}
 
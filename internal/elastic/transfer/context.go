@@ -80,7 +80,7 @@ func filterAddress(ctx transfer.GetContext) core.Item {
 }
 
 func filterTokenID(ctx transfer.GetContext) core.Item {
-	if ctx.TokenID >= 0 {
+	if ctx.TokenID != "" {
 		return core.Term("token_id", ctx.TokenID)
 	}
 	return nil
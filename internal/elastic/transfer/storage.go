@@ -66,7 +66,7 @@ func (storage *Storage) GetAll(network string, level int64) ([]transfer.Transfer
 }
 
 // GetTokenSupply -
-func (storage *Storage) GetTokenSupply(network, address string, tokenID int64) (result transfer.TokenSupply, err error) {
+func (storage *Storage) GetTokenSupply(network, address string, tokenID string) (result transfer.TokenSupply, err error) {
 	query := core.NewQuery().Query(
 		core.Bool(
 			core.Filter(
@@ -116,7 +116,7 @@ func (storage *Storage) GetTokenSupply(network, address string, tokenID int64) (
 }
 
 // GetToken24HoursVolume - returns token volume for last 24 hours
-func (storage *Storage) GetToken24HoursVolume(network, contract string, initiators, entrypoints []string, tokenID int64) (float64, error) {
+func (storage *Storage) GetToken24HoursVolume(network, contract string, initiators, entrypoints []string, tokenID string) (float64, error) {
 	query := core.NewQuery().Query(
 		core.Bool(
 			core.Filter(
@@ -147,7 +147,7 @@ func (storage *Storage) GetToken24HoursVolume(network, contract string, initiato
 }
 
 // GetTokenVolumeSeries -
-func (storage *Storage) GetTokenVolumeSeries(network, period string, contracts []string, entrypoints []tzip.DAppContract, tokenID uint) ([][]float64, error) {
+func (storage *Storage) GetTokenVolumeSeries(network, period string, contracts []string, entrypoints []tzip.DAppContract, tokenID string) ([][]float64, error) {
 	hist := core.Item{
 		"date_histogram": core.Item{
 			"field":             "timestamp",
@@ -0,0 +1,137 @@
+package tzipversion
+
+import (
+	"encoding/json"
+
+	"github.com/baking-bad/bcdhub/internal/elastic/consts"
+	"github.com/baking-bad/bcdhub/internal/elastic/core"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/tzipversion"
+)
+
+// Storage -
+type Storage struct {
+	es *core.Elastic
+}
+
+// NewStorage -
+func NewStorage(es *core.Elastic) *Storage {
+	return &Storage{es}
+}
+
+// Get - returns metadata versions of contract from the newest one
+func (storage *Storage) Get(network, address string, size, offset int64) ([]tzipversion.Version, error) {
+	if size == 0 {
+		size = consts.DefaultSize
+	}
+
+	query := core.NewQuery().Query(
+		core.Bool(
+			core.Filter(
+				core.Match("network", network),
+				core.MatchPhrase("address", address),
+			),
+		),
+	).Sort("level", "desc").Size(size).From(offset)
+
+	return storage.query(query)
+}
+
+// GetLast - returns the newest metadata version of contract
+func (storage *Storage) GetLast(network, address string) (version tzipversion.Version, err error) {
+	versions, err := storage.Get(network, address, 1, 0)
+	if err != nil {
+		return
+	}
+	if len(versions) == 0 {
+		return version, core.NewRecordNotFoundError(models.DocTZIPVersions, "")
+	}
+	return versions[0], nil
+}
+
+// GetPending - returns pending versions which next fetch attempt is due before `before` (unix timestamp)
+func (storage *Storage) GetPending(before, size int64) ([]tzipversion.Version, error) {
+	if size == 0 {
+		size = consts.DefaultSize
+	}
+
+	query := core.NewQuery().Query(
+		core.Bool(
+			core.Filter(
+				core.Term("status", tzipversion.StatusPending),
+				core.Range("next_attempt_at", core.Item{"lte": before}),
+			),
+		),
+	).Sort("next_attempt_at", "asc").Size(size)
+
+	return storage.query(query)
+}
+
+func (storage *Storage) query(query core.Base) ([]tzipversion.Version, error) {
+	var response core.SearchResponse
+	if err := storage.es.Query([]string{models.DocTZIPVersions}, query, &response); err != nil {
+		return nil, err
+	}
+
+	versions := make([]tzipversion.Version, len(response.Hits.Hits))
+	for i := range response.Hits.Hits {
+		if err := json.Unmarshal(response.Hits.Hits[i].Source, &versions[i]); err != nil {
+			return nil, err
+		}
+	}
+	return versions, nil
+}
+
+// GetAfter - returns versions of `network` created after `level`
+func (storage *Storage) GetAfter(network string, level int64) ([]tzipversion.Version, error) {
+	query := core.NewQuery().Query(
+		core.Bool(
+			core.Filter(
+				core.Match("network", network),
+				core.Range("level", core.Item{"gt": level}),
+			),
+		),
+	)
+
+	versions := make([]tzipversion.Version, 0)
+	err := storage.es.GetAllByQuery(query, &versions)
+	return versions, err
+}
+
+// GetLastBefore - returns the newest fetched version of contract created not later than `level`
+func (storage *Storage) GetLastBefore(network, address string, level int64) (version tzipversion.Version, err error) {
+	query := core.NewQuery().Query(
+		core.Bool(
+			core.Filter(
+				core.Match("network", network),
+				core.MatchPhrase("address", address),
+				core.Term("status", tzipversion.StatusFetched),
+				core.Range("level", core.Item{"lte": level}),
+			),
+		),
+	).Sort("level", "desc").One()
+
+	versions, err := storage.query(query)
+	if err != nil {
+		return
+	}
+	if len(versions) == 0 {
+		return version, core.NewRecordNotFoundError(models.DocTZIPVersions, "")
+	}
+	return versions[0], nil
+}
+
+// GetReferenced - returns versions which URI refers to big map key
+func (storage *Storage) GetReferenced() ([]tzipversion.Version, error) {
+	query := core.NewQuery().Query(
+		core.Bool(
+			core.Filter(
+				core.Exists("ref_key_hash"),
+			),
+		),
+	)
+
+	versions := make([]tzipversion.Version, 0)
+	err := storage.es.GetAllByQuery(query, &versions)
+	return versions, err
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/kinds"
+	"github.com/baking-bad/bcdhub/internal/contractparser/meta"
+	"github.com/baking-bad/bcdhub/internal/helpers"
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/contract"
+	"github.com/baking-bad/bcdhub/internal/models/contractinterface"
+	"github.com/baking-bad/bcdhub/internal/models/schema"
+)
+
+const retagPageSize = 1000
+
+// ContractInterfaces - stores user-defined interfaces of share directory to the index and retags known contracts when interface is added or changed
+type ContractInterfaces struct {
+	storage   models.GeneralRepository
+	contracts contract.Repository
+	schema    schema.Repository
+	directory string
+	networks  []string
+}
+
+// NewContractInterfaces -
+func NewContractInterfaces(storage models.GeneralRepository, contracts contract.Repository, schemaRepo schema.Repository, directory string, networks []string) *ContractInterfaces {
+	return &ContractInterfaces{
+		storage:   storage,
+		contracts: contracts,
+		schema:    schemaRepo,
+		directory: directory,
+		networks:  networks,
+	}
+}
+
+// Run - syncs interfaces every `period` until `stop` is closed
+func (ci *ContractInterfaces) Run(period time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := ci.Sync(); err != nil {
+				logger.Errorf("[contract interfaces] sync error: %s", err)
+			}
+		}
+	}
+}
+
+// Sync - stores new and changed interfaces of directory and retags contracts by every interface which is not retagged yet
+func (ci *ContractInterfaces) Sync() error {
+	stored := make([]contractinterface.Interface, 0)
+	if err := ci.storage.GetAll(&stored); err != nil {
+		return err
+	}
+
+	changed, err := ci.load(stored)
+	if err != nil {
+		return err
+	}
+	pending := make([]*contractinterface.Interface, 0)
+	names := make(map[string]struct{})
+	if len(changed) > 0 {
+		updates := make([]models.Model, len(changed))
+		for i := range changed {
+			updates[i] = changed[i]
+			names[changed[i].Name] = struct{}{}
+		}
+		if err := ci.storage.BulkInsert(updates); err != nil {
+			return err
+		}
+		pending = append(pending, changed...)
+	}
+	for i := range stored {
+		if _, ok := names[stored[i].Name]; ok || stored[i].Retagged {
+			continue
+		}
+		pending = append(pending, &stored[i])
+	}
+
+	for i := range pending {
+		if err := ci.Retag(pending[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Retag - adds tag of interface `i` to contracts which implement it and removes it from others. Interface is marked as retagged after that.
+// Contracts are paged by address and schema is checked only for contracts which have all named entrypoints of interface or already have its tag.
+func (ci *ContractInterfaces) Retag(i *contractinterface.Interface) error {
+	kind := i.Kind()
+	if err := kind.Validate(); err != nil {
+		return err
+	}
+	interfaces := map[string]kinds.ContractKind{
+		i.Name: kind.Kind(),
+	}
+
+	for _, network := range ci.networks {
+		var lastAddress string
+		var count int
+		for {
+			contracts, err := ci.contracts.GetPage(network, lastAddress, retagPageSize)
+			if err != nil {
+				return err
+			}
+			if len(contracts) == 0 {
+				break
+			}
+			lastAddress = contracts[len(contracts)-1].Address
+
+			updated := make([]contract.Contract, 0)
+			for j := range contracts {
+				if !hasTag(contracts[j], i.Name) && !mayImplement(contracts[j], interfaces[i.Name]) {
+					continue
+				}
+				ok, err := ci.implements(contracts[j].Address, interfaces)
+				if err != nil {
+					return err
+				}
+				if setTag(&contracts[j], i.Name, ok) {
+					updated = append(updated, contracts[j])
+				}
+			}
+			if err := ci.contracts.UpdateField(updated, "Tags"); err != nil {
+				return err
+			}
+			count += len(updated)
+		}
+		logger.Info("[contract interfaces] %d contracts of %s are retagged by %s", count, network, i.Name)
+	}
+
+	i.Retagged = true
+	return ci.storage.BulkUpdate([]models.Model{i})
+}
+
+// load - returns interfaces of directory which are absent in `stored` or differ from stored ones
+func (ci *ContractInterfaces) load(stored []contractinterface.Interface) ([]*contractinterface.Interface, error) {
+	custom, err := kinds.LoadDirectory(ci.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]kinds.Interface, len(stored))
+	for i := range stored {
+		known[stored[i].Name] = stored[i].Kind()
+	}
+
+	changed := make([]*contractinterface.Interface, 0)
+	for i := range custom {
+		if old, ok := known[custom[i].Name]; ok {
+			equal, err := sameInterfaces(old, custom[i])
+			if err != nil {
+				return nil, err
+			}
+			if equal {
+				continue
+			}
+		}
+		changed = append(changed, contractinterface.New(custom[i]))
+	}
+	return changed, nil
+}
+
+func (ci *ContractInterfaces) implements(address string, interfaces map[string]kinds.ContractKind) (bool, error) {
+	contractSchema, err := meta.GetContractSchema(ci.schema, address)
+	if err != nil {
+		if ci.storage.IsRecordNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, metadata := range contractSchema.Parameter {
+		tags, err := kinds.Find(metadata, interfaces)
+		if err != nil {
+			return false, err
+		}
+		if len(tags) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// mayImplement - returns false if contract `c` lacks any named entrypoint of `kind`. Root interfaces can't be filtered by entrypoint names.
+func mayImplement(c contract.Contract, kind kinds.ContractKind) bool {
+	if kind.IsRoot {
+		return true
+	}
+	for i := range kind.Entrypoints {
+		if kind.Entrypoints[i].Name == "" {
+			continue
+		}
+		if !helpers.StringInArray(kind.Entrypoints[i].Name, c.Entrypoints) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTag(c contract.Contract, tag string) bool {
+	return helpers.StringInArray(tag, c.Tags)
+}
+
+// setTag - adds or removes `tag` of contract `c`. Returns true if tags were changed.
+func setTag(c *contract.Contract, tag string, has bool) bool {
+	for i := range c.Tags {
+		if c.Tags[i] != tag {
+			continue
+		}
+		if has {
+			return false
+		}
+		c.Tags = append(c.Tags[:i], c.Tags[i+1:]...)
+		return true
+	}
+	if !has {
+		return false
+	}
+	c.Tags = append(c.Tags, tag)
+	return true
+}
+
+func sameInterfaces(a, b kinds.Interface) (bool, error) {
+	dataA, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	dataB, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(dataA, dataB), nil
+}
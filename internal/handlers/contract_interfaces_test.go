@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/kinds"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/contract"
+	"github.com/baking-bad/bcdhub/internal/models/contractinterface"
+	mock_general "github.com/baking-bad/bcdhub/internal/models/mock"
+	mock_contract "github.com/baking-bad/bcdhub/internal/models/mock/contract"
+	mock_schema "github.com/baking-bad/bcdhub/internal/models/mock/schema"
+	"github.com/baking-bad/bcdhub/internal/models/schema"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_mayImplement(t *testing.T) {
+	tests := []struct {
+		name     string
+		contract contract.Contract
+		kind     kinds.ContractKind
+		want     bool
+	}{
+		{
+			name:     "all entrypoints",
+			contract: contract.Contract{Entrypoints: []string{"mint", "burn", "transfer"}},
+			kind:     kinds.ContractKind{Entrypoints: []kinds.Entrypoint{{Name: "mint"}, {Name: "burn"}}},
+			want:     true,
+		}, {
+			name:     "missing entrypoint",
+			contract: contract.Contract{Entrypoints: []string{"mint", "transfer"}},
+			kind:     kinds.ContractKind{Entrypoints: []kinds.Entrypoint{{Name: "mint"}, {Name: "burn"}}},
+			want:     false,
+		}, {
+			name:     "unnamed entrypoint",
+			contract: contract.Contract{Entrypoints: []string{"mint"}},
+			kind:     kinds.ContractKind{Entrypoints: []kinds.Entrypoint{{Name: "mint"}, {Prim: "nat"}}},
+			want:     true,
+		}, {
+			name:     "root interface",
+			contract: contract.Contract{Entrypoints: []string{"default"}},
+			kind:     kinds.ContractKind{Entrypoints: []kinds.Entrypoint{{Name: "mint"}}, IsRoot: true},
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, mayImplement(tt.contract, tt.kind))
+		})
+	}
+}
+
+func TestContractInterfaces_Retag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mock_general.NewMockGeneralRepository(ctrl)
+	contracts := mock_contract.NewMockRepository(ctrl)
+	schemaRepo := mock_schema.NewMockRepository(ctrl)
+
+	page := []contract.Contract{
+		{Network: "mainnet", Address: "KT1other", Entrypoints: []string{"transfer"}},
+		{Network: "mainnet", Address: "KT1tagged", Entrypoints: []string{"transfer"}, Tags: []string{"mintable"}},
+	}
+	notFound := errors.New("not found")
+
+	contracts.EXPECT().GetPage("mainnet", "", int64(retagPageSize)).Return(page, nil)
+	contracts.EXPECT().GetPage("mainnet", "KT1tagged", int64(retagPageSize)).Return(nil, nil)
+	schemaRepo.EXPECT().Get("KT1tagged").Return(schema.Schema{}, notFound)
+	storage.EXPECT().IsRecordNotFound(notFound).Return(true)
+	contracts.EXPECT().UpdateField(gomock.Any(), "Tags").DoAndReturn(func(updated []contract.Contract, fields ...string) error {
+		if assert.Len(t, updated, 1) {
+			assert.Equal(t, "KT1tagged", updated[0].Address)
+			assert.Empty(t, updated[0].Tags)
+		}
+		return nil
+	})
+	storage.EXPECT().BulkUpdate(gomock.Any()).DoAndReturn(func(items []models.Model) error {
+		if assert.Len(t, items, 1) {
+			assert.True(t, items[0].(*contractinterface.Interface).Retagged)
+		}
+		return nil
+	})
+
+	ci := NewContractInterfaces(storage, contracts, schemaRepo, "", []string{"mainnet"})
+	err := ci.Retag(&contractinterface.Interface{
+		Name:        "mintable",
+		Entrypoints: []kinds.Entrypoint{{Name: "mint", Prim: "nat"}},
+	})
+	assert.NoError(t, err)
+}
@@ -27,7 +27,7 @@ func TestLedger_getTokenBalance(t *testing.T) {
 				Contract: "KT1VYsVfmobT7rsMVivvZ4J8i3bPiqz12NaH",
 				Network:  "mainnet",
 				Value:    big.NewInt(1000000),
-				TokenID:  0,
+				TokenID:  "0",
 			},
 		}, {
 			name:       "test 2",
@@ -38,7 +38,7 @@ func TestLedger_getTokenBalance(t *testing.T) {
 				Contract: "KT1VYsVfmobT7rsMVivvZ4J8i3bPiqz12NaH",
 				Network:  "mainnet",
 				Value:    big.NewInt(0),
-				TokenID:  0,
+				TokenID:  "0",
 			},
 		},
 	}
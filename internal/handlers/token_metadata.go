@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"github.com/baking-bad/bcdhub/internal/ipfs"
 	"github.com/baking-bad/bcdhub/internal/logger"
 	"github.com/baking-bad/bcdhub/internal/models"
 	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
@@ -19,10 +20,10 @@ type TokenMetadata struct {
 }
 
 // NewTokenMetadata -
-func NewTokenMetadata(bigMapRepo bigmapdiff.Repository, blockRepo block.Repository, protocolRepo protocol.Repository, schemaRepo schema.Repository, storage models.GeneralRepository, rpcs map[string]noderpc.INode, sharePath string, ipfs []string) *TokenMetadata {
+func NewTokenMetadata(bigMapRepo bigmapdiff.Repository, blockRepo block.Repository, protocolRepo protocol.Repository, schemaRepo schema.Repository, storage models.GeneralRepository, rpcs map[string]noderpc.INode, sharePath string, ipfs ipfs.Resolver) *TokenMetadata {
 	parsers := make(map[string]tokens.Parser)
 	for network, rpc := range rpcs {
-		parsers[network] = tokens.NewParser(bigMapRepo, blockRepo, protocolRepo, schemaRepo, storage, rpc, sharePath, network, ipfs...)
+		parsers[network] = tokens.NewParser(bigMapRepo, blockRepo, protocolRepo, schemaRepo, storage, rpc, sharePath, network, ipfs)
 	}
 	return &TokenMetadata{
 		storage, parsers,
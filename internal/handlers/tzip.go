@@ -1,34 +1,86 @@
 package handlers
 
 import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/ipfs"
 	"github.com/baking-bad/bcdhub/internal/logger"
 	"github.com/baking-bad/bcdhub/internal/models"
 	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
 	"github.com/baking-bad/bcdhub/internal/models/block"
 	"github.com/baking-bad/bcdhub/internal/models/schema"
 	tzipModel "github.com/baking-bad/bcdhub/internal/models/tzip"
+	"github.com/baking-bad/bcdhub/internal/models/tzipversion"
 	"github.com/baking-bad/bcdhub/internal/noderpc"
 	"github.com/baking-bad/bcdhub/internal/parsers/tzip"
+	tzipStorage "github.com/baking-bad/bcdhub/internal/parsers/tzip/storage"
 	"github.com/pkg/errors"
 )
 
-// TZIP -
+const (
+	defaultTZIPMaxAttempts = 10
+	defaultTZIPBackoff     = time.Minute
+	maxTZIPBackoff         = 24 * time.Hour
+	tzipRetryBatchSize     = 100
+)
+
+// TZIP - stores every version of contract metadata. Current metadata of contract is the latest fetched version.
+// Latest versions which refer to big map key by `tezos-storage:` URI are tracked and fetched again when the key is updated.
 type TZIP struct {
-	storage models.GeneralRepository
-	parsers map[string]tzip.Parser
+	storage  models.GeneralRepository
+	versions tzipversion.Repository
+	parsers  map[string]tzip.Parser
+
+	maxAttempts int64
+	backoff     time.Duration
+
+	refsMux    sync.Mutex
+	refs       map[string]map[string]tzipversion.Version
+	referenced map[string]string
+}
+
+// TZIPOption -
+type TZIPOption func(*TZIP)
+
+// WithTZIPMaxAttempts - sets count of fetch attempts after which version is failed
+func WithTZIPMaxAttempts(maxAttempts int64) TZIPOption {
+	return func(t *TZIP) {
+		if maxAttempts > 0 {
+			t.maxAttempts = maxAttempts
+		}
+	}
+}
+
+// WithTZIPBackoff - sets delay before the second fetch attempt. Every next delay is twice longer.
+func WithTZIPBackoff(backoff time.Duration) TZIPOption {
+	return func(t *TZIP) {
+		if backoff > 0 {
+			t.backoff = backoff
+		}
+	}
 }
 
 // NewTZIP -
-func NewTZIP(bigMapRepo bigmapdiff.Repository, blockRepo block.Repository, schemaRepo schema.Repository, storage models.GeneralRepository, rpcs map[string]noderpc.INode, ipfs []string) *TZIP {
+func NewTZIP(bigMapRepo bigmapdiff.Repository, blockRepo block.Repository, schemaRepo schema.Repository, versions tzipversion.Repository, storage models.GeneralRepository, rpcs map[string]noderpc.INode, ipfs ipfs.Resolver, opts ...TZIPOption) *TZIP {
 	parsers := make(map[string]tzip.Parser)
 	for network, rpc := range rpcs {
 		parsers[network] = tzip.NewParser(bigMapRepo, blockRepo, schemaRepo, storage, rpc, tzip.ParserConfig{
-			IPFSGateways: ipfs,
+			IPFS: ipfs,
 		})
 	}
-	return &TZIP{
-		storage, parsers,
+	t := &TZIP{
+		storage:     storage,
+		versions:    versions,
+		parsers:     parsers,
+		maxAttempts: defaultTZIPMaxAttempts,
+		backoff:     defaultTZIPBackoff,
+	}
+	for i := range opts {
+		opts[i](t)
 	}
+	return t
 }
 
 // Do -
@@ -37,37 +89,267 @@ func (t *TZIP) Do(model models.Model) (bool, error) {
 	if !ok {
 		return false, nil
 	}
-	if bmd.KeyHash != tzip.EmptyStringKey {
-		return false, nil
+	if bmd.KeyHash == tzip.EmptyStringKey {
+		return true, t.handle(bmd)
+	}
+	return t.handleReference(bmd)
+}
+
+// Retry - fetches metadata of pending versions which next attempt is due
+func (t *TZIP) Retry() error {
+	versions, err := t.versions.GetPending(time.Now().Unix(), tzipRetryBatchSize)
+	if err != nil {
+		return err
+	}
+	for i := range versions {
+		if err := t.fetch(&versions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunRetries - retries pending versions every `period` until `stop` is closed
+func (t *TZIP) RunRetries(period time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := t.Retry(); err != nil {
+				logger.Errorf("[metadata] retry error: %s", err)
+			}
+		}
 	}
-	return true, t.handle(bmd)
 }
 
 func (t *TZIP) handle(bmd *bigmapdiff.BigMapDiff) error {
-	tzipParser, ok := t.parsers[bmd.Network]
-	if !ok {
-		return errors.Errorf("Unknown network for tzip parser: %s", bmd.Network)
+	uri := tzipStorage.DecodeValue(bmd.Value)
+	if uri == "" {
+		return nil
 	}
 
-	model, err := tzipParser.Parse(tzip.ParseContext{
-		BigMapDiff: *bmd,
-	})
+	version := tzipversion.Version{
+		Network:   bmd.Network,
+		Address:   bmd.Address,
+		Level:     bmd.Level,
+		Timestamp: bmd.Timestamp,
+		Ptr:       bmd.Ptr,
+		URI:       uri,
+	}
+	refAddress, refKeyHash, err := tzipStorage.Reference(uri, bmd.Address)
 	if err != nil {
-		logger.With(bmd).Error(err)
-		return nil
+		logger.With(&version).Warning(err.Error())
+	}
+	version.RefAddress = refAddress
+	version.RefKeyHash = refKeyHash
+
+	if err := t.fetch(&version); err != nil {
+		return err
+	}
+	if err := t.setReference(version); err != nil {
+		return err
+	}
+
+	logger.With(bmd).Info("Big map diff with TZIP is processed")
+	return nil
+}
+
+// handleReference - creates new versions of metadata which refers to the updated key
+func (t *TZIP) handleReference(bmd *bigmapdiff.BigMapDiff) (bool, error) {
+	refs, err := t.getReferences(bmd.Network, bmd.Address, bmd.KeyHash)
+	if err != nil || len(refs) == 0 {
+		return false, err
+	}
+
+	for i := range refs {
+		version := tzipversion.Version{
+			Network:    refs[i].Network,
+			Address:    refs[i].Address,
+			Level:      bmd.Level,
+			Timestamp:  bmd.Timestamp,
+			Ptr:        refs[i].Ptr,
+			URI:        refs[i].URI,
+			RefAddress: refs[i].RefAddress,
+			RefKeyHash: refs[i].RefKeyHash,
+		}
+		if err := t.fetch(&version); err != nil {
+			return true, err
+		}
+		if err := t.setReference(version); err != nil {
+			return true, err
+		}
+		logger.With(&version).Info("Referenced metadata key is updated")
+	}
+	return true, nil
+}
+
+func (t *TZIP) getReferences(network, address, keyHash string) ([]tzipversion.Version, error) {
+	t.refsMux.Lock()
+	defer t.refsMux.Unlock()
+
+	if err := t.loadReferences(); err != nil {
+		return nil, err
 	}
-	if model == nil {
+	contracts := t.refs[referenceKey(network, address, keyHash)]
+	refs := make([]tzipversion.Version, 0, len(contracts))
+	for _, version := range contracts {
+		refs = append(refs, version)
+	}
+	return refs, nil
+}
+
+// setReference - replaces reference of contract by reference of its new latest `version`
+func (t *TZIP) setReference(version tzipversion.Version) error {
+	t.refsMux.Lock()
+	defer t.refsMux.Unlock()
+
+	if err := t.loadReferences(); err != nil {
+		return err
+	}
+	t.addReference(version)
+	return nil
+}
+
+// loadReferences - loads latest versions which refer to big map key. It's called once under `refsMux`.
+func (t *TZIP) loadReferences() error {
+	if t.refs != nil {
 		return nil
 	}
 
+	versions, err := t.versions.GetReferenced()
+	if err != nil {
+		if !t.storage.IsRecordNotFound(err) {
+			return err
+		}
+	}
+
+	latest := make(map[string]tzipversion.Version)
+	for i := range versions {
+		key := contractKey(versions[i].Network, versions[i].Address)
+		if last, ok := latest[key]; !ok || last.Level < versions[i].Level {
+			latest[key] = versions[i]
+		}
+	}
+
+	t.refs = make(map[string]map[string]tzipversion.Version)
+	t.referenced = make(map[string]string)
+	for _, version := range latest {
+		last, err := t.versions.GetLast(version.Network, version.Address)
+		if err != nil {
+			return err
+		}
+		if last.Level == version.Level {
+			t.addReference(version)
+		}
+	}
+	return nil
+}
+
+func (t *TZIP) addReference(version tzipversion.Version) {
+	contract := contractKey(version.Network, version.Address)
+	if key, ok := t.referenced[contract]; ok {
+		delete(t.refs[key], contract)
+		if len(t.refs[key]) == 0 {
+			delete(t.refs, key)
+		}
+		delete(t.referenced, contract)
+	}
+	if version.RefKeyHash == "" {
+		return
+	}
+
+	key := referenceKey(version.Network, version.RefAddress, version.RefKeyHash)
+	if _, ok := t.refs[key]; !ok {
+		t.refs[key] = make(map[string]tzipversion.Version)
+	}
+	t.refs[key][contract] = version
+	t.referenced[contract] = key
+}
+
+func contractKey(network, address string) string {
+	return fmt.Sprintf("%s:%s", network, address)
+}
+
+func referenceKey(network, address, keyHash string) string {
+	return fmt.Sprintf("%s:%s:%s", network, address, keyHash)
+}
+
+// fetch - makes fetch attempt of `version` and saves result. Current metadata is updated if `version` is the latest one.
+func (t *TZIP) fetch(version *tzipversion.Version) error {
+	tzipParser, ok := t.parsers[version.Network]
+	if !ok {
+		return errors.Errorf("Unknown network for tzip parser: %s", version.Network)
+	}
+
+	version.Attempts++
+	data, err := tzipParser.Fetch(version.Network, version.Address, version.URI, version.Ptr)
+	switch {
+	case err == nil:
+		data.Level = version.Level
+		data.Timestamp = version.Timestamp
+		version.Status = tzipversion.StatusFetched
+		version.Metadata = data
+		version.Error = ""
+		version.NextAttemptAt = 0
+	case errors.Is(err, tzip.ErrFetch) && version.Attempts < t.maxAttempts:
+		version.Status = tzipversion.StatusPending
+		version.Error = err.Error()
+		version.NextAttemptAt = time.Now().Add(t.delay(version.Attempts)).Unix()
+		logger.With(version).Warning(err.Error())
+	default:
+		version.Status = tzipversion.StatusFailed
+		version.Error = err.Error()
+		version.NextAttemptAt = 0
+		logger.With(version).Error(err)
+	}
+
+	items := []models.Model{version}
+	if version.Status == tzipversion.StatusFetched {
+		current, err := t.isCurrent(version)
+		if err != nil {
+			return err
+		}
+		if current {
+			items = append(items, version.Metadata)
+		}
+	}
+	return t.storage.BulkInsert(items)
+}
+
+// isCurrent - returns true if `version` is not older than the latest known version and metadata is not set off-chain
+func (t *TZIP) isCurrent(version *tzipversion.Version) (bool, error) {
+	last, err := t.versions.GetLast(version.Network, version.Address)
+	switch {
+	case err == nil:
+		if last.Level > version.Level {
+			return false, nil
+		}
+	case !t.storage.IsRecordNotFound(err):
+		return false, err
+	}
+
 	m := tzipModel.TZIP{
-		Address: model.Address,
-		Network: model.Network,
+		Address: version.Address,
+		Network: version.Network,
 	}
 	if err := t.storage.GetByID(&m); err == nil && m.OffChain {
-		return nil
+		return false, nil
 	}
+	return true, nil
+}
 
-	logger.With(bmd).Info("Big map diff with TZIP is processed")
-	return t.storage.BulkInsert([]models.Model{model})
+// delay - returns delay after `attempts` failed attempts
+func (t *TZIP) delay(attempts int64) time.Duration {
+	delay := t.backoff
+	for i := int64(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= maxTZIPBackoff {
+			return maxTZIPBackoff
+		}
+	}
+	return delay
 }
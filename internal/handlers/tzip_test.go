@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
+	mock_general "github.com/baking-bad/bcdhub/internal/models/mock"
+	mock_tzipversion "github.com/baking-bad/bcdhub/internal/models/mock/tzipversion"
+	tzipModel "github.com/baking-bad/bcdhub/internal/models/tzip"
+	"github.com/baking-bad/bcdhub/internal/models/tzipversion"
+	"github.com/baking-bad/bcdhub/internal/noderpc"
+	"github.com/golang/mock/gomock"
+)
+
+func TestTZIP_fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"name":"Token","version":"1.0.0"}`)) //nolint
+	}))
+	defer server.Close()
+
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	unavailable.Close()
+
+	errNotFound := errors.New("not found")
+
+	tests := []struct {
+		name         string
+		version      tzipversion.Version
+		last         *tzipversion.Version
+		wantStatus   string
+		wantAttempts int64
+		wantCurrent  bool
+	}{
+		{
+			name:         "fetched first version",
+			version:      tzipversion.Version{Network: "mainnet", Address: "KT1", Level: 100, URI: server.URL},
+			wantStatus:   tzipversion.StatusFetched,
+			wantAttempts: 1,
+			wantCurrent:  true,
+		}, {
+			name:         "fetched old version",
+			version:      tzipversion.Version{Network: "mainnet", Address: "KT1", Level: 100, URI: server.URL, Status: tzipversion.StatusPending, Attempts: 2},
+			last:         &tzipversion.Version{Network: "mainnet", Address: "KT1", Level: 200, Status: tzipversion.StatusFetched},
+			wantStatus:   tzipversion.StatusFetched,
+			wantAttempts: 3,
+		}, {
+			name:         "unavailable server",
+			version:      tzipversion.Version{Network: "mainnet", Address: "KT1", Level: 100, URI: unavailable.URL},
+			wantStatus:   tzipversion.StatusPending,
+			wantAttempts: 1,
+		}, {
+			name:         "attempts are exhausted",
+			version:      tzipversion.Version{Network: "mainnet", Address: "KT1", Level: 100, URI: unavailable.URL, Status: tzipversion.StatusPending, Attempts: 2},
+			wantStatus:   tzipversion.StatusFailed,
+			wantAttempts: 3,
+		}, {
+			name:         "server error",
+			version:      tzipversion.Version{Network: "mainnet", Address: "KT1", Level: 100, URI: server.URL + "/unavailable"},
+			wantStatus:   tzipversion.StatusPending,
+			wantAttempts: 1,
+		}, {
+			name:         "missing document",
+			version:      tzipversion.Version{Network: "mainnet", Address: "KT1", Level: 100, URI: server.URL + "/missing"},
+			wantStatus:   tzipversion.StatusFailed,
+			wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mock_general.NewMockGeneralRepository(ctrl)
+			versions := mock_tzipversion.NewMockRepository(ctrl)
+
+			if tt.wantStatus == tzipversion.StatusFetched {
+				if tt.last != nil {
+					versions.EXPECT().GetLast("mainnet", "KT1").Return(*tt.last, nil)
+				} else {
+					versions.EXPECT().GetLast("mainnet", "KT1").Return(tzipversion.Version{}, errNotFound)
+					storage.EXPECT().IsRecordNotFound(errNotFound).Return(true)
+					storage.EXPECT().GetByID(gomock.Any()).Return(errNotFound)
+				}
+			}
+
+			storage.EXPECT().BulkInsert(gomock.Any()).DoAndReturn(func(items []models.Model) error {
+				version, ok := items[0].(*tzipversion.Version)
+				if !ok {
+					t.Errorf("first item is not version: %T", items[0])
+					return nil
+				}
+				if version.Status != tt.wantStatus || version.Attempts != tt.wantAttempts {
+					t.Errorf("version status = %s attempts = %d, want %s %d (error: %s)", version.Status, version.Attempts, tt.wantStatus, tt.wantAttempts, version.Error)
+				}
+				if version.Status == tzipversion.StatusPending && version.NextAttemptAt <= time.Now().Unix() {
+					t.Errorf("next attempt is not scheduled: %d", version.NextAttemptAt)
+				}
+
+				if tt.wantCurrent != (len(items) == 2) {
+					t.Errorf("current metadata is updated = %v, want %v", len(items) == 2, tt.wantCurrent)
+					return nil
+				}
+				if tt.wantCurrent {
+					current := items[1].(*tzipModel.TZIP)
+					if current.Name != "Token" || current.Level != tt.version.Level || current.Address != "KT1" {
+						t.Errorf("current metadata = %+v", current)
+					}
+				}
+				return nil
+			})
+
+			handler := NewTZIP(nil, nil, nil, versions, storage, map[string]noderpc.INode{"mainnet": nil}, nil, WithTZIPMaxAttempts(3))
+			version := tt.version
+			if err := handler.fetch(&version); err != nil {
+				t.Errorf("fetch() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestTZIP_DoReference(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"Token","version":"2.0.0"}`)) //nolint
+	}))
+	defer server.Close()
+
+	const keyHash = "expruaHzyjwFcmFKHqR49qdxwJupAna6ygSKo2mFJQtqZQjid5t8GK"
+	errNotFound := errors.New("not found")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mock_general.NewMockGeneralRepository(ctrl)
+	versions := mock_tzipversion.NewMockRepository(ctrl)
+
+	referenced := tzipversion.Version{Network: "mainnet", Address: "KT1", Level: 100, Ptr: 1, URI: server.URL, RefAddress: "KT1", RefKeyHash: keyHash, Status: tzipversion.StatusFetched}
+	versions.EXPECT().GetReferenced().Return([]tzipversion.Version{referenced}, nil)
+	versions.EXPECT().GetLast("mainnet", "KT1").Return(referenced, nil).Times(2)
+	storage.EXPECT().GetByID(gomock.Any()).Return(errNotFound)
+
+	storage.EXPECT().BulkInsert(gomock.Any()).DoAndReturn(func(items []models.Model) error {
+		if len(items) != 2 {
+			t.Errorf("items count = %d, want 2", len(items))
+			return nil
+		}
+		version := items[0].(*tzipversion.Version)
+		if version.Level != 200 || version.Status != tzipversion.StatusFetched || version.RefKeyHash != keyHash || version.URI != server.URL {
+			t.Errorf("version = %+v", version)
+		}
+		current := items[1].(*tzipModel.TZIP)
+		if current.Version != "2.0.0" || current.Level != 200 {
+			t.Errorf("current metadata = %+v", current)
+		}
+		return nil
+	})
+
+	handler := NewTZIP(nil, nil, nil, versions, storage, map[string]noderpc.INode{"mainnet": nil}, nil)
+
+	ok, err := handler.Do(&bigmapdiff.BigMapDiff{Network: "mainnet", Address: "KT1", Ptr: 1, KeyHash: "exprOther", Level: 150})
+	if err != nil || ok {
+		t.Errorf("Do() of unrelated key = %v, %v", ok, err)
+	}
+
+	ok, err = handler.Do(&bigmapdiff.BigMapDiff{Network: "mainnet", Address: "KT1", Ptr: 1, KeyHash: keyHash, Level: 200})
+	if err != nil || !ok {
+		t.Errorf("Do() of referenced key = %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,39 @@
+package helpers
+
+import (
+	"strings"
+)
+
+// IsDecimal - returns true if `s` is non-negative integer in canonical decimal form (without sign and leading zeros)
+func IsDecimal(s string) bool {
+	if s == "" || (len(s) > 1 && s[0] == '0') {
+		return false
+	}
+	for i := range s {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// CompareDecimals - compares non-negative integers in canonical decimal form. Returns -1 if a < b, 0 if a == b and 1 if a > b.
+func CompareDecimals(a, b string) int {
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// DecimalFromJSON - returns decimal string from raw JSON number or JSON string. Documents indexed before arbitrary-precision token ids contain numbers.
+func DecimalFromJSON(data []byte) string {
+	s := string(data)
+	if s == "null" {
+		return ""
+	}
+	return strings.Trim(s, `"`)
+}
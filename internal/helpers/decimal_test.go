@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"testing"
+)
+
+func TestIsDecimal(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{s: "0", want: true},
+		{s: "123", want: true},
+		{s: "115792089237316195423570985008687907853269984665640564039457584007913129639935", want: true},
+		{s: "", want: false},
+		{s: "01", want: false},
+		{s: "-1", want: false},
+		{s: "1.5", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := IsDecimal(tt.s); got != tt.want {
+				t.Errorf("IsDecimal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareDecimals(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{a: "2", b: "10", want: -1},
+		{a: "10", b: "2", want: 1},
+		{a: "12", b: "12", want: 0},
+		{a: "18446744073709551616", b: "9223372036854775807", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := CompareDecimals(tt.a, tt.b); got != tt.want {
+				t.Errorf("CompareDecimals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -35,6 +35,24 @@ func URLJoin(baseURL, queryPath string) string {
 	return u.String()
 }
 
+// URLJoinWithQuery - joins `queryPath` to `baseURL` like `URLJoin` and sets `params` as URL query
+func URLJoinWithQuery(baseURL, queryPath string, params map[string]string) string {
+	joined := URLJoin(baseURL, queryPath)
+	if len(params) == 0 {
+		return joined
+	}
+	u, err := url.Parse(joined)
+	if err != nil {
+		return joined
+	}
+	q := u.Query()
+	for key, value := range params {
+		q.Set(key, value)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
 // SpaceStringsBuilder -
 func SpaceStringsBuilder(str string) string {
 	var b strings.Builder
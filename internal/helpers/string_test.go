@@ -50,3 +50,91 @@ func TestIsContract(t *testing.T) {
 		})
 	}
 }
+
+func TestURLJoin(t *testing.T) {
+	tests := []struct {
+		name      string
+		baseURL   string
+		queryPath string
+		want      string
+	}{
+		{
+			name:      "rpc with trailing slash",
+			baseURL:   "https://rpc.tzkt.io/mainnet/",
+			queryPath: "chains/main/blocks/head",
+			want:      "https://rpc.tzkt.io/mainnet/chains/main/blocks/head",
+		}, {
+			name:      "rpc without trailing slash",
+			baseURL:   "https://rpc.tzkt.io/mainnet",
+			queryPath: "chains/main/blocks/head/context/contracts/KT1Hkg5qeNhfwpKW4fXvq7HGZB9z2EnmCCA9/script",
+			want:      "https://rpc.tzkt.io/mainnet/chains/main/blocks/head/context/contracts/KT1Hkg5qeNhfwpKW4fXvq7HGZB9z2EnmCCA9/script",
+		}, {
+			name:      "rpc host only",
+			baseURL:   "http://127.0.0.1:8732",
+			queryPath: "monitor/heads/main",
+			want:      "http://127.0.0.1:8732/monitor/heads/main",
+		}, {
+			name:      "leading slash",
+			baseURL:   "https://rpc.tzkt.io/mainnet/",
+			queryPath: "/chains/main/blocks/head",
+			want:      "https://rpc.tzkt.io/mainnet/chains/main/blocks/head",
+		}, {
+			name:      "tzkt api",
+			baseURL:   "https://api.tzkt.io/v1/",
+			queryPath: "blocks/levels",
+			want:      "https://api.tzkt.io/v1/blocks/levels",
+		}, {
+			name:      "tzkt services",
+			baseURL:   "https://services.tzkt.io/v1",
+			queryPath: "mempool/tz1VSUr8wwNhLAzempoch5d6hLRiTh8Cjcjb",
+			want:      "https://services.tzkt.io/v1/mempool/tz1VSUr8wwNhLAzempoch5d6hLRiTh8Cjcjb",
+		}, {
+			name:      "pinata",
+			baseURL:   "https://api.pinata.cloud/",
+			queryPath: "pinning/pinJSONToIPFS",
+			want:      "https://api.pinata.cloud/pinning/pinJSONToIPFS",
+		}, {
+			name:      "question mark is a part of path",
+			baseURL:   "https://rpc.tzkt.io/mainnet",
+			queryPath: "chains/main/blocks/head?x=1",
+			want:      "https://rpc.tzkt.io/mainnet/chains/main/blocks/head%3Fx=1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := URLJoin(tt.baseURL, tt.queryPath); got != tt.want {
+				t.Errorf("URLJoin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLJoinWithQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		baseURL   string
+		queryPath string
+		params    map[string]string
+		want      string
+	}{
+		{
+			name:      "without params",
+			baseURL:   "https://rpc.tzkt.io/mainnet/",
+			queryPath: "chains/main/blocks/head",
+			want:      "https://rpc.tzkt.io/mainnet/chains/main/blocks/head",
+		}, {
+			name:      "with params",
+			baseURL:   "https://rpc.tzkt.io/mainnet",
+			queryPath: "chains/main/blocks/head/context/big_maps/1",
+			params:    map[string]string{"offset": "10", "length": "100"},
+			want:      "https://rpc.tzkt.io/mainnet/chains/main/blocks/head/context/big_maps/1?length=100&offset=10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := URLJoinWithQuery(tt.baseURL, tt.queryPath, tt.params); got != tt.want {
+				t.Errorf("URLJoinWithQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package ipfs
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/pkg/errors"
+)
+
+const defaultCacheSize = 1 << 30
+
+// CacheOption -
+type CacheOption func(*Cache)
+
+// WithCacheSize - sets max size of cached content in bytes
+func WithCacheSize(size int64) CacheOption {
+	return func(c *Cache) {
+		if size > 0 {
+			c.maxSize = size
+		}
+	}
+}
+
+// Cache - decorator of `Resolver` which stores resolved content in on-disk LRU cache keyed by CID.
+// IPFS content is immutable, so entries are never invalidated. Only trusted content is stored, content of path without sub path is verified again on read.
+type Cache struct {
+	Resolver
+
+	dir     string
+	maxSize int64
+
+	mux     sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+	size    int64
+}
+
+type cacheEntry struct {
+	key  string
+	size int64
+}
+
+// NewCache - creates cached decorator of `resolver` which stores content in `dir`
+func NewCache(resolver Resolver, dir string, opts ...CacheOption) (*Cache, error) {
+	c := &Cache{
+		Resolver: resolver,
+		dir:      dir,
+		maxSize:  defaultCacheSize,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+	for i := range opts {
+		opts[i](c)
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Resolve -
+func (c *Cache) Resolve(p Path) ([]byte, error) {
+	key := cacheKey(p)
+	if data, ok := c.get(key, p); ok {
+		return data, nil
+	}
+
+	data, trusted, err := resolveTrusted(c.Resolver, p)
+	if err != nil {
+		return nil, err
+	}
+	if !trusted {
+		logger.Info("[IPFS cache] %s is not cached: content is not verified", p)
+		return data, nil
+	}
+	if err := c.put(key, data); err != nil {
+		logger.Warning("[IPFS cache] can't cache %s: %s", p, err)
+	}
+	return data, nil
+}
+
+func (c *Cache) get(key string, p Path) ([]byte, bool) {
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	if p.Sub == "" {
+		if err := p.CID.Verify(data); err != nil && !errors.Is(err, ErrUnverifiable) {
+			logger.Warning("[IPFS cache] invalid entry %s: %s", key, err)
+			c.mux.Lock()
+			c.remove(key)
+			c.mux.Unlock()
+			return nil, false
+		}
+	}
+
+	c.mux.Lock()
+	c.add(key, int64(len(data)))
+	c.evict()
+	c.mux.Unlock()
+	return data, true
+}
+
+func (c *Cache) put(key string, data []byte) error {
+	tmp, err := ioutil.TempFile(c.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	c.mux.Lock()
+	c.add(key, int64(len(data)))
+	c.evict()
+	c.mux.Unlock()
+	return nil
+}
+
+// add - adds entry to LRU or marks it as recently used. Must be called under lock.
+func (c *Cache) add(key string, size int64) {
+	if el, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key, size})
+	c.size += size
+}
+
+// remove - removes entry from LRU and disk. Must be called under lock.
+func (c *Cache) remove(key string) {
+	if el, ok := c.entries[key]; ok {
+		entry := c.lru.Remove(el).(*cacheEntry)
+		delete(c.entries, key)
+		c.size -= entry.size
+	}
+	os.Remove(c.path(key))
+}
+
+// evict - removes least recently used entries while cache is oversized. Must be called under lock.
+func (c *Cache) evict() {
+	for c.size > c.maxSize && c.lru.Len() > 1 {
+		c.remove(c.lru.Back().Value.(*cacheEntry).key)
+	}
+}
+
+// load - restores LRU from cache directory
+func (c *Cache) load() error {
+	files, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+	for i := range files {
+		if files[i].IsDir() || strings.HasPrefix(files[i].Name(), ".") {
+			continue
+		}
+		c.add(files[i].Name(), files[i].Size())
+	}
+	c.evict()
+	return nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
+
+// cacheKey - CID for root content, CID with hash of sub path otherwise
+func cacheKey(p Path) string {
+	if p.Sub == "" {
+		return p.CID.String()
+	}
+	hash := sha256.Sum256([]byte(p.Sub))
+	return p.CID.String() + "-" + hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,184 @@
+package ipfs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/pkg/errors"
+)
+
+// multicodec codes
+const (
+	CodecRaw     uint64 = 0x55
+	CodecDagPB   uint64 = 0x70
+	CodecJSON    uint64 = 0x0200
+	HashIdentity uint64 = 0x00
+	HashSha256   uint64 = 0x12
+)
+
+// maxChunkSize - default chunk size of `ipfs add`. Files which are larger than it are split into several blocks.
+const maxChunkSize = 256 << 10
+
+var base32Encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// CID - content identifier: https://github.com/multiformats/cid
+type CID struct {
+	Version uint64
+	Codec   uint64
+	Hash    uint64
+	Digest  []byte
+
+	raw string
+}
+
+// ParseCID -
+func ParseCID(value string) (CID, error) {
+	if len(value) == 46 && strings.HasPrefix(value, "Qm") {
+		return parseCIDv0(value)
+	}
+	if len(value) < 2 {
+		return CID{}, errors.Wrap(ErrInvalidCID, value)
+	}
+
+	var data []byte
+	var err error
+	switch value[0] {
+	case 'b':
+		data, err = base32Encoding.DecodeString(strings.ToUpper(value[1:]))
+	case 'B':
+		data, err = base32Encoding.DecodeString(value[1:])
+	case 'z':
+		data = base58.Decode(value[1:])
+	case 'f', 'F':
+		data, err = hex.DecodeString(value[1:])
+	default:
+		return CID{}, errors.Wrapf(ErrInvalidCID, "unsupported multibase of %s", value)
+	}
+	if err != nil || len(data) == 0 {
+		return CID{}, errors.Wrap(ErrInvalidCID, value)
+	}
+
+	r := bytes.NewReader(data)
+	version, err := binary.ReadUvarint(r)
+	if err != nil || version != 1 {
+		return CID{}, errors.Wrapf(ErrInvalidCID, "unsupported version of %s", value)
+	}
+	codec, err := binary.ReadUvarint(r)
+	if err != nil {
+		return CID{}, errors.Wrap(ErrInvalidCID, value)
+	}
+	cid := CID{
+		Version: version,
+		Codec:   codec,
+		raw:     value,
+	}
+	if err := cid.parseMultihash(r); err != nil {
+		return CID{}, errors.Wrap(err, value)
+	}
+	return cid, nil
+}
+
+func parseCIDv0(value string) (CID, error) {
+	data := base58.Decode(value)
+	if len(data) == 0 {
+		return CID{}, errors.Wrap(ErrInvalidCID, value)
+	}
+	cid := CID{
+		Codec: CodecDagPB,
+		raw:   value,
+	}
+	if err := cid.parseMultihash(bytes.NewReader(data)); err != nil {
+		return CID{}, errors.Wrap(err, value)
+	}
+	if cid.Hash != HashSha256 {
+		return CID{}, errors.Wrap(ErrInvalidCID, value)
+	}
+	return cid, nil
+}
+
+func (cid *CID) parseMultihash(r *bytes.Reader) error {
+	code, err := binary.ReadUvarint(r)
+	if err != nil {
+		return ErrInvalidCID
+	}
+	length, err := binary.ReadUvarint(r)
+	if err != nil || length != uint64(r.Len()) {
+		return ErrInvalidCID
+	}
+	cid.Hash = code
+	cid.Digest = make([]byte, length)
+	_, err = r.Read(cid.Digest)
+	return err
+}
+
+// String -
+func (cid CID) String() string {
+	return cid.raw
+}
+
+// Equal - returns true if CIDs address the same block. CIDs of different versions can be equal.
+func (cid CID) Equal(other CID) bool {
+	return cid.Codec == other.Codec && cid.Hash == other.Hash && bytes.Equal(cid.Digest, other.Digest)
+}
+
+// Verify - checks that `data` is content of file addressed by CID. Only `raw` and `json` blocks and
+// single-block UnixFS files (which are created by `ipfs add` for files up to 256 KiB) can be verified.
+func (cid CID) Verify(data []byte) error {
+	var block []byte
+	switch cid.Codec {
+	case CodecRaw, CodecJSON:
+		block = data
+	case CodecDagPB:
+		if len(data) > maxChunkSize {
+			return errors.Wrapf(ErrUnverifiable, "file %s is split into several blocks", cid)
+		}
+		block = unixFSFileBlock(data)
+	default:
+		return errors.Wrapf(ErrUnverifiable, "unsupported codec 0x%x of %s", cid.Codec, cid)
+	}
+
+	var digest []byte
+	switch cid.Hash {
+	case HashIdentity:
+		digest = block
+	case HashSha256:
+		sum := sha256.Sum256(block)
+		digest = sum[:]
+	default:
+		return errors.Wrapf(ErrUnverifiable, "unsupported hash function 0x%x of %s", cid.Hash, cid)
+	}
+
+	if !bytes.Equal(digest, cid.Digest) {
+		return errors.Wrap(ErrHashMismatch, cid.String())
+	}
+	return nil
+}
+
+// unixFSFileBlock - encodes `data` as dag-pb node of UnixFS file without links: PBNode{Data: UnixFS{Type: File, Data: data, filesize}}
+func unixFSFileBlock(data []byte) []byte {
+	unixfs := []byte{0x08, 0x02}
+	if len(data) > 0 {
+		unixfs = appendBytesField(unixfs, 0x12, data)
+	}
+	unixfs = append(unixfs, 0x18)
+	unixfs = appendUvarint(unixfs, uint64(len(data)))
+
+	return appendBytesField(make([]byte, 0, len(unixfs)+binary.MaxVarintLen64+1), 0x0a, unixfs)
+}
+
+func appendBytesField(buf []byte, tag byte, value []byte) []byte {
+	buf = append(buf, tag)
+	buf = appendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+func appendUvarint(buf []byte, value uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], value)
+	return append(buf, tmp[:n]...)
+}
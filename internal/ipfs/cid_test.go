@@ -0,0 +1,100 @@
+package ipfs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCID_Verify(t *testing.T) {
+	tests := []struct {
+		name    string
+		cid     string
+		data    string
+		wantErr error
+	}{
+		{
+			name: "CIDv0 of UnixFS file",
+			cid:  "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o",
+			data: "hello world\n",
+		}, {
+			name: "CIDv0 of empty file",
+			cid:  "QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH",
+			data: "",
+		}, {
+			name: "CIDv1 of raw block",
+			cid:  "bafkreifzjut3te2nhyekklss27nh3k72ysco7y32koao5eei66wof36n5e",
+			data: "hello world",
+		}, {
+			name:    "content mismatch",
+			cid:     "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o",
+			data:    "hello world",
+			wantErr: ErrHashMismatch,
+		}, {
+			name:    "large UnixFS file",
+			cid:     "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o",
+			data:    strings.Repeat("a", maxChunkSize+1),
+			wantErr: ErrUnverifiable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cid, err := ParseCID(tt.cid)
+			if err != nil {
+				t.Errorf("ParseCID() error = %v", err)
+				return
+			}
+			if err := cid.Verify([]byte(tt.data)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantSub string
+		wantErr bool
+	}{
+		{
+			name:  "ipfs URI",
+			value: "ipfs://QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o",
+			want:  "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o",
+		}, {
+			name:    "ipfs URI with path",
+			value:   "ipfs://bafkreifzjut3te2nhyekklss27nh3k72ysco7y32koao5eei66wof36n5e/a/../metadata.json",
+			want:    "bafkreifzjut3te2nhyekklss27nh3k72ysco7y32koao5eei66wof36n5e/metadata.json",
+			wantSub: "metadata.json",
+		}, {
+			name:  "gateway path",
+			value: "/ipfs/QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH",
+			want:  "QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH",
+		}, {
+			name:    "invalid CID",
+			value:   "ipfs://Qminvalid",
+			wantErr: true,
+		}, {
+			name:    "unsupported multibase",
+			value:   "ipfs://mAXASIA",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePath(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.String() != tt.want || got.Sub != tt.wantSub {
+				t.Errorf("ParsePath() = %s (sub %s), want %s (sub %s)", got, got.Sub, tt.want, tt.wantSub)
+			}
+		})
+	}
+}
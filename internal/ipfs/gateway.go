@@ -0,0 +1,172 @@
+package ipfs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultTimeout  = 10 * time.Second
+	defaultMaxSize  = 10 << 20
+	headerIPFSRoots = "X-Ipfs-Roots"
+)
+
+// Gateways - requests all public HTTP gateways at once and returns the first valid response.
+// Gateways are not trusted: content of path without sub path is verified against its CID.
+// Content of sub path and multi-block files can not be verified without walking the DAG, so it is reported as untrusted.
+// `X-Ipfs-Roots` header is only used to skip gateways which report another root.
+type Gateways struct {
+	urls    []string
+	client  *http.Client
+	maxSize int64
+}
+
+// GatewaysOption -
+type GatewaysOption func(*Gateways)
+
+// WithGatewaysTimeout -
+func WithGatewaysTimeout(timeout time.Duration) GatewaysOption {
+	return func(g *Gateways) {
+		if timeout > 0 {
+			g.client.Timeout = timeout
+		}
+	}
+}
+
+// WithGatewaysMaxSize - sets max size of content in bytes
+func WithGatewaysMaxSize(size int64) GatewaysOption {
+	return func(g *Gateways) {
+		if size > 0 {
+			g.maxSize = size
+		}
+	}
+}
+
+// NewGateways -
+func NewGateways(urls []string, opts ...GatewaysOption) *Gateways {
+	g := &Gateways{
+		urls: urls,
+		client: &http.Client{
+			Timeout: defaultTimeout,
+		},
+		maxSize: defaultMaxSize,
+	}
+	for i := range opts {
+		opts[i](g)
+	}
+	return g
+}
+
+type gatewayResult struct {
+	data     []byte
+	verified bool
+	err      error
+}
+
+// Resolve -
+func (g *Gateways) Resolve(p Path) ([]byte, error) {
+	data, _, err := g.ResolveTrusted(p)
+	return data, err
+}
+
+// ResolveTrusted - content is trusted if it's verified against CID
+func (g *Gateways) ResolveTrusted(p Path) ([]byte, bool, error) {
+	if len(g.urls) == 0 {
+		return nil, false, ErrNoResolvers
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := make(chan gatewayResult, len(g.urls))
+	for i := range g.urls {
+		go func(url string) {
+			data, verified, err := g.get(ctx, url, p)
+			results <- gatewayResult{data, verified, err}
+		}(g.urls[i])
+	}
+
+	messages := make([]string, 0, len(g.urls))
+	for range g.urls {
+		result := <-results
+		if result.err == nil {
+			return result.data, result.verified, nil
+		}
+		messages = append(messages, result.err.Error())
+	}
+	return nil, false, errors.Wrapf(ErrNotResolved, "%s: %s", p, strings.Join(messages, "; "))
+}
+
+func (g *Gateways) get(ctx context.Context, gateway string, p Path) ([]byte, bool, error) {
+	url := fmt.Sprintf("%s/ipfs/%s", strings.TrimSuffix(gateway, "/"), p)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, errors.Errorf("%s: invalid status code %d", gateway, resp.StatusCode)
+	}
+
+	data, err := readLimited(resp.Body, g.maxSize)
+	if err != nil {
+		return nil, false, errors.Wrap(err, gateway)
+	}
+
+	if p.Sub != "" {
+		if err := checkRoot(p, resp.Header.Get(headerIPFSRoots)); err != nil {
+			return nil, false, errors.Wrap(err, gateway)
+		}
+		logger.Info("[IPFS] content of %s from %s is not verified", p, gateway)
+		return data, false, nil
+	}
+	if err := p.CID.Verify(data); err != nil {
+		if !errors.Is(err, ErrUnverifiable) {
+			return nil, false, errors.Wrap(err, gateway)
+		}
+		logger.Info("[IPFS] content of %s from %s is not verified: %s", p, gateway, err)
+		return data, false, nil
+	}
+	return data, true, nil
+}
+
+// checkRoot - returns error if gateway reports root of `p` which differs from its CID. Missing header is not an error.
+func checkRoot(p Path, roots string) error {
+	if roots == "" {
+		return nil
+	}
+	parts := strings.Split(roots, ",")
+	root, err := ParseCID(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return err
+	}
+	if !root.Equal(p.CID) {
+		return errors.Wrapf(ErrHashMismatch, "root %s of %s", root, p)
+	}
+	return nil
+}
+
+func readLimited(r io.Reader, maxSize int64) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, ErrTooLargeValue
+	}
+	return data, nil
+}
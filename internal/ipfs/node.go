@@ -0,0 +1,74 @@
+package ipfs
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Node - resolves paths with HTTP API of local IPFS node. Node verifies blocks by itself, so its content is trusted.
+type Node struct {
+	api     string
+	client  *http.Client
+	maxSize int64
+}
+
+// NodeOption -
+type NodeOption func(*Node)
+
+// WithNodeTimeout -
+func WithNodeTimeout(timeout time.Duration) NodeOption {
+	return func(n *Node) {
+		if timeout > 0 {
+			n.client.Timeout = timeout
+		}
+	}
+}
+
+// WithNodeMaxSize - sets max size of content in bytes
+func WithNodeMaxSize(size int64) NodeOption {
+	return func(n *Node) {
+		if size > 0 {
+			n.maxSize = size
+		}
+	}
+}
+
+// NewNode - `api` is base URL of node HTTP API, e.g. `http://127.0.0.1:5001`
+func NewNode(api string, opts ...NodeOption) *Node {
+	n := &Node{
+		api: strings.TrimSuffix(api, "/"),
+		client: &http.Client{
+			Timeout: defaultTimeout,
+		},
+		maxSize: defaultMaxSize,
+	}
+	for i := range opts {
+		opts[i](n)
+	}
+	return n
+}
+
+// ResolveTrusted - content of node is always trusted
+func (n *Node) ResolveTrusted(p Path) ([]byte, bool, error) {
+	data, err := n.Resolve(p)
+	return data, err == nil, err
+}
+
+// Resolve -
+func (n *Node) Resolve(p Path) ([]byte, error) {
+	query := url.Values{"arg": []string{"/ipfs/" + p.String()}}
+	resp, err := n.client.Post(n.api+"/api/v0/cat?"+query.Encode(), "", nil)
+	if err != nil {
+		return nil, errors.Wrap(ErrNotResolved, err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Wrapf(ErrNotResolved, "%s: invalid status code %d", p, resp.StatusCode)
+	}
+	return readLimited(resp.Body, n.maxSize)
+}
@@ -0,0 +1,42 @@
+package ipfs
+
+import (
+	"sync"
+
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/pinata"
+)
+
+// Pinning - decorator of `Resolver` which pins root CID of every resolved path to pinning service.
+// Pinning errors are logged and do not affect resolving.
+type Pinning struct {
+	Resolver
+
+	service pinata.Service
+	pinned  sync.Map
+}
+
+// NewPinning -
+func NewPinning(resolver Resolver, service pinata.Service) *Pinning {
+	return &Pinning{
+		Resolver: resolver,
+		service:  service,
+	}
+}
+
+// Resolve -
+func (p *Pinning) Resolve(path Path) ([]byte, error) {
+	data, err := p.Resolver.Resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cid := path.CID.String()
+	if _, ok := p.pinned.LoadOrStore(cid, struct{}{}); !ok {
+		if _, err := p.service.PinByHash(cid); err != nil {
+			p.pinned.Delete(cid)
+			logger.Warning("[IPFS] can't pin %s: %s", cid, err)
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,119 @@
+package ipfs
+
+import (
+	"errors"
+	"path"
+	"strings"
+)
+
+// Errors
+var (
+	ErrInvalidCID    = errors.New("Invalid CID")
+	ErrHashMismatch  = errors.New("Content does not match CID")
+	ErrUnverifiable  = errors.New("Content can not be verified")
+	ErrNoResolvers   = errors.New("Empty IPFS resolver list")
+	ErrNotResolved   = errors.New("Can't resolve IPFS path")
+	ErrTooLargeValue = errors.New("Too large IPFS content")
+)
+
+// Resolver - returns content of IPFS path
+type Resolver interface {
+	Resolve(p Path) ([]byte, error)
+}
+
+// TrustedResolver - resolver which reports whether content is trusted: it's verified against CID or received from node which verifies blocks by itself
+type TrustedResolver interface {
+	Resolver
+	ResolveTrusted(p Path) ([]byte, bool, error)
+}
+
+// resolveTrusted - resolves `p` by `r`. Content of resolver which does not report trust is trusted only if it's verified against CID.
+func resolveTrusted(r Resolver, p Path) ([]byte, bool, error) {
+	if tr, ok := r.(TrustedResolver); ok {
+		return tr.ResolveTrusted(p)
+	}
+	data, err := r.Resolve(p)
+	if err != nil {
+		return nil, false, err
+	}
+	return data, p.Sub == "" && p.CID.Verify(data) == nil, nil
+}
+
+// Path - IPFS path: CID of root and optional path inside it (e.g. `ipfs://<cid>/metadata.json`)
+type Path struct {
+	CID CID
+	Sub string
+}
+
+// ParsePath - parses `ipfs://<cid>[/path]` or `/ipfs/<cid>[/path]`
+func ParsePath(value string) (Path, error) {
+	value = strings.TrimPrefix(value, "ipfs://")
+	value = strings.TrimPrefix(value, "/ipfs/")
+
+	var sub string
+	if idx := strings.IndexByte(value, '/'); idx > -1 {
+		value, sub = value[:idx], value[idx:]
+	}
+
+	cid, err := ParseCID(value)
+	if err != nil {
+		return Path{}, err
+	}
+
+	if sub != "" {
+		sub = strings.TrimPrefix(path.Clean(sub), "/")
+	}
+	return Path{
+		CID: cid,
+		Sub: sub,
+	}, nil
+}
+
+// String -
+func (p Path) String() string {
+	if p.Sub == "" {
+		return p.CID.String()
+	}
+	return p.CID.String() + "/" + p.Sub
+}
+
+// Fallback - tries resolvers one by one until one of them returns trusted content.
+// Untrusted content (e.g. content of sub path received from gateway) is returned only if no resolver returns trusted one, so local node is preferred for it.
+type Fallback []Resolver
+
+// NewFallback -
+func NewFallback(resolvers ...Resolver) Fallback {
+	return Fallback(resolvers)
+}
+
+// Resolve -
+func (f Fallback) Resolve(p Path) ([]byte, error) {
+	data, _, err := f.ResolveTrusted(p)
+	return data, err
+}
+
+// ResolveTrusted -
+func (f Fallback) ResolveTrusted(p Path) ([]byte, bool, error) {
+	if len(f) == 0 {
+		return nil, false, ErrNoResolvers
+	}
+
+	var untrusted []byte
+	var resolved bool
+	var err error
+	for i := range f {
+		data, trusted, resolveErr := resolveTrusted(f[i], p)
+		switch {
+		case resolveErr != nil:
+			err = resolveErr
+		case trusted:
+			return data, true, nil
+		case !resolved:
+			untrusted, resolved = data, true
+		}
+	}
+	if resolved {
+		return untrusted, false, nil
+	}
+	return nil, false, err
+}
@@ -0,0 +1,245 @@
+package ipfs
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	helloCID = "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o"
+	emptyCID = "QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH"
+)
+
+func mustParsePath(t *testing.T, value string) Path {
+	p, err := ParsePath(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestGateways_Resolve(t *testing.T) {
+	honest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		switch r.URL.Path {
+		case "/ipfs/" + helloCID:
+			w.Write([]byte("hello world\n")) //nolint
+		case "/ipfs/" + emptyCID + "/hello.txt":
+			w.Header().Set(headerIPFSRoots, emptyCID+","+helloCID)
+			w.Write([]byte("hello world\n")) //nolint
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer honest.Close()
+
+	malicious := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("forged content")) //nolint
+	}))
+	defer malicious.Close()
+
+	wrongRoot := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerIPFSRoots, helloCID+","+helloCID)
+		w.Write([]byte("forged content")) //nolint
+	}))
+	defer wrongRoot.Close()
+
+	tests := []struct {
+		name     string
+		gateways []string
+		path     string
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "forged response is skipped",
+			gateways: []string{malicious.URL, honest.URL},
+			path:     "ipfs://" + helloCID,
+			want:     "hello world\n",
+		}, {
+			name:     "sub path",
+			gateways: []string{honest.URL},
+			path:     "ipfs://" + emptyCID + "/hello.txt",
+			want:     "hello world\n",
+		}, {
+			name:     "sub path without roots",
+			gateways: []string{malicious.URL},
+			path:     "ipfs://" + emptyCID + "/hello.txt",
+			want:     "forged content",
+		}, {
+			name:     "sub path with another root",
+			gateways: []string{wrongRoot.URL},
+			path:     "ipfs://" + emptyCID + "/hello.txt",
+			wantErr:  ErrNotResolved,
+		}, {
+			name:     "all gateways failed",
+			gateways: []string{malicious.URL, honest.URL},
+			path:     "ipfs://" + emptyCID,
+			wantErr:  ErrNotResolved,
+		}, {
+			name:    "empty gateway list",
+			path:    "ipfs://" + emptyCID,
+			wantErr: ErrNoResolvers,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewGateways(tt.gateways).Resolve(mustParsePath(t, tt.path))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Resolve() error = %v", err)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type countingResolver struct {
+	data  []byte
+	calls int
+}
+
+func (r *countingResolver) Resolve(p Path) ([]byte, error) {
+	r.calls++
+	return r.data, nil
+}
+
+// trustedResolver - resolver of local node which content is trusted
+type trustedResolver struct {
+	*countingResolver
+}
+
+func (r trustedResolver) ResolveTrusted(p Path) ([]byte, bool, error) {
+	data, err := r.Resolve(p)
+	return data, true, err
+}
+
+func TestFallback_ResolveTrusted(t *testing.T) {
+	p := mustParsePath(t, "ipfs://"+emptyCID+"/hello.txt")
+	gateway := &countingResolver{data: []byte("forged content")}
+	node := trustedResolver{&countingResolver{data: []byte("hello world\n")}}
+
+	got, trusted, err := NewFallback(gateway, node).ResolveTrusted(p)
+	if err != nil {
+		t.Fatalf("ResolveTrusted() error = %v", err)
+	}
+	if string(got) != "hello world\n" || !trusted {
+		t.Errorf("ResolveTrusted() = %q, %v, want content of node", got, trusted)
+	}
+
+	got, trusted, err = NewFallback(gateway).ResolveTrusted(p)
+	if err != nil {
+		t.Fatalf("ResolveTrusted() error = %v", err)
+	}
+	if string(got) != "forged content" || trusted {
+		t.Errorf("ResolveTrusted() = %q, %v, want untrusted content of gateway", got, trusted)
+	}
+}
+
+func TestCache_ResolveUntrusted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ipfs-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p := mustParsePath(t, "ipfs://"+emptyCID+"/hello.txt")
+	tests := []struct {
+		name      string
+		trusted   bool
+		wantCalls int
+	}{
+		{
+			name:      "unverified content is not cached",
+			wantCalls: 2,
+		}, {
+			name:      "content of trusted resolver is cached",
+			trusted:   true,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &countingResolver{data: []byte("hello world\n")}
+			var resolver Resolver = source
+			if tt.trusted {
+				resolver = trustedResolver{source}
+			}
+
+			cacheDir, err := ioutil.TempDir(dir, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			cache, err := NewCache(resolver, cacheDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 2; i++ {
+				if got, err := cache.Resolve(p); err != nil || string(got) != "hello world\n" {
+					t.Fatalf("Resolve() = %q, %v", got, err)
+				}
+			}
+			if source.calls != tt.wantCalls {
+				t.Errorf("source is called %d times, want %d", source.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCache_Resolve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ipfs-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	source := &countingResolver{data: []byte("hello world\n")}
+	cache, err := NewCache(source, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := mustParsePath(t, "ipfs://"+helloCID)
+
+	for i := 0; i < 2; i++ {
+		got, err := cache.Resolve(p)
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		if string(got) != "hello world\n" {
+			t.Fatalf("Resolve() = %q", got)
+		}
+	}
+	if source.calls != 1 {
+		t.Errorf("source is called %d times, want 1", source.calls)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, helloCID), []byte("corrupted"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := NewCache(source, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reopened.Resolve(p)
+	if err != nil || string(got) != "hello world\n" {
+		t.Errorf("Resolve() of corrupted entry = %q, %v", got, err)
+	}
+	if source.calls != 2 {
+		t.Errorf("source is called %d times, want 2", source.calls)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
 	"github.com/baking-bad/bcdhub/internal/events"
+	"github.com/baking-bad/bcdhub/internal/ipfs"
 	"github.com/baking-bad/bcdhub/internal/logger"
 	"github.com/baking-bad/bcdhub/internal/models"
 	"github.com/baking-bad/bcdhub/internal/models/contract"
@@ -19,7 +20,7 @@ import (
 )
 
 // CreateTokenMetadata -
-func (h *Handler) CreateTokenMetadata(rpc noderpc.INode, sharePath string, c *contract.Contract, ipfs ...string) error {
+func (h *Handler) CreateTokenMetadata(rpc noderpc.INode, sharePath string, c *contract.Contract, ipfs ipfs.Resolver) error {
 
 	result := make([]models.Model, 0)
 
@@ -35,7 +36,7 @@ func (h *Handler) CreateTokenMetadata(rpc noderpc.INode, sharePath string, c *co
 }
 
 // FixTokenMetadata -
-func (h *Handler) FixTokenMetadata(rpc noderpc.INode, sharePath string, contract *contract.Contract, operation *operation.Operation, ipfs ...string) error {
+func (h *Handler) FixTokenMetadata(rpc noderpc.INode, sharePath string, contract *contract.Contract, operation *operation.Operation, ipfs ipfs.Resolver) error {
 	if !operation.IsTransaction() || !operation.IsApplied() || !operation.IsCall() {
 		return nil
 	}
@@ -43,7 +44,6 @@ func (h *Handler) FixTokenMetadata(rpc noderpc.INode, sharePath string, contract
 	tokenMetadatas, err := h.TokenMetadata.Get(tokenmetadata.GetContext{
 		Contract: operation.Destination,
 		Network:  operation.Network,
-		TokenID:  -1,
 	})
 	if err != nil {
 		if !h.Storage.IsRecordNotFound(err) {
@@ -54,7 +54,7 @@ func (h *Handler) FixTokenMetadata(rpc noderpc.INode, sharePath string, contract
 	result := make([]models.Model, 0)
 
 	for _, tokenMetadata := range tokenMetadatas {
-		parser := tokens.NewParser(h.BigMapDiffs, h.Blocks, h.Protocol, h.Schema, h.Storage, rpc, sharePath, operation.Network, ipfs...)
+		parser := tokens.NewParser(h.BigMapDiffs, h.Blocks, h.Protocol, h.Schema, h.Storage, rpc, sharePath, operation.Network, ipfs)
 		metadata, err := parser.Parse(tokenMetadata.Contract, operation.Level)
 		if err != nil {
 			return err
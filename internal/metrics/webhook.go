@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"github.com/baking-bad/bcdhub/internal/database"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/contract"
+	"github.com/baking-bad/bcdhub/internal/models/migration"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/webhook"
+)
+
+const subscriptionsBatchSize = 100
+
+// SendOperationWebhooks - notifies webhooks of subscriptions to source or destination of operation
+func (h *Handler) SendOperationWebhooks(sender *webhook.Sender, op operation.Operation) error {
+	addresses := []string{op.Source}
+	if op.Destination != "" && op.Destination != op.Source {
+		addresses = append(addresses, op.Destination)
+	}
+
+	for _, address := range addresses {
+		subscriptions, err := h.DB.GetSubscriptions(address, op.Network)
+		if err != nil {
+			return err
+		}
+		for i := range subscriptions {
+			if event, ok := webhook.OperationEvent(subscriptions[i], op); ok {
+				if err := h.notifyWebhooks(sender, subscriptions[i], event); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// SendMigrationWebhooks - notifies webhooks of subscriptions to migrated contract
+func (h *Handler) SendMigrationWebhooks(sender *webhook.Sender, m migration.Migration) error {
+	subscriptions, err := h.DB.GetSubscriptions(m.Address, m.Network)
+	if err != nil {
+		return err
+	}
+	for i := range subscriptions {
+		if event, ok := webhook.MigrationEvent(subscriptions[i], m); ok {
+			if err := h.notifyWebhooks(sender, subscriptions[i], event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SendContractWebhooks - notifies webhooks of subscriptions to contracts which are the same or similar to new contract `c`
+func (h *Handler) SendContractWebhooks(sender *webhook.Sender, c contract.Contract) error {
+	subscriptions, err := h.DB.GetSubscriptionsByMask(c.Network, database.WatchSame|database.WatchSimilar)
+	if err != nil {
+		return err
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	addresses := make([]contract.Address, len(subscriptions))
+	for i := range subscriptions {
+		addresses[i] = contract.Address{
+			Address: subscriptions[i].Address,
+			Network: subscriptions[i].Network,
+		}
+	}
+	subscribed := make(map[string]contract.Contract, len(addresses))
+	for start := 0; start < len(addresses); start += subscriptionsBatchSize {
+		end := start + subscriptionsBatchSize
+		if end > len(addresses) {
+			end = len(addresses)
+		}
+		contracts, err := h.Contracts.GetByAddresses(addresses[start:end])
+		if err != nil {
+			return err
+		}
+		for i := range contracts {
+			subscribed[contracts[i].Address] = contracts[i]
+		}
+	}
+
+	for i := range subscriptions {
+		item, ok := subscribed[subscriptions[i].Address]
+		if !ok {
+			continue
+		}
+		if event, ok := webhook.ContractEvent(subscriptions[i], item, c); ok {
+			if err := h.notifyWebhooks(sender, subscriptions[i], event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (h *Handler) notifyWebhooks(sender *webhook.Sender, subscription database.Subscription, event models.Event) error {
+	webhooks, err := h.DB.GetActiveWebhooks(subscription.ID)
+	if err != nil {
+		return err
+	}
+	return sender.Notify(event, webhooks...)
+}
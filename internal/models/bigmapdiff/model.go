@@ -1,6 +1,9 @@
 package bigmapdiff
 
 import (
+	"bytes"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -44,7 +47,33 @@ func (b *BigMapDiff) GetQueues() []string {
 
 // MarshalToQueue -
 func (b *BigMapDiff) MarshalToQueue() ([]byte, error) {
-	return []byte(b.ID), nil
+	return json.Marshal(QueueMessage{
+		ID:      b.ID,
+		Network: b.Network,
+		Address: b.Address,
+		Ptr:     b.Ptr,
+		KeyHash: b.KeyHash,
+	})
+}
+
+// QueueMessage - body of big map diff message. Subscribers can filter messages by it without request to the storage.
+type QueueMessage struct {
+	ID      string `json:"id"`
+	Network string `json:"network"`
+	Address string `json:"address"`
+	Ptr     int64  `json:"ptr"`
+	KeyHash string `json:"key_hash"`
+}
+
+// ParseQueueMessage - decodes body of big map diff message. Body of previous format contains only ID.
+func ParseQueueMessage(body []byte) (QueueMessage, error) {
+	var msg QueueMessage
+	if !bytes.HasPrefix(body, []byte("{")) {
+		msg.ID = strings.Trim(string(body), `"`)
+		return msg, nil
+	}
+	err := json.Unmarshal(body, &msg)
+	return msg, err
 }
 
 // LogFields -
@@ -12,5 +12,6 @@ type Repository interface {
 	GetUniqueByOperationID(string) ([]BigMapDiff, error)
 	Count(network string, ptr int64) (int64, error)
 	CurrentByKey(network, keyHash string, ptr int64) (BigMapDiff, error)
+	CurrentByKeyAtLevel(network, keyHash string, ptr, level int64) (BigMapDiff, error)
 	Previous([]BigMapDiff, int64, string) ([]BigMapDiff, error)
 }
@@ -6,33 +6,47 @@ import (
 	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
 	"github.com/baking-bad/bcdhub/internal/models/block"
 	"github.com/baking-bad/bcdhub/internal/models/contract"
+	"github.com/baking-bad/bcdhub/internal/models/contractinterface"
 	"github.com/baking-bad/bcdhub/internal/models/migration"
 	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/models/outbox"
 	"github.com/baking-bad/bcdhub/internal/models/protocol"
+	"github.com/baking-bad/bcdhub/internal/models/rollbackplan"
+	"github.com/baking-bad/bcdhub/internal/models/saplingdiff"
 	"github.com/baking-bad/bcdhub/internal/models/schema"
 	"github.com/baking-bad/bcdhub/internal/models/tezosdomain"
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
+	"github.com/baking-bad/bcdhub/internal/models/ticketupdate"
 	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
 	"github.com/baking-bad/bcdhub/internal/models/tokenmetadata"
 	"github.com/baking-bad/bcdhub/internal/models/transfer"
 	"github.com/baking-bad/bcdhub/internal/models/tzip"
+	"github.com/baking-bad/bcdhub/internal/models/tzipversion"
 )
 
 // Document names
 const (
-	DocBalanceUpdates = "balance_update"
-	DocBigMapActions  = "bigmapaction"
-	DocBigMapDiff     = "bigmapdiff"
-	DocBlocks         = "block"
-	DocContracts      = "contract"
-	DocMigrations     = "migration"
-	DocOperations     = "operation"
-	DocProtocol       = "protocol"
-	DocSchema         = "schema"
-	DocTezosDomains   = "tezos_domain"
-	DocTokenBalances  = "token_balance"
-	DocTokenMetadata  = "token_metadata"
-	DocTransfers      = "transfer"
-	DocTZIP           = "tzip"
+	DocBalanceUpdates     = "balance_update"
+	DocBigMapActions      = "bigmapaction"
+	DocBigMapDiff         = "bigmapdiff"
+	DocBlocks             = "block"
+	DocContracts          = "contract"
+	DocContractInterfaces = "contract_interface"
+	DocMigrations         = "migration"
+	DocOperations         = "operation"
+	DocOutbox             = "outbox"
+	DocProtocol           = "protocol"
+	DocRollbackPlans      = "rollback_plan"
+	DocSaplingDiffs       = "sapling_diff"
+	DocSchema             = "schema"
+	DocTezosDomains       = "tezos_domain"
+	DocTicketBalances     = "ticket_balance"
+	DocTicketUpdates      = "ticket_update"
+	DocTokenBalances      = "token_balance"
+	DocTokenMetadata      = "token_metadata"
+	DocTransfers          = "transfer"
+	DocTZIP               = "tzip"
+	DocTZIPVersions       = "tzip_version"
 )
 
 // AllDocuments - returns all document names
@@ -43,15 +57,34 @@ func AllDocuments() []string {
 		DocBigMapDiff,
 		DocBlocks,
 		DocContracts,
+		DocContractInterfaces,
 		DocMigrations,
 		DocOperations,
+		DocOutbox,
 		DocProtocol,
+		DocRollbackPlans,
+		DocSaplingDiffs,
 		DocSchema,
 		DocTezosDomains,
+		DocTicketBalances,
+		DocTicketUpdates,
 		DocTokenBalances,
 		DocTokenMetadata,
 		DocTransfers,
 		DocTZIP,
+		DocTZIPVersions,
+	}
+}
+
+// ContractFields - returns names of the fields which contain contract address in documents of `index`. Document belongs to contract if any of them matches.
+func ContractFields(index string) []string {
+	switch index {
+	case DocBigMapActions, DocBigMapDiff, DocMigrations, DocTicketBalances, DocTicketUpdates, DocTZIP, DocTZIPVersions:
+		return []string{"address"}
+	case DocOperations:
+		return []string{"source", "destination"}
+	default:
+		return []string{"contract"}
 	}
 }
 
@@ -63,14 +96,21 @@ func AllModels() []Model {
 		&bigmapdiff.BigMapDiff{},
 		&block.Block{},
 		&contract.Contract{},
+		&contractinterface.Interface{},
 		&migration.Migration{},
 		&operation.Operation{},
+		&outbox.Message{},
 		&protocol.Protocol{},
+		&rollbackplan.Plan{},
+		&saplingdiff.SaplingDiff{},
 		&schema.Schema{},
 		&tezosdomain.TezosDomain{},
+		&ticketbalance.TicketBalance{},
+		&ticketupdate.TicketUpdate{},
 		&tokenbalance.TokenBalance{},
 		&tokenmetadata.TokenMetadata{},
 		&transfer.Transfer{},
 		&tzip.TZIP{},
+		&tzipversion.Version{},
 	}
 }
@@ -0,0 +1,64 @@
+package contractinterface
+
+import (
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/kinds"
+	"github.com/sirupsen/logrus"
+)
+
+// Interface - user-defined contract interface stored in the index. Interface is `Retagged` when all known contracts are checked for it.
+type Interface struct {
+	Name        string             `json:"name"`
+	IsRoot      bool               `json:"is_root"`
+	Entrypoints []kinds.Entrypoint `json:"entrypoints"`
+	Timestamp   time.Time          `json:"timestamp"`
+	Retagged    bool               `json:"retagged"`
+}
+
+// New - creates model of interface `i` which has to be retagged
+func New(i kinds.Interface) *Interface {
+	return &Interface{
+		Name:        i.Name,
+		IsRoot:      i.IsRoot,
+		Entrypoints: i.Entrypoints,
+		Timestamp:   time.Now().UTC(),
+	}
+}
+
+// Kind - returns description of the interface
+func (i *Interface) Kind() kinds.Interface {
+	return kinds.Interface{
+		Name:        i.Name,
+		IsRoot:      i.IsRoot,
+		Entrypoints: i.Entrypoints,
+	}
+}
+
+// GetID -
+func (i *Interface) GetID() string {
+	return i.Name
+}
+
+// GetIndex -
+func (i *Interface) GetIndex() string {
+	return "contract_interface"
+}
+
+// GetQueues -
+func (i *Interface) GetQueues() []string {
+	return nil
+}
+
+// MarshalToQueue -
+func (i *Interface) MarshalToQueue() ([]byte, error) {
+	return nil, nil
+}
+
+// LogFields -
+func (i *Interface) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"name":     i.Name,
+		"retagged": i.Retagged,
+	}
+}
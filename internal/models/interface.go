@@ -9,7 +9,10 @@ type GeneralRepository interface {
 	CreateIndexes() error
 	DeleteIndices(indices []string) error
 	DeleteByLevelAndNetwork([]string, string, int64) error
+	// DeleteByContract - deletes documents of contract `address` from `indices`. Fields which contain address are returned by `ContractFields`.
 	DeleteByContract(indices []string, network, address string) error
+	// Refresh - makes all changes of `indices` visible for search. It returns after changes are visible.
+	Refresh(indices []string) error
 	GetAll(interface{}) error
 	GetByID(Model) error
 	GetByIDs(output interface{}, ids ...string) error
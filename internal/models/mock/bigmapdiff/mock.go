@@ -184,6 +184,21 @@ func (mr *MockRepositoryMockRecorder) CurrentByKey(network, keyHash, ptr interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentByKey", reflect.TypeOf((*MockRepository)(nil).CurrentByKey), network, keyHash, ptr)
 }
 
+// CurrentByKeyAtLevel mocks base method
+func (m *MockRepository) CurrentByKeyAtLevel(network, keyHash string, ptr, level int64) (bigmapdiff.BigMapDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurrentByKeyAtLevel", network, keyHash, ptr, level)
+	ret0, _ := ret[0].(bigmapdiff.BigMapDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CurrentByKeyAtLevel indicates an expected call of CurrentByKeyAtLevel
+func (mr *MockRepositoryMockRecorder) CurrentByKeyAtLevel(network, keyHash, ptr, level interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentByKeyAtLevel", reflect.TypeOf((*MockRepository)(nil).CurrentByKeyAtLevel), network, keyHash, ptr, level)
+}
+
 // Previous mocks base method
 func (m *MockRepository) Previous(arg0 []bigmapdiff.BigMapDiff, arg1 int64, arg2 string) ([]bigmapdiff.BigMapDiff, error) {
 	m.ctrl.T.Helper()
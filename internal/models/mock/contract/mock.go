@@ -63,19 +63,34 @@ func (mr *MockRepositoryMockRecorder) GetMany(by interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockRepository)(nil).GetMany), by)
 }
 
+// GetPage mocks base method
+func (m *MockRepository) GetPage(network, lastAddress string, size int64) ([]contractModel.Contract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPage", network, lastAddress, size)
+	ret0, _ := ret[0].([]contractModel.Contract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPage indicates an expected call of GetPage
+func (mr *MockRepositoryMockRecorder) GetPage(network, lastAddress, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPage", reflect.TypeOf((*MockRepository)(nil).GetPage), network, lastAddress, size)
+}
+
 // GetRandom mocks base method
-func (m *MockRepository) GetRandom() (contractModel.Contract, error) {
+func (m *MockRepository) GetRandom(network string) (contractModel.Contract, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRandom")
+	ret := m.ctrl.Call(m, "GetRandom", network)
 	ret0, _ := ret[0].(contractModel.Contract)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRandom indicates an expected call of GetRandom
-func (mr *MockRepositoryMockRecorder) GetRandom() *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetRandom(network interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRandom", reflect.TypeOf((*MockRepository)(nil).GetRandom))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRandom", reflect.TypeOf((*MockRepository)(nil).GetRandom), network)
 }
 
 // GetAddressesByNetworkAndLevel mocks base method
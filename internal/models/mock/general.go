@@ -90,6 +90,20 @@ func (mr *MockGeneralRepositoryMockRecorder) DeleteByContract(indices, network,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByContract", reflect.TypeOf((*MockGeneralRepository)(nil).DeleteByContract), indices, network, address)
 }
 
+// Refresh mocks base method
+func (m *MockGeneralRepository) Refresh(indices []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", indices)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refresh indicates an expected call of Refresh
+func (mr *MockGeneralRepositoryMockRecorder) Refresh(indices interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockGeneralRepository)(nil).Refresh), indices)
+}
+
 // GetAll mocks base method
 func (m *MockGeneralRepository) GetAll(arg0 interface{}) error {
 	m.ctrl.T.Helper()
@@ -180,10 +194,10 @@ func (mr *MockGeneralRepositoryMockRecorder) UpdateDoc(model interface{}) *gomoc
 }
 
 // UpdateFields mocks base method
-func (m *MockGeneralRepository) UpdateFields(arg0, arg1 string, arg2 interface{}, arg3 ...string) error {
+func (m *MockGeneralRepository) UpdateFields(index, id string, data interface{}, fields ...string) error {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{arg0, arg1, arg2}
-	for _, a := range arg3 {
+	varargs := []interface{}{index, id, data}
+	for _, a := range fields {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "UpdateFields", varargs...)
@@ -192,9 +206,9 @@ func (m *MockGeneralRepository) UpdateFields(arg0, arg1 string, arg2 interface{}
 }
 
 // UpdateFields indicates an expected call of UpdateFields
-func (mr *MockGeneralRepositoryMockRecorder) UpdateFields(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+func (mr *MockGeneralRepositoryMockRecorder) UpdateFields(index, id, data interface{}, fields ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	varargs := append([]interface{}{index, id, data}, fields...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFields", reflect.TypeOf((*MockGeneralRepository)(nil).UpdateFields), varargs...)
 }
 
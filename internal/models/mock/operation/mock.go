@@ -78,6 +78,36 @@ func (mr *MockRepositoryMockRecorder) Last(network, address, indexedTime interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Last", reflect.TypeOf((*MockRepository)(nil).Last), network, address, indexedTime)
 }
 
+// LastByLevel mocks base method
+func (m *MockRepository) LastByLevel(network, address string, level int64) (operation.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastByLevel", network, address, level)
+	ret0, _ := ret[0].(operation.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastByLevel indicates an expected call of LastByLevel
+func (mr *MockRepositoryMockRecorder) LastByLevel(network, address, level interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastByLevel", reflect.TypeOf((*MockRepository)(nil).LastByLevel), network, address, level)
+}
+
+// GetBalance mocks base method
+func (m *MockRepository) GetBalance(network, address string, level int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", network, address, level)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalance indicates an expected call of GetBalance
+func (mr *MockRepositoryMockRecorder) GetBalance(network, address, level interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockRepository)(nil).GetBalance), network, address, level)
+}
+
 // Get mocks base method
 func (m *MockRepository) Get(filter map[string]interface{}, size int64, sort bool) ([]operation.Operation, error) {
 	m.ctrl.T.Helper()
@@ -138,6 +168,21 @@ func (mr *MockRepositoryMockRecorder) GetParticipatingContracts(network, fromLev
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParticipatingContracts", reflect.TypeOf((*MockRepository)(nil).GetParticipatingContracts), network, fromLevel, toLevel)
 }
 
+// GetContractLevels mocks base method
+func (m *MockRepository) GetContractLevels(network, address string) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContractLevels", network, address)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContractLevels indicates an expected call of GetContractLevels
+func (mr *MockRepositoryMockRecorder) GetContractLevels(network, address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContractLevels", reflect.TypeOf((*MockRepository)(nil).GetContractLevels), network, address)
+}
+
 // RecalcStats mocks base method
 func (m *MockRepository) RecalcStats(network, address string) (operation.ContractStats, error) {
 	m.ctrl.T.Helper()
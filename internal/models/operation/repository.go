@@ -6,6 +6,10 @@ type Repository interface {
 	GetStats(network, address string) (Stats, error)
 	// Last - returns last operation. TODO: change network and address.
 	Last(network string, address string, indexedTime int64) (Operation, error)
+	// LastByLevel - returns last applied operation to `address` with storage at or before `level`
+	LastByLevel(network, address string, level int64) (Operation, error)
+	// GetBalance - returns balance of `address` computed by applied operations at or before `level`
+	GetBalance(network, address string, level int64) (int64, error)
 
 	// GetOperations - get operation by `filter`. `Size` - if 0 - return all, else certain `size` operations.
 	// `Sort` - sort by time and content index by desc
@@ -15,6 +19,8 @@ type Repository interface {
 	GetTokensStats(network string, addresses, entrypoints []string) (map[string]TokenUsageStats, error)
 
 	GetParticipatingContracts(network string, fromLevel int64, toLevel int64) ([]string, error)
+	// GetContractLevels - returns ascending unique levels of operations where `address` is source or destination
+	GetContractLevels(network, address string) ([]int64, error)
 	RecalcStats(network, address string) (ContractStats, error)
 	GetDAppStats(string, []string, string) (DAppStats, error)
 }
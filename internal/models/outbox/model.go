@@ -0,0 +1,61 @@
+package outbox
+
+import (
+	"github.com/baking-bad/bcdhub/internal/helpers"
+	"github.com/baking-bad/bcdhub/internal/mq"
+)
+
+// Message - queue message which is saved together with indexed data and is removed after publishing
+type Message struct {
+	ID string `json:"-"`
+
+	Network string `json:"network"`
+	Level   int64  `json:"level"`
+	Queue   string `json:"queue"`
+	Body    string `json:"body"`
+}
+
+// New - creates outbox messages for every queue of `msg`
+func New(network string, level int64, msg mq.IMessage) ([]*Message, error) {
+	queues := msg.GetQueues()
+	if len(queues) == 0 {
+		return nil, nil
+	}
+
+	body, err := msg.MarshalToQueue()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*Message, len(queues))
+	for i := range queues {
+		messages[i] = &Message{
+			ID:      helpers.GenerateID(),
+			Network: network,
+			Level:   level,
+			Queue:   queues[i],
+			Body:    string(body),
+		}
+	}
+	return messages, nil
+}
+
+// GetID -
+func (m *Message) GetID() string {
+	return m.ID
+}
+
+// GetIndex -
+func (m *Message) GetIndex() string {
+	return "outbox"
+}
+
+// MarshalToQueue -
+func (m *Message) MarshalToQueue() ([]byte, error) {
+	return nil, nil
+}
+
+// GetQueues -
+func (m *Message) GetQueues() []string {
+	return nil
+}
@@ -0,0 +1,55 @@
+package rollbackplan
+
+import (
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
+	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
+)
+
+// Plan - persisted state of network rollback. It is saved before any indexed data is changed and its `Step` is moved forward after every finished step,
+// so rollback interrupted by crash or restart is resumed from the first unfinished step. Only one plan per network can exist.
+type Plan struct {
+	Network   string    `json:"network"`
+	FromLevel int64     `json:"from_level"`
+	ToLevel   int64     `json:"to_level"`
+	ToHash    string    `json:"to_hash,omitempty"`
+	Protocol  string    `json:"protocol"`
+	Step      int       `json:"step"`
+	CreatedAt time.Time `json:"created_at"`
+
+	AffectedContracts []string `json:"affected_contracts,omitempty"`
+
+	// Offset - number of balances restored by current step
+	Offset int `json:"offset"`
+
+	// TokenBalances and TicketBalances - page of balances at `ToLevel` which is being written. Page is saved here before it is written
+	// and cleared when `Offset` is moved, so resumed rollback writes the same absolute values instead of applying changes twice.
+	TokenBalances  []*tokenbalance.TokenBalance   `json:"token_balances,omitempty"`
+	TicketBalances []*ticketbalance.TicketBalance `json:"ticket_balances,omitempty"`
+}
+
+// NewPlan -
+func NewPlan(network string) *Plan {
+	return &Plan{Network: network}
+}
+
+// GetID -
+func (p *Plan) GetID() string {
+	return p.Network
+}
+
+// GetIndex -
+func (p *Plan) GetIndex() string {
+	return "rollback_plan"
+}
+
+// GetQueues -
+func (p *Plan) GetQueues() []string {
+	return nil
+}
+
+// MarshalToQueue -
+func (p *Plan) MarshalToQueue() ([]byte, error) {
+	return nil, nil
+}
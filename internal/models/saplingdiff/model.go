@@ -0,0 +1,75 @@
+package saplingdiff
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Sapling state diff actions
+const (
+	ActionAlloc  = "alloc"
+	ActionUpdate = "update"
+	ActionCopy   = "copy"
+	ActionRemove = "remove"
+)
+
+// SaplingDiff - changes of sapling state made by operation
+type SaplingDiff struct {
+	ID               string    `json:"-"`
+	Network          string    `json:"network"`
+	Level            int64     `json:"level"`
+	Timestamp        time.Time `json:"timestamp"`
+	IndexedTime      int64     `json:"indexed_time"`
+	OperationID      string    `json:"operation_id"`
+	Hash             string    `json:"hash"`
+	Contract         string    `json:"contract"`
+	Ptr              int64     `json:"ptr"`
+	Action           string    `json:"action"`
+	SourcePtr        *int64    `json:"source_ptr,omitempty"`
+	MemoSize         int64     `json:"memo_size,omitempty"`
+	Commitments      []string  `json:"commitments,omitempty"`
+	Nullifiers       []string  `json:"nullifiers,omitempty"`
+	CommitmentsCount int64     `json:"commitments_count"`
+	NullifiersCount  int64     `json:"nullifiers_count"`
+}
+
+// GetID -
+func (s *SaplingDiff) GetID() string {
+	return s.ID
+}
+
+// GetIndex -
+func (s *SaplingDiff) GetIndex() string {
+	return "sapling_diff"
+}
+
+// GetQueues -
+func (s *SaplingDiff) GetQueues() []string {
+	return nil
+}
+
+// MarshalToQueue -
+func (s *SaplingDiff) MarshalToQueue() ([]byte, error) {
+	return nil, nil
+}
+
+// LogFields -
+func (s *SaplingDiff) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"network":     s.Network,
+		"contract":    s.Contract,
+		"ptr":         s.Ptr,
+		"action":      s.Action,
+		"block":       s.Level,
+		"commitments": s.CommitmentsCount,
+		"nullifiers":  s.NullifiersCount,
+	}
+}
+
+// Stats - aggregated counters of sapling state
+type Stats struct {
+	Commitments int64
+	Nullifiers  int64
+	Updates     int64
+}
@@ -0,0 +1,8 @@
+package saplingdiff
+
+// Repository -
+type Repository interface {
+	Get(network string, ptr, size, offset int64) ([]SaplingDiff, error)
+	GetFirst(network string, ptr int64) (SaplingDiff, error)
+	GetStats(network string, ptr, maxLevel int64) (Stats, error)
+}
@@ -0,0 +1,91 @@
+package ticketbalance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// TicketBalance - amount of tickets with the same ticketer and content held by contract. Ticketer has no balance of its own tickets.
+type TicketBalance struct {
+	Network     string `json:"network"`
+	Address     string `json:"address"`
+	Ticketer    string `json:"ticketer"`
+	ContentType string `json:"content_type"`
+	Content     string `json:"content"`
+	Balance     string `json:"balance"`
+
+	Value *big.Int `json:"-"`
+}
+
+// GetID -
+func (tb *TicketBalance) GetID() string {
+	return fmt.Sprintf("%s_%s_%s_%s", tb.Network, tb.Address, tb.Ticketer, ContentHash(tb.ContentType, tb.Content))
+}
+
+// GetIndex -
+func (tb *TicketBalance) GetIndex() string {
+	return "ticket_balance"
+}
+
+// GetQueues -
+func (tb *TicketBalance) GetQueues() []string {
+	return nil
+}
+
+// MarshalToQueue -
+func (tb *TicketBalance) MarshalToQueue() ([]byte, error) {
+	return nil, nil
+}
+
+// LogFields -
+func (tb *TicketBalance) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"network":  tb.Network,
+		"address":  tb.Address,
+		"ticketer": tb.Ticketer,
+		"content":  tb.Content,
+		"balance":  tb.Value.String(),
+	}
+}
+
+// Sum -
+func (tb *TicketBalance) Sum(delta *TicketBalance) {
+	tb.Value.Add(tb.Value, delta.Value)
+}
+
+// UnmarshalJSON -
+func (tb *TicketBalance) UnmarshalJSON(data []byte) error {
+	type buf TicketBalance
+	if err := json.Unmarshal(data, (*buf)(tb)); err != nil {
+		return err
+	}
+	tb.Value = big.NewInt(0)
+
+	if _, ok := tb.Value.SetString(tb.Balance, 10); !ok {
+		return fmt.Errorf("Can't set balance value: %s", tb.Balance)
+	}
+	return nil
+}
+
+// MarshalJSON -
+func (tb *TicketBalance) MarshalJSON() ([]byte, error) {
+	if tb.Value == nil {
+		return nil, fmt.Errorf("Nil balance value")
+	}
+	tb.Balance = tb.Value.String()
+	type buf TicketBalance
+	return json.Marshal((*buf)(tb))
+}
+
+// ContentHash - returns short hash of ticket content which identifies ticket kind together with ticketer
+func ContentHash(contentType, content string) string {
+	h := sha256.Sum256([]byte(contentType + content))
+	return hex.EncodeToString(h[:16])
+}
@@ -0,0 +1,7 @@
+package ticketbalance
+
+// Repository -
+type Repository interface {
+	GetAccountBalances(network, address string) ([]TicketBalance, error)
+	Update(updates []*TicketBalance) error
+}
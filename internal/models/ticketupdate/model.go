@@ -0,0 +1,107 @@
+package ticketupdate
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// TicketUpdate - change of amount of tickets held by contract. Amount is negative if contract sent tickets.
+type TicketUpdate struct {
+	ID          string    `json:"-"`
+	Network     string    `json:"network"`
+	Level       int64     `json:"level"`
+	Timestamp   time.Time `json:"timestamp"`
+	OperationID string    `json:"operation_id"`
+	Address     string    `json:"address"`
+	Ticketer    string    `json:"ticketer"`
+	ContentType string    `json:"content_type"`
+	Content     string    `json:"content"`
+	Amount      string    `json:"amount"`
+
+	AmountBigInt *big.Int `json:"-"`
+}
+
+// GetID -
+func (t *TicketUpdate) GetID() string {
+	return t.ID
+}
+
+// GetIndex -
+func (t *TicketUpdate) GetIndex() string {
+	return "ticket_update"
+}
+
+// GetQueues -
+func (t *TicketUpdate) GetQueues() []string {
+	return nil
+}
+
+// MarshalToQueue -
+func (t *TicketUpdate) MarshalToQueue() ([]byte, error) {
+	return nil, nil
+}
+
+// LogFields -
+func (t *TicketUpdate) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"network":  t.Network,
+		"block":    t.Level,
+		"address":  t.Address,
+		"ticketer": t.Ticketer,
+		"amount":   t.AmountBigInt.String(),
+	}
+}
+
+// GetTicketBalanceID -
+func (t *TicketUpdate) GetTicketBalanceID() string {
+	return fmt.Sprintf("%s_%s_%s_%s", t.Network, t.Address, t.Ticketer, ticketbalance.ContentHash(t.ContentType, t.Content))
+}
+
+// MakeTicketBalanceUpdate -
+func (t *TicketUpdate) MakeTicketBalanceUpdate(rollback bool) *ticketbalance.TicketBalance {
+	tb := &ticketbalance.TicketBalance{
+		Network:     t.Network,
+		Address:     t.Address,
+		Ticketer:    t.Ticketer,
+		ContentType: t.ContentType,
+		Content:     t.Content,
+		Value:       big.NewInt(0),
+	}
+	if rollback {
+		tb.Value.Neg(t.AmountBigInt)
+	} else {
+		tb.Value.Set(t.AmountBigInt)
+	}
+	return tb
+}
+
+// UnmarshalJSON -
+func (t *TicketUpdate) UnmarshalJSON(data []byte) error {
+	type buf TicketUpdate
+	if err := json.Unmarshal(data, (*buf)(t)); err != nil {
+		return err
+	}
+	t.AmountBigInt = big.NewInt(0)
+
+	if _, ok := t.AmountBigInt.SetString(t.Amount, 10); !ok {
+		return fmt.Errorf("Can't set amount value: %s", t.Amount)
+	}
+	return nil
+}
+
+// MarshalJSON -
+func (t *TicketUpdate) MarshalJSON() ([]byte, error) {
+	if t.AmountBigInt == nil {
+		return nil, fmt.Errorf("Nil amount value")
+	}
+	t.Amount = t.AmountBigInt.String()
+	type buf TicketUpdate
+	return json.Marshal((*buf)(t))
+}
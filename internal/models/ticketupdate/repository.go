@@ -0,0 +1,6 @@
+package ticketupdate
+
+// Repository -
+type Repository interface {
+	GetAll(network string, level int64) ([]TicketUpdate, error)
+}
@@ -1,9 +1,11 @@
 package tokenbalance
 
 import (
+	stdJSON "encoding/json"
 	"fmt"
 	"math/big"
 
+	"github.com/baking-bad/bcdhub/internal/helpers"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/sirupsen/logrus"
 )
@@ -15,7 +17,7 @@ type TokenBalance struct {
 	Network  string `json:"network"`
 	Address  string `json:"address"`
 	Contract string `json:"contract"`
-	TokenID  int64  `json:"token_id"`
+	TokenID  string `json:"token_id"`
 	Balance  string `json:"balance"`
 
 	Value *big.Int `json:"-"`
@@ -23,7 +25,7 @@ type TokenBalance struct {
 
 // GetID -
 func (tb *TokenBalance) GetID() string {
-	return fmt.Sprintf("%s_%s_%s_%d", tb.Network, tb.Address, tb.Contract, tb.TokenID)
+	return fmt.Sprintf("%s_%s_%s_%s", tb.Network, tb.Address, tb.Contract, tb.TokenID)
 }
 
 // GetIndex -
@@ -60,9 +62,15 @@ func (tb *TokenBalance) Sum(delta *TokenBalance) {
 // UnmarshalJSON -
 func (tb *TokenBalance) UnmarshalJSON(data []byte) error {
 	type buf TokenBalance
-	if err := json.Unmarshal(data, (*buf)(tb)); err != nil {
+	var b struct {
+		*buf
+		TokenID stdJSON.RawMessage `json:"token_id"`
+	}
+	b.buf = (*buf)(tb)
+	if err := json.Unmarshal(data, &b); err != nil {
 		return err
 	}
+	tb.TokenID = helpers.DecimalFromJSON(b.TokenID)
 	tb.Value = big.NewInt(0)
 
 	if _, ok := tb.Value.SetString(tb.Balance, 10); !ok {
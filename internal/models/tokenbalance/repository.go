@@ -4,5 +4,5 @@ package tokenbalance
 type Repository interface {
 	GetAccountBalances(string, string) ([]TokenBalance, error)
 	Update(updates []*TokenBalance) error
-	GetHolders(network, contract string, tokenID int64) ([]TokenBalance, error)
+	GetHolders(network, contract string, tokenID string) ([]TokenBalance, error)
 }
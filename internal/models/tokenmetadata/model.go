@@ -1,9 +1,11 @@
 package tokenmetadata
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/baking-bad/bcdhub/internal/helpers"
 	"github.com/sirupsen/logrus"
 )
 
@@ -13,7 +15,7 @@ type TokenMetadata struct {
 	Contract  string                 `json:"contract"`
 	Level     int64                  `json:"level"`
 	Timestamp time.Time              `json:"timestamp"`
-	TokenID   int64                  `json:"token_id"`
+	TokenID   string                 `json:"token_id"`
 	Symbol    string                 `json:"symbol"`
 	Name      string                 `json:"name"`
 	Decimals  *int64                 `json:"decimals,omitempty"`
@@ -38,13 +40,15 @@ func (tm ByName) Less(i, j int) bool {
 // ByTokenID - TokenMetadata sorting filter by TokenID field
 type ByTokenID []TokenMetadata
 
-func (tm ByTokenID) Len() int           { return len(tm) }
-func (tm ByTokenID) Swap(i, j int)      { tm[i], tm[j] = tm[j], tm[i] }
-func (tm ByTokenID) Less(i, j int) bool { return tm[i].TokenID < tm[j].TokenID }
+func (tm ByTokenID) Len() int      { return len(tm) }
+func (tm ByTokenID) Swap(i, j int) { tm[i], tm[j] = tm[j], tm[i] }
+func (tm ByTokenID) Less(i, j int) bool {
+	return helpers.CompareDecimals(tm[i].TokenID, tm[j].TokenID) < 0
+}
 
 // GetID -
 func (t *TokenMetadata) GetID() string {
-	return fmt.Sprintf("%s_%s_%d", t.Network, t.Contract, t.TokenID)
+	return fmt.Sprintf("%s_%s_%s", t.Network, t.Contract, t.TokenID)
 }
 
 // GetIndex -
@@ -70,3 +74,18 @@ func (t *TokenMetadata) LogFields() logrus.Fields {
 		"token_id": t.TokenID,
 	}
 }
+
+// UnmarshalJSON -
+func (t *TokenMetadata) UnmarshalJSON(data []byte) error {
+	type buf TokenMetadata
+	var b struct {
+		*buf
+		TokenID json.RawMessage `json:"token_id"`
+	}
+	b.buf = (*buf)(t)
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	t.TokenID = helpers.DecimalFromJSON(b.TokenID)
+	return nil
+}
@@ -1,9 +1,12 @@
 package transfer
 
 import (
+	"bytes"
+	stdJSON "encoding/json"
 	"fmt"
 	"math/big"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/baking-bad/bcdhub/internal/helpers"
@@ -28,7 +31,7 @@ type Transfer struct {
 	Level        int64     `json:"level"`
 	From         string    `json:"from"`
 	To           string    `json:"to"`
-	TokenID      int64     `json:"token_id"`
+	TokenID      string    `json:"token_id"`
 	Amount       float64   `json:"amount"`
 	AmountStr    string    `json:"amount_str"`
 	AmountBigInt *big.Int  `json:"-"`
@@ -49,12 +52,38 @@ func (t *Transfer) GetIndex() string {
 
 // GetQueues -
 func (t *Transfer) GetQueues() []string {
-	return nil
+	return []string{"transfers"}
 }
 
 // MarshalToQueue -
 func (t *Transfer) MarshalToQueue() ([]byte, error) {
-	return nil, nil
+	return json.Marshal(QueueMessage{
+		ID:       t.ID,
+		Network:  t.Network,
+		Contract: t.Contract,
+		From:     t.From,
+		To:       t.To,
+	})
+}
+
+// QueueMessage - body of transfer message. Subscribers can filter messages by it without request to the storage.
+type QueueMessage struct {
+	ID       string `json:"id"`
+	Network  string `json:"network"`
+	Contract string `json:"contract"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+}
+
+// ParseQueueMessage - decodes body of transfer message. Body of previous format contains only ID.
+func ParseQueueMessage(body []byte) (QueueMessage, error) {
+	var msg QueueMessage
+	if !bytes.HasPrefix(body, []byte("{")) {
+		msg.ID = strings.Trim(string(body), `"`)
+		return msg, nil
+	}
+	err := json.Unmarshal(body, &msg)
+	return msg, err
 }
 
 // LogFields -
@@ -80,6 +109,7 @@ func EmptyTransfer(o operation.Operation) *Transfer {
 		Timestamp:    o.Timestamp,
 		Level:        o.Level,
 		Initiator:    o.Source,
+		TokenID:      "0",
 		AmountBigInt: big.NewInt(0),
 		Counter:      o.Counter,
 		Nonce:        o.Nonce,
@@ -89,7 +119,7 @@ func EmptyTransfer(o operation.Operation) *Transfer {
 // GetFromTokenBalanceID -
 func (t *Transfer) GetFromTokenBalanceID() string {
 	if t.From != "" {
-		return fmt.Sprintf("%s_%s_%s_%d", t.Network, t.From, t.Contract, t.TokenID)
+		return fmt.Sprintf("%s_%s_%s_%s", t.Network, t.From, t.Contract, t.TokenID)
 	}
 	return ""
 }
@@ -97,7 +127,7 @@ func (t *Transfer) GetFromTokenBalanceID() string {
 // GetToTokenBalanceID -
 func (t *Transfer) GetToTokenBalanceID() string {
 	if t.To != "" {
-		return fmt.Sprintf("%s_%s_%s_%d", t.Network, t.To, t.Contract, t.TokenID)
+		return fmt.Sprintf("%s_%s_%s_%s", t.Network, t.To, t.Contract, t.TokenID)
 	}
 	return ""
 }
@@ -130,7 +160,7 @@ func (t *Transfer) MakeTokenBalanceUpdate(from, rollback bool) *tokenbalance.Tok
 // TokenBalance -
 type TokenBalance struct {
 	Address string
-	TokenID int64
+	TokenID string
 }
 
 // TokenSupply -
@@ -149,9 +179,15 @@ type Pageable struct {
 // UnmarshalJSON -
 func (t *Transfer) UnmarshalJSON(data []byte) error {
 	type buf Transfer
-	if err := json.Unmarshal(data, (*buf)(t)); err != nil {
+	var b struct {
+		*buf
+		TokenID stdJSON.RawMessage `json:"token_id"`
+	}
+	b.buf = (*buf)(t)
+	if err := json.Unmarshal(data, &b); err != nil {
 		return err
 	}
+	t.TokenID = helpers.DecimalFromJSON(b.TokenID)
 	t.AmountBigInt = big.NewInt(0)
 
 	if _, ok := t.AmountBigInt.SetString(t.AmountStr, 10); !ok {
@@ -6,7 +6,7 @@ import "github.com/baking-bad/bcdhub/internal/models/tzip"
 type Repository interface {
 	Get(ctx GetContext) (Pageable, error)
 	GetAll(network string, level int64) ([]Transfer, error)
-	GetTokenSupply(network, address string, tokenID int64) (result TokenSupply, err error)
-	GetToken24HoursVolume(network, contract string, initiators, entrypoints []string, tokenID int64) (float64, error)
-	GetTokenVolumeSeries(network, period string, contracts []string, entrypoints []tzip.DAppContract, tokenID uint) ([][]float64, error)
+	GetTokenSupply(network, address string, tokenID string) (result TokenSupply, err error)
+	GetToken24HoursVolume(network, contract string, initiators, entrypoints []string, tokenID string) (float64, error)
+	GetTokenVolumeSeries(network, period string, contracts []string, entrypoints []tzip.DAppContract, tokenID string) ([][]float64, error)
 }
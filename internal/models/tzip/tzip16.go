@@ -4,6 +4,12 @@ import (
 	"encoding/json"
 )
 
+// View implementation kinds
+const (
+	ViewKindMichelsonStorage = "michelsonStorageView"
+	ViewKindRestAPIQuery     = "restApiQuery"
+)
+
 // TZIP16 -
 type TZIP16 struct {
 	Name        string   `json:"name,omitempty"`
@@ -14,6 +20,27 @@ type TZIP16 struct {
 	Authors     []string `json:"authors,omitempty"`
 	Interfaces  []string `json:"interfaces,omitempty"`
 	Views       []View   `json:"views,omitempty"`
+	Errors      []Error  `json:"errors,omitempty"`
+	Source      *Source  `json:"source,omitempty"`
+}
+
+// Source - tools which were used to produce contract code
+type Source struct {
+	Tools    []string `json:"tools,omitempty"`
+	Location string   `json:"location,omitempty"`
+}
+
+// Error - translation of contract error. Static translation is set by `Expansion`, dynamic one is computed by off-chain `View`.
+type Error struct {
+	Error     json.RawMessage `json:"error,omitempty"`
+	Expansion json.RawMessage `json:"expansion,omitempty"`
+	View      string          `json:"view,omitempty"`
+	Languages []string        `json:"languages,omitempty"`
+}
+
+// IsStatic -
+func (e Error) IsStatic() bool {
+	return !isNull(e.Error) && !isNull(e.Expansion)
 }
 
 // License -
@@ -52,5 +79,31 @@ type View struct {
 
 // ViewImplementation -
 type ViewImplementation struct {
-	MichelsonStorageView Sections `json:"michelsonStorageView"`
+	MichelsonStorageView Sections     `json:"michelsonStorageView"`
+	RestAPIQuery         RestAPIQuery `json:"restApiQuery"`
+}
+
+// Kind - returns kind of implementation or empty string if implementation is empty
+func (impl ViewImplementation) Kind() string {
+	switch {
+	case !impl.MichelsonStorageView.Empty():
+		return ViewKindMichelsonStorage
+	case !impl.RestAPIQuery.Empty():
+		return ViewKindRestAPIQuery
+	default:
+		return ""
+	}
+}
+
+// RestAPIQuery - view which is served by REST API described by OpenAPI specification
+type RestAPIQuery struct {
+	SpecificationURI string `json:"specificationUri,omitempty"`
+	BaseURI          string `json:"baseUri,omitempty"`
+	Path             string `json:"path,omitempty"`
+	Method           string `json:"method,omitempty"`
+}
+
+// Empty -
+func (q RestAPIQuery) Empty() bool {
+	return q.SpecificationURI == "" && q.Path == ""
 }
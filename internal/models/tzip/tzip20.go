@@ -52,12 +52,17 @@ var null = "null"
 
 // Empty -
 func (s Sections) Empty() bool {
-	return string(s.Code) == null && string(s.Parameter) == null && string(s.ReturnType) == null
+	return isNull(s.Code) && isNull(s.Parameter) && isNull(s.ReturnType)
 }
 
 // IsParameterEmpty -
 func (s Sections) IsParameterEmpty() bool {
-	return string(s.Parameter) == null
+	return isNull(s.Parameter)
+}
+
+// isNull - returns true if section is omitted or is `null`
+func isNull(section json.RawMessage) bool {
+	return len(section) == 0 || string(section) == null
 }
 
 // MichelsonInitialStorageEvent -
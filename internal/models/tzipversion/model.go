@@ -0,0 +1,66 @@
+package tzipversion
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/models/tzip"
+	"github.com/sirupsen/logrus"
+)
+
+// Statuses of metadata version
+const (
+	StatusFetched = "fetched"
+	StatusPending = "pending"
+	StatusFailed  = "failed"
+)
+
+// Version - metadata of contract which was set by update of the empty key of `%metadata` big map.
+// If metadata can't be fetched version is pending until fetching is retried successfully or attempts are exhausted.
+// If URI refers to another big map key (`tezos-storage:`), the key is kept in `RefAddress` and `RefKeyHash` and its update creates new version.
+type Version struct {
+	Network       string     `json:"network"`
+	Address       string     `json:"address"`
+	Level         int64      `json:"level"`
+	Timestamp     time.Time  `json:"timestamp"`
+	Ptr           int64      `json:"ptr"`
+	URI           string     `json:"uri"`
+	RefAddress    string     `json:"ref_address,omitempty"`
+	RefKeyHash    string     `json:"ref_key_hash,omitempty"`
+	Status        string     `json:"status"`
+	Attempts      int64      `json:"attempts"`
+	NextAttemptAt int64      `json:"next_attempt_at,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	Metadata      *tzip.TZIP `json:"metadata,omitempty"`
+}
+
+// GetID -
+func (v *Version) GetID() string {
+	return fmt.Sprintf("%s_%s_%d", v.Network, v.Address, v.Level)
+}
+
+// GetIndex -
+func (v *Version) GetIndex() string {
+	return "tzip_version"
+}
+
+// GetQueues -
+func (v *Version) GetQueues() []string {
+	return nil
+}
+
+// MarshalToQueue -
+func (v *Version) MarshalToQueue() ([]byte, error) {
+	return nil, nil
+}
+
+// LogFields -
+func (v *Version) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"network": v.Network,
+		"address": v.Address,
+		"level":   v.Level,
+		"uri":     v.URI,
+		"status":  v.Status,
+	}
+}
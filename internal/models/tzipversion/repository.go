@@ -0,0 +1,14 @@
+package tzipversion
+
+// Repository -
+type Repository interface {
+	Get(network, address string, size, offset int64) ([]Version, error)
+	GetLast(network, address string) (Version, error)
+	GetPending(before, size int64) ([]Version, error)
+	// GetAfter - returns versions of `network` created after `level`
+	GetAfter(network string, level int64) ([]Version, error)
+	// GetLastBefore - returns the newest fetched version of contract created not later than `level`
+	GetLastBefore(network, address string, level int64) (Version, error)
+	// GetReferenced - returns versions which URI refers to big map key
+	GetReferenced() ([]Version, error)
+}
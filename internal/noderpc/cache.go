@@ -0,0 +1,430 @@
+package noderpc
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	defaultCacheSize     = 1 << 30
+	defaultCacheFinality = 30
+	cacheHeadTTL         = time.Minute
+	cacheOrphanTTL       = time.Minute
+
+	cacheKeysDir   = "keys"
+	cacheValuesDir = "values"
+)
+
+// CacheStats - statistics of cached node
+type CacheStats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+	Size    int64  `json:"size"`
+}
+
+// CacheOption -
+type CacheOption func(*CachedNode)
+
+// WithCacheSize - sets max size of cached values in bytes
+func WithCacheSize(size int64) CacheOption {
+	return func(c *CachedNode) {
+		if size > 0 {
+			c.maxSize = size
+		}
+	}
+}
+
+// WithCacheFinality - lookups of levels which are less than `finality` blocks behind the head are not cached because they can be rolled back
+func WithCacheFinality(finality int64) CacheOption {
+	return func(c *CachedNode) {
+		if finality > 0 {
+			c.finality = finality
+		}
+	}
+}
+
+// CachedNode - decorator of `INode` which stores scripts and storages of contracts at historical levels in on-disk LRU cache.
+// Values are content-addressed, so equal responses (e.g. script of contract at different levels) are stored once.
+// Directory can be shared by several processes: size limit is applied by every process to entries known by it.
+type CachedNode struct {
+	INode
+
+	dir      string
+	maxSize  int64
+	finality int64
+
+	mux     sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+	values  map[string]*cacheValue
+	size    int64
+
+	headMux  sync.Mutex
+	head     int64
+	headTime time.Time
+
+	hits   uint64
+	misses uint64
+}
+
+type cacheEntry struct {
+	key   string
+	value string
+}
+
+type cacheValue struct {
+	refs int
+	size int64
+}
+
+// NewCachedNode - creates cached decorator of `node` which stores values in `dir`
+func NewCachedNode(node INode, dir string, opts ...CacheOption) (*CachedNode, error) {
+	c := &CachedNode{
+		INode:    node,
+		dir:      dir,
+		maxSize:  defaultCacheSize,
+		finality: defaultCacheFinality,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+		values:   make(map[string]*cacheValue),
+	}
+
+	for i := range opts {
+		opts[i](c)
+	}
+
+	for _, sub := range []string{cacheKeysDir, cacheValuesDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), os.ModePerm); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close - closes decorated node if it has to be closed
+func (c *CachedNode) Close() {
+	if closer, ok := c.INode.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+// Stats - returns cache statistics
+func (c *CachedNode) Stats() CacheStats {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	return CacheStats{
+		Hits:    atomic.LoadUint64(&c.hits),
+		Misses:  atomic.LoadUint64(&c.misses),
+		Entries: len(c.entries),
+		Size:    c.size,
+	}
+}
+
+// LogStats - logs cache statistics of `network` every `period` until `stop` is closed
+func (c *CachedNode) LogStats(network string, period time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			stats := c.Stats()
+			logger.WithNetwork(network).Infof("RPC cache: %d hits, %d misses, %d entries, %d bytes", stats.Hits, stats.Misses, stats.Entries, stats.Size)
+		}
+	}
+}
+
+// GetHead -
+func (c *CachedNode) GetHead() (Header, error) {
+	head, err := c.INode.GetHead()
+	if err == nil {
+		c.setHead(head.Level)
+	}
+	return head, err
+}
+
+// GetScriptJSON -
+func (c *CachedNode) GetScriptJSON(address string, level int64) (gjson.Result, error) {
+	return c.cached("script", address, level, func() (gjson.Result, error) {
+		return c.INode.GetScriptJSON(address, level)
+	})
+}
+
+// GetScriptStorageJSON -
+func (c *CachedNode) GetScriptStorageJSON(address string, level int64) (gjson.Result, error) {
+	return c.cached("storage", address, level, func() (gjson.Result, error) {
+		return c.INode.GetScriptStorageJSON(address, level)
+	})
+}
+
+// GetCode -
+func (c *CachedNode) GetCode(address string, level int64) (gjson.Result, error) {
+	return c.cached("code", address, level, func() (gjson.Result, error) {
+		return c.INode.GetCode(address, level)
+	})
+}
+
+func (c *CachedNode) cached(method, address string, level int64, fetch func() (gjson.Result, error)) (gjson.Result, error) {
+	if !c.isFinal(level) {
+		return fetch()
+	}
+
+	key := hashString(fmt.Sprintf("%s:%s:%d", method, address, level))
+	if data, ok := c.get(key); ok {
+		atomic.AddUint64(&c.hits, 1)
+		return gjson.ParseBytes(data), nil
+	}
+	atomic.AddUint64(&c.misses, 1)
+
+	result, err := fetch()
+	if err != nil {
+		return result, err
+	}
+	if err := c.put(key, []byte(result.Raw)); err != nil {
+		logger.Warning("[CachedNode] can't cache %s of %s at %d: %s", method, address, level, err)
+	}
+	return result, nil
+}
+
+// isFinal - returns true if `level` is far enough behind the head
+func (c *CachedNode) isFinal(level int64) bool {
+	if level <= 0 {
+		return false
+	}
+
+	c.headMux.Lock()
+	head, headTime := c.head, c.headTime
+	c.headMux.Unlock()
+
+	if level <= head-c.finality {
+		return true
+	}
+	if time.Since(headTime) < cacheHeadTTL {
+		return false
+	}
+
+	head, err := c.INode.GetLevel()
+	if err != nil {
+		return false
+	}
+	c.setHead(head)
+	return level <= head-c.finality
+}
+
+func (c *CachedNode) setHead(level int64) {
+	c.headMux.Lock()
+	if level >= c.head {
+		c.head = level
+		c.headTime = time.Now()
+	}
+	c.headMux.Unlock()
+}
+
+func (c *CachedNode) get(key string) ([]byte, bool) {
+	c.mux.Lock()
+	el, ok := c.entries[key]
+	if ok {
+		c.lru.MoveToFront(el)
+	}
+	c.mux.Unlock()
+
+	var value string
+	if ok {
+		value = el.Value.(*cacheEntry).value
+	} else {
+		// entry can be written by another process
+		data, err := ioutil.ReadFile(c.keyPath(key))
+		if err != nil {
+			return nil, false
+		}
+		value = string(data)
+	}
+
+	data, err := ioutil.ReadFile(c.valuePath(value))
+	if err != nil {
+		c.mux.Lock()
+		c.remove(key)
+		c.mux.Unlock()
+		os.Remove(c.keyPath(key))
+		return nil, false
+	}
+
+	if !ok {
+		c.mux.Lock()
+		c.add(key, value, int64(len(data)))
+		c.evict()
+		c.mux.Unlock()
+	}
+	return data, true
+}
+
+func (c *CachedNode) put(key string, data []byte) error {
+	value := hashString(string(data))
+	if _, err := os.Stat(c.valuePath(value)); os.IsNotExist(err) {
+		if err := writeFileAtomic(c.valuePath(value), data); err != nil {
+			return err
+		}
+	}
+	if err := writeFileAtomic(c.keyPath(key), []byte(value)); err != nil {
+		return err
+	}
+
+	c.mux.Lock()
+	c.add(key, value, int64(len(data)))
+	c.evict()
+	c.mux.Unlock()
+	return nil
+}
+
+// add - adds entry to LRU. Must be called under lock.
+func (c *CachedNode) add(key, value string, size int64) {
+	if el, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key, value})
+
+	if v, ok := c.values[value]; ok {
+		v.refs++
+		return
+	}
+	c.values[value] = &cacheValue{refs: 1, size: size}
+	c.size += size
+}
+
+// remove - removes entry from LRU and returns true if its value is not referenced anymore. Must be called under lock.
+func (c *CachedNode) remove(key string) (string, bool) {
+	el, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	entry := c.lru.Remove(el).(*cacheEntry)
+	delete(c.entries, key)
+
+	v, ok := c.values[entry.value]
+	if !ok {
+		return entry.value, false
+	}
+	v.refs--
+	if v.refs > 0 {
+		return entry.value, false
+	}
+	delete(c.values, entry.value)
+	c.size -= v.size
+	return entry.value, true
+}
+
+// evict - removes least recently used entries while cache is oversized. Must be called under lock.
+func (c *CachedNode) evict() {
+	for c.size > c.maxSize && c.lru.Len() > 1 {
+		key := c.lru.Back().Value.(*cacheEntry).key
+		value, unused := c.remove(key)
+		os.Remove(c.keyPath(key))
+		if unused {
+			os.Remove(c.valuePath(value))
+		}
+	}
+}
+
+// load - restores LRU from cache directory. Values without keys are removed.
+func (c *CachedNode) load() error {
+	keys, err := ioutil.ReadDir(filepath.Join(c.dir, cacheKeysDir))
+	if err != nil {
+		return err
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ModTime().Before(keys[j].ModTime())
+	})
+
+	for i := range keys {
+		key := keys[i].Name()
+		if strings.HasPrefix(key, ".") {
+			continue
+		}
+		data, err := ioutil.ReadFile(c.keyPath(key))
+		if err != nil {
+			return err
+		}
+		value := string(data)
+		info, err := os.Stat(c.valuePath(value))
+		if err != nil {
+			os.Remove(c.keyPath(key))
+			continue
+		}
+		c.add(key, value, info.Size())
+	}
+
+	values, err := ioutil.ReadDir(filepath.Join(c.dir, cacheValuesDir))
+	if err != nil {
+		return err
+	}
+	for i := range values {
+		if _, ok := c.values[values[i].Name()]; ok {
+			continue
+		}
+		// value can be just written by another process
+		if time.Since(values[i].ModTime()) > cacheOrphanTTL {
+			os.Remove(c.valuePath(values[i].Name()))
+		}
+	}
+
+	c.evict()
+	return nil
+}
+
+func (c *CachedNode) keyPath(key string) string {
+	return filepath.Join(c.dir, cacheKeysDir, key)
+}
+
+func (c *CachedNode) valuePath(value string) string {
+	return filepath.Join(c.dir, cacheValuesDir, value)
+}
+
+func hashString(s string) string {
+	hash := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(hash[:])
+}
+
+// writeFileAtomic - writes file through temporary file to prevent reading of partially written file by another process
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrap(err, "writeFileAtomic")
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package noderpc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/tidwall/gjson"
+)
+
+func TestCachedNode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "rpc-cache")
+	if err != nil {
+		t.Errorf("TempDir() error = %v", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	const (
+		address = "KT1BvVxWM6cjFuJNet4R9m64VDCN2iMvjuGE"
+		script  = `{"code":[{"prim":"parameter","args":[{"prim":"unit"}]}]}`
+	)
+
+	node := NewMockINode(ctrl)
+	node.EXPECT().GetLevel().Return(int64(1000), nil).AnyTimes()
+	node.EXPECT().GetScriptJSON(address, int64(100)).Return(gjson.Parse(script), nil).Times(1)
+	node.EXPECT().GetScriptJSON(address, int64(101)).Return(gjson.Parse(script), nil).Times(1)
+	node.EXPECT().GetScriptJSON(address, int64(990)).Return(gjson.Parse(script), nil).Times(2)
+
+	cached, err := NewCachedNode(node, dir)
+	if err != nil {
+		t.Errorf("NewCachedNode() error = %v", err)
+		return
+	}
+
+	for _, level := range []int64{100, 100, 101, 101, 990, 990} {
+		result, err := cached.GetScriptJSON(address, level)
+		if err != nil {
+			t.Errorf("GetScriptJSON() error = %v", err)
+			return
+		}
+		if result.Raw != script {
+			t.Errorf("GetScriptJSON() = %s, want %s", result.Raw, script)
+			return
+		}
+	}
+
+	want := CacheStats{Hits: 2, Misses: 2, Entries: 2, Size: int64(len(script))}
+	if stats := cached.Stats(); stats != want {
+		t.Errorf("Stats() = %+v, want %+v", stats, want)
+	}
+
+	values, err := ioutil.ReadDir(filepath.Join(dir, cacheValuesDir))
+	if err != nil {
+		t.Errorf("ReadDir() error = %v", err)
+		return
+	}
+	if len(values) != 1 {
+		t.Errorf("values count = %d, want 1", len(values))
+	}
+
+	restored, err := NewCachedNode(node, dir)
+	if err != nil {
+		t.Errorf("NewCachedNode() error = %v", err)
+		return
+	}
+	if _, err := restored.GetScriptJSON(address, 100); err != nil {
+		t.Errorf("GetScriptJSON() error = %v", err)
+		return
+	}
+	if stats := restored.Stats(); stats.Hits != 1 || stats.Entries != 2 {
+		t.Errorf("restored Stats() = %+v", stats)
+	}
+}
+
+func TestCachedNode_evict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "rpc-cache")
+	if err != nil {
+		t.Errorf("TempDir() error = %v", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	node := NewMockINode(ctrl)
+	node.EXPECT().GetLevel().Return(int64(1000), nil).AnyTimes()
+	node.EXPECT().GetCode("KT1a", int64(1)).Return(gjson.Parse(`{"a":1}`), nil).Times(2)
+	node.EXPECT().GetCode("KT1b", int64(1)).Return(gjson.Parse(`{"b":2}`), nil).Times(1)
+
+	cached, err := NewCachedNode(node, dir, WithCacheSize(10))
+	if err != nil {
+		t.Errorf("NewCachedNode() error = %v", err)
+		return
+	}
+
+	for _, address := range []string{"KT1a", "KT1b", "KT1b", "KT1a"} {
+		if _, err := cached.GetCode(address, 1); err != nil {
+			t.Errorf("GetCode() error = %v", err)
+			return
+		}
+	}
+
+	if stats := cached.Stats(); stats.Entries != 1 || stats.Size != 7 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
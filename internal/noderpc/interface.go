@@ -1,6 +1,7 @@
 package noderpc
 
 import (
+	"context"
 	"time"
 
 	"github.com/tidwall/gjson"
@@ -10,6 +11,7 @@ import (
 type INode interface {
 	GetHead() (Header, error)
 	GetHeader(int64) (Header, error)
+	GetHeaderWithContext(context.Context, int64) (Header, error)
 	GetLevel() (int64, error)
 	GetLevelTime(int) (time.Time, error)
 	GetScriptJSON(string, int64) (gjson.Result, error)
@@ -17,10 +19,13 @@ type INode interface {
 	GetContractBalance(string, int64) (int64, error)
 	GetContractData(string, int64) (ContractData, error)
 	GetOperations(int64) (gjson.Result, error)
+	GetOperationsWithContext(context.Context, int64) (gjson.Result, error)
 	GetContractsByBlock(int64) ([]string, error)
 	GetNetworkConstants(int64) (Constants, error)
 	RunCode(gjson.Result, gjson.Result, gjson.Result, string, string, string, string, string, int64, int64) (gjson.Result, error)
 	RunOperation(string, string, string, string, int64, int64, int64, int64, int64, gjson.Result) (gjson.Result, error)
 	GetCounter(string) (int64, error)
 	GetCode(address string, level int64) (gjson.Result, error)
+	GetBigMapValues(ptr, level, offset, length int64) (gjson.Result, error)
+	MonitorHeads(stop <-chan struct{}) (<-chan Header, error)
 }
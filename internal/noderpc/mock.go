@@ -5,6 +5,7 @@
 package noderpc
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	gjson "github.com/tidwall/gjson"
 	reflect "reflect"
@@ -64,6 +65,21 @@ func (mr *MockINodeMockRecorder) GetHeader(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeader", reflect.TypeOf((*MockINode)(nil).GetHeader), arg0)
 }
 
+// GetHeaderWithContext mocks base method
+func (m *MockINode) GetHeaderWithContext(arg0 context.Context, arg1 int64) (Header, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeaderWithContext", arg0, arg1)
+	ret0, _ := ret[0].(Header)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeaderWithContext indicates an expected call of GetHeaderWithContext
+func (mr *MockINodeMockRecorder) GetHeaderWithContext(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeaderWithContext", reflect.TypeOf((*MockINode)(nil).GetHeaderWithContext), arg0, arg1)
+}
+
 // GetLevel mocks base method
 func (m *MockINode) GetLevel() (int64, error) {
 	m.ctrl.T.Helper()
@@ -169,6 +185,21 @@ func (mr *MockINodeMockRecorder) GetOperations(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperations", reflect.TypeOf((*MockINode)(nil).GetOperations), arg0)
 }
 
+// GetOperationsWithContext mocks base method
+func (m *MockINode) GetOperationsWithContext(arg0 context.Context, arg1 int64) (gjson.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOperationsWithContext", arg0, arg1)
+	ret0, _ := ret[0].(gjson.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOperationsWithContext indicates an expected call of GetOperationsWithContext
+func (mr *MockINodeMockRecorder) GetOperationsWithContext(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperationsWithContext", reflect.TypeOf((*MockINode)(nil).GetOperationsWithContext), arg0, arg1)
+}
+
 // GetContractsByBlock mocks base method
 func (m *MockINode) GetContractsByBlock(arg0 int64) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -258,3 +289,33 @@ func (mr *MockINodeMockRecorder) GetCode(address, level interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCode", reflect.TypeOf((*MockINode)(nil).GetCode), address, level)
 }
+
+// GetBigMapValues mocks base method
+func (m *MockINode) GetBigMapValues(ptr, level, offset, length int64) (gjson.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBigMapValues", ptr, level, offset, length)
+	ret0, _ := ret[0].(gjson.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBigMapValues indicates an expected call of GetBigMapValues
+func (mr *MockINodeMockRecorder) GetBigMapValues(ptr, level, offset, length interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBigMapValues", reflect.TypeOf((*MockINode)(nil).GetBigMapValues), ptr, level, offset, length)
+}
+
+// MonitorHeads mocks base method
+func (m *MockINode) MonitorHeads(stop <-chan struct{}) (<-chan Header, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MonitorHeads", stop)
+	ret0, _ := ret[0].(<-chan Header)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MonitorHeads indicates an expected call of MonitorHeads
+func (mr *MockINodeMockRecorder) MonitorHeads(stop interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MonitorHeads", reflect.TypeOf((*MockINode)(nil).MonitorHeads), stop)
+}
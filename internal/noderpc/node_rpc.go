@@ -2,8 +2,10 @@ package noderpc
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"strconv"
 	"time"
@@ -113,6 +115,9 @@ func (rpc *NodeRPC) makeRequest(req *http.Request) (*http.Response, error) {
 	for ; count < rpc.retryCount; count++ {
 		resp, err := client.Do(req)
 		if err != nil {
+			if ctxErr := req.Context().Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
 			logger.Warning("Attempt #%d: %s", count+1, err.Error())
 			continue
 		}
@@ -122,9 +127,9 @@ func (rpc *NodeRPC) makeRequest(req *http.Request) (*http.Response, error) {
 	return nil, NewMaxRetryExceededError(rpc.baseURL)
 }
 
-func (rpc *NodeRPC) makeGetRequest(uri string) (*http.Response, error) {
-	url := helpers.URLJoin(rpc.baseURL, uri)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+func (rpc *NodeRPC) makeGetRequest(ctx context.Context, uri string, params map[string]string) (*http.Response, error) {
+	url := helpers.URLJoinWithQuery(rpc.baseURL, uri, params)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, errors.Errorf("makeGetRequest.NewRequest: %v", err)
 	}
@@ -146,9 +151,14 @@ func (rpc *NodeRPC) makePostRequest(uri string, data map[string]interface{}) (*h
 	return rpc.makeRequest(req)
 }
 
-//nolint
+// nolint
 func (rpc *NodeRPC) get(uri string, response interface{}) error {
-	resp, err := rpc.makeGetRequest(uri)
+	return rpc.getWithContext(context.Background(), uri, response)
+}
+
+// nolint
+func (rpc *NodeRPC) getWithContext(ctx context.Context, uri string, response interface{}) error {
+	resp, err := rpc.makeGetRequest(ctx, uri, nil)
 	if err != nil {
 		return err
 	}
@@ -157,9 +167,19 @@ func (rpc *NodeRPC) get(uri string, response interface{}) error {
 	return rpc.parseResponse(resp, true, response)
 }
 
-//nolint
+// nolint
 func (rpc *NodeRPC) getGJSON(uri string) (gjson.Result, error) {
-	resp, err := rpc.makeGetRequest(uri)
+	return rpc.getGJSONWithContext(context.Background(), uri)
+}
+
+// nolint
+func (rpc *NodeRPC) getGJSONWithContext(ctx context.Context, uri string) (gjson.Result, error) {
+	return rpc.getGJSONWithQuery(ctx, uri, nil)
+}
+
+// nolint
+func (rpc *NodeRPC) getGJSONWithQuery(ctx context.Context, uri string, params map[string]string) (gjson.Result, error) {
+	resp, err := rpc.makeGetRequest(ctx, uri, params)
 	if err != nil {
 		return gjson.Result{}, err
 	}
@@ -168,7 +188,7 @@ func (rpc *NodeRPC) getGJSON(uri string) (gjson.Result, error) {
 	return rpc.getGJSONReponse(resp, true)
 }
 
-//nolint
+// nolint
 func (rpc *NodeRPC) post(uri string, data map[string]interface{}, checkStatusCode bool, response interface{}) error {
 	resp, err := rpc.makePostRequest(uri, data)
 	if err != nil {
@@ -179,7 +199,7 @@ func (rpc *NodeRPC) post(uri string, data map[string]interface{}, checkStatusCod
 	return rpc.parseResponse(resp, checkStatusCode, response)
 }
 
-//nolint
+// nolint
 func (rpc *NodeRPC) postGJSON(uri string, data map[string]interface{}, checkStatusCode bool) (gjson.Result, error) {
 	resp, err := rpc.makePostRequest(uri, data)
 	if err != nil {
@@ -207,8 +227,13 @@ func (rpc *NodeRPC) GetLevel() (int64, error) {
 }
 
 // GetHeader - get head for certain level
-func (rpc *NodeRPC) GetHeader(level int64) (header Header, err error) {
-	err = rpc.get(fmt.Sprintf("chains/main/blocks/%s/header", getBlockString(level)), &header)
+func (rpc *NodeRPC) GetHeader(level int64) (Header, error) {
+	return rpc.GetHeaderWithContext(context.Background(), level)
+}
+
+// GetHeaderWithContext - get head for certain level. Request is cancelled when `ctx` is done.
+func (rpc *NodeRPC) GetHeaderWithContext(ctx context.Context, level int64) (header Header, err error) {
+	err = rpc.getWithContext(ctx, fmt.Sprintf("chains/main/blocks/%s/header", getBlockString(level)), &header)
 	return
 }
 
@@ -272,7 +297,12 @@ func (rpc *NodeRPC) GetContractData(address string, level int64) (ContractData,
 
 // GetOperations -
 func (rpc *NodeRPC) GetOperations(block int64) (gjson.Result, error) {
-	return rpc.getGJSON(fmt.Sprintf("chains/main/blocks/%d/operations/3", block))
+	return rpc.GetOperationsWithContext(context.Background(), block)
+}
+
+// GetOperationsWithContext - get operations of block. Request is cancelled when `ctx` is done.
+func (rpc *NodeRPC) GetOperationsWithContext(ctx context.Context, block int64) (gjson.Result, error) {
+	return rpc.getGJSONWithContext(ctx, fmt.Sprintf("chains/main/blocks/%d/operations/3", block))
 }
 
 // GetContractsByBlock -
@@ -366,3 +396,76 @@ func (rpc *NodeRPC) GetCode(address string, level int64) (gjson.Result, error) {
 
 	return contract.Get("code"), nil
 }
+
+// GetBigMapValues - returns `length` live values of big map `ptr` starting from `offset`
+func (rpc *NodeRPC) GetBigMapValues(ptr, level, offset, length int64) (gjson.Result, error) {
+	return rpc.getGJSONWithQuery(context.Background(), fmt.Sprintf("chains/main/blocks/%s/context/big_maps/%d", getBlockString(level), ptr), map[string]string{
+		"offset": fmt.Sprintf("%d", offset),
+		"length": fmt.Sprintf("%d", length),
+	})
+}
+
+// MonitorHeads - streams heads of main chain from `monitor/heads/main`. Returned channel is closed when stream is dropped or `stop` is closed.
+func (rpc *NodeRPC) MonitorHeads(stop <-chan struct{}) (<-chan Header, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	url := helpers.URLJoin(rpc.baseURL, "monitor/heads/main")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		cancel()
+		return nil, errors.Errorf("MonitorHeads.NewRequest: %v", err)
+	}
+
+	// stream has no deadline, so timeout is applied to connection and response headers only
+	client := http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout: rpc.timeout,
+			}).DialContext,
+			ResponseHeaderTimeout: rpc.timeout,
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, NewMaxRetryExceededError(rpc.baseURL)
+	}
+	if err := rpc.checkStatusCode(resp, true); err != nil {
+		resp.Body.Close()
+		cancel()
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	heads := make(chan Header)
+	go func() {
+		defer close(heads)
+		defer cancel()
+		defer resp.Body.Close()
+
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var head Header
+			if err := decoder.Decode(&head); err != nil {
+				if ctx.Err() == nil {
+					logger.Warning("%s: heads stream is dropped: %s", rpc.baseURL, err)
+				}
+				return
+			}
+
+			select {
+			case heads <- head:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return heads, nil
+}
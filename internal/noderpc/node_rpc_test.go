@@ -0,0 +1,116 @@
+package noderpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNodeRPC_MonitorHeads(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/monitor/heads/main" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		flusher := w.(http.Flusher)
+		for level := 100; level < 102; level++ {
+			fmt.Fprintf(w, `{"hash":"BL%d","level":%d,"proto":8,"predecessor":"BL%d","timestamp":"2021-01-01T00:00:00Z"}`+"\n", level, level, level-1)
+			flusher.Flush()
+		}
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	stop := make(chan struct{})
+	heads, err := NewNodeRPC(server.URL).MonitorHeads(stop)
+	if err != nil {
+		t.Errorf("MonitorHeads() error = %v", err)
+		return
+	}
+
+	for level := int64(100); level < 102; level++ {
+		select {
+		case head := <-heads:
+			if head.Level != level || head.Hash != fmt.Sprintf("BL%d", level) {
+				t.Errorf("head = %+v, want level %d", head, level)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("head %d was not received", level)
+			return
+		}
+	}
+
+	close(stop)
+	select {
+	case _, ok := <-heads:
+		if ok {
+			t.Errorf("heads channel is not closed after stop")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("heads channel is not closed after stop")
+	}
+}
+
+func TestNodeRPC_MonitorHeads_dropped(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"hash":"BL100","level":100}`)
+	}))
+	defer server.Close()
+
+	heads, err := NewNodeRPC(server.URL).MonitorHeads(make(chan struct{}))
+	if err != nil {
+		t.Errorf("MonitorHeads() error = %v", err)
+		return
+	}
+
+	var count int
+	for range heads {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("received %d heads, want 1", count)
+	}
+}
+
+func TestNodeRPC_GetHeaderWithContext_cancelled(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := NewNodeRPC(server.URL).GetHeaderWithContext(ctx, 100)
+		errs <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errs:
+		if err != context.Canceled {
+			t.Errorf("GetHeaderWithContext() error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("GetHeaderWithContext() is not cancelled")
+		return
+	}
+	if count := atomic.LoadInt32(&requests); count != 1 {
+		t.Errorf("cancelled request is retried: %d requests", count)
+	}
+}
@@ -1,89 +1,338 @@
 package noderpc
 
 import (
-	"math/rand"
+	"context"
 	"reflect"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
 )
 
-// Pool - node pool
-type Pool []*poolItem
+const (
+	blockDuration      = 5 * time.Minute
+	defaultMaxLag      = 2
+	defaultCheckPeriod = 15 * time.Second
+	// weight of the last response time in average latency of node
+	latencyWeight = 0.3
+)
+
+// methods which are duplicated to the second node if the first one is slow
+var hedgedMethods = map[string]struct{}{
+	"GetOperations":            {},
+	"GetOperationsWithContext": {},
+	"GetScriptJSON":            {},
+}
+
+// Pool - node pool. Requests are sent to the fastest available node which head is not lagging behind the highest known head.
+type Pool struct {
+	nodes []*poolItem
+
+	maxLag      int64
+	checkPeriod time.Duration
+	hedgeDelay  time.Duration
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// PoolOption -
+type PoolOption func(*Pool)
+
+// WithMaxLag - nodes which head is more than `maxLag` blocks behind the highest head are skipped
+func WithMaxLag(maxLag int64) PoolOption {
+	return func(p *Pool) {
+		if maxLag > 0 {
+			p.maxLag = maxLag
+		}
+	}
+}
+
+// WithHeadCheckPeriod - sets how often heads of nodes are requested in background
+func WithHeadCheckPeriod(period time.Duration) PoolOption {
+	return func(p *Pool) {
+		if period > 0 {
+			p.checkPeriod = period
+		}
+	}
+}
+
+// WithHedging - if node doesn't respond to `GetOperations` or `GetScriptJSON` during `delay` the request is sent to the next node too.
+// The first successful response is returned.
+func WithHedging(delay time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.hedgeDelay = delay
+	}
+}
+
+// NodeStatus - state of node in pool
+type NodeStatus struct {
+	URL       string
+	Level     int64
+	Protocol  string
+	Latency   time.Duration
+	Available bool
+}
 
 type poolItem struct {
-	node      *NodeRPC
+	node *NodeRPC
+
+	mux       sync.RWMutex
 	blockTime time.Time
+	level     int64
+	protocol  string
+	latency   time.Duration
 }
 
-func newPoolItem(url string, opts ...NodeOption) *poolItem {
+func newPoolItem(node *NodeRPC) *poolItem {
 	return &poolItem{
-		node:      NewNodeRPC(url, opts...),
+		node:      node,
 		blockTime: time.Now(),
 	}
 }
 
-func newWaitPoolItem(url string, opts ...NodeOption) *poolItem {
-	return &poolItem{
-		node:      NewWaitNodeRPC(url, opts...),
-		blockTime: time.Now(),
+func (p *poolItem) block() {
+	p.mux.Lock()
+	p.blockTime = time.Now().Add(blockDuration)
+	p.mux.Unlock()
+}
+
+func (p *poolItem) setHead(level int64, protocol string) {
+	p.mux.Lock()
+	p.level = level
+	if protocol != "" {
+		p.protocol = protocol
 	}
+	p.mux.Unlock()
 }
 
-func (p *poolItem) block() {
-	p.blockTime = time.Now().Add(time.Minute * 5)
+func (p *poolItem) addLatency(duration time.Duration) {
+	p.mux.Lock()
+	if p.latency == 0 {
+		p.latency = duration
+	} else {
+		p.latency = time.Duration(latencyWeight*float64(duration) + (1-latencyWeight)*float64(p.latency))
+	}
+	p.mux.Unlock()
 }
 
-func (p *poolItem) isBlocked() bool {
-	return time.Now().After(p.blockTime)
+func (p *poolItem) status() NodeStatus {
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+	return NodeStatus{
+		URL:       p.node.baseURL,
+		Level:     p.level,
+		Protocol:  p.protocol,
+		Latency:   p.latency,
+		Available: time.Now().After(p.blockTime),
+	}
 }
 
 // NewPool - creates `Pool` struct by `urls`
-func NewPool(urls []string, opts ...NodeOption) Pool {
-	pool := make(Pool, len(urls))
+func NewPool(urls []string, nodeOpts []NodeOption, opts ...PoolOption) *Pool {
+	nodes := make([]*NodeRPC, len(urls))
 	for i := range urls {
-		pool[i] = newPoolItem(urls[i], opts...)
+		nodes[i] = NewNodeRPC(urls[i], nodeOpts...)
 	}
-	return pool
+	return newPool(nodes, opts...)
 }
 
 // NewWaitPool -
-func NewWaitPool(urls []string, opts ...NodeOption) Pool {
-	pool := make(Pool, len(urls))
+func NewWaitPool(urls []string, nodeOpts []NodeOption, opts ...PoolOption) *Pool {
+	nodes := make([]*NodeRPC, len(urls))
 	for i := range urls {
-		pool[i] = newWaitPoolItem(urls[i], opts...)
+		nodes[i] = NewWaitNodeRPC(urls[i], nodeOpts...)
+	}
+	return newPool(nodes, opts...)
+}
+
+func newPool(nodes []*NodeRPC, opts ...PoolOption) *Pool {
+	pool := &Pool{
+		nodes:       make([]*poolItem, len(nodes)),
+		maxLag:      defaultMaxLag,
+		checkPeriod: defaultCheckPeriod,
+		stop:        make(chan struct{}),
+	}
+	for i := range nodes {
+		pool.nodes[i] = newPoolItem(nodes[i])
+	}
+	for i := range opts {
+		opts[i](pool)
+	}
+
+	if len(pool.nodes) > 1 {
+		pool.checkHeads()
+		go pool.watchHeads()
 	}
 	return pool
 }
 
-func (p Pool) getNode() (*poolItem, error) {
-	rand.Seed(time.Now().UnixNano())
-	nodes := make([]*poolItem, 0)
-	for i := range p {
-		if p[i].isBlocked() {
-			nodes = append(nodes, p[i])
+// Close - stops background requests of node heads
+func (p *Pool) Close() {
+	p.once.Do(func() {
+		close(p.stop)
+	})
+}
+
+// Status - returns state of pool nodes
+func (p *Pool) Status() []NodeStatus {
+	statuses := make([]NodeStatus, len(p.nodes))
+	for i := range p.nodes {
+		statuses[i] = p.nodes[i].status()
+	}
+	return statuses
+}
+
+// watchHeads - requests heads of nodes every check period until pool is closed
+func (p *Pool) watchHeads() {
+	ticker := time.NewTicker(p.checkPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.checkHeads()
+		}
+	}
+}
+
+// checkHeads - requests heads of all available nodes
+func (p *Pool) checkHeads() {
+	var wg sync.WaitGroup
+	for i := range p.nodes {
+		if !p.nodes[i].status().Available {
+			continue
+		}
+		wg.Add(1)
+		go func(item *poolItem) {
+			defer wg.Done()
+			if _, err := p.callNode(item, "GetHead"); err != nil && !IsNodeUnavailiableError(err) {
+				item.setHead(0, "")
+			}
+		}(p.nodes[i])
+	}
+	wg.Wait()
+}
+
+// getNodes - returns available nodes which are not lagging behind the highest head. Heads are taken from the state refreshed in background.
+// Nodes are ordered by latency or by head level if `freshest` is true. Nodes without measured latency go first to be measured.
+func (p *Pool) getNodes(freshest bool) ([]*poolItem, error) {
+	var maxLevel int64
+	statuses := make([]NodeStatus, len(p.nodes))
+	for i := range p.nodes {
+		statuses[i] = p.nodes[i].status()
+		if statuses[i].Available && statuses[i].Level > maxLevel {
+			maxLevel = statuses[i].Level
 		}
 	}
 
-	if len(nodes) == 0 {
+	type candidate struct {
+		item   *poolItem
+		status NodeStatus
+	}
+	candidates := make([]candidate, 0, len(p.nodes))
+	for i := range p.nodes {
+		if !statuses[i].Available {
+			continue
+		}
+		if maxLevel > 0 && maxLevel-statuses[i].Level > p.maxLag {
+			continue
+		}
+		candidates = append(candidates, candidate{p.nodes[i], statuses[i]})
+	}
+	if len(candidates) == 0 {
 		return nil, errors.Errorf("No availiable nodes")
 	}
 
-	return nodes[rand.Intn(len(nodes))], nil
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if freshest && candidates[i].status.Level != candidates[j].status.Level {
+			return candidates[i].status.Level > candidates[j].status.Level
+		}
+		return candidates[i].status.Latency < candidates[j].status.Latency
+	})
+
+	nodes := make([]*poolItem, len(candidates))
+	for i := range candidates {
+		nodes[i] = candidates[i].item
+	}
+	return nodes, nil
 }
 
-func (p Pool) call(method string, args ...interface{}) (reflect.Value, error) {
-	node, err := p.getNode()
+func (p *Pool) call(method string, args ...interface{}) (reflect.Value, error) {
+	nodes, err := p.getNodes(isHeadMethod(method, args...))
 	if err != nil {
 		return reflect.Value{}, err
 	}
-	nodeVal := reflect.ValueOf(&node.node)
-	if nodeVal.Kind() == reflect.Ptr {
-		nodeVal = nodeVal.Elem()
+
+	var response reflect.Value
+	if _, ok := hedgedMethods[method]; ok && p.hedgeDelay > 0 && len(nodes) > 1 {
+		response, err = p.hedgedCall(nodes[0], nodes[1], method, args...)
+	} else {
+		response, err = p.callNode(nodes[0], method, args...)
+	}
+	if err != nil && IsNodeUnavailiableError(err) {
+		return p.call(method, args...)
 	}
+	return response, err
+}
+
+type callResult struct {
+	value reflect.Value
+	err   error
+}
 
-	mthd := nodeVal.MethodByName(method)
+// hedgedCall - sends request to `second` node if `first` doesn't respond during hedge delay or is unavailable
+func (p *Pool) hedgedCall(first, second *poolItem, method string, args ...interface{}) (reflect.Value, error) {
+	results := make(chan callResult, 2)
+	send := func(item *poolItem) {
+		value, err := p.callNode(item, method, args...)
+		results <- callResult{value, err}
+	}
+
+	go send(first)
+	pending := 1
+	hedged := false
+
+	timer := time.NewTimer(p.hedgeDelay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if !hedged {
+				hedged = true
+				pending++
+				go send(second)
+			}
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				return result.value, nil
+			}
+			if pending > 0 {
+				continue
+			}
+			if !hedged && IsNodeUnavailiableError(result.err) {
+				hedged = true
+				pending++
+				go send(second)
+				continue
+			}
+			return result.value, result.err
+		}
+	}
+}
+
+// callNode - calls `method` of node and updates node state by result
+func (p *Pool) callNode(item *poolItem, method string, args ...interface{}) (reflect.Value, error) {
+	mthd := reflect.ValueOf(item.node).MethodByName(method)
+	if !mthd.IsValid() {
+		return reflect.Value{}, errors.Errorf("Unknown method: %s", method)
+	}
 	numIn := mthd.Type().NumIn()
 	if numIn != len(args) {
 		return reflect.Value{}, errors.Errorf("Invalid args count: wait %d got %d", numIn, len(args))
@@ -94,24 +343,48 @@ func (p Pool) call(method string, args ...interface{}) (reflect.Value, error) {
 		in[i] = reflect.ValueOf(args[i])
 	}
 
+	start := time.Now()
 	response := mthd.Call(in)
 	if len(response) != 2 {
-		node.block()
+		item.block()
 		return reflect.Value{}, errors.Errorf("Invalid response length: %d", len(response))
 	}
 
 	if !response[1].IsNil() {
-		if IsNodeUnavailiableError(response[1].Interface().(error)) {
-			node.block()
-			return p.call(method, args...)
+		err := response[1].Interface().(error)
+		if IsNodeUnavailiableError(err) {
+			item.block()
+		}
+		return response[0], err
+	}
+
+	item.addLatency(time.Since(start))
+	if isHeadMethod(method, args...) {
+		switch head := response[0].Interface().(type) {
+		case Header:
+			item.setHead(head.Level, head.Protocol)
+		case int64:
+			item.setHead(head, "")
 		}
-		return response[0], response[1].Interface().(error)
 	}
 	return response[0], nil
 }
 
+// isHeadMethod - returns true if method requests head of node
+func isHeadMethod(method string, args ...interface{}) bool {
+	switch method {
+	case "GetHead", "GetLevel":
+		return true
+	case "GetHeader":
+		level, ok := args[0].(int64)
+		return ok && level == 0
+	default:
+		return false
+	}
+}
+
 // GetHead -
-func (p Pool) GetHead() (Header, error) {
+func (p *Pool) GetHead() (Header, error) {
 	data, err := p.call("GetHead")
 	if err != nil {
 		return Header{}, err
@@ -120,7 +393,7 @@ func (p Pool) GetHead() (Header, error) {
 }
 
 // GetHeader -
-func (p Pool) GetHeader(block int64) (Header, error) {
+func (p *Pool) GetHeader(block int64) (Header, error) {
 	data, err := p.call("GetHeader", block)
 	if err != nil {
 		return Header{}, err
@@ -128,8 +401,17 @@ func (p Pool) GetHeader(block int64) (Header, error) {
 	return data.Interface().(Header), nil
 }
 
+// GetHeaderWithContext -
+func (p *Pool) GetHeaderWithContext(ctx context.Context, block int64) (Header, error) {
+	data, err := p.call("GetHeaderWithContext", ctx, block)
+	if err != nil {
+		return Header{}, err
+	}
+	return data.Interface().(Header), nil
+}
+
 // GetLevel -
-func (p Pool) GetLevel() (int64, error) {
+func (p *Pool) GetLevel() (int64, error) {
 	data, err := p.call("GetLevel")
 	if err != nil {
 		return 0, err
@@ -138,7 +420,7 @@ func (p Pool) GetLevel() (int64, error) {
 }
 
 // GetLevelTime - get level time
-func (p Pool) GetLevelTime(level int) (time.Time, error) {
+func (p *Pool) GetLevelTime(level int) (time.Time, error) {
 	data, err := p.call("GetLevelTime", level)
 	if err != nil {
 		return time.Now(), err
@@ -147,7 +429,7 @@ func (p Pool) GetLevelTime(level int) (time.Time, error) {
 }
 
 // GetScriptJSON -
-func (p Pool) GetScriptJSON(address string, level int64) (gjson.Result, error) {
+func (p *Pool) GetScriptJSON(address string, level int64) (gjson.Result, error) {
 	data, err := p.call("GetScriptJSON", address, level)
 	if err != nil {
 		return gjson.Result{}, err
@@ -156,7 +438,7 @@ func (p Pool) GetScriptJSON(address string, level int64) (gjson.Result, error) {
 }
 
 // GetScriptStorageJSON -
-func (p Pool) GetScriptStorageJSON(address string, level int64) (gjson.Result, error) {
+func (p *Pool) GetScriptStorageJSON(address string, level int64) (gjson.Result, error) {
 	data, err := p.call("GetScriptStorageJSON", address, level)
 	if err != nil {
 		return gjson.Result{}, err
@@ -165,7 +447,7 @@ func (p Pool) GetScriptStorageJSON(address string, level int64) (gjson.Result, e
 }
 
 // GetContractBalance -
-func (p Pool) GetContractBalance(address string, level int64) (int64, error) {
+func (p *Pool) GetContractBalance(address string, level int64) (int64, error) {
 	data, err := p.call("GetContractBalance", address, level)
 	if err != nil {
 		return 0, err
@@ -174,7 +456,7 @@ func (p Pool) GetContractBalance(address string, level int64) (int64, error) {
 }
 
 // GetContractData -
-func (p Pool) GetContractData(address string, level int64) (ContractData, error) {
+func (p *Pool) GetContractData(address string, level int64) (ContractData, error) {
 	data, err := p.call("GetContractData", address, level)
 	if err != nil {
 		return ContractData{}, err
@@ -183,7 +465,7 @@ func (p Pool) GetContractData(address string, level int64) (ContractData, error)
 }
 
 // GetOperations -
-func (p Pool) GetOperations(block int64) (res gjson.Result, err error) {
+func (p *Pool) GetOperations(block int64) (res gjson.Result, err error) {
 	data, err := p.call("GetOperations", block)
 	if err != nil {
 		return
@@ -191,8 +473,17 @@ func (p Pool) GetOperations(block int64) (res gjson.Result, err error) {
 	return data.Interface().(gjson.Result), nil
 }
 
+// GetOperationsWithContext -
+func (p *Pool) GetOperationsWithContext(ctx context.Context, block int64) (res gjson.Result, err error) {
+	data, err := p.call("GetOperationsWithContext", ctx, block)
+	if err != nil {
+		return
+	}
+	return data.Interface().(gjson.Result), nil
+}
+
 // GetContractsByBlock -
-func (p Pool) GetContractsByBlock(block int64) ([]string, error) {
+func (p *Pool) GetContractsByBlock(block int64) ([]string, error) {
 	data, err := p.call("GetContractsByBlock", block)
 	if err != nil {
 		return nil, err
@@ -201,7 +492,7 @@ func (p Pool) GetContractsByBlock(block int64) ([]string, error) {
 }
 
 // GetNetworkConstants -
-func (p Pool) GetNetworkConstants(level int64) (res Constants, err error) {
+func (p *Pool) GetNetworkConstants(level int64) (res Constants, err error) {
 	data, err := p.call("GetNetworkConstants", level)
 	if err != nil {
 		return res, err
@@ -210,7 +501,7 @@ func (p Pool) GetNetworkConstants(level int64) (res Constants, err error) {
 }
 
 // RunCode -
-func (p Pool) RunCode(script, storage, input gjson.Result, chainID, source, payer, entrypoint, proto string, amount, gas int64) (gjson.Result, error) {
+func (p *Pool) RunCode(script, storage, input gjson.Result, chainID, source, payer, entrypoint, proto string, amount, gas int64) (gjson.Result, error) {
 	data, err := p.call("RunCode", script, storage, input, chainID, source, payer, entrypoint, proto, amount, gas)
 	if err != nil {
 		return gjson.Result{}, err
@@ -219,7 +510,7 @@ func (p Pool) RunCode(script, storage, input gjson.Result, chainID, source, paye
 }
 
 // RunOperation -
-func (p Pool) RunOperation(chainID, branch, source, destination string, fee, gasLimit, storageLimit, counter, amount int64, parameters gjson.Result) (gjson.Result, error) {
+func (p *Pool) RunOperation(chainID, branch, source, destination string, fee, gasLimit, storageLimit, counter, amount int64, parameters gjson.Result) (gjson.Result, error) {
 	data, err := p.call("RunOperation", chainID, branch, source, destination, fee, gasLimit, storageLimit, counter, amount, parameters)
 	if err != nil {
 		return gjson.Result{}, err
@@ -228,7 +519,7 @@ func (p Pool) RunOperation(chainID, branch, source, destination string, fee, gas
 }
 
 // GetCounter -
-func (p Pool) GetCounter(address string) (int64, error) {
+func (p *Pool) GetCounter(address string) (int64, error) {
 	data, err := p.call("GetCounter", address)
 	if err != nil {
 		return 0, err
@@ -237,10 +528,33 @@ func (p Pool) GetCounter(address string) (int64, error) {
 }
 
 // GetCode -
-func (p Pool) GetCode(address string, level int64) (gjson.Result, error) {
+func (p *Pool) GetCode(address string, level int64) (gjson.Result, error) {
 	data, err := p.call("GetCode", address, level)
 	if err != nil {
 		return gjson.Result{}, err
 	}
 	return data.Interface().(gjson.Result), nil
 }
+
+// GetBigMapValues -
+func (p *Pool) GetBigMapValues(ptr, level, offset, length int64) (gjson.Result, error) {
+	data, err := p.call("GetBigMapValues", ptr, level, offset, length)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	return data.Interface().(gjson.Result), nil
+}
+
+// MonitorHeads - streams heads from the node with the highest head
+func (p *Pool) MonitorHeads(stop <-chan struct{}) (<-chan Header, error) {
+	nodes, err := p.getNodes(true)
+	if err != nil {
+		return nil, err
+	}
+	heads, err := nodes[0].node.MonitorHeads(stop)
+	if err != nil && IsNodeUnavailiableError(err) {
+		nodes[0].block()
+		return p.MonitorHeads(stop)
+	}
+	return heads, err
+}
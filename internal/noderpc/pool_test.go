@@ -0,0 +1,158 @@
+package noderpc
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestNode(level int64, delay time.Duration, status int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/header"):
+			fmt.Fprintf(w, `{"level":%d,"protocol":"PsDELPH1"}`, level)
+		case strings.HasSuffix(r.URL.Path, "/operations/3"):
+			fmt.Fprintf(w, `[{"level":%d}]`, level)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestPool_GetHead(t *testing.T) {
+	fresh := newTestNode(100, 0, http.StatusOK)
+	defer fresh.Close()
+	lagging := newTestNode(90, 0, http.StatusOK)
+	defer lagging.Close()
+
+	pool := NewPool([]string{lagging.URL, fresh.URL}, []NodeOption{WithRetryCount(1)}, WithMaxLag(2))
+	defer pool.Close()
+	for i := 0; i < 5; i++ {
+		head, err := pool.GetHead()
+		if err != nil {
+			t.Errorf("GetHead() error = %v", err)
+			return
+		}
+		if head.Level != 100 {
+			t.Errorf("GetHead() level = %d, want 100", head.Level)
+			return
+		}
+	}
+
+	for _, status := range pool.Status() {
+		switch status.URL {
+		case lagging.URL:
+			if status.Level != 90 {
+				t.Errorf("lagging node level = %d, want 90", status.Level)
+			}
+		case fresh.URL:
+			if status.Level != 100 || status.Protocol != "PsDELPH1" || status.Latency == 0 {
+				t.Errorf("fresh node status = %+v", status)
+			}
+		}
+	}
+}
+
+func TestPool_getNodes(t *testing.T) {
+	unavailable := newTestNode(100, 0, http.StatusBadGateway)
+	defer unavailable.Close()
+	slow := newTestNode(100, 50*time.Millisecond, http.StatusOK)
+	defer slow.Close()
+	fast := newTestNode(99, 0, http.StatusOK)
+	defer fast.Close()
+
+	pool := NewPool([]string{unavailable.URL, slow.URL, fast.URL}, []NodeOption{WithRetryCount(1)})
+	defer pool.Close()
+	nodes, err := pool.getNodes(false)
+	if err != nil {
+		t.Errorf("getNodes() error = %v", err)
+		return
+	}
+	if len(nodes) != 2 {
+		t.Errorf("getNodes() count = %d, want 2", len(nodes))
+		return
+	}
+	if nodes[0].node.baseURL != fast.URL {
+		t.Errorf("getNodes() first = %s, want fast node", nodes[0].node.baseURL)
+	}
+
+	nodes, err = pool.getNodes(true)
+	if err != nil {
+		t.Errorf("getNodes() error = %v", err)
+		return
+	}
+	if nodes[0].node.baseURL != slow.URL {
+		t.Errorf("getNodes(freshest) first = %s, want node with the highest head", nodes[0].node.baseURL)
+	}
+}
+
+func TestPool_hedging(t *testing.T) {
+	slow := newTestNode(100, time.Second, http.StatusOK)
+	defer slow.Close()
+	fast := newTestNode(100, 0, http.StatusOK)
+	defer fast.Close()
+
+	pool := NewPool([]string{slow.URL, fast.URL}, []NodeOption{WithRetryCount(1)}, WithHedging(20*time.Millisecond))
+	defer pool.Close()
+	// slow node is measured as the fastest one to be chosen first
+	pool.nodes[0].latency = time.Millisecond
+	pool.nodes[1].latency = time.Second
+
+	start := time.Now()
+	if _, err := pool.GetOperations(100); err != nil {
+		t.Errorf("GetOperations() error = %v", err)
+		return
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("GetOperations() was not hedged: %v", elapsed)
+	}
+}
+
+func TestPool_watchHeads(t *testing.T) {
+	var level, requests int64 = 100, 0
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		fmt.Fprintf(w, `{"level":%d,"protocol":"PsDELPH1"}`, atomic.LoadInt64(&level))
+	}))
+	defer node.Close()
+	other := newTestNode(100, 0, http.StatusOK)
+	defer other.Close()
+
+	pool := NewPool([]string{node.URL, other.URL}, []NodeOption{WithRetryCount(1)}, WithHeadCheckPeriod(20*time.Millisecond))
+	if status := pool.Status()[0]; status.Level != 100 {
+		t.Errorf("level after start = %d, want 100", status.Level)
+	}
+
+	// nodes are chosen by cached state without requests
+	checked := atomic.LoadInt64(&requests)
+	if _, err := pool.getNodes(false); err != nil {
+		t.Errorf("getNodes() error = %v", err)
+		return
+	}
+	if count := atomic.LoadInt64(&requests); count != checked {
+		t.Errorf("getNodes() sent %d requests", count-checked)
+	}
+
+	atomic.StoreInt64(&level, 101)
+	time.Sleep(100 * time.Millisecond)
+	if status := pool.Status()[0]; status.Level != 101 {
+		t.Errorf("level after background check = %d, want 101", status.Level)
+	}
+
+	pool.Close()
+	time.Sleep(40 * time.Millisecond)
+	checked = atomic.LoadInt64(&requests)
+	time.Sleep(100 * time.Millisecond)
+	if count := atomic.LoadInt64(&requests); count != checked {
+		t.Errorf("heads are requested after Close(): %d requests", count-checked)
+	}
+}
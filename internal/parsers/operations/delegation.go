@@ -0,0 +1,75 @@
+package operations
+
+import (
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/helpers"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/tidwall/gjson"
+)
+
+// Delegation - parses delegations which set or withdraw delegate of contract
+type Delegation struct {
+	*ParseParams
+}
+
+// NewDelegation -
+func NewDelegation(params *ParseParams) Delegation {
+	return Delegation{params}
+}
+
+// Parse -
+func (p Delegation) Parse(data gjson.Result) ([]models.Model, error) {
+	source := data.Get("source").String()
+	delegation := operation.Operation{
+		ID:           helpers.GenerateID(),
+		Network:      p.network,
+		Hash:         p.hash,
+		Protocol:     p.head.Protocol,
+		Level:        p.head.Level,
+		Timestamp:    p.head.Timestamp,
+		Kind:         data.Get("kind").String(),
+		Initiator:    source,
+		Source:       source,
+		Fee:          data.Get("fee").Int(),
+		Counter:      data.Get("counter").Int(),
+		GasLimit:     data.Get("gas_limit").Int(),
+		StorageLimit: data.Get("storage_limit").Int(),
+		Delegate:     data.Get("delegate").String(),
+		IndexedTime:  time.Now().UnixNano() / 1000,
+		ContentIndex: p.contentIdx,
+	}
+
+	p.fillInternal(&delegation)
+
+	if data.Get("nonce").Exists() {
+		nonce := data.Get("nonce").Int()
+		delegation.Nonce = &nonce
+	}
+
+	delegationModels := []models.Model{&delegation}
+
+	if operationMetadata := parseMetadata(data, delegation); operationMetadata != nil {
+		delegation.Result = &operationMetadata.Result
+		delegation.Status = delegation.Result.Status
+		delegation.Errors = delegation.Result.Errors
+	}
+
+	p.stackTrace.Add(delegation)
+	return delegationModels, nil
+}
+
+func (p Delegation) fillInternal(tx *operation.Operation) {
+	if p.main == nil {
+		p.main = tx
+		return
+	}
+
+	tx.Counter = p.main.Counter
+	tx.Hash = p.main.Hash
+	tx.Level = p.main.Level
+	tx.Timestamp = p.main.Timestamp
+	tx.Internal = true
+	tx.Initiator = p.main.Source
+}
@@ -26,6 +26,13 @@ func (opg Group) Parse(data gjson.Result) ([]models.Model, error) {
 	opg.hash = data.Get("hash").String()
 	helpers.SetTagSentry("hash", opg.hash)
 
+	opg.originators = make(map[string]struct{})
+	for _, item := range data.Get("contents").Array() {
+		if kind := item.Get("kind").String(); kind == consts.Origination || kind == consts.OriginationNew {
+			opg.originators[item.Get("source").String()] = struct{}{}
+		}
+	}
+
 	for idx, item := range data.Get("contents").Array() {
 		opg.contentIdx = int64(idx)
 
@@ -53,7 +60,11 @@ func NewContent(params *ParseParams) Content {
 
 // Parse -
 func (content Content) Parse(data gjson.Result) ([]models.Model, error) {
-	if !content.needParse(data) {
+	need, err := content.needParse(data)
+	if err != nil {
+		return nil, err
+	}
+	if !need {
 		return nil, nil
 	}
 
@@ -73,6 +84,18 @@ func (content Content) Parse(data gjson.Result) ([]models.Model, error) {
 			return nil, err
 		}
 		models = append(models, txModels...)
+	case consts.Delegation:
+		delegationModels, err := NewDelegation(content.ParseParams).Parse(data)
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, delegationModels...)
+	case consts.Reveal:
+		revealModels, err := NewReveal(content.ParseParams).Parse(data)
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, revealModels...)
 	default:
 		return nil, errors.Errorf("Invalid operation kind: %s", kind)
 	}
@@ -86,14 +109,41 @@ func (content Content) Parse(data gjson.Result) ([]models.Model, error) {
 	return models, nil
 }
 
-func (content Content) needParse(item gjson.Result) bool {
+func (content Content) needParse(item gjson.Result) (bool, error) {
 	kind := item.Get("kind").String()
 	source := item.Get("source").String()
 	destination := item.Get("destination").String()
 	prefixCondition := helpers.IsContract(source) || helpers.IsContract(destination)
 	transactionCondition := kind == consts.Transaction && prefixCondition
 	originationCondition := (kind == consts.Origination || kind == consts.OriginationNew) && item.Get("script").Exists()
-	return originationCondition || transactionCondition
+	delegationCondition := kind == consts.Delegation && helpers.IsContract(source)
+	if originationCondition || transactionCondition || delegationCondition {
+		return true, nil
+	}
+	if kind == consts.Reveal {
+		return content.isManager(source)
+	}
+	return false, nil
+}
+
+// isManager - returns true if `address` originates contract in current operation group or manages indexed contract
+func (content Content) isManager(address string) (bool, error) {
+	if _, ok := content.originators[address]; ok {
+		return true, nil
+	}
+	if content.contracts == nil {
+		return false, nil
+	}
+	if _, err := content.contracts.Get(map[string]interface{}{
+		"network": content.network,
+		"manager": address,
+	}); err != nil {
+		if content.Storage.IsRecordNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
 }
 
 func (content Content) parseInternal(data gjson.Result) ([]models.Model, error) {
@@ -14,6 +14,7 @@ import (
 	mock_general "github.com/baking-bad/bcdhub/internal/models/mock"
 	mock_bmd "github.com/baking-bad/bcdhub/internal/models/mock/bigmapdiff"
 	mock_block "github.com/baking-bad/bcdhub/internal/models/mock/block"
+	mock_contract "github.com/baking-bad/bcdhub/internal/models/mock/contract"
 	mock_schema "github.com/baking-bad/bcdhub/internal/models/mock/schema"
 	mock_token_balance "github.com/baking-bad/bcdhub/internal/models/mock/tokenbalance"
 	mock_tzip "github.com/baking-bad/bcdhub/internal/models/mock/tzip"
@@ -25,6 +26,7 @@ import (
 	"github.com/baking-bad/bcdhub/internal/noderpc"
 	"github.com/baking-bad/bcdhub/internal/parsers/contract"
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
 )
 
@@ -59,6 +61,27 @@ func TestGroup_Parse(t *testing.T) {
 	defer ctrlRPC.Finish()
 	rpc := noderpc.NewMockINode(ctrlRPC)
 
+	ctrlContractRepo := gomock.NewController(t)
+	defer ctrlContractRepo.Finish()
+	contractRepo := mock_contract.NewMockRepository(ctrlContractRepo)
+
+	errContractNotFound := errors.New("contract not found")
+	contractRepo.
+		EXPECT().
+		Get(map[string]interface{}{"network": "delphinet", "manager": "tz1SX7SPdx4ZJb6uP5Hh5XBVZhh9wTfFaud3"}).
+		Return(modelContract.Contract{Network: "delphinet", Address: "KT1managed", Manager: "tz1SX7SPdx4ZJb6uP5Hh5XBVZhh9wTfFaud3"}, nil).
+		AnyTimes()
+	contractRepo.
+		EXPECT().
+		Get(map[string]interface{}{"network": "edo2net", "manager": "tz1SX7SPdx4ZJb6uP5Hh5XBVZhh9wTfFaud3"}).
+		Return(modelContract.Contract{}, errContractNotFound).
+		AnyTimes()
+	generalRepo.
+		EXPECT().
+		IsRecordNotFound(errContractNotFound).
+		Return(true).
+		AnyTimes()
+
 	ctrlScriptSaver := gomock.NewController(t)
 	defer ctrlScriptSaver.Finish()
 	scriptSaver := contract.NewMockScriptSaver(ctrlScriptSaver)
@@ -297,7 +320,7 @@ func TestGroup_Parse(t *testing.T) {
 					Level:        1151495,
 					From:         "KT1Ap287P1NzsnToSJdA4aqSNjPomRaHBZSr",
 					To:           "tz1dMH7tW7RhdvVMR4wKVFF1Ke8m8ZDvrTTE",
-					TokenID:      0,
+					TokenID:      "0",
 					AmountBigInt: big.NewInt(7.87488e+06),
 					Counter:      6909186,
 					Nonce:        setInt64(0),
@@ -546,6 +569,87 @@ func TestGroup_Parse(t *testing.T) {
 					Protocol:     "PsCARTHAGazKbHtnKfLzQg3kms52kSRpgnDY982a9oYsSXRLQEb",
 				},
 			},
+		}, {
+			name: "ooMASi45ub7Qe4ZE36UT5G6cU4ud8Fhhe4deS4F3cw9KTAb8dLc",
+			ParseParams: NewParseParams(
+				rpc, generalRepo, bmdRepo, blockRepo, tzipRepo, schemaRepo, tbRepo,
+				WithShareDirectory("./test"),
+				WithHead(noderpc.Header{
+					Timestamp: timestamp,
+					Protocol:  "PsddFKi32cMJ2qPjf43Qv5GDWLDPZb3T3bF6fLKiF5HtvHNU7aP",
+					Level:     300000,
+					ChainID:   "test",
+				}),
+				WithNetwork(consts.Mainnet),
+			),
+			filename: "./data/rpc/opg/ooMASi45ub7Qe4ZE36UT5G6cU4ud8Fhhe4deS4F3cw9KTAb8dLc.json",
+			want: []models.Model{
+				&operation.Operation{
+					Network:      consts.Mainnet,
+					Protocol:     "PsddFKi32cMJ2qPjf43Qv5GDWLDPZb3T3bF6fLKiF5HtvHNU7aP",
+					Hash:         "ooMASi45ub7Qe4ZE36UT5G6cU4ud8Fhhe4deS4F3cw9KTAb8dLc",
+					Status:       consts.Applied,
+					Timestamp:    timestamp,
+					Level:        300000,
+					Kind:         consts.Delegation,
+					Initiator:    "KT1VvXEpeBpreAVpfp4V8ZujqZu6gLWkUxcp",
+					Source:       "KT1VvXEpeBpreAVpfp4V8ZujqZu6gLWkUxcp",
+					Fee:          1400,
+					Counter:      213440,
+					GasLimit:     10100,
+					StorageLimit: 0,
+					Delegate:     "tz1WCd2jm4uSt4vntk4vSuUWoZQGhLcDuR9q",
+				},
+			},
+		}, {
+			name: "opukC7edhDQ7cn5d4gEYkbUrMWeWQLGsCmrG6dLaYyNoVKf58ZT",
+			ParseParams: NewParseParams(
+				rpc, generalRepo, bmdRepo, blockRepo, tzipRepo, schemaRepo, tbRepo,
+				WithShareDirectory("./test"),
+				WithHead(noderpc.Header{
+					Timestamp: timestamp,
+					Protocol:  "PsDELPH1Kxsxt8f9eWbxQeRxkjfbxoqM52jvs5Y5fBxWWh4ifpo",
+					Level:     86141,
+					ChainID:   "test",
+				}),
+				WithNetwork("delphinet"),
+				WithContracts(contractRepo),
+			),
+			filename: "./data/rpc/opg/opukC7edhDQ7cn5d4gEYkbUrMWeWQLGsCmrG6dLaYyNoVKf58ZT.json",
+			want: []models.Model{
+				&operation.Operation{
+					Network:      "delphinet",
+					Protocol:     "PsDELPH1Kxsxt8f9eWbxQeRxkjfbxoqM52jvs5Y5fBxWWh4ifpo",
+					Hash:         "opukC7edhDQ7cn5d4gEYkbUrMWeWQLGsCmrG6dLaYyNoVKf58ZT",
+					Status:       consts.Applied,
+					Timestamp:    timestamp,
+					Level:        86141,
+					Kind:         consts.Reveal,
+					Initiator:    "tz1SX7SPdx4ZJb6uP5Hh5XBVZhh9wTfFaud3",
+					Source:       "tz1SX7SPdx4ZJb6uP5Hh5XBVZhh9wTfFaud3",
+					Fee:          1269,
+					Counter:      654593,
+					GasLimit:     10000,
+					StorageLimit: 0,
+					PublicKey:    "edpkuAJhbFLfJ4zWbQQWTZNGDg7hrcG1m1CBSWVB3iDHChjuzeaZB6",
+				},
+			},
+		}, {
+			name: "opukC7edhDQ7cn5d4gEYkbUrMWeWQLGsCmrG6dLaYyNoVKf58ZT: source does not manage contracts",
+			ParseParams: NewParseParams(
+				rpc, generalRepo, bmdRepo, blockRepo, tzipRepo, schemaRepo, tbRepo,
+				WithShareDirectory("./test"),
+				WithHead(noderpc.Header{
+					Timestamp: timestamp,
+					Protocol:  "PsDELPH1Kxsxt8f9eWbxQeRxkjfbxoqM52jvs5Y5fBxWWh4ifpo",
+					Level:     86141,
+					ChainID:   "test",
+				}),
+				WithNetwork("edo2net"),
+				WithContracts(contractRepo),
+			),
+			filename: "./data/rpc/opg/opukC7edhDQ7cn5d4gEYkbUrMWeWQLGsCmrG6dLaYyNoVKf58ZT.json",
+			want:     []models.Model{},
 		},
 	}
 	for _, tt := range tests {
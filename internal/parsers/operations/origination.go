@@ -3,6 +3,7 @@ package operations
 import (
 	"time"
 
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
 	"github.com/baking-bad/bcdhub/internal/helpers"
 	"github.com/baking-bad/bcdhub/internal/models"
 	"github.com/baking-bad/bcdhub/internal/models/operation"
@@ -118,7 +119,16 @@ func (p Origination) appliedHandler(item gjson.Result, origination *operation.Op
 	for i := range bu {
 		models = append(models, bu[i])
 	}
-	return models, nil
+	sd := NewSaplingDiff(origination).Parse(item)
+	for i := range sd {
+		models = append(models, sd[i])
+	}
+
+	ticketUpdates, err := p.parseTickets(origination, origination.GetScriptSection(consts.STORAGE), origination.Script.Get("storage"))
+	if err != nil {
+		return nil, err
+	}
+	return append(models, ticketUpdates...), nil
 }
 
 func (p Origination) fillInternal(tx *operation.Operation) {
@@ -8,9 +8,11 @@ import (
 	"github.com/baking-bad/bcdhub/internal/models"
 	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
 	"github.com/baking-bad/bcdhub/internal/models/block"
+	modelContract "github.com/baking-bad/bcdhub/internal/models/contract"
 	"github.com/baking-bad/bcdhub/internal/models/operation"
 	"github.com/baking-bad/bcdhub/internal/models/protocol"
 	"github.com/baking-bad/bcdhub/internal/models/schema"
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
 	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
 	"github.com/baking-bad/bcdhub/internal/models/tzip"
 	"github.com/baking-bad/bcdhub/internal/noderpc"
@@ -26,6 +28,9 @@ type ParseParams struct {
 	Schema        schema.Repository
 	TokenBalances tokenbalance.Repository
 
+	ticketBalances ticketbalance.Repository
+	contracts      modelContract.Repository
+
 	rpc      noderpc.INode
 	shareDir string
 
@@ -47,6 +52,8 @@ type ParseParams struct {
 	contentIdx int64
 	main       *operation.Operation
 
+	originators map[string]struct{}
+
 	once *sync.Once
 }
 
@@ -116,6 +123,20 @@ func WithMainOperation(main *operation.Operation) ParseParamsOption {
 	}
 }
 
+// WithTicketBalances - sets repository of ticket balances. Ticket balances are not updated if it's not set.
+func WithTicketBalances(repo ticketbalance.Repository) ParseParamsOption {
+	return func(dp *ParseParams) {
+		dp.ticketBalances = repo
+	}
+}
+
+// WithContracts - sets repository of contracts. Reveals are indexed only if their sources manage indexed contracts, so reveals are skipped if it's not set.
+func WithContracts(repo modelContract.Repository) ParseParamsOption {
+	return func(dp *ParseParams) {
+		dp.contracts = repo
+	}
+}
+
 // NewParseParams -
 func NewParseParams(rpc noderpc.INode, storage models.GeneralRepository, bmdRepo bigmapdiff.Repository, blockRepo block.Repository, tzipRepo tzip.Repository, schemaRepo schema.Repository, tbRepo tokenbalance.Repository, opts ...ParseParamsOption) *ParseParams {
 	params := &ParseParams{
@@ -0,0 +1,51 @@
+package operations
+
+import (
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/helpers"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/tidwall/gjson"
+)
+
+// Reveal - parses reveals of public keys of contract managers. Reveal is stored once by its source and is returned with operations of managed contracts.
+type Reveal struct {
+	*ParseParams
+}
+
+// NewReveal -
+func NewReveal(params *ParseParams) Reveal {
+	return Reveal{params}
+}
+
+// Parse -
+func (p Reveal) Parse(data gjson.Result) ([]models.Model, error) {
+	source := data.Get("source").String()
+	reveal := operation.Operation{
+		ID:           helpers.GenerateID(),
+		Network:      p.network,
+		Hash:         p.hash,
+		Protocol:     p.head.Protocol,
+		Level:        p.head.Level,
+		Timestamp:    p.head.Timestamp,
+		Kind:         data.Get("kind").String(),
+		Initiator:    source,
+		Source:       source,
+		Fee:          data.Get("fee").Int(),
+		Counter:      data.Get("counter").Int(),
+		GasLimit:     data.Get("gas_limit").Int(),
+		StorageLimit: data.Get("storage_limit").Int(),
+		PublicKey:    data.Get("public_key").String(),
+		IndexedTime:  time.Now().UnixNano() / 1000,
+		ContentIndex: p.contentIdx,
+	}
+
+	if operationMetadata := parseMetadata(data, reveal); operationMetadata != nil {
+		reveal.Result = &operationMetadata.Result
+		reveal.Status = reveal.Result.Status
+		reveal.Errors = reveal.Result.Errors
+	}
+
+	return []models.Model{&reveal}, nil
+}
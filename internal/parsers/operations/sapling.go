@@ -0,0 +1,74 @@
+package operations
+
+import (
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/helpers"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/models/saplingdiff"
+	"github.com/tidwall/gjson"
+)
+
+// SaplingDiff -
+type SaplingDiff struct {
+	operation *operation.Operation
+}
+
+// NewSaplingDiff -
+func NewSaplingDiff(operation *operation.Operation) SaplingDiff {
+	return SaplingDiff{operation}
+}
+
+// Parse - receives sapling state diffs from `lazy_storage_diff` of operation result
+func (s SaplingDiff) Parse(data gjson.Result) []*saplingdiff.SaplingDiff {
+	path := "metadata.operation_result.lazy_storage_diff"
+	if !data.Get(path).Exists() {
+		path = "result.lazy_storage_diff"
+		if !data.Get(path).Exists() {
+			return nil
+		}
+	}
+
+	diffs := make([]*saplingdiff.SaplingDiff, 0)
+	for _, item := range data.Get(path).Array() {
+		if item.Get("kind").String() != "sapling_state" {
+			continue
+		}
+		ptr := item.Get("id").Int()
+		if ptr < 0 {
+			continue
+		}
+
+		diff := item.Get("diff")
+		sd := &saplingdiff.SaplingDiff{
+			ID:          helpers.GenerateID(),
+			IndexedTime: time.Now().UnixNano() / 1000,
+			Network:     s.operation.Network,
+			Level:       s.operation.Level,
+			Timestamp:   s.operation.Timestamp,
+			OperationID: s.operation.ID,
+			Hash:        s.operation.Hash,
+			Contract:    s.operation.Destination,
+			Ptr:         ptr,
+			Action:      diff.Get("action").String(),
+			MemoSize:    diff.Get("memo_size").Int(),
+		}
+
+		if source := diff.Get("source"); source.Exists() {
+			sourcePtr := source.Int()
+			sd.SourcePtr = &sourcePtr
+		}
+
+		for _, cc := range diff.Get("updates.commitments_and_ciphertexts").Array() {
+			sd.Commitments = append(sd.Commitments, cc.Get("0").String())
+		}
+		for _, nullifier := range diff.Get("updates.nullifiers").Array() {
+			sd.Nullifiers = append(sd.Nullifiers, nullifier.String())
+		}
+		sd.CommitmentsCount = int64(len(sd.Commitments))
+		sd.NullifiersCount = int64(len(sd.Nullifiers))
+
+		diffs = append(diffs, sd)
+	}
+	return diffs
+}
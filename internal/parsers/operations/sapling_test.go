@@ -0,0 +1,96 @@
+package operations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/models/saplingdiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaplingDiff_Parse(t *testing.T) {
+	timestamp := time.Now()
+	op := &operation.Operation{
+		ID:          "operation_id",
+		Network:     "edo2net",
+		Level:       123,
+		Destination: "KT1A1",
+		Timestamp:   timestamp,
+		Hash:        "hash",
+	}
+	sourcePtr := int64(14)
+
+	tests := []struct {
+		name     string
+		fileName string
+		want     []*saplingdiff.SaplingDiff
+	}{
+		{
+			name:     "update",
+			fileName: "./data/sapling/test1.json",
+			want: []*saplingdiff.SaplingDiff{
+				{
+					Network:     "edo2net",
+					Level:       123,
+					Timestamp:   timestamp,
+					OperationID: "operation_id",
+					Hash:        "hash",
+					Contract:    "KT1A1",
+					Ptr:         14,
+					Action:      saplingdiff.ActionUpdate,
+					Commitments: []string{
+						"1ba7b9a8bf2ee35c5bfe4e9fcd6c33c3ae54ef5a7a6c4dc1cc11d4cc12bc4a61",
+						"4a2c8e3ac0eae1a5f5bcd2f5b1f08f1ab2e2e8fcd0e35c0b20a8b31cd1a0b26d",
+					},
+					Nullifiers: []string{
+						"0ee7cc5e3b7f4b8fc9df1dbbd4e8b5c6a0bd1e68e6c6d8e0b0cf4e8fd8a4b5f2",
+					},
+					CommitmentsCount: 2,
+					NullifiersCount:  1,
+				},
+			},
+		}, {
+			name:     "alloc and copy",
+			fileName: "./data/sapling/test2.json",
+			want: []*saplingdiff.SaplingDiff{
+				{
+					Network:     "edo2net",
+					Level:       123,
+					Timestamp:   timestamp,
+					OperationID: "operation_id",
+					Hash:        "hash",
+					Contract:    "KT1A1",
+					Ptr:         15,
+					Action:      saplingdiff.ActionCopy,
+					SourcePtr:   &sourcePtr,
+				}, {
+					Network:     "edo2net",
+					Level:       123,
+					Timestamp:   timestamp,
+					OperationID: "operation_id",
+					Hash:        "hash",
+					Contract:    "KT1A1",
+					Ptr:         16,
+					Action:      saplingdiff.ActionAlloc,
+					MemoSize:    8,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := readJSONFile(tt.fileName)
+			if err != nil {
+				t.Errorf(`readJSONFile("%s") = error %v`, tt.fileName, err)
+				return
+			}
+			got := NewSaplingDiff(op).Parse(data)
+			for i := range got {
+				got[i].ID = ""
+				got[i].IndexedTime = 0
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -176,7 +176,7 @@ func compareTransfers(one, two *transfer.Transfer) bool {
 		return false
 	}
 	if one.TokenID != two.TokenID {
-		logger.Info("TokenID: %d != %d", one.TokenID, two.TokenID)
+		logger.Info("TokenID: %s != %s", one.TokenID, two.TokenID)
 		return false
 	}
 	if one.AmountBigInt.Cmp(two.AmountBigInt) != 0 {
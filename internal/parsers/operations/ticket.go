@@ -0,0 +1,32 @@
+package operations
+
+import (
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/parsers/ticket"
+	"github.com/tidwall/gjson"
+)
+
+// parseTickets - finds tickets which were sent to operation destination in `data` of type `typ` and updates ticket balances
+func (p *ParseParams) parseTickets(op *operation.Operation, typ, data gjson.Result) ([]models.Model, error) {
+	tickets, err := ticket.Find(typ, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(tickets) == 0 {
+		return nil, nil
+	}
+
+	updates := ticket.NewUpdates(*op, op.Destination, tickets)
+	if p.ticketBalances != nil {
+		if err := ticket.UpdateBalances(p.ticketBalances, updates, false); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]models.Model, len(updates))
+	for i := range updates {
+		result[i] = updates[i]
+	}
+	return result, nil
+}
@@ -113,22 +113,10 @@ func (p Transaction) normalizeOperationParameter(operation *operation.Operation)
 		return nil
 	}
 	params := gjson.Parse(operation.Parameters)
-
-	var value gjson.Result
-	var entrypointName string
-
 	entrypoint := params.Get("entrypoint")
-	if entrypoint.Exists() {
-		value = params.Get("value")
-		entrypointName = entrypoint.String()
-	} else {
-		value = params
-		entrypointName = consts.DefaultEntrypoint
-	}
 
-	paramsType := operation.GetScriptSection(consts.PARAMETER)
-	typ, err := findByFieldName(entrypointName, paramsType)
-	if err != nil && !errors.Is(err, errAnnotsIsNotFound) {
+	value, typ, err := getParameterWithType(operation)
+	if err != nil {
 		return err
 	}
 	data, err := normalize.Data(value, typ)
@@ -149,6 +137,29 @@ func (p Transaction) normalizeOperationParameter(operation *operation.Operation)
 	return nil
 }
 
+func getParameterWithType(operation *operation.Operation) (gjson.Result, gjson.Result, error) {
+	params := gjson.Parse(operation.Parameters)
+
+	var value gjson.Result
+	var entrypointName string
+
+	entrypoint := params.Get("entrypoint")
+	if entrypoint.Exists() {
+		value = params.Get("value")
+		entrypointName = entrypoint.String()
+	} else {
+		value = params
+		entrypointName = consts.DefaultEntrypoint
+	}
+
+	paramsType := operation.GetScriptSection(consts.PARAMETER)
+	typ, err := findByFieldName(entrypointName, paramsType)
+	if err != nil && !errors.Is(err, errAnnotsIsNotFound) {
+		return value, typ, err
+	}
+	return value, typ, nil
+}
+
 // errors
 var (
 	errInvalidJSONType  = errors.New("Invalid JSON type")
@@ -242,6 +253,18 @@ func (p Transaction) appliedHandler(item gjson.Result, op *operation.Operation)
 		return nil, err
 	}
 
+	if op.IsCall() {
+		value, typ, err := getParameterWithType(op)
+		if err != nil {
+			return nil, err
+		}
+		ticketUpdates, err := p.parseTickets(op, typ, value)
+		if err != nil {
+			return nil, err
+		}
+		resultModels = append(resultModels, ticketUpdates...)
+	}
+
 	migration := NewMigration(op).Parse(item)
 	if migration != nil {
 		resultModels = append(resultModels, migration)
@@ -251,6 +274,11 @@ func (p Transaction) appliedHandler(item gjson.Result, op *operation.Operation)
 	for i := range bu {
 		resultModels = append(resultModels, bu[i])
 	}
+
+	sd := NewSaplingDiff(op).Parse(item)
+	for i := range sd {
+		resultModels = append(resultModels, sd[i])
+	}
 	return resultModels, p.getEntrypoint(item, schema, op)
 }
 
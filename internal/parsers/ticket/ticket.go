@@ -0,0 +1,138 @@
+package ticket
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/contractparser/newmiguel"
+	"github.com/baking-bad/bcdhub/internal/contractparser/unpack"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// Ticket - ticket value found in Michelson data
+type Ticket struct {
+	Ticketer    string
+	ContentType string
+	Content     string
+	Amount      *big.Int
+}
+
+// Find - returns all tickets from `data` of type `typ`. Big map pointers are skipped.
+func Find(typ, data gjson.Result) ([]Ticket, error) {
+	tickets := make([]Ticket, 0)
+	if err := find(typ, data, &tickets); err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+func find(typ, data gjson.Result, tickets *[]Ticket) error {
+	if !data.Exists() {
+		return nil
+	}
+
+	switch typ.Get("prim").String() {
+	case consts.TICKET:
+		t, err := newTicket(typ.Get("args.0"), data)
+		if err != nil {
+			return err
+		}
+		*tickets = append(*tickets, t)
+	case consts.PAIR:
+		return findInPair(typ, data, tickets)
+	case consts.OR:
+		switch data.Get("prim").String() {
+		case consts.Left:
+			return find(typ.Get("args.0"), data.Get("args.0"), tickets)
+		case consts.Right:
+			return find(typ.Get("args.1"), data.Get("args.0"), tickets)
+		}
+	case consts.OPTION:
+		if data.Get("prim").String() == consts.Some {
+			return find(typ.Get("args.0"), data.Get("args.0"), tickets)
+		}
+	case consts.LIST, consts.SET:
+		for _, item := range data.Array() {
+			if err := find(typ.Get("args.0"), item, tickets); err != nil {
+				return err
+			}
+		}
+	case consts.MAP, consts.BIGMAP:
+		if !data.IsArray() {
+			return nil
+		}
+		for _, item := range data.Array() {
+			if err := find(typ.Get("args.1"), item.Get("args.1"), tickets); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func findInPair(typ, data gjson.Result, tickets *[]Ticket) error {
+	typArgs := typ.Get("args").Array()
+	var dataArgs []gjson.Result
+	if data.IsArray() {
+		dataArgs = data.Array()
+	} else {
+		dataArgs = data.Get("args").Array()
+	}
+	if len(typArgs) < 2 || len(dataArgs) < 2 {
+		return errors.Errorf("Invalid pair: %s", data.Raw)
+	}
+
+	switch {
+	case len(typArgs) > len(dataArgs):
+		last := len(dataArgs) - 1
+		typArgs = append(typArgs[:last], combine(`{"prim":"pair","args":[%s]}`, typArgs[last:]))
+	case len(typArgs) < len(dataArgs):
+		last := len(typArgs) - 1
+		dataArgs = append(dataArgs[:last], combine(`{"prim":"Pair","args":[%s]}`, dataArgs[last:]))
+	}
+
+	for i := range typArgs {
+		if err := find(typArgs[i], dataArgs[i], tickets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func combine(format string, args []gjson.Result) gjson.Result {
+	raws := make([]string, len(args))
+	for i := range args {
+		raws[i] = args[i].Raw
+	}
+	return gjson.Parse(fmt.Sprintf(format, strings.Join(raws, ",")))
+}
+
+func newTicket(contentType, data gjson.Result) (Ticket, error) {
+	ticketer, content, amount, err := newmiguel.GetTicketArgs(data)
+	if err != nil {
+		return Ticket{}, err
+	}
+
+	t := Ticket{
+		ContentType: contentType.Raw,
+		Content:     content.Raw,
+		Amount:      big.NewInt(0),
+	}
+
+	if ticketer.Get(consts.BYTES).Exists() {
+		t.Ticketer, err = unpack.Contract(ticketer.Get(consts.BYTES).String())
+		if err != nil {
+			return t, err
+		}
+	} else {
+		t.Ticketer = ticketer.Get(consts.STRING).String()
+	}
+
+	if _, ok := t.Amount.SetString(amount.Get(consts.INT).String(), 10); !ok {
+		return t, errors.Errorf("Invalid ticket amount: %s", amount.Raw)
+	}
+	return t, nil
+}
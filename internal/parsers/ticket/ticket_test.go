@@ -0,0 +1,201 @@
+package ticket
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
+	"github.com/tidwall/gjson"
+)
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		data    string
+		want    []Ticket
+		wantErr bool
+	}{
+		{
+			name: "no tickets",
+			typ:  `{"prim":"pair","args":[{"prim":"nat"},{"prim":"string"}]}`,
+			data: `{"prim":"Pair","args":[{"int":"1"},{"string":"a"}]}`,
+			want: []Ticket{},
+		}, {
+			name: "ticket in comb pair",
+			typ:  `{"prim":"pair","args":[{"prim":"nat"},{"prim":"string"},{"prim":"ticket","args":[{"prim":"nat"}]}]}`,
+			data: `{"prim":"Pair","args":[{"int":"1"},{"prim":"Pair","args":[{"string":"a"},{"prim":"Pair","args":[{"string":"KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm"},{"prim":"Pair","args":[{"int":"7"},{"int":"10"}]}]}]}]}`,
+			want: []Ticket{
+				{Ticketer: "KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm", ContentType: `{"prim":"nat"}`, Content: `{"int":"7"}`, Amount: big.NewInt(10)},
+			},
+		}, {
+			name: "tickets in list and option",
+			typ:  `{"prim":"pair","args":[{"prim":"list","args":[{"prim":"ticket","args":[{"prim":"unit"}]}]},{"prim":"option","args":[{"prim":"ticket","args":[{"prim":"string"}]}]}]}`,
+			data: `{"prim":"Pair","args":[[{"prim":"Pair","args":[{"bytes":"016f516588d2ee560385e386708a13bd63da907cf300"},{"prim":"Unit"},{"int":"1"}]}],{"prim":"Some","args":[{"prim":"Pair","args":[{"string":"KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm"},{"string":"b"},{"int":"2"}]}]}]}`,
+			want: []Ticket{
+				{Ticketer: "KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm", ContentType: `{"prim":"unit"}`, Content: `{"prim":"Unit"}`, Amount: big.NewInt(1)},
+				{Ticketer: "KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm", ContentType: `{"prim":"string"}`, Content: `{"string":"b"}`, Amount: big.NewInt(2)},
+			},
+		}, {
+			name: "tickets in map and big map pointer",
+			typ:  `{"prim":"or","args":[{"prim":"map","args":[{"prim":"nat"},{"prim":"ticket","args":[{"prim":"nat"}]}]},{"prim":"big_map","args":[{"prim":"nat"},{"prim":"ticket","args":[{"prim":"nat"}]}]}]}`,
+			data: `{"prim":"Left","args":[[{"prim":"Elt","args":[{"int":"0"},{"prim":"Pair","args":[{"string":"KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm"},{"int":"3"},{"int":"4"}]}]}]]}`,
+			want: []Ticket{
+				{Ticketer: "KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm", ContentType: `{"prim":"nat"}`, Content: `{"int":"3"}`, Amount: big.NewInt(4)},
+			},
+		}, {
+			name: "big map pointer",
+			typ:  `{"prim":"big_map","args":[{"prim":"nat"},{"prim":"ticket","args":[{"prim":"nat"}]}]}`,
+			data: `{"int":"42"}`,
+			want: []Ticket{},
+		}, {
+			name:    "invalid amount",
+			typ:     `{"prim":"ticket","args":[{"prim":"nat"}]}`,
+			data:    `{"prim":"Pair","args":[{"string":"KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm"},{"int":"3"},{"string":"a"}]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Find(gjson.Parse(tt.typ), gjson.Parse(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Find() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("Find() = %v, want %v", got, tt.want)
+				return
+			}
+			for i := range got {
+				if got[i].Ticketer != tt.want[i].Ticketer || got[i].ContentType != tt.want[i].ContentType ||
+					got[i].Content != tt.want[i].Content || got[i].Amount.Cmp(tt.want[i].Amount) != 0 {
+					t.Errorf("Find()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewUpdates(t *testing.T) {
+	ticketer := "KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm"
+	tickets := []Ticket{
+		{Ticketer: ticketer, ContentType: `{"prim":"nat"}`, Content: `{"int":"1"}`, Amount: big.NewInt(10)},
+	}
+	tests := []struct {
+		name     string
+		source   string
+		receiver string
+		want     map[string]int64
+	}{
+		{
+			name:   "minted by ticketer",
+			source: ticketer,
+			want: map[string]int64{
+				"KT1receiver": 10,
+			},
+		}, {
+			name:   "sent by holder",
+			source: "KT1BvVxWM6cjFuJNet4R9m64VDCN2iMvjuGE",
+			want: map[string]int64{
+				"KT1receiver":                          10,
+				"KT1BvVxWM6cjFuJNet4R9m64VDCN2iMvjuGE": -10,
+			},
+		}, {
+			name:     "returned to ticketer",
+			source:   "KT1BvVxWM6cjFuJNet4R9m64VDCN2iMvjuGE",
+			receiver: ticketer,
+			want: map[string]int64{
+				"KT1BvVxWM6cjFuJNet4R9m64VDCN2iMvjuGE": -10,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := tt.receiver
+			if receiver == "" {
+				receiver = "KT1receiver"
+			}
+			op := operation.Operation{ID: "op", Network: "mainnet", Source: tt.source, Level: 100}
+			updates := NewUpdates(op, receiver, tickets)
+			if len(updates) != len(tt.want) {
+				t.Errorf("NewUpdates() count = %d, want %d", len(updates), len(tt.want))
+				return
+			}
+			for _, upd := range updates {
+				want, ok := tt.want[upd.Address]
+				if !ok {
+					t.Errorf("NewUpdates() unexpected address %s", upd.Address)
+					continue
+				}
+				if upd.AmountBigInt.Int64() != want || upd.Level != 100 || upd.OperationID != "op" {
+					t.Errorf("NewUpdates() %s amount = %s, want %d", upd.Address, upd.AmountBigInt, want)
+				}
+			}
+		})
+	}
+}
+
+// memoryTicketBalances - in-memory ticket balances with semantics of storage `Update`
+type memoryTicketBalances map[string]*ticketbalance.TicketBalance
+
+func (m memoryTicketBalances) GetAccountBalances(network, address string) ([]ticketbalance.TicketBalance, error) {
+	return nil, nil
+}
+
+func (m memoryTicketBalances) Update(updates []*ticketbalance.TicketBalance) error {
+	for i := range updates {
+		if balance, ok := m[updates[i].GetID()]; ok {
+			balance.Sum(updates[i])
+			continue
+		}
+		m[updates[i].GetID()] = updates[i]
+	}
+	return nil
+}
+
+func (m memoryTicketBalances) balance(address string, t Ticket) int64 {
+	tb := ticketbalance.TicketBalance{Network: "mainnet", Address: address, Ticketer: t.Ticketer, ContentType: t.ContentType, Content: t.Content}
+	if balance, ok := m[tb.GetID()]; ok {
+		return balance.Value.Int64()
+	}
+	return 0
+}
+
+func TestUpdateBalances(t *testing.T) {
+	ticketer := "KT1JjN5bTE9yayzYHiBm6ruktwEWSHRF8aDm"
+	holderA := "KT1BvVxWM6cjFuJNet4R9m64VDCN2iMvjuGE"
+	holderB := "KT1Ap287P1NzsnToSJdA4aqSNjPomRaHBZSr"
+	minted := Ticket{Ticketer: ticketer, ContentType: `{"prim":"nat"}`, Content: `{"int":"1"}`, Amount: big.NewInt(10)}
+	sent := Ticket{Ticketer: ticketer, ContentType: `{"prim":"nat"}`, Content: `{"int":"1"}`, Amount: big.NewInt(4)}
+
+	repo := make(memoryTicketBalances)
+	mint := NewUpdates(operation.Operation{ID: "mint", Network: "mainnet", Source: ticketer}, holderA, []Ticket{minted})
+	if err := UpdateBalances(repo, mint, false); err != nil {
+		t.Errorf("UpdateBalances() error = %v", err)
+		return
+	}
+	transfer := NewUpdates(operation.Operation{ID: "transfer", Network: "mainnet", Source: holderA}, holderB, []Ticket{sent})
+	if err := UpdateBalances(repo, transfer, false); err != nil {
+		t.Errorf("UpdateBalances() error = %v", err)
+		return
+	}
+
+	want := map[string]int64{ticketer: 0, holderA: 6, holderB: 4}
+	for address, amount := range want {
+		if got := repo.balance(address, minted); got != amount {
+			t.Errorf("balance of %s = %d, want %d", address, got, amount)
+		}
+	}
+
+	if err := UpdateBalances(repo, transfer, true); err != nil {
+		t.Errorf("UpdateBalances() rollback error = %v", err)
+		return
+	}
+	want = map[string]int64{ticketer: 0, holderA: 10, holderB: 0}
+	for address, amount := range want {
+		if got := repo.balance(address, minted); got != amount {
+			t.Errorf("balance of %s after rollback = %d, want %d", address, got, amount)
+		}
+	}
+}
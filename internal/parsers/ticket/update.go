@@ -0,0 +1,58 @@
+package ticket
+
+import (
+	"math/big"
+
+	"github.com/baking-bad/bcdhub/internal/helpers"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
+	"github.com/baking-bad/bcdhub/internal/models/ticketupdate"
+)
+
+// NewUpdates - creates ticket updates for `tickets` sent by operation to `receiver`.
+// Every credit of receiver is paired with debit of sender contract. Ticketer mints its tickets without limit,
+// so balances of ticketer in its own tickets are not tracked: it is not debited on mint and not credited when tickets come back.
+func NewUpdates(op operation.Operation, receiver string, tickets []Ticket) []*ticketupdate.TicketUpdate {
+	updates := make([]*ticketupdate.TicketUpdate, 0)
+	for i := range tickets {
+		if receiver != tickets[i].Ticketer {
+			updates = append(updates, newUpdate(op, receiver, tickets[i], tickets[i].Amount))
+		}
+		if helpers.IsContract(op.Source) && op.Source != tickets[i].Ticketer {
+			updates = append(updates, newUpdate(op, op.Source, tickets[i], big.NewInt(0).Neg(tickets[i].Amount)))
+		}
+	}
+	return updates
+}
+
+func newUpdate(op operation.Operation, address string, t Ticket, amount *big.Int) *ticketupdate.TicketUpdate {
+	return &ticketupdate.TicketUpdate{
+		ID:           helpers.GenerateID(),
+		Network:      op.Network,
+		Level:        op.Level,
+		Timestamp:    op.Timestamp,
+		OperationID:  op.ID,
+		Address:      address,
+		Ticketer:     t.Ticketer,
+		ContentType:  t.ContentType,
+		Content:      t.Content,
+		AmountBigInt: big.NewInt(0).Set(amount),
+	}
+}
+
+// UpdateBalances - sums ticket updates by balance and applies them to balances. If `rollback` is true updates are reverted.
+func UpdateBalances(repo ticketbalance.Repository, updates []*ticketupdate.TicketUpdate, rollback bool) error {
+	exists := make(map[string]*ticketbalance.TicketBalance)
+	balances := make([]*ticketbalance.TicketBalance, 0)
+	for i := range updates {
+		id := updates[i].GetTicketBalanceID()
+		upd := updates[i].MakeTicketBalanceUpdate(rollback)
+		if balance, ok := exists[id]; ok {
+			balance.Sum(upd)
+			continue
+		}
+		exists[id] = upd
+		balances = append(balances, upd)
+	}
+	return repo.Update(balances)
+}
@@ -52,6 +52,6 @@ func (p MultiAsset) Parse(item gjson.Result) (TokenBalance, error) {
 	return TokenBalance{
 		Value:   balance,
 		Address: address,
-		TokenID: item.Get("args.0.args.1.int").Int(),
+		TokenID: item.Get("args.0.args.1.int").String(),
 	}, nil
 }
@@ -28,7 +28,7 @@ type Parser interface {
 // TokenBalance -
 type TokenBalance struct {
 	Address string
-	TokenID int64
+	TokenID string
 	Value   *big.Int
 }
 
@@ -19,6 +19,7 @@ func Test_singleAssetBalanceParser_Parse(t *testing.T) {
 			args: `{"args": [{"string": "test"}, {"int": "100000000000000"}]}`,
 			want: TokenBalance{
 				Address: "test",
+				TokenID: "0",
 				Value:   newBigIntFromString("100000000000000"),
 			},
 		}, {
@@ -26,6 +27,7 @@ func Test_singleAssetBalanceParser_Parse(t *testing.T) {
 			args: `{"args": [{"bytes": "0000c67788ea8ada32b2426e1b02b9ebebdc2dc51007"}, {"int": "1000000000000000"}]}`,
 			want: TokenBalance{
 				Address: "tz1djRgXXWWJiY1rpMECCxr5d9ZBqWewuiU1",
+				TokenID: "0",
 				Value:   newBigIntFromString("1000000000000000"),
 			},
 		},
@@ -62,7 +64,7 @@ func Test_multiAssetBalanceParser_Parse(t *testing.T) {
 			args: `{"args": [{"args": [{"string": "test"}, {"int": "1"}]}, {"int": "1000000000000000"}]}`,
 			want: TokenBalance{
 				Address: "test",
-				TokenID: 1,
+				TokenID: "1",
 				Value:   newBigIntFromString("1000000000000000"),
 			},
 		}, {
@@ -70,7 +72,7 @@ func Test_multiAssetBalanceParser_Parse(t *testing.T) {
 			args: `{"args": [{"args": [{"bytes": "0000c67788ea8ada32b2426e1b02b9ebebdc2dc51007"}, {"int": "1"}]}, {"int": "1000000000000000"}]}`,
 			want: TokenBalance{
 				Address: "tz1djRgXXWWJiY1rpMECCxr5d9ZBqWewuiU1",
-				TokenID: 1,
+				TokenID: "1",
 				Value:   newBigIntFromString("1000000000000000"),
 			},
 		},
@@ -81,7 +83,7 @@ func Test_multiAssetBalanceParser_Parse(t *testing.T) {
 			data := gjson.Parse(tt.args)
 			got, err := p.Parse(data)
 			if err != nil {
-				t.Errorf("Parse error=%w", err)
+				t.Errorf("Parse error=%v", err)
 				return
 			}
 			assert.Equal(t, got, tt.want)
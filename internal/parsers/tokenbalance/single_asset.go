@@ -52,5 +52,6 @@ func (p SingleAsset) Parse(item gjson.Result) (TokenBalance, error) {
 	return TokenBalance{
 		Address: address,
 		Value:   balance,
+		TokenID: "0",
 	}, nil
 }
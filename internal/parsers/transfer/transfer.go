@@ -204,7 +204,7 @@ func (p *Parser) makeFA2Transfers(operation operation.Operation, parameters gjso
 			if err := transfer.SetAmountFromString(to.Get("args.1.args.1.int").String()); err != nil {
 				return nil, fmt.Errorf("makeFA2Transfers error: %s %s %w", operation.Hash, operation.Network, err)
 			}
-			transfer.TokenID = to.Get("args.1.args.0.int").Int()
+			transfer.TokenID = to.Get("args.1.args.0.int").String()
 
 			p.setParentEntrypoint(operation, transfer)
 
@@ -1,9 +1,13 @@
 package tzip
 
-import "time"
+import (
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/ipfs"
+)
 
 // ParserConfig -
 type ParserConfig struct {
-	IPFSGateways []string
-	HTTPTimeout  time.Duration
+	IPFS        ipfs.Resolver
+	HTTPTimeout time.Duration
 }
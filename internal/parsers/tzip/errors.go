@@ -1,8 +1,32 @@
 package tzip
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Errors
 var (
 	ErrUnknownStorageType = errors.New("Unknown storage type")
+	ErrFetch              = errors.New("Can't fetch metadata")
 )
+
+// fetchError - temporary error of metadata loading. It matches both `ErrFetch` and the underlying error.
+type fetchError struct {
+	err error
+}
+
+// Error -
+func (e fetchError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrFetch, e.err)
+}
+
+// Unwrap -
+func (e fetchError) Unwrap() error {
+	return e.err
+}
+
+// Is -
+func (e fetchError) Is(target error) bool {
+	return target == ErrFetch
+}
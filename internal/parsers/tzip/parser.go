@@ -50,34 +50,48 @@ func NewParser(bigMapRepo bigmapdiff.Repository, blocksRepo block.Repository, sc
 }
 
 // Parse -
-func (p *Parser) Parse(ctx ParseContext) (data *tzip.TZIP, err error) {
+func (p *Parser) Parse(ctx ParseContext) (*tzip.TZIP, error) {
 	decoded := tzipStorage.DecodeValue(ctx.BigMapDiff.Value)
 	if decoded == "" {
 		return nil, nil
 	}
 
-	data = &tzip.TZIP{}
-	s := tzipStorage.NewFull(p.bigMapRepo, p.blocksRepo, p.schemaRepo, p.storage, p.rpc, p.cfg.IPFSGateways...)
-	if err := s.Get(ctx.BigMapDiff.Network, ctx.BigMapDiff.Address, decoded, ctx.BigMapDiff.Ptr, data); err != nil {
+	data, err := p.Fetch(ctx.BigMapDiff.Network, ctx.BigMapDiff.Address, decoded, ctx.BigMapDiff.Ptr)
+	if err != nil {
 		switch {
 		case errors.Is(err, tzipStorage.ErrHTTPRequest) || errors.Is(err, tzipStorage.ErrJSONDecoding):
 			logger.With(&ctx.BigMapDiff).Error(err)
 			return nil, nil
 		case errors.Is(err, tzipStorage.ErrNoIPFSResponse):
+			data = &tzip.TZIP{
+				Address: ctx.BigMapDiff.Address,
+				Network: ctx.BigMapDiff.Network,
+			}
 			data.Description = fmt.Sprintf("Failed to fetch metadata %s", decoded)
 			data.Name = "Unknown"
 		default:
 			return nil, err
 		}
 	}
-	if data == nil {
-		return nil, nil
-	}
 
-	data.Address = ctx.BigMapDiff.Address
-	data.Network = ctx.BigMapDiff.Network
 	data.Level = ctx.BigMapDiff.Level
 	data.Timestamp = ctx.BigMapDiff.Timestamp
+	return data, nil
+}
+
+// Fetch - loads metadata of contract by `uri` which is the value of the empty key of `%metadata` big map `ptr`.
+// Failed requests to HTTP server or IPFS are wrapped with `ErrFetch`: they can be retried later.
+func (p *Parser) Fetch(network, address, uri string, ptr int64) (*tzip.TZIP, error) {
+	data := new(tzip.TZIP)
+	s := tzipStorage.NewFull(p.bigMapRepo, p.blocksRepo, p.schemaRepo, p.storage, p.rpc, p.cfg.IPFS)
+	if err := s.Get(network, address, uri, ptr, data); err != nil {
+		if errors.Is(err, tzipStorage.ErrHTTPRequest) || errors.Is(err, tzipStorage.ErrNoIPFSResponse) {
+			return nil, fetchError{err}
+		}
+		return nil, err
+	}
 
-	return
+	data.Address = address
+	data.Network = network
+	return data, nil
 }
@@ -60,7 +60,10 @@ func (s HTTPStorage) Get(value string, output interface{}) error {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return errors.Wrapf(ErrHTTPRequest, "invalid status code: %d", resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
 		return errors.Errorf("Invalid status code: %d", resp.StatusCode)
 	}
 
@@ -1,10 +1,9 @@
 package storage
 
 import (
-	"fmt"
-	"strings"
-	"time"
+	"encoding/json"
 
+	"github.com/baking-bad/bcdhub/internal/ipfs"
 	"github.com/pkg/errors"
 )
 
@@ -15,46 +14,37 @@ const (
 
 // IPFSStorage -
 type IPFSStorage struct {
-	HTTPStorage
-	gateways []string
-}
-
-// IPFSStorageOption -
-type IPFSStorageOption func(*IPFSStorage)
-
-// WithTimeoutIPFS -
-func WithTimeoutIPFS(timeout time.Duration) IPFSStorageOption {
-	return func(s *IPFSStorage) {
-		WithTimeoutHTTP(timeout)(&s.HTTPStorage)
-	}
+	resolver ipfs.Resolver
 }
 
 // NewIPFSStorage -
-func NewIPFSStorage(gateways []string, opts ...IPFSStorageOption) IPFSStorage {
-	s := IPFSStorage{
-		HTTPStorage: NewHTTPStorage(),
-		gateways:    gateways,
-	}
-
-	for i := range opts {
-		opts[i](&s)
+func NewIPFSStorage(resolver ipfs.Resolver) IPFSStorage {
+	return IPFSStorage{
+		resolver: resolver,
 	}
-
-	return s
 }
 
 // Get -
 func (s IPFSStorage) Get(value string, output interface{}) error {
-	if len(s.gateways) == 0 {
+	if s.resolver == nil {
 		return ErrEmptyIPFSGatewayList
 	}
 
-	for i := range s.gateways {
-		url := fmt.Sprintf("%s/ipfs/%s", s.gateways[i], strings.TrimPrefix(value, "ipfs://"))
-		if err := s.HTTPStorage.Get(url, output); err == nil {
-			return nil
+	path, err := ipfs.ParsePath(value)
+	if err != nil {
+		return errors.Wrap(ErrInvalidURI, err.Error())
+	}
+
+	data, err := s.resolver.Resolve(path)
+	if err != nil {
+		if errors.Is(err, ipfs.ErrNoResolvers) {
+			return ErrEmptyIPFSGatewayList
 		}
+		return errors.Wrap(ErrNoIPFSResponse, err.Error())
 	}
 
-	return errors.Wrap(ErrNoIPFSResponse, value)
+	if err := json.Unmarshal(data, output); err != nil {
+		return errors.Wrap(ErrJSONDecoding, err.Error())
+	}
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/baking-bad/bcdhub/internal/ipfs"
 	"github.com/baking-bad/bcdhub/internal/models"
 	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
 	"github.com/baking-bad/bcdhub/internal/models/block"
@@ -14,7 +15,6 @@ import (
 
 const (
 	httpTimeout = time.Second
-	ipfsTimeout = 10 * time.Second
 )
 
 // Storage -
@@ -30,11 +30,11 @@ type Full struct {
 	storage    models.GeneralRepository
 
 	rpc  noderpc.INode
-	ipfs []string
+	ipfs ipfs.Resolver
 }
 
 // NewFull -
-func NewFull(bmdRepo bigmapdiff.Repository, blockRepo block.Repository, schemaRepo schema.Repository, storage models.GeneralRepository, rpc noderpc.INode, ipfs ...string) *Full {
+func NewFull(bmdRepo bigmapdiff.Repository, blockRepo block.Repository, schemaRepo schema.Repository, storage models.GeneralRepository, rpc noderpc.INode, ipfs ipfs.Resolver) *Full {
 	return &Full{
 		bmdRepo, blockRepo, schemaRepo, storage, rpc, ipfs,
 	}
@@ -49,10 +49,7 @@ func (f Full) Get(network, address, url string, ptr int64, output interface{}) e
 			WithTimeoutHTTP(httpTimeout),
 		)
 	case strings.HasPrefix(url, PrefixIPFS):
-		store = NewIPFSStorage(
-			f.ipfs,
-			WithTimeoutIPFS(ipfsTimeout),
-		)
+		store = NewIPFSStorage(f.ipfs)
 	case strings.HasPrefix(url, PrefixSHA256):
 		store = NewSha256Storage(
 			WithTimeoutSha256(httpTimeout),
@@ -92,6 +92,30 @@ func (s TezosStorage) Get(value string, output interface{}) error {
 	return json.Unmarshal(decoded, output)
 }
 
+// Reference - returns contract and hash of big map key which `tezos-storage:` URI refers to. Contract is `address` if URI does not contain it.
+// Empty values are returned if `value` is not `tezos-storage:` URI or it refers to another network.
+func Reference(value, address string) (string, string, error) {
+	var uri TezosStorageURI
+	if err := uri.Parse(value); err != nil {
+		if errors.Is(err, ErrInvalidTezosStoragePrefix) {
+			return "", "", nil
+		}
+		return "", "", err
+	}
+	if uri.Network != "" {
+		return "", "", nil
+	}
+	if uri.Address != "" {
+		address = uri.Address
+	}
+
+	key, err := hash.Key(gjson.Parse(fmt.Sprintf(`{"string": "%s"}`, uri.Key)))
+	if err != nil {
+		return "", "", err
+	}
+	return address, key, nil
+}
+
 func (s *TezosStorage) fillFields(uri TezosStorageURI) error {
 	if uri.Network != "" {
 		s.network = uri.Network
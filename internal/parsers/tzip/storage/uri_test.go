@@ -121,3 +121,46 @@ func TestTezosStorageURI_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestReference(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		address     string
+		wantAddress string
+		wantKeyHash string
+	}{
+		{
+			name:        "key of the same contract",
+			value:       "tezos-storage:here",
+			address:     "KT1TbHFZF8yFJJMuFRnX6SJaqz4m5yhBTaoU",
+			wantAddress: "KT1TbHFZF8yFJJMuFRnX6SJaqz4m5yhBTaoU",
+			wantKeyHash: "expruaHzyjwFcmFKHqR49qdxwJupAna6ygSKo2mFJQtqZQjid5t8GK",
+		}, {
+			name:        "key of another contract",
+			value:       "tezos-storage://KT1QDFEu8JijYbsJqzoXq7mKvfaQQamHD1kX/here",
+			address:     "KT1TbHFZF8yFJJMuFRnX6SJaqz4m5yhBTaoU",
+			wantAddress: "KT1QDFEu8JijYbsJqzoXq7mKvfaQQamHD1kX",
+			wantKeyHash: "expruaHzyjwFcmFKHqR49qdxwJupAna6ygSKo2mFJQtqZQjid5t8GK",
+		}, {
+			name:    "key of another network",
+			value:   "tezos-storage://KT1QDFEu8JijYbsJqzoXq7mKvfaQQamHD1kX.NetXdQprcVkpaWU/here",
+			address: "KT1TbHFZF8yFJJMuFRnX6SJaqz4m5yhBTaoU",
+		}, {
+			name:    "not tezos storage",
+			value:   "ipfs://QmWqnV2JEKc2Vb7Kuiq5wfXTnbH6bjt2Z6ZuGCL8KjwbpQ",
+			address: "KT1TbHFZF8yFJJMuFRnX6SJaqz4m5yhBTaoU",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, keyHash, err := Reference(tt.value, tt.address)
+			if err != nil {
+				t.Errorf("Reference() error = %v", err)
+				return
+			}
+			assert.Equal(t, tt.wantAddress, address)
+			assert.Equal(t, tt.wantKeyHash, keyHash)
+		})
+	}
+}
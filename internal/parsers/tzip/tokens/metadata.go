@@ -25,7 +25,7 @@ const (
 type TokenMetadata struct {
 	Level     int64
 	Timestamp time.Time
-	TokenID   int64
+	TokenID   string
 	Symbol    string
 	Name      string
 	Decimals  *int64
@@ -63,7 +63,7 @@ func (m *TokenMetadata) Parse(value gjson.Result, address string, ptr int64) err
 		return ErrInvalidStorageStructure
 	}
 
-	m.TokenID = tokenID.Int()
+	m.TokenID = tokenID.String()
 
 	m.Extras = make(map[string]interface{})
 	for _, item := range arr.Array() {
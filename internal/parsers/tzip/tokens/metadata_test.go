@@ -20,7 +20,7 @@ func TestTokenMetadata_Parse(t *testing.T) {
 			wantErr: false,
 			want: &TokenMetadata{
 				Link:    "ipfs://QmT63cK5XJiCdPGCjXjRabcAgdjxxuqMMgU6yAmLnaxEZ5",
-				TokenID: 0,
+				TokenID: "0",
 				Extras:  make(map[string]interface{}),
 			},
 		}, {
@@ -28,7 +28,7 @@ func TestTokenMetadata_Parse(t *testing.T) {
 			value:   `{"prim":"Pair","args":[{"int":"1"},[{"prim":"Elt","args":[{"string":"decimals"},{"bytes":"36"}]},{"prim":"Elt","args":[{"string":"name"},{"bytes":"4e616d65"}]},{"prim":"Elt","args":[{"string":"symbol"},{"bytes":"534d42"}]}]]}`,
 			wantErr: false,
 			want: &TokenMetadata{
-				TokenID:  1,
+				TokenID:  "1",
 				Decimals: getIntPtr(6),
 				Name:     "Name",
 				Symbol:   "SMB",
@@ -39,7 +39,7 @@ func TestTokenMetadata_Parse(t *testing.T) {
 			value:   `{"prim":"Pair","args":[{"int":"2"},[{"prim":"Elt","args":[{"string":""},{"bytes":"74657a6f732d73746f726167653a636f6e74656e74"}]},{"prim":"Elt","args":[{"string":"content"},{"bytes":"7b226e616d65223a20224e616d65222c202273796d626f6c223a2022534d42222c2022646563696d616c73223a20367d"}]}]]}`,
 			wantErr: false,
 			want: &TokenMetadata{
-				TokenID: 2,
+				TokenID: "2",
 				Extras: map[string]interface{}{
 					"content": "{\"name\": \"Name\", \"symbol\": \"SMB\", \"decimals\": 6}",
 				},
@@ -65,7 +65,7 @@ func TestTokenMetadata_Parse(t *testing.T) {
 			value:   `{"prim":"Pair","args":[{"int":"0"},[{"prim":"Elt","args":[{"string":"artifactUri"},{"bytes":"68747470733a2f2f636c6f7564666c6172652d697066732e636f6d2f697066732f516d53395634504b536a516838687a79517a52714b46786b4363535931794c755851594b7837596f54794a595965"}]},{"prim":"Elt","args":[{"string":"booleanAmount"},{"bytes":"74727565"}]},{"prim":"Elt","args":[{"string":"decimals"},{"bytes":"30"}]},{"prim":"Elt","args":[{"string":"displayUri"},{"bytes":"68747470733a2f2f636c6f7564666c6172652d697066732e636f6d2f697066732f516d53395634504b536a516838687a79517a52714b46786b4363535931794c755851594b7837596f54794a595965"}]},{"prim":"Elt","args":[{"string":"name"},{"bytes":"4361742044726177696e67"}]}]]}`,
 			wantErr: false,
 			want: &TokenMetadata{
-				TokenID:  0,
+				TokenID:  "0",
 				Decimals: getIntPtr(0),
 				Name:     "Cat Drawing",
 				Extras: map[string]interface{}{
@@ -74,6 +74,15 @@ func TestTokenMetadata_Parse(t *testing.T) {
 					"displayUri":    "https://cloudflare-ipfs.com/ipfs/QmS9V4PKSjQh8hzyQzRqKFxkCcSY1yLuXQYKx7YoTyJYYe",
 				},
 			},
+		}, {
+			name:    "test 8: token ID greater than int64",
+			value:   `{"prim":"Pair","args":[{"int":"340282366920938463463374607431768211456"},[{"prim":"Elt","args":[{"string":"name"},{"bytes":"4e616d65"}]}]]}`,
+			wantErr: false,
+			want: &TokenMetadata{
+				TokenID: "340282366920938463463374607431768211456",
+				Name:    "Name",
+				Extras:  make(map[string]interface{}),
+			},
 		},
 	}
 	for _, tt := range tests {
@@ -111,7 +120,7 @@ func TestTokenMetadata_Merge(t *testing.T) {
 				Symbol:   "symbol",
 				Name:     "name",
 				Decimals: getIntPtr(10),
-				TokenID:  10,
+				TokenID:  "10",
 			},
 			want: &TokenMetadata{
 				Symbol:   "symbol",
@@ -125,13 +134,13 @@ func TestTokenMetadata_Merge(t *testing.T) {
 				Symbol:   "symbol",
 				Name:     "name",
 				Decimals: getIntPtr(10),
-				TokenID:  10,
+				TokenID:  "10",
 			},
 			want: &TokenMetadata{
 				Symbol:   "symbol",
 				Name:     "name",
 				Decimals: getIntPtr(10),
-				TokenID:  10,
+				TokenID:  "10",
 			},
 		}, {
 			name: "test 2",
@@ -139,19 +148,19 @@ func TestTokenMetadata_Merge(t *testing.T) {
 				Symbol:   "symbol old",
 				Name:     "name old",
 				Decimals: getIntPtr(9),
-				TokenID:  11,
+				TokenID:  "11",
 			},
 			second: &TokenMetadata{
 				Symbol:   "symbol",
 				Name:     "name",
 				Decimals: getIntPtr(10),
-				TokenID:  10,
+				TokenID:  "10",
 			},
 			want: &TokenMetadata{
 				Symbol:   "symbol",
 				Name:     "name",
 				Decimals: getIntPtr(10),
-				TokenID:  11,
+				TokenID:  "11",
 			},
 		}, {
 			name: "test 2",
@@ -159,7 +168,7 @@ func TestTokenMetadata_Merge(t *testing.T) {
 				Symbol:   "symbol old",
 				Name:     "name old",
 				Decimals: getIntPtr(9),
-				TokenID:  11,
+				TokenID:  "11",
 				Extras: map[string]interface{}{
 					"test": "1234",
 					"a":    "234",
@@ -169,7 +178,7 @@ func TestTokenMetadata_Merge(t *testing.T) {
 				Symbol:   "symbol",
 				Name:     "name",
 				Decimals: getIntPtr(10),
-				TokenID:  10,
+				TokenID:  "10",
 				Extras: map[string]interface{}{
 					"test": "12345",
 					"b":    "234",
@@ -179,7 +188,7 @@ func TestTokenMetadata_Merge(t *testing.T) {
 				Symbol:   "symbol",
 				Name:     "name",
 				Decimals: getIntPtr(10),
-				TokenID:  11,
+				TokenID:  "11",
 				Extras: map[string]interface{}{
 					"test": "12345",
 					"a":    "234",
@@ -4,6 +4,7 @@ import (
 	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
 	"github.com/baking-bad/bcdhub/internal/contractparser/meta"
 	"github.com/baking-bad/bcdhub/internal/contractparser/storage"
+	"github.com/baking-bad/bcdhub/internal/ipfs"
 	"github.com/baking-bad/bcdhub/internal/logger"
 	"github.com/baking-bad/bcdhub/internal/models"
 	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
@@ -28,11 +29,11 @@ type Parser struct {
 	rpc       noderpc.INode
 	sharePath string
 	network   string
-	ipfs      []string
+	ipfs      ipfs.Resolver
 }
 
 // NewParser -
-func NewParser(bmdRepo bigmapdiff.Repository, blocksRepo block.Repository, protocolRepo protocol.Repository, schemaRepo schema.Repository, storage models.GeneralRepository, rpc noderpc.INode, sharePath, network string, ipfs ...string) Parser {
+func NewParser(bmdRepo bigmapdiff.Repository, blocksRepo block.Repository, protocolRepo protocol.Repository, schemaRepo schema.Repository, storage models.GeneralRepository, rpc noderpc.INode, sharePath, network string, ipfs ipfs.Resolver) Parser {
 	return Parser{
 		bmdRepo: bmdRepo, blocksRepo: blocksRepo, storage: storage, protocolRepo: protocolRepo, schemaRepo: schemaRepo,
 		rpc: rpc, sharePath: sharePath, network: network, ipfs: ipfs,
@@ -71,7 +72,7 @@ func (t Parser) parseBigMapDiff(bmd *bigmapdiff.BigMapDiff, state block.Block) (
 	m.Level = bmd.Level
 
 	if m.Link != "" {
-		s := tzipStorage.NewFull(t.bmdRepo, t.blocksRepo, t.schemaRepo, t.storage, t.rpc, t.ipfs...)
+		s := tzipStorage.NewFull(t.bmdRepo, t.blocksRepo, t.schemaRepo, t.storage, t.rpc, t.ipfs)
 
 		remoteMetadata := &TokenMetadata{}
 		if err := s.Get(t.network, bmd.Address, m.Link, bmd.Ptr, remoteMetadata); err != nil {
@@ -127,7 +128,7 @@ func (t Parser) parse(address string, state block.Block) ([]tokenmetadata.TokenM
 	result := make([]tokenmetadata.TokenMetadata, 0)
 	for _, m := range metadata {
 		if m.Link != "" {
-			s := tzipStorage.NewFull(t.bmdRepo, t.blocksRepo, t.schemaRepo, t.storage, t.rpc, t.ipfs...)
+			s := tzipStorage.NewFull(t.bmdRepo, t.blocksRepo, t.schemaRepo, t.storage, t.rpc, t.ipfs)
 
 			remoteMetadata := &TokenMetadata{}
 			if err := s.Get(t.network, address, m.Link, ptr, remoteMetadata); err != nil {
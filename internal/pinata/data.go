@@ -30,3 +30,15 @@ type PinJSONResponse struct {
 	PinSize   int       `json:"PinSize"`
 	Timestamp time.Time `json:"Timestamp"`
 }
+
+type pinByHashRequest struct {
+	HashToPin string `json:"hashToPin"`
+}
+
+// PinByHashResponse -
+type PinByHashResponse struct {
+	ID       string `json:"id"`
+	IpfsHash string `json:"ipfsHash"`
+	Status   string `json:"status"`
+	Name     string `json:"name"`
+}
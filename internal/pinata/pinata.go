@@ -1,6 +1,7 @@
 package pinata
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -18,6 +19,7 @@ type Service interface {
 	PinList() (PinList, error)
 	PinJSONToIPFS(data io.Reader) (PinJSONResponse, error)
 	UnPin(hash string) error
+	PinByHash(hash string) (PinByHashResponse, error)
 }
 
 // Pinata -
@@ -100,6 +102,31 @@ func (p *Pinata) UnPin(hash string) error {
 	return fmt.Errorf("%s", string(data))
 }
 
+// PinByHash - https://pinata.cloud/documentation#PinByHash
+func (p *Pinata) PinByHash(hash string) (PinByHashResponse, error) {
+	var ret PinByHashResponse
+	body, err := json.Marshal(pinByHashRequest{HashToPin: hash})
+	if err != nil {
+		return ret, err
+	}
+	response, err := p.request("POST", "pinning/pinByHash", bytes.NewReader(body), make(map[string]string))
+	if err != nil {
+		return ret, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK {
+		return ret, json.NewDecoder(response.Body).Decode(&ret)
+	}
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return ret, err
+	}
+
+	return ret, fmt.Errorf("%s", string(data))
+}
+
 func (p *Pinata) request(method, endpoint string, body io.Reader, params map[string]string) (*http.Response, error) {
 	url := helpers.URLJoin(baseURL, endpoint)
 
@@ -0,0 +1,26 @@
+package balanceupdate
+
+import (
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// GetBalance -
+func (storage *Storage) GetBalance(network, address string) (int64, error) {
+	query := storage.db.Query(models.DocBalanceUpdates).
+		Where("network = ?", network).
+		Where(core.Eq("contract"), address)
+
+	balance, err := storage.db.Sum(query, "change")
+	return int64(balance), err
+}
@@ -0,0 +1,31 @@
+package bigmapaction
+
+import (
+	"fmt"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/bigmapaction"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// Get -
+func (storage *Storage) Get(ptr int64, network string) ([]bigmapaction.BigMapAction, error) {
+	query := storage.db.Query(models.DocBigMapActions).
+		Where("network = ?", network).
+		Where(fmt.Sprintf("%s = ? OR %s = ?", core.IntField("source_ptr"), core.IntField("destination_ptr")), ptr, ptr).
+		Order(core.Order("indexed_time", true))
+
+	result := make([]bigmapaction.BigMapAction, 0)
+	err := storage.db.GetAllByQuery(query, &result)
+	return result, err
+}
@@ -0,0 +1,45 @@
+package bigmapdiff
+
+import (
+	"fmt"
+
+	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+	"github.com/jinzhu/gorm"
+)
+
+func buildGetContext(ctx *bigmapdiff.GetContext, query *gorm.DB) *gorm.DB {
+	if ctx.Ptr != nil {
+		query = query.Where(fmt.Sprintf("%s = ?", core.IntField("ptr")), *ctx.Ptr)
+	}
+	if ctx.Network != "" {
+		query = query.Where("network = ?", ctx.Network)
+	}
+
+	if ctx.Query != "" {
+		query = core.ILike(ctx.Query, "key", "key_hash", "key_strings", "bin_path", "value", "value_strings").Apply(query)
+	}
+
+	if ctx.Size == 0 {
+		ctx.Size = core.DefaultSize
+	}
+
+	if ctx.MaxLevel != nil {
+		query = query.Where("level <= ?", *ctx.MaxLevel)
+	}
+
+	if ctx.MinLevel != nil {
+		query = query.Where("level >= ?", *ctx.MinLevel)
+	}
+
+	if ctx.CurrentLevel != nil {
+		query = query.Where("level = ?", *ctx.CurrentLevel)
+	}
+
+	if ctx.Contract != "" {
+		query = query.Where(core.Eq("address"), ctx.Contract)
+	}
+
+	ctx.To = ctx.Size + ctx.Offset
+	return query
+}
@@ -0,0 +1,243 @@
+package bigmapdiff
+
+import (
+	"fmt"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+func ptrCondition() string {
+	return fmt.Sprintf("%s = ?", core.IntField("ptr"))
+}
+
+// CurrentByKey -
+func (storage *Storage) CurrentByKey(network, keyHash string, ptr int64) (data bigmapdiff.BigMapDiff, err error) {
+	if ptr < 0 {
+		err = errors.Errorf("Invalid pointer value: %d", ptr)
+		return
+	}
+
+	query := storage.db.Query(models.DocBigMapDiff).
+		Where("network = ?", network).
+		Where(core.Eq("key_hash"), keyHash).
+		Where(ptrCondition(), ptr).
+		Order("level desc")
+
+	err = storage.db.GetOne(query, &data)
+	return
+}
+
+// CurrentByKeyAtLevel - returns the last diff of the key at or before `level`
+func (storage *Storage) CurrentByKeyAtLevel(network, keyHash string, ptr, level int64) (data bigmapdiff.BigMapDiff, err error) {
+	if ptr < 0 {
+		err = errors.Errorf("Invalid pointer value: %d", ptr)
+		return
+	}
+
+	query := storage.db.Query(models.DocBigMapDiff).
+		Where("network = ?", network).
+		Where(core.Eq("key_hash"), keyHash).
+		Where(ptrCondition(), ptr).
+		Where("level <= ?", level).
+		Order("level desc")
+
+	err = storage.db.GetOne(query, &data)
+	return
+}
+
+// GetForAddress -
+func (storage *Storage) GetForAddress(address string) ([]bigmapdiff.BigMapDiff, error) {
+	query := storage.db.Query(models.DocBigMapDiff).
+		Where(core.Eq("address"), address).
+		Order(core.Order("indexed_time", true))
+
+	return storage.getTop(query, func(bmd bigmapdiff.BigMapDiff) string {
+		return bmd.KeyHash
+	})
+}
+
+// GetByAddress -
+func (storage *Storage) GetByAddress(network, address string) ([]bigmapdiff.BigMapDiff, error) {
+	query := storage.db.Query(models.DocBigMapDiff).
+		Where("network = ?", network).
+		Where(core.Eq("address"), address).
+		Order(core.Order("indexed_time", true))
+
+	response := make([]bigmapdiff.BigMapDiff, 0)
+	err := storage.db.GetAllByQuery(query, &response)
+	return response, err
+}
+
+// GetValuesByKey -
+func (storage *Storage) GetValuesByKey(keyHash string) ([]bigmapdiff.BigMapDiff, error) {
+	query := storage.db.Query(models.DocBigMapDiff).
+		Where(core.Eq("key_hash"), keyHash).
+		Order(core.Order("indexed_time", true))
+
+	return storage.getTop(query, func(bmd bigmapdiff.BigMapDiff) string {
+		return fmt.Sprintf("%s_%s_%d", bmd.Network, bmd.Address, bmd.Ptr)
+	})
+}
+
+// Count -
+func (storage *Storage) Count(network string, ptr int64) (count int64, err error) {
+	err = storage.db.Query(models.DocBigMapDiff).
+		Where("network = ?", network).
+		Where(ptrCondition(), ptr).
+		Select(fmt.Sprintf("count(DISTINCT %s)", core.Field("key_hash"))).
+		Row().
+		Scan(&count)
+	return
+}
+
+// Previous -
+func (storage *Storage) Previous(filters []bigmapdiff.BigMapDiff, indexedTime int64, address string) ([]bigmapdiff.BigMapDiff, error) {
+	query := storage.db.Query(models.DocBigMapDiff).
+		Where(core.Eq("address"), address).
+		Where(fmt.Sprintf("%s < ?", core.IntField("indexed_time")), indexedTime)
+
+	if len(filters) > 0 {
+		conditions := make([]core.Condition, len(filters))
+		for i := range filters {
+			conditions[i] = core.NewCondition(
+				fmt.Sprintf("%s AND %s", core.Eq("key_hash"), core.Eq("bin_path")),
+				filters[i].KeyHash, filters[i].BinPath,
+			)
+		}
+		query = core.Or(conditions...).Apply(query)
+	}
+	query = query.Order(core.Order("indexed_time", true))
+
+	return storage.getTop(query, func(bmd bigmapdiff.BigMapDiff) string {
+		return fmt.Sprintf("%s_%s", bmd.KeyHash, bmd.BinPath)
+	})
+}
+
+// GetUniqueByOperationID -
+func (storage *Storage) GetUniqueByOperationID(operationID string) ([]bigmapdiff.BigMapDiff, error) {
+	query := storage.db.Query(models.DocBigMapDiff).
+		Where(core.Eq("operation_id"), operationID).
+		Order(core.Order("indexed_time", true))
+
+	return storage.getTop(query, func(bmd bigmapdiff.BigMapDiff) string {
+		return fmt.Sprintf("%d_%s", bmd.Ptr, bmd.KeyHash)
+	})
+}
+
+// GetByPtrAndKeyHash -
+func (storage *Storage) GetByPtrAndKeyHash(ptr int64, network, keyHash string, size, offset int64) ([]bigmapdiff.BigMapDiff, int64, error) {
+	if ptr < 0 {
+		return nil, 0, errors.Errorf("Invalid pointer value: %d", ptr)
+	}
+	if size == 0 {
+		size = core.DefaultSize
+	}
+
+	query := storage.db.Query(models.DocBigMapDiff).
+		Where("network = ?", network).
+		Where(core.Eq("key_hash"), keyHash).
+		Where(ptrCondition(), ptr)
+
+	total, err := storage.db.Count(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]bigmapdiff.BigMapDiff, 0)
+	err = storage.db.GetAllByQuery(query.Order("level desc").Limit(size).Offset(offset), &result)
+	return result, total, err
+}
+
+// GetByOperationID -
+func (storage *Storage) GetByOperationID(operationID string) ([]*bigmapdiff.BigMapDiff, error) {
+	query := storage.db.Query(models.DocBigMapDiff).
+		Where(core.Eq("operation_id"), operationID)
+
+	result := make([]*bigmapdiff.BigMapDiff, 0)
+	err := storage.db.GetAllByQuery(query, &result)
+	return result, err
+}
+
+// GetByPtr -
+func (storage *Storage) GetByPtr(address, network string, ptr int64) ([]bigmapdiff.BigMapDiff, error) {
+	query := storage.db.Query(models.DocBigMapDiff).
+		Where("network = ?", network).
+		Where(core.Eq("address"), address).
+		Where(ptrCondition(), ptr).
+		Order(core.Order("indexed_time", true))
+
+	return storage.getTop(query, func(bmd bigmapdiff.BigMapDiff) string {
+		return bmd.KeyHash
+	})
+}
+
+// Get - returns the latest diff of every key matched `ctx` sorted by time of the last change
+func (storage *Storage) Get(ctx bigmapdiff.GetContext) ([]bigmapdiff.Bucket, error) {
+	if ctx.Ptr != nil && *ctx.Ptr < 0 {
+		return nil, errors.Errorf("Invalid pointer value: %d", *ctx.Ptr)
+	}
+
+	query := buildGetContext(&ctx, storage.db.Query(models.DocBigMapDiff))
+
+	keyHash := core.Field("key_hash")
+	indexedTime := core.IntField("indexed_time")
+	subQuery := query.
+		Select(fmt.Sprintf("DISTINCT ON (%s) id, data, %s AS indexed_time, count(*) OVER (PARTITION BY %s) AS total", keyHash, indexedTime, keyHash)).
+		Order(fmt.Sprintf("%s, %s desc", keyHash, indexedTime))
+
+	rows, err := storage.db.
+		Raw("SELECT id, data, total FROM (?) AS keys ORDER BY indexed_time desc OFFSET ? LIMIT ?", subQuery.QueryExpr(), ctx.Offset, ctx.Size).
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]bigmapdiff.Bucket, 0)
+	for rows.Next() {
+		var id string
+		var data []byte
+		var bucket bigmapdiff.Bucket
+		if err := rows.Scan(&id, &data, &bucket.Count); err != nil {
+			return nil, err
+		}
+		if err := core.Decode(id, data, &bucket.BigMapDiff); err != nil {
+			return nil, err
+		}
+		result = append(result, bucket)
+	}
+	return result, rows.Err()
+}
+
+func (storage *Storage) getTop(query *gorm.DB, idFunc func(bigmapdiff.BigMapDiff) string) ([]bigmapdiff.BigMapDiff, error) {
+	all := make([]bigmapdiff.BigMapDiff, 0)
+	if err := storage.db.GetAllByQuery(query, &all); err != nil {
+		return nil, err
+	}
+
+	response := make([]bigmapdiff.BigMapDiff, 0)
+	found := make(map[string]struct{})
+	for i := range all {
+		id := idFunc(all[i])
+		if _, ok := found[id]; ok {
+			continue
+		}
+		found[id] = struct{}{}
+		response = append(response, all[i])
+	}
+	return response, nil
+}
@@ -0,0 +1,61 @@
+package block
+
+import (
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/block"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// Get -
+func (storage *Storage) Get(network string, level int64) (block block.Block, err error) {
+	block.Network = network
+
+	query := storage.db.Query(models.DocBlocks).
+		Where("network = ? AND level = ?", network, level)
+
+	err = storage.db.GetOne(query, &block)
+	return
+}
+
+// Last - returns current indexer state for network
+func (storage *Storage) Last(network string) (block block.Block, err error) {
+	query := storage.db.Query(models.DocBlocks).
+		Where("network = ?", network).
+		Order("level desc")
+
+	if err = storage.db.GetOne(query, &block); err != nil && storage.db.IsRecordNotFound(err) {
+		block.Network = network
+		return block, nil
+	}
+	return
+}
+
+// LastByNetworks - return last block for all networks
+func (storage *Storage) LastByNetworks() ([]block.Block, error) {
+	query := storage.db.Query(models.DocBlocks).
+		Where("(network, level) IN (SELECT network, max(level) FROM block GROUP BY network)")
+
+	response := make([]block.Block, 0)
+	err := storage.db.GetAllByQuery(query, &response)
+	return response, err
+}
+
+// GetNetworkAlias -
+func (storage *Storage) GetNetworkAlias(chainID string) (string, error) {
+	query := storage.db.Query(models.DocBlocks).
+		Where(core.Eq("chain_id"), chainID)
+
+	var block block.Block
+	err := storage.db.GetOne(query, &block)
+	return block.Network, err
+}
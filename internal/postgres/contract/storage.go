@@ -0,0 +1,374 @@
+package contract
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/contract"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// Get -
+func (storage *Storage) Get(by map[string]interface{}) (c contract.Contract, err error) {
+	query, err := core.FiltersToQuery(storage.db.Query(models.DocContracts), by)
+	if err != nil {
+		return
+	}
+	if err = storage.db.GetOne(query, &c); err != nil && storage.db.IsRecordNotFound(err) {
+		err = core.NewRecordNotFoundError(models.DocContracts, "")
+	}
+	return
+}
+
+// GetMany -
+func (storage *Storage) GetMany(by map[string]interface{}) ([]contract.Contract, error) {
+	query, err := core.FiltersToQuery(storage.db.Query(models.DocContracts), by)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts := make([]contract.Contract, 0)
+	err = storage.db.GetAllByQuery(query, &contracts)
+	return contracts, err
+}
+
+// GetPage - returns up to `size` contracts of `network` with address greater than `lastAddress` sorted by address
+func (storage *Storage) GetPage(network, lastAddress string, size int64) ([]contract.Contract, error) {
+	query := storage.db.Query(models.DocContracts).
+		Where("network = ?", network).
+		Where(fmt.Sprintf("%s > ?", core.Field("address")), lastAddress).
+		Order(core.Order("address", false)).
+		Limit(size)
+
+	contracts := make([]contract.Contract, 0)
+	err := storage.db.GetAllByQuery(query, &contracts)
+	return contracts, err
+}
+
+// GetRandom -
+func (storage *Storage) GetRandom(network string) (c contract.Contract, err error) {
+	query := storage.db.Query(models.DocContracts).
+		Where(fmt.Sprintf("%s >= 2", core.IntField("tx_count")))
+	if network != "" {
+		query = query.Where("network = ?", network)
+	}
+
+	if err = storage.db.GetOne(query.Order("random()"), &c); err != nil && storage.db.IsRecordNotFound(err) {
+		err = core.NewRecordNotFoundError(models.DocContracts, "")
+	}
+	return
+}
+
+// IsFA -
+func (storage *Storage) IsFA(network, address string) (bool, error) {
+	query := storage.db.Query(models.DocContracts).
+		Where("network = ?", network).
+		Where(core.Eq("address"), address)
+
+	count, err := storage.db.Count(core.ContainsAny("tags", "fa12", "fa1").Apply(query))
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+// UpdateMigrationsCount -
+func (storage *Storage) UpdateMigrationsCount(address, network string) error {
+	contract := contract.NewEmptyContract(network, address)
+	if err := storage.db.GetByID(&contract); err != nil {
+		return err
+	}
+	contract.MigrationsCount++
+	return storage.db.UpdateDoc(&contract)
+}
+
+// GetAddressesByNetworkAndLevel -
+func (storage *Storage) GetAddressesByNetworkAndLevel(network string, maxLevel int64) ([]string, error) {
+	addresses := make([]string, 0)
+	err := storage.db.Query(models.DocContracts).
+		Where("network = ?", network).
+		Where("level > ?", maxLevel).
+		Pluck(core.Field("address"), &addresses).Error
+	return addresses, err
+}
+
+// GetIDsByAddresses -
+func (storage *Storage) GetIDsByAddresses(addresses []string, network string) ([]string, error) {
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0)
+	err := storage.db.Query(models.DocContracts).
+		Where("network = ?", network).
+		Where(core.In("address"), addresses).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetByAddresses -
+func (storage *Storage) GetByAddresses(addresses []contract.Address) ([]contract.Contract, error) {
+	contracts := make([]contract.Contract, 0)
+	if len(addresses) == 0 {
+		return contracts, nil
+	}
+
+	conditions := make([]core.Condition, len(addresses))
+	for i := range addresses {
+		conditions[i] = core.NewCondition(fmt.Sprintf("network = ? AND %s", core.Eq("address")), addresses[i].Network, addresses[i].Address)
+	}
+
+	query := core.Or(conditions...).Apply(storage.db.Query(models.DocContracts))
+	err := storage.db.GetAllByQuery(query, &contracts)
+	return contracts, err
+}
+
+// GetProjectsLastContract -
+func (storage *Storage) GetProjectsLastContract(c *contract.Contract) ([]contract.Contract, error) {
+	query := storage.db.Query(models.DocContracts)
+
+	if c != nil {
+		conditions := make([]core.Condition, 0)
+		if c.Manager != "" {
+			conditions = append(conditions, core.NewCondition(core.Eq("manager"), c.Manager))
+		}
+		if c.Language != "" {
+			conditions = append(conditions, core.NewCondition(core.Eq("language"), c.Language))
+		}
+		for _, filter := range []struct {
+			field  string
+			values []string
+		}{
+			{"tags", c.Tags},
+			{"annotations", c.Annotations},
+			{"fail_strings", c.FailStrings},
+			{"entrypoints", c.Entrypoints},
+		} {
+			if len(filter.values) > 0 {
+				conditions = append(conditions, arrayFilter(filter.field, filter.values))
+			}
+		}
+		if c.Fingerprint != nil {
+			conditions = append(conditions, core.NewCondition(
+				fmt.Sprintf("%s AND %s AND %s", core.Eq("fingerprint.parameter"), core.Eq("fingerprint.storage"), core.Eq("fingerprint.code")),
+				c.Fingerprint.Parameter, c.Fingerprint.Storage, c.Fingerprint.Code,
+			))
+		}
+		query = core.Or(conditions...).Apply(query)
+	}
+
+	contracts, err := storage.topContracts(query.Order(core.Order("timestamp", true)), func(c contract.Contract) string {
+		return c.ProjectID
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(contracts) == 0 {
+		return nil, core.NewRecordNotFoundError(models.DocContracts, "")
+	}
+	return contracts, nil
+}
+
+// arrayFilter - matches documents which array `field` contains at least a half of `values`
+func arrayFilter(field string, values []string) core.Condition {
+	return core.NewCondition(
+		fmt.Sprintf("(SELECT count(*) FROM jsonb_array_elements_text(coalesce(%s, '[]'::jsonb)) AS item WHERE item IN (?)) >= ?", core.JSONField(field)),
+		values, len(values)/2,
+	)
+}
+
+// GetSameContracts -
+func (storage *Storage) GetSameContracts(c contract.Contract, manager string, size, offset int64) (pcr contract.SameResponse, err error) {
+	if c.Fingerprint == nil {
+		return pcr, errors.Errorf("Invalid contract data")
+	}
+
+	if size == 0 {
+		size = core.DefaultSize
+	} else if size+offset > core.MaxQuerySize {
+		size = core.MaxQuerySize - offset
+	}
+
+	query := storage.db.Query(models.DocContracts).
+		Where(core.Eq("hash"), c.Hash).
+		Where(fmt.Sprintf("%s <> ?", core.Field("address")), c.Address)
+	if manager != "" {
+		query = query.Where(core.Eq("manager"), manager)
+	}
+	query = query.Order(core.Order("last_action", true)).Limit(size).Offset(offset)
+
+	pcr.Contracts = make([]contract.Contract, 0)
+	if pcr.Count, err = storage.db.GetAllByQueryWithTotal(query, &pcr.Contracts); err != nil {
+		return
+	}
+	if len(pcr.Contracts) == 0 {
+		return pcr, core.NewRecordNotFoundError(models.DocContracts, "")
+	}
+	return
+}
+
+// GetSimilarContracts -
+func (storage *Storage) GetSimilarContracts(c contract.Contract, size, offset int64) ([]contract.Similar, int, error) {
+	if c.Fingerprint == nil {
+		return nil, 0, nil
+	}
+
+	if size == 0 {
+		size = core.DefaultSize
+	} else if size+offset > core.MaxQuerySize {
+		size = core.MaxQuerySize - offset
+	}
+
+	hash := core.Field("hash")
+	lastAction := core.TimeField("last_action")
+	subQuery := storage.db.Query(models.DocContracts).
+		Where(core.Eq("project_id"), c.ProjectID).
+		Where(fmt.Sprintf("%s <> ?", hash), c.Hash).
+		Select(fmt.Sprintf("DISTINCT ON (%s) id, data, %s AS last_action, count(*) OVER (PARTITION BY %s) AS count", hash, lastAction, hash)).
+		Order(fmt.Sprintf("%s, %s desc", hash, lastAction))
+
+	rows, err := storage.db.
+		Raw("SELECT data, count, count(*) OVER () FROM (?) AS hashes ORDER BY last_action desc OFFSET ? LIMIT ?", subQuery.QueryExpr(), offset, size).
+		Rows()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var total int
+	contracts := make([]contract.Similar, 0)
+	for rows.Next() {
+		var data []byte
+		var similar contract.Similar
+		similar.Contract = new(contract.Contract)
+		if err := rows.Scan(&data, &similar.Count, &total); err != nil {
+			return nil, 0, err
+		}
+		if err := core.Decode("", data, similar.Contract); err != nil {
+			return nil, 0, err
+		}
+		contracts = append(contracts, similar)
+	}
+	return contracts, total, rows.Err()
+}
+
+// GetDiffTasks -
+func (storage *Storage) GetDiffTasks() ([]contract.DiffTask, error) {
+	query := storage.db.Query(models.DocContracts).
+		Where(fmt.Sprintf("coalesce(%s, '') <> ''", core.Field("project_id")))
+
+	documents, err := storage.db.GetRaw(query)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make(map[string][]string)
+	last := make(map[string]gjson.Result)
+	for i := range documents {
+		projectID := documents[i].Get("project_id").String()
+		key := fmt.Sprintf("%s_%s", projectID, documents[i].Get("hash").String())
+
+		current, ok := last[key]
+		if !ok {
+			projects[projectID] = append(projects[projectID], key)
+		}
+		if !ok || current.Get("last_action").Time().Before(documents[i].Get("last_action").Time()) {
+			last[key] = documents[i]
+		}
+	}
+
+	tasks := make([]contract.DiffTask, 0)
+	for _, similar := range projects {
+		if len(similar) < 2 {
+			continue
+		}
+
+		for i := 0; i < len(similar)-1; i++ {
+			current := last[similar[i]]
+			for j := i + 1; j < len(similar); j++ {
+				next := last[similar[j]]
+				tasks = append(tasks, contract.DiffTask{
+					Network1: current.Get("network").String(),
+					Address1: current.Get("address").String(),
+					Network2: next.Get("network").String(),
+					Address2: next.Get("address").String(),
+				})
+			}
+		}
+	}
+
+	rand.Seed(time.Now().Unix())
+	rand.Shuffle(len(tasks), func(i, j int) { tasks[i], tasks[j] = tasks[j], tasks[i] })
+	return tasks, nil
+}
+
+// GetTokens -
+func (storage *Storage) GetTokens(network, tokenInterface string, offset, size int64) ([]contract.Contract, int64, error) {
+	tags := []string{"fa12", "fa1", "fa2"}
+	if tokenInterface != "" {
+		tags = []string{tokenInterface}
+	}
+
+	query := core.ContainsAny("tags", tags...).
+		Apply(storage.db.Query(models.DocContracts).Where("network = ?", network)).
+		Order(core.Order("timestamp", true))
+
+	if size > 0 {
+		query = query.Limit(size)
+	}
+
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	contracts := make([]contract.Contract, 0)
+	total, err := storage.db.GetAllByQueryWithTotal(query, &contracts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return contracts, total, nil
+}
+
+func (storage *Storage) topContracts(query *gorm.DB, idFunc func(c contract.Contract) string) ([]contract.Contract, error) {
+	all := make([]contract.Contract, 0)
+	if err := storage.db.GetAllByQuery(query, &all); err != nil {
+		return nil, err
+	}
+
+	response := make([]contract.Contract, 0)
+	found := make(map[string]struct{})
+	for i := range all {
+		id := idFunc(all[i])
+		if _, ok := found[id]; ok {
+			continue
+		}
+		found[id] = struct{}{}
+		response = append(response, all[i])
+	}
+	return response, nil
+}
+
+// UpdateField -
+func (storage *Storage) UpdateField(where []contract.Contract, fields ...string) error {
+	for i := range where {
+		if err := storage.db.UpdateFields(models.DocContracts, where[i].GetID(), &where[i], fields...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/baking-bad/bcdhub/internal/models/tokenmetadata"
+)
+
+// Comparator - returns condition of comparison of integer `field` with comparator value
+func Comparator(field string, cmp tokenmetadata.Comparator) Condition {
+	var operator string
+	switch cmp.Comparator {
+	case "gt":
+		operator = ">"
+	case "gte":
+		operator = ">="
+	case "lt":
+		operator = "<"
+	case "lte":
+		operator = "<="
+	case "eq":
+		operator = "="
+	default:
+		return Condition{}
+	}
+	return NewCondition(fmt.Sprintf("%s %s ?", IntField(field), operator), cmp.Value)
+}
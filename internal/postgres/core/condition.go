@@ -0,0 +1,67 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Condition - part of SQL `WHERE` clause with its arguments
+type Condition struct {
+	SQL  string
+	Args []interface{}
+}
+
+// NewCondition -
+func NewCondition(sql string, args ...interface{}) Condition {
+	return Condition{sql, args}
+}
+
+// Or - joins conditions by `OR`
+func Or(conditions ...Condition) Condition {
+	return join(" OR ", conditions)
+}
+
+// And - joins conditions by `AND`
+func And(conditions ...Condition) Condition {
+	return join(" AND ", conditions)
+}
+
+// ContainsAny - returns condition which checks that array field contains at least one of `values`
+func ContainsAny(name string, values ...string) Condition {
+	conditions := make([]Condition, 0, len(values))
+	for i := range values {
+		value, err := json.Marshal([]string{values[i]})
+		if err != nil {
+			continue
+		}
+		conditions = append(conditions, NewCondition(Contains(name), string(value)))
+	}
+	if len(conditions) == 0 {
+		return NewCondition("false")
+	}
+	return Or(conditions...)
+}
+
+// Apply - adds condition to query
+func (c Condition) Apply(query *gorm.DB) *gorm.DB {
+	if c.SQL == "" {
+		return query
+	}
+	return query.Where(c.SQL, c.Args...)
+}
+
+func join(separator string, conditions []Condition) Condition {
+	parts := make([]string, 0, len(conditions))
+	args := make([]interface{}, 0)
+	for i := range conditions {
+		if conditions[i].SQL == "" {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("(%s)", conditions[i].SQL))
+		args = append(args, conditions[i].Args...)
+	}
+	return Condition{strings.Join(parts, separator), args}
+}
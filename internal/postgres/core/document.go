@@ -0,0 +1,79 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/baking-bad/bcdhub/internal/helpers"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// row - document stored in postgres table. `network` and `level` are copied from document to separate columns for rollback and partitioning queries.
+type row struct {
+	ID      string
+	Network string
+	Level   int64
+	Data    string
+}
+
+func newRow(model models.Model) (row, error) {
+	data, err := json.Marshal(model)
+	if err != nil {
+		return row{}, err
+	}
+	id := model.GetID()
+	if id == "" {
+		id = helpers.GenerateID()
+	}
+	return row{
+		ID:      id,
+		Network: gjson.GetBytes(data, "network").String(),
+		Level:   gjson.GetBytes(data, "level").Int(),
+		Data:    string(data),
+	}, nil
+}
+
+// Decode - unmarshals document to `output`. Identifier of document is stored to `ID` field since models do not marshal it.
+func Decode(id string, data []byte, output interface{}) error {
+	val := reflect.ValueOf(output)
+	if val.Kind() != reflect.Ptr {
+		return errors.Errorf("Invalid `output` type: %s", val.Kind())
+	}
+	if err := json.Unmarshal(data, output); err != nil {
+		return err
+	}
+	if val.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	if field := val.Elem().FieldByName("ID"); field.IsValid() && field.Kind() == reflect.String && field.CanSet() {
+		field.SetString(id)
+	}
+	return nil
+}
+
+// jsonName - returns JSON name of struct field
+func jsonName(typ reflect.Type, field string) string {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return field
+	}
+	f, ok := typ.FieldByName(field)
+	if !ok {
+		return field
+	}
+	tag := f.Tag.Get("json")
+	for i := range tag {
+		if tag[i] == ',' {
+			tag = tag[:i]
+			break
+		}
+	}
+	if tag == "" || tag == "-" {
+		return field
+	}
+	return tag
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// default errors
+var (
+	ErrNotSupported = errors.New("Operation is not supported by postgres storage")
+)
+
+// IsRecordNotFound -
+func (p *Postgres) IsRecordNotFound(err error) bool {
+	var target *RecordNotFoundError
+	return errors.As(err, &target) || gorm.IsRecordNotFoundError(err)
+}
+
+// RecordNotFoundError -
+type RecordNotFoundError struct {
+	index string
+	id    string
+}
+
+// NewRecordNotFoundError -
+func NewRecordNotFoundError(index, id string) *RecordNotFoundError {
+	return &RecordNotFoundError{index, id}
+}
+
+// Error -
+func (e *RecordNotFoundError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("Record is not found: ")
+	if e.index != "" {
+		builder.WriteString("index=")
+		builder.WriteString(e.index)
+		builder.WriteString(" ")
+	}
+	if e.id != "" {
+		builder.WriteString("id=")
+		builder.WriteString(e.id)
+		builder.WriteString(" ")
+	}
+	return builder.String()
+}
@@ -0,0 +1,191 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	constants "github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/models"
+)
+
+// GetEvents -
+func (p *Postgres) GetEvents(subscriptions []models.SubscriptionRequest, size, offset int64) ([]models.Event, error) {
+	if len(subscriptions) == 0 {
+		return []models.Event{}, nil
+	}
+
+	if size == 0 {
+		size = DefaultSize
+	}
+
+	events := make([]models.Event, 0)
+	contractEvents, err := p.getContractEvents(subscriptions, size, offset)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, contractEvents...)
+
+	operationEvents, err := p.getOperationEvents(subscriptions, size, offset)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, operationEvents...)
+
+	migrationEvents, err := p.getMigrationEvents(subscriptions, size, offset)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, migrationEvents...)
+	return events, nil
+}
+
+func (p *Postgres) getContractEvents(subscriptions []models.SubscriptionRequest, size, offset int64) ([]models.Event, error) {
+	events := make([]models.Event, 0)
+	for _, subscription := range subscriptions {
+		conditions := make([]Condition, 0)
+		if subscription.WithSame {
+			conditions = append(conditions, Condition{
+				fmt.Sprintf("%s AND %s <> ?", Eq("hash"), Field("address")),
+				[]interface{}{subscription.Hash, subscription.Address},
+			})
+		}
+		if subscription.WithSimilar {
+			conditions = append(conditions, Condition{
+				fmt.Sprintf("%s AND %s <> ? AND %s <> ?", Eq("project_id"), Field("hash"), Field("address")),
+				[]interface{}{subscription.ProjectID, subscription.Hash, subscription.Address},
+			})
+		}
+		if len(conditions) == 0 {
+			continue
+		}
+
+		where := Or(conditions...)
+		query := p.Query(models.DocContracts).
+			Where(where.SQL, where.Args...).
+			Order(Order("timestamp", true)).
+			Limit(size).
+			Offset(offset)
+
+		var items []EventContract
+		if err := p.GetAllByQuery(query, &items); err != nil {
+			return nil, err
+		}
+
+		for _, event := range items {
+			res := models.Event{
+				Body:    event,
+				Network: subscription.Network,
+				Address: subscription.Address,
+				Alias:   subscription.Alias,
+			}
+
+			if event.Hash == subscription.Hash {
+				res.Type = models.EventTypeSame
+			} else {
+				res.Type = models.EventTypeSimilar
+			}
+			events = append(events, res)
+		}
+	}
+
+	return events, nil
+}
+
+func (p *Postgres) getOperationEvents(subscriptions []models.SubscriptionRequest, size, offset int64) ([]models.Event, error) {
+	events := make([]models.Event, 0)
+	for _, subscription := range subscriptions {
+		addressField := "destination"
+		if strings.HasPrefix(subscription.Address, "tz") {
+			addressField = "source"
+		}
+
+		conditions := make([]Condition, 0)
+		if subscription.WithCalls {
+			conditions = append(conditions, Condition{
+				fmt.Sprintf("%s AND %s AND %s", Eq("kind"), Eq(addressField), Eq("status")),
+				[]interface{}{constants.Transaction, subscription.Address, constants.Applied},
+			})
+		}
+		if subscription.WithErrors {
+			conditions = append(conditions, Condition{
+				fmt.Sprintf("%s AND %s <> ?", Eq(addressField), Field("status")),
+				[]interface{}{subscription.Address, constants.Applied},
+			})
+		}
+		if subscription.WithDeployments {
+			conditions = append(conditions, Condition{
+				fmt.Sprintf("%s AND %s", Eq("kind"), Eq("source")),
+				[]interface{}{constants.Origination, subscription.Address},
+			})
+		}
+		if len(conditions) == 0 {
+			continue
+		}
+
+		where := Or(conditions...)
+		query := p.Query(models.DocOperations).
+			Where("network = ?", subscription.Network).
+			Where(where.SQL, where.Args...).
+			Order(Order("timestamp", true)).
+			Limit(size).
+			Offset(offset)
+
+		var items []EventOperation
+		if err := p.GetAllByQuery(query, &items); err != nil {
+			return nil, err
+		}
+
+		for _, event := range items {
+			res := models.Event{
+				Body:    event,
+				Network: subscription.Network,
+				Address: subscription.Address,
+			}
+
+			switch {
+			case event.Status != constants.Applied:
+				res.Type = models.EventTypeError
+			case event.Source == subscription.Address && event.Kind == constants.Origination:
+				res.Type = models.EventTypeDeploy
+			case event.Source == subscription.Address && event.Kind == constants.Transaction:
+				res.Type = models.EventTypeCall
+			case event.Destination == subscription.Address && event.Kind == constants.Transaction:
+				res.Type = models.EventTypeInvoke
+			}
+			events = append(events, res)
+		}
+	}
+	return events, nil
+}
+
+func (p *Postgres) getMigrationEvents(subscriptions []models.SubscriptionRequest, size, offset int64) ([]models.Event, error) {
+	events := make([]models.Event, 0)
+	for _, subscription := range subscriptions {
+		if !subscription.WithMigrations {
+			continue
+		}
+		query := p.Query(models.DocMigrations).
+			Where(In("kind"), []string{constants.MigrationBootstrap, constants.MigrationLambda, constants.MigrationUpdate}).
+			Where("network = ?", subscription.Network).
+			Where(Eq("address"), subscription.Address).
+			Order(Order("timestamp", true)).
+			Limit(size).
+			Offset(offset)
+
+		var items []EventMigration
+		if err := p.GetAllByQuery(query, &items); err != nil {
+			return nil, err
+		}
+
+		for _, event := range items {
+			events = append(events, models.Event{
+				Body:    event,
+				Network: subscription.Network,
+				Address: subscription.Address,
+				Type:    models.EventTypeMigration,
+				Alias:   subscription.Alias,
+			})
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidField -
+var ErrInvalidField = errors.New("Invalid field name")
+
+var fieldNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_.]+$`)
+
+// CheckField - returns error if `name` can't be used as field name. Field names received from outside must be checked before building query.
+func CheckField(name string) error {
+	if !fieldNameRegexp.MatchString(name) {
+		return errors.Wrap(ErrInvalidField, name)
+	}
+	return nil
+}
+
+// Field - returns SQL expression of text value of document field. Nested fields are separated by dot: `fingerprint.code`.
+// Invalid name is replaced by `NULL`.
+func Field(name string) string {
+	if err := CheckField(name); err != nil {
+		logger.Error(err)
+		return "NULL"
+	}
+	switch name {
+	case "id", "network":
+		return name
+	}
+	return fmt.Sprintf("data #>> '{%s}'", strings.ReplaceAll(name, ".", ","))
+}
+
+// JSONField - returns SQL expression of JSON value of document field. Invalid name is replaced by `NULL`.
+func JSONField(name string) string {
+	if err := CheckField(name); err != nil {
+		logger.Error(err)
+		return "NULL"
+	}
+	return fmt.Sprintf("data #> '{%s}'", strings.ReplaceAll(name, ".", ","))
+}
+
+// IntField - returns SQL expression of integer document field
+func IntField(name string) string {
+	if name == "level" {
+		return name
+	}
+	return fmt.Sprintf("(%s)::bigint", Field(name))
+}
+
+// FloatField - returns SQL expression of numeric document field
+func FloatField(name string) string {
+	return fmt.Sprintf("(%s)::double precision", Field(name))
+}
+
+// NumericField - returns SQL expression of arbitrary-precision integer document field
+func NumericField(name string) string {
+	return fmt.Sprintf("(%s)::numeric", Field(name))
+}
+
+// TimeField - returns SQL expression of time document field
+func TimeField(name string) string {
+	return fmt.Sprintf("(%s)::timestamptz", Field(name))
+}
+
+// Eq - returns SQL condition `field = ?`
+func Eq(name string) string {
+	return fmt.Sprintf("%s = ?", Field(name))
+}
+
+// In - returns SQL condition `field IN (?)`
+func In(name string) string {
+	return fmt.Sprintf("%s IN (?)", Field(name))
+}
+
+// Contains - returns SQL condition which checks that array field contains value. Value has to be JSON array.
+func Contains(name string) string {
+	return fmt.Sprintf("%s @> ?::jsonb", JSONField(name))
+}
+
+// Order - returns SQL order expression
+func Order(field string, desc bool) string {
+	var expr string
+	switch field {
+	case "level", "timestamp", "indexed_time", "counter", "nonce", "ptr", "token_id", "tx_count", "last_action", "start_level", "end_level":
+		expr = fmt.Sprintf("(%s)", sortable(field))
+	default:
+		expr = Field(field)
+	}
+	if desc {
+		return expr + " desc"
+	}
+	return expr + " asc"
+}
+
+func sortable(field string) string {
+	switch field {
+	case "timestamp", "last_action":
+		return TimeField(field)
+	case "token_id":
+		return NumericField(field)
+	default:
+		return IntField(field)
+	}
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestField(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "column",
+			field: "network",
+			want:  "network",
+		}, {
+			name:  "nested field",
+			field: "fingerprint.code",
+			want:  "data #>> '{fingerprint,code}'",
+		}, {
+			name:    "injection",
+			field:   "address}') OR true --",
+			want:    "NULL",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckField(tt.field)
+			assert.Equal(t, tt.wantErr, errors.Is(err, ErrInvalidField))
+			assert.Equal(t, tt.want, Field(tt.field))
+		})
+	}
+}
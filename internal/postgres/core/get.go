@@ -0,0 +1,262 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// Sizes -
+const (
+	DefaultSize  = 10
+	MaxQuerySize = 10000
+)
+
+// Count - returns total count of documents matched `query`
+func (p *Postgres) Count(query *gorm.DB) (int64, error) {
+	var count int64
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// GetOne - receives first document of `query` result. Returns `RecordNotFoundError` if result is empty.
+func (p *Postgres) GetOne(query *gorm.DB, output interface{}) error {
+	items := make([]document, 0)
+	if err := fetch(query.Limit(1), false, func(doc document) error {
+		items = append(items, doc)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return NewRecordNotFoundError("", "")
+	}
+	return Decode(items[0].ID, items[0].Data, output)
+}
+
+// GetByID -
+func (p *Postgres) GetByID(ret models.Model) error {
+	query := p.Query(ret.GetIndex()).Where("id = ?", ret.GetID())
+	if err := p.GetOne(query, ret); err != nil {
+		if p.IsRecordNotFound(err) {
+			return NewRecordNotFoundError(ret.GetIndex(), ret.GetID())
+		}
+		return err
+	}
+	return nil
+}
+
+// GetByIDs -
+func (p *Postgres) GetByIDs(output interface{}, ids ...string) error {
+	index, err := getIndex(output)
+	if err != nil {
+		return err
+	}
+	return p.GetAllByQuery(p.Query(index).Where("id IN (?)", ids), output)
+}
+
+// GetAll -
+func (p *Postgres) GetAll(output interface{}) error {
+	index, err := getIndex(output)
+	if err != nil {
+		return err
+	}
+	return p.GetAllByQuery(p.Query(index), output)
+}
+
+// GetByNetwork -
+func (p *Postgres) GetByNetwork(network string, output interface{}) error {
+	index, err := getIndex(output)
+	if err != nil {
+		return err
+	}
+	query := p.Query(index).Where("network = ?", network).Order("level asc")
+	return p.GetAllByQuery(query, output)
+}
+
+// GetByNetworkWithSort -
+func (p *Postgres) GetByNetworkWithSort(network, sortField, sortOrder string, output interface{}) error {
+	index, err := getIndex(output)
+	if err != nil {
+		return err
+	}
+	if err := CheckField(sortField); err != nil {
+		return err
+	}
+	query := p.Query(index).Where("network = ?", network).Order(Order(sortField, sortOrder == "desc"))
+	return p.GetAllByQuery(query, output)
+}
+
+// GetAllByQuery -
+func (p *Postgres) GetAllByQuery(query *gorm.DB, output interface{}) error {
+	return fetch(query, false, func(doc document) error {
+		return appendDocument(doc, output)
+	})
+}
+
+// GetAllByQueryWithTotal - returns documents and total count of documents matched `query` without its limit and offset.
+// Total is 0 if offset is greater than count of documents.
+func (p *Postgres) GetAllByQueryWithTotal(query *gorm.DB, output interface{}) (int64, error) {
+	var total int64
+	err := fetch(query, true, func(doc document) error {
+		total = doc.Total
+		return appendDocument(doc, output)
+	})
+	return total, err
+}
+
+// GetRaw - returns JSON documents by query
+func (p *Postgres) GetRaw(query *gorm.DB) ([]gjson.Result, error) {
+	result := make([]gjson.Result, 0)
+	err := fetch(query, false, func(doc document) error {
+		result = append(result, gjson.ParseBytes(doc.Data))
+		return nil
+	})
+	return result, err
+}
+
+// FiltersToQuery - adds equality conditions of `by` to query. Keys with `.or` suffix expect array value and match any of its elements.
+func FiltersToQuery(query *gorm.DB, by map[string]interface{}) (*gorm.DB, error) {
+	for k, v := range by {
+		if strings.HasSuffix(k, ".or") {
+			field := strings.TrimSuffix(k, ".or")
+			if err := CheckField(field); err != nil {
+				return nil, err
+			}
+			val, ok := v.([]interface{})
+			if !ok {
+				continue
+			}
+			conditions := make([]Condition, len(val))
+			for i := range val {
+				conditions[i] = NewCondition(Eq(field), val[i])
+			}
+			query = Or(conditions...).Apply(query)
+		} else {
+			if err := CheckField(k); err != nil {
+				return nil, err
+			}
+			query = query.Where(Eq(k), v)
+		}
+	}
+	return query, nil
+}
+
+type document struct {
+	ID    string
+	Data  []byte
+	Total int64
+}
+
+func fetch(query *gorm.DB, withTotal bool, handler func(doc document) error) error {
+	if query == nil {
+		return errors.New("Query pointer is nil")
+	}
+	columns := "id, data"
+	if withTotal {
+		columns = "id, data, count(*) OVER() AS total"
+	}
+	rows, err := query.Select(columns).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var doc document
+		if withTotal {
+			err = rows.Scan(&doc.ID, &doc.Data, &doc.Total)
+		} else {
+			err = rows.Scan(&doc.ID, &doc.Data)
+		}
+		if err != nil {
+			return err
+		}
+		if err := handler(doc); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func appendDocument(doc document, output interface{}) error {
+	typ, err := getElementType(output)
+	if err != nil {
+		return err
+	}
+	isPtr := typ.Kind() == reflect.Ptr
+	if isPtr {
+		typ = typ.Elem()
+	}
+
+	obj := reflect.New(typ)
+	if err := Decode(doc.ID, doc.Data, obj.Interface()); err != nil {
+		return err
+	}
+	val := obj
+	if !isPtr {
+		val = obj.Elem()
+	}
+
+	el := reflect.ValueOf(output).Elem()
+	if el.Kind() == reflect.Slice {
+		el.Set(reflect.Append(el, val))
+	} else {
+		el.Set(val)
+	}
+	return nil
+}
+
+func getElementType(output interface{}) (reflect.Type, error) {
+	arr := reflect.TypeOf(output)
+	if arr.Kind() != reflect.Ptr {
+		return arr, errors.Errorf("Invalid `output` type: %s", arr.Kind())
+	}
+	arr = arr.Elem()
+	if arr.Kind() == reflect.Slice {
+		return arr.Elem(), nil
+	}
+	return arr, nil
+}
+
+func getIndex(output interface{}) (string, error) {
+	typ, err := getElementType(output)
+	if err != nil {
+		return "", err
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	model, ok := reflect.New(typ).Interface().(models.Model)
+	if !ok {
+		return "", errors.Errorf("getIndex: 'output' is not implemented `Model` interface")
+	}
+	return model.GetIndex(), nil
+}
+
+// Sum - returns sum of numeric `field` of documents matched `query`
+func (p *Postgres) Sum(query *gorm.DB, field string) (float64, error) {
+	if err := CheckField(field); err != nil {
+		return 0, err
+	}
+	var result struct {
+		Value float64
+	}
+	err := query.Select(fmt.Sprintf("coalesce(sum(%s), 0) AS value", FloatField(field))).Scan(&result).Error
+	return result.Value, err
+}
+
+// GetUnique - returns unique values of `field` of documents matched `query`
+func (p *Postgres) GetUnique(field string, query *gorm.DB) ([]string, error) {
+	if err := CheckField(field); err != nil {
+		return nil, err
+	}
+	values := make([]string, 0)
+	err := query.Select(fmt.Sprintf("DISTINCT %s AS value", Field(field))).Pluck("value", &values).Error
+	return values, err
+}
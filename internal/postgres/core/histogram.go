@@ -0,0 +1,181 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/tzip"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// GetDateHistogram -
+func (p *Postgres) GetDateHistogram(period string, opts ...models.HistogramOption) ([][]int64, error) {
+	ctx := models.HistogramContext{
+		Period: period,
+	}
+	for _, opt := range opts {
+		opt(&ctx)
+	}
+
+	buckets := make(map[int64]float64)
+	for _, index := range ctx.Indices {
+		query, err := buildHistogramContext(ctx, p.Query(index))
+		if err != nil {
+			return nil, err
+		}
+		series, err := Histogram(query, period, ctx.Function.Name, ctx.Function.Field)
+		if err != nil {
+			return nil, err
+		}
+		for i := range series {
+			buckets[int64(series[i][0])] += series[i][1]
+		}
+	}
+
+	series := fillGaps(buckets, period)
+	histogram := make([][]int64, len(series))
+	for i := range series {
+		histogram[i] = []int64{int64(series[i][0]), int64(series[i][1])}
+	}
+	return histogram, nil
+}
+
+func buildHistogramContext(ctx models.HistogramContext, query *gorm.DB) (*gorm.DB, error) {
+	for _, fltr := range ctx.Filters {
+		field := strings.TrimSuffix(fltr.Field, ".keyword")
+		if fltr.Kind != models.HistogramFilterDexEnrtypoints {
+			if err := CheckField(field); err != nil {
+				return nil, err
+			}
+		}
+		switch fltr.Kind {
+		case models.HistogramFilterKindExists:
+			query = query.Where(fmt.Sprintf("%s IS NOT NULL", JSONField(field)))
+		case models.HistogramFilterKindMatch:
+			query = query.Where(Eq(field), fmt.Sprintf("%v", fltr.Value))
+		case models.HistogramFilterKindIn, models.HistogramFilterKindAddresses:
+			if arr, ok := fltr.Value.([]string); ok {
+				query = query.Where(In(field), arr)
+			}
+		case models.HistogramFilterDexEnrtypoints:
+			if value, ok := fltr.Value.([]tzip.DAppContract); ok {
+				query = DexEntrypoints(query, value)
+			}
+		}
+	}
+	return query, nil
+}
+
+// DexEntrypoints - filters documents which were initiated by one of DEX volume entrypoints of `contracts`
+func DexEntrypoints(query *gorm.DB, contracts []tzip.DAppContract) *gorm.DB {
+	conditions := make([]Condition, 0)
+	for i := range contracts {
+		for j := range contracts[i].DexVolumeEntrypoints {
+			conditions = append(conditions, Condition{
+				fmt.Sprintf("%s AND %s", Eq("initiator"), Eq("parent")),
+				[]interface{}{contracts[i].Address, contracts[i].DexVolumeEntrypoints[j]},
+			})
+		}
+	}
+	if len(conditions) == 0 {
+		return query.Where("false")
+	}
+	where := Or(conditions...)
+	return query.Where(where.SQL, where.Args...)
+}
+
+// Histogram - splits documents of `query` by `period` buckets using `timestamp` field and computes `function` of `field` in every bucket. If `function` is empty documents count is computed.
+// Returns pairs of bucket start in milliseconds and value sorted by time. Empty buckets between the first and the last ones are filled by zero.
+func Histogram(query *gorm.DB, period, function, field string) ([][]float64, error) {
+	if _, err := nextPeriod(time.Time{}, period); err != nil {
+		return nil, err
+	}
+
+	value := "count(*)"
+	if function != "" && field != "" {
+		field = strings.TrimSuffix(field, ".keyword")
+		if err := CheckField(field); err != nil {
+			return nil, err
+		}
+		switch function {
+		case "cardinality":
+			value = fmt.Sprintf("count(DISTINCT %s)", Field(field))
+		case "sum", "avg", "min", "max":
+			value = fmt.Sprintf("coalesce(%s(%s), 0)", function, FloatField(field))
+		default:
+			return nil, errors.Errorf("Unknown histogram function: %s", function)
+		}
+	}
+
+	bucket := fmt.Sprintf("date_trunc('%s', %s AT TIME ZONE 'UTC')", period, TimeField("timestamp"))
+	rows, err := query.
+		Select(fmt.Sprintf("%s AS bucket, %s", bucket, value)).
+		Group("bucket").
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := make(map[int64]float64)
+	for rows.Next() {
+		var ts time.Time
+		var val float64
+		if err := rows.Scan(&ts, &val); err != nil {
+			return nil, err
+		}
+		buckets[ts.UTC().UnixNano()/int64(time.Millisecond)] += val
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return fillGaps(buckets, period), nil
+}
+
+func fillGaps(buckets map[int64]float64, period string) [][]float64 {
+	histogram := make([][]float64, 0)
+	if len(buckets) == 0 {
+		return histogram
+	}
+
+	var first, last int64
+	isFirst := true
+	for key := range buckets {
+		if isFirst || key < first {
+			first = key
+		}
+		if isFirst || key > last {
+			last = key
+		}
+		isFirst = false
+	}
+
+	ms := int64(time.Millisecond)
+	for key := time.Unix(0, first*ms).UTC(); key.UnixNano()/ms <= last; key, _ = nextPeriod(key, period) {
+		histogram = append(histogram, []float64{
+			float64(key.UnixNano() / ms),
+			buckets[key.UnixNano()/ms],
+		})
+	}
+	return histogram
+}
+
+func nextPeriod(ts time.Time, period string) (time.Time, error) {
+	switch period {
+	case "hour":
+		return ts.Add(time.Hour), nil
+	case "day":
+		return ts.AddDate(0, 0, 1), nil
+	case "week":
+		return ts.AddDate(0, 0, 7), nil
+	case "month":
+		return ts.AddDate(0, 1, 0), nil
+	case "year":
+		return ts.AddDate(1, 0, 0), nil
+	default:
+		return ts, errors.Errorf("Unknown histogram period: %s", period)
+	}
+}
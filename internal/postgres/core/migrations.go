@@ -0,0 +1,153 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/jinzhu/gorm"
+)
+
+// migration - versioned schema change. Applied migrations are stored in `schema_migrations` table.
+type migration struct {
+	Version     int
+	Description string
+	Up          func(tx *gorm.DB) error
+}
+
+// migrations - list of schema changes. Append new migrations to the end and never change applied ones.
+var migrations = []migration{
+	{
+		Version:     1,
+		Description: "indices of operations, big map diffs, transfers and token balances",
+		Up: createIndices(map[string][]string{
+			models.DocOperations:    {"hash", "source", "destination", "entrypoint"},
+			models.DocBigMapDiff:    {"operation_id", "address", "key_hash", "ptr"},
+			models.DocTransfers:     {"contract", "from", "to", "token_id"},
+			models.DocTokenBalances: {"address", "contract", "token_id"},
+		}),
+	}, {
+		Version:     2,
+		Description: "indices of contracts and metadata",
+		Up: createIndices(map[string][]string{
+			models.DocContracts:     {"address", "project_id", "hash", "manager"},
+			models.DocTZIP:          {"address", "slug"},
+			models.DocTokenMetadata: {"contract"},
+			models.DocMigrations:    {"address"},
+			models.DocSaplingDiffs:  {"ptr"},
+		}),
+	}, {
+		Version:     3,
+		Description: "indices of metadata versions",
+		Up: createIndices(map[string][]string{
+			models.DocTZIPVersions: {"address", "status"},
+		}),
+	}, {
+		Version:     4,
+		Description: "arbitrary-precision token ids",
+		Up:          tokenIDToString,
+	},
+}
+
+// Migrate - creates tables of all indices and applies new schema migrations
+func (p *Postgres) Migrate() error {
+	for _, index := range models.AllDocuments() {
+		if err := p.createTable(index); err != nil {
+			return err
+		}
+	}
+
+	if err := p.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version integer PRIMARY KEY, description text NOT NULL, applied_at timestamptz NOT NULL DEFAULT now())`).Error; err != nil {
+		return err
+	}
+
+	var applied []int
+	if err := p.Table("schema_migrations").Pluck("version", &applied).Error; err != nil {
+		return err
+	}
+	done := make(map[int]struct{}, len(applied))
+	for i := range applied {
+		done[applied[i]] = struct{}{}
+	}
+
+	for _, m := range migrations {
+		if _, ok := done[m.Version]; ok {
+			continue
+		}
+
+		tx := p.Begin()
+		if tx.Error != nil {
+			return tx.Error
+		}
+		if err := m.Up(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", m.Version, err)
+		}
+		if err := tx.Exec(`INSERT INTO schema_migrations (version, description) VALUES (?, ?)`, m.Version, m.Description).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit().Error; err != nil {
+			return err
+		}
+		logger.Info("Applied postgres migration %d: %s", m.Version, m.Description)
+	}
+	return nil
+}
+
+func (p *Postgres) createTable(index string) error {
+	if err := p.Exec(fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %q (id text PRIMARY KEY, network text NOT NULL DEFAULT '', level bigint NOT NULL DEFAULT 0, data jsonb NOT NULL)`,
+		index,
+	)).Error; err != nil {
+		return err
+	}
+	return p.Exec(fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %q ON %q (network, level)`, index+"_network_level_idx", index)).Error
+}
+
+func createIndices(fields map[string][]string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		for index, names := range fields {
+			for _, name := range names {
+				if err := tx.Exec(fmt.Sprintf(
+					`CREATE INDEX IF NOT EXISTS %q ON %q ((%s))`,
+					fmt.Sprintf("%s_%s_idx", index, name), index, indexExpression(name),
+				)).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// tokenIDToString - converts numeric token ids to decimal strings and replaces bigint indices of token id by text ones
+func tokenIDToString(tx *gorm.DB) error {
+	for _, index := range []string{models.DocTransfers, models.DocTokenBalances, models.DocTokenMetadata} {
+		if err := tx.Exec(fmt.Sprintf(
+			`UPDATE %q SET data = jsonb_set(data, '{token_id}', to_jsonb(%s)) WHERE jsonb_typeof(%s) = 'number'`,
+			index, Field("token_id"), JSONField("token_id"),
+		)).Error; err != nil {
+			return err
+		}
+	}
+	for _, index := range []string{models.DocTransfers, models.DocTokenBalances} {
+		name := fmt.Sprintf("%s_token_id_idx", index)
+		if err := tx.Exec(fmt.Sprintf(`DROP INDEX IF EXISTS %q`, name)).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(fmt.Sprintf(`CREATE INDEX %q ON %q ((%s))`, name, index, Field("token_id"))).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func indexExpression(name string) string {
+	switch name {
+	case "token_id", "ptr":
+		return IntField(name)
+	default:
+		return Field(name)
+	}
+}
@@ -0,0 +1,247 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/database"
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/jinzhu/gorm"
+)
+
+const bulkSize = 1000
+
+// Postgres - storage of indexed data in PostgreSQL. Every index is a table with `id`, `network`, `level` columns and JSONB document in `data` column.
+type Postgres struct {
+	*gorm.DB
+}
+
+// New - creates storage on connection to postgres. Connection is shared with application database if they have the same connection string.
+func New(connectionString string) (*Postgres, error) {
+	db, err := database.Connect(connectionString)
+	if err != nil {
+		return nil, err
+	}
+	return &Postgres{db}, nil
+}
+
+// WaitNew - waiting for postgres up and creating connection
+func WaitNew(connectionString string, timeout int) *Postgres {
+	var db *Postgres
+	var err error
+
+	for db == nil {
+		db, err = New(connectionString)
+		if err != nil {
+			logger.Warning("Waiting postgres up %d seconds...", timeout)
+			time.Sleep(time.Second * time.Duration(timeout))
+		}
+	}
+	return db
+}
+
+// Query - returns query to table of `index`
+func (p *Postgres) Query(index string) *gorm.DB {
+	return p.Table(index)
+}
+
+// CreateIndexes - applies schema migrations
+func (p *Postgres) CreateIndexes() error {
+	return p.Migrate()
+}
+
+// DeleteIndices -
+func (p *Postgres) DeleteIndices(indices []string) error {
+	for i := range indices {
+		if err := p.Exec(fmt.Sprintf(`TRUNCATE TABLE %q`, indices[i])).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteByLevelAndNetwork -
+func (p *Postgres) DeleteByLevelAndNetwork(indices []string, network string, maxLevel int64) error {
+	for i := range indices {
+		result := p.Exec(fmt.Sprintf(`DELETE FROM %q WHERE network = ? AND level > ?`, indices[i]), network, maxLevel)
+		if result.Error != nil {
+			return result.Error
+		}
+		logger.Info("Removed %d records from %s", result.RowsAffected, indices[i])
+	}
+	return nil
+}
+
+// Refresh - committed changes are visible immediately, so there is nothing to wait
+func (p *Postgres) Refresh(indices []string) error {
+	return nil
+}
+
+// DeleteByContract -
+func (p *Postgres) DeleteByContract(indices []string, network, address string) error {
+	for i := range indices {
+		conditions := []string{"true"}
+		args := make([]interface{}, 0)
+		if network != "" {
+			conditions = append(conditions, Eq("network"))
+			args = append(args, network)
+		}
+		if address != "" {
+			fields := models.ContractFields(indices[i])
+			matches := make([]string, len(fields))
+			for j := range fields {
+				matches[j] = Eq(fields[j])
+				args = append(args, address)
+			}
+			conditions = append(conditions, fmt.Sprintf("(%s)", strings.Join(matches, " OR ")))
+		}
+		result := p.Exec(fmt.Sprintf(`DELETE FROM %q WHERE %s`, indices[i], strings.Join(conditions, " AND ")), args...)
+		if result.Error != nil {
+			return result.Error
+		}
+		logger.Info("Removed %d records from %s", result.RowsAffected, indices[i])
+	}
+	return nil
+}
+
+// BulkInsert - inserts documents. Existing documents are replaced. All documents are saved in one transaction.
+func (p *Postgres) BulkInsert(items []models.Model) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	tx := p.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if err := upsert(tx, items); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// BulkUpdate -
+func (p *Postgres) BulkUpdate(updates []models.Model) error {
+	return p.BulkInsert(updates)
+}
+
+// BulkDelete -
+func (p *Postgres) BulkDelete(items []models.Model) error {
+	byIndex := make(map[string][]string)
+	for i := range items {
+		byIndex[items[i].GetIndex()] = append(byIndex[items[i].GetIndex()], items[i].GetID())
+	}
+	for index, ids := range byIndex {
+		if err := p.Exec(fmt.Sprintf(`DELETE FROM %q WHERE id IN (?)`, index), ids).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BulkRemoveField -
+func (p *Postgres) BulkRemoveField(field string, where []models.Model) error {
+	for i := range where {
+		path := fmt.Sprintf("{%s}", strings.ReplaceAll(field, ".", ","))
+		if err := p.Exec(
+			fmt.Sprintf(`UPDATE %q SET data = data #- ? WHERE id = ?`, where[i].GetIndex()),
+			path, where[i].GetID(),
+		).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFieldValue -
+func (p *Postgres) GetFieldValue(data interface{}, field string) interface{} {
+	val := reflect.ValueOf(data)
+	f := reflect.Indirect(val).FieldByName(field)
+	return f.Interface()
+}
+
+// SetAlias -
+func (p *Postgres) SetAlias(network, address, alias string) error {
+	updates := []struct {
+		index string
+		field string
+		alias string
+	}{
+		{models.DocContracts, "address", "alias"},
+		{models.DocContracts, "delegate", "delegate_alias"},
+		{models.DocOperations, "source", "source_alias"},
+		{models.DocOperations, "destination", "destination_alias"},
+		{models.DocOperations, "delegate", "delegate_alias"},
+	}
+
+	for _, update := range updates {
+		if err := p.Exec(
+			fmt.Sprintf(`UPDATE %q SET data = jsonb_set(data, ?, to_jsonb(?::text)) WHERE network = ? AND %s`, update.index, Eq(update.field)),
+			fmt.Sprintf("{%s}", update.alias), alias, network, address,
+		).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func upsert(tx *gorm.DB, items []models.Model) error {
+	byIndex := make(map[string][]row)
+	indices := make([]string, 0)
+	for i := range items {
+		r, err := newRow(items[i])
+		if err != nil {
+			return err
+		}
+		index := items[i].GetIndex()
+		if _, ok := byIndex[index]; !ok {
+			indices = append(indices, index)
+		}
+		byIndex[index] = append(byIndex[index], r)
+	}
+
+	for _, index := range indices {
+		rows := byIndex[index]
+		for start := 0; start < len(rows); start += bulkSize {
+			end := start + bulkSize
+			if end > len(rows) {
+				end = len(rows)
+			}
+
+			values := make([]string, 0, end-start)
+			args := make([]interface{}, 0, (end-start)*4)
+			for _, r := range uniqueRows(rows[start:end]) {
+				values = append(values, "(?, ?, ?, ?::jsonb)")
+				args = append(args, r.ID, r.Network, r.Level, r.Data)
+			}
+
+			query := fmt.Sprintf(
+				`INSERT INTO %q (id, network, level, data) VALUES %s ON CONFLICT (id) DO UPDATE SET network = EXCLUDED.network, level = EXCLUDED.level, data = EXCLUDED.data`,
+				index, strings.Join(values, ","),
+			)
+			if err := tx.Exec(query, args...).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// uniqueRows - leaves the last version of every document since postgres can't update the same row twice in one statement
+func uniqueRows(rows []row) []row {
+	positions := make(map[string]int)
+	result := make([]row, 0, len(rows))
+	for i := range rows {
+		if pos, ok := positions[rows[i].ID]; ok {
+			result[pos] = rows[i]
+			continue
+		}
+		positions[rows[i].ID] = len(result)
+		result = append(result, rows[i])
+	}
+	return result
+}
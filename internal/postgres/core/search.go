@@ -0,0 +1,153 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/search"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// SearchByText - searches documents which fields contain `text` (case insensitive). Grouping is not supported.
+func (p *Postgres) SearchByText(text string, offset int64, fields []string, filters map[string]interface{}, group bool) (models.Result, error) {
+	result := models.Result{}
+	if text == "" {
+		return result, errors.Errorf("Empty search string. Please query something")
+	}
+
+	ctx, err := prepareSearchContext(text, filters, fields)
+	if err != nil {
+		return result, err
+	}
+
+	start := time.Now()
+	items := make([]models.Item, 0)
+	for _, index := range ctx.Indices {
+		conditions := make([]Condition, 0)
+		for i := range ctx.Fields {
+			if ctx.Fields[i] == "ptr" {
+				ptr, err := strconv.ParseInt(ctx.Text, 10, 64)
+				if err != nil {
+					return result, err
+				}
+				conditions = append(conditions, Condition{fmt.Sprintf("%s = ?", IntField("ptr")), []interface{}{ptr}})
+			} else {
+				conditions = append(conditions, ILike(ctx.Text, ctx.Fields[i]))
+			}
+		}
+		where := Or(conditions...)
+
+		query, err := prepareFilters(filters, p.Query(index).Where(where.SQL, where.Args...))
+		if err != nil {
+			return result, err
+		}
+
+		err = fetch(query.Order("level desc").Limit(int64(offset)+DefaultSize), false, func(doc document) error {
+			val, err := search.Parse(index, nil, doc.Data)
+			if err != nil {
+				return err
+			}
+			switch t := val.(type) {
+			case models.Item:
+				items = append(items, t)
+			case []models.Item:
+				items = append(items, t...)
+			}
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.Count = int64(len(items))
+	if offset < int64(len(items)) {
+		items = items[offset:]
+	} else {
+		items = items[:0]
+	}
+	if len(items) > DefaultSize {
+		items = items[:DefaultSize]
+	}
+	result.Items = items
+	result.Time = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+func prepareSearchContext(searchString string, filters map[string]interface{}, fields []string) (search.Context, error) {
+	ctx := search.NewContext()
+
+	if search.IsPtrSearch(searchString) {
+		ctx.Text = strings.TrimPrefix(searchString, "ptr:")
+		ctx.Indices = []string{models.DocBigMapDiff}
+		ctx.Fields = []string{"ptr"}
+		return ctx, nil
+	}
+
+	var indices []string
+	if val, ok := filters["indices"]; ok {
+		indices = val.([]string)
+		delete(filters, "indices")
+	}
+
+	info, err := search.GetScores(searchString, fields, indices...)
+	if err != nil {
+		return ctx, err
+	}
+	ctx.Text = strings.TrimSuffix(searchString, "*")
+	ctx.Indices = info.Indices
+	for i := range info.Scores {
+		ctx.Fields = append(ctx.Fields, strings.Split(info.Scores[i], "^")[0])
+	}
+	return ctx, nil
+}
+
+func prepareFilters(filters map[string]interface{}, query *gorm.DB) (*gorm.DB, error) {
+	for field, value := range filters {
+		switch field {
+		case "from", "to":
+			ts, err := time.Parse(time.RFC3339, value.(string))
+			if err != nil {
+				return nil, err
+			}
+			if field == "from" {
+				query = query.Where(fmt.Sprintf("%s > ?", TimeField("timestamp")), ts)
+			} else {
+				query = query.Where(fmt.Sprintf("%s < ?", TimeField("timestamp")), ts)
+			}
+		case "networks":
+			networks, ok := value.([]string)
+			if !ok {
+				return nil, errors.Errorf("Invalid type for 'network' filter (wait []string): %T", value)
+			}
+			query = query.Where("network IN (?)", networks)
+		case "languages":
+			languages, ok := value.([]string)
+			if !ok {
+				return nil, errors.Errorf("Invalid type for 'network' filter (wait []string): %T", value)
+			}
+			query = query.Where(In("language"), languages)
+		default:
+			return nil, errors.Errorf("Unknown search filter: %s", field)
+		}
+	}
+	return query, nil
+}
+
+// ILike - returns condition which matches documents containing `text` in any of `fields` case-insensitively
+func ILike(text string, fields ...string) Condition {
+	pattern := fmt.Sprintf("%%%s%%", escapeLike(text))
+	conditions := make([]Condition, len(fields))
+	for i := range fields {
+		conditions[i] = NewCondition(fmt.Sprintf("%s ILIKE ?", Field(fields[i])), pattern)
+	}
+	return Or(conditions...)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
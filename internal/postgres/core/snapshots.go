@@ -0,0 +1,57 @@
+package core
+
+import (
+	"io"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+)
+
+// CreateAWSRepository -
+func (p *Postgres) CreateAWSRepository(name, awsBucketName, awsRegion string) error {
+	return ErrNotSupported
+}
+
+// ListRepositories -
+func (p *Postgres) ListRepositories() ([]models.Repository, error) {
+	return nil, ErrNotSupported
+}
+
+// CreateSnapshots -
+func (p *Postgres) CreateSnapshots(repository, snapshot string, indices []string) error {
+	return ErrNotSupported
+}
+
+// RestoreSnapshots -
+func (p *Postgres) RestoreSnapshots(repository, snapshot string, indices []string) error {
+	return ErrNotSupported
+}
+
+// ListSnapshots -
+func (p *Postgres) ListSnapshots(repository string) (string, error) {
+	return "", ErrNotSupported
+}
+
+// SetSnapshotPolicy -
+func (p *Postgres) SetSnapshotPolicy(policyID, cronSchedule, name, repository string, expireAfterInDays int64) error {
+	return ErrNotSupported
+}
+
+// GetAllPolicies -
+func (p *Postgres) GetAllPolicies() ([]string, error) {
+	return nil, ErrNotSupported
+}
+
+// GetMappings -
+func (p *Postgres) GetMappings(indices []string) (map[string]string, error) {
+	return nil, nil
+}
+
+// CreateMapping -
+func (p *Postgres) CreateMapping(index string, reader io.Reader) error {
+	return nil
+}
+
+// ReloadSecureSettings -
+func (p *Postgres) ReloadSecureSettings() error {
+	return ErrNotSupported
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/jinzhu/gorm"
+)
+
+func countByField(field string, query *gorm.DB) (map[string]int64, error) {
+	rows, err := query.Select(fmt.Sprintf("%s AS key, count(*)", Field(field))).Group("key").Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	response := make(map[string]int64)
+	for rows.Next() {
+		var key string
+		var count int64
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, err
+		}
+		response[key] = count
+	}
+	return response, rows.Err()
+}
+
+// GetNetworkCountStats -
+func (p *Postgres) GetNetworkCountStats(network string) (map[string]int64, error) {
+	res := make(map[string]int64)
+	for _, index := range []string{models.DocContracts, models.DocOperations} {
+		count, err := p.Count(p.Query(index).Where("network = ?", network))
+		if err != nil {
+			return nil, err
+		}
+		res[index] = count
+	}
+	return res, nil
+}
+
+// GetCallsCountByNetwork -
+func (p *Postgres) GetCallsCountByNetwork(network string) (map[string]int64, error) {
+	query := p.Query(models.DocOperations).Where(fmt.Sprintf("coalesce(%s, '') <> ''", Field("entrypoint")))
+	if network != "" {
+		query = query.Where("network = ?", network)
+	}
+	return countByField("network", query)
+}
+
+// GetContractStatsByNetwork -
+func (p *Postgres) GetContractStatsByNetwork(network string) (map[string]models.ContractCountStats, error) {
+	query := p.Query(models.DocContracts)
+	if network != "" {
+		query = query.Where("network = ?", network)
+	}
+
+	rows, err := query.Select(fmt.Sprintf(
+		"network, count(*), count(DISTINCT (%s, %s, %s))",
+		Field("fingerprint.parameter"), Field("fingerprint.storage"), Field("fingerprint.code"),
+	)).Group("network").Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]models.ContractCountStats)
+	for rows.Next() {
+		var key string
+		var stats models.ContractCountStats
+		if err := rows.Scan(&key, &stats.Total, &stats.SameCount); err != nil {
+			return nil, err
+		}
+		counts[key] = stats
+	}
+	return counts, rows.Err()
+}
+
+// GetFACountByNetwork -
+func (p *Postgres) GetFACountByNetwork(network string) (map[string]int64, error) {
+	query := ContainsAny("tags", "fa1", "fa12").Apply(p.Query(models.DocContracts))
+	if network != "" {
+		query = query.Where("network = ?", network)
+	}
+	return countByField("network", query)
+}
+
+// GetLanguagesForNetwork -
+func (p *Postgres) GetLanguagesForNetwork(network string) (map[string]int64, error) {
+	return countByField("language", p.Query(models.DocContracts).Where("network = ?", network))
+}
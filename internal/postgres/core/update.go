@@ -0,0 +1,41 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+)
+
+// UpdateDoc - updates document
+func (p *Postgres) UpdateDoc(model models.Model) error {
+	return upsert(p.DB, []models.Model{model})
+}
+
+// UpdateFields - updates `fields` of document. `fields` are names of `data` struct fields. If document does not exist it's created from `data`.
+func (p *Postgres) UpdateFields(index, id string, data interface{}, fields ...string) error {
+	typ := reflect.TypeOf(data)
+	patch := make(map[string]interface{})
+	for i := range fields {
+		patch[jsonName(typ, fields[i])] = p.GetFieldValue(data, fields[i])
+	}
+	value, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	result := p.Exec(fmt.Sprintf(`UPDATE %q SET data = data || ?::jsonb WHERE id = ?`, index), string(value), id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	model, ok := data.(models.Model)
+	if !ok {
+		return NewRecordNotFoundError(index, id)
+	}
+	return p.UpdateDoc(model)
+}
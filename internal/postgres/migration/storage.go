@@ -0,0 +1,40 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/migration"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// Get -
+func (storage *Storage) Get(network, address string) (migrations []migration.Migration, err error) {
+	query := storage.db.Query(models.DocMigrations).
+		Where("network = ?", network).
+		Where(core.Eq("address"), address).
+		Order(core.Order("level", true))
+
+	migrations = make([]migration.Migration, 0)
+	err = storage.db.GetAllByQuery(query, &migrations)
+	return
+}
+
+// Count -
+func (storage *Storage) Count(network, address string) (int64, error) {
+	query := storage.db.Query(models.DocMigrations).
+		Where("network = ?", network).
+		Where(fmt.Sprintf("%s OR %s", core.Eq("source"), core.Eq("destination")), address, address)
+
+	return storage.db.Count(query)
+}
@@ -0,0 +1,396 @@
+package operation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/helpers"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+var sortString = fmt.Sprintf(
+	"level * 10000000000 + coalesce(%s, 0) * 1000 + CASE WHEN (%s)::boolean THEN 998 - coalesce(%s, 0) ELSE 999 END desc",
+	core.IntField("counter"), core.Field("internal"), core.IntField("nonce"),
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+type opgForContract struct {
+	Hash    string
+	Counter int64
+}
+
+func (storage *Storage) getContractOPG(address, network string, size uint64, filters map[string]interface{}) ([]opgForContract, error) {
+	if size == 0 || size > core.MaxQuerySize {
+		size = core.DefaultSize
+	}
+
+	participants := fmt.Sprintf("%s OR %s", core.Eq("source"), core.Eq("destination"))
+	args := []interface{}{address, address}
+	if manager, ok := filters["manager"]; ok && manager != "" {
+		participants += fmt.Sprintf(" OR (%s AND %s)", core.Eq("source"), core.Eq("kind"))
+		args = append(args, manager, consts.Reveal)
+	}
+
+	query := storage.db.Query(models.DocOperations).
+		Where("network = ?", network).
+		Where(fmt.Sprintf("(%s)", participants), args...)
+
+	query, err := prepareOperationFilters(filters, query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := query.
+		Select(fmt.Sprintf("%s AS hash, coalesce(%s, 0) AS counter", core.Field("hash"), core.IntField("counter"))).
+		Group("1, 2, level").
+		Order("level desc").
+		Limit(size).
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resp := make([]opgForContract, 0)
+	for rows.Next() {
+		var opg opgForContract
+		if err := rows.Scan(&opg.Hash, &opg.Counter); err != nil {
+			return nil, err
+		}
+		resp = append(resp, opg)
+	}
+	return resp, rows.Err()
+}
+
+func prepareOperationFilters(filters map[string]interface{}, query *gorm.DB) (*gorm.DB, error) {
+	for k, v := range filters {
+		if v == "" || v == nil {
+			continue
+		}
+		switch k {
+		case "from":
+			query = query.Where(fmt.Sprintf("%s >= ?", core.TimeField("timestamp")), millisToTime(v))
+		case "to":
+			query = query.Where(fmt.Sprintf("%s <= ?", core.TimeField("timestamp")), millisToTime(v))
+		case "entrypoints":
+			query = query.Where(core.In("entrypoint"), splitFilterValues(v))
+		case "last_id":
+			lastID, err := strconv.ParseInt(fmt.Sprintf("%v", v), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			query = query.Where(fmt.Sprintf("%s < ?", core.IntField("indexed_time")), lastID)
+		case "status":
+			query = query.Where(core.In("status"), splitFilterValues(v))
+		case "kind":
+			query = query.Where(core.In("kind"), splitFilterValues(v))
+		case "manager":
+			// reveals of manager are selected with operations of contract
+		default:
+			return nil, errors.Errorf("Unknown operation filter: %s %v", k, v)
+		}
+	}
+	return query, nil
+}
+
+// splitFilterValues - splits filter value in format `'value1','value2'`
+func splitFilterValues(value interface{}) []string {
+	values := strings.Split(fmt.Sprintf("%v", value), ",")
+	for i := range values {
+		values[i] = strings.Trim(values[i], "' ")
+	}
+	return values
+}
+
+func millisToTime(value interface{}) time.Time {
+	ts, err := strconv.ParseInt(fmt.Sprintf("%v", value), 10, 64)
+	if err != nil {
+		return time.Unix(0, 0).UTC()
+	}
+	return time.Unix(0, ts*int64(time.Millisecond)).UTC()
+}
+
+// GetByContract -
+func (storage *Storage) GetByContract(network, address string, size uint64, filters map[string]interface{}) (po operation.Pageable, err error) {
+	opg, err := storage.getContractOPG(address, network, size, filters)
+	if err != nil {
+		return
+	}
+
+	po.Operations = make([]operation.Operation, 0)
+	if len(opg) == 0 {
+		po.LastID = "0"
+		return
+	}
+
+	conditions := make([]core.Condition, len(opg))
+	for i := range opg {
+		conditions[i] = core.NewCondition(
+			fmt.Sprintf("%s AND coalesce(%s, 0) = ?", core.Eq("hash"), core.IntField("counter")),
+			opg[i].Hash, opg[i].Counter,
+		)
+	}
+
+	query := storage.db.Query(models.DocOperations).Where("network = ?", network)
+	query = core.Or(conditions...).Apply(query).Order(sortString)
+
+	if err = storage.db.GetAllByQuery(query, &po.Operations); err != nil {
+		return
+	}
+
+	var lastID int64
+	for i := range po.Operations {
+		if lastID == 0 || po.Operations[i].IndexedTime < lastID {
+			lastID = po.Operations[i].IndexedTime
+		}
+	}
+	po.LastID = fmt.Sprintf("%d", lastID)
+	return
+}
+
+// Last -
+func (storage *Storage) Last(network, address string, indexedTime int64) (op operation.Operation, err error) {
+	query := storage.db.Query(models.DocOperations).
+		Where("network = ?", network).
+		Where(core.Eq("destination"), address).
+		Where(core.Eq("status"), consts.Applied).
+		Where(fmt.Sprintf("coalesce(%s, '') <> ''", core.Field("deffated_storage"))).
+		Where(fmt.Sprintf("%s < ?", core.IntField("indexed_time")), indexedTime).
+		Order(core.Order("indexed_time", true))
+
+	if err = storage.db.GetOne(query, &op); err != nil && storage.db.IsRecordNotFound(err) {
+		err = core.NewRecordNotFoundError(models.DocOperations, "")
+	}
+	return
+}
+
+// LastByLevel -
+func (storage *Storage) LastByLevel(network, address string, level int64) (op operation.Operation, err error) {
+	query := storage.db.Query(models.DocOperations).
+		Where("network = ?", network).
+		Where(core.Eq("destination"), address).
+		Where(core.Eq("status"), consts.Applied).
+		Where(fmt.Sprintf("coalesce(%s, '') <> ''", core.Field("deffated_storage"))).
+		Where(fmt.Sprintf("%s <= ?", core.IntField("level")), level).
+		Order(core.Order("indexed_time", true))
+
+	if err = storage.db.GetOne(query, &op); err != nil && storage.db.IsRecordNotFound(err) {
+		err = core.NewRecordNotFoundError(models.DocOperations, "")
+	}
+	return
+}
+
+// GetBalance -
+func (storage *Storage) GetBalance(network, address string, level int64) (balance int64, err error) {
+	err = storage.db.Query(models.DocOperations).
+		Where("network = ?", network).
+		Where(core.Eq("status"), consts.Applied).
+		Where(fmt.Sprintf("%s <= ?", core.IntField("level")), level).
+		Where(fmt.Sprintf("(%s OR %s)", core.Eq("source"), core.Eq("destination")), address, address).
+		Where(fmt.Sprintf("%s IS DISTINCT FROM %s", core.Field("source"), core.Field("destination"))).
+		Select(fmt.Sprintf(
+			"coalesce(sum(CASE WHEN %s = ? THEN %s ELSE -%s END), 0)",
+			core.Field("destination"), core.IntField("amount"), core.IntField("amount"),
+		), address).
+		Row().
+		Scan(&balance)
+	return
+}
+
+// Get -
+func (storage *Storage) Get(filters map[string]interface{}, size int64, sort bool) (operations []operation.Operation, err error) {
+	query, err := core.FiltersToQuery(storage.db.Query(models.DocOperations), filters)
+	if err != nil {
+		return
+	}
+
+	if sort {
+		query = query.Order(sortString)
+	}
+
+	if size > 0 {
+		query = query.Limit(size)
+	}
+
+	operations = make([]operation.Operation, 0)
+	err = storage.db.GetAllByQuery(query, &operations)
+	return
+}
+
+// GetStats -
+func (storage *Storage) GetStats(network, address string) (stats operation.Stats, err error) {
+	var lastAction *time.Time
+	err = storage.db.Query(models.DocOperations).
+		Where("network = ?", network).
+		Where(fmt.Sprintf("(%s OR %s)", core.Eq("source"), core.Eq("destination")), address, address).
+		Select(fmt.Sprintf("count(*), max(%s)", core.TimeField("timestamp"))).
+		Row().
+		Scan(&stats.Count, &lastAction)
+	if lastAction != nil {
+		stats.LastAction = lastAction.UTC()
+	}
+	return
+}
+
+// GetContract24HoursVolume -
+func (storage *Storage) GetContract24HoursVolume(network, address string, entrypoints []string) (float64, error) {
+	query := storage.db.Query(models.DocOperations).
+		Where("network = ?", network).
+		Where(core.Eq("destination"), address).
+		Where(core.Eq("status"), consts.Applied).
+		Where(fmt.Sprintf("%s > ?", core.TimeField("timestamp")), time.Now().UTC().Add(-24*time.Hour))
+
+	if len(entrypoints) > 0 {
+		query = query.Where(core.In("entrypoint"), entrypoints)
+	}
+
+	return storage.db.Sum(query, "amount")
+}
+
+// GetTokensStats -
+func (storage *Storage) GetTokensStats(network string, addresses, entrypoints []string) (map[string]operation.TokenUsageStats, error) {
+	query := storage.db.Query(models.DocOperations).
+		Where("network = ?", network).
+		Where(core.In("destination"), addresses).
+		Where(core.In("entrypoint"), entrypoints)
+
+	rows, err := query.
+		Select(fmt.Sprintf("%s, %s, count(*), coalesce(avg(%s), 0)", core.Field("destination"), core.Field("entrypoint"), core.FloatField("result.consumed_gas"))).
+		Group("1, 2").
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usageStats := make(map[string]operation.TokenUsageStats)
+	for rows.Next() {
+		var address, entrypoint string
+		var count int64
+		var avg float64
+		if err := rows.Scan(&address, &entrypoint, &count, &avg); err != nil {
+			return nil, err
+		}
+		if _, ok := usageStats[address]; !ok {
+			usageStats[address] = make(operation.TokenUsageStats)
+		}
+		usageStats[address][entrypoint] = operation.TokenMethodUsageStats{
+			Count:       count,
+			ConsumedGas: int64(avg),
+		}
+	}
+	return usageStats, rows.Err()
+}
+
+// GetParticipatingContracts -
+func (storage *Storage) GetParticipatingContracts(network string, fromLevel, toLevel int64) ([]string, error) {
+	rows, err := storage.db.Query(models.DocOperations).
+		Where("network = ?", network).
+		Where("level <= ?", fromLevel).
+		Where("level > ?", toLevel).
+		Select(fmt.Sprintf("coalesce(%s, ''), coalesce(%s, '')", core.Field("source"), core.Field("destination"))).
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exists := make(map[string]struct{})
+	addresses := make([]string, 0)
+	for rows.Next() {
+		var source, destination string
+		if err := rows.Scan(&source, &destination); err != nil {
+			return nil, err
+		}
+		for _, address := range []string{source, destination} {
+			if _, ok := exists[address]; !ok && helpers.IsContract(address) {
+				addresses = append(addresses, address)
+				exists[address] = struct{}{}
+			}
+		}
+	}
+
+	return addresses, rows.Err()
+}
+
+// GetContractLevels -
+func (storage *Storage) GetContractLevels(network, address string) (levels []int64, err error) {
+	err = storage.db.Query(models.DocOperations).
+		Where("network = ?", network).
+		Where(fmt.Sprintf("(%s OR %s)", core.Eq("source"), core.Eq("destination")), address, address).
+		Order("level asc").
+		Pluck("distinct level", &levels).Error
+	return
+}
+
+// RecalcStats -
+func (storage *Storage) RecalcStats(network, address string) (stats operation.ContractStats, err error) {
+	var lastAction *time.Time
+	err = storage.db.Query(models.DocOperations).
+		Where("network = ?", network).
+		Where(fmt.Sprintf("(%s OR %s)", core.Eq("source"), core.Eq("destination")), address, address).
+		Select(fmt.Sprintf(
+			"count(*), max(%s), coalesce(sum(CASE WHEN %s = ? THEN CASE WHEN %s = ? THEN %s ELSE -%s END ELSE 0 END), 0)",
+			core.TimeField("timestamp"), core.Field("status"), core.Field("destination"), core.IntField("amount"), core.IntField("amount"),
+		), consts.Applied, address).
+		Row().
+		Scan(&stats.TxCount, &lastAction, &stats.Balance)
+	if lastAction != nil {
+		stats.LastAction = lastAction.UTC()
+	}
+	return
+}
+
+// GetDAppStats -
+func (storage *Storage) GetDAppStats(network string, addresses []string, period string) (stats operation.DAppStats, err error) {
+	query := storage.db.Query(models.DocOperations).
+		Where("network = ?", network).
+		Where(core.Eq("status"), consts.Applied).
+		Where(core.In("destination"), addresses).
+		Where(fmt.Sprintf("%s IS NOT NULL", core.JSONField("entrypoint")))
+
+	if query, err = periodToRange(period, query); err != nil {
+		return
+	}
+
+	err = query.
+		Select(fmt.Sprintf("count(DISTINCT %s), count(*), coalesce(sum(%s), 0)", core.Field("source"), core.IntField("amount"))).
+		Row().
+		Scan(&stats.Users, &stats.Calls, &stats.Volume)
+	return
+}
+
+func periodToRange(period string, query *gorm.DB) (*gorm.DB, error) {
+	var interval string
+	switch period {
+	case "year":
+		interval = "1 year"
+	case "month":
+		interval = "1 month"
+	case "week":
+		interval = "1 week"
+	case "day":
+		interval = "1 day"
+	case "all":
+		return query, nil
+	default:
+		return nil, errors.Errorf("Unknown period value: %s", period)
+	}
+	return query.Where(fmt.Sprintf("%s >= date_trunc('day', now() - interval '%s')", core.TimeField("timestamp"), interval)), nil
+}
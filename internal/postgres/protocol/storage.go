@@ -0,0 +1,54 @@
+package protocol
+
+import (
+	"fmt"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/protocol"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// GetProtocol - returns current protocol for `network` and `level` (`hash` is optional, leave empty string for default)
+func (storage *Storage) GetProtocol(network, hash string, level int64) (p protocol.Protocol, err error) {
+	query := storage.db.Query(models.DocProtocol).
+		Where("network = ?", network)
+
+	if level > -1 {
+		query = query.Where(fmt.Sprintf("%s <= ?", core.IntField("start_level")), level)
+	}
+	if hash != "" {
+		query = query.Where(core.Eq("hash"), hash)
+	}
+	query = query.Order(core.Order("start_level", true))
+
+	err = storage.db.GetOne(query, &p)
+	return
+}
+
+// GetSymLinks - returns list of symlinks in `network` after `level`
+func (storage *Storage) GetSymLinks(network string, level int64) (map[string]struct{}, error) {
+	query := storage.db.Query(models.DocProtocol).
+		Where("network = ?", network).
+		Where(fmt.Sprintf("%s > ?", core.IntField("start_level")), level)
+
+	links, err := storage.db.GetUnique("sym_link", query)
+	if err != nil {
+		return nil, err
+	}
+
+	symMap := make(map[string]struct{})
+	for i := range links {
+		symMap[links[i]] = struct{}{}
+	}
+	return symMap, nil
+}
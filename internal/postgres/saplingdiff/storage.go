@@ -0,0 +1,65 @@
+package saplingdiff
+
+import (
+	"fmt"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/saplingdiff"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// Get -
+func (storage *Storage) Get(network string, ptr, size, offset int64) ([]saplingdiff.SaplingDiff, error) {
+	if size == 0 {
+		size = core.DefaultSize
+	}
+
+	query := storage.db.Query(models.DocSaplingDiffs).
+		Where("network = ?", network).
+		Where(fmt.Sprintf("%s = ?", core.IntField("ptr")), ptr).
+		Order(core.Order("indexed_time", true)).
+		Limit(size).
+		Offset(offset)
+
+	diffs := make([]saplingdiff.SaplingDiff, 0)
+	err := storage.db.GetAllByQuery(query, &diffs)
+	return diffs, err
+}
+
+// GetFirst -
+func (storage *Storage) GetFirst(network string, ptr int64) (diff saplingdiff.SaplingDiff, err error) {
+	query := storage.db.Query(models.DocSaplingDiffs).
+		Where("network = ?", network).
+		Where(fmt.Sprintf("%s = ?", core.IntField("ptr")), ptr).
+		Order(core.Order("indexed_time", false))
+
+	err = storage.db.GetOne(query, &diff)
+	return
+}
+
+// GetStats -
+func (storage *Storage) GetStats(network string, ptr, maxLevel int64) (stats saplingdiff.Stats, err error) {
+	query := storage.db.Query(models.DocSaplingDiffs).
+		Where("network = ?", network).
+		Where(fmt.Sprintf("%s = ?", core.IntField("ptr")), ptr)
+	if maxLevel > 0 {
+		query = query.Where("level <= ?", maxLevel)
+	}
+
+	row := query.Select(fmt.Sprintf(
+		"coalesce(sum(%s), 0), coalesce(sum(%s), 0), count(*)",
+		core.IntField("commitments_count"), core.IntField("nullifiers_count"),
+	)).Row()
+	err = row.Scan(&stats.Commitments, &stats.Nullifiers, &stats.Updates)
+	return
+}
@@ -0,0 +1,23 @@
+package schema
+
+import (
+	"github.com/baking-bad/bcdhub/internal/models/schema"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// Get -
+func (storage *Storage) Get(address string) (schema.Schema, error) {
+	data := schema.Schema{ID: address}
+	err := storage.db.GetByID(&data)
+	return data, err
+}
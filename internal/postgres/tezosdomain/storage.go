@@ -0,0 +1,58 @@
+package tezosdomain
+
+import (
+	"github.com/baking-bad/bcdhub/internal/helpers"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/tezosdomain"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+	"github.com/pkg/errors"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// ListDomains -
+func (storage *Storage) ListDomains(network string, size, offset int64) (tezosdomain.DomainsResponse, error) {
+	if size > core.DefaultSize {
+		size = core.DefaultSize
+	}
+
+	query := storage.db.Query(models.DocTezosDomains).
+		Where("network = ?", network).
+		Order(core.Order("timestamp", true)).
+		Limit(size).
+		Offset(offset)
+
+	domains := make([]tezosdomain.TezosDomain, 0)
+	total, err := storage.db.GetAllByQueryWithTotal(query, &domains)
+	if err != nil {
+		return tezosdomain.DomainsResponse{}, err
+	}
+
+	return tezosdomain.DomainsResponse{
+		Domains: domains,
+		Total:   total,
+	}, nil
+}
+
+// ResolveDomainByAddress -
+func (storage *Storage) ResolveDomainByAddress(network string, address string) (*tezosdomain.TezosDomain, error) {
+	if !helpers.IsAddress(address) {
+		return nil, errors.Errorf("Invalid address: %s", address)
+	}
+
+	query := storage.db.Query(models.DocTezosDomains).
+		Where("network = ?", network).
+		Where(core.Eq("address"), address)
+
+	var td tezosdomain.TezosDomain
+	err := storage.db.GetOne(query, &td)
+	return &td, err
+}
@@ -0,0 +1,58 @@
+package ticketbalance
+
+import (
+	"fmt"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// Update -
+func (storage *Storage) Update(updates []*ticketbalance.TicketBalance) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	ids := make([]string, len(updates))
+	for i := range updates {
+		ids[i] = updates[i].GetID()
+	}
+	buf := make([]ticketbalance.TicketBalance, 0)
+	if err := storage.db.GetByIDs(&buf, ids...); err != nil {
+		return err
+	}
+
+	items := make([]models.Model, 0, len(updates))
+	for i := range updates {
+		for j := range buf {
+			if buf[j].GetID() == updates[i].GetID() {
+				updates[i].Sum(&buf[j])
+				break
+			}
+		}
+		items = append(items, updates[i])
+	}
+	return storage.db.BulkInsert(items)
+}
+
+// GetAccountBalances - returns non-zero ticket balances of contract `address`
+func (storage *Storage) GetAccountBalances(network, address string) (balances []ticketbalance.TicketBalance, err error) {
+	query := storage.db.Query(models.DocTicketBalances).
+		Where("network = ?", network).
+		Where(core.Eq("address"), address).
+		Where(fmt.Sprintf("%s <> '0'", core.Field("balance")))
+
+	balances = make([]ticketbalance.TicketBalance, 0)
+	err = storage.db.GetAllByQuery(query, &balances)
+	return
+}
@@ -0,0 +1,27 @@
+package ticketupdate
+
+import (
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/ticketupdate"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// GetAll -
+func (storage *Storage) GetAll(network string, level int64) (updates []ticketupdate.TicketUpdate, err error) {
+	query := storage.db.Query(models.DocTicketUpdates).
+		Where("network = ? AND level > ?", network, level)
+
+	updates = make([]ticketupdate.TicketUpdate, 0)
+	err = storage.db.GetAllByQuery(query, &updates)
+	return
+}
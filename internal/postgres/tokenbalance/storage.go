@@ -0,0 +1,70 @@
+package tokenbalance
+
+import (
+	"fmt"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// Update - adds balance changes to stored balances in one transaction
+func (storage *Storage) Update(updates []*tokenbalance.TokenBalance) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	ids := make([]string, len(updates))
+	for i := range updates {
+		ids[i] = updates[i].GetID()
+	}
+	buf := make([]tokenbalance.TokenBalance, 0)
+	if err := storage.db.GetByIDs(&buf, ids...); err != nil {
+		return err
+	}
+
+	items := make([]models.Model, 0, len(updates))
+	for i := range updates {
+		for j := range buf {
+			if buf[j].GetID() == updates[i].GetID() {
+				updates[i].Sum(&buf[j])
+				break
+			}
+		}
+		items = append(items, updates[i])
+	}
+	return storage.db.BulkInsert(items)
+}
+
+// GetHolders -
+func (storage *Storage) GetHolders(network, contract string, tokenID string) ([]tokenbalance.TokenBalance, error) {
+	query := storage.db.Query(models.DocTokenBalances).
+		Where("network = ?", network).
+		Where(core.Eq("contract"), contract).
+		Where(core.Eq("token_id"), tokenID).
+		Where(fmt.Sprintf("%s <> '0'", core.Field("balance")))
+
+	balances := make([]tokenbalance.TokenBalance, 0)
+	err := storage.db.GetAllByQuery(query, &balances)
+	return balances, err
+}
+
+// GetAccountBalances -
+func (storage *Storage) GetAccountBalances(network, address string) ([]tokenbalance.TokenBalance, error) {
+	query := storage.db.Query(models.DocTokenBalances).
+		Where("network = ?", network).
+		Where(core.Eq("address"), address)
+
+	balances := make([]tokenbalance.TokenBalance, 0)
+	err := storage.db.GetAllByQuery(query, &balances)
+	return balances, err
+}
@@ -0,0 +1,28 @@
+package tokenmetadata
+
+import (
+	"github.com/baking-bad/bcdhub/internal/models/tokenmetadata"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+)
+
+// buildGetTokenMetadataContext - returns condition which matches any of contexts
+func buildGetTokenMetadataContext(ctx ...tokenmetadata.GetContext) core.Condition {
+	filters := make([]core.Condition, 0)
+	for _, c := range ctx {
+		filter := []core.Condition{core.NewCondition("true")}
+		if c.Contract != "" {
+			filter = append(filter, core.NewCondition(core.Eq("contract"), c.Contract))
+		}
+		if c.Network != "" {
+			filter = append(filter, core.NewCondition("network = ?", c.Network))
+		}
+		if c.Level.IsFilled() {
+			filter = append(filter, core.Comparator("level", c.Level))
+		}
+		if c.TokenID != "" {
+			filter = append(filter, core.NewCondition(core.Eq("token_id"), c.TokenID))
+		}
+		filters = append(filters, core.And(filter...))
+	}
+	return core.Or(filters...)
+}
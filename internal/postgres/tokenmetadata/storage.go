@@ -0,0 +1,29 @@
+package tokenmetadata
+
+import (
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/tokenmetadata"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// Get -
+func (storage *Storage) Get(ctx ...tokenmetadata.GetContext) (tokens []tokenmetadata.TokenMetadata, err error) {
+	query := storage.db.Query(models.DocTokenMetadata)
+	if len(ctx) > 0 {
+		query = buildGetTokenMetadataContext(ctx...).Apply(query)
+	}
+
+	tokens = make([]tokenmetadata.TokenMetadata, 0)
+	err = storage.db.GetAllByQuery(query, &tokens)
+	return
+}
@@ -0,0 +1,132 @@
+package transfer
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/helpers"
+	"github.com/baking-bad/bcdhub/internal/models/transfer"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+	"github.com/jinzhu/gorm"
+)
+
+func buildGetContext(ctx transfer.GetContext, query *gorm.DB) (*gorm.DB, error) {
+	query = filterNetwork(ctx, query)
+	query = filterAddress(ctx, query)
+	query = filterTime(ctx, query)
+	query, err := filterCursor(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	query = filterContracts(ctx, query)
+	query = filterTokenID(ctx, query)
+	query = filterHash(ctx, query)
+	query = filterCounter(ctx, query)
+	query = filterNonce(ctx, query)
+
+	query = appendSort(ctx, query)
+	query = appendOffset(ctx, query)
+	query = appendSize(ctx, query)
+	return query, nil
+}
+
+func filterNetwork(ctx transfer.GetContext, query *gorm.DB) *gorm.DB {
+	if ctx.Network != "" {
+		return query.Where("network = ?", ctx.Network)
+	}
+	return query
+}
+
+func filterHash(ctx transfer.GetContext, query *gorm.DB) *gorm.DB {
+	if ctx.Hash != "" {
+		return query.Where(core.Eq("hash"), ctx.Hash)
+	}
+	return query
+}
+
+func filterAddress(ctx transfer.GetContext, query *gorm.DB) *gorm.DB {
+	if ctx.Address == "" {
+		return query
+	}
+	return query.Where(fmt.Sprintf("(%s OR %s)", core.Eq("from"), core.Eq("to")), ctx.Address, ctx.Address)
+}
+
+func filterTokenID(ctx transfer.GetContext, query *gorm.DB) *gorm.DB {
+	if ctx.TokenID != "" {
+		return query.Where(core.Eq("token_id"), ctx.TokenID)
+	}
+	return query
+}
+
+func filterTime(ctx transfer.GetContext, query *gorm.DB) *gorm.DB {
+	if ctx.Start > 0 {
+		query = query.Where(fmt.Sprintf("%s >= ?", core.TimeField("timestamp")), millisToTime(ctx.Start))
+	}
+	if ctx.End > 0 {
+		query = query.Where(fmt.Sprintf("%s < ?", core.TimeField("timestamp")), millisToTime(ctx.End))
+	}
+	return query
+}
+
+func filterCursor(ctx transfer.GetContext, query *gorm.DB) (*gorm.DB, error) {
+	if ctx.LastID == "" {
+		return query, nil
+	}
+	lastID, err := strconv.ParseInt(ctx.LastID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	eq := "<"
+	if ctx.SortOrder == "asc" {
+		eq = ">"
+	}
+	return query.Where(fmt.Sprintf("%s %s ?", core.IntField("indexed_time"), eq), lastID), nil
+}
+
+func filterContracts(ctx transfer.GetContext, query *gorm.DB) *gorm.DB {
+	if len(ctx.Contracts) == 0 {
+		return query
+	}
+	return query.Where(core.In("contract"), ctx.Contracts)
+}
+
+func filterCounter(ctx transfer.GetContext, query *gorm.DB) *gorm.DB {
+	if ctx.Counter != nil {
+		return query.Where(fmt.Sprintf("%s = ?", core.IntField("counter")), *ctx.Counter)
+	}
+	return query
+}
+
+func filterNonce(ctx transfer.GetContext, query *gorm.DB) *gorm.DB {
+	if ctx.Nonce != nil {
+		return query.Where(fmt.Sprintf("%s = ?", core.IntField("nonce")), *ctx.Nonce)
+	}
+	return query
+}
+
+func appendSize(ctx transfer.GetContext, query *gorm.DB) *gorm.DB {
+	if ctx.Size > 0 && ctx.Size <= maxTransfersSize {
+		return query.Limit(ctx.Size)
+	}
+	return query.Limit(maxTransfersSize)
+}
+
+func appendOffset(ctx transfer.GetContext, query *gorm.DB) *gorm.DB {
+	if ctx.Offset > 0 && ctx.Offset <= maxTransfersSize {
+		return query.Offset(ctx.Offset)
+	}
+	return query
+}
+
+func appendSort(ctx transfer.GetContext, query *gorm.DB) *gorm.DB {
+	desc := true
+	if helpers.StringInArray(ctx.SortOrder, []string{"desc", "asc"}) {
+		desc = ctx.SortOrder == "desc"
+	}
+	return query.Order(core.Order("timestamp", desc)).Order(core.Order("indexed_time", desc))
+}
+
+func millisToTime(value uint) time.Time {
+	return time.Unix(0, int64(value)*int64(time.Millisecond)).UTC()
+}
@@ -0,0 +1,105 @@
+package transfer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/transfer"
+	"github.com/baking-bad/bcdhub/internal/models/tzip"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+)
+
+const (
+	maxTransfersSize = 10000
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// Get -
+func (storage *Storage) Get(ctx transfer.GetContext) (po transfer.Pageable, err error) {
+	query, err := buildGetContext(ctx, storage.db.Query(models.DocTransfers))
+	if err != nil {
+		return
+	}
+
+	po.Transfers = make([]transfer.Transfer, 0)
+	if po.Total, err = storage.db.GetAllByQueryWithTotal(query, &po.Transfers); err != nil {
+		return
+	}
+	if len(po.Transfers) > 0 {
+		po.LastID = fmt.Sprintf("%d", po.Transfers[len(po.Transfers)-1].IndexedTime)
+	}
+	return
+}
+
+// GetAll -
+func (storage *Storage) GetAll(network string, level int64) ([]transfer.Transfer, error) {
+	query := storage.db.Query(models.DocTransfers).
+		Where("network = ?", network).
+		Where("level > ?", level)
+
+	transfers := make([]transfer.Transfer, 0)
+	err := storage.db.GetAllByQuery(query, &transfers)
+	return transfers, err
+}
+
+// GetTokenSupply -
+func (storage *Storage) GetTokenSupply(network, address string, tokenID string) (result transfer.TokenSupply, err error) {
+	amount := core.FloatField("amount")
+	err = storage.db.Query(models.DocTransfers).
+		Where("network = ?", network).
+		Where(core.Eq("contract"), address).
+		Where(core.Eq("status"), consts.Applied).
+		Where(core.Eq("token_id"), tokenID).
+		Select(fmt.Sprintf(
+			`coalesce(sum(CASE WHEN coalesce(%[1]s, '') = '' THEN %[3]s WHEN coalesce(%[2]s, '') = '' THEN -%[3]s ELSE 0 END), 0),
+			coalesce(sum(CASE WHEN coalesce(%[1]s, '') <> '' AND coalesce(%[2]s, '') <> '' THEN %[3]s ELSE 0 END), 0)`,
+			core.Field("from"), core.Field("to"), amount,
+		)).
+		Row().
+		Scan(&result.Supply, &result.Transfered)
+	return
+}
+
+// GetToken24HoursVolume - returns token volume for last 24 hours
+func (storage *Storage) GetToken24HoursVolume(network, contract string, initiators, entrypoints []string, tokenID string) (float64, error) {
+	query := storage.db.Query(models.DocTransfers).
+		Where("network = ?", network).
+		Where(core.Eq("contract"), contract).
+		Where(core.Eq("status"), consts.Applied).
+		Where(core.Eq("token_id"), tokenID).
+		Where(fmt.Sprintf("%s > ?", core.TimeField("timestamp")), time.Now().UTC().Add(-24*time.Hour)).
+		Where(core.In("parent"), entrypoints).
+		Where(core.In("initiator"), initiators)
+
+	return storage.db.Sum(query, "amount")
+}
+
+// GetTokenVolumeSeries -
+func (storage *Storage) GetTokenVolumeSeries(network, period string, contracts []string, entrypoints []tzip.DAppContract, tokenID string) ([][]float64, error) {
+	query := storage.db.Query(models.DocTransfers).
+		Where("network = ?", network).
+		Where(core.Eq("status"), consts.Applied).
+		Where(core.Eq("token_id"), tokenID).
+		Where(fmt.Sprintf("%s IS DISTINCT FROM %s", core.Field("from"), core.Field("to")))
+
+	if len(contracts) > 0 {
+		query = query.Where(core.In("contract"), contracts)
+	}
+
+	if len(entrypoints) > 0 {
+		query = core.DexEntrypoints(query, entrypoints)
+	}
+
+	return core.Histogram(query, period, "sum", "amount")
+}
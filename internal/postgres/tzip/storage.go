@@ -0,0 +1,149 @@
+package tzip
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/tzip"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+	"github.com/jinzhu/gorm"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// Get -
+func (storage *Storage) Get(network, address string) (t tzip.TZIP, err error) {
+	t.Address = address
+	t.Network = network
+	err = storage.db.GetByID(&t)
+	return
+}
+
+// GetDApps -
+func (storage *Storage) GetDApps() ([]tzip.DApp, error) {
+	query := storage.db.Query(models.DocTZIP).
+		Where(fmt.Sprintf("%s IS NOT NULL", core.JSONField("dapps"))).
+		Order(fmt.Sprintf("%s asc", core.IntField("dapps.0.order")))
+
+	tzips := make([]tzip.TZIP, 0)
+	if err := storage.db.GetAllByQuery(query, &tzips); err != nil {
+		return nil, err
+	}
+	if len(tzips) == 0 {
+		return nil, core.NewRecordNotFoundError(models.DocTZIP, "")
+	}
+
+	dapps := make([]tzip.DApp, 0)
+	for i := range tzips {
+		dapps = append(dapps, tzips[i].DApps...)
+	}
+	return dapps, nil
+}
+
+// GetDAppBySlug -
+func (storage *Storage) GetDAppBySlug(slug string) (*tzip.DApp, error) {
+	filter, err := json.Marshal([]map[string]string{{"slug": slug}})
+	if err != nil {
+		return nil, err
+	}
+	query := storage.db.Query(models.DocTZIP).Where(core.Contains("dapps"), string(filter))
+
+	var model tzip.TZIP
+	if err := storage.getOne(query, &model); err != nil {
+		return nil, err
+	}
+	return &model.DApps[0], nil
+}
+
+// GetBySlug -
+func (storage *Storage) GetBySlug(slug string) (*tzip.TZIP, error) {
+	query := storage.db.Query(models.DocTZIP).Where(core.Eq("slug"), slug)
+
+	var model tzip.TZIP
+	err := storage.getOne(query, &model)
+	return &model, err
+}
+
+// GetAliasesMap -
+func (storage *Storage) GetAliasesMap(network string) (map[string]string, error) {
+	rows, err := storage.db.Query(models.DocTZIP).
+		Where("network = ?", network).
+		Select(fmt.Sprintf("%s, coalesce(%s, '')", core.Field("address"), core.Field("name"))).
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := make(map[string]string)
+	for rows.Next() {
+		var address, name string
+		if err := rows.Scan(&address, &name); err != nil {
+			return nil, err
+		}
+		aliases[address] = name
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(aliases) == 0 {
+		return nil, core.NewRecordNotFoundError(models.DocTZIP, "")
+	}
+	return aliases, nil
+}
+
+// GetAliases -
+func (storage *Storage) GetAliases(network string) ([]tzip.TZIP, error) {
+	query := storage.db.Query(models.DocTZIP).
+		Where("network = ?", network).
+		Where(fmt.Sprintf("%s IS NOT NULL", core.JSONField("name")))
+
+	return storage.getAll(query)
+}
+
+// GetAlias -
+func (storage *Storage) GetAlias(network, address string) (*tzip.TZIP, error) {
+	query := storage.db.Query(models.DocTZIP).
+		Where("network = ?", network).
+		Where(core.Eq("address"), address)
+
+	var data tzip.TZIP
+	err := storage.getOne(query, &data)
+	return &data, err
+}
+
+// GetWithEvents -
+func (storage *Storage) GetWithEvents() ([]tzip.TZIP, error) {
+	query := storage.db.Query(models.DocTZIP).
+		Where(fmt.Sprintf("%s IS NOT NULL", core.JSONField("events")))
+
+	return storage.getAll(query)
+}
+
+func (storage *Storage) getOne(query *gorm.DB, output *tzip.TZIP) error {
+	err := storage.db.GetOne(query, output)
+	if err != nil && storage.db.IsRecordNotFound(err) {
+		return core.NewRecordNotFoundError(models.DocTZIP, "")
+	}
+	return err
+}
+
+func (storage *Storage) getAll(query *gorm.DB) ([]tzip.TZIP, error) {
+	tzips := make([]tzip.TZIP, 0)
+	if err := storage.db.GetAllByQuery(query, &tzips); err != nil {
+		return nil, err
+	}
+	if len(tzips) == 0 {
+		return nil, core.NewRecordNotFoundError(models.DocTZIP, "")
+	}
+	return tzips, nil
+}
@@ -0,0 +1,99 @@
+package tzipversion
+
+import (
+	"fmt"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/tzipversion"
+	"github.com/baking-bad/bcdhub/internal/postgres/core"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Postgres
+}
+
+// NewStorage -
+func NewStorage(db *core.Postgres) *Storage {
+	return &Storage{db}
+}
+
+// Get -
+func (storage *Storage) Get(network, address string, size, offset int64) ([]tzipversion.Version, error) {
+	if size == 0 {
+		size = core.DefaultSize
+	}
+
+	query := storage.db.Query(models.DocTZIPVersions).
+		Where("network = ?", network).
+		Where(core.Eq("address"), address).
+		Order(core.Order("level", true)).
+		Limit(size).
+		Offset(offset)
+
+	versions := make([]tzipversion.Version, 0)
+	err := storage.db.GetAllByQuery(query, &versions)
+	return versions, err
+}
+
+// GetLast -
+func (storage *Storage) GetLast(network, address string) (version tzipversion.Version, err error) {
+	query := storage.db.Query(models.DocTZIPVersions).
+		Where("network = ?", network).
+		Where(core.Eq("address"), address).
+		Order(core.Order("level", true))
+
+	err = storage.db.GetOne(query, &version)
+	return
+}
+
+// GetPending -
+func (storage *Storage) GetPending(before, size int64) ([]tzipversion.Version, error) {
+	if size == 0 {
+		size = core.DefaultSize
+	}
+
+	query := storage.db.Query(models.DocTZIPVersions).
+		Where(core.Eq("status"), tzipversion.StatusPending).
+		Where(fmt.Sprintf("%s <= ?", core.IntField("next_attempt_at")), before).
+		Order(fmt.Sprintf("%s asc", core.IntField("next_attempt_at"))).
+		Limit(size)
+
+	versions := make([]tzipversion.Version, 0)
+	err := storage.db.GetAllByQuery(query, &versions)
+	return versions, err
+}
+
+// GetAfter -
+func (storage *Storage) GetAfter(network string, level int64) ([]tzipversion.Version, error) {
+	query := storage.db.Query(models.DocTZIPVersions).
+		Where("network = ?", network).
+		Where("level > ?", level)
+
+	versions := make([]tzipversion.Version, 0)
+	err := storage.db.GetAllByQuery(query, &versions)
+	return versions, err
+}
+
+// GetLastBefore -
+func (storage *Storage) GetLastBefore(network, address string, level int64) (version tzipversion.Version, err error) {
+	query := storage.db.Query(models.DocTZIPVersions).
+		Where("network = ?", network).
+		Where(core.Eq("address"), address).
+		Where(core.Eq("status"), tzipversion.StatusFetched).
+		Where("level <= ?", level).
+		Order(core.Order("level", true))
+
+	err = storage.db.GetOne(query, &version)
+	return
+}
+
+// GetReferenced -
+func (storage *Storage) GetReferenced() ([]tzipversion.Version, error) {
+	query := storage.db.Query(models.DocTZIPVersions).
+		Where(fmt.Sprintf("coalesce(%s, '') <> ''", core.Field("ref_key_hash")))
+
+	versions := make([]tzipversion.Version, 0)
+	err := storage.db.GetAllByQuery(query, &versions)
+	return versions, err
+}
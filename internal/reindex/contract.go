@@ -0,0 +1,285 @@
+package reindex
+
+import (
+	"strings"
+
+	"github.com/baking-bad/bcdhub/internal/config"
+	"github.com/baking-bad/bcdhub/internal/index"
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/balanceupdate"
+	"github.com/baking-bad/bcdhub/internal/models/bigmapaction"
+	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/models/protocol"
+	"github.com/baking-bad/bcdhub/internal/models/saplingdiff"
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
+	"github.com/baking-bad/bcdhub/internal/models/ticketupdate"
+	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
+	"github.com/baking-bad/bcdhub/internal/models/transfer"
+	"github.com/baking-bad/bcdhub/internal/mq"
+	"github.com/baking-bad/bcdhub/internal/noderpc"
+	"github.com/baking-bad/bcdhub/internal/parsers/operations"
+	"github.com/tidwall/gjson"
+)
+
+// ContractIndices - indices which are cleared and filled again by contract reindex.
+// Contract document, its metadata, TZIP and migrations are kept as they are not rebuilt from operations.
+var ContractIndices = []string{
+	models.DocBalanceUpdates,
+	models.DocBigMapActions,
+	models.DocBigMapDiff,
+	models.DocOperations,
+	models.DocSaplingDiffs,
+	models.DocTicketBalances,
+	models.DocTicketUpdates,
+	models.DocTokenBalances,
+	models.DocTransfers,
+}
+
+// Contract - deletes indexed data of single contract and replays its operations from node
+type Contract struct {
+	ctx     *config.Context
+	rpc     noderpc.INode
+	indexer index.Indexer
+
+	network string
+	address string
+
+	tokenBalances  tokenbalance.Repository
+	ticketBalances ticketbalance.Repository
+	protocols      map[string]protocol.Protocol
+}
+
+// ContractOption -
+type ContractOption func(*Contract)
+
+// WithExternalIndexer - sets external indexer which returns blocks with contract operations to replay
+func WithExternalIndexer(indexer index.Indexer) ContractOption {
+	return func(c *Contract) {
+		c.indexer = indexer
+	}
+}
+
+// NewContract -
+func NewContract(ctx *config.Context, rpc noderpc.INode, network, address string, opts ...ContractOption) *Contract {
+	c := &Contract{
+		ctx:            ctx,
+		rpc:            rpc,
+		network:        network,
+		address:        address,
+		tokenBalances:  contractTokenBalances{ctx.TokenBalances, address},
+		ticketBalances: contractTicketBalances{ctx.TicketBalances, address},
+		protocols:      make(map[string]protocol.Protocol),
+	}
+	for i := range opts {
+		opts[i](c)
+	}
+	return c
+}
+
+// Reindex - replays operations of contract up to the indexed head of network
+func (c *Contract) Reindex() error {
+	state, err := c.ctx.Blocks.Last(c.network)
+	if err != nil {
+		return err
+	}
+
+	item, err := c.ctx.Contracts.Get(map[string]interface{}{
+		"network": c.network,
+		"address": c.address,
+	})
+	if err != nil {
+		return err
+	}
+
+	levels, err := c.getLevels(item.Level, state.Level)
+	if err != nil {
+		return err
+	}
+	logger.Info("[%s] Found %d blocks for %s since %d", c.network, len(levels), c.address, item.Level)
+
+	if err := c.ctx.Storage.DeleteByContract(ContractIndices, c.network, c.address); err != nil {
+		return err
+	}
+	if err := c.ctx.Storage.Refresh(ContractIndices); err != nil {
+		return err
+	}
+
+	for _, level := range levels {
+		if err := c.replay(level); err != nil {
+			return err
+		}
+	}
+
+	return c.ctx.MQ.SendRaw(mq.QueueRecalc, []byte(item.GetID()))
+}
+
+// getLevels - returns levels of blocks to replay up to `head` including origination `level`. Levels are received before contract data is deleted.
+func (c *Contract) getLevels(level, head int64) ([]int64, error) {
+	operationLevels, err := c.getOperationLevels(level, head)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := []int64{level}
+	for i := range operationLevels {
+		if operationLevels[i] > level && operationLevels[i] <= head {
+			levels = append(levels, operationLevels[i])
+		}
+	}
+	return levels, nil
+}
+
+// getOperationLevels - returns blocks with contract operations from external indexer. Levels of indexed operations of contract are used if external indexer is not set or unavailable.
+func (c *Contract) getOperationLevels(level, head int64) ([]int64, error) {
+	if c.indexer != nil {
+		blocks, err := c.indexer.GetContractOperationBlocks(level, head, true)
+		if err == nil {
+			return blocks, nil
+		}
+		logger.Warning("[%s] External indexer is unavailable, indexed levels of %s are replayed: %s", c.network, c.address, err)
+	}
+	return c.ctx.Operations.GetContractLevels(c.network, c.address)
+}
+
+func (c *Contract) replay(level int64) error {
+	if level <= 1 {
+		return nil
+	}
+
+	head, err := c.rpc.GetHeader(level)
+	if err != nil {
+		return err
+	}
+	data, err := c.rpc.GetOperations(level)
+	if err != nil {
+		return err
+	}
+	proto, err := c.getProtocol(head)
+	if err != nil {
+		return err
+	}
+
+	parsedModels := make([]models.Model, 0)
+	for _, opg := range data.Array() {
+		if !strings.Contains(opg.Raw, c.address) {
+			continue
+		}
+		opgModels, err := c.parse(head, proto, opg)
+		if err != nil {
+			return err
+		}
+		parsedModels = append(parsedModels, opgModels...)
+	}
+	if len(parsedModels) == 0 {
+		return nil
+	}
+
+	logger.Info("[%s] %d block: %d models of %s", c.network, level, len(parsedModels), c.address)
+	if err := c.ctx.Storage.BulkInsert(parsedModels); err != nil {
+		return err
+	}
+	for i := range parsedModels {
+		if err := c.ctx.MQ.Send(parsedModels[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Contract) parse(head noderpc.Header, proto protocol.Protocol, opg gjson.Result) ([]models.Model, error) {
+	parser := operations.NewGroup(operations.NewParseParams(
+		c.rpc,
+		c.ctx.Storage, c.ctx.BigMapDiffs, c.ctx.Blocks, c.ctx.TZIP, c.ctx.Schema, c.tokenBalances,
+		operations.WithConstants(proto.Constants),
+		operations.WithHead(head),
+		operations.WithIPFSGateways(c.ctx.Config.IPFSGateways),
+		operations.WithInterfaces(c.ctx.Interfaces.All()),
+		operations.WithShareDirectory(c.ctx.SharePath),
+		operations.WithNetwork(c.network),
+		operations.WithTicketBalances(c.ticketBalances),
+	))
+	parsed, err := parser.Parse(opg)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Model, 0)
+	for i := range parsed {
+		if c.isContractModel(parsed[i]) {
+			result = append(result, parsed[i])
+		}
+	}
+	return result, nil
+}
+
+// isContractModel - checks that `model` is stored in one of `ContractIndices` and belongs to reindexed contract
+func (c *Contract) isContractModel(model models.Model) bool {
+	switch m := model.(type) {
+	case *balanceupdate.BalanceUpdate:
+		return m.Contract == c.address
+	case *bigmapaction.BigMapAction:
+		return m.Address == c.address
+	case *bigmapdiff.BigMapDiff:
+		return m.Address == c.address
+	case *operation.Operation:
+		return m.Source == c.address || m.Destination == c.address
+	case *saplingdiff.SaplingDiff:
+		return m.Contract == c.address
+	case *ticketupdate.TicketUpdate:
+		return m.Address == c.address
+	case *transfer.Transfer:
+		return m.Contract == c.address
+	default:
+		return false
+	}
+}
+
+func (c *Contract) getProtocol(head noderpc.Header) (protocol.Protocol, error) {
+	if proto, ok := c.protocols[head.Protocol]; ok {
+		return proto, nil
+	}
+	proto, err := c.ctx.Protocols.GetProtocol(c.network, head.Protocol, head.Level)
+	if err != nil {
+		return proto, err
+	}
+	c.protocols[head.Protocol] = proto
+	return proto, nil
+}
+
+// contractTokenBalances - applies only updates of token balances of the reindexed contract. Balances of other contracts are already up to date.
+type contractTokenBalances struct {
+	tokenbalance.Repository
+
+	address string
+}
+
+// Update -
+func (r contractTokenBalances) Update(updates []*tokenbalance.TokenBalance) error {
+	filtered := make([]*tokenbalance.TokenBalance, 0, len(updates))
+	for i := range updates {
+		if updates[i].Contract == r.address {
+			filtered = append(filtered, updates[i])
+		}
+	}
+	return r.Repository.Update(filtered)
+}
+
+// contractTicketBalances - applies only updates of ticket balances of the reindexed contract
+type contractTicketBalances struct {
+	ticketbalance.Repository
+
+	address string
+}
+
+// Update -
+func (r contractTicketBalances) Update(updates []*ticketbalance.TicketBalance) error {
+	filtered := make([]*ticketbalance.TicketBalance, 0, len(updates))
+	for i := range updates {
+		if updates[i].Address == r.address {
+			filtered = append(filtered, updates[i])
+		}
+	}
+	return r.Repository.Update(filtered)
+}
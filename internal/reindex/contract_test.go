@@ -0,0 +1,137 @@
+package reindex
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/baking-bad/bcdhub/internal/config"
+	"github.com/baking-bad/bcdhub/internal/index"
+	"github.com/baking-bad/bcdhub/internal/models"
+	mock_operation "github.com/baking-bad/bcdhub/internal/models/mock/operation"
+	mock_tokenbalance "github.com/baking-bad/bcdhub/internal/models/mock/tokenbalance"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
+	"github.com/baking-bad/bcdhub/internal/models/transfer"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+)
+
+func TestContractTokenBalances_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_tokenbalance.NewMockRepository(ctrl)
+	own := &tokenbalance.TokenBalance{Contract: "KT1reindexed", Address: "tz1holder", Value: big.NewInt(10)}
+	other := &tokenbalance.TokenBalance{Contract: "KT1other", Address: "tz1holder", Value: big.NewInt(5)}
+	repo.EXPECT().Update([]*tokenbalance.TokenBalance{own}).Return(nil)
+
+	balances := contractTokenBalances{repo, "KT1reindexed"}
+	if err := balances.Update([]*tokenbalance.TokenBalance{own, other}); err != nil {
+		t.Errorf("Update() error = %v", err)
+	}
+}
+
+func TestContract_isContractModel(t *testing.T) {
+	c := &Contract{address: "KT1reindexed"}
+	tests := []struct {
+		name  string
+		model models.Model
+		want  bool
+	}{
+		{
+			name:  "call of contract",
+			model: &operation.Operation{Source: "tz1sender", Destination: "KT1reindexed"},
+			want:  true,
+		}, {
+			name:  "internal call from contract",
+			model: &operation.Operation{Source: "KT1reindexed", Destination: "KT1other"},
+			want:  true,
+		}, {
+			name:  "call of other contract",
+			model: &operation.Operation{Source: "tz1sender", Destination: "KT1other"},
+			want:  false,
+		}, {
+			name:  "transfer of contract token",
+			model: &transfer.Transfer{Contract: "KT1reindexed"},
+			want:  true,
+		}, {
+			name:  "transfer of other token",
+			model: &transfer.Transfer{Contract: "KT1other", To: "KT1reindexed"},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.isContractModel(tt.model); got != tt.want {
+				t.Errorf("isContractModel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContract_getLevels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_operation.NewMockRepository(ctrl)
+	repo.EXPECT().GetContractLevels("mainnet", "KT1reindexed").Return([]int64{100, 105, 110, 120}, nil)
+
+	c := NewContract(&config.Context{Operations: repo}, nil, "mainnet", "KT1reindexed")
+	got, err := c.getLevels(100, 110)
+	if err != nil {
+		t.Errorf("getLevels() error = %v", err)
+		return
+	}
+	if want := []int64{100, 105, 110}; !reflect.DeepEqual(got, want) {
+		t.Errorf("getLevels() = %v, want %v", got, want)
+	}
+}
+
+type externalIndexer struct {
+	index.Indexer
+
+	blocks []int64
+	err    error
+}
+
+func (e externalIndexer) GetContractOperationBlocks(startBlock, endBlock int64, skipDelegatorBlocks bool) ([]int64, error) {
+	return e.blocks, e.err
+}
+
+func TestContract_getLevels_ExternalIndexer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_operation.NewMockRepository(ctrl)
+	indexer := externalIndexer{blocks: []int64{100, 102, 107, 110}}
+
+	c := NewContract(&config.Context{Operations: repo}, nil, "mainnet", "KT1reindexed", WithExternalIndexer(indexer))
+	got, err := c.getLevels(100, 110)
+	if err != nil {
+		t.Errorf("getLevels() error = %v", err)
+		return
+	}
+	if want := []int64{100, 102, 107, 110}; !reflect.DeepEqual(got, want) {
+		t.Errorf("getLevels() = %v, want %v", got, want)
+	}
+}
+
+func TestContract_getLevels_ExternalIndexerUnavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_operation.NewMockRepository(ctrl)
+	repo.EXPECT().GetContractLevels("mainnet", "KT1reindexed").Return([]int64{100, 105}, nil)
+	indexer := externalIndexer{err: errors.New("timeout")}
+
+	c := NewContract(&config.Context{Operations: repo}, nil, "mainnet", "KT1reindexed", WithExternalIndexer(indexer))
+	got, err := c.getLevels(100, 110)
+	if err != nil {
+		t.Errorf("getLevels() error = %v", err)
+		return
+	}
+	if want := []int64{100, 105}; !reflect.DeepEqual(got, want) {
+		t.Errorf("getLevels() = %v, want %v", got, want)
+	}
+}
@@ -24,6 +24,8 @@ func (storage *Storage) GetBalance(network, address string) (int64, error) {
 	query.AggregateSum("change")
 
 	it := query.Exec()
+	defer it.Close()
+
 	if it.Error() != nil {
 		return 0, it.Error()
 	}
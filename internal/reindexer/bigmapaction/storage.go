@@ -28,18 +28,7 @@ func (storage *Storage) Get(ptr int64, network string) ([]bigmapaction.BigMapAct
 		CloseBracket().
 		Sort("indexed_time", true)
 
-	it := query.Exec()
-	defer it.Close()
-
-	if it.Error() != nil {
-		return nil, it.Error()
-	}
-
 	result := make([]bigmapaction.BigMapAction, 0)
-	for it.Next() {
-		action := it.Object().(*bigmapaction.BigMapAction)
-		result = append(result, *action)
-	}
-
-	return result, nil
+	err := storage.db.GetAllByQuery(query, &result)
+	return result, err
 }
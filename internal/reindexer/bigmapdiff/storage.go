@@ -37,6 +37,24 @@ func (storage *Storage) CurrentByKey(network, keyHash string, ptr int64) (data b
 	return
 }
 
+// CurrentByKeyAtLevel - returns the last diff of the key at or before `level`
+func (storage *Storage) CurrentByKeyAtLevel(network, keyHash string, ptr, level int64) (data bigmapdiff.BigMapDiff, err error) {
+	if ptr < 0 {
+		err = errors.Errorf("Invalid pointer value: %d", ptr)
+		return
+	}
+
+	query := storage.db.Query(models.DocBigMapDiff).
+		Match("network", network).
+		Match("key_hash", keyHash).
+		WhereInt64("ptr", reindexer.EQ, ptr).
+		WhereInt64("level", reindexer.LE, level).
+		Sort("level", true)
+
+	err = storage.db.GetOne(query, &data)
+	return
+}
+
 // GetForAddress -
 func (storage *Storage) GetForAddress(address string) ([]bigmapdiff.BigMapDiff, error) {
 	query := storage.db.Query(models.DocBigMapDiff).
@@ -151,29 +169,17 @@ func (storage *Storage) GetByOperationID(operationID string) ([]*bigmapdiff.BigM
 	return result, err
 }
 
-// GetByPtr -
-// TODO: check
+// GetByPtr - returns the latest diff of every key of big map `ptr`
 func (storage *Storage) GetByPtr(address, network string, ptr int64) ([]bigmapdiff.BigMapDiff, error) {
 	query := storage.db.Query(models.DocBigMapDiff).
-		Match("network", network).
-		Match("address", address).
-		WhereInt64("ptr", reindexer.EQ, ptr)
-
-	keyHash, err := storage.db.GetUnique("key_hash", query)
-	if err != nil {
-		return nil, err
-	}
-
-	secondQuery := storage.db.Query(models.DocBigMapDiff).
-		Match("key_hash", keyHash...).
 		Match("network", network).
 		Match("address", address).
 		WhereInt64("ptr", reindexer.EQ, ptr).
 		Sort("indexed_time", true)
 
-	response := make([]bigmapdiff.BigMapDiff, 0)
-	err = storage.db.GetAllByQuery(secondQuery, &response)
-	return response, err
+	return storage.getTop(query, func(bmd bigmapdiff.BigMapDiff) string {
+		return bmd.KeyHash
+	})
 }
 
 // Get -
@@ -19,6 +19,8 @@ func NewStorage(db *core.Reindexer) *Storage {
 
 // Get -
 func (storage *Storage) Get(network string, level int64) (block block.Block, err error) {
+	block.Network = network
+
 	query := storage.db.Query(models.DocBlocks).
 		WhereString("network", reindexer.EQ, network).
 		WhereInt64("level", reindexer.EQ, level)
@@ -33,7 +35,10 @@ func (storage *Storage) Last(network string) (block block.Block, err error) {
 		WhereString("network", reindexer.EQ, network).
 		Sort("level", true)
 
-	err = storage.db.GetOne(query, &block)
+	if err = storage.db.GetOne(query, &block); err != nil && storage.db.IsRecordNotFound(err) {
+		block.Network = network
+		return block, nil
+	}
 	return
 }
 
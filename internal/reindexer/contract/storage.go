@@ -1,13 +1,16 @@
 package contract
 
 import (
+	"fmt"
 	"math/rand"
+	"time"
 
 	"github.com/baking-bad/bcdhub/internal/models"
 	"github.com/baking-bad/bcdhub/internal/models/contract"
 	"github.com/baking-bad/bcdhub/internal/reindexer/core"
 	"github.com/pkg/errors"
 	"github.com/restream/reindexer"
+	"github.com/tidwall/gjson"
 )
 
 // Storage -
@@ -44,16 +47,31 @@ func (storage *Storage) GetMany(by map[string]interface{}) (contracts []contract
 	return
 }
 
+// GetPage - returns up to `size` contracts of `network` with address greater than `lastAddress` sorted by address
+func (storage *Storage) GetPage(network, lastAddress string, size int64) (contracts []contract.Contract, err error) {
+	query := storage.db.Query(models.DocContracts).
+		Match("network", network).
+		WhereString("address", reindexer.GT, lastAddress).
+		Sort("address", false).
+		Limit(int(size))
+
+	err = storage.db.GetAllByQuery(query, &contracts)
+	return
+}
+
 // GetRandom -
 func (storage *Storage) GetRandom(network string) (c contract.Contract, err error) {
-	query := storage.db.Query(models.DocContracts).WhereInt("tx_count", reindexer.GE, 2)
+	query := storage.db.Query(models.DocContracts).Match("network", network).WhereInt("tx_count", reindexer.GE, 2)
 	count, err := storage.db.Count(query)
 	if err != nil {
 		return c, err
 	}
+	if count == 0 {
+		return c, core.NewRecordNotFoundError(models.DocContracts, "")
+	}
 
 	idx := rand.Intn(int(count))
-	secondQuery := storage.db.Query(models.DocContracts).WhereInt("tx_count", reindexer.GE, 2).Limit(1).Offset(idx)
+	secondQuery := storage.db.Query(models.DocContracts).Match("network", network).WhereInt("tx_count", reindexer.GE, 2).Offset(idx)
 	err = storage.db.GetOne(secondQuery, &c)
 	return
 }
@@ -76,13 +94,11 @@ func (storage *Storage) IsFA(network, address string) (bool, error) {
 // UpdateMigrationsCount -
 func (storage *Storage) UpdateMigrationsCount(address, network string) error {
 	contract := contract.NewEmptyContract(network, address)
-	it := storage.db.Query(models.DocOperations).
-		Where("id", reindexer.EQ, contract.GetID()).
-		Set("migrations_count", "migrations_count + 1").
-		Update()
-	defer it.Close()
-
-	return it.Error()
+	if err := storage.db.GetByID(&contract); err != nil {
+		return err
+	}
+	contract.MigrationsCount++
+	return storage.db.UpdateDoc(&contract)
 }
 
 // GetAddressesByNetworkAndLevel -
@@ -144,9 +160,43 @@ func (storage *Storage) GetByAddresses(addresses []contract.Address) (contracts
 // GetProjectsLastContract -
 func (storage *Storage) GetProjectsLastContract(c *contract.Contract) ([]contract.Contract, error) {
 	query := storage.db.Query(models.DocContracts).Sort("timestamp", true)
-	return storage.topContracts(query, func(c contract.Contract) string {
+
+	if c != nil {
+		query = query.OpenBracket()
+		if c.Manager != "" {
+			query = query.Match("manager", c.Manager).Or()
+		}
+		if c.Language != "" {
+			query = query.Match("language", c.Language).Or()
+		}
+		for field, values := range map[string][]string{
+			"tags":         c.Tags,
+			"annotations":  c.Annotations,
+			"fail_strings": c.FailStrings,
+			"entrypoints":  c.Entrypoints,
+		} {
+			if len(values) > 0 {
+				query = query.WhereString(field, reindexer.SET, values...).Or()
+			}
+		}
+		query = query.OpenBracket().
+			Match("fingerprint.parameter", c.Fingerprint.Parameter).
+			Match("fingerprint.storage", c.Fingerprint.Storage).
+			Match("fingerprint.code", c.Fingerprint.Code).
+			CloseBracket().
+			CloseBracket()
+	}
+
+	contracts, err := storage.topContracts(query, func(c contract.Contract) string {
 		return c.ProjectID
 	})
+	if err != nil {
+		return nil, err
+	}
+	if len(contracts) == 0 {
+		return nil, core.NewRecordNotFoundError(models.DocContracts, "")
+	}
+	return contracts, nil
 }
 
 // GetSameContracts -
@@ -211,28 +261,22 @@ func (storage *Storage) GetSimilarContracts(c contract.Contract, size, offset in
 		count[bucket.Values[0]] = bucket.Count
 	}
 
-	sit := storage.db.Query(models.DocContracts).Match("hash", hash...).Exec()
-	defer it.Close()
-
-	if sit.Error() != nil {
-		return nil, 0, sit.Error()
+	similar := make([]contract.Contract, 0)
+	if err := storage.db.GetAllByQuery(storage.db.Query(models.DocContracts).Match("hash", hash...), &similar); err != nil {
+		return nil, 0, err
 	}
 
 	found := make(map[string]struct{})
 	contracts := make([]contract.Similar, 0)
-
-	for sit.Next() {
-		var c contract.Contract
-		sit.NextObj(&c)
-		if _, ok := found[c.Hash]; ok {
+	for i := range similar {
+		if _, ok := found[similar[i].Hash]; ok {
 			continue
 		}
-		found[c.Hash] = struct{}{}
+		found[similar[i].Hash] = struct{}{}
 		contracts = append(contracts, contract.Similar{
-			Contract: &c,
-			Count:    int64(count[c.Hash]),
+			Contract: &similar[i],
+			Count:    int64(count[similar[i].Hash]),
 		})
-
 	}
 
 	total := len(it.AggResults()[0].Distincts)
@@ -241,13 +285,60 @@ func (storage *Storage) GetSimilarContracts(c contract.Contract, size, offset in
 
 // GetDiffTasks -
 func (storage *Storage) GetDiffTasks() ([]contract.DiffTask, error) {
-	return nil, nil
+	query := storage.db.Query(models.DocContracts).
+		Select("network", "address", "project_id", "hash", "last_action").
+		Not().
+		WhereString("project_id", reindexer.EMPTY)
+
+	documents, err := storage.db.GetRaw(query)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make(map[string][]string)
+	last := make(map[string]gjson.Result)
+	for i := range documents {
+		projectID := documents[i].Get("project_id").String()
+		key := fmt.Sprintf("%s_%s", projectID, documents[i].Get("hash").String())
+
+		current, ok := last[key]
+		if !ok {
+			projects[projectID] = append(projects[projectID], key)
+		}
+		if !ok || current.Get("last_action").Int() < documents[i].Get("last_action").Int() {
+			last[key] = documents[i]
+		}
+	}
+
+	tasks := make([]contract.DiffTask, 0)
+	for _, similar := range projects {
+		if len(similar) < 2 {
+			continue
+		}
+
+		for i := 0; i < len(similar)-1; i++ {
+			current := last[similar[i]]
+			for j := i + 1; j < len(similar); j++ {
+				next := last[similar[j]]
+				tasks = append(tasks, contract.DiffTask{
+					Network1: current.Get("network").String(),
+					Address1: current.Get("address").String(),
+					Network2: next.Get("network").String(),
+					Address2: next.Get("address").String(),
+				})
+			}
+		}
+	}
+
+	rand.Seed(time.Now().Unix())
+	rand.Shuffle(len(tasks), func(i, j int) { tasks[i], tasks[j] = tasks[j], tasks[i] })
+	return tasks, nil
 }
 
 // GetTokens -
 func (storage *Storage) GetTokens(network, tokenInterface string, offset, size int64) ([]contract.Contract, int64, error) {
 	tags := []string{"fa12", "fa1", "fa2"}
-	if tokenInterface == "fa12" || tokenInterface == "fa1" || tokenInterface == "fa2" {
+	if tokenInterface != "" {
 		tags = []string{tokenInterface}
 	}
 
@@ -295,25 +386,10 @@ func (storage *Storage) topContracts(query *reindexer.Query, idFunc func(c contr
 
 // UpdateField -
 func (storage *Storage) UpdateField(where []contract.Contract, fields ...string) error {
-	if len(where) == 0 {
-		return nil
-	}
-	tx, err := storage.db.BeginTx(models.DocContracts)
-	if err != nil {
-		return err
-	}
 	for i := range where {
-		query := tx.Query().Match("id", where[i].GetID())
-		for j := range fields {
-			value := storage.db.GetFieldValue(where[i], fields[j])
-			query = query.Set(fields[j], value)
-		}
-		it := query.Update()
-		defer it.Close()
-
-		if it.Error() != nil {
-			return it.Error()
+		if err := storage.db.UpdateFields(models.DocContracts, where[i].GetID(), &where[i], fields...); err != nil {
+			return err
 		}
 	}
-	return tx.Commit()
+	return nil
 }
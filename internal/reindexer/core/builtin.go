@@ -0,0 +1,9 @@
+//go:build builtin
+// +build builtin
+
+package core
+
+import (
+	// embedded reindexer by `builtin://` scheme. It requires cgo and reindexer library.
+	_ "github.com/restream/reindexer/bindings/builtin"
+)
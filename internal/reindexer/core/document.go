@@ -0,0 +1,127 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// document - returns JSON representation of model with its identifier.
+// Time fields are stored as unix timestamps to be comparable in queries.
+func document(model models.Model) ([]byte, error) {
+	data, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	data, err = sjson.SetBytes(data, "id", model.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range timeFields(reflect.TypeOf(model)) {
+		value := gjson.GetBytes(data, field)
+		if value.Type != gjson.String {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339Nano, value.String())
+		if err != nil {
+			return nil, err
+		}
+		if data, err = sjson.SetBytes(data, field, ts.Unix()); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// decode - unmarshals document to `output`. Identifier of document is stored to `ID` field since models do not marshal it.
+// If `output` is not a struct the first field of document is decoded (it's used for queries with single selected field).
+func decode(item gjson.Result, output interface{}) error {
+	val := reflect.ValueOf(output)
+	if val.Kind() != reflect.Ptr {
+		return errors.Errorf("Invalid `output` type: %s", val.Kind())
+	}
+
+	if val.Elem().Kind() != reflect.Struct {
+		var first gjson.Result
+		item.ForEach(func(_, value gjson.Result) bool {
+			first = value
+			return false
+		})
+		return json.Unmarshal([]byte(first.Raw), output)
+	}
+
+	data := []byte(item.Raw)
+	for _, field := range timeFields(val.Type()) {
+		value := item.Get(field)
+		if value.Type != gjson.Number {
+			continue
+		}
+		var err error
+		if data, err = sjson.SetBytes(data, field, time.Unix(value.Int(), 0).UTC().Format(time.RFC3339)); err != nil {
+			return err
+		}
+	}
+
+	if err := json.Unmarshal(data, output); err != nil {
+		return err
+	}
+
+	if id := val.Elem().FieldByName("ID"); id.IsValid() && id.Kind() == reflect.String && id.CanSet() {
+		id.SetString(item.Get("id").String())
+	}
+	return nil
+}
+
+// timeFields - returns JSON names of time fields of struct
+func timeFields(typ reflect.Type) []string {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := make([]string, 0)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType != timeType {
+			continue
+		}
+		if name := jsonName(typ, field.Name); name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// jsonName - returns JSON name of struct field
+func jsonName(typ reflect.Type, field string) string {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return field
+	}
+	f, ok := typ.FieldByName(field)
+	if !ok {
+		return field
+	}
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return field
+	}
+	return name
+}
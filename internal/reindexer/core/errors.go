@@ -3,18 +3,19 @@ package core
 import (
 	"strings"
 
-	"github.com/elastic/go-elasticsearch/v8/esapi"
 	"github.com/pkg/errors"
 )
 
 // default errors
 var (
 	ErrQueryPointerIsNil = errors.New("Query pointer is nil")
+	ErrNotSupported      = errors.New("Operation is not supported by reindexer storage")
 )
 
 // IsRecordNotFound -
 func (r *Reindexer) IsRecordNotFound(err error) bool {
-	return false
+	var target *RecordNotFoundError
+	return errors.As(err, &target)
 }
 
 // RecordNotFoundError -
@@ -28,11 +29,6 @@ func NewRecordNotFoundError(index, id string) *RecordNotFoundError {
 	return &RecordNotFoundError{index, id}
 }
 
-// NewRecordNotFoundErrorFromResponse -
-func NewRecordNotFoundErrorFromResponse(resp *esapi.Response) *RecordNotFoundError {
-	return &RecordNotFoundError{resp.String(), ""}
-}
-
 // Error -
 func (e *RecordNotFoundError) Error() string {
 	var builder strings.Builder
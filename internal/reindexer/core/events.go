@@ -40,33 +40,23 @@ func (r *Reindexer) GetEvents(subscriptions []models.SubscriptionRequest, size,
 }
 
 func (r *Reindexer) getContractEvents(subscriptions []models.SubscriptionRequest, size, offset int64) ([]models.Event, error) {
-	tx, err := r.BeginTx(models.DocContracts)
-	if err != nil {
-		return nil, err
-	}
-
 	events := make([]models.Event, 0)
 	for _, subscription := range subscriptions {
 		if !subscription.WithSame && !subscription.WithSimilar {
 			continue
 		}
 
-		query := tx.Query()
+		query := r.Query(models.DocContracts)
 		getSubscriptionWithSame(subscription, query)
 		getSubscriptionWithSimilar(subscription, query)
-		it := query.Limit(int(size)).Offset(int(offset)).Exec()
-		defer it.Close()
+		query = query.Limit(int(size)).Offset(int(offset))
 
-		if it.Error() != nil {
-			if err := tx.Rollback(); err != nil {
-				return nil, err
-			}
-			return nil, it.Error()
+		var items []EventContract
+		if err := r.GetAllByQuery(query, &items); err != nil {
+			return nil, err
 		}
 
-		for it.Next() {
-			var event EventContract
-			it.NextObj(&event)
+		for _, event := range items {
 			res := models.Event{
 				Body:    event,
 				Network: subscription.Network,
@@ -83,37 +73,27 @@ func (r *Reindexer) getContractEvents(subscriptions []models.SubscriptionRequest
 		}
 	}
 
-	return events, tx.Commit()
+	return events, nil
 }
 
 func (r *Reindexer) getOperationEvents(subscriptions []models.SubscriptionRequest, size, offset int64) ([]models.Event, error) {
-	tx, err := r.BeginTx(models.DocOperations)
-	if err != nil {
-		return nil, err
-	}
-
 	events := make([]models.Event, 0)
 	for _, subscription := range subscriptions {
 		if !subscription.WithCalls && !subscription.WithErrors && !subscription.WithDeployments {
 			continue
 		}
-		query := tx.Query()
+		query := r.Query(models.DocOperations)
 		getEventsWatchCalls(subscription, query)
 		getEventsWatchErrors(subscription, query)
 		getEventsWatchDeployments(subscription, query)
-		it := query.Limit(int(size)).Offset(int(offset)).Exec()
-		defer it.Close()
+		query = query.Limit(int(size)).Offset(int(offset))
 
-		if it.Error() != nil {
-			if err := tx.Rollback(); err != nil {
-				return nil, err
-			}
-			return nil, it.Error()
+		var items []EventOperation
+		if err := r.GetAllByQuery(query, &items); err != nil {
+			return nil, err
 		}
 
-		for it.Next() {
-			var event EventOperation
-			it.NextObj(&event)
+		for _, event := range items {
 			res := models.Event{
 				Body:    event,
 				Network: subscription.Network,
@@ -133,36 +113,27 @@ func (r *Reindexer) getOperationEvents(subscriptions []models.SubscriptionReques
 			events = append(events, res)
 		}
 	}
-	return events, tx.Commit()
+	return events, nil
 }
 
 func (r *Reindexer) getMigrationEvents(subscriptions []models.SubscriptionRequest, size, offset int64) ([]models.Event, error) {
-	tx, err := r.BeginTx(models.DocMigrations)
-	if err != nil {
-		return nil, err
-	}
 	events := make([]models.Event, 0)
 	for _, subscription := range subscriptions {
 		if !subscription.WithMigrations {
 			continue
 		}
-		it := tx.Query().
+		query := r.Query(models.DocMigrations).
 			Match("kind", constants.MigrationBootstrap, constants.MigrationLambda, constants.MigrationUpdate).
 			Match("network", subscription.Network).
 			Match("address", subscription.Address).
-			Limit(int(size)).Offset(int(offset)).Exec()
-		defer it.Close()
+			Limit(int(size)).Offset(int(offset))
 
-		if it.Error() != nil {
-			if err := tx.Rollback(); err != nil {
-				return nil, err
-			}
-			return nil, it.Error()
+		var items []EventMigration
+		if err := r.GetAllByQuery(query, &items); err != nil {
+			return nil, err
 		}
 
-		for it.Next() {
-			var event EventOperation
-			it.NextObj(&event)
+		for _, event := range items {
 			events = append(events, models.Event{
 				Body:    event,
 				Network: subscription.Network,
@@ -172,7 +143,7 @@ func (r *Reindexer) getMigrationEvents(subscriptions []models.SubscriptionReques
 			})
 		}
 	}
-	return events, tx.Commit()
+	return events, nil
 }
 
 func getEventsWatchDeployments(subscription models.SubscriptionRequest, query *reindexer.Query) {
@@ -6,6 +6,7 @@ import (
 	"github.com/baking-bad/bcdhub/internal/models"
 	"github.com/pkg/errors"
 	"github.com/restream/reindexer"
+	"github.com/tidwall/gjson"
 )
 
 // Sizes -
@@ -13,9 +14,14 @@ const (
 	DefaultSize = 10
 )
 
-// Count -
+const (
+	itemsField = "items"
+	totalField = "total"
+)
+
+// Count - returns total count of documents matched `query`
 func (r *Reindexer) Count(query *reindexer.Query) (int64, error) {
-	it := query.Exec()
+	it := query.ReqTotal().Limit(0).Exec()
 	defer it.Close()
 
 	if it.Error() != nil {
@@ -25,22 +31,35 @@ func (r *Reindexer) Count(query *reindexer.Query) (int64, error) {
 	return int64(count), nil
 }
 
-// GetOne -
+// GetOne - receives first document of `query` result. Returns `RecordNotFoundError` if result is empty.
 func (r *Reindexer) GetOne(query *reindexer.Query, output interface{}) error {
-	it := query.Exec()
-	defer it.Close()
+	if query == nil {
+		return ErrQueryPointerIsNil
+	}
 
-	if it.Error() != nil {
-		return it.Error()
+	namespace := query.Namespace
+	result, err := execToJSON(query.Limit(1))
+	if err != nil {
+		return err
 	}
-	it.NextObj(output)
-	return nil
+
+	items := result.Get(itemsField).Array()
+	if len(items) == 0 {
+		return NewRecordNotFoundError(namespace, "")
+	}
+	return decode(items[0], output)
 }
 
 // GetByID -
 func (r *Reindexer) GetByID(ret models.Model) error {
 	query := r.Query(ret.GetIndex()).WhereString("id", reindexer.EQ, ret.GetID())
-	return r.GetOne(query, ret)
+	if err := r.GetOne(query, ret); err != nil {
+		if r.IsRecordNotFound(err) {
+			return NewRecordNotFoundError(ret.GetIndex(), ret.GetID())
+		}
+		return err
+	}
+	return nil
 }
 
 // GetByIDs -
@@ -96,18 +115,11 @@ func (r *Reindexer) GetAllByQuery(query *reindexer.Query, output interface{}) er
 		return ErrQueryPointerIsNil
 	}
 
-	it := query.Exec()
-	defer it.Close()
-
-	if it.Error() != nil {
-		return it.Error()
-	}
-
-	if it.Count() == 0 {
-		return nil
+	result, err := execToJSON(query)
+	if err != nil {
+		return err
 	}
-
-	return parse(it, output)
+	return parse(result.Get(itemsField).Array(), output)
 }
 
 // GetAllByQueryWithTotal -
@@ -116,18 +128,33 @@ func (r *Reindexer) GetAllByQueryWithTotal(query *reindexer.Query, output interf
 		return 0, ErrQueryPointerIsNil
 	}
 
-	it := query.ReqTotal().Exec()
-	defer it.Close()
-
-	if it.Error() != nil {
-		return 0, it.Error()
+	result, err := execToJSON(query.ReqTotal(totalField))
+	if err != nil {
+		return 0, err
 	}
+	return int(result.Get(totalField).Int()), parse(result.Get(itemsField).Array(), output)
+}
 
-	if it.Count() == 0 {
-		return 0, nil
+// GetRaw - returns JSON documents by query. Time fields are returned as unix timestamps.
+func (r *Reindexer) GetRaw(query *reindexer.Query) ([]gjson.Result, error) {
+	response, err := execToJSON(query)
+	if err != nil {
+		return nil, err
 	}
+	return response.Get(itemsField).Array(), nil
+}
 
-	return it.TotalCount(), parse(it, output)
+func execToJSON(query *reindexer.Query) (gjson.Result, error) {
+	it := query.ExecToJson(itemsField)
+	if it.Error() != nil {
+		defer it.Close()
+		return gjson.Result{}, it.Error()
+	}
+	data, err := it.FetchAll()
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	return gjson.ParseBytes(data), nil
 }
 
 func getElementType(output interface{}) (reflect.Type, error) {
@@ -147,6 +174,9 @@ func getIndex(output interface{}) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
 	newItem := reflect.New(typ)
 	interfaceType := reflect.TypeOf((*models.Model)(nil)).Elem()
 
@@ -165,17 +195,29 @@ func getIndex(output interface{}) (string, error) {
 	return getIndexResult[0].Interface().(string), nil
 }
 
-func parse(it *reindexer.Iterator, output interface{}) error {
+func parse(items []gjson.Result, output interface{}) error {
 	typ, err := getElementType(output)
 	if err != nil {
 		return err
 	}
 	el := reflect.ValueOf(output).Elem()
 
-	for it.Next() {
-		obj := reflect.New(typ).Interface()
-		it.NextObj(obj)
-		val := reflect.ValueOf(obj).Elem()
+	for i := range items {
+		isPtr := typ.Kind() == reflect.Ptr
+		itemType := typ
+		if isPtr {
+			itemType = typ.Elem()
+		}
+
+		obj := reflect.New(itemType)
+		if err := decode(items[i], obj.Interface()); err != nil {
+			return err
+		}
+
+		val := obj
+		if !isPtr {
+			val = obj.Elem()
+		}
 		if el.Kind() == reflect.Slice {
 			el.Set(reflect.Append(el, val))
 		} else {
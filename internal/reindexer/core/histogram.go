@@ -1,10 +1,197 @@
 package core
 
 import (
+	"strings"
+	"time"
+
 	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/tzip"
+	"github.com/pkg/errors"
+	"github.com/restream/reindexer"
+	"github.com/tidwall/gjson"
+)
+
+// Histogram functions
+const (
+	HistogramFunctionSum         = "sum"
+	HistogramFunctionAvg         = "avg"
+	HistogramFunctionMin         = "min"
+	HistogramFunctionMax         = "max"
+	HistogramFunctionCardinality = "cardinality"
 )
 
 // GetDateHistogram -
 func (r *Reindexer) GetDateHistogram(period string, opts ...models.HistogramOption) ([][]int64, error) {
-	return make([][]int64, 0), nil
+	ctx := models.HistogramContext{
+		Period: period,
+	}
+	for _, opt := range opts {
+		opt(&ctx)
+	}
+
+	documents := make([]gjson.Result, 0)
+	for _, index := range ctx.Indices {
+		query := r.Query(index)
+		buildHistogramContext(ctx, query)
+
+		items, err := r.GetRaw(query)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, items...)
+	}
+
+	series, err := Histogram(documents, period, ctx.Function.Name, ctx.Function.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	histogram := make([][]int64, len(series))
+	for i := range series {
+		histogram[i] = []int64{int64(series[i][0]), int64(series[i][1])}
+	}
+	return histogram, nil
+}
+
+func buildHistogramContext(ctx models.HistogramContext, query *reindexer.Query) {
+	for _, fltr := range ctx.Filters {
+		field := strings.TrimSuffix(fltr.Field, ".keyword")
+		switch fltr.Kind {
+		case models.HistogramFilterKindExists:
+			query.Where(field, reindexer.ANY, nil)
+		case models.HistogramFilterKindMatch:
+			query.Where(field, reindexer.EQ, fltr.Value)
+		case models.HistogramFilterKindIn, models.HistogramFilterKindAddresses:
+			if arr, ok := fltr.Value.([]string); ok {
+				query.WhereString(field, reindexer.SET, arr...)
+			}
+		case models.HistogramFilterDexEnrtypoints:
+			if value, ok := fltr.Value.([]tzip.DAppContract); ok {
+				DexEntrypoints(query, value)
+			}
+		}
+	}
+}
+
+// DexEntrypoints - filters documents which were initiated by one of DEX volume entrypoints of `contracts`
+func DexEntrypoints(query *reindexer.Query, contracts []tzip.DAppContract) {
+	query.OpenBracket()
+	var count int
+	for i := range contracts {
+		for j := range contracts[i].DexVolumeEntrypoints {
+			if count > 0 {
+				query.Or()
+			}
+			query.OpenBracket().
+				WhereString("initiator", reindexer.EQ, contracts[i].Address).
+				WhereString("parent", reindexer.EQ, contracts[i].DexVolumeEntrypoints[j]).
+				CloseBracket()
+			count++
+		}
+	}
+	query.CloseBracket()
+}
+
+// Histogram - splits `documents` by `period` buckets using `timestamp` field and computes `function` of `field` in every bucket. If `function` is empty documents count is computed. Empty buckets between the first and the last ones are filled by zero.
+func Histogram(documents []gjson.Result, period, function, field string) ([][]float64, error) {
+	field = strings.TrimSuffix(field, ".keyword")
+
+	buckets := make(map[int64][]gjson.Result)
+	var first, last time.Time
+	for i := range documents {
+		ts := time.Unix(documents[i].Get("timestamp").Int(), 0).UTC()
+		key, err := truncateTime(ts, period)
+		if err != nil {
+			return nil, err
+		}
+		if first.IsZero() || key.Before(first) {
+			first = key
+		}
+		if last.IsZero() || key.After(last) {
+			last = key
+		}
+		buckets[key.Unix()] = append(buckets[key.Unix()], documents[i])
+	}
+
+	histogram := make([][]float64, 0)
+	if len(buckets) == 0 {
+		return histogram, nil
+	}
+
+	for key := first; !key.After(last); key = nextPeriod(key, period) {
+		histogram = append(histogram, []float64{
+			float64(key.UnixNano() / int64(time.Millisecond)),
+			aggregate(buckets[key.Unix()], function, field),
+		})
+	}
+	return histogram, nil
+}
+
+func truncateTime(ts time.Time, period string) (time.Time, error) {
+	switch period {
+	case "hour":
+		return ts.Truncate(time.Hour), nil
+	case "day":
+		return time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.UTC), nil
+	case "week":
+		day := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.UTC)
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset), nil
+	case "month":
+		return time.Date(ts.Year(), ts.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	case "year":
+		return time.Date(ts.Year(), 1, 1, 0, 0, 0, 0, time.UTC), nil
+	default:
+		return ts, errors.Errorf("Unknown histogram period: %s", period)
+	}
+}
+
+func nextPeriod(ts time.Time, period string) time.Time {
+	switch period {
+	case "hour":
+		return ts.Add(time.Hour)
+	case "day":
+		return ts.AddDate(0, 0, 1)
+	case "week":
+		return ts.AddDate(0, 0, 7)
+	case "month":
+		return ts.AddDate(0, 1, 0)
+	default:
+		return ts.AddDate(1, 0, 0)
+	}
+}
+
+func aggregate(documents []gjson.Result, function, field string) float64 {
+	if function == "" || field == "" {
+		return float64(len(documents))
+	}
+
+	var result float64
+	switch function {
+	case HistogramFunctionCardinality:
+		unique := make(map[string]struct{})
+		for i := range documents {
+			unique[documents[i].Get(field).String()] = struct{}{}
+		}
+		result = float64(len(unique))
+	case HistogramFunctionAvg:
+		for i := range documents {
+			result += documents[i].Get(field).Float()
+		}
+		if len(documents) > 0 {
+			result /= float64(len(documents))
+		}
+	case HistogramFunctionMin, HistogramFunctionMax:
+		for i := range documents {
+			value := documents[i].Get(field).Float()
+			if i == 0 || (function == HistogramFunctionMin && value < result) || (function == HistogramFunctionMax && value > result) {
+				result = value
+			}
+		}
+	default:
+		for i := range documents {
+			result += documents[i].Get(field).Float()
+		}
+	}
+	return result
 }
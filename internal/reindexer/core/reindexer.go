@@ -1,11 +1,13 @@
 package core
 
 import (
-	"fmt"
 	"reflect"
 
 	"github.com/baking-bad/bcdhub/internal/models"
 	"github.com/restream/reindexer"
+
+	// connection to standalone reindexer server by `cproto://` scheme
+	_ "github.com/restream/reindexer/bindings/cproto"
 )
 
 // Reindexer -
@@ -13,9 +15,12 @@ type Reindexer struct {
 	*reindexer.Reindexer
 }
 
-// New -
+// New - creates connection to reindexer. Supported schemes are `cproto://` and `builtin://` (only if binary is built with `builtin` tag).
 func New(uri string) (*Reindexer, error) {
-	db := reindexer.NewReindex(uri)
+	db := reindexer.NewReindex(uri, reindexer.WithCreateDBIfMissing())
+	if err := db.Status().Err; err != nil {
+		return nil, err
+	}
 	return &Reindexer{db}, nil
 }
 
@@ -25,16 +30,41 @@ func (r *Reindexer) CreateIndexes() error {
 		if err := r.OpenNamespace(index.GetIndex(), reindexer.DefaultNamespaceOptions(), index); err != nil {
 			return err
 		}
+		if err := r.createPrimaryKey(index.GetIndex()); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// createPrimaryKey - models do not marshal their identifiers, so primary key index is added to namespace manually. Documents receive `id` field on saving.
+func (r *Reindexer) createPrimaryKey(namespace string) error {
+	description, err := r.DescribeNamespace(namespace)
+	if err != nil {
+		return err
+	}
+	for i := range description.Indexes {
+		if description.Indexes[i].IsPK {
+			return nil
+		}
+	}
+	return r.AddIndex(namespace, reindexer.IndexDef{
+		Name:      "id",
+		JSONPaths: []string{"id"},
+		IndexType: "hash",
+		FieldType: "string",
+		IsPK:      true,
+	})
+}
+
 // DeleteByLevelAndNetwork -
 func (r *Reindexer) DeleteByLevelAndNetwork(indices []string, network string, maxLevel int64) error {
 	for i := range indices {
-		val := r.ExecSQL(fmt.Sprintf("DELETE FROM %s WHERE network = '%s' AND level > %d", indices[i], network, maxLevel))
-		if val.Error() != nil {
-			return val.Error()
+		if _, err := r.Query(indices[i]).
+			WhereString("network", reindexer.EQ, network).
+			WhereInt64("level", reindexer.GT, maxLevel).
+			Delete(); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -50,12 +80,25 @@ func (r *Reindexer) DeleteIndices(indices []string) error {
 	return nil
 }
 
+// Refresh - reindexer applies changes synchronously, so there is nothing to wait
+func (r *Reindexer) Refresh(indices []string) error {
+	return nil
+}
+
 // DeleteByContract -
 func (r *Reindexer) DeleteByContract(indices []string, network, address string) error {
 	for i := range indices {
-		val := r.ExecSQL(fmt.Sprintf("DELETE FROM %s WHERE network = '%s' AND contract = '%s'", indices[i], network, address))
-		if val.Error() != nil {
-			return val.Error()
+		query := r.Query(indices[i]).
+			WhereString("network", reindexer.EQ, network).
+			OpenBracket()
+		for j, field := range models.ContractFields(indices[i]) {
+			if j > 0 {
+				query = query.Or()
+			}
+			query = query.WhereString(field, reindexer.EQ, address)
+		}
+		if _, err := query.CloseBracket().Delete(); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -80,7 +123,11 @@ func (r *Reindexer) BulkInsert(items []models.Model) error {
 	}
 
 	for i := range items {
-		if _, err := r.Insert(items[i].GetIndex(), items[i]); err != nil {
+		doc, err := document(items[i])
+		if err != nil {
+			return err
+		}
+		if err := r.Upsert(items[i].GetIndex(), doc); err != nil {
 			return err
 		}
 	}
@@ -93,7 +140,11 @@ func (r *Reindexer) BulkUpdate(updates []models.Model) error {
 		return nil
 	}
 	for i := range updates {
-		if _, err := r.Update(updates[i].GetIndex(), updates[i]); err != nil {
+		doc, err := document(updates[i])
+		if err != nil {
+			return err
+		}
+		if err := r.Upsert(updates[i].GetIndex(), doc); err != nil {
 			return err
 		}
 	}
@@ -106,7 +157,9 @@ func (r *Reindexer) BulkDelete(updates []models.Model) error {
 		return nil
 	}
 	for i := range updates {
-		if err := r.Delete(updates[i].GetIndex(), updates[i]); err != nil {
+		if _, err := r.Query(updates[i].GetIndex()).
+			WhereString("id", reindexer.EQ, updates[i].GetID()).
+			Delete(); err != nil {
 			return err
 		}
 	}
@@ -119,11 +172,12 @@ func (r *Reindexer) BulkRemoveField(field string, where []models.Model) error {
 		return nil
 	}
 	for i := range where {
-		it := r.Query(where[i].GetIndex()).Match("id", where[i].GetID()).Drop(field).Update()
-		defer it.Close()
+		it := r.Query(where[i].GetIndex()).WhereString("id", reindexer.EQ, where[i].GetID()).Drop(field).Update()
+		err := it.Error()
+		it.Close()
 
-		if it.Error() != nil {
-			return it.Error()
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -138,5 +192,30 @@ func (r *Reindexer) GetFieldValue(data interface{}, field string) interface{} {
 
 // SetAlias -
 func (r *Reindexer) SetAlias(network, address, alias string) error {
+	updates := []struct {
+		index string
+		field string
+		alias string
+	}{
+		{models.DocContracts, "address", "alias"},
+		{models.DocContracts, "delegate", "delegate_alias"},
+		{models.DocOperations, "source", "source_alias"},
+		{models.DocOperations, "destination", "destination_alias"},
+		{models.DocOperations, "delegate", "delegate_alias"},
+	}
+
+	for _, update := range updates {
+		it := r.Query(update.index).
+			WhereString("network", reindexer.EQ, network).
+			WhereString(update.field, reindexer.EQ, address).
+			Set(update.alias, alias).
+			Update()
+		err := it.Error()
+		it.Close()
+
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -1,126 +1,140 @@
 package core
 
 import (
-	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/baking-bad/bcdhub/internal/models"
-	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
-	"github.com/baking-bad/bcdhub/internal/models/contract"
-	"github.com/baking-bad/bcdhub/internal/models/operation"
-	"github.com/baking-bad/bcdhub/internal/models/tezosdomain"
-	"github.com/baking-bad/bcdhub/internal/models/tzip"
 	"github.com/baking-bad/bcdhub/internal/search"
 	"github.com/pkg/errors"
 	"github.com/restream/reindexer"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
 )
 
-// SearchByText -
+// SearchByText - searches documents which fields are equal to `text`. Reindexer storage does not support full text search and grouping.
 func (r *Reindexer) SearchByText(text string, offset int64, fields []string, filters map[string]interface{}, group bool) (models.Result, error) {
 	result := models.Result{}
 	if text == "" {
 		return result, errors.Errorf("Empty search string. Please query something")
 	}
 
-	query, err := r.prepareSearchQuery(text, filters, fields, offset)
+	ctx, err := prepareSearchContext(text, filters, fields)
 	if err != nil {
 		return result, err
 	}
 
 	start := time.Now()
-	it := query.Exec()
-	defer it.Close()
+	items := make([]models.Item, 0)
+	for _, index := range ctx.Indices {
+		query := r.Query(index).OpenBracket()
+		for i := range ctx.Fields {
+			if i > 0 {
+				query = query.Or()
+			}
+			if ptr, err := strconv.ParseInt(ctx.Text, 10, 64); err == nil && ctx.Fields[i] == "ptr" {
+				query = query.WhereInt64(ctx.Fields[i], reindexer.EQ, ptr)
+			} else {
+				query = query.WhereString(ctx.Fields[i], reindexer.EQ, ctx.Text)
+			}
+		}
+		query = query.CloseBracket()
+		if err := prepareFilters(filters, query); err != nil {
+			return result, err
+		}
 
-	if it.Error() != nil {
-		return result, err
+		response, err := execToJSON(query)
+		if err != nil {
+			return result, err
+		}
+		for _, doc := range response.Get(itemsField).Array() {
+			data, err := restoreDocument(index, doc)
+			if err != nil {
+				return result, err
+			}
+			val, err := search.Parse(index, nil, data)
+			if err != nil {
+				return result, err
+			}
+
+			switch t := val.(type) {
+			case models.Item:
+				items = append(items, t)
+			case []models.Item:
+				items = append(items, t...)
+			}
+		}
 	}
-	items, err := parseSearchResponse(it)
-	if err != nil {
-		return result, err
+
+	result.Count = int64(len(items))
+	if offset < int64(len(items)) {
+		items = items[offset:]
+	} else {
+		items = items[:0]
+	}
+	if len(items) > DefaultSize {
+		items = items[:DefaultSize]
 	}
-	result.Time = time.Since(start).Milliseconds()
-	result.Count = int64(it.TotalCount())
 	result.Items = items
-
+	result.Time = time.Since(start).Milliseconds()
 	return result, nil
 }
 
-func (r *Reindexer) prepareSearchQuery(searchString string, filters map[string]interface{}, fields []string, offset int64) (*reindexer.Query, error) {
+func prepareSearchContext(searchString string, filters map[string]interface{}, fields []string) (search.Context, error) {
 	ctx := search.NewContext()
 
 	if search.IsPtrSearch(searchString) {
 		ctx.Text = strings.TrimPrefix(searchString, "ptr:")
 		ctx.Indices = []string{models.DocBigMapDiff}
 		ctx.Fields = []string{"ptr"}
-	} else {
-		info, err := getFields(ctx.Text, filters, fields)
-		if err != nil {
-			return nil, err
-		}
-		ctx.Indices = info.Indices
-		ctx.Fields = info.Scores
-		ctx.Text = fmt.Sprintf("%s*", searchString)
+		return ctx, nil
 	}
-	ctx.Offset = offset
 
-	return r.buildSeacrhQuery(ctx, filters)
-}
-
-func (r *Reindexer) buildSeacrhQuery(ctx search.Context, filters map[string]interface{}) (*reindexer.Query, error) {
-	var query *reindexer.Query
-	for i := range ctx.Indices {
-		subQuery := r.Query(ctx.Indices[i])
-		for _, field := range ctx.Fields {
-			subQuery = subQuery.Match(field, ctx.Text)
-		}
-		if err := prepareFilters(filters, subQuery); err != nil {
-			return nil, err
-		}
-
-		subQuery = subQuery.Offset(int(ctx.Offset)).Functions("text.highlight(<em>,</em>)")
-
-		if query == nil {
-			query = subQuery
-		} else {
-			query.Merge(subQuery)
-		}
-	}
-
-	query = query.ReqTotal()
-
-	return query, nil
-}
-
-func getFields(searchString string, filters map[string]interface{}, fields []string) (search.ScoreInfo, error) {
 	var indices []string
 	if val, ok := filters["indices"]; ok {
 		indices = val.([]string)
 		delete(filters, "indices")
 	}
 
-	return search.GetScores(searchString, fields, indices...)
+	info, err := search.GetScores(searchString, fields, indices...)
+	if err != nil {
+		return ctx, err
+	}
+	ctx.Text = searchString
+	ctx.Indices = info.Indices
+	for i := range info.Scores {
+		ctx.Fields = append(ctx.Fields, strings.Split(info.Scores[i], "^")[0])
+	}
+	return ctx, nil
 }
 
 func prepareFilters(filters map[string]interface{}, query *reindexer.Query) error {
 	for field, value := range filters {
 		switch field {
-		case "from":
-			query = query.Where("timestamp", reindexer.GT, value)
-		case "to":
-			query = query.Where("timestamp", reindexer.LT, value)
+		case "from", "to":
+			ts, err := time.Parse(time.RFC3339, value.(string))
+			if err != nil {
+				return err
+			}
+			if field == "from" {
+				query.WhereInt64("timestamp", reindexer.GT, ts.Unix())
+			} else {
+				query.WhereInt64("timestamp", reindexer.LT, ts.Unix())
+			}
 		case "networks":
 			networks, ok := value.([]string)
 			if !ok {
 				return errors.Errorf("Invalid type for 'network' filter (wait []string): %T", value)
 			}
-			query = query.Match("network", networks...)
+			query.Match("network", networks...)
 		case "languages":
 			languages, ok := value.([]string)
 			if !ok {
 				return errors.Errorf("Invalid type for 'network' filter (wait []string): %T", value)
 			}
-			query = query.Match("language", languages...)
+			query.Match("language", languages...)
 		default:
 			return errors.Errorf("Unknown search filter: %s", field)
 		}
@@ -128,41 +142,23 @@ func prepareFilters(filters map[string]interface{}, query *reindexer.Query) erro
 	return nil
 }
 
-func parseSearchResponse(it *reindexer.Iterator) ([]models.Item, error) {
-	items := make([]models.Item, 0)
-	for it.Next() {
-		searchItem := models.Item{}
-
-		switch elem := it.Object().(type) {
-		case contract.Contract:
-			searchItem.Type = models.DocContracts
-			searchItem.Value = elem.Address
-			searchItem.Body = elem
-			searchItem.Network = elem.Network
-		case operation.Operation:
-			searchItem.Type = models.DocOperations
-			searchItem.Value = elem.Hash
-			searchItem.Body = elem
-			searchItem.Network = elem.Network
-		case bigmapdiff.BigMapDiff:
-			searchItem.Type = models.DocBigMapDiff
-			searchItem.Value = elem.KeyHash
-			searchItem.Body = elem
-			searchItem.Network = elem.Network
-		case tezosdomain.TezosDomain:
-			searchItem.Type = models.DocTezosDomains
-			searchItem.Value = elem.Address
-			searchItem.Body = elem
-			searchItem.Network = elem.Network
-		case tzip.TZIP:
-			searchItem.Value = elem.Address
-			searchItem.Network = elem.Network
-			searchItem.Type = models.DocTokenMetadata
-		default:
-			return nil, errors.Errorf("Unknown search type")
+// restoreDocument - returns JSON of the model stored in `index` as it's marshaled by model itself
+func restoreDocument(index string, doc gjson.Result) ([]byte, error) {
+	data := []byte(doc.Raw)
+	for _, model := range models.AllModels() {
+		if model.GetIndex() != index {
+			continue
+		}
+		for _, field := range timeFields(reflect.TypeOf(model)) {
+			value := doc.Get(field)
+			if value.Type != gjson.Number {
+				continue
+			}
+			var err error
+			if data, err = sjson.SetBytes(data, field, time.Unix(value.Int(), 0).UTC().Format(time.RFC3339)); err != nil {
+				return nil, err
+			}
 		}
-
-		items = append(items, searchItem)
 	}
-	return items, nil
+	return data, nil
 }
@@ -8,37 +8,37 @@ import (
 
 // CreateAWSRepository -
 func (r *Reindexer) CreateAWSRepository(name, awsBucketName, awsRegion string) error {
-	return nil
+	return ErrNotSupported
 }
 
 // ListRepositories -
 func (r *Reindexer) ListRepositories() ([]models.Repository, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 // CreateSnapshots -
 func (r *Reindexer) CreateSnapshots(repository, snapshot string, indices []string) error {
-	return nil
+	return ErrNotSupported
 }
 
 // RestoreSnapshots -
 func (r *Reindexer) RestoreSnapshots(repository, snapshot string, indices []string) error {
-	return nil
+	return ErrNotSupported
 }
 
 // ListSnapshots -
 func (r *Reindexer) ListSnapshots(repository string) (string, error) {
-	return "", nil
+	return "", ErrNotSupported
 }
 
 // SetSnapshotPolicy -
 func (r *Reindexer) SetSnapshotPolicy(policyID, cronSchedule, name, repository string, expireAfterInDays int64) error {
-	return nil
+	return ErrNotSupported
 }
 
 // GetAllPolicies -
 func (r *Reindexer) GetAllPolicies() ([]string, error) {
-	return nil, nil
+	return nil, ErrNotSupported
 }
 
 // GetMappings -
@@ -53,5 +53,5 @@ func (r *Reindexer) CreateMapping(index string, reader io.Reader) error {
 
 // ReloadSecureSettings -
 func (r *Reindexer) ReloadSecureSettings() error {
-	return nil
+	return ErrNotSupported
 }
@@ -2,6 +2,7 @@ package core
 
 import (
 	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/contract"
 	"github.com/restream/reindexer"
 )
 
@@ -41,56 +42,52 @@ func (r *Reindexer) GetNetworkCountStats(network string) (map[string]int64, erro
 
 // GetCallsCountByNetwork -
 func (r *Reindexer) GetCallsCountByNetwork(network string) (map[string]int64, error) {
-	query := r.Query(models.DocContracts).
+	query := r.Query(models.DocOperations).
+		Not().
 		WhereString("entrypoint", reindexer.EMPTY, "")
+	if network != "" {
+		query = query.Match("network", network)
+	}
 
 	return countByField("network", query)
 }
 
 // GetContractStatsByNetwork -
-// TODO: to do =)
 func (r *Reindexer) GetContractStatsByNetwork(network string) (map[string]models.ContractCountStats, error) {
-	// query := NewQuery().Add(
-	// 	Aggs(
-	// 		AggItem{
-	// 			"network", Item{
-	// 				"terms": Item{
-	// 					"field": "network.keyword",
-	// 				},
-	// 				"core.Aggs": Item{
-	// 					"same": Item{
-	// 						"cardinality": Item{
-	// 							"script": "doc['fingerprint.parameter'].value + '|' + doc['fingerprint.storage'].value + '|' + doc['fingerprint.code'].value",
-	// 						},
-	// 					},
-	// 					"balance":         Sum("balance"),
-	// 				},
-	// 			},
-	// 		},
-	// 	),
-	// ).Zero()
-
-	// var response getContractStatsByNetworkStats
-	// if err := e.Query([]string{models.DocContracts}, query, &response); err != nil {
-	// 	return nil, err
-	// }
-
-	// counts := make(map[string]models.ContractCountStats)
-	// for _, item := range response.Agg.Network.Buckets {
-	// 	counts[item.Key] = models.ContractCountStats{
-	// 		Total:          item.DocCount,
-	// 		SameCount:      item.Same.Value,
-	// 		Balance:        int64(item.Balance.Value),
-	// 		TotalWithdrawn: int64(item.TotalWithdrawn.Value),
-	// 	}
-	// }
-	// return counts, nil
-	return nil, nil
+	query := r.Query(models.DocContracts).Select("network", "fingerprint")
+	if network != "" {
+		query = query.Match("network", network)
+	}
+
+	var items []contract.Contract
+	if err := r.GetAllByQuery(query, &items); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]models.ContractCountStats)
+	same := make(map[string]map[string]struct{})
+	for i := range items {
+		stats := counts[items[i].Network]
+		stats.Total++
+
+		if _, ok := same[items[i].Network]; !ok {
+			same[items[i].Network] = make(map[string]struct{})
+		}
+		if fgpt := items[i].Fingerprint; fgpt != nil {
+			same[items[i].Network][fgpt.Parameter+"|"+fgpt.Storage+"|"+fgpt.Code] = struct{}{}
+		}
+		stats.SameCount = int64(len(same[items[i].Network]))
+		counts[items[i].Network] = stats
+	}
+	return counts, nil
 }
 
 // GetFACountByNetwork -
 func (r *Reindexer) GetFACountByNetwork(network string) (map[string]int64, error) {
 	query := r.Query(models.DocContracts).Match("tags", "fa1", "fa12")
+	if network != "" {
+		query = query.Match("network", network)
+	}
 	return countByField("network", query)
 }
 
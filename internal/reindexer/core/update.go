@@ -1,23 +1,46 @@
 package core
 
 import (
+	"reflect"
+	"time"
+
 	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/restream/reindexer"
 )
 
 // UpdateDoc - updates document
 func (r *Reindexer) UpdateDoc(model models.Model) error {
-	_, err := r.Update(model.GetIndex(), model)
-	return err
+	doc, err := document(model)
+	if err != nil {
+		return err
+	}
+	return r.Upsert(model.GetIndex(), doc)
 }
 
-// UpdateFields -
+// UpdateFields - updates `fields` of document. `fields` are names of `data` struct fields. If document does not exist it's created from `data`.
 func (r *Reindexer) UpdateFields(index, id string, data interface{}, fields ...string) error {
-	query := r.Query(index).Match("id", id)
+	typ := reflect.TypeOf(data)
+	query := r.Query(index).WhereString("id", reindexer.EQ, id)
 	for j := range fields {
 		value := r.GetFieldValue(data, fields[j])
-		query = query.Set(fields[j], value)
+		if ts, ok := value.(time.Time); ok {
+			value = ts.Unix()
+		}
+		query = query.Set(jsonName(typ, fields[j]), value)
 	}
 	it := query.Update()
 	defer it.Close()
-	return it.Error()
+
+	if it.Error() != nil {
+		return it.Error()
+	}
+	if it.Count() > 0 {
+		return nil
+	}
+
+	model, ok := data.(models.Model)
+	if !ok {
+		return NewRecordNotFoundError(index, id)
+	}
+	return r.UpdateDoc(model)
 }
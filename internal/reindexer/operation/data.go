@@ -1,6 +1,6 @@
 package operation
 
 type opgForContract struct {
-	Hash    string `reindex:"hash"`
-	Counter int64  `reindex:"counter"`
+	Hash    string `json:"hash" reindex:"hash"`
+	Counter int64  `json:"counter" reindex:"counter"`
 }
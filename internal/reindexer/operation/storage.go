@@ -2,6 +2,8 @@ package operation
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -38,8 +40,15 @@ func (storage *Storage) getContractOPG(address, network string, size uint64, fil
 		OpenBracket().
 		Match("source", address).
 		Or().
-		Match("destination", address).
-		CloseBracket().
+		Match("destination", address)
+	if manager, ok := filters["manager"].(string); ok && manager != "" {
+		query = query.Or().
+			OpenBracket().
+			Match("source", manager).
+			Match("kind", consts.Reveal).
+			CloseBracket()
+	}
+	query = query.CloseBracket().
 		Match("network", network)
 
 	if err := prepareOperationFilters(filters, query); err != nil {
@@ -53,9 +62,11 @@ func (storage *Storage) getContractOPG(address, network string, size uint64, fil
 	}
 
 	resp := make([]opgForContract, 0)
-	for it.Next() {
+	for {
 		var obj opgForContract
-		it.NextObj(&obj)
+		if !it.NextObj(&obj) {
+			break
+		}
 		resp = append(resp, obj)
 	}
 
@@ -69,23 +80,47 @@ func prepareOperationFilters(filters map[string]interface{}, query *reindexer.Qu
 		}
 		switch k {
 		case "from":
-			query = query.Where("timestamp", reindexer.GE, v)
+			query.WhereInt64("timestamp", reindexer.GE, millisToSeconds(v))
 		case "to":
-			query = query.Where("timestamp", reindexer.LE, v)
+			query.WhereInt64("timestamp", reindexer.LE, millisToSeconds(v))
 		case "entrypoints":
-			query = query.Where("entrypoint", reindexer.EQ, v)
+			query.WhereString("entrypoint", reindexer.SET, splitFilterValues(v)...)
 		case "last_id":
-			query = query.Where("timestamp", reindexer.LT, v)
+			lastID, err := strconv.ParseInt(fmt.Sprintf("%v", v), 10, 64)
+			if err != nil {
+				return err
+			}
+			query.WhereInt64("indexed_time", reindexer.LT, lastID)
 		case "status":
-			query = query.Where("status", reindexer.EQ, v)
+			query.WhereString("status", reindexer.SET, splitFilterValues(v)...)
+		case "kind":
+			query.WhereString("kind", reindexer.SET, splitFilterValues(v)...)
+		case "manager":
+			// reveals of manager are selected with operations of contract
 		default:
 			return errors.Errorf("Unknown operation filter: %s %v", k, v)
 		}
-
 	}
 	return nil
 }
 
+// splitFilterValues - splits filter value in format `'value1','value2'`
+func splitFilterValues(value interface{}) []string {
+	values := strings.Split(fmt.Sprintf("%v", value), ",")
+	for i := range values {
+		values[i] = strings.Trim(values[i], "' ")
+	}
+	return values
+}
+
+func millisToSeconds(value interface{}) int64 {
+	ts, err := strconv.ParseInt(fmt.Sprintf("%v", value), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return ts / 1000
+}
+
 // GetByContract -
 func (storage *Storage) GetByContract(network, address string, size uint64, filters map[string]interface{}) (po operation.Pageable, err error) {
 	opg, err := storage.getContractOPG(address, network, size, filters)
@@ -107,20 +142,19 @@ func (storage *Storage) GetByContract(network, address string, size uint64, filt
 		}
 	}
 	query = query.CloseBracket().Sort(sortString, true)
-	query.AggregateMin("indexed_time")
-
-	it := query.Exec()
-	defer it.Close()
 
-	if err = it.Error(); err != nil {
+	po.Operations = make([]operation.Operation, 0)
+	if err = storage.db.GetAllByQuery(query, &po.Operations); err != nil {
 		return
 	}
 
-	po.Operations = make([]operation.Operation, it.Count())
-	for i := 0; i < it.Count(); i++ {
-		it.NextObj(&po.Operations[i])
+	var lastID int64
+	for i := range po.Operations {
+		if lastID == 0 || po.Operations[i].IndexedTime < lastID {
+			lastID = po.Operations[i].IndexedTime
+		}
 	}
-	po.LastID = fmt.Sprintf("%.0f", it.AggResults()[0].Value)
+	po.LastID = fmt.Sprintf("%d", lastID)
 	return
 }
 
@@ -138,6 +172,61 @@ func (storage *Storage) Last(network, address string, indexedTime int64) (op ope
 	return
 }
 
+// LastByLevel -
+func (storage *Storage) LastByLevel(network, address string, level int64) (op operation.Operation, err error) {
+	query := storage.db.Query(models.DocOperations).
+		Match("destination", address).
+		Match("network", network).
+		Match("status", consts.Applied).
+		Not().WhereString("deffated_storage", reindexer.EMPTY, "").
+		WhereInt64("level", reindexer.LE, level).
+		Sort("indexed_time", true)
+
+	err = storage.db.GetOne(query, &op)
+	return
+}
+
+// GetBalance -
+func (storage *Storage) GetBalance(network, address string, level int64) (balance int64, err error) {
+	query := storage.db.Query(models.DocOperations).
+		Match("network", network).
+		Match("status", consts.Applied).
+		WhereInt64("level", reindexer.LE, level).
+		OpenBracket().
+		Match("source", address).
+		Or().
+		Match("destination", address).
+		CloseBracket()
+
+	it := query.Exec()
+	defer it.Close()
+
+	if it.Error() != nil {
+		return 0, it.Error()
+	}
+
+	type amount struct {
+		Source      string `json:"source"`
+		Destination string `json:"destination"`
+		Amount      int64  `json:"amount"`
+	}
+	for {
+		var op amount
+		if !it.NextObj(&op) {
+			break
+		}
+		if op.Source == op.Destination {
+			continue
+		}
+		if op.Source == address {
+			balance -= op.Amount
+		} else {
+			balance += op.Amount
+		}
+	}
+	return
+}
+
 // Get -
 func (storage *Storage) Get(filters map[string]interface{}, size int64, sort bool) (operations []operation.Operation, err error) {
 	query := storage.db.Query(models.DocOperations)
@@ -179,7 +268,7 @@ func (storage *Storage) GetStats(network, address string) (stats operation.Stats
 	}
 
 	stats.Count = int64(it.TotalCount())
-	stats.LastAction = time.Unix(int64(it.AggResults()[0].Value), 0) // TODO: is the date valid? check parsing`
+	stats.LastAction = time.Unix(int64(it.AggResults()[0].Value), 0).UTC()
 	return
 }
 
@@ -248,12 +337,14 @@ func (storage *Storage) GetParticipatingContracts(network string, fromLevel, toL
 	addresses := make([]string, 0)
 
 	type response struct {
-		Source      string `reindex:"source"`
-		Destination string `reindex:"destination"`
+		Source      string `json:"source" reindex:"source"`
+		Destination string `json:"destination" reindex:"destination"`
 	}
-	for it.Next() {
+	for {
 		var item response
-		it.NextObj(&item)
+		if !it.NextObj(&item) {
+			break
+		}
 		if _, ok := exists[item.Destination]; helpers.IsContract(item.Destination) && !ok {
 			exists[item.Destination] = struct{}{}
 			addresses = append(addresses, item.Destination)
@@ -267,6 +358,31 @@ func (storage *Storage) GetParticipatingContracts(network string, fromLevel, toL
 	return addresses, nil
 }
 
+// GetContractLevels -
+func (storage *Storage) GetContractLevels(network, address string) ([]int64, error) {
+	query := storage.db.Query(models.DocOperations).
+		Match("network", network).
+		OpenBracket().
+		Match("source", address).
+		Or().
+		Match("destination", address).
+		CloseBracket()
+
+	values, err := storage.db.GetUnique("level", query)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make([]int64, len(values))
+	for i := range values {
+		if levels[i], err = strconv.ParseInt(values[i], 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	return levels, nil
+}
+
 // RecalcStats -
 func (storage *Storage) RecalcStats(network, address string) (stats operation.ContractStats, err error) {
 	query := storage.db.Query(models.DocOperations).
@@ -289,11 +405,21 @@ func (storage *Storage) RecalcStats(network, address string) (stats operation.Co
 	}
 
 	stats.TxCount = int64(it.TotalCount())
-	stats.LastAction = time.Unix(0, int64(it.AggResults()[0].Value)*1000000).UTC()
+	stats.LastAction = time.Unix(int64(it.AggResults()[0].Value), 0).UTC()
 
-	for it.Next() {
-		var op operation.Operation
-		it.NextObj(&op)
+	type amount struct {
+		Source      string `json:"source"`
+		Destination string `json:"destination"`
+		Amount      int64  `json:"amount"`
+	}
+	for {
+		var op amount
+		if !it.NextObj(&op) {
+			break
+		}
+		if op.Source == op.Destination {
+			continue
+		}
 		if op.Source == address {
 			stats.Balance -= op.Amount
 		} else {
@@ -353,5 +479,21 @@ func periodToRange(period string, query *reindexer.Query) error {
 
 // GetContract24HoursVolume -
 func (storage *Storage) GetContract24HoursVolume(network, address string, entrypoints []string) (float64, error) {
-	return 0, nil
+	query := storage.db.Query(models.DocOperations).
+		Match("network", network).
+		Match("destination", address).
+		Match("status", consts.Applied).
+		WhereInt64("timestamp", reindexer.GT, time.Now().Add(-24*time.Hour).Unix())
+	if len(entrypoints) > 0 {
+		query.WhereString("entrypoint", reindexer.SET, entrypoints...)
+	}
+	query.AggregateSum("amount")
+
+	it := query.Exec()
+	defer it.Close()
+
+	if it.Error() != nil {
+		return 0, it.Error()
+	}
+	return it.AggResults()[0].Value, nil
 }
@@ -50,12 +50,14 @@ func (storage *Storage) GetSymLinks(network string, level int64) (map[string]str
 	symMap := make(map[string]struct{})
 
 	type link struct {
-		symLimk string `reindex:"sym_link"`
+		SymLink string `json:"sym_link" reindex:"sym_link"`
 	}
-	for it.Next() {
+	for {
 		var sl link
-		it.NextObj(&sl)
-		symMap[sl.symLimk] = struct{}{}
+		if !it.NextObj(&sl) {
+			break
+		}
+		symMap[sl.SymLink] = struct{}{}
 	}
 
 	return symMap, nil
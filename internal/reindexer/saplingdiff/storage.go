@@ -0,0 +1,72 @@
+package saplingdiff
+
+import (
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/saplingdiff"
+	"github.com/baking-bad/bcdhub/internal/reindexer/core"
+	"github.com/restream/reindexer"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Reindexer
+}
+
+// NewStorage -
+func NewStorage(db *core.Reindexer) *Storage {
+	return &Storage{db}
+}
+
+// Get -
+func (storage *Storage) Get(network string, ptr, size, offset int64) ([]saplingdiff.SaplingDiff, error) {
+	if size == 0 {
+		size = core.DefaultSize
+	}
+
+	query := storage.db.Query(models.DocSaplingDiffs).
+		Match("network", network).
+		WhereInt64("ptr", reindexer.EQ, ptr).
+		Limit(int(size)).
+		Offset(int(offset)).
+		Sort("indexed_time", true)
+
+	diffs := make([]saplingdiff.SaplingDiff, 0)
+	err := storage.db.GetAllByQuery(query, &diffs)
+	return diffs, err
+}
+
+// GetFirst -
+func (storage *Storage) GetFirst(network string, ptr int64) (diff saplingdiff.SaplingDiff, err error) {
+	query := storage.db.Query(models.DocSaplingDiffs).
+		Match("network", network).
+		WhereInt64("ptr", reindexer.EQ, ptr).
+		Sort("indexed_time", false)
+
+	err = storage.db.GetOne(query, &diff)
+	return
+}
+
+// GetStats -
+func (storage *Storage) GetStats(network string, ptr, maxLevel int64) (stats saplingdiff.Stats, err error) {
+	query := storage.db.Query(models.DocSaplingDiffs).
+		Match("network", network).
+		WhereInt64("ptr", reindexer.EQ, ptr)
+	if maxLevel > 0 {
+		query = query.WhereInt64("level", reindexer.LE, maxLevel)
+	}
+	query.AggregateSum("commitments_count")
+	query.AggregateSum("nullifiers_count")
+	query.ReqTotal()
+
+	it := query.Exec()
+	defer it.Close()
+
+	if it.Error() != nil {
+		return stats, it.Error()
+	}
+
+	stats.Commitments = int64(it.AggResults()[0].Value)
+	stats.Nullifiers = int64(it.AggResults()[1].Value)
+	stats.Updates = int64(it.TotalCount())
+	return
+}
@@ -0,0 +1,57 @@
+package ticketbalance
+
+import (
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
+	"github.com/baking-bad/bcdhub/internal/reindexer/core"
+	"github.com/restream/reindexer"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Reindexer
+}
+
+// NewStorage -
+func NewStorage(db *core.Reindexer) *Storage {
+	return &Storage{db}
+}
+
+// Update -
+func (storage *Storage) Update(updates []*ticketbalance.TicketBalance) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	ids := make([]string, len(updates))
+	for i := range updates {
+		ids[i] = updates[i].GetID()
+	}
+	buf := make([]ticketbalance.TicketBalance, 0)
+	if err := storage.db.GetByIDs(&buf, ids...); err != nil {
+		return err
+	}
+
+	items := make([]models.Model, len(updates))
+	for i := range updates {
+		for j := range buf {
+			if buf[j].GetID() == updates[i].GetID() {
+				updates[i].Sum(&buf[j])
+				break
+			}
+		}
+		items[i] = updates[i]
+	}
+
+	return storage.db.BulkInsert(items)
+}
+
+// GetAccountBalances -
+func (storage *Storage) GetAccountBalances(network, address string) (balances []ticketbalance.TicketBalance, err error) {
+	query := storage.db.Query(models.DocTicketBalances).
+		Match("network", network).
+		Match("address", address).
+		Not().WhereString("balance", reindexer.EQ, "0")
+
+	err = storage.db.GetAllByQuery(query, &balances)
+	return
+}
@@ -0,0 +1,28 @@
+package ticketupdate
+
+import (
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/ticketupdate"
+	"github.com/baking-bad/bcdhub/internal/reindexer/core"
+	"github.com/restream/reindexer"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Reindexer
+}
+
+// NewStorage -
+func NewStorage(db *core.Reindexer) *Storage {
+	return &Storage{db}
+}
+
+// GetAll -
+func (storage *Storage) GetAll(network string, level int64) (updates []ticketupdate.TicketUpdate, err error) {
+	query := storage.db.Query(models.DocTicketUpdates).
+		Match("network", network).
+		WhereInt64("level", reindexer.GT, level)
+
+	err = storage.db.GetAllByQuery(query, &updates)
+	return
+}
@@ -17,67 +17,50 @@ func NewStorage(db *core.Reindexer) *Storage {
 	return &Storage{db}
 }
 
-// const scriptUpdateBalance = `{"source": "ctx._source.balance = ctx._source.balance + (long)params.delta", "lang": "painless", "params": { "delta": %d }}`
-
 // Update -
 func (storage *Storage) Update(updates []*tokenbalance.TokenBalance) error {
 	if len(updates) == 0 {
 		return nil
 	}
-
-	tx, err := storage.db.BeginTx(models.DocTokenBalances)
-	for err != nil {
+	ids := make([]string, len(updates))
+	for i := range updates {
+		ids[i] = updates[i].GetID()
+	}
+	buf := make([]tokenbalance.TokenBalance, 0)
+	if err := storage.db.GetByIDs(&buf, ids...); err != nil {
 		return err
 	}
 
-	// bulk := bytes.NewBuffer([]byte{})
-	// for i := range updates {
-	// 	bulk.WriteString(fmt.Sprintf(`{ "update": { "_id": "%s"}}`, updates[i].GetID()))
-	// 	bulk.WriteByte('\n')
-
-	// 	script := fmt.Sprintf(scriptUpdateBalance, updates[i].Balance)
-
-	// 	upsert, err := json.Marshal(updates[i])
-	// 	if err != nil {
-	// 		return err
-	// 	}
+	updatedModels := make([]models.Model, 0)
+	insertedModels := make([]models.Model, 0)
+	for i := range updates {
+		var found bool
+		for j := range buf {
+			if buf[j].GetID() == updates[i].GetID() {
+				found = true
+				updates[i].Sum(&buf[j])
+				updatedModels = append(updatedModels, updates[i])
+				break
+			}
+		}
+		if !found {
+			insertedModels = append(insertedModels, updates[i])
+		}
+	}
 
-	// 	bulk.WriteString(fmt.Sprintf(`{ "script": %s, "upsert": %s }`, script, string(upsert)))
-	// 	bulk.WriteByte('\n')
-	// 	if (i%1000 == 0 && i > 0) || i == len(updates)-1 {
-	// 		if err := storage.bulkUpsertBalances(bulk); err != nil {
-	// 			return err
-	// 		}
-	// 		bulk.Reset()
-	// 	}
-	// }
-	return tx.Commit()
+	if err := storage.db.BulkInsert(insertedModels); err != nil {
+		return err
+	}
+	return storage.db.BulkUpdate(updatedModels)
 }
 
-// func (storage *Storage) bulkUpsertBalances(buf *bytes.Buffer) error {
-// 	req := esapi.BulkRequest{
-// 		Body:    bytes.NewReader(buf.Bytes()),
-// 		Refresh: "true",
-// 		Index:   models.DocTokenBalances,
-// 	}
-
-// 	res, err := req.Do(context.Background(), storage.es)
-// 	if err != nil {
-// 		return err
-// 	}
-// 	defer res.Body.Close()
-
-// 	var response core.BulkResponse
-// 	return storage.es.GetResponse(res, &response)
-// }
-
 // GetHolders -
-func (storage *Storage) GetHolders(network, contract string, tokenID int64) (balances []tokenbalance.TokenBalance, err error) {
+func (storage *Storage) GetHolders(network, contract string, tokenID string) (balances []tokenbalance.TokenBalance, err error) {
 	query := storage.db.Query(models.DocTokenBalances).
 		Match("network", network).
 		Match("contract", contract).
-		WhereInt64("token_id", reindexer.EQ, tokenID).
-		WhereInt64("balance", reindexer.GT, 0)
+		WhereString("token_id", reindexer.EQ, tokenID).
+		Not().WhereString("balance", reindexer.EQ, "0")
 
 	err = storage.db.GetAllByQuery(query, &balances)
 	return
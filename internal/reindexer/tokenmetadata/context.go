@@ -16,7 +16,7 @@ func buildGetTokenMetadataContext(query *reindexer.Query, ctx ...tokenmetadata.G
 	if ctx[0].Level.IsFilled() {
 		core.SetComaparator("level", ctx[0].Level, query)
 	}
-	if ctx[0].TokenID != -1 {
-		query.WhereInt64("tokens.static.token_id", reindexer.EQ, ctx[0].TokenID)
+	if ctx[0].TokenID != "" {
+		query.WhereString("tokens.static.token_id", reindexer.EQ, ctx[0].TokenID)
 	}
 }
@@ -2,6 +2,7 @@ package transfer
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
 	"github.com/baking-bad/bcdhub/internal/models"
@@ -9,6 +10,7 @@ import (
 	"github.com/baking-bad/bcdhub/internal/models/tzip"
 	"github.com/baking-bad/bcdhub/internal/reindexer/core"
 	"github.com/restream/reindexer"
+	"github.com/tidwall/gjson"
 )
 
 // Storage -
@@ -56,12 +58,12 @@ func (storage *Storage) GetAll(network string, level int64) (transfers []transfe
 }
 
 // GetTokenSupply -
-func (storage *Storage) GetTokenSupply(network, address string, tokenID int64) (result transfer.TokenSupply, err error) {
+func (storage *Storage) GetTokenSupply(network, address string, tokenID string) (result transfer.TokenSupply, err error) {
 	it := storage.db.Query(models.DocTransfers).
 		Match("network", network).
 		Match("contract", address).
 		Match("status", consts.Applied).
-		WhereInt64("token_id", reindexer.EQ, tokenID).
+		WhereString("token_id", reindexer.EQ, tokenID).
 		Exec()
 	defer it.Close()
 
@@ -69,9 +71,15 @@ func (storage *Storage) GetTokenSupply(network, address string, tokenID int64) (
 		return result, it.Error()
 	}
 
-	for it.Next() {
-		var t transfer.Transfer
-		it.NextObj(&t)
+	for {
+		var t struct {
+			From   string  `json:"from" reindex:"from"`
+			To     string  `json:"to" reindex:"to"`
+			Amount float64 `json:"amount" reindex:"amount"`
+		}
+		if !it.NextObj(&t) {
+			break
+		}
 
 		switch {
 		case t.From == "":
@@ -86,11 +94,49 @@ func (storage *Storage) GetTokenSupply(network, address string, tokenID int64) (
 }
 
 // GetTokenVolumeSeries -
-func (storage *Storage) GetTokenVolumeSeries(network, period string, contracts []string, entrypoints []tzip.DAppContract, tokenID uint) ([][]float64, error) {
-	return nil, nil
+func (storage *Storage) GetTokenVolumeSeries(network, period string, contracts []string, entrypoints []tzip.DAppContract, tokenID string) ([][]float64, error) {
+	query := storage.db.Query(models.DocTransfers).
+		Match("network", network).
+		Match("status", consts.Applied).
+		WhereString("token_id", reindexer.EQ, tokenID)
+	if len(contracts) > 0 {
+		query.WhereString("contract", reindexer.SET, contracts...)
+	}
+	if len(entrypoints) > 0 {
+		core.DexEntrypoints(query, entrypoints)
+	}
+
+	documents, err := storage.db.GetRaw(query)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers := make([]gjson.Result, 0, len(documents))
+	for i := range documents {
+		if documents[i].Get("from").String() != documents[i].Get("to").String() {
+			transfers = append(transfers, documents[i])
+		}
+	}
+	return core.Histogram(transfers, period, core.HistogramFunctionSum, "amount")
 }
 
-// GetToken24HoursVolume -
-func (storage *Storage) GetToken24HoursVolume(network, contract string, initiators, entrypoints []string, tokenID int64) (float64, error) {
-	return 0, nil
+// GetToken24HoursVolume - returns token volume for last 24 hours
+func (storage *Storage) GetToken24HoursVolume(network, contract string, initiators, entrypoints []string, tokenID string) (float64, error) {
+	query := storage.db.Query(models.DocTransfers).
+		Match("network", network).
+		Match("contract", contract).
+		Match("status", consts.Applied).
+		WhereString("token_id", reindexer.EQ, tokenID).
+		WhereInt64("timestamp", reindexer.GT, time.Now().Add(-24*time.Hour).Unix()).
+		WhereString("parent", reindexer.SET, entrypoints...).
+		WhereString("initiator", reindexer.SET, initiators...)
+	query.AggregateSum("amount")
+
+	it := query.Exec()
+	defer it.Close()
+
+	if it.Error() != nil {
+		return 0, it.Error()
+	}
+	return it.AggResults()[0].Value, nil
 }
@@ -68,12 +68,14 @@ func (storage *Storage) GetAliasesMap(network string) (map[string]string, error)
 	aliases := make(map[string]string)
 
 	type res struct {
-		Address string `reindex:"address"`
-		Name    string `reindex:"name"`
+		Address string `json:"address" reindex:"address"`
+		Name    string `json:"name" reindex:"name"`
 	}
-	for it.Next() {
+	for {
 		var r res
-		it.NextObj(&r)
+		if !it.NextObj(&r) {
+			break
+		}
 		aliases[r.Address] = r.Name
 	}
 
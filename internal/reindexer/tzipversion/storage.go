@@ -0,0 +1,98 @@
+package tzipversion
+
+import (
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/tzipversion"
+	"github.com/baking-bad/bcdhub/internal/reindexer/core"
+	"github.com/restream/reindexer"
+)
+
+// Storage -
+type Storage struct {
+	db *core.Reindexer
+}
+
+// NewStorage -
+func NewStorage(db *core.Reindexer) *Storage {
+	return &Storage{db}
+}
+
+// Get -
+func (storage *Storage) Get(network, address string, size, offset int64) ([]tzipversion.Version, error) {
+	if size == 0 {
+		size = core.DefaultSize
+	}
+
+	query := storage.db.Query(models.DocTZIPVersions).
+		Match("network", network).
+		Match("address", address).
+		Limit(int(size)).
+		Offset(int(offset)).
+		Sort("level", true)
+
+	versions := make([]tzipversion.Version, 0)
+	err := storage.db.GetAllByQuery(query, &versions)
+	return versions, err
+}
+
+// GetLast -
+func (storage *Storage) GetLast(network, address string) (version tzipversion.Version, err error) {
+	query := storage.db.Query(models.DocTZIPVersions).
+		Match("network", network).
+		Match("address", address).
+		Sort("level", true)
+
+	err = storage.db.GetOne(query, &version)
+	return
+}
+
+// GetPending -
+func (storage *Storage) GetPending(before, size int64) ([]tzipversion.Version, error) {
+	if size == 0 {
+		size = core.DefaultSize
+	}
+
+	query := storage.db.Query(models.DocTZIPVersions).
+		Match("status", tzipversion.StatusPending).
+		WhereInt64("next_attempt_at", reindexer.LE, before).
+		Limit(int(size)).
+		Sort("next_attempt_at", false)
+
+	versions := make([]tzipversion.Version, 0)
+	err := storage.db.GetAllByQuery(query, &versions)
+	return versions, err
+}
+
+// GetAfter -
+func (storage *Storage) GetAfter(network string, level int64) ([]tzipversion.Version, error) {
+	query := storage.db.Query(models.DocTZIPVersions).
+		Match("network", network).
+		WhereInt64("level", reindexer.GT, level)
+
+	versions := make([]tzipversion.Version, 0)
+	err := storage.db.GetAllByQuery(query, &versions)
+	return versions, err
+}
+
+// GetLastBefore -
+func (storage *Storage) GetLastBefore(network, address string, level int64) (version tzipversion.Version, err error) {
+	query := storage.db.Query(models.DocTZIPVersions).
+		Match("network", network).
+		Match("address", address).
+		Match("status", tzipversion.StatusFetched).
+		WhereInt64("level", reindexer.LE, level).
+		Sort("level", true)
+
+	err = storage.db.GetOne(query, &version)
+	return
+}
+
+// GetReferenced -
+func (storage *Storage) GetReferenced() ([]tzipversion.Version, error) {
+	query := storage.db.Query(models.DocTZIPVersions).
+		Not().WhereString("ref_key_hash", reindexer.EMPTY, "")
+
+	versions := make([]tzipversion.Version, 0)
+	err := storage.db.GetAllByQuery(query, &versions)
+	return versions, err
+}
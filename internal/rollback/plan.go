@@ -0,0 +1,146 @@
+package rollback
+
+import (
+	"sort"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/block"
+	"github.com/baking-bad/bcdhub/internal/models/rollbackplan"
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
+	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
+	"github.com/pkg/errors"
+)
+
+// ErrTargetChanged - target block of rollback is not in the main chain of the node anymore
+var ErrTargetChanged = errors.New("rollback target block differs from node")
+
+func (rm Manager) getPlan(network string) (*rollbackplan.Plan, error) {
+	plan := rollbackplan.NewPlan(network)
+	if err := rm.storage.GetByID(plan); err != nil {
+		if rm.storage.IsRecordNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (rm Manager) createPlan(fromState block.Block, toLevel int64) (*rollbackplan.Plan, error) {
+	plan := rollbackplan.NewPlan(fromState.Network)
+	plan.FromLevel = fromState.Level
+	plan.ToLevel = toLevel
+	plan.Protocol = fromState.Protocol
+	plan.CreatedAt = time.Now().UTC()
+
+	if toLevel > 0 {
+		target, err := rm.blocksRepo.Get(fromState.Network, toLevel)
+		if err != nil {
+			return nil, err
+		}
+		plan.ToHash = target.Hash
+		if err := rm.verifyTarget(plan); err != nil {
+			return nil, err
+		}
+	}
+
+	affected, err := rm.getAffectedContracts(fromState.Network, fromState.Level, toLevel)
+	if err != nil {
+		return nil, err
+	}
+	plan.AffectedContracts = affected
+	logger.Info("Rollback will affect %d contracts", len(affected))
+
+	if err := rm.storage.BulkInsert([]models.Model{plan}); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// verifyTarget - checks that block which indexer rolls back to is still in the main chain of the node
+func (rm Manager) verifyTarget(plan *rollbackplan.Plan) error {
+	if plan.ToHash == "" {
+		return nil
+	}
+	header, err := rm.rpc.GetHeader(plan.ToLevel)
+	if err != nil {
+		return err
+	}
+	if header.Hash != plan.ToHash {
+		return errors.Wrapf(ErrTargetChanged, "level %d: %s != %s", plan.ToLevel, plan.ToHash, header.Hash)
+	}
+	return nil
+}
+
+func (rm Manager) getAffectedContracts(network string, fromLevel, toLevel int64) ([]string, error) {
+	addresses, err := rm.operationRepo.GetParticipatingContracts(network, fromLevel, toLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	return rm.contractsRepo.GetIDsByAddresses(addresses, network)
+}
+
+// getTokenBalanceUpdates - returns changes of token balances which revert transfers after `toLevel`. Changes are sorted by balance id.
+func (rm Manager) getTokenBalanceUpdates(network string, toLevel int64) ([]*tokenbalance.TokenBalance, error) {
+	transfers, err := rm.transfersRepo.GetAll(network, toLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	exists := make(map[string]*tokenbalance.TokenBalance)
+	updates := make([]*tokenbalance.TokenBalance, 0)
+	for i := range transfers {
+		if id := transfers[i].GetFromTokenBalanceID(); id != "" {
+			if update, ok := exists[id]; ok {
+				update.Value.Add(update.Value, transfers[i].AmountBigInt)
+			} else {
+				upd := transfers[i].MakeTokenBalanceUpdate(true, true)
+				updates = append(updates, upd)
+				exists[id] = upd
+			}
+		}
+
+		if id := transfers[i].GetToTokenBalanceID(); id != "" {
+			if update, ok := exists[id]; ok {
+				update.Value.Sub(update.Value, transfers[i].AmountBigInt)
+			} else {
+				upd := transfers[i].MakeTokenBalanceUpdate(false, true)
+				updates = append(updates, upd)
+				exists[id] = upd
+			}
+		}
+	}
+
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].GetID() < updates[j].GetID()
+	})
+	return updates, nil
+}
+
+// getTicketBalanceUpdates - returns changes of ticket balances which revert ticket updates after `toLevel`. Changes are sorted by balance id.
+func (rm Manager) getTicketBalanceUpdates(network string, toLevel int64) ([]*ticketbalance.TicketBalance, error) {
+	ticketUpdates, err := rm.ticketsRepo.GetAll(network, toLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	exists := make(map[string]*ticketbalance.TicketBalance)
+	updates := make([]*ticketbalance.TicketBalance, 0)
+	for i := range ticketUpdates {
+		id := ticketUpdates[i].GetTicketBalanceID()
+		upd := ticketUpdates[i].MakeTicketBalanceUpdate(true)
+		if balance, ok := exists[id]; ok {
+			balance.Sum(upd)
+			continue
+		}
+		exists[id] = upd
+		updates = append(updates, upd)
+	}
+
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].GetID() < updates[j].GetID()
+	})
+	return updates, nil
+}
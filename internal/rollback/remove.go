@@ -5,6 +5,7 @@ import (
 	"github.com/baking-bad/bcdhub/internal/logger"
 	"github.com/baking-bad/bcdhub/internal/models"
 	"github.com/baking-bad/bcdhub/internal/models/contract"
+	"github.com/baking-bad/bcdhub/internal/models/rollbackplan"
 	"github.com/baking-bad/bcdhub/internal/models/schema"
 )
 
@@ -18,7 +19,10 @@ func Remove(storage models.GeneralRepository, contractsRepo contract.Repository,
 
 func removeOthers(storage models.GeneralRepository, network string) error {
 	logger.Info("Deleting general data...")
-	return storage.DeleteByLevelAndNetwork([]string{models.DocBigMapDiff, models.DocBigMapActions, models.DocMigrations, models.DocOperations, models.DocTransfers, models.DocBlocks, models.DocProtocol}, network, -1)
+	if err := storage.BulkDelete([]models.Model{rollbackplan.NewPlan(network)}); err != nil {
+		return err
+	}
+	return storage.DeleteByLevelAndNetwork([]string{models.DocBigMapDiff, models.DocBigMapActions, models.DocMigrations, models.DocOperations, models.DocOutbox, models.DocSaplingDiffs, models.DocTicketUpdates, models.DocTransfers, models.DocTZIPVersions, models.DocBlocks, models.DocProtocol}, network, -1)
 }
 
 func removeContracts(storage models.GeneralRepository, contractsRepo contract.Repository, network, appDir string) error {
@@ -2,7 +2,7 @@ package rollback
 
 import (
 	"fmt"
-	"time"
+	"math/big"
 
 	"github.com/baking-bad/bcdhub/internal/contractparser"
 	"github.com/baking-bad/bcdhub/internal/helpers"
@@ -12,139 +12,382 @@ import (
 	"github.com/baking-bad/bcdhub/internal/models/contract"
 	"github.com/baking-bad/bcdhub/internal/models/operation"
 	"github.com/baking-bad/bcdhub/internal/models/protocol"
+	"github.com/baking-bad/bcdhub/internal/models/rollbackplan"
 	"github.com/baking-bad/bcdhub/internal/models/schema"
+	"github.com/baking-bad/bcdhub/internal/models/ticketbalance"
+	"github.com/baking-bad/bcdhub/internal/models/ticketupdate"
 	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
 	"github.com/baking-bad/bcdhub/internal/models/transfer"
+	tzipModel "github.com/baking-bad/bcdhub/internal/models/tzip"
+	"github.com/baking-bad/bcdhub/internal/models/tzipversion"
 	"github.com/baking-bad/bcdhub/internal/mq"
 	"github.com/baking-bad/bcdhub/internal/noderpc"
 	"github.com/pkg/errors"
 	"github.com/schollz/progressbar/v3"
 )
 
+// Rollback steps. Their order is persisted in plan so it must not be changed.
+const (
+	stepTokenBalances = iota
+	stepTicketBalances
+	stepOperations
+	stepContracts
+	stepBlocks
+	stepRecalc
+	stepsCount
+)
+
+const balancesPageSize = 1000
+
 // Manager -
 type Manager struct {
 	storage       models.GeneralRepository
+	blocksRepo    block.Repository
 	contractsRepo contract.Repository
 	operationRepo operation.Repository
 	transfersRepo transfer.Repository
-	tbRepo        tokenbalance.Repository
+	ticketsRepo   ticketupdate.Repository
 	protocolsRepo protocol.Repository
+	versionsRepo  tzipversion.Repository
 	messageQueue  mq.IMessagePublisher
 	rpc           noderpc.INode
 	sharePath     string
 }
 
 // NewManager -
-func NewManager(storage models.GeneralRepository, contractsRepo contract.Repository, operationRepo operation.Repository, transfersRepo transfer.Repository, tbRepo tokenbalance.Repository, protocolsRepo protocol.Repository, messageQueue mq.IMessagePublisher, rpc noderpc.INode, sharePath string) Manager {
+func NewManager(storage models.GeneralRepository, blocksRepo block.Repository, contractsRepo contract.Repository, operationRepo operation.Repository, transfersRepo transfer.Repository, ticketsRepo ticketupdate.Repository, protocolsRepo protocol.Repository, versionsRepo tzipversion.Repository, messageQueue mq.IMessagePublisher, rpc noderpc.INode, sharePath string) Manager {
 	return Manager{
-		storage, contractsRepo, operationRepo, transfersRepo, tbRepo, protocolsRepo, messageQueue, rpc, sharePath,
+		storage, blocksRepo, contractsRepo, operationRepo, transfersRepo, ticketsRepo, protocolsRepo, versionsRepo, messageQueue, rpc, sharePath,
 	}
 }
 
-// Rollback - rollback indexer state to level
+// Rollback - rollback indexer state to level. If unfinished rollback of the network exists it is completed first.
 func (rm Manager) Rollback(fromState block.Block, toLevel int64) error {
 	if toLevel >= fromState.Level {
 		return errors.Errorf("To level must be less than from level: %d >= %d", toLevel, fromState.Level)
 	}
-	affectedContractIDs, err := rm.getAffectedContracts(fromState.Network, fromState.Level, toLevel)
+
+	plan, err := rm.getPlan(fromState.Network)
 	if err != nil {
 		return err
 	}
-	logger.Info("Rollback will affect %d contracts", len(affectedContractIDs))
+	if plan != nil {
+		if plan.ToLevel <= toLevel {
+			logger.Warning("[%s] Resuming unfinished rollback to %d instead of rollback to %d", plan.Network, plan.ToLevel, toLevel)
+			return rm.execute(plan, true)
+		}
 
-	if err := rm.rollbackTokenBalances(fromState.Network, toLevel); err != nil {
-		return err
-	}
-	if err := rm.rollbackOperations(fromState.Network, toLevel); err != nil {
-		return err
+		// target of unfinished rollback is not in the main chain anymore, so it is finished without verification and rollback goes deeper
+		logger.Warning("[%s] Finishing unfinished rollback to %d before rollback to %d", plan.Network, plan.ToLevel, toLevel)
+		if err := rm.execute(plan, false); err != nil {
+			return err
+		}
+		fromState = block.Block{
+			Network:  plan.Network,
+			Level:    plan.ToLevel,
+			Protocol: fromState.Protocol,
+		}
 	}
-	if err := rm.rollbackContracts(fromState, toLevel); err != nil {
+
+	plan, err = rm.createPlan(fromState, toLevel)
+	if err != nil {
 		return err
 	}
-	if err := rm.rollbackBlocks(fromState.Network, toLevel); err != nil {
-		return err
+	return rm.execute(plan, true)
+}
+
+// Resume - completes unfinished rollback of `network` if it exists. Returns true if rollback was resumed.
+func (rm Manager) Resume(network string) (bool, error) {
+	plan, err := rm.getPlan(network)
+	if err != nil || plan == nil {
+		return false, err
 	}
+	logger.Warning("[%s] Resuming unfinished rollback from %d to %d (step %d of %d)", network, plan.FromLevel, plan.ToLevel, plan.Step+1, stepsCount)
+	return true, rm.execute(plan, true)
+}
 
-	time.Sleep(time.Second) // Golden hack: Waiting while elastic remove records
-	logger.Info("Sending to queue affected contract ids...")
-	for i := range affectedContractIDs {
-		if err := rm.messageQueue.SendRaw(mq.QueueRecalc, []byte(affectedContractIDs[i])); err != nil {
+func (rm Manager) execute(plan *rollbackplan.Plan, verify bool) error {
+	for plan.Step < stepsCount {
+		if verify {
+			if err := rm.verifyTarget(plan); err != nil {
+				return err
+			}
+		}
+
+		if err := rm.executeStep(plan); err != nil {
+			return errors.Wrapf(err, "rollback step %d", plan.Step)
+		}
+
+		plan.Step++
+		plan.Offset = 0
+		if err := rm.storage.UpdateDoc(plan); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return rm.storage.BulkDelete([]models.Model{plan})
 }
 
-func (rm Manager) rollbackTokenBalances(network string, toLevel int64) error {
-	transfers, err := rm.transfersRepo.GetAll(network, toLevel)
+func (rm Manager) executeStep(plan *rollbackplan.Plan) error {
+	switch plan.Step {
+	case stepTokenBalances:
+		return rm.rollbackTokenBalances(plan)
+	case stepTicketBalances:
+		return rm.rollbackTicketBalances(plan)
+	case stepOperations:
+		return rm.rollbackOperations(plan.Network, plan.ToLevel)
+	case stepContracts:
+		return rm.rollbackContracts(plan)
+	case stepBlocks:
+		return rm.rollbackBlocks(plan.Network, plan.ToLevel)
+	case stepRecalc:
+		return rm.sendRecalc(plan.AffectedContracts)
+	default:
+		return errors.Errorf("Unknown rollback step: %d", plan.Step)
+	}
+}
+
+func (rm Manager) rollbackTokenBalances(plan *rollbackplan.Plan) error {
+	updates, err := rm.getTokenBalanceUpdates(plan.Network, plan.ToLevel)
 	if err != nil {
 		return err
 	}
-	if len(transfers) == 0 {
+	if len(updates) == 0 {
 		return nil
 	}
+	logger.Info("Restoring %d token balances...", len(updates))
+
+	for plan.Offset < len(updates) {
+		if len(plan.TokenBalances) == 0 {
+			end := helpers.MinInt(plan.Offset+balancesPageSize, len(updates))
+			page, err := rm.getTokenBalancesPage(updates[plan.Offset:end])
+			if err != nil {
+				return err
+			}
+			plan.TokenBalances = page
+			if err := rm.storage.UpdateDoc(plan); err != nil {
+				plan.TokenBalances = nil
+				return err
+			}
+		}
+
+		items := make([]models.Model, len(plan.TokenBalances))
+		for i := range plan.TokenBalances {
+			items[i] = plan.TokenBalances[i]
+		}
+		if err := rm.applyPage(plan, items); err != nil {
+			return err
+		}
+	}
+	return rm.storage.Refresh([]string{models.DocTokenBalances})
+}
 
-	exists := make(map[string]*tokenbalance.TokenBalance)
-	updates := make([]*tokenbalance.TokenBalance, 0)
-	for i := range transfers {
+// getTokenBalancesPage - returns balances at rollback level: current balances with reverted changes
+func (rm Manager) getTokenBalancesPage(updates []*tokenbalance.TokenBalance) ([]*tokenbalance.TokenBalance, error) {
+	ids := make([]string, len(updates))
+	for i := range updates {
+		ids[i] = updates[i].GetID()
+	}
+	current := make([]tokenbalance.TokenBalance, 0)
+	if err := rm.storage.GetByIDs(&current, ids...); err != nil && !rm.storage.IsRecordNotFound(err) {
+		return nil, err
+	}
+	byID := make(map[string]*tokenbalance.TokenBalance, len(current))
+	for i := range current {
+		byID[current[i].GetID()] = &current[i]
+	}
 
-		if id := transfers[i].GetFromTokenBalanceID(); id != "" {
-			if update, ok := exists[id]; ok {
-				update.Value.Add(update.Value, transfers[i].AmountBigInt)
-			} else {
-				upd := transfers[i].MakeTokenBalanceUpdate(true, true)
-				updates = append(updates, upd)
-				exists[id] = upd
-			}
+	page := make([]*tokenbalance.TokenBalance, len(updates))
+	for i := range updates {
+		balance := *updates[i]
+		balance.Value = new(big.Int).Set(updates[i].Value)
+		if value, ok := byID[ids[i]]; ok {
+			balance.Sum(value)
 		}
+		page[i] = &balance
+	}
+	return page, nil
+}
 
-		if id := transfers[i].GetToTokenBalanceID(); id != "" {
-			if update, ok := exists[id]; ok {
-				update.Value.Sub(update.Value, transfers[i].AmountBigInt)
-			} else {
-				upd := transfers[i].MakeTokenBalanceUpdate(false, true)
-				updates = append(updates, upd)
-				exists[id] = upd
+func (rm Manager) rollbackTicketBalances(plan *rollbackplan.Plan) error {
+	updates, err := rm.getTicketBalanceUpdates(plan.Network, plan.ToLevel)
+	if err != nil {
+		return err
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	logger.Info("Restoring %d ticket balances...", len(updates))
+
+	for plan.Offset < len(updates) {
+		if len(plan.TicketBalances) == 0 {
+			end := helpers.MinInt(plan.Offset+balancesPageSize, len(updates))
+			page, err := rm.getTicketBalancesPage(updates[plan.Offset:end])
+			if err != nil {
+				return err
+			}
+			plan.TicketBalances = page
+			if err := rm.storage.UpdateDoc(plan); err != nil {
+				plan.TicketBalances = nil
+				return err
 			}
 		}
+
+		items := make([]models.Model, len(plan.TicketBalances))
+		for i := range plan.TicketBalances {
+			items[i] = plan.TicketBalances[i]
+		}
+		if err := rm.applyPage(plan, items); err != nil {
+			return err
+		}
+	}
+	return rm.storage.Refresh([]string{models.DocTicketBalances})
+}
+
+// getTicketBalancesPage - returns balances at rollback level: current balances with reverted changes
+func (rm Manager) getTicketBalancesPage(updates []*ticketbalance.TicketBalance) ([]*ticketbalance.TicketBalance, error) {
+	ids := make([]string, len(updates))
+	for i := range updates {
+		ids[i] = updates[i].GetID()
+	}
+	current := make([]ticketbalance.TicketBalance, 0)
+	if err := rm.storage.GetByIDs(&current, ids...); err != nil && !rm.storage.IsRecordNotFound(err) {
+		return nil, err
+	}
+	byID := make(map[string]*ticketbalance.TicketBalance, len(current))
+	for i := range current {
+		byID[current[i].GetID()] = &current[i]
+	}
+
+	page := make([]*ticketbalance.TicketBalance, len(updates))
+	for i := range updates {
+		balance := *updates[i]
+		balance.Value = new(big.Int).Set(updates[i].Value)
+		if value, ok := byID[ids[i]]; ok {
+			balance.Sum(value)
+		}
+		page[i] = &balance
+	}
+	return page, nil
+}
+
+// applyPage - writes page of balances saved in plan and moves plan to the next page. Page contains absolute values,
+// so if rollback is interrupted before plan is moved, the page is written again with the same result.
+func (rm Manager) applyPage(plan *rollbackplan.Plan, items []models.Model) error {
+	if err := rm.storage.BulkInsert(items); err != nil {
+		return err
 	}
+	next := *plan
+	next.Offset += len(items)
+	next.TokenBalances = nil
+	next.TicketBalances = nil
+	if err := rm.storage.UpdateDoc(&next); err != nil {
+		return err
+	}
+	*plan = next
+	return nil
+}
 
-	return rm.tbRepo.Update(updates)
+func (rm Manager) sendRecalc(addresses []string) error {
+	logger.Info("Sending to queue affected contract ids...")
+	for i := range addresses {
+		if err := rm.messageQueue.SendRaw(mq.QueueRecalc, []byte(addresses[i])); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (rm Manager) rollbackBlocks(network string, toLevel int64) error {
 	logger.Info("Deleting blocks...")
-	return rm.storage.DeleteByLevelAndNetwork([]string{models.DocBlocks}, network, toLevel)
+	if err := rm.storage.DeleteByLevelAndNetwork([]string{models.DocBlocks}, network, toLevel); err != nil {
+		return err
+	}
+	return rm.storage.Refresh([]string{models.DocBlocks})
 }
 
 func (rm Manager) rollbackOperations(network string, toLevel int64) error {
-	logger.Info("Deleting operations, migrations, transfers and big map diffs...")
-	return rm.storage.DeleteByLevelAndNetwork([]string{models.DocBigMapDiff, models.DocBigMapActions, models.DocTZIP, models.DocMigrations, models.DocOperations, models.DocTransfers, models.DocTokenMetadata}, network, toLevel)
+	if err := rm.restoreMetadata(network, toLevel); err != nil {
+		return err
+	}
+
+	logger.Info("Deleting operations, migrations, transfers, ticket updates and big map diffs...")
+	indices := []string{models.DocBigMapDiff, models.DocBigMapActions, models.DocTZIP, models.DocTZIPVersions, models.DocMigrations, models.DocOperations, models.DocOutbox, models.DocSaplingDiffs, models.DocTicketUpdates, models.DocTransfers, models.DocTokenMetadata}
+	if err := rm.storage.DeleteByLevelAndNetwork(indices, network, toLevel); err != nil {
+		return err
+	}
+	return rm.storage.Refresh(indices)
+}
+
+// restoreMetadata - sets the latest fetched version which is not rolled back as current metadata of contracts which versions are rolled back.
+// Metadata of contracts without such version is deleted with rolled back versions.
+func (rm Manager) restoreMetadata(network string, toLevel int64) error {
+	if rm.versionsRepo == nil {
+		return nil
+	}
+	versions, err := rm.versionsRepo.GetAfter(network, toLevel)
+	if err != nil {
+		if rm.storage.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	items := make([]models.Model, 0)
+	processed := make(map[string]struct{})
+	for i := range versions {
+		if _, ok := processed[versions[i].Address]; ok {
+			continue
+		}
+		processed[versions[i].Address] = struct{}{}
+
+		current := tzipModel.TZIP{
+			Network: network,
+			Address: versions[i].Address,
+		}
+		if err := rm.storage.GetByID(&current); err == nil && current.OffChain {
+			continue
+		}
+
+		last, err := rm.versionsRepo.GetLastBefore(network, versions[i].Address, toLevel)
+		if err != nil {
+			if rm.storage.IsRecordNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if last.Metadata != nil {
+			items = append(items, last.Metadata)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	logger.Info("Restoring metadata of %d contracts...", len(items))
+	if err := rm.storage.BulkInsert(items); err != nil {
+		return err
+	}
+	return rm.storage.Refresh([]string{models.DocTZIP})
 }
 
-func (rm Manager) rollbackContracts(fromState block.Block, toLevel int64) error {
-	if err := rm.removeMetadata(fromState, toLevel); err != nil {
+func (rm Manager) rollbackContracts(plan *rollbackplan.Plan) error {
+	if err := rm.removeMetadata(plan.Network, plan.Protocol, plan.ToLevel); err != nil {
 		return err
 	}
-	if err := rm.updateMetadata(fromState.Network, fromState.Level, toLevel); err != nil {
+	if err := rm.updateMetadata(plan.Network, plan.FromLevel, plan.ToLevel); err != nil {
 		return err
 	}
 
 	logger.Info("Deleting contracts...")
+	toLevel := plan.ToLevel
 	if toLevel == 0 {
 		toLevel = -1
 	}
-	return rm.storage.DeleteByLevelAndNetwork([]string{models.DocContracts}, fromState.Network, toLevel)
-}
-
-func (rm Manager) getAffectedContracts(network string, fromLevel, toLevel int64) ([]string, error) {
-	addresses, err := rm.operationRepo.GetParticipatingContracts(network, fromLevel, toLevel)
-	if err != nil {
-		return nil, err
+	if err := rm.storage.DeleteByLevelAndNetwork([]string{models.DocContracts}, plan.Network, toLevel); err != nil {
+		return err
 	}
-
-	return rm.contractsRepo.GetIDsByAddresses(addresses, network)
+	return rm.storage.Refresh([]string{models.DocContracts, models.DocSchema})
 }
 
 func (rm Manager) getProtocolByLevel(protocols []protocol.Protocol, level int64) (protocol.Protocol, error) {
@@ -159,14 +402,14 @@ func (rm Manager) getProtocolByLevel(protocols []protocol.Protocol, level int64)
 	return protocols[0], nil
 }
 
-func (rm Manager) removeMetadata(fromState block.Block, toLevel int64) error {
+func (rm Manager) removeMetadata(network, protocol string, toLevel int64) error {
 	logger.Info("Preparing metadata for removing...")
-	addresses, err := rm.contractsRepo.GetAddressesByNetworkAndLevel(fromState.Network, toLevel)
+	addresses, err := rm.contractsRepo.GetAddressesByNetworkAndLevel(network, toLevel)
 	if err != nil {
 		return err
 	}
 
-	return rm.removeContractsMetadata(fromState.Network, addresses, fromState.Protocol)
+	return rm.removeContractsMetadata(network, addresses, protocol)
 }
 
 func (rm Manager) removeContractsMetadata(network string, addresses []string, protocol string) error {
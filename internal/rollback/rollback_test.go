@@ -0,0 +1,179 @@
+package rollback
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/block"
+	mock_general "github.com/baking-bad/bcdhub/internal/models/mock"
+	mock_block "github.com/baking-bad/bcdhub/internal/models/mock/block"
+	mock_transfer "github.com/baking-bad/bcdhub/internal/models/mock/transfer"
+	"github.com/baking-bad/bcdhub/internal/models/rollbackplan"
+	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
+	"github.com/baking-bad/bcdhub/internal/models/transfer"
+	"github.com/baking-bad/bcdhub/internal/mq"
+	"github.com/baking-bad/bcdhub/internal/noderpc"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+)
+
+func TestManager_Rollback_TargetChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mock_general.NewMockGeneralRepository(ctrl)
+	blocks := mock_block.NewMockRepository(ctrl)
+	rpc := noderpc.NewMockINode(ctrl)
+
+	notFound := errors.New("not found")
+	storage.EXPECT().GetByID(gomock.Any()).Return(notFound)
+	storage.EXPECT().IsRecordNotFound(notFound).Return(true)
+	blocks.EXPECT().Get("mainnet", int64(90)).Return(block.Block{Network: "mainnet", Level: 90, Hash: "BLockA"}, nil)
+	rpc.EXPECT().GetHeader(int64(90)).Return(noderpc.Header{Level: 90, Hash: "BLockB"}, nil)
+
+	manager := NewManager(storage, blocks, nil, nil, nil, nil, nil, nil, nil, rpc, "")
+	err := manager.Rollback(block.Block{Network: "mainnet", Level: 100}, 90)
+	if errors.Cause(err) != ErrTargetChanged {
+		t.Errorf("Rollback() error = %v, want %v", err, ErrTargetChanged)
+	}
+}
+
+func TestManager_Resume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mock_general.NewMockGeneralRepository(ctrl)
+	rpc := noderpc.NewMockINode(ctrl)
+	messageQueue := mq.NewMockIMessagePublisher(ctrl)
+
+	storage.EXPECT().GetByID(gomock.Any()).DoAndReturn(func(model models.Model) error {
+		plan := model.(*rollbackplan.Plan)
+		plan.FromLevel = 100
+		plan.ToLevel = 90
+		plan.ToHash = "BLockA"
+		plan.Step = stepBlocks
+		plan.AffectedContracts = []string{"contract_id"}
+		return nil
+	})
+	rpc.EXPECT().GetHeader(int64(90)).Return(noderpc.Header{Level: 90, Hash: "BLockA"}, nil).Times(2)
+
+	gomock.InOrder(
+		storage.EXPECT().DeleteByLevelAndNetwork([]string{models.DocBlocks}, "mainnet", int64(90)).Return(nil),
+		storage.EXPECT().Refresh([]string{models.DocBlocks}).Return(nil),
+		storage.EXPECT().UpdateDoc(gomock.Any()).Return(nil),
+		messageQueue.EXPECT().SendRaw(mq.QueueRecalc, []byte("contract_id")).Return(nil),
+		storage.EXPECT().UpdateDoc(gomock.Any()).Return(nil),
+		storage.EXPECT().BulkDelete(gomock.Any()).Return(nil),
+	)
+
+	manager := NewManager(storage, nil, nil, nil, nil, nil, nil, nil, messageQueue, rpc, "")
+	resumed, err := manager.Resume("mainnet")
+	if err != nil {
+		t.Errorf("Resume() error = %v", err)
+		return
+	}
+	if !resumed {
+		t.Errorf("Resume() resumed = false, want true")
+	}
+}
+
+func TestManager_rollbackTokenBalances_Resume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mock_general.NewMockGeneralRepository(ctrl)
+	transfers := mock_transfer.NewMockRepository(ctrl)
+
+	transfers.EXPECT().GetAll("mainnet", int64(90)).Return([]transfer.Transfer{
+		{Network: "mainnet", Contract: "KT1token", TokenID: "0", From: "tz1sender", To: "tz1receiver", AmountBigInt: big.NewInt(5)},
+	}, nil)
+	// balance of receiver is the first one by id and it was restored before restart
+	storage.EXPECT().GetByIDs(gomock.Any(), "mainnet_tz1receiver_KT1token_0").Times(0)
+	storage.EXPECT().GetByIDs(gomock.Any(), "mainnet_tz1sender_KT1token_0").DoAndReturn(func(output interface{}, ids ...string) error {
+		balances := output.(*[]tokenbalance.TokenBalance)
+		*balances = append(*balances, tokenbalance.TokenBalance{Network: "mainnet", Contract: "KT1token", TokenID: "0", Address: "tz1sender", Value: big.NewInt(8)})
+		return nil
+	})
+	gomock.InOrder(
+		storage.EXPECT().UpdateDoc(gomock.Any()).DoAndReturn(func(model models.Model) error {
+			plan := model.(*rollbackplan.Plan)
+			if plan.Offset != 1 || len(plan.TokenBalances) != 1 || plan.TokenBalances[0].Value.Int64() != 13 {
+				t.Errorf("UpdateDoc() plan has to keep offset 1 and page with balance 13 before the page is written")
+			}
+			return nil
+		}),
+		storage.EXPECT().BulkInsert(gomock.Any()).DoAndReturn(func(items []models.Model) error {
+			if len(items) != 1 {
+				t.Errorf("BulkInsert() count = %d, want 1", len(items))
+				return nil
+			}
+			balance := items[0].(*tokenbalance.TokenBalance)
+			if balance.Address != "tz1sender" || balance.Value.Int64() != 13 {
+				t.Errorf("BulkInsert() balance of %s = %s, want 13 of tz1sender", balance.Address, balance.Value)
+			}
+			return nil
+		}),
+		storage.EXPECT().UpdateDoc(gomock.Any()).DoAndReturn(func(model models.Model) error {
+			if plan := model.(*rollbackplan.Plan); plan.Offset != 2 || len(plan.TokenBalances) != 0 {
+				t.Errorf("UpdateDoc() plan offset = %d with %d pending balances, want 2 without pending balances", plan.Offset, len(plan.TokenBalances))
+			}
+			return nil
+		}),
+		storage.EXPECT().Refresh([]string{models.DocTokenBalances}).Return(nil),
+	)
+
+	plan := &rollbackplan.Plan{Network: "mainnet", FromLevel: 100, ToLevel: 90, Step: stepTokenBalances, Offset: 1}
+	manager := NewManager(storage, nil, nil, nil, transfers, nil, nil, nil, nil, nil, "")
+	if err := manager.rollbackTokenBalances(plan); err != nil {
+		t.Errorf("rollbackTokenBalances() error = %v", err)
+	}
+}
+
+func TestManager_rollbackTokenBalances_ResumePendingPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mock_general.NewMockGeneralRepository(ctrl)
+	transfers := mock_transfer.NewMockRepository(ctrl)
+
+	transfers.EXPECT().GetAll("mainnet", int64(90)).Return([]transfer.Transfer{
+		{Network: "mainnet", Contract: "KT1token", TokenID: "0", From: "tz1sender", To: "tz1receiver", AmountBigInt: big.NewInt(5)},
+	}, nil)
+	// page may be written partially before restart, so current balances are not read again
+	storage.EXPECT().GetByIDs(gomock.Any(), gomock.Any()).Times(0)
+	gomock.InOrder(
+		storage.EXPECT().BulkInsert(gomock.Any()).DoAndReturn(func(items []models.Model) error {
+			if len(items) != 2 {
+				t.Errorf("BulkInsert() count = %d, want 2", len(items))
+				return nil
+			}
+			for i, want := range []int64{0, 13} {
+				if value := items[i].(*tokenbalance.TokenBalance).Value.Int64(); value != want {
+					t.Errorf("BulkInsert() balance #%d = %d, want %d", i, value, want)
+				}
+			}
+			return nil
+		}),
+		storage.EXPECT().UpdateDoc(gomock.Any()).Return(nil),
+		storage.EXPECT().Refresh([]string{models.DocTokenBalances}).Return(nil),
+	)
+
+	plan := &rollbackplan.Plan{
+		Network:   "mainnet",
+		FromLevel: 100,
+		ToLevel:   90,
+		Step:      stepTokenBalances,
+		TokenBalances: []*tokenbalance.TokenBalance{
+			{Network: "mainnet", Contract: "KT1token", TokenID: "0", Address: "tz1receiver", Value: big.NewInt(0)},
+			{Network: "mainnet", Contract: "KT1token", TokenID: "0", Address: "tz1sender", Value: big.NewInt(13)},
+		},
+	}
+	manager := NewManager(storage, nil, nil, nil, transfers, nil, nil, nil, nil, nil, "")
+	if err := manager.rollbackTokenBalances(plan); err != nil {
+		t.Errorf("rollbackTokenBalances() error = %v", err)
+	}
+	if plan.Offset != 2 || len(plan.TokenBalances) != 0 {
+		t.Errorf("rollbackTokenBalances() plan offset = %d with %d pending balances, want 2 without pending balances", plan.Offset, len(plan.TokenBalances))
+	}
+}
@@ -0,0 +1,153 @@
+package safehttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// errors
+var (
+	ErrForbiddenAddress = errors.New("Forbidden address")
+	ErrInvalidURL       = errors.New("Invalid URL")
+	ErrRedirect         = errors.New("Redirects are not allowed")
+)
+
+var forbiddenNetworks = parseNetworks(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+// Guard - rejects requests to loopback, private, link-local, unspecified and multicast addresses.
+// Hosts from allowlist are not checked.
+type Guard struct {
+	allowed map[string]struct{}
+}
+
+// NewGuard -
+func NewGuard(allowedHosts ...string) *Guard {
+	allowed := make(map[string]struct{}, len(allowedHosts))
+	for i := range allowedHosts {
+		if host := strings.ToLower(strings.TrimSpace(allowedHosts[i])); host != "" {
+			allowed[host] = struct{}{}
+		}
+	}
+	return &Guard{allowed}
+}
+
+// IsAllowedHost - returns true if `host` is in allowlist
+func (g *Guard) IsAllowedHost(host string) bool {
+	_, ok := g.allowed[strings.ToLower(host)]
+	return ok
+}
+
+// CheckURL - validates scheme of `rawURL` and resolves its host. Returns error if any of host addresses is forbidden.
+func (g *Guard) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.Wrap(ErrInvalidURL, rawURL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Hostname() == "" {
+		return errors.Wrap(ErrInvalidURL, rawURL)
+	}
+
+	host := u.Hostname()
+	if g.IsAllowedHost(host) {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return checkIP(ip)
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for i := range addrs {
+		if err := checkIP(addrs[i].IP); err != nil {
+			return errors.Wrap(err, host)
+		}
+	}
+	return nil
+}
+
+// Client - returns HTTP client which checks addresses on connection and refuses redirects.
+// Address is checked after DNS resolving, so DNS rebinding can not bypass the guard.
+func (g *Guard) Client(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           g.DialContext,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return errors.Wrap(ErrRedirect, req.URL.String())
+		},
+	}
+}
+
+// DialContext - dials `address` and checks the address of connection before it is established
+func (g *Guard) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if host, _, err := net.SplitHostPort(address); err != nil || !g.IsAllowedHost(host) {
+		dialer.Control = control
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+func control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return errors.Wrap(ErrForbiddenAddress, host)
+	}
+	return checkIP(ip)
+}
+
+func checkIP(ip net.IP) error {
+	for i := range forbiddenNetworks {
+		if forbiddenNetworks[i].Contains(ip) {
+			return errors.Wrap(ErrForbiddenAddress, ip.String())
+		}
+	}
+	return nil
+}
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i := range cidrs {
+		_, network, err := net.ParseCIDR(cidrs[i])
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
@@ -0,0 +1,101 @@
+package safehttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGuard_CheckURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		url     string
+		wantErr error
+	}{
+		{
+			name: "public address",
+			url:  "https://1.1.1.1/api",
+		}, {
+			name:    "loopback",
+			url:     "http://127.0.0.1:8080/",
+			wantErr: ErrForbiddenAddress,
+		}, {
+			name:    "private",
+			url:     "http://192.168.1.10/",
+			wantErr: ErrForbiddenAddress,
+		}, {
+			name:    "link-local",
+			url:     "http://169.254.169.254/latest/meta-data",
+			wantErr: ErrForbiddenAddress,
+		}, {
+			name:    "unspecified",
+			url:     "http://0.0.0.0/",
+			wantErr: ErrForbiddenAddress,
+		}, {
+			name:    "IPv6 loopback",
+			url:     "http://[::1]/",
+			wantErr: ErrForbiddenAddress,
+		}, {
+			name:    "IPv4-mapped IPv6",
+			url:     "http://[::ffff:10.0.0.1]/",
+			wantErr: ErrForbiddenAddress,
+		}, {
+			name:    "localhost",
+			url:     "http://localhost/",
+			wantErr: ErrForbiddenAddress,
+		}, {
+			name:    "allowed host",
+			allowed: []string{"LocalHost"},
+			url:     "http://localhost/",
+		}, {
+			name:    "invalid scheme",
+			url:     "file:///etc/passwd",
+			wantErr: ErrInvalidURL,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewGuard(tt.allowed...).CheckURL(context.Background(), tt.url)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("CheckURL() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckURL() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGuard_Client(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if _, err := NewGuard().Client(time.Second).Get(server.URL); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Get() error = %v, want %v", err, ErrForbiddenAddress)
+	}
+
+	client := NewGuard("127.0.0.1").Client(time.Second)
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Errorf("Get() error = %v", err)
+		return
+	}
+	resp.Body.Close()
+
+	if _, err := client.Get(server.URL + "/redirect"); !errors.Is(err, ErrRedirect) {
+		t.Errorf("Get() error = %v, want %v", err, ErrRedirect)
+	}
+}
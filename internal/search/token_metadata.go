@@ -11,7 +11,7 @@ import (
 type TokenResponse struct {
 	Name      string                 `json:"name"`
 	Symbol    string                 `json:"symbol"`
-	TokenID   int64                  `json:"token_id"`
+	TokenID   string                 `json:"token_id"`
 	Network   string                 `json:"network"`
 	Address   string                 `json:"address"`
 	Level     int64                  `json:"level"`
@@ -0,0 +1,507 @@
+package storagetest
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/config"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/bigmapdiff"
+	"github.com/baking-bad/bcdhub/internal/models/block"
+	"github.com/baking-bad/bcdhub/internal/models/contract"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
+	"github.com/baking-bad/bcdhub/internal/models/transfer"
+)
+
+// Run - runs conformance suite against repositories of `ctx`. Every storage backend has to pass it.
+// Suite writes documents of unique network so it can be run against non-empty storage.
+func Run(t *testing.T, ctx *config.Context) {
+	if err := ctx.Storage.CreateIndexes(); err != nil {
+		t.Fatalf("CreateIndexes() error = %v", err)
+	}
+
+	s := &suite{
+		ctx:     ctx,
+		network: fmt.Sprintf("conformance_%d", time.Now().UnixNano()),
+	}
+	defer s.cleanup(t)
+
+	t.Run("Blocks", s.testBlocks)
+	t.Run("GeneralRepository", s.testGeneral)
+	t.Run("TokenBalances", s.testTokenBalances)
+	t.Run("DeleteByContract", s.testDeleteByContract)
+	t.Run("OperationsByContract", s.testOperationsByContract)
+	t.Run("ManagerReveals", s.testManagerReveals)
+	t.Run("OperationsState", s.testOperationsState)
+	t.Run("Transfers", s.testTransfers)
+	t.Run("ContractsPage", s.testContractsPage)
+	t.Run("BigMapDiffsByPtr", s.testBigMapDiffsByPtr)
+}
+
+type suite struct {
+	ctx     *config.Context
+	network string
+}
+
+func (s *suite) cleanup(t *testing.T) {
+	if err := s.ctx.Storage.DeleteByLevelAndNetwork(models.AllDocuments(), s.network, -1); err != nil {
+		t.Errorf("cleanup error = %v", err)
+	}
+}
+
+func (s *suite) newBlock(level int64) *block.Block {
+	return &block.Block{
+		ID:        fmt.Sprintf("%s_%d", s.network, level),
+		Network:   s.network,
+		Level:     level,
+		Hash:      fmt.Sprintf("block_%d", level),
+		ChainID:   "NetXdQprcVkpaWU",
+		Protocol:  "PsDELPH1Kxsxt1V4xm9rTkTGQNbsUb4HR8HpNMvqmRe9KM7hS4",
+		Timestamp: time.Date(2021, 1, 1, 0, 0, int(level), 0, time.UTC),
+	}
+}
+
+func (s *suite) insertBlocks(t *testing.T, levels ...int64) []*block.Block {
+	blocks := make([]*block.Block, len(levels))
+	items := make([]models.Model, len(levels))
+	for i := range levels {
+		blocks[i] = s.newBlock(levels[i])
+		items[i] = blocks[i]
+	}
+	if err := s.ctx.Storage.BulkInsert(items); err != nil {
+		t.Fatalf("BulkInsert() error = %v", err)
+	}
+	return blocks
+}
+
+func (s *suite) testBlocks(t *testing.T) {
+	last, err := s.ctx.Blocks.Last(s.network)
+	if err != nil {
+		t.Fatalf("Last() on empty network error = %v", err)
+	}
+	if last.Level != 0 || last.Network != s.network {
+		t.Errorf("Last() on empty network = %v", last)
+	}
+
+	blocks := s.insertBlocks(t, 1, 2, 3)
+
+	last, err = s.ctx.Blocks.Last(s.network)
+	if err != nil {
+		t.Fatalf("Last() error = %v", err)
+	}
+	if last.Level != 3 || last.Hash != blocks[2].Hash {
+		t.Errorf("Last() = %v, want level 3", last)
+	}
+	if !last.Timestamp.Equal(blocks[2].Timestamp) {
+		t.Errorf("Last() timestamp = %v, want %v", last.Timestamp, blocks[2].Timestamp)
+	}
+
+	b, err := s.ctx.Blocks.Get(s.network, 2)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if b.Hash != blocks[1].Hash {
+		t.Errorf("Get() hash = %s, want %s", b.Hash, blocks[1].Hash)
+	}
+
+	if _, err := s.ctx.Blocks.Get(s.network, 100); !s.ctx.Storage.IsRecordNotFound(err) {
+		t.Errorf("Get() of unknown level error = %v, want record not found", err)
+	}
+}
+
+func (s *suite) testGeneral(t *testing.T) {
+	b := block.Block{ID: fmt.Sprintf("%s_%d", s.network, 1)}
+	if err := s.ctx.Storage.GetByID(&b); err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if b.Level != 1 || b.Network != s.network {
+		t.Errorf("GetByID() = %v", b)
+	}
+
+	unknown := block.Block{ID: fmt.Sprintf("%s_unknown", s.network)}
+	if err := s.ctx.Storage.GetByID(&unknown); !s.ctx.Storage.IsRecordNotFound(err) {
+		t.Errorf("GetByID() of unknown document error = %v, want record not found", err)
+	}
+
+	var blocks []block.Block
+	if err := s.ctx.Storage.GetByNetwork(s.network, &blocks); err != nil {
+		t.Fatalf("GetByNetwork() error = %v", err)
+	}
+	if len(blocks) != 3 {
+		t.Errorf("GetByNetwork() returned %d blocks, want 3", len(blocks))
+	}
+
+	b.Hash = "updated"
+	if err := s.ctx.Storage.BulkUpdate([]models.Model{&b}); err != nil {
+		t.Fatalf("BulkUpdate() error = %v", err)
+	}
+	updated := block.Block{ID: b.ID}
+	if err := s.ctx.Storage.GetByID(&updated); err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if updated.Hash != "updated" {
+		t.Errorf("BulkUpdate() hash = %s, want updated", updated.Hash)
+	}
+
+	if err := s.ctx.Storage.DeleteByLevelAndNetwork([]string{models.DocBlocks}, s.network, 2); err != nil {
+		t.Fatalf("DeleteByLevelAndNetwork() error = %v", err)
+	}
+	s.checkDeleted(t, s.newBlock(3))
+	s.checkExists(t, s.newBlock(2))
+
+	if err := s.ctx.Storage.BulkDelete([]models.Model{s.newBlock(2)}); err != nil {
+		t.Fatalf("BulkDelete() error = %v", err)
+	}
+	s.checkDeleted(t, s.newBlock(2))
+	s.checkExists(t, s.newBlock(1))
+}
+
+func (s *suite) testTokenBalances(t *testing.T) {
+	contract := "KT1conformance"
+	newBalance := func(value int64) *tokenbalance.TokenBalance {
+		return &tokenbalance.TokenBalance{
+			Network:  s.network,
+			Address:  "tz1conformance",
+			Contract: contract,
+			TokenID:  "0",
+			Value:    big.NewInt(value),
+		}
+	}
+
+	if err := s.ctx.TokenBalances.Update([]*tokenbalance.TokenBalance{newBalance(100)}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := s.ctx.TokenBalances.Update([]*tokenbalance.TokenBalance{newBalance(-30)}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	holders, err := s.ctx.TokenBalances.GetHolders(s.network, contract, "0")
+	if err != nil {
+		t.Fatalf("GetHolders() error = %v", err)
+	}
+	if len(holders) != 1 {
+		t.Fatalf("GetHolders() returned %d holders, want 1", len(holders))
+	}
+	if holders[0].Balance != "70" {
+		t.Errorf("GetHolders() balance = %s, want 70", holders[0].Balance)
+	}
+}
+
+func (s *suite) testDeleteByContract(t *testing.T) {
+	transfers := []*transfer.Transfer{
+		{ID: fmt.Sprintf("%s_transfer_1", s.network), Network: s.network, Contract: "KT1first", Level: 1, AmountBigInt: big.NewInt(1)},
+		{ID: fmt.Sprintf("%s_transfer_2", s.network), Network: s.network, Contract: "KT1second", Level: 1, AmountBigInt: big.NewInt(1)},
+	}
+	calls := []*operation.Operation{
+		{ID: fmt.Sprintf("%s_call_1", s.network), Network: s.network, Source: "KT1second", Destination: "KT1first", Level: 1},
+		{ID: fmt.Sprintf("%s_call_2", s.network), Network: s.network, Source: "KT1first", Destination: "KT1second", Level: 1},
+		{ID: fmt.Sprintf("%s_call_3", s.network), Network: s.network, Source: "KT1second", Destination: "KT1third", Level: 1},
+	}
+	diffs := []*bigmapdiff.BigMapDiff{
+		{ID: fmt.Sprintf("%s_diff_1", s.network), Network: s.network, Address: "KT1first", Ptr: 1, Level: 1},
+		{ID: fmt.Sprintf("%s_diff_2", s.network), Network: s.network, Address: "KT1second", Ptr: 2, Level: 1},
+	}
+	if err := s.ctx.Storage.BulkInsert([]models.Model{transfers[0], transfers[1], calls[0], calls[1], calls[2], diffs[0], diffs[1]}); err != nil {
+		t.Fatalf("BulkInsert() error = %v", err)
+	}
+
+	if err := s.ctx.Storage.DeleteByContract([]string{models.DocTransfers, models.DocOperations, models.DocBigMapDiff}, s.network, "KT1first"); err != nil {
+		t.Fatalf("DeleteByContract() error = %v", err)
+	}
+	s.checkDeleted(t, transfers[0])
+	s.checkExists(t, transfers[1])
+	s.checkDeleted(t, calls[0])
+	s.checkDeleted(t, calls[1])
+	s.checkExists(t, calls[2])
+	s.checkDeleted(t, diffs[0])
+	s.checkExists(t, diffs[1])
+}
+
+func (s *suite) testOperationsByContract(t *testing.T) {
+	address := "KT1operations"
+	nonce := int64(0)
+	operations := []*operation.Operation{
+		{Hash: "opg_1", Counter: 10, Level: 1, IndexedTime: 10, Source: "tz1sender", Destination: address, Kind: "transaction", Status: "applied"},
+		{Hash: "opg_2", Counter: 20, Level: 2, IndexedTime: 20, Source: "tz1sender", Destination: address, Kind: "transaction", Status: "applied"},
+		{Hash: "opg_3", Counter: 30, Level: 3, IndexedTime: 30, Source: "tz1sender", Destination: address, Kind: "transaction", Status: "applied"},
+		{Hash: "opg_3", Counter: 30, Level: 3, IndexedTime: 31, Source: address, Destination: "tz1receiver", Kind: "transaction", Status: "applied", Internal: true, Nonce: &nonce},
+		{Hash: "opg_4", Counter: 40, Level: 4, IndexedTime: 40, Source: "tz1sender", Destination: address, Kind: "transaction", Status: "failed"},
+	}
+	items := make([]models.Model, len(operations))
+	for i := range operations {
+		operations[i].ID = fmt.Sprintf("%s_operation_%d", s.network, i)
+		operations[i].Network = s.network
+		operations[i].Timestamp = time.Date(2021, 1, 1, 0, 0, int(operations[i].Level), 0, time.UTC)
+		items[i] = operations[i]
+	}
+	if err := s.ctx.Storage.BulkInsert(items); err != nil {
+		t.Fatalf("BulkInsert() error = %v", err)
+	}
+
+	filters := map[string]interface{}{"status": "'applied'"}
+	page, err := s.ctx.Operations.GetByContract(s.network, address, 2, filters)
+	if err != nil {
+		t.Fatalf("GetByContract() error = %v", err)
+	}
+	checkOperations(t, page.Operations, operations[2].IndexedTime, operations[3].IndexedTime, operations[1].IndexedTime)
+	if page.LastID != "20" {
+		t.Errorf("GetByContract() last id = %s, want 20", page.LastID)
+	}
+
+	filters["last_id"] = page.LastID
+	page, err = s.ctx.Operations.GetByContract(s.network, address, 2, filters)
+	if err != nil {
+		t.Fatalf("GetByContract() error = %v", err)
+	}
+	checkOperations(t, page.Operations, operations[0].IndexedTime)
+
+	levels, err := s.ctx.Operations.GetContractLevels(s.network, address)
+	if err != nil {
+		t.Fatalf("GetContractLevels() error = %v", err)
+	}
+	if want := []int64{1, 2, 3, 4}; !reflect.DeepEqual(levels, want) {
+		t.Errorf("GetContractLevels() = %v, want %v", levels, want)
+	}
+}
+
+func (s *suite) testManagerReveals(t *testing.T) {
+	address := "KT1managed"
+	operations := []*operation.Operation{
+		{Hash: "opg_managed_1", Counter: 10, Level: 1, IndexedTime: 10, Source: "tz1manager", Destination: address, Kind: "origination", Status: "applied"},
+		{Hash: "opg_managed_2", Counter: 20, Level: 2, IndexedTime: 20, Source: "tz1manager", Kind: "reveal", Status: "applied"},
+		{Hash: "opg_managed_3", Counter: 21, Level: 2, IndexedTime: 21, Source: "tz1stranger", Kind: "reveal", Status: "applied"},
+		{Hash: "opg_managed_4", Counter: 30, Level: 3, IndexedTime: 30, Source: "tz1manager", Destination: address, Kind: "transaction", Status: "applied"},
+	}
+	items := make([]models.Model, len(operations))
+	for i := range operations {
+		operations[i].ID = fmt.Sprintf("%s_managed_%d", s.network, i)
+		operations[i].Network = s.network
+		operations[i].Timestamp = time.Date(2021, 1, 1, 0, 0, int(operations[i].Level), 0, time.UTC)
+		items[i] = operations[i]
+	}
+	if err := s.ctx.Storage.BulkInsert(items); err != nil {
+		t.Fatalf("BulkInsert() error = %v", err)
+	}
+
+	page, err := s.ctx.Operations.GetByContract(s.network, address, 0, map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("GetByContract() error = %v", err)
+	}
+	checkOperations(t, page.Operations, operations[3].IndexedTime, operations[0].IndexedTime)
+
+	filters := map[string]interface{}{"manager": "tz1manager"}
+	page, err = s.ctx.Operations.GetByContract(s.network, address, 2, filters)
+	if err != nil {
+		t.Fatalf("GetByContract() error = %v", err)
+	}
+	checkOperations(t, page.Operations, operations[3].IndexedTime, operations[1].IndexedTime)
+
+	filters["last_id"] = page.LastID
+	page, err = s.ctx.Operations.GetByContract(s.network, address, 2, filters)
+	if err != nil {
+		t.Fatalf("GetByContract() error = %v", err)
+	}
+	checkOperations(t, page.Operations, operations[0].IndexedTime)
+
+	page, err = s.ctx.Operations.GetByContract(s.network, address, 0, map[string]interface{}{"manager": "tz1manager", "kind": "'transaction'"})
+	if err != nil {
+		t.Fatalf("GetByContract() error = %v", err)
+	}
+	checkOperations(t, page.Operations, operations[3].IndexedTime)
+}
+
+func (s *suite) testOperationsState(t *testing.T) {
+	address := "KT1state"
+	operations := []*operation.Operation{
+		{Level: 1, IndexedTime: 100, Source: "tz1sender", Destination: address, Amount: 100, DeffatedStorage: `{"int":"1"}`, Kind: "origination", Status: "applied"},
+		{Level: 2, IndexedTime: 200, Source: "tz1sender", Destination: address, Amount: 50, DeffatedStorage: `{"int":"2"}`, Kind: "transaction", Status: "applied"},
+		{Level: 2, IndexedTime: 201, Source: address, Destination: "tz1receiver", Amount: 30, Kind: "transaction", Status: "applied"},
+		{Level: 2, IndexedTime: 202, Source: address, Destination: address, Amount: 70, Kind: "transaction", Status: "applied"},
+		{Level: 3, IndexedTime: 300, Source: "tz1sender", Destination: address, Amount: 1000, DeffatedStorage: `{"int":"3"}`, Kind: "transaction", Status: "failed"},
+		{Level: 4, IndexedTime: 400, Source: "tz1sender", Destination: address, Amount: 10, DeffatedStorage: `{"int":"4"}`, Kind: "transaction", Status: "applied"},
+	}
+	items := make([]models.Model, len(operations))
+	for i := range operations {
+		operations[i].ID = fmt.Sprintf("%s_state_%d", s.network, i)
+		operations[i].Network = s.network
+		operations[i].Hash = fmt.Sprintf("opg_state_%d", i)
+		operations[i].Timestamp = time.Date(2021, 1, 1, 0, 0, int(operations[i].Level), 0, time.UTC)
+		items[i] = operations[i]
+	}
+	if err := s.ctx.Storage.BulkInsert(items); err != nil {
+		t.Fatalf("BulkInsert() error = %v", err)
+	}
+
+	op, err := s.ctx.Operations.LastByLevel(s.network, address, 3)
+	if err != nil {
+		t.Fatalf("LastByLevel() error = %v", err)
+	}
+	if op.DeffatedStorage != `{"int":"2"}` {
+		t.Errorf("LastByLevel() storage = %s, want storage of level 2", op.DeffatedStorage)
+	}
+	if _, err := s.ctx.Operations.LastByLevel(s.network, address, 0); !s.ctx.Storage.IsRecordNotFound(err) {
+		t.Errorf("LastByLevel() before origination error = %v, want record not found", err)
+	}
+
+	balance, err := s.ctx.Operations.GetBalance(s.network, address, 3)
+	if err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+	if balance != 120 {
+		t.Errorf("GetBalance() = %d, want 120", balance)
+	}
+}
+
+func checkOperations(t *testing.T, operations []operation.Operation, indexedTimes ...int64) {
+	if len(operations) != len(indexedTimes) {
+		t.Errorf("GetByContract() returned %d operations, want %d", len(operations), len(indexedTimes))
+		return
+	}
+	for i := range operations {
+		if operations[i].IndexedTime != indexedTimes[i] {
+			t.Errorf("GetByContract() operation %d indexed time = %d, want %d", i, operations[i].IndexedTime, indexedTimes[i])
+		}
+	}
+}
+
+func (s *suite) testTransfers(t *testing.T) {
+	contract := "KT1transfers"
+	transfers := make([]*transfer.Transfer, 4)
+	items := make([]models.Model, len(transfers))
+	for i := range transfers {
+		transfers[i] = &transfer.Transfer{
+			ID:           fmt.Sprintf("%s_transfers_%d", s.network, i),
+			Network:      s.network,
+			Contract:     contract,
+			Hash:         fmt.Sprintf("transfer_%d", i),
+			Status:       "applied",
+			From:         "tz1from",
+			To:           "tz1to",
+			Level:        int64(i + 1),
+			IndexedTime:  int64(i + 1),
+			Timestamp:    time.Date(2021, 1, 1, 0, 0, i+1, 0, time.UTC),
+			TokenID:      strconv.Itoa(i % 2),
+			AmountBigInt: big.NewInt(1),
+		}
+		items[i] = transfers[i]
+	}
+	transfers[3].To = "tz1other"
+	if err := s.ctx.Storage.BulkInsert(items); err != nil {
+		t.Fatalf("BulkInsert() error = %v", err)
+	}
+
+	page, err := s.ctx.Transfers.Get(transfer.GetContext{
+		Network:   s.network,
+		Address:   "tz1to",
+		Size:      2,
+		Start:     uint(transfers[0].Timestamp.Unix() * 1000),
+		End:       uint(transfers[3].Timestamp.Unix() * 1000),
+		Contracts: []string{contract},
+	})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if page.Total != 3 {
+		t.Errorf("Get() total = %d, want 3", page.Total)
+	}
+	if len(page.Transfers) != 2 || page.Transfers[0].Hash != "transfer_2" || page.Transfers[1].Hash != "transfer_1" {
+		t.Errorf("Get() returned unexpected transfers: %v", page.Transfers)
+	}
+	if page.LastID != "2" {
+		t.Errorf("Get() last id = %s, want 2", page.LastID)
+	}
+
+	page, err = s.ctx.Transfers.Get(transfer.GetContext{
+		Network:   s.network,
+		TokenID:   "1",
+		SortOrder: "asc",
+		LastID:    "2",
+	})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(page.Transfers) != 1 || page.Transfers[0].Hash != "transfer_3" {
+		t.Errorf("Get() returned unexpected transfers: %v", page.Transfers)
+	}
+}
+
+func (s *suite) testContractsPage(t *testing.T) {
+	addresses := []string{"KT1third", "KT1first", "KT1second"}
+	items := make([]models.Model, len(addresses))
+	for i := range addresses {
+		items[i] = &contract.Contract{Network: s.network, Address: addresses[i], Level: 1}
+	}
+	if err := s.ctx.Storage.BulkInsert(items); err != nil {
+		t.Fatalf("BulkInsert() error = %v", err)
+	}
+
+	var lastAddress string
+	got := make([]string, 0)
+	for {
+		page, err := s.ctx.Contracts.GetPage(s.network, lastAddress, 2)
+		if err != nil {
+			t.Fatalf("GetPage() error = %v", err)
+		}
+		if len(page) > 2 {
+			t.Fatalf("GetPage() returned %d contracts, want at most 2", len(page))
+		}
+		if len(page) == 0 {
+			break
+		}
+		for i := range page {
+			got = append(got, page[i].Address)
+		}
+		lastAddress = page[len(page)-1].Address
+	}
+
+	want := []string{"KT1first", "KT1second", "KT1third"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetPage() addresses = %v, want %v", got, want)
+	}
+}
+
+func (s *suite) testBigMapDiffsByPtr(t *testing.T) {
+	address := "KT1bigmap"
+	diffs := []models.Model{
+		&bigmapdiff.BigMapDiff{ID: fmt.Sprintf("%s_bmd_1", s.network), Network: s.network, Address: address, Ptr: 10, KeyHash: "key_a", Level: 1, IndexedTime: 1},
+		&bigmapdiff.BigMapDiff{ID: fmt.Sprintf("%s_bmd_2", s.network), Network: s.network, Address: address, Ptr: 10, KeyHash: "key_a", Level: 2, IndexedTime: 2},
+		&bigmapdiff.BigMapDiff{ID: fmt.Sprintf("%s_bmd_3", s.network), Network: s.network, Address: address, Ptr: 10, KeyHash: "key_b", Level: 2, IndexedTime: 3},
+		&bigmapdiff.BigMapDiff{ID: fmt.Sprintf("%s_bmd_4", s.network), Network: s.network, Address: address, Ptr: 11, KeyHash: "key_a", Level: 2, IndexedTime: 4},
+	}
+	if err := s.ctx.Storage.BulkInsert(diffs); err != nil {
+		t.Fatalf("BulkInsert() error = %v", err)
+	}
+
+	result, err := s.ctx.BigMapDiffs.GetByPtr(address, s.network, 10)
+	if err != nil {
+		t.Fatalf("GetByPtr() error = %v", err)
+	}
+	got := make(map[string]int64)
+	for i := range result {
+		if _, ok := got[result[i].KeyHash]; ok {
+			t.Errorf("GetByPtr() returned key %s twice", result[i].KeyHash)
+		}
+		got[result[i].KeyHash] = result[i].IndexedTime
+	}
+	want := map[string]int64{"key_a": 2, "key_b": 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetByPtr() latest diffs = %v, want %v", got, want)
+	}
+}
+
+func (s *suite) checkDeleted(t *testing.T, model models.Model) {
+	if err := s.ctx.Storage.GetByID(model); !s.ctx.Storage.IsRecordNotFound(err) {
+		t.Errorf("document %s of %s was not deleted: %v", model.GetID(), model.GetIndex(), err)
+	}
+}
+
+func (s *suite) checkExists(t *testing.T, model models.Model) {
+	if err := s.ctx.Storage.GetByID(model); err != nil {
+		t.Errorf("document %s of %s is not found: %v", model.GetID(), model.GetIndex(), err)
+	}
+}
@@ -0,0 +1,52 @@
+package storagetest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/baking-bad/bcdhub/internal/config"
+)
+
+func TestElastic(t *testing.T) {
+	uri := os.Getenv("BCD_TEST_ELASTIC_URI")
+	if uri == "" {
+		t.Skip("BCD_TEST_ELASTIC_URI is not set")
+	}
+
+	Run(t, config.NewContext(
+		config.WithStorage(config.StorageConfig{
+			Kind:    config.StorageKindElastic,
+			URI:     []string{uri},
+			Timeout: 10,
+		}),
+	))
+}
+
+func TestReindexer(t *testing.T) {
+	uri := os.Getenv("BCD_TEST_REINDEXER_URI")
+	if uri == "" {
+		t.Skip("BCD_TEST_REINDEXER_URI is not set")
+	}
+
+	Run(t, config.NewContext(
+		config.WithStorage(config.StorageConfig{
+			Kind: config.StorageKindReindexer,
+			URI:  []string{uri},
+		}),
+	))
+}
+
+func TestPostgres(t *testing.T) {
+	uri := os.Getenv("BCD_TEST_POSTGRES_URI")
+	if uri == "" {
+		t.Skip("BCD_TEST_POSTGRES_URI is not set")
+	}
+
+	Run(t, config.NewContext(
+		config.WithStorage(config.StorageConfig{
+			Kind:    config.StorageKindPostgres,
+			URI:     []string{uri},
+			Timeout: 10,
+		}),
+	))
+}
@@ -0,0 +1,176 @@
+package views
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/models/tzip"
+	"github.com/baking-bad/bcdhub/internal/safehttp"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultRestTimeout      = 10 * time.Second
+	defaultRestResponseSize = 1 << 20
+)
+
+// errors
+var (
+	ErrRestReturn    = errors.New(`REST API return error`)
+	ErrInvalidMethod = errors.New(`Invalid REST API method`)
+)
+
+// RestAPIQueryView -
+type RestAPIQueryView struct {
+	BaseURI string
+	Path    string
+	Method  string
+	Name    string
+}
+
+// NewRestAPIQueryView -
+func NewRestAPIQueryView(impl tzip.ViewImplementation, name string) *RestAPIQueryView {
+	method := strings.ToUpper(impl.RestAPIQuery.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	return &RestAPIQueryView{
+		BaseURI: impl.RestAPIQuery.BaseURI,
+		Path:    impl.RestAPIQuery.Path,
+		Method:  method,
+		Name:    name,
+	}
+}
+
+// RestClient - HTTP client which calls REST API views. Requests to private addresses and redirects are refused.
+type RestClient struct {
+	client       *http.Client
+	guard        *safehttp.Guard
+	timeout      time.Duration
+	responseSize int64
+}
+
+// RestClientOption -
+type RestClientOption func(*RestClient)
+
+// WithRestTimeout - sets timeout of REST API view call
+func WithRestTimeout(timeout time.Duration) RestClientOption {
+	return func(rc *RestClient) {
+		if timeout > 0 {
+			rc.timeout = timeout
+		}
+	}
+}
+
+// WithRestResponseSize - sets max size of REST API view response in bytes
+func WithRestResponseSize(size int64) RestClientOption {
+	return func(rc *RestClient) {
+		if size > 0 {
+			rc.responseSize = size
+		}
+	}
+}
+
+// WithRestAllowedHosts - sets hosts which may be called even if they are resolved to private addresses
+func WithRestAllowedHosts(hosts []string) RestClientOption {
+	return func(rc *RestClient) {
+		rc.guard = safehttp.NewGuard(hosts...)
+	}
+}
+
+// NewRestClient -
+func NewRestClient(opts ...RestClientOption) *RestClient {
+	rc := &RestClient{
+		guard:        safehttp.NewGuard(),
+		timeout:      defaultRestTimeout,
+		responseSize: defaultRestResponseSize,
+	}
+	for i := range opts {
+		opts[i](rc)
+	}
+	rc.client = rc.guard.Client(rc.timeout)
+	return rc
+}
+
+// Execute - calls REST API view. Path parameters (e.g. `/balance/{owner}`) are taken from `args`,
+// the rest of `args` is sent as query string for GET requests or as JSON body otherwise.
+func (rc *RestClient) Execute(view *RestAPIQueryView, args map[string]interface{}) (json.RawMessage, error) {
+	req, err := rc.newRequest(view, args)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := rc.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, rc.responseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > rc.responseSize {
+		return nil, errors.Wrapf(ErrRestReturn, "response of view %s is too large", view.Name)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Wrapf(ErrRestReturn, "%s: %s", resp.Status, data)
+	}
+	if !json.Valid(data) {
+		return nil, errors.Wrapf(ErrRestReturn, "invalid JSON response of view %s", view.Name)
+	}
+	return data, nil
+}
+
+func (rc *RestClient) newRequest(view *RestAPIQueryView, args map[string]interface{}) (*http.Request, error) {
+	if err := rc.guard.CheckURL(context.Background(), view.BaseURI); err != nil {
+		return nil, errors.Wrap(err, "Invalid base URI of REST API view")
+	}
+	base, err := url.Parse(view.BaseURI)
+	if err != nil {
+		return nil, err
+	}
+
+	query := make(map[string]interface{}, len(args))
+	path := view.Path
+	for name, value := range args {
+		placeholder := fmt.Sprintf("{%s}", name)
+		if strings.Contains(path, placeholder) {
+			path = strings.ReplaceAll(path, placeholder, url.PathEscape(fmt.Sprint(value)))
+		} else {
+			query[name] = value
+		}
+	}
+	base.Path = strings.TrimSuffix(base.Path, "/") + "/" + strings.TrimPrefix(path, "/")
+
+	switch view.Method {
+	case http.MethodGet:
+		values := base.Query()
+		for name, value := range query {
+			values.Set(name, fmt.Sprint(value))
+		}
+		base.RawQuery = values.Encode()
+		return http.NewRequest(view.Method, base.String(), nil)
+	case http.MethodPost, http.MethodPut:
+		body, err := json.Marshal(query)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequest(view.Method, base.String(), bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	default:
+		return nil, errors.Wrap(ErrInvalidMethod, view.Method)
+	}
+}
@@ -0,0 +1,91 @@
+package views
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baking-bad/bcdhub/internal/models/tzip"
+	"github.com/baking-bad/bcdhub/internal/safehttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestClient_Execute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/balance/tz1owner":
+			w.Write([]byte(`{"token":"` + r.URL.Query().Get("token_id") + `"}`)) //nolint
+		case "/api/total":
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Write(body) //nolint
+		case "/api/large":
+			w.Write([]byte(`"01234567890123456789"`)) //nolint
+		case "/api/redirect":
+			http.Redirect(w, r, "/api/total", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		query   tzip.RestAPIQuery
+		args    map[string]interface{}
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "GET with path parameter",
+			query: tzip.RestAPIQuery{SpecificationURI: "https://example.com/spec.json", BaseURI: server.URL + "/api/", Path: "/balance/{owner}"},
+			args:  map[string]interface{}{"owner": "tz1owner", "token_id": 1},
+			want:  `{"token":"1"}`,
+		}, {
+			name:  "POST",
+			query: tzip.RestAPIQuery{SpecificationURI: "https://example.com/spec.json", BaseURI: server.URL + "/api", Path: "/total", Method: "post"},
+			args:  map[string]interface{}{"token_id": 1},
+			want:  `{"token_id":1}`,
+		}, {
+			name:    "not found",
+			query:   tzip.RestAPIQuery{SpecificationURI: "https://example.com/spec.json", BaseURI: server.URL, Path: "/unknown"},
+			wantErr: ErrRestReturn,
+		}, {
+			name:    "too large response",
+			query:   tzip.RestAPIQuery{SpecificationURI: "https://example.com/spec.json", BaseURI: server.URL + "/api", Path: "/large"},
+			wantErr: ErrRestReturn,
+		}, {
+			name:    "redirect",
+			query:   tzip.RestAPIQuery{SpecificationURI: "https://example.com/spec.json", BaseURI: server.URL + "/api", Path: "/redirect"},
+			wantErr: safehttp.ErrRedirect,
+		}, {
+			name:    "private address",
+			query:   tzip.RestAPIQuery{SpecificationURI: "https://example.com/spec.json", BaseURI: "http://10.0.0.1/api", Path: "/total"},
+			wantErr: safehttp.ErrForbiddenAddress,
+		}, {
+			name:    "invalid method",
+			query:   tzip.RestAPIQuery{SpecificationURI: "https://example.com/spec.json", BaseURI: server.URL, Path: "/total", Method: "DELETE"},
+			wantErr: ErrInvalidMethod,
+		},
+	}
+
+	client := NewRestClient(WithRestResponseSize(16), WithRestAllowedHosts([]string{"127.0.0.1"}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := NewRestAPIQueryView(tzip.ViewImplementation{RestAPIQuery: tt.query}, "view")
+			got, err := client.Execute(view, tt.args)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Execute() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Execute() error = %v", err)
+				return
+			}
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
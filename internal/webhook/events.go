@@ -0,0 +1,161 @@
+package webhook
+
+import (
+	"strings"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/cerrors"
+	"github.com/baking-bad/bcdhub/internal/contractparser/consts"
+	"github.com/baking-bad/bcdhub/internal/database"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/contract"
+	"github.com/baking-bad/bcdhub/internal/models/migration"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+)
+
+// Operation - event body of operation. It has the same fields as operation event of `/profile/subscriptions/events`.
+type Operation struct {
+	Network          string    `json:"network"`
+	Hash             string    `json:"hash"`
+	Level            int64     `json:"level"`
+	Internal         bool      `json:"internal"`
+	Status           string    `json:"status"`
+	Timestamp        time.Time `json:"timestamp"`
+	Kind             string    `json:"kind"`
+	Fee              int64     `json:"fee,omitempty"`
+	Amount           int64     `json:"amount,omitempty"`
+	Entrypoint       string    `json:"entrypoint,omitempty"`
+	Source           string    `json:"source"`
+	SourceAlias      string    `json:"source_alias,omitempty"`
+	Destination      string    `json:"destination,omitempty"`
+	DestinationAlias string    `json:"destination_alias,omitempty"`
+
+	Errors []*cerrors.Error `json:"errors,omitempty"`
+	Burned int64            `json:"burned,omitempty"`
+}
+
+// Contract - event body of same or similar contract
+type Contract struct {
+	Network   string    `json:"network"`
+	Address   string    `json:"address"`
+	Hash      string    `json:"hash"`
+	ProjectID string    `json:"project_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// OperationEvent - returns event of `op` for subscription if subscription watches it
+func OperationEvent(sub database.Subscription, op operation.Operation) (models.Event, bool) {
+	if sub.Network != op.Network {
+		return models.Event{}, false
+	}
+
+	address := op.Destination
+	if strings.HasPrefix(sub.Address, "tz") {
+		address = op.Source
+	}
+
+	var eventType string
+	switch {
+	case op.Status != consts.Applied:
+		if sub.WatchMask&database.WatchErrors == 0 || address != sub.Address {
+			return models.Event{}, false
+		}
+		eventType = models.EventTypeError
+	case op.Kind == consts.Origination:
+		if sub.WatchMask&database.WatchDeployments == 0 || op.Source != sub.Address {
+			return models.Event{}, false
+		}
+		eventType = models.EventTypeDeploy
+	case op.Kind == consts.Transaction:
+		if sub.WatchMask&database.WatchCalls == 0 || address != sub.Address {
+			return models.Event{}, false
+		}
+		eventType = models.EventTypeInvoke
+		if op.Source == sub.Address {
+			eventType = models.EventTypeCall
+		}
+	default:
+		return models.Event{}, false
+	}
+
+	return models.Event{
+		Type:    eventType,
+		Network: sub.Network,
+		Address: sub.Address,
+		Alias:   sub.Alias,
+		Body: Operation{
+			Network:          op.Network,
+			Hash:             op.Hash,
+			Level:            op.Level,
+			Internal:         op.Internal,
+			Status:           op.Status,
+			Timestamp:        op.Timestamp,
+			Kind:             op.Kind,
+			Fee:              op.Fee,
+			Amount:           op.Amount,
+			Entrypoint:       op.Entrypoint,
+			Source:           op.Source,
+			SourceAlias:      op.SourceAlias,
+			Destination:      op.Destination,
+			DestinationAlias: op.DestinationAlias,
+			Errors:           op.Errors,
+			Burned:           op.Burned,
+		},
+	}, true
+}
+
+// MigrationEvent - returns event of `m` for subscription if subscription watches it
+func MigrationEvent(sub database.Subscription, m migration.Migration) (models.Event, bool) {
+	if sub.WatchMask&database.WatchMigrations == 0 || sub.Network != m.Network || sub.Address != m.Address {
+		return models.Event{}, false
+	}
+	switch m.Kind {
+	case consts.MigrationBootstrap, consts.MigrationLambda, consts.MigrationUpdate:
+	default:
+		return models.Event{}, false
+	}
+	return models.Event{
+		Type:    models.EventTypeMigration,
+		Network: sub.Network,
+		Address: sub.Address,
+		Alias:   sub.Alias,
+		Body:    m,
+	}, true
+}
+
+// ContractEvent - returns event of new contract `c` for subscription to `subscribed` contract if `c` is the same or similar to it
+func ContractEvent(sub database.Subscription, subscribed, c contract.Contract) (models.Event, bool) {
+	if subscribed.Address == c.Address {
+		return models.Event{}, false
+	}
+
+	var eventType string
+	switch {
+	case subscribed.Hash == c.Hash:
+		if sub.WatchMask&database.WatchSame == 0 {
+			return models.Event{}, false
+		}
+		eventType = models.EventTypeSame
+	case subscribed.ProjectID != "" && subscribed.ProjectID == c.ProjectID:
+		if sub.WatchMask&database.WatchSimilar == 0 {
+			return models.Event{}, false
+		}
+		eventType = models.EventTypeSimilar
+	default:
+		return models.Event{}, false
+	}
+
+	return models.Event{
+		Type:    eventType,
+		Network: sub.Network,
+		Address: sub.Address,
+		Alias:   sub.Alias,
+		Body: Contract{
+			Network:   c.Network,
+			Address:   c.Address,
+			Hash:      c.Hash,
+			ProjectID: c.ProjectID,
+			Timestamp: c.Timestamp,
+		},
+	}, true
+}
@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/baking-bad/bcdhub/internal/database"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/contract"
+	"github.com/baking-bad/bcdhub/internal/models/operation"
+)
+
+func TestOperationEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		sub       database.Subscription
+		op        operation.Operation
+		wantType  string
+		wantMatch bool
+	}{
+		{
+			name:      "invoke of contract",
+			sub:       database.Subscription{Address: "KT1contract", Network: "mainnet", WatchMask: database.WatchCalls},
+			op:        operation.Operation{Network: "mainnet", Kind: "transaction", Status: "applied", Source: "tz1user", Destination: "KT1contract"},
+			wantType:  models.EventTypeInvoke,
+			wantMatch: true,
+		}, {
+			name:      "call of implicit account",
+			sub:       database.Subscription{Address: "tz1user", Network: "mainnet", WatchMask: database.WatchCalls},
+			op:        operation.Operation{Network: "mainnet", Kind: "transaction", Status: "applied", Source: "tz1user", Destination: "KT1contract"},
+			wantType:  models.EventTypeCall,
+			wantMatch: true,
+		}, {
+			name: "calls are not watched",
+			sub:  database.Subscription{Address: "KT1contract", Network: "mainnet", WatchMask: database.WatchErrors},
+			op:   operation.Operation{Network: "mainnet", Kind: "transaction", Status: "applied", Source: "tz1user", Destination: "KT1contract"},
+		}, {
+			name:      "failed call",
+			sub:       database.Subscription{Address: "KT1contract", Network: "mainnet", WatchMask: database.WatchErrors},
+			op:        operation.Operation{Network: "mainnet", Kind: "transaction", Status: "failed", Source: "tz1user", Destination: "KT1contract"},
+			wantType:  models.EventTypeError,
+			wantMatch: true,
+		}, {
+			name: "failed call is not a call",
+			sub:  database.Subscription{Address: "KT1contract", Network: "mainnet", WatchMask: database.WatchCalls},
+			op:   operation.Operation{Network: "mainnet", Kind: "transaction", Status: "failed", Source: "tz1user", Destination: "KT1contract"},
+		}, {
+			name:      "deployment",
+			sub:       database.Subscription{Address: "tz1user", Network: "mainnet", WatchMask: database.WatchDeployments},
+			op:        operation.Operation{Network: "mainnet", Kind: "origination", Status: "applied", Source: "tz1user", Destination: "KT1contract"},
+			wantType:  models.EventTypeDeploy,
+			wantMatch: true,
+		}, {
+			name: "other network",
+			sub:  database.Subscription{Address: "KT1contract", Network: "edo2net", WatchMask: database.WatchCalls},
+			op:   operation.Operation{Network: "mainnet", Kind: "transaction", Status: "applied", Source: "tz1user", Destination: "KT1contract"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, ok := OperationEvent(tt.sub, tt.op)
+			if ok != tt.wantMatch {
+				t.Errorf("OperationEvent() match = %v, want %v", ok, tt.wantMatch)
+				return
+			}
+			if ok && event.Type != tt.wantType {
+				t.Errorf("OperationEvent() type = %v, want %v", event.Type, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestContractEvent(t *testing.T) {
+	subscribed := contract.Contract{Network: "mainnet", Address: "KT1subscribed", Hash: "hash", ProjectID: "project"}
+	tests := []struct {
+		name      string
+		mask      uint
+		c         contract.Contract
+		wantType  string
+		wantMatch bool
+	}{
+		{
+			name:      "same",
+			mask:      database.WatchSame | database.WatchSimilar,
+			c:         contract.Contract{Network: "mainnet", Address: "KT1new", Hash: "hash", ProjectID: "project"},
+			wantType:  models.EventTypeSame,
+			wantMatch: true,
+		}, {
+			name:      "similar",
+			mask:      database.WatchSame | database.WatchSimilar,
+			c:         contract.Contract{Network: "mainnet", Address: "KT1new", Hash: "other", ProjectID: "project"},
+			wantType:  models.EventTypeSimilar,
+			wantMatch: true,
+		}, {
+			name: "similar is not watched",
+			mask: database.WatchSame,
+			c:    contract.Contract{Network: "mainnet", Address: "KT1new", Hash: "other", ProjectID: "project"},
+		}, {
+			name: "subscribed contract itself",
+			mask: database.WatchSame,
+			c:    subscribed,
+		}, {
+			name: "other project",
+			mask: database.WatchSame | database.WatchSimilar,
+			c:    contract.Contract{Network: "mainnet", Address: "KT1new", Hash: "other", ProjectID: "other"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := database.Subscription{Address: subscribed.Address, Network: subscribed.Network, WatchMask: tt.mask}
+			event, ok := ContractEvent(sub, subscribed, tt.c)
+			if ok != tt.wantMatch {
+				t.Errorf("ContractEvent() match = %v, want %v", ok, tt.wantMatch)
+				return
+			}
+			if ok && event.Type != tt.wantType {
+				t.Errorf("ContractEvent() type = %v, want %v", event.Type, tt.wantType)
+			}
+		})
+	}
+}
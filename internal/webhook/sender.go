@@ -0,0 +1,279 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/database"
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/safehttp"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Headers of webhook request
+const (
+	HeaderEvent     = "X-BCD-Event"
+	HeaderDelivery  = "X-BCD-Delivery"
+	HeaderTimestamp = "X-BCD-Timestamp"
+	HeaderSignature = "X-BCD-Signature"
+)
+
+const (
+	defaultTimeout     = 10 * time.Second
+	defaultMaxAttempts = 8
+	defaultBackoff     = 30 * time.Second
+	maxBackoff         = 6 * time.Hour
+	dispatchBatchSize  = 100
+	secretLength       = 32
+)
+
+// Sender - stores events of subscriptions in delivery log and delivers them to webhooks.
+// Deliveries to private addresses are cancelled and redirects are not followed.
+type Sender struct {
+	db          database.IWebhook
+	client      *http.Client
+	guard       *safehttp.Guard
+	timeout     time.Duration
+	maxAttempts uint
+	backoff     time.Duration
+}
+
+// SenderOption -
+type SenderOption func(*Sender)
+
+// WithTimeout -
+func WithTimeout(timeout time.Duration) SenderOption {
+	return func(s *Sender) {
+		if timeout != 0 {
+			s.timeout = timeout
+		}
+	}
+}
+
+// WithMaxAttempts -
+func WithMaxAttempts(maxAttempts uint) SenderOption {
+	return func(s *Sender) {
+		if maxAttempts != 0 {
+			s.maxAttempts = maxAttempts
+		}
+	}
+}
+
+// WithBackoff - sets delay before the second attempt. Every next delay is twice longer.
+func WithBackoff(backoff time.Duration) SenderOption {
+	return func(s *Sender) {
+		if backoff != 0 {
+			s.backoff = backoff
+		}
+	}
+}
+
+// WithAllowedHosts - sets hosts which may receive webhooks even if they are resolved to private addresses
+func WithAllowedHosts(hosts []string) SenderOption {
+	return func(s *Sender) {
+		s.guard = safehttp.NewGuard(hosts...)
+	}
+}
+
+// NewSender -
+func NewSender(db database.IWebhook, opts ...SenderOption) *Sender {
+	s := &Sender{
+		db:          db,
+		guard:       safehttp.NewGuard(),
+		timeout:     defaultTimeout,
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+	}
+
+	for i := range opts {
+		opts[i](s)
+	}
+	s.client = s.guard.Client(s.timeout)
+
+	return s
+}
+
+// Notify - puts `event` to delivery log of `webhooks`. Deliveries are sent by `Dispatch`.
+func (s *Sender) Notify(event models.Event, webhooks ...database.Webhook) error {
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for i := range webhooks {
+		delivery := database.WebhookDelivery{
+			WebhookID:     webhooks[i].ID,
+			Event:         event.Type,
+			Payload:       string(payload),
+			Status:        database.DeliveryPending,
+			NextAttemptAt: &now,
+		}
+		if err := s.db.CreateWebhookDelivery(&delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Dispatch - claims pending deliveries which are due and makes their next attempt
+func (s *Sender) Dispatch() error {
+	// deliveries are sent one by one, so batch is claimed for the time of sending all of them
+	deliveries, err := s.db.ClaimWebhookDeliveries(time.Now().UTC(), s.timeout*dispatchBatchSize, dispatchBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range deliveries {
+		hook, err := s.db.GetWebhook(deliveries[i].WebhookID)
+		switch {
+		case err == nil && hook.Active:
+			if err := s.attempt(hook, &deliveries[i]); err != nil {
+				return err
+			}
+		case err == nil:
+			if err := s.cancel(&deliveries[i], "webhook is disabled"); err != nil {
+				return err
+			}
+		case gorm.IsRecordNotFoundError(err):
+			if err := s.cancel(&deliveries[i], "webhook is deleted"); err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// Run - dispatches deliveries every `period` until `stop` is closed
+func (s *Sender) Run(period time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := s.Dispatch(); err != nil {
+				logger.Errorf("[webhooks] dispatch error: %s", err)
+			}
+		}
+	}
+}
+
+func (s *Sender) attempt(hook database.Webhook, delivery *database.WebhookDelivery) error {
+	delivery.Attempts++
+	code, err := s.post(hook, *delivery)
+	delivery.ResponseCode = code
+
+	now := time.Now().UTC()
+	switch {
+	case errors.Is(err, safehttp.ErrForbiddenAddress) || errors.Is(err, safehttp.ErrInvalidURL) || errors.Is(err, safehttp.ErrRedirect):
+		return s.cancel(delivery, err.Error())
+	case err == nil:
+		delivery.Status = database.DeliveryDelivered
+		delivery.Error = ""
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts >= s.maxAttempts:
+		delivery.Status = database.DeliveryFailed
+		delivery.Error = err.Error()
+		delivery.NextAttemptAt = nil
+	default:
+		delivery.Error = err.Error()
+		next := now.Add(s.delay(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+	}
+	return s.db.UpdateWebhookDelivery(delivery)
+}
+
+func (s *Sender) cancel(delivery *database.WebhookDelivery, reason string) error {
+	delivery.Status = database.DeliveryFailed
+	delivery.Error = reason
+	delivery.NextAttemptAt = nil
+	return s.db.UpdateWebhookDelivery(delivery)
+}
+
+func (s *Sender) post(hook database.Webhook, delivery database.WebhookDelivery) (int, error) {
+	if err := s.guard.CheckURL(context.Background(), hook.URL); err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, fmt.Sprintf("%d", delivery.ID))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(hook.Secret, timestamp, []byte(delivery.Payload)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// body is read to reuse connection
+	if _, err := io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16)); err != nil {
+		return resp.StatusCode, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, errors.Errorf("Invalid status code: %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// delay - returns delay after `attempts` failed attempts
+func (s *Sender) delay(attempts uint) time.Duration {
+	delay := s.backoff
+	for i := uint(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
+
+// Sign - returns hex encoded HMAC-SHA256 of `timestamp.payload` signed by webhook secret.
+// Receiver computes the same value and compares it with `X-BCD-Signature` header.
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret - generates random webhook secret
+func NewSecret() (string, error) {
+	buf := make([]byte, secretLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
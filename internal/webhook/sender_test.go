@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/database"
+	"github.com/golang/mock/gomock"
+)
+
+func TestSender_Dispatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const (
+		secret  = "secret"
+		payload = `{"type":"invoke"}`
+	)
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("ReadAll() error = %v", err)
+		}
+		want := "sha256=" + Sign(secret, r.Header.Get(HeaderTimestamp), body)
+		if got := r.Header.Get(HeaderSignature); got != want {
+			t.Errorf("signature = %s, want %s", got, want)
+		}
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	db := database.NewMockDB(ctrl)
+	hook := database.Webhook{ID: 1, URL: server.URL, Secret: secret, Active: true}
+	delivery := database.WebhookDelivery{ID: 10, WebhookID: 1, Event: "invoke", Payload: payload, Status: database.DeliveryPending}
+
+	db.EXPECT().GetWebhook(uint(1)).Return(hook, nil).Times(2)
+	gomock.InOrder(
+		db.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).Return([]database.WebhookDelivery{delivery}, nil),
+		db.EXPECT().UpdateWebhookDelivery(gomock.Any()).DoAndReturn(func(d *database.WebhookDelivery) error {
+			if d.Status != database.DeliveryPending || d.Attempts != 1 || d.ResponseCode != http.StatusInternalServerError {
+				t.Errorf("first attempt = %+v", d)
+			}
+			if d.NextAttemptAt == nil || time.Until(*d.NextAttemptAt) < 50*time.Second {
+				t.Errorf("next attempt at = %v, want after backoff", d.NextAttemptAt)
+			}
+			delivery = *d
+			return nil
+		}),
+		db.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(time.Time, time.Duration, uint) ([]database.WebhookDelivery, error) {
+			return []database.WebhookDelivery{delivery}, nil
+		}),
+		db.EXPECT().UpdateWebhookDelivery(gomock.Any()).DoAndReturn(func(d *database.WebhookDelivery) error {
+			if d.Status != database.DeliveryDelivered || d.Attempts != 2 || d.DeliveredAt == nil || d.NextAttemptAt != nil {
+				t.Errorf("second attempt = %+v", d)
+			}
+			return nil
+		}),
+	)
+
+	sender := NewSender(db, WithBackoff(time.Minute), WithAllowedHosts([]string{"127.0.0.1"}))
+	for i := 0; i < 2; i++ {
+		if err := sender.Dispatch(); err != nil {
+			t.Errorf("Dispatch() error = %v", err)
+			return
+		}
+	}
+}
+
+func TestSender_delay(t *testing.T) {
+	sender := NewSender(nil, WithBackoff(time.Second))
+	tests := []struct {
+		attempts uint
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{40, maxBackoff},
+	}
+	for _, tt := range tests {
+		if got := sender.delay(tt.attempts); got != tt.want {
+			t.Errorf("delay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestSender_DispatchForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db := database.NewMockDB(ctrl)
+	hook := database.Webhook{ID: 1, URL: "http://169.254.169.254/latest/meta-data", Secret: "secret", Active: true}
+	delivery := database.WebhookDelivery{ID: 10, WebhookID: 1, Event: "invoke", Payload: "{}", Status: database.DeliveryPending}
+
+	db.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).Return([]database.WebhookDelivery{delivery}, nil)
+	db.EXPECT().GetWebhook(uint(1)).Return(hook, nil)
+	db.EXPECT().UpdateWebhookDelivery(gomock.Any()).DoAndReturn(func(d *database.WebhookDelivery) error {
+		if d.Status != database.DeliveryFailed || d.NextAttemptAt != nil {
+			t.Errorf("delivery = %+v, want cancelled", d)
+		}
+		return nil
+	})
+
+	if err := NewSender(db).Dispatch(); err != nil {
+		t.Errorf("Dispatch() error = %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/baking-bad/bcdhub/internal/audit"
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/models/contract"
+	"github.com/baking-bad/bcdhub/internal/mq"
+)
+
+type auditCommand struct {
+	Network   string   `short:"n" long:"network" description:"Network" required:"true"`
+	Addresses []string `short:"a" long:"address" description:"Contract address to audit. Can be set several times"`
+	Sample    int      `short:"s" long:"sample" description:"Count of random contracts to audit. All contracts of network are audited if neither sample nor address is set"`
+	Output    string   `short:"o" long:"output" description:"Path to drift report file. Report is printed to stdout if it's not set"`
+	Recalc    bool     `short:"r" long:"recalc" description:"Send contracts with drifts to recalc queue"`
+}
+
+var auditCmd auditCommand
+
+// Execute
+func (x *auditCommand) Execute(_ []string) error {
+	state, err := ctx.Blocks.Last(x.Network)
+	if err != nil {
+		return err
+	}
+
+	rpc, err := ctx.GetRPC(x.Network)
+	if err != nil {
+		return err
+	}
+
+	contracts, err := x.getContracts()
+	if err != nil {
+		return err
+	}
+	logger.Info("Auditing %d contracts of %s at level %d...", len(contracts), x.Network, state.Level)
+
+	auditor := audit.NewAuditor(ctx.Storage, rpc, ctx.Operations, ctx.BigMapDiffs, ctx.Transfers, ctx.TokenBalances, ctx.Schema)
+	report := auditor.Audit(x.Network, state.Protocol, state.Level, contracts)
+	logger.Info("Checked %d contracts: %d drifts, %d failed", report.Checked, len(report.Drifts), len(report.Failed))
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if x.Output != "" {
+		if err := ioutil.WriteFile(x.Output, data, 0644); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(string(data))
+	}
+
+	if x.Recalc {
+		affected := report.AffectedContracts()
+		logger.Info("Sending %d contracts to recalc queue...", len(affected))
+		for i := range affected {
+			if err := ctx.MQ.SendRaw(mq.QueueRecalc, []byte(affected[i])); err != nil {
+				return err
+			}
+		}
+	}
+
+	logger.Info("Done")
+	return nil
+}
+
+func (x *auditCommand) getContracts() ([]contract.Contract, error) {
+	switch {
+	case len(x.Addresses) > 0:
+		addresses := make([]contract.Address, len(x.Addresses))
+		for i := range x.Addresses {
+			addresses[i] = contract.Address{
+				Address: x.Addresses[i],
+				Network: x.Network,
+			}
+		}
+		return ctx.Contracts.GetByAddresses(addresses)
+	case x.Sample > 0:
+		exists := make(map[string]struct{})
+		contracts := make([]contract.Contract, 0, x.Sample)
+		for attempts := 0; len(contracts) < x.Sample && attempts < x.Sample*3; attempts++ {
+			c, err := ctx.Contracts.GetRandom(x.Network)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := exists[c.Address]; ok {
+				continue
+			}
+			exists[c.Address] = struct{}{}
+			contracts = append(contracts, c)
+		}
+		return contracts, nil
+	default:
+		return ctx.Contracts.GetMany(map[string]interface{}{
+			"network": x.Network,
+		})
+	}
+}
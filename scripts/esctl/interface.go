@@ -0,0 +1,34 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/baking-bad/bcdhub/internal/contractparser/kinds"
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/contractinterface"
+)
+
+type addInterfaceCommand struct {
+	File string `short:"f" long:"file" description:"JSON or YAML file with interface description" required:"true"`
+}
+
+var addInterfaceCmd addInterfaceCommand
+
+// Execute - stores interface to the index. Known contracts are retagged by metrics service.
+func (x *addInterfaceCommand) Execute(_ []string) error {
+	data, err := ioutil.ReadFile(x.File)
+	if err != nil {
+		return err
+	}
+	i, err := kinds.ParseInterface(data, filepath.Ext(x.File))
+	if err != nil {
+		return err
+	}
+	if err := ctx.Storage.BulkInsert([]models.Model{contractinterface.New(i)}); err != nil {
+		return err
+	}
+	logger.Info("Interface %s is added. Contracts will be retagged by metrics service.", i.Name)
+	return nil
+}
@@ -35,6 +35,7 @@ func main() {
 		config.WithConfigCopy(cfg),
 		config.WithRPC(cfg.RPC),
 		config.WithShare(cfg.SharePath),
+		config.WithContractsInterfaces(),
 	)
 
 	parser := flags.NewParser(nil, flags.Default)
@@ -46,6 +47,13 @@ func main() {
 		logger.Fatal(err)
 	}
 
+	if _, err := parser.AddCommand("audit",
+		"Audit index",
+		"Compare indexed contracts state with node state at indexed head",
+		&auditCmd); err != nil {
+		logger.Fatal(err)
+	}
+
 	if _, err := parser.AddCommand("remove",
 		"Remove network data",
 		"Remove full network data from BCD",
@@ -53,6 +61,13 @@ func main() {
 		logger.Fatal(err)
 	}
 
+	if _, err := parser.AddCommand("reindex_contract",
+		"Reindex contract",
+		"Delete contract data and index its operations again",
+		&reindexContractCmd); err != nil {
+		logger.Fatal(err)
+	}
+
 	if _, err := parser.AddCommand("create_repository",
 		"Create repository",
 		"Create repository",
@@ -95,6 +110,13 @@ func main() {
 		logger.Fatal(err)
 	}
 
+	if _, err := parser.AddCommand("add_interface",
+		"Add contract interface",
+		"Add user-defined contract interface from JSON or YAML file",
+		&addInterfaceCmd); err != nil {
+		logger.Fatal(err)
+	}
+
 	if _, err := parser.Parse(); err != nil {
 		panic(err)
 	}
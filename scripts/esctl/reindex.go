@@ -0,0 +1,42 @@
+package main
+
+import (
+	"time"
+
+	"github.com/baking-bad/bcdhub/internal/index"
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/reindex"
+)
+
+type reindexContractCommand struct {
+	Network string `short:"n" long:"network" description:"Network" required:"true"`
+	Address string `short:"a" long:"address" description:"Contract address" required:"true"`
+}
+
+var reindexContractCmd reindexContractCommand
+
+// Execute
+func (x *reindexContractCommand) Execute(_ []string) error {
+	rpc, err := ctx.GetRPC(x.Network)
+	if err != nil {
+		return err
+	}
+
+	logger.Warning("Do you want to delete data of %s in '%s' and index it again? (yes - continue. no - cancel)", x.Address, x.Network)
+	if !yes() {
+		logger.Info("Cancelled")
+		return nil
+	}
+
+	opts := make([]reindex.ContractOption, 0)
+	if tzkt, ok := ctx.Config.TzKT[x.Network]; ok && tzkt.URI != "" {
+		opts = append(opts, reindex.WithExternalIndexer(index.NewTzKT(tzkt.URI, time.Duration(tzkt.Timeout)*time.Second)))
+	}
+
+	if err := reindex.NewContract(ctx, rpc, x.Network, x.Address, opts...).Reindex(); err != nil {
+		return err
+	}
+
+	logger.Info("Done")
+	return nil
+}
@@ -30,7 +30,7 @@ func (x *rollbackCommand) Execute(_ []string) error {
 		panic(err)
 	}
 
-	manager := rollback.NewManager(ctx.Storage, ctx.Contracts, ctx.Operations, ctx.Transfers, ctx.TokenBalances, ctx.Protocols, ctx.MQ, rpc, ctx.SharePath)
+	manager := rollback.NewManager(ctx.Storage, ctx.Blocks, ctx.Contracts, ctx.Operations, ctx.Transfers, ctx.TicketUpdates, ctx.Protocols, ctx.TZIPVersions, ctx.MQ, rpc, ctx.SharePath)
 	if err = manager.Rollback(state, x.Level); err != nil {
 		return err
 	}
@@ -29,6 +29,7 @@ var migrationsList = []migrations.Migration{
 	&migrations.ParameterEvents{},
 	&migrations.TokenBalanceRecalc{},
 	&migrations.TokenMetadataSetDecimals{},
+	&migrations.TokenIDToString{},
 }
 
 func main() {
@@ -55,7 +56,9 @@ func main() {
 		config.WithStorage(cfg.Storage),
 		config.WithDatabase(cfg.DB),
 		config.WithRPC(cfg.RPC),
+		config.WithIPFS(cfg.IPFSGateways, cfg.IPFSResolver),
 		config.WithConfigCopy(cfg),
+		config.WithShare(cfg.SharePath),
 		config.WithLoadErrorDescriptions("data/errors.json"),
 		config.WithContractsInterfaces(),
 	)
@@ -56,7 +56,7 @@ func (m *CreateTZIP) Do(ctx *config.Context) error {
 			return err
 		}
 		parser := tzipParsers.NewParser(ctx.BigMapDiffs, ctx.Blocks, ctx.Schema, ctx.Storage, rpc, tzipParsers.ParserConfig{
-			IPFSGateways: ctx.Config.IPFSGateways,
+			IPFS: ctx.IPFS,
 		})
 
 		t, err := parser.Parse(tzipParsers.ParseContext{
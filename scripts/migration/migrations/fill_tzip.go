@@ -2,6 +2,7 @@ package migrations
 
 import (
 	"errors"
+	"strconv"
 
 	"github.com/baking-bad/bcdhub/internal/config"
 	"github.com/baking-bad/bcdhub/internal/logger"
@@ -125,7 +126,7 @@ func processTzipItem(ctx *config.Context, item repository.Item, inserts, updates
 			Contract:  model.Address,
 			Level:     0,
 			Timestamp: model.Timestamp,
-			TokenID:   token.TokenID,
+			TokenID:   strconv.FormatInt(token.TokenID, 10),
 			Symbol:    token.Symbol,
 			Name:      token.Name,
 			Decimals:  token.Decimals,
@@ -0,0 +1,147 @@
+package migrations
+
+import (
+	"github.com/baking-bad/bcdhub/internal/config"
+	"github.com/baking-bad/bcdhub/internal/elastic/core"
+	"github.com/baking-bad/bcdhub/internal/helpers"
+	"github.com/baking-bad/bcdhub/internal/logger"
+	"github.com/baking-bad/bcdhub/internal/models"
+	"github.com/baking-bad/bcdhub/internal/models/tokenbalance"
+	"github.com/baking-bad/bcdhub/internal/models/tokenmetadata"
+	"github.com/baking-bad/bcdhub/internal/models/transfer"
+	reindexerCore "github.com/baking-bad/bcdhub/internal/reindexer/core"
+	"github.com/pkg/errors"
+	"github.com/restream/reindexer"
+)
+
+const tokenIDBulkSize = 1000
+
+// tokenIDScript - painless script which converts numeric `token_id` to string
+const tokenIDScript = `if (ctx._source.token_id != null && !(ctx._source.token_id instanceof String)) { ctx._source.token_id = ctx._source.token_id.toString(); }`
+
+var tokenIndices = []string{models.DocTransfers, models.DocTokenBalances, models.DocTokenMetadata}
+
+// TokenIDToString - migration that rewrites numeric token ids of transfers, token balances and token metadata as decimal strings.
+// Elastic indices are copied to new indices with `keyword` token id and aliases are switched to the copies. Reindexer documents are rewritten in place by batches.
+type TokenIDToString struct{}
+
+// Key -
+func (m *TokenIDToString) Key() string {
+	return "token_id_to_string"
+}
+
+// Description -
+func (m *TokenIDToString) Description() string {
+	return "rewrite token ids as decimal strings"
+}
+
+// Do - migrate function
+func (m *TokenIDToString) Do(ctx *config.Context) error {
+	switch storage := ctx.Storage.(type) {
+	case *core.Elastic:
+		return m.migrateElastic(storage)
+	case *reindexerCore.Reindexer:
+		return m.migrateReindexer(storage)
+	default:
+		logger.Info("Token ids of postgres storage are converted by schema migration")
+		return nil
+	}
+}
+
+func (m *TokenIDToString) migrateElastic(storage *core.Elastic) error {
+	invalid := core.Bool(
+		core.Filter(core.Exists("token_id")),
+		core.MustNot(core.Item{
+			"regexp": core.Item{"token_id": "[0-9]+"},
+		}),
+	)
+	for _, index := range tokenIndices {
+		if err := storage.MigrateIndex(index, tokenIDScript, invalid); err != nil {
+			return errors.Wrap(err, index)
+		}
+		logger.Info("%s is migrated", index)
+	}
+	return nil
+}
+
+func (m *TokenIDToString) migrateReindexer(storage *reindexerCore.Reindexer) error {
+	for _, index := range tokenIndices {
+		for offset := 0; ; offset += tokenIDBulkSize {
+			query := storage.Query(index).
+				Sort("id", false).
+				Limit(tokenIDBulkSize).
+				Offset(offset)
+
+			items, err := getTokenDocuments(storage, index, query)
+			if err != nil {
+				return err
+			}
+			for i := range items {
+				if err := checkTokenID(items[i]); err != nil {
+					return err
+				}
+			}
+			if err := storage.BulkUpdate(items); err != nil {
+				return errors.Wrapf(err, "%s documents from %d are not saved", index, offset)
+			}
+			if len(items) < tokenIDBulkSize {
+				break
+			}
+		}
+		logger.Info("%s is migrated", index)
+	}
+	return nil
+}
+
+func getTokenDocuments(storage *reindexerCore.Reindexer, index string, query *reindexer.Query) ([]models.Model, error) {
+	switch index {
+	case models.DocTransfers:
+		var transfers []transfer.Transfer
+		if err := storage.GetAllByQuery(query, &transfers); err != nil {
+			return nil, err
+		}
+		items := make([]models.Model, len(transfers))
+		for i := range transfers {
+			items[i] = &transfers[i]
+		}
+		return items, nil
+	case models.DocTokenBalances:
+		var balances []tokenbalance.TokenBalance
+		if err := storage.GetAllByQuery(query, &balances); err != nil {
+			return nil, err
+		}
+		items := make([]models.Model, len(balances))
+		for i := range balances {
+			items[i] = &balances[i]
+		}
+		return items, nil
+	case models.DocTokenMetadata:
+		var metadata []tokenmetadata.TokenMetadata
+		if err := storage.GetAllByQuery(query, &metadata); err != nil {
+			return nil, err
+		}
+		items := make([]models.Model, len(metadata))
+		for i := range metadata {
+			items[i] = &metadata[i]
+		}
+		return items, nil
+	default:
+		return nil, errors.Errorf("Unknown token index: %s", index)
+	}
+}
+
+func checkTokenID(model models.Model) error {
+	var tokenID string
+	switch t := model.(type) {
+	case *transfer.Transfer:
+		tokenID = t.TokenID
+	case *tokenbalance.TokenBalance:
+		tokenID = t.TokenID
+	case *tokenmetadata.TokenMetadata:
+		tokenID = t.TokenID
+	}
+	if !helpers.IsDecimal(tokenID) {
+		return errors.Errorf("Invalid token id of %s document %s: %s", model.GetIndex(), model.GetID(), tokenID)
+	}
+	return nil
+}